@@ -43,6 +43,9 @@ const (
 	Capture = "capture"
 	// Evolve indicates the binding is evolved by TiDB from old bindings.
 	Evolve = "evolve"
+	// History indicates the binding is created from the plan in the statement history by
+	// "create binding from history using plan digest ...".
+	History = "history"
 	// Builtin indicates the binding is a builtin record for internal locking purpose. It is also the status for the builtin binding.
 	Builtin = "builtin"
 )
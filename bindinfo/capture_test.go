@@ -312,6 +312,46 @@ func TestBindingSource(t *testing.T) {
 	require.Equal(t, bindinfo.Capture, bind.Source)
 }
 
+func TestCreateBindingFromHistory(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, index idx_a(a))")
+	require.True(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil))
+	tk.MustExec("select * from t ignore index(idx_a) where a > 10")
+	rows := tk.MustQuery("select plan_digest from information_schema.statements_summary where query_sample_text = 'select * from t ignore index(idx_a) where a > 10'").Rows()
+	require.Len(t, rows, 1)
+	planDigest := rows[0][0].(string)
+
+	tk.MustExec(fmt.Sprintf("create global binding from history using plan digest '%s'", planDigest))
+	rows = tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "select * from `test` . `t` where `a` > ?", rows[0][0])
+	require.Equal(t, "SELECT /*+ use_index(@`sel_1` `test`.`t` )*/ * FROM `test`.`t` WHERE `a` > 10", rows[0][1])
+	require.Equal(t, bindinfo.History, rows[0][8])
+
+	sql, hash := utilNormalizeWithDefaultDB(t, "select * from t where a > ?", "test")
+	bindData := dom.BindHandle().GetBindRecord(hash, sql, "test")
+	require.NotNil(t, bindData)
+	require.Len(t, bindData.Bindings, 1)
+	require.Equal(t, bindinfo.History, bindData.Bindings[0].Source)
+
+	tk.MustQuery("select * from t where a > 10")
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+
+	tk.MustGetErrMsg("create global binding from history using plan digest 'unknown'", "can't find any plans for 'unknown' in the statement summary")
+
+	// The slow log isn't searched because it doesn't record the plan hints,
+	// so the plan can't be found once it's evicted from the statement summary.
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustGetErrMsg(fmt.Sprintf("create global binding from history using plan digest '%s'", planDigest),
+		fmt.Sprintf("can't find any plans for '%s' in the statement summary", planDigest))
+}
+
 func TestCapturedBindingCharset(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
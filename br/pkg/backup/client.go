@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/ranger"
@@ -403,18 +404,16 @@ func WriteBackupDDLJobs(metaWriter *metautil.MetaWriter, store kv.Storage, lastB
 		return errors.Trace(err)
 	}
 	allJobs := make([]*model.Job, 0)
-	defaultJobs, err := snapMeta.GetAllDDLJobsInQueue(meta.DefaultJobListKey)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	log.Debug("get default jobs", zap.Int("jobs", len(defaultJobs)))
-	allJobs = append(allJobs, defaultJobs...)
-	addIndexJobs, err := snapMeta.GetAllDDLJobsInQueue(meta.AddIndexJobListKey)
-	if err != nil {
-		return errors.Trace(err)
+	for _, jobListKey := range meta.AllJobListKeys(variable.MaxDDLJobQueueCount) {
+		queueJobs, err := snapMeta.GetAllDDLJobsInQueue(jobListKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(queueJobs) > 0 {
+			log.Debug("get jobs in queue", zap.ByteString("queue", jobListKey), zap.Int("jobs", len(queueJobs)))
+		}
+		allJobs = append(allJobs, queueJobs...)
 	}
-	log.Debug("get add index jobs", zap.Int("jobs", len(addIndexJobs)))
-	allJobs = append(allJobs, addIndexJobs...)
 	historyJobs, err := snapMeta.GetAllHistoryDDLJobs()
 	if err != nil {
 		return errors.Trace(err)
@@ -603,6 +603,16 @@ type StmtSummary struct {
 	RefreshInterval int `toml:"refresh-interval" json:"refresh-interval"`
 	// The maximum history size of statement summary.
 	HistorySize int `toml:"history-size" json:"history-size"`
+	// Persist the expired statement summaries to the local file or not.
+	EnablePersistent bool `toml:"enable-persistent" json:"enable-persistent"`
+	// The file that the expired statement summaries are written to.
+	Filename string `toml:"filename" json:"filename"`
+	// The maximum size of a statement summary file in MB.
+	FileMaxSize int `toml:"file-max-size" json:"file-max-size"`
+	// The maximum days to retain the rotated statement summary files.
+	FileMaxDays int `toml:"file-max-days" json:"file-max-days"`
+	// The maximum number of the rotated statement summary files to retain.
+	FileMaxBackups int `toml:"file-max-backups" json:"file-max-backups"`
 }
 
 // TopSQL is the config for TopSQL.
@@ -754,6 +764,11 @@ var defaultConf = Config{
 		MaxSQLLength:        4096,
 		RefreshInterval:     1800,
 		HistorySize:         24,
+		EnablePersistent:    false,
+		Filename:            "tidb-statements.log",
+		FileMaxSize:         64,
+		FileMaxDays:         3,
+		FileMaxBackups:      0,
 	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -114,6 +115,57 @@ func adjustColumnInfoInDropColumn(tblInfo *model.TableInfo, offset int) {
 	tblInfo.Columns = newCols
 }
 
+// locateColumnOffset returns the offset that the non-public added column should be moved to.
+// The non-public columns are at the end of tblInfo.Columns, so the column is placed before
+// them if it has no position.
+func locateColumnOffset(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *ast.ColumnPosition) int {
+	switch pos.Tp {
+	case ast.ColumnPositionFirst:
+		return 0
+	case ast.ColumnPositionAfter:
+		c := model.FindColumnInfo(tblInfo.Columns, pos.RelativeColumn.Name.L)
+		if c.Offset < colInfo.Offset {
+			return c.Offset + 1
+		}
+		return c.Offset
+	}
+	offset := colInfo.Offset
+	for offset > 0 && tblInfo.Columns[offset-1].State != model.StatePublic {
+		offset--
+	}
+	return offset
+}
+
+// moveColumnInfo moves the column at offset from to offset to, and updates the offsets of the
+// columns and index columns in between.
+func moveColumnInfo(tblInfo *model.TableInfo, from, to int) {
+	if from == to {
+		return
+	}
+	cols := tblInfo.Columns
+	moved := cols[from]
+	if from < to {
+		copy(cols[from:to], cols[from+1:to+1])
+	} else {
+		copy(cols[to+1:from+1], cols[to:from])
+	}
+	cols[to] = moved
+	offsetChanged := make(map[int]int)
+	for i, col := range cols {
+		if col.Offset != i {
+			offsetChanged[col.Offset] = i
+			col.Offset = i
+		}
+	}
+	for _, idx := range tblInfo.Indices {
+		for _, col := range idx.Columns {
+			if newOffset, ok := offsetChanged[col.Offset]; ok {
+				col.Offset = newOffset
+			}
+		}
+	}
+}
+
 func createColumnInfo(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *ast.ColumnPosition) (*model.ColumnInfo, *ast.ColumnPosition, int, error) {
 	// Check column name duplicate.
 	cols := tblInfo.Columns
@@ -146,33 +198,45 @@ func createColumnInfo(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *
 	return colInfo, pos, offset, nil
 }
 
-func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, error) {
+func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, []*model.ConstraintInfo, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 	col := &model.ColumnInfo{}
 	pos := &ast.ColumnPosition{}
 	offset := 0
-	err = job.DecodeArgs(col, pos, &offset)
+	var constraints []*model.ConstraintInfo
+	err = job.DecodeArgs(col, pos, &offset, &constraints)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 
 	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
 	if columnInfo != nil {
-		if columnInfo.State == model.StatePublic {
+		// The column is public before its check constraints are added.
+		if columnInfo.State == model.StatePublic && !hasAddingConstraints(tblInfo, constraints) {
 			// We already have a column with the same column name.
 			job.State = model.JobStateCancelled
-			return nil, nil, nil, nil, 0, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+			return nil, nil, nil, nil, 0, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 		}
 	}
-	return tblInfo, columnInfo, col, pos, offset, nil
+	return tblInfo, columnInfo, col, pos, offset, constraints, nil
 }
 
-func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+// hasAddingConstraints returns whether the check constraints added with the column are in the table and not public.
+func hasAddingConstraints(tblInfo *model.TableInfo, constraints []*model.ConstraintInfo) bool {
+	for _, constr := range constraints {
+		if constrInfo := tblInfo.FindConstraintInfoByName(constr.Name.L); constrInfo != nil && constrInfo.State != model.StatePublic {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *worker) onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// Handle the rolling back job.
 	if job.IsRollingback() {
 		ver, err = onDropColumn(t, job)
@@ -188,7 +252,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 	})
 
-	tblInfo, columnInfo, col, pos, offset, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, pos, offset, constraints, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -201,7 +265,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		logutil.BgLogger().Info("[ddl] run add column job", zap.String("job", job.String()), zap.Reflect("columnInfo", *columnInfo), zap.Int("offset", offset))
 		// Set offset arg to job.
 		if offset != 0 {
-			job.Args = []interface{}{columnInfo, pos, offset}
+			job.Args = []interface{}{columnInfo, pos, offset, constraints}
 		}
 		if err = checkAddColumnTooManyColumns(len(tblInfo.Columns)); err != nil {
 			job.State = model.JobStateCancelled
@@ -239,9 +303,33 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
 		// reorganization -> public
-		// Adjust table column offset.
-		adjustColumnInfoInAddColumn(tblInfo, offset)
+		if job.MultiSchemaInfo != nil {
+			if checkAndMarkNonRevertible(job) {
+				return ver, nil
+			}
+			// The column may not be the last one, because the other sub-jobs may add columns after it.
+			moveColumnInfo(tblInfo, columnInfo.Offset, locateColumnOffset(tblInfo, columnInfo, pos))
+		} else {
+			// Adjust table column offset.
+			adjustColumnInfoInAddColumn(tblInfo, offset)
+		}
 		columnInfo.State = model.StatePublic
+		if len(constraints) > 0 {
+			// The check constraints can only be built on the public column. They are checked by the write path
+			// first, then the existing rows are verified.
+			for _, constr := range constraints {
+				constrInfo := constr.Clone()
+				constrInfo.ID = allocateConstraintID(tblInfo)
+				constrInfo.State = model.StateWriteOnly
+				tblInfo.Constraints = append(tblInfo.Constraints, constrInfo)
+			}
+			ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.SchemaState = model.StatePublic
+			return ver, nil
+		}
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -250,6 +338,46 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		// Finish this job.
 		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
 		asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionAddColumn, TableInfo: tblInfo, ColumnInfos: []*model.ColumnInfo{columnInfo}})
+	case model.StatePublic:
+		// The column is public, verify the existing rows with its check constraints.
+		dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		for _, constr := range constraints {
+			constrInfo := tblInfo.FindConstraintInfoByName(constr.Name.L)
+			if constrInfo == nil || constrInfo.State == model.StatePublic {
+				continue
+			}
+			if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constrInfo); err != nil {
+				if !ErrCheckConstraintViolated.Equal(err) {
+					return ver, errors.Trace(err)
+				}
+				// Remove the check constraints, then the column is dropped by the rolling back job.
+				for _, constr := range constraints {
+					removeCheckConstraint(tblInfo, constr.Name)
+				}
+				ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+				if err1 != nil {
+					return ver, errors.Trace(err1)
+				}
+				job.State = model.JobStateRollingback
+				job.Args = []interface{}{columnInfo.Name}
+				return ver, errors.Trace(err)
+			}
+		}
+		// write only -> public
+		for _, constr := range constraints {
+			if constrInfo := tblInfo.FindConstraintInfoByName(constr.Name.L); constrInfo != nil {
+				constrInfo.State = model.StatePublic
+			}
+		}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionAddColumn, TableInfo: tblInfo, ColumnInfos: []*model.ColumnInfo{columnInfo}})
 	default:
 		err = ErrInvalidDDLState.GenWithStackByArgs("column", columnInfo.State)
 	}
@@ -546,6 +674,8 @@ func checkDropColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.
 func checkDropColumnForStatePublic(tblInfo *model.TableInfo, colInfo *model.ColumnInfo) (err error) {
 	// Set this column's offset to the last and reset all following columns' offsets.
 	adjustColumnInfoInDropColumn(tblInfo, colInfo.Offset)
+	// The check constraints which only reference this column are dropped together.
+	removeDependentCheckConstraints(tblInfo, colInfo.Name)
 	// When the dropping column has not-null flag and it hasn't the default value, we can backfill the column value like "add column".
 	// NOTE: If the state of StateWriteOnly can be rollbacked, we'd better reconsider the original default value.
 	// And we need consider the column without not-null flag.
@@ -574,6 +704,9 @@ func onDropColumn(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := colInfo.State
 	switch colInfo.State {
 	case model.StatePublic:
+		if checkAndMarkNonRevertible(job) {
+			return ver, nil
+		}
 		// public -> write only
 		colInfo.State = model.StateWriteOnly
 		setIndicesState(idxInfos, model.StateWriteOnly)
@@ -686,7 +819,10 @@ func onSetDefaultValue(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	return updateColumnDefaultValue(t, job, newCol, &newCol.Name)
 }
 
-func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
+func needChangeColumnData(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) bool {
+	if needRewriteCharsetOrCollation(tblInfo, oldCol, newCol) {
+		return true
+	}
 	toUnsigned := mysql.HasUnsignedFlag(newCol.Flag)
 	originUnsigned := mysql.HasUnsignedFlag(oldCol.Flag)
 	needTruncationOrToggleSign := func() bool {
@@ -742,6 +878,21 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 	return true
 }
 
+// needRewriteCharsetOrCollation returns true if the data of the string column needs to be re-encoded to change
+// its charset, or the indexes on the column need to be rebuilt to change its collation.
+func needRewriteCharsetOrCollation(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) bool {
+	if !types.IsString(oldCol.Tp) || !types.IsString(newCol.Tp) || !canRewriteCharsetAndCollation(oldCol.Charset, newCol.Charset) {
+		return false
+	}
+	// The data of utf8 is valid for utf8mb4, so it doesn't need to be checked and re-encoded.
+	if oldCol.Charset != newCol.Charset && !(oldCol.Charset == charset.CharsetUTF8 && newCol.Charset == charset.CharsetUTF8MB4) {
+		return true
+	}
+	// The index keys are encoded by the collation of the column.
+	return collate.NewCollationEnabled() && !collate.CompatibleCollate(oldCol.Collate, newCol.Collate) &&
+		isColumnWithIndex(oldCol.Name.L, tblInfo.Indices)
+}
+
 // Column type conversion between varchar to char need reorganization because
 // 1. varchar -> char: char type is stored with the padding removed. All the indexes need to be rewritten.
 // 2. char -> varchar: the index value encoding of secondary index on clustered primary key tables is different.
@@ -842,7 +993,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 
 	if job.IsRollingback() {
 		// For those column-type-change jobs which don't reorg the data.
-		if !needChangeColumnData(oldCol, jobParam.newCol) {
+		if !needChangeColumnData(tblInfo, oldCol, jobParam.newCol) {
 			return rollbackModifyColumnJob(t, tblInfo, job, jobParam.newCol, oldCol, jobParam.modifyColumnTp)
 		}
 		// For those column-type-change jobs which reorg the data.
@@ -872,7 +1023,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		return ver, errors.Trace(err)
 	}
 
-	if !needChangeColumnData(oldCol, jobParam.newCol) {
+	if !needChangeColumnData(tblInfo, oldCol, jobParam.newCol) {
 		return w.doModifyColumn(d, t, job, dbInfo, tblInfo, jobParam.newCol, oldCol, jobParam.pos)
 	}
 
@@ -907,25 +1058,44 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 			return ver, errors.Trace(err)
 		}
 
-		idxInfos, offsets := findIndexesByColName(tblInfo.Indices, oldCol.Name.L)
+		// The non-public indexes are the changing indexes of the other sub-jobs of a multi-schema change,
+		// they are replaced by the changing indexes built here.
+		idxInfos, offsets := findIndexesByColName(publicIndexes(tblInfo), oldCol.Name.L)
 		jobParam.changingIdxs = make([]*model.IndexInfo, 0, len(idxInfos))
 		for i, idxInfo := range idxInfos {
 			newIdxInfo := idxInfo.Clone()
 			newIdxInfo.Name = model.NewCIStr(genChangingIndexUniqueName(tblInfo, idxInfo))
 			newIdxInfo.ID = allocateIndexID(tblInfo)
-			newIdxChangingCol := newIdxInfo.Columns[offsets[i]]
-			newIdxChangingCol.Name = newColName
-			newIdxChangingCol.Offset = jobParam.changingCol.Offset
-			canPrefix := types.IsTypePrefixable(jobParam.changingCol.Tp)
-			if !canPrefix || (canPrefix && jobParam.changingCol.Flen < newIdxChangingCol.Length) {
-				newIdxChangingCol.Length = types.UnspecifiedLength
+			for j, idxCol := range newIdxInfo.Columns {
+				changingCol := jobParam.changingCol
+				if j != offsets[i] {
+					// The column may be changed by the other sub-jobs of a multi-schema change.
+					if changingCol = findChangingColumn(tblInfo, idxCol.Offset); changingCol == nil {
+						continue
+					}
+				}
+				idxCol.Name = changingCol.Name
+				idxCol.Offset = changingCol.Offset
+				canPrefix := types.IsTypePrefixable(changingCol.Tp)
+				if !canPrefix || (canPrefix && changingCol.Flen < idxCol.Length) {
+					idxCol.Length = types.UnspecifiedLength
+				}
 			}
 			jobParam.changingIdxs = append(jobParam.changingIdxs, newIdxInfo)
 		}
 		tblInfo.Indices = append(tblInfo.Indices, jobParam.changingIdxs...)
 	} else {
-		tblInfo.Columns[len(tblInfo.Columns)-1] = jobParam.changingCol
-		copy(tblInfo.Indices[len(tblInfo.Indices)-len(jobParam.changingIdxs):], jobParam.changingIdxs)
+		// The changing column and indexes in the table info are the latest ones, their offsets and names
+		// may have been adjusted by the other sub-jobs of a multi-schema change.
+		jobParam.changingCol = model.FindColumnInfoByID(tblInfo.Columns, jobParam.changingCol.ID)
+		if jobParam.changingCol == nil {
+			return ver, errors.Trace(errKeyColumnDoesNotExits.GenWithStack("the changing column of %s doesn't exist", oldCol.Name))
+		}
+		for i, idx := range jobParam.changingIdxs {
+			if jobParam.changingIdxs[i] = findIndexByID(tblInfo, idx.ID); jobParam.changingIdxs[i] == nil {
+				return ver, errors.Trace(errKeyColumnDoesNotExits.GenWithStack("the changing index %s doesn't exist", idx.Name))
+			}
+		}
 	}
 
 	return w.doModifyColumnTypeWithData(d, t, job, dbInfo, tblInfo, jobParam.changingCol, oldCol, jobParam.newCol.Name, jobParam.pos, jobParam.changingIdxs)
@@ -943,8 +1113,7 @@ func rollbackModifyColumnJobWithData(t *meta.Meta, tblInfo *model.TableInfo, job
 	if jobParam.changingCol != nil {
 		// changingCol isn't nil means the job has been in the mid state. These appended changingCol and changingIndex should
 		// be removed from the tableInfo as well.
-		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
-		tblInfo.Indices = tblInfo.Indices[:len(tblInfo.Indices)-len(jobParam.changingIdxs)]
+		removeChangingColumnAndIndexes(tblInfo, jobParam.changingCol, jobParam.changingIdxs)
 	}
 	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
 	if err != nil {
@@ -1043,59 +1212,23 @@ func (w *worker) doModifyColumnTypeWithData(
 		job.SnapshotVer = 0
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
-		tbl, err := getTable(d.store, dbInfo.ID, tblInfo)
-		if err != nil {
-			return ver, errors.Trace(err)
-		}
-
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, BuildElements(changingCol, changingIdxs))
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		// Inject a failpoint so that we can pause here and do verification on other components.
-		// With a failpoint-enabled version of TiDB, you can trigger this failpoint by the following command:
-		// enable: curl -X PUT -d "pause" "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData".
-		// disable: curl -X DELETE "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData"
-		failpoint.Inject("mockDelayInModifyColumnTypeWithData", func() {})
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onModifyColumn",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("modify table `%v` column `%v` panic", tblInfo.Name, oldCol.Name)
-				}, false)
-			// Use old column name to generate less confusing error messages.
-			changingColCpy := changingCol.Clone()
-			changingColCpy.Name = oldCol.Name
-			return w.updateColumnAndIndexes(tbl, oldCol, changingColCpy, changingIdxs, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// If timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
-			}
-			if kv.IsTxnRetryableError(err) {
-				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-				w.reorgCtx.cleanNotifyReorgCancel()
-				return ver, errors.Trace(err)
+		// The data of a non-revertible sub-job has been reorganized, it only waits for the other sub-jobs.
+		if job.MultiSchemaInfo == nil || job.MultiSchemaInfo.Revertible {
+			var done bool
+			done, ver, err = w.doReorgWorkForModifyColumn(d, t, job, dbInfo, tblInfo, changingCol, oldCol, changingIdxs)
+			if !done {
+				return ver, err
 			}
-			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-				logutil.BgLogger().Warn("[ddl] run modify column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
-					zap.String("job", job.String()), zap.Error(err1))
+			if checkAndMarkNonRevertible(job) {
+				// Reset the snapshot version, so the column is rolled back as a not started one if the job is cancelled.
+				job.SnapshotVer = 0
+				return ver, nil
 			}
-			logutil.BgLogger().Warn("[ddl] run modify column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-			job.State = model.JobStateRollingback
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
 
 		// Remove the old column and indexes. Update the relative column name and index names.
 		oldIdxIDs := make([]int64, 0, len(changingIdxs))
-		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+		removeChangingColumnAndIndexes(tblInfo, changingCol, changingIdxs)
 		for _, cIdx := range changingIdxs {
 			idxName := getChangingIndexOriginName(cIdx)
 			for i, idx := range tblInfo.Indices {
@@ -1117,7 +1250,6 @@ func (w *worker) doModifyColumnTypeWithData(
 		if err = changingCol.SetOriginDefaultValue(nil); err != nil {
 			return ver, errors.Trace(err)
 		}
-		tblInfo.Indices = tblInfo.Indices[:len(tblInfo.Indices)-len(changingIdxs)]
 		// Adjust table column offset.
 		if err = adjustColumnInfoInModifyColumn(job, tblInfo, changingCol, oldCol, pos, changingColumnUniqueName.L); err != nil {
 			// TODO: Do rollback.
@@ -1141,6 +1273,60 @@ func (w *worker) doModifyColumnTypeWithData(
 	return ver, errors.Trace(err)
 }
 
+func (w *worker) doReorgWorkForModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job, dbInfo *model.DBInfo, tblInfo *model.TableInfo,
+	changingCol, oldCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, dbInfo.ID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, BuildElements(changingCol, changingIdxs))
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	// Inject a failpoint so that we can pause here and do verification on other components.
+	// With a failpoint-enabled version of TiDB, you can trigger this failpoint by the following command:
+	// enable: curl -X PUT -d "pause" "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData".
+	// disable: curl -X DELETE "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData"
+	failpoint.Inject("mockDelayInModifyColumnTypeWithData", func() {})
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onModifyColumn",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("modify table `%v` column `%v` panic", tblInfo.Name, oldCol.Name)
+			}, false)
+		// Use old column name to generate less confusing error messages.
+		changingColCpy := changingCol.Clone()
+		changingColCpy.Name = oldCol.Name
+		return w.updateColumnAndIndexes(tbl, oldCol, changingColCpy, changingIdxs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) {
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] run modify column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("[ddl] run modify column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+		job.State = model.JobStateRollingback
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 // BuildElements is exported for testing.
 func BuildElements(changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) []*meta.Element {
 	elements := make([]*meta.Element, 0, len(changingIdxs)+1)
@@ -1507,6 +1693,10 @@ func (w *worker) doModifyColumn(
 		}
 	}
 
+	if checkAndMarkNonRevertible(job) {
+		return ver, nil
+	}
+
 	if err := adjustColumnInfoInModifyColumn(job, tblInfo, newCol, oldCol, pos, ""); err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -1600,6 +1790,9 @@ func adjustColumnInfoInModifyColumn(
 			}
 		}
 	}
+	if tblInfo.TTLInfo != nil && tblInfo.TTLInfo.ColumnName.L == oldCol.Name.L {
+		tblInfo.TTLInfo.ColumnName = newCol.Name
+	}
 	return nil
 }
 
@@ -1731,6 +1924,9 @@ func updateColumnDefaultValue(t *meta.Meta, job *model.Job, newCol *model.Column
 		job.State = model.JobStateCancelled
 		return ver, infoschema.ErrColumnNotExists.GenWithStackByArgs(newCol.Name, tblInfo.Name)
 	}
+	if checkAndMarkNonRevertible(job) {
+		return ver, nil
+	}
 	// The newCol's offset may be the value of the old schema version, so we can't use newCol directly.
 	oldCol.DefaultValue = newCol.DefaultValue
 	oldCol.DefaultValueBit = newCol.DefaultValueBit
@@ -1974,6 +2170,73 @@ func getChangingColumnOriginName(changingCol *model.IndexColumn) string {
 	return colName[:pos]
 }
 
+// removeChangingColumnAndIndexes removes the changing column and indexes from the table info. The changing columns of
+// the other sub-jobs of a multi-schema change may follow the removed one, so the offsets after it are adjusted.
+func removeChangingColumnAndIndexes(tblInfo *model.TableInfo, changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) {
+	for i, col := range tblInfo.Columns {
+		if col.ID != changingCol.ID {
+			continue
+		}
+		tblInfo.Columns = append(tblInfo.Columns[:i], tblInfo.Columns[i+1:]...)
+		for _, c := range tblInfo.Columns[i:] {
+			c.Offset--
+		}
+		for _, idx := range tblInfo.Indices {
+			for _, idxCol := range idx.Columns {
+				if idxCol.Offset > i {
+					idxCol.Offset--
+				}
+			}
+		}
+		break
+	}
+	indices := tblInfo.Indices[:0]
+	for _, idx := range tblInfo.Indices {
+		if !containsIndex(changingIdxs, idx.ID) {
+			indices = append(indices, idx)
+		}
+	}
+	tblInfo.Indices = indices
+}
+
+// findChangingColumn returns the changing column of the column at the offset, or nil if the column isn't being changed.
+func findChangingColumn(tblInfo *model.TableInfo, offset int) *model.ColumnInfo {
+	for _, col := range tblInfo.Columns {
+		if col.ChangeStateInfo != nil && col.ChangeStateInfo.DependencyColumnOffset == offset {
+			return col
+		}
+	}
+	return nil
+}
+
+func publicIndexes(tblInfo *model.TableInfo) []*model.IndexInfo {
+	indexes := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idx := range tblInfo.Indices {
+		if idx.State == model.StatePublic {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
+
+func findIndexByID(tblInfo *model.TableInfo, id int64) *model.IndexInfo {
+	for _, idx := range tblInfo.Indices {
+		if idx.ID == id {
+			return idx
+		}
+	}
+	return nil
+}
+
+func containsIndex(indexes []*model.IndexInfo, id int64) bool {
+	for _, idx := range indexes {
+		if idx.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func getChangingIndexOriginName(changingIdx *model.IndexInfo) string {
 	idxName := strings.TrimPrefix(changingIdx.Name.O, changingIndexPrefix)
 	// Since the unique idxName may contain the suffix number (indexName_num), better trim the suffix.
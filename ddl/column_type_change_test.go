@@ -2374,3 +2374,71 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeBetweenFloatAndDouble(c
 	tk.MustExec("alter table t modify a float(6,1)")
 	tk.MustQuery("select a from t;").Check(testkit.Rows("36.4", "24.1"))
 }
+
+func (s *testColumnTypeChangeSuite) TestColumnTypeChangeOfCharsetAndCollation(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	checkColumnCharset := func(tblName, colName, chs, coll string) {
+		tbl := testGetTableByName(c, tk.Se, "test", tblName)
+		col := table.FindCol(tbl.Cols(), colName)
+		c.Assert(col, NotNil)
+		c.Assert(col.Charset, Equals, chs)
+		c.Assert(col.Collate, Equals, coll)
+	}
+
+	// The string data is re-encoded and the index is rebuilt.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, a varchar(10) charset latin1, b varchar(10) collate utf8mb4_bin, index idx_a(a), unique index idx_b(b))")
+	tk.MustExec("insert into t values (1, 'café', 'A'), (2, 'abc', 'b')")
+	tk.MustExec("alter table t modify column a varchar(10) charset utf8mb4")
+	checkColumnCharset("t", "a", "utf8mb4", "utf8mb4_bin")
+	tk.MustQuery("select id, a from t use index(idx_a) order by a").Check(testkit.Rows("2 abc", "1 café"))
+	tk.MustExec("admin check table t")
+
+	// The index on the column is rebuilt with the new collation.
+	tk.MustExec("alter table t modify column b varchar(10) collate utf8mb4_general_ci")
+	checkColumnCharset("t", "b", "utf8mb4", "utf8mb4_general_ci")
+	tk.MustQuery("select id from t use index(idx_b) where b = 'a'").Check(testkit.Rows("1"))
+	tk.MustExec("admin check table t")
+	// The unique index can't be rebuilt if the values are duplicated in the new collation.
+	tk.MustExec("alter table t modify column b varchar(10) collate utf8mb4_bin")
+	tk.MustExec("insert into t values (3, 'x', 'c'), (4, 'y', 'C')")
+	tk.MustGetErrCode("alter table t modify column b varchar(10) collate utf8mb4_unicode_ci", mysql.ErrDupEntry)
+	checkColumnCharset("t", "b", "utf8mb4", "utf8mb4_bin")
+	tk.MustExec("admin check table t")
+
+	// The column in primary key can't be converted.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a varchar(10) charset latin1 primary key)")
+	tk.MustGetErrCode("alter table t modify column a varchar(10) charset utf8mb4", mysql.ErrUnsupportedDDLOperation)
+
+	// The invalid data is reported as an error in strict mode, and as a warning in non-strict mode.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a varchar(10) charset latin1)")
+	tk.MustExec("insert into t values (0xE9), ('b')")
+	tk.MustGetErrMsg("alter table t modify column a varchar(10) charset utf8mb4", "[table:1366]Incorrect string value '\\xE9' for column 'a'")
+	checkColumnCharset("t", "a", "latin1", "latin1_bin")
+	tk.MustExec("set @@sql_mode = ''")
+	tk.MustExec("alter table t modify column a varchar(10) charset utf8mb4")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1366 Incorrect string value '\\xE9' for column 'a'"))
+	tk.MustQuery("select a from t").Check(testkit.Rows("?", "b"))
+	checkColumnCharset("t", "a", "utf8mb4", "utf8mb4_bin")
+	tk.MustExec("set @@sql_mode = default")
+
+	// The columns are converted by the column-type-change jobs when converting the table charset.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, a varchar(10), b char(10) charset utf8mb4 collate utf8mb4_bin, c int, index idx_b(b)) charset latin1")
+	tk.MustExec("insert into t values (1, 'café', 'A', 1), (2, 'abc', 'a', 2)")
+	tk.MustExec("alter table t convert to charset utf8mb4 collate utf8mb4_general_ci")
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	c.Assert(tbl.Meta().Charset, Equals, "utf8mb4")
+	c.Assert(tbl.Meta().Collate, Equals, "utf8mb4_general_ci")
+	checkColumnCharset("t", "a", "utf8mb4", "utf8mb4_general_ci")
+	checkColumnCharset("t", "b", "utf8mb4", "utf8mb4_general_ci")
+	tk.MustQuery("select id from t use index(idx_b) where b = 'a' order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where id = 1").Check(testkit.Rows("café"))
+	tk.MustExec("admin check table t")
+}
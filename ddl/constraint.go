@@ -0,0 +1,475 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+func allocateConstraintID(tblInfo *model.TableInfo) int64 {
+	tblInfo.MaxConstraintID++
+	return tblInfo.MaxConstraintID
+}
+
+// buildConstraintInfo builds the check constraint info from the AST, the caller should make sure
+// the constraint name is not empty.
+func buildConstraintInfo(tblInfo *model.TableInfo, constr *ast.Constraint, state model.SchemaState) (*model.ConstraintInfo, error) {
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	restoreCtx := format.NewRestoreCtx(restoreFlags, &sb)
+	if err := constr.Expr.Restore(restoreCtx); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	dependedCols := make([]model.CIStr, 0, 1)
+	seen := make(map[string]struct{})
+	for _, colName := range findColumnNamesInExpr(constr.Expr) {
+		if _, ok := seen[colName.Name.L]; ok {
+			continue
+		}
+		seen[colName.Name.L] = struct{}{}
+		dependedCols = append(dependedCols, colName.Name)
+	}
+
+	return &model.ConstraintInfo{
+		Name:           model.NewCIStr(constr.Name),
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+		State:          state,
+	}, nil
+}
+
+// genConstraintName generates a check constraint name in the MySQL style, such as `t_chk_1`.
+func genConstraintName(tblInfo *model.TableInfo, existNames map[string]struct{}) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_chk_%d", tblInfo.Name.O, i)
+		if _, ok := existNames[strings.ToLower(name)]; !ok {
+			return name
+		}
+	}
+}
+
+type constraintExprChecker struct {
+	name string
+	err  error
+}
+
+func (c *constraintExprChecker) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	switch node := inNode.(type) {
+	case *ast.FuncCallExpr:
+		_, isFunctionBlocked := expression.IllegalFunctions4GeneratedColumns[node.FnName.L]
+		if isFunctionBlocked || !expression.IsFunctionSupported(node.FnName.L) {
+			c.err = errCheckConstraintNamedFunctionIsNotAllowed.GenWithStackByArgs(c.name, node.FnName.L)
+			return inNode, true
+		}
+	case *ast.VariableExpr:
+		c.err = errCheckConstraintVariables.GenWithStackByArgs(c.name)
+		return inNode, true
+	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.DefaultExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+		c.err = errCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(c.name)
+		return inNode, true
+	}
+	return inNode, false
+}
+
+func (c *constraintExprChecker) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	return inNode, c.err == nil
+}
+
+// checkConstraintExpr checks whether the check constraint expression is legal for the table.
+func checkConstraintExpr(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint, constrInfo *model.ConstraintInfo) error {
+	c := &constraintExprChecker{name: constrInfo.Name.O}
+	constr.Expr.Accept(c)
+	if c.err != nil {
+		return c.err
+	}
+	_, autoIncCol := infoschema.HasAutoIncrementColumn(tblInfo)
+	for _, colName := range constrInfo.ConstraintCols {
+		col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+		if col == nil || col.Hidden || col.State != model.StatePublic {
+			return errCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constrInfo.Name.O, colName.O)
+		}
+		if constrInfo.InColumn && colName.L != strings.ToLower(constr.InColumnName) {
+			return errColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constrInfo.Name.O)
+		}
+		if colName.L == autoIncCol {
+			return errCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constrInfo.Name.O)
+		}
+	}
+	// Make sure the expression can be built with the table columns.
+	_, err := expression.ParseSimpleExprWithTableInfo(ctx, constrInfo.ExprString, tblInfo)
+	return errors.Trace(err)
+}
+
+// buildConstraintInfos builds the check constraints for the table to be created.
+func buildConstraintInfos(ctx sessionctx.Context, tblInfo *model.TableInfo, constraints []*ast.Constraint) error {
+	existNames := make(map[string]struct{})
+	for _, constr := range constraints {
+		if constr.Tp != ast.ConstraintCheck || constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := existNames[name]; ok {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		existNames[name] = struct{}{}
+	}
+	for _, constr := range constraints {
+		if constr.Tp != ast.ConstraintCheck {
+			continue
+		}
+		if constr.Name == "" {
+			constr.Name = genConstraintName(tblInfo, existNames)
+			existNames[strings.ToLower(constr.Name)] = struct{}{}
+		}
+		constrInfo, err := buildConstraintInfo(tblInfo, constr, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err = checkConstraintExpr(ctx, tblInfo, constr, constrInfo); err != nil {
+			return errors.Trace(err)
+		}
+		constrInfo.ID = allocateConstraintID(tblInfo)
+		tblInfo.Constraints = append(tblInfo.Constraints, constrInfo)
+	}
+	return nil
+}
+
+// buildAddColumnConstraintInfos builds the check constraints defined on the column to be added. They are added
+// by the job of adding the column after the column is public.
+func buildAddColumnConstraintInfos(ctx sessionctx.Context, tblInfo *model.TableInfo, colInfo *model.ColumnInfo, constraints []*ast.Constraint) ([]*model.ConstraintInfo, error) {
+	// The constraints are checked with the table which has the new column.
+	tblInfo = tblInfo.Clone()
+	colInfo = colInfo.Clone()
+	colInfo.Offset = len(tblInfo.Columns)
+	colInfo.State = model.StatePublic
+	tblInfo.Columns = append(tblInfo.Columns, colInfo)
+
+	existNames := make(map[string]struct{}, len(tblInfo.Constraints)+len(constraints))
+	for _, c := range tblInfo.Constraints {
+		existNames[c.Name.L] = struct{}{}
+	}
+	constrInfos := make([]*model.ConstraintInfo, 0, len(constraints))
+	for _, constr := range constraints {
+		if constr.Name == "" {
+			constr.Name = genConstraintName(tblInfo, existNames)
+		} else if _, ok := existNames[strings.ToLower(constr.Name)]; ok {
+			return nil, ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		existNames[strings.ToLower(constr.Name)] = struct{}{}
+		constrInfo, err := buildConstraintInfo(tblInfo, constr, model.StateNone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = checkConstraintExpr(ctx, tblInfo, constr, constrInfo); err != nil {
+			return nil, errors.Trace(err)
+		}
+		constrInfos = append(constrInfos, constrInfo)
+	}
+	return constrInfos, nil
+}
+
+// findDependentCheckConstraints returns the check constraints which reference the column.
+func findDependentCheckConstraints(tblInfo *model.TableInfo, colName model.CIStr) []*model.ConstraintInfo {
+	var constraints []*model.ConstraintInfo
+	for _, constr := range tblInfo.Constraints {
+		for _, col := range constr.ConstraintCols {
+			if col.L == colName.L {
+				constraints = append(constraints, constr)
+				break
+			}
+		}
+	}
+	return constraints
+}
+
+// checkDropColumnWithCheckConstraint checks whether the column can be dropped. The check constraint which
+// only references the dropped column is dropped together, otherwise the column can't be dropped.
+func checkDropColumnWithCheckConstraint(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, constr := range findDependentCheckConstraints(tblInfo, colName) {
+		if len(constr.ConstraintCols) > 1 {
+			return errDependentByCheckConstraint.GenWithStackByArgs(constr.Name.O, colName.O)
+		}
+	}
+	return nil
+}
+
+// removeDependentCheckConstraints removes the check constraints which reference the column.
+func removeDependentCheckConstraints(tblInfo *model.TableInfo, colName model.CIStr) {
+	for _, constr := range findDependentCheckConstraints(tblInfo, colName) {
+		removeCheckConstraint(tblInfo, constr.Name)
+	}
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, constrName model.CIStr) {
+	constraints := tblInfo.Constraints[:0]
+	for _, constr := range tblInfo.Constraints {
+		if constr.Name.L != constrName.L {
+			constraints = append(constraints, constr)
+		}
+	}
+	tblInfo.Constraints = constraints
+}
+
+func (w *worker) onAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	schemaID := job.SchemaID
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	constrInfoInJob := &model.ConstraintInfo{}
+	if err = job.DecodeArgs(constrInfoInJob); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	if job.IsRollingback() {
+		return rollbackAddCheckConstraint(t, job, tblInfo, constrInfoInJob.Name)
+	}
+
+	constrInfo := tblInfo.FindConstraintInfoByName(constrInfoInJob.Name.L)
+	if job.SchemaState == model.StateNone {
+		if constrInfo != nil {
+			job.State = model.JobStateCancelled
+			return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constrInfoInJob.Name.O)
+		}
+		for _, colName := range constrInfoInJob.ConstraintCols {
+			col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+			if col == nil || col.State != model.StatePublic {
+				job.State = model.JobStateCancelled
+				return ver, errCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constrInfoInJob.Name.O, colName.O)
+			}
+		}
+		constrInfo = constrInfoInJob.Clone()
+		constrInfo.ID = allocateConstraintID(tblInfo)
+		constrInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constrInfo)
+	} else if constrInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constrInfoInJob.Name.O)
+	}
+
+	originalState := constrInfo.State
+	switch constrInfo.State {
+	case model.StateNone:
+		if !constrInfo.Enforced {
+			// The constraint which is not enforced doesn't affect the data, so we make it public directly.
+			// none -> public
+			constrInfo.State = model.StatePublic
+			ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constrInfo.State)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		// none -> write only
+		constrInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constrInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// All the new written rows are checked now, verify the existing rows.
+		if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constrInfo); err != nil {
+			if ErrCheckConstraintViolated.Equal(err) {
+				job.State = model.JobStateRollingback
+			}
+			return ver, errors.Trace(err)
+		}
+		// write only -> public
+		constrInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constrInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constrInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// verifyRemainRecordsForCheckConstraint checks whether the existing rows of the table satisfy the check constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constrInfo *model.ConstraintInfo) error {
+	failpoint.Inject("mockVerifyRemainDataSuccess", func(val failpoint.Value) {
+		if val.(bool) {
+			failpoint.Return(nil)
+		}
+	})
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	// The expression string is restored from the AST, escape `%` since it's embedded in the SQL template.
+	sql := fmt.Sprintf("select 1 from %%n.%%n where not (%s) limit 1", strings.ReplaceAll(constrInfo.ExprString, "%", "%%"))
+	stmt, err := sctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(w.ddlJobCtx, true, sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(w.ddlJobCtx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) != 0 {
+		return ErrCheckConstraintViolated.GenWithStackByArgs(constrInfo.Name.O)
+	}
+	return nil
+}
+
+// rollbackAddCheckConstraint removes the constraint which is violated by the existing rows.
+func rollbackAddCheckConstraint(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, constrName model.CIStr) (ver int64, err error) {
+	removeCheckConstraint(tblInfo, constrName)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constrName model.CIStr
+	if err = job.DecodeArgs(&constrName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constrInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constrInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	// Removing a check constraint only relaxes the write path, so it's safe to drop it in one step.
+	// public -> none
+	removeCheckConstraint(tblInfo, constrName)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func (w *worker) onAlterCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var (
+		constrName model.CIStr
+		enforced   bool
+	)
+	if err = job.DecodeArgs(&constrName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constrInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constrInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	if job.IsRollingback() {
+		// Restore the constraint to be not enforced.
+		constrInfo.Enforced = false
+		constrInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateRollbackDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	if job.SchemaState == model.StateNone && constrInfo.Enforced == enforced {
+		// Nothing to do.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+	if !enforced {
+		// Stop enforcing the constraint only relaxes the write path, so it's done in one step.
+		constrInfo.Enforced = false
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		// public(not enforced) -> write only
+		constrInfo.Enforced = true
+		constrInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constrInfo); err != nil {
+			if ErrCheckConstraintViolated.Equal(err) {
+				job.State = model.JobStateRollingback
+			}
+			return ver, errors.Trace(err)
+		}
+		// write only -> public
+		constrInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
@@ -651,7 +651,7 @@ func (s *testIntegrationSuite5) TestErrnoErrorCode(c *C) {
 	sql = "alter table test_drop_columns drop column c1, drop column c2, drop column c3;"
 	tk.MustGetErrCode(sql, errno.ErrCantRemoveAllFields)
 	sql = "alter table test_drop_columns drop column c1, add column c2 int;"
-	tk.MustGetErrCode(sql, errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode(sql, errno.ErrDupFieldName)
 	sql = "alter table test_drop_columns drop column c1, drop column c1;"
 	tk.MustGetErrCode(sql, errno.ErrCantDropFieldOrKey)
 	// add index
@@ -682,9 +682,13 @@ func (s *testIntegrationSuite5) TestErrnoErrorCode(c *C) {
 	tk.MustExec("set global tidb_enable_change_multi_schema = false")
 	sql = "alter table test_error_code_null add column (x1 int, x2 int)"
 	tk.MustGetErrCode(sql, errno.ErrUnsupportedDDLOperation)
-	sql = "alter table test_error_code_null add column (x1 int, x2 int)"
+	sql = "alter table test_error_code_null add column x1 int, add index idx_c1(c1)"
 	tk.MustGetErrCode(sql, errno.ErrUnsupportedDDLOperation)
 	tk.MustExec("set global tidb_enable_change_multi_schema = true")
+	// The columns are added by a multi-schema change job.
+	tk.MustExec("alter table test_error_code_null add column x1 int, add index idx_c1(c1)")
+	sql = "alter table test_error_code_null add column x1 int, add index idx_c2(c1)"
+	tk.MustGetErrCode(sql, errno.ErrDupFieldName)
 }
 
 func (s *testIntegrationSuite3) TestTableDDLWithFloatType(c *C) {
@@ -847,10 +851,11 @@ func (s *testIntegrationSuite3) TestChangingCharsetToUtf8(c *C) {
 	tk.MustExec("alter table t modify column a varchar(20) charset latin1")
 	tk.MustQuery("select * from t;").Check(testkit.Rows("t_value"))
 
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8 collate utf8_bin", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4 collate utf8mb4_general_ci", errno.ErrUnsupportedDDLOperation)
+	// The data is converted by the column-type-change reorg.
+	tk.MustExec("alter table t modify column a varchar(20) charset utf8")
+	tk.MustExec("alter table t modify column a varchar(20) charset utf8mb4 collate utf8mb4_general_ci")
+	tk.MustQuery("select * from t;").Check(testkit.Rows("t_value"))
+	tk.MustGetErrCode("alter table t modify column a varchar(20) charset gbk", errno.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4 collate utf8bin", errno.ErrUnknownCollation)
 	tk.MustGetErrCode("alter table t collate LATIN1_GENERAL_CI charset utf8 collate utf8_bin", errno.ErrConflictingDeclarations)
@@ -894,10 +899,16 @@ func (s *testIntegrationSuite4) TestChangingTableCharset(c *C) {
 	}
 	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
 
-	// Test when column charset can not convert to the target charset.
+	// Test when column data needs to be converted to the target charset.
 	tk.MustExec("drop table t;")
 	tk.MustExec("create table t(a varchar(10) character set ascii) charset utf8mb4")
-	tk.MustGetErrCode("alter table t convert to charset utf8mb4;", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t convert to charset utf8mb4;")
+	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
+
+	// Test when column charset can not convert to the target charset.
+	tk.MustExec("drop table t;")
+	tk.MustExec("create table t(a varchar(10) character set utf8mb4) charset utf8mb4")
+	tk.MustGetErrCode("alter table t convert to charset gbk;", errno.ErrUnsupportedDDLOperation)
 
 	tk.MustExec("drop table t;")
 	tk.MustExec("create table t(a varchar(10) character set utf8) charset utf8")
@@ -1303,7 +1303,19 @@ func (s *testIntegrationSuite3) TestCreateTableWithKeyPartition(c *C) {
 	partition by key(s1) partitions 10;`)
 
 	tk.MustExec(`drop table if exists tm2`)
-	tk.MustExec(`create table tm2 (a char(5), unique key(a(5))) partition by key() partitions 5;`)
+	tk.MustExec(`create table tm2 (a char(5) not null, unique key(a)) partition by key() partitions 5;`)
+	tk.MustQuery("show create table tm2").Check(testkit.Rows("tm2 CREATE TABLE `tm2` (\n" +
+		"  `a` char(5) NOT NULL,\n" +
+		"  UNIQUE KEY `a` (`a`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY KEY (`a`) PARTITIONS 5"))
+
+	// The columns of KEY() must be found from the primary key or a unique key on NOT NULL columns.
+	tk.MustExec(`drop table if exists tm3`)
+	tk.MustGetErrCode(`create table tm3 (a char(5), unique key(a(5))) partition by key() partitions 5;`, tmysql.ErrFieldNotFoundPart)
+	tk.MustGetErrCode(`create table tm3 (a int, b int) partition by key(a, a) partitions 5;`, tmysql.ErrSameNamePartitionField)
+	tk.MustGetErrCode(`create table tm3 (a int, b text) partition by key(b) partitions 5;`, tmysql.ErrFieldTypeNotAllowedAsPartitionField)
+	tk.MustGetErrCode(`create table tm3 (a int, b int, unique key(a)) partition by key(b) partitions 5;`, tmysql.ErrUniqueKeyNeedAllFieldsInPf)
 }
 
 func (s *testIntegrationSuite5) TestAlterTableAddPartition(c *C) {
@@ -3027,11 +3039,9 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash(store_id)
 	partitions 4;`)
-	_, err := tk.Exec("alter table employees add partition partitions 8;")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
-
-	_, err = tk.Exec("alter table employees add partition (partition p5 values less than (42));")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
+	tk.MustExec("alter table employees add partition partitions 8;")
+	tk.MustGetErrCode("alter table employees add partition partitions 0;", tmysql.ErrAddPartitionNoNewPartition)
+	tk.MustGetErrCode("alter table employees add partition (partition p5 values less than (42));", tmysql.ErrPartitionWrongValues)
 
 	// coalesce partition
 	tk.MustExec(`create table clients (
@@ -3042,24 +3052,24 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash( month(signed) )
 	partitions 12;`)
-	_, err = tk.Exec("alter table clients coalesce partition 4;")
-	c.Assert(ddl.ErrUnsupportedCoalescePartition.Equal(err), IsTrue)
+	tk.MustExec("alter table clients coalesce partition 4;")
+	tk.MustGetErrCode("alter table clients coalesce partition 0;", tmysql.ErrCoalescePartitionNoPartition)
+	tk.MustGetErrCode("alter table clients coalesce partition 8;", tmysql.ErrDropLastPartition)
 
 	tk.MustExec(`create table t_part (a int key)
 		partition by range(a) (
 		partition p0 values less than (10),
 		partition p1 values less than (20)
 		);`)
-	_, err = tk.Exec("alter table t_part coalesce partition 4;")
+	_, err := tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
 	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
-			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
+			partition p0 values less than (15));`, tmysql.ErrReorgOutsideRange)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
 
 	// Reduce the impact on DML when executing partition DDL
@@ -3210,8 +3220,8 @@ func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
 		);
 	`)
 
-	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	_, err := tk.Exec("alter table test_1465 partition by linear hash(a) partitions 4")
+	c.Assert(err, ErrorMatches, ".*Unsupported alter table partition by")
 }
 
 func (s *testSerialDBSuite1) TestCommitWhenSchemaChange(c *C) {
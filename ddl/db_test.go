@@ -2664,7 +2664,7 @@ func (s *testDBSuite5) TestRenameColumn(c *C) {
 
 	// Test renaming the column with foreign key.
 	tk.MustExec("drop table test_rename_column")
-	tk.MustExec("create table test_rename_column_base (base int)")
+	tk.MustExec("create table test_rename_column_base (base int, index(base))")
 	tk.MustExec("create table test_rename_column (col int, foreign key (col) references test_rename_column_base(base))")
 
 	tk.MustGetErrCode("alter table test_rename_column rename column col to col1", errno.ErrFKIncompatibleColumns)
@@ -3253,7 +3253,7 @@ func (s *testDBSuite2) TestCreateTableWithEnumCol(c *C) {
 func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
-	tk.MustExec("create table t1 (a int, b int);")
+	tk.MustExec("create table t1 (a int, b int, index(b));")
 	// test create table with foreign key.
 	failSQL := "create table t2 (c int, foreign key (a) references t1(a));"
 	tk.MustGetErrCode(failSQL, errno.ErrKeyColumnDoesNotExits)
@@ -6477,30 +6477,65 @@ func (s *testDBSuite4) TestColumnCheck(c *C) {
 	tk.MustExec("drop table if exists column_check")
 	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1))")
 	defer tk.MustExec("drop table if exists column_check")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustExec("insert into column_check values (1, 2), (2, null)")
+	tk.MustGetErrCode("insert into column_check values (3, 1)", errno.ErrCheckConstraintViolated)
+
+	tk.MustGetErrCode("create table t_other_col (a int, b int check (a > 1))", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t_auto_inc (a int auto_increment primary key check (a > 1))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t_func (a int check (a > rand()))", errno.ErrCheckConstraintNamedFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_var (a int check (a > @a))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("create table t_dup (a int, constraint c1 check (a > 1), constraint c1 check (a < 10))", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("create table t_unknown (a int, check (b > 1))", errno.ErrCheckConstraintRefersUnknownColumn)
 }
 
 func (s *testDBSuite5) TestAlterCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("create table alter_check (pk int primary key)")
+	tk.MustExec("create table alter_check (pk int primary key, a int)")
 	defer tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("alter table alter_check alter check crcn ENFORCED")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ALTER CHECK is not supported"))
+	tk.MustGetErrCode("alter table alter_check alter check crcn ENFORCED", errno.ErrConstraintNotFound)
+
+	tk.MustExec("alter table alter_check add constraint crcn check (a > 1) not enforced")
+	tk.MustExec("insert into alter_check values (1, 1)")
+	tk.MustGetErrCode("alter table alter_check alter check crcn enforced", errno.ErrCheckConstraintViolated)
+	// The failed job leaves the constraint not enforced.
+	tk.MustExec("insert into alter_check values (2, 0)")
+	tk.MustExec("delete from alter_check")
+	tk.MustExec("alter table alter_check alter check crcn enforced")
+	tk.MustGetErrCode("insert into alter_check values (3, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table alter_check alter check crcn not enforced")
+	tk.MustExec("insert into alter_check values (3, 0)")
+	tk.MustQuery("show create table alter_check").Check(testutil.RowsWithSep("|", ""+
+		"alter_check CREATE TABLE `alter_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `crcn` CHECK ((`a` > 1)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 }
 
 func (s *testDBSuite6) TestDropCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("create table drop_check (pk int primary key)")
+	tk.MustExec("create table drop_check (pk int primary key, a int, b int, constraint crcn check (a > b), check (b > 0))")
 	defer tk.MustExec("drop table if exists drop_check")
+	tk.MustGetErrCode("alter table drop_check drop check not_exist", errno.ErrConstraintNotFound)
+	tk.MustGetErrCode("insert into drop_check values (1, 1, 2)", errno.ErrCheckConstraintViolated)
 	tk.MustExec("alter table drop_check drop check crcn")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|DROP CHECK is not supported"))
+	tk.MustExec("insert into drop_check values (1, 1, 2)")
+
+	// The column used by other columns' check constraints can't be dropped or renamed.
+	tk.MustExec("alter table drop_check add constraint c_ab check (a + b > 0)")
+	tk.MustGetErrCode("alter table drop_check drop column a", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check rename column a to c", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check change column b c int", errno.ErrDependentByCheckConstraint)
+	tk.MustExec("alter table drop_check drop constraint c_ab")
+	// The check constraint only referencing the dropped column is dropped together.
+	tk.MustExec("alter table drop_check drop column b")
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "'").Check(testkit.Rows("0"))
 }
 
 func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
@@ -6509,22 +6544,36 @@ func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
 	tk.MustExec("drop table if exists add_constraint_check")
 	tk.MustExec("create table add_constraint_check (pk int primary key, a int)")
 	defer tk.MustExec("drop table if exists add_constraint_check")
+	tk.MustExec("insert into add_constraint_check values (1, 1)")
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 1)", errno.ErrCheckConstraintViolated)
+	// The constraint is removed after the job is rolled back.
+	tk.MustExec("insert into add_constraint_check values (2, 0)")
+	tk.MustExec("delete from add_constraint_check")
 	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1)")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ADD CONSTRAINT CHECK is not supported"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustGetErrCode("insert into add_constraint_check values (3, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a < 10)", errno.ErrCheckConstraintDupName)
+	tk.MustExec("alter table add_constraint_check add check (a < 10)")
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "' order by constraint_name").
+		Check(testkit.Rows("add_constraint_check_chk_1 (`a` < 10)", "crn (`a` > 1)"))
+	tk.MustQuery("select constraint_name from information_schema.table_constraints where table_name = 'add_constraint_check' and constraint_type = 'CHECK' order by constraint_name").
+		Check(testkit.Rows("add_constraint_check_chk_1", "crn"))
 }
 
-func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
+func (s *testDBSuite7) TestCreateTableWithCheckConstraint(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
-	tk.MustExec("drop table if exists table_constraint_check")
+	tk.MustExec("drop table if exists admin_user")
 	tk.MustExec("CREATE TABLE admin_user (enable bool, CHECK (enable IN (0, 1)));")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	defer tk.MustExec("drop table if exists admin_user")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
 	tk.MustQuery("show create table admin_user").Check(testutil.RowsWithSep("|", ""+
 		"admin_user CREATE TABLE `admin_user` (\n"+
-		"  `enable` tinyint(1) DEFAULT NULL\n"+
+		"  `enable` tinyint(1) DEFAULT NULL,\n"+
+		"  CONSTRAINT `admin_user_chk_1` CHECK ((`enable` in (0,1)))\n"+
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("insert into admin_user values (2)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into admin_user values (1)")
 }
 
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
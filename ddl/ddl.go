@@ -60,7 +60,9 @@ const (
 	DDLOwnerKey = "/tidb/ddl/fg/owner"
 	// addingDDLJobPrefix is the path prefix used to record the newly added DDL job, and it's saved to etcd.
 	addingDDLJobPrefix = "/tidb/ddl/add_ddl_job_"
-	ddlPrompt          = "ddl"
+	// addingDDLJobQueueKey is used to notify the owner of the newly added DDL job in the extra job queues.
+	addingDDLJobQueueKey = addingDDLJobPrefix + "queue"
+	ddlPrompt            = "ddl"
 
 	shardRowIDBitsMax = 15
 
@@ -176,8 +178,9 @@ type DDL interface {
 }
 
 type limitJobTask struct {
-	job *model.Job
-	err chan error
+	job      *model.Job
+	err      chan error
+	queueCnt int
 }
 
 // ddl is used to handle the statements that define the structure or schema of the database.
@@ -192,6 +195,13 @@ type ddl struct {
 	workers     map[workerType]*worker
 	sessPool    *sessionPool
 	delRangeMgr delRangeManager
+
+	// queueWorkers are the workers of the extra job queues, they're started when their queues have jobs.
+	queueWorkers struct {
+		sync.Mutex
+		workers map[string]*worker
+	}
+	queueWorkerCh chan struct{}
 }
 
 // ddlCtx is the context when we use worker to handle DDL jobs.
@@ -208,6 +218,11 @@ type ddlCtx struct {
 	statsHandle  *handle.Handle
 	tableLockCkr util.DeadTableLockChecker
 	etcdCli      *clientv3.Client
+	// ingest is built lazily when an index is added by ingesting.
+	ingest struct {
+		sync.Mutex
+		backend IngestBackend
+	}
 
 	// hook may be modified.
 	mu struct {
@@ -358,8 +373,8 @@ func (d *ddl) Start(ctxPool *pools.ResourcePool) error {
 		d.workers = make(map[workerType]*worker, 2)
 		d.sessPool = newSessionPool(ctxPool)
 		d.delRangeMgr = d.newDeleteRangeManager(ctxPool == nil)
-		d.workers[generalWorker] = newWorker(d.ctx, generalWorker, d.sessPool, d.delRangeMgr)
-		d.workers[addIdxWorker] = newWorker(d.ctx, addIdxWorker, d.sessPool, d.delRangeMgr)
+		d.workers[generalWorker] = newWorker(d.ctx, generalWorker, 0, d.sessPool, d.delRangeMgr)
+		d.workers[addIdxWorker] = newWorker(d.ctx, addIdxWorker, 0, d.sessPool, d.delRangeMgr)
 		for _, worker := range d.workers {
 			worker.wg.Add(1)
 			w := worker
@@ -377,6 +392,12 @@ func (d *ddl) Start(ctxPool *pools.ResourcePool) error {
 			d.wg.Add(1)
 			go d.startCleanDeadTableLock()
 		}
+		d.wg.Add(1)
+		go d.startIntervalPartitionManagement()
+		d.queueWorkers.workers = make(map[string]*worker)
+		d.queueWorkerCh = make(chan struct{}, 1)
+		d.wg.Add(1)
+		go d.startJobQueueWorkers()
 		metrics.DDLCounter.WithLabelValues(metrics.StartCleanWork).Inc()
 	}
 
@@ -400,6 +421,7 @@ func (d *ddl) close() {
 	for _, worker := range d.workers {
 		worker.close()
 	}
+	d.closeJobQueueWorkers()
 	// d.delRangeMgr using sessions from d.sessPool.
 	// Put it before d.sessPool.close to reduce the time spent by d.sessPool.close.
 	if d.delRangeMgr != nil {
@@ -408,6 +430,7 @@ func (d *ddl) close() {
 	if d.sessPool != nil {
 		d.sessPool.close()
 	}
+	d.closeIngestBackend()
 
 	variable.UnregisterStatistics(d)
 
@@ -536,9 +559,14 @@ func updateTickerInterval(ticker *time.Ticker, lease time.Duration, job *model.J
 // - context.Cancel: job has been sent to worker, but not found in history DDL job before cancel
 // - other: found in history DDL job and return that job error
 func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
+	if mci := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo; mci != nil {
+		// The job is submitted later as a sub-job of the multi-schema change job.
+		return errors.Trace(appendToSubJobs(mci, job))
+	}
 	// Get a global job ID and put the DDL job in the queue.
 	job.Query, _ = ctx.Value(sessionctx.QueryString).(string)
-	task := &limitJobTask{job, make(chan error)}
+	queueCnt := getJobQueueCount(ctx)
+	task := &limitJobTask{job, make(chan error), queueCnt}
 	d.limitJobCh <- task
 	// worker should restart to continue handling tasks in limitJobCh, and send back through task.err
 	err := <-task.err
@@ -550,7 +578,11 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 	ctx.GetSessionVars().StmtCtx.IsDDLJobInQueue = true
 
 	// Notice worker that we push a new job and wait the job done.
-	d.asyncNotifyWorker(job)
+	if jobQueueIdx(job, queueCnt) > 0 {
+		d.asyncNotifyJobQueueWorkers(job)
+	} else {
+		d.asyncNotifyWorker(job)
+	}
 	logutil.BgLogger().Info("[ddl] start DDL job", zap.String("job", job.String()), zap.String("query", job.Query))
 
 	var historyJob *model.Job
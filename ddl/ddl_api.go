@@ -829,7 +829,9 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				constraint := &ast.Constraint{Tp: ast.ConstraintCheck, Name: v.ConstraintName, Expr: v.Expr,
+					Enforced: v.Enforced, InColumn: true, InColumnName: colDef.Name.Name.O}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -1367,7 +1369,11 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 	fkNames := map[string]bool{}
 
 	// Check not empty constraint name whether is duplicated.
+	// The check constraint names are checked when building the constraint infos.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			err := checkDuplicateConstraint(fkNames, constr.Name, true)
 			if err != nil {
@@ -1552,6 +1558,9 @@ func buildTableInfo(
 			constr.Tp != ast.ConstraintPrimaryKey {
 			return nil, errUnsupportedClusteredSecondaryKey
 		}
+		if err := checkIndexOptionNotHypo(constr.Option); err != nil {
+			return nil, err
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			for _, fk := range tbInfo.ForeignKeys {
 				if fk.Name.L == strings.ToLower(constr.Name) {
@@ -1597,7 +1606,7 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			// Check constraints are built after all the columns are settled.
 			continue
 		}
 		// build index info.
@@ -1640,6 +1649,12 @@ func buildTableInfo(
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
 
+	if err = buildConstraintInfos(ctx, tbInfo, constraints); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = buildFKIndexes(tbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return
 }
 
@@ -1744,6 +1759,9 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 			}
 		}
 	}
+	if err := checkTTLInfoValid(ctx, tbInfo); err != nil {
+		return errors.Trace(err)
+	}
 
 	return nil
 }
@@ -1763,7 +1781,7 @@ func checkPartitionDefinitionConstraints(ctx sessionctx.Context, tbInfo *model.T
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
 		err = checkPartitionByRange(ctx, tbInfo)
-	case model.PartitionTypeHash:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
 		err = checkPartitionByHash(ctx, tbInfo)
 	case model.PartitionTypeList:
 		err = checkPartitionByList(ctx, tbInfo)
@@ -1998,6 +2016,9 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	if err = checkFKParentIndexes(is, schema.Name, tbInfo, tbInfo.ForeignKeys); err != nil {
+		return err
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
@@ -2611,7 +2632,11 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 	if tbInfo.PreSplitRegions > shardingBits {
 		tbInfo.PreSplitRegions = shardingBits
 	}
-	return nil
+	ttlInfo, ttlEnable, ttlJobInterval, err := getTTLInfoInOptions(options)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return updateTTLInfo(tbInfo, ttlInfo, ttlEnable, ttlJobInterval)
 }
 
 func shardingBits(tblInfo *model.TableInfo) uint64 {
@@ -2753,22 +2778,31 @@ func isSameTypeMultiSpecs(specs []*ast.AlterTableSpec) bool {
 	return true
 }
 
+// checkMultiSpecs checks whether the specs change several schema objects in one statement,
+// which is only allowed when tidb_enable_change_multi_schema is on.
 func checkMultiSpecs(sctx sessionctx.Context, specs []*ast.AlterTableSpec) error {
-	if !sctx.GetSessionVars().EnableChangeMultiSchema {
-		if len(specs) > 1 {
-			return errRunMultiSchemaChanges
-		}
-		if len(specs) == 1 && len(specs[0].NewColumns) > 1 && specs[0].Tp == ast.AlterTableAddColumns {
-			return errRunMultiSchemaChanges
-		}
-	} else {
-		if len(specs) > 1 && !isSameTypeMultiSpecs(specs) {
-			return errRunMultiSchemaChanges
-		}
+	if sctx.GetSessionVars().EnableChangeMultiSchema {
+		return nil
+	}
+	if len(specs) > 1 || (len(specs) == 1 && len(specs[0].NewColumns) > 1 && specs[0].Tp == ast.AlterTableAddColumns) {
+		return errMultiSchemaChangeDisabled
 	}
 	return nil
 }
 
+// isLegacyMultiSpecs returns whether the specs can be handled by the AddColumns, DropColumns
+// and DropIndexes jobs, which are kept for compatibility.
+func isLegacyMultiSpecs(specs []*ast.AlterTableSpec) bool {
+	if len(specs) <= 1 || !isSameTypeMultiSpecs(specs) {
+		return false
+	}
+	switch specs[0].Tp {
+	case ast.AlterTableAddColumns, ast.AlterTableDropColumn, ast.AlterTableDropPrimaryKey, ast.AlterTableDropIndex:
+		return true
+	}
+	return false
+}
+
 func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast.Ident, specs []*ast.AlterTableSpec) (err error) {
 	validSpecs, err := resolveAlterTableSpec(sctx, specs)
 	if err != nil {
@@ -2780,12 +2814,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
 	}
 
-	err = checkMultiSpecs(sctx, validSpecs)
-	if err != nil {
+	if err = checkMultiSpecs(sctx, validSpecs); err != nil {
 		return err
 	}
 
-	if len(validSpecs) > 1 {
+	if isLegacyMultiSpecs(validSpecs) {
 		switch validSpecs[0].Tp {
 		case ast.AlterTableAddColumns:
 			err = d.AddColumns(sctx, ident, validSpecs)
@@ -2793,30 +2826,47 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			err = d.DropColumns(sctx, ident, validSpecs)
 		case ast.AlterTableDropPrimaryKey, ast.AlterTableDropIndex:
 			err = d.DropIndexes(sctx, ident, validSpecs)
-		default:
-			return errRunMultiSchemaChanges
-		}
-		if err != nil {
-			return errors.Trace(err)
 		}
-		return nil
+		return errors.Trace(err)
+	}
+
+	if len(validSpecs) > 1 {
+		// The jobs built by the specs are collected as the sub-jobs of one multi-schema change job.
+		sctx.GetSessionVars().StmtCtx.MultiSchemaInfo = model.NewMultiSchemaInfo()
+		defer func() {
+			sctx.GetSessionVars().StmtCtx.MultiSchemaInfo = nil
+		}()
 	}
 
 	for _, spec := range validSpecs {
 		var handledCharsetOrCollate bool
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
-			if len(spec.NewColumns) != 1 {
-				err = d.AddColumns(sctx, ident, []*ast.AlterTableSpec{spec})
-			} else {
+			switch {
+			case len(spec.NewColumns) == 1:
 				err = d.AddColumn(sctx, ident, spec)
+			case sctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil:
+				// Each column is added by a sub-job.
+				for _, col := range spec.NewColumns {
+					colSpec := &ast.AlterTableSpec{
+						IfNotExists: spec.IfNotExists,
+						Tp:          ast.AlterTableAddColumns,
+						NewColumns:  []*ast.ColumnDef{col},
+						Position:    spec.Position,
+					}
+					if err = d.AddColumn(sctx, ident, colSpec); err != nil {
+						break
+					}
+				}
+			default:
+				err = d.AddColumns(sctx, ident, []*ast.AlterTableSpec{spec})
 			}
 		case ast.AlterTableAddPartitions:
 			err = d.AddTablePartitions(sctx, ident, spec)
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(sctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(sctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2824,7 +2874,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableOptimizePartition:
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(errUnsupportedRemovePartition)
+			err = d.RemovePartitioning(sctx, ident)
+		case ast.AlterTableAddLastPartition:
+			err = d.AddLastPartition(sctx, ident, spec)
+		case ast.AlterTableDropFirstPartition:
+			err = d.DropFirstPartition(sctx, ident, spec)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2841,7 +2895,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			err = d.TruncateTablePartition(sctx, ident, spec)
 		case ast.AlterTableWriteable:
 			if !config.TableLockEnabled() {
-				return nil
+				continue
 			}
 			tName := &ast.TableName{Schema: ident.Schema, Name: ident.Name}
 			if spec.Writeable {
@@ -2877,7 +2931,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			case ast.ConstraintFulltext:
 				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				err = d.CreateCheckConstraint(sctx, ident, spec.Constraint)
 			default:
 				// Nothing to do now.
 			}
@@ -2897,11 +2951,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			isAlterTable := true
 			err = d.RenameTable(sctx, ident, newIdent, isAlterTable)
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(sctx, ident, spec)
 		case ast.AlterTableOption:
 			var placementSettings *model.PlacementSettings
 			var placementPolicyRef *model.PolicyRefInfo
+			var hasTTLOption bool
 			for i, opt := range spec.Options {
 				switch opt.Tp {
 				case ast.TableOptionShardRowID:
@@ -2950,6 +3004,9 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 						placementSettings = &model.PlacementSettings{}
 					}
 					err = SetDirectPlacementOpt(placementSettings, ast.PlacementOptionType(opt.Tp), opt.StrValue, opt.UintValue)
+				case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobInterval:
+					// The TTL options are handled together after all the options are checked.
+					hasTTLOption = true
 				case ast.TableOptionEngine:
 				default:
 					err = errUnsupportedAlterTableOption
@@ -2962,17 +3019,32 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 
 			if placementPolicyRef != nil || placementSettings != nil {
 				err = d.AlterTablePlacement(sctx, ident, placementPolicyRef, placementSettings)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			if hasTTLOption {
+				ttlInfo, ttlEnable, ttlJobInterval, err := getTTLInfoInOptions(spec.Options)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				err = d.AlterTableTTLInfoOrEnable(sctx, ident, ttlInfo, ttlEnable, ttlJobInterval)
+				if err != nil {
+					return errors.Trace(err)
+				}
 			}
 		case ast.AlterTableSetTiFlashReplica:
 			err = d.AlterTableSetTiFlashReplica(sctx, ident, spec.TiFlashReplica)
 		case ast.AlterTableOrderByColumns:
 			err = d.OrderByColumns(sctx, ident)
+		case ast.AlterTableRemoveTTL:
+			err = d.AlterTableRemoveTTL(sctx, ident)
 		case ast.AlterTableIndexInvisible:
 			err = d.AlterIndexVisibility(sctx, ident, spec.IndexName, spec.Visibility)
 		case ast.AlterTableAlterCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			err = d.AlterCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name), spec.Constraint.Enforced)
 		case ast.AlterTableDropCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			err = d.DropCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name))
 		case ast.AlterTableWithValidation:
 			sctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -3000,7 +3072,10 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		}
 	}
 
-	return nil
+	if sctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil {
+		err = d.multiSchemaChange(sctx, ident)
+	}
+	return errors.Trace(err)
 }
 
 func (d *ddl) RebaseAutoID(ctx sessionctx.Context, ident ast.Ident, newBase int64, tp autoid.AllocatorType, force bool) error {
@@ -3132,10 +3207,12 @@ func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 	return nil
 }
 
-func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, spec *ast.AlterTableSpec, t table.Table, specNewColumn *ast.ColumnDef) (*table.Column, error) {
+// checkAndCreateNewColumn checks and creates the column to be added, the check constraints defined on the column
+// are returned too.
+func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, spec *ast.AlterTableSpec, t table.Table, specNewColumn *ast.ColumnDef) (*table.Column, []*ast.Constraint, error) {
 	err := checkUnsupportedColumnConstraint(specNewColumn, ti)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	colName := specNewColumn.Name.Name.O
@@ -3145,15 +3222,15 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		err = infoschema.ErrColumnExists.GenWithStackByArgs(colName)
 		if spec.IfNotExists {
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
-			return nil, nil
+			return nil, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
 	if err = checkColumnAttributes(colName, specNewColumn.Tp); err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	if utf8.RuneCountInString(colName) > mysql.MaxColumnNameLength {
-		return nil, ErrTooLongIdent.GenWithStackByArgs(colName)
+		return nil, nil, ErrTooLongIdent.GenWithStackByArgs(colName)
 	}
 
 	// If new column is a generated column, do validation.
@@ -3162,17 +3239,17 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	for _, option := range specNewColumn.Options {
 		if option.Tp == ast.ColumnOptionGenerated {
 			if err := checkIllegalFn4Generated(specNewColumn.Name.Name.L, typeColumn, option.Expr); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 
 			if option.Stored {
-				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
+				return nil, nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
 			}
 
 			_, dependColNames := findDependedColumnNames(specNewColumn)
 			if !ctx.GetSessionVars().EnableAutoIncrementInGenerated {
 				if err = checkAutoIncrementRef(specNewColumn.Name.Name.L, dependColNames, t.Meta()); err != nil {
-					return nil, errors.Trace(err)
+					return nil, nil, errors.Trace(err)
 				}
 			}
 			duplicateColNames := make(map[string]struct{}, len(dependColNames))
@@ -3182,11 +3259,11 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 			cols := t.Cols()
 
 			if err = checkDependedColExist(dependColNames, cols); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 
 			if err = verifyColumnGenerationSingle(duplicateColNames, cols, spec.Position); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 		}
 		// Specially, since sequence has been supported, if a newly added column has a
@@ -3196,10 +3273,10 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		if option.Tp == ast.ColumnOptionDefaultValue {
 			_, isSeqExpr, err := tryToGetSequenceDefaultValue(option)
 			if err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 			if isSeqExpr {
-				return nil, errors.Trace(ErrAddColumnWithSequenceAsDefault.GenWithStackByArgs(specNewColumn.Name.Name.O))
+				return nil, nil, errors.Trace(ErrAddColumnWithSequenceAsDefault.GenWithStackByArgs(specNewColumn.Name.Name.O))
 			}
 		}
 	}
@@ -3209,11 +3286,12 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		ast.CharsetOpt{Chs: schema.Charset, Col: schema.Collate},
 	)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	// Ignore table constraints now, they will be checked later.
 	// We use length(t.Cols()) as the default offset firstly, we will change the column's offset later.
-	col, _, err = buildColumnAndConstraint(
+	var constraints []*ast.Constraint
+	col, constraints, err = buildColumnAndConstraint(
 		ctx,
 		len(t.Cols()),
 		specNewColumn,
@@ -3222,16 +3300,22 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		tableCollate,
 	)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
+	}
+	checkConstraints := make([]*ast.Constraint, 0, len(constraints))
+	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			checkConstraints = append(checkConstraints, constr)
+		}
 	}
 
 	originDefVal, err := generateOriginDefaultValue(col.ToInfo())
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	err = col.SetOriginDefaultValue(originDefVal)
-	return col, err
+	return col, checkConstraints, err
 }
 
 // AddColumn will add a new column to the table.
@@ -3244,7 +3328,7 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + 1); err != nil {
 		return errors.Trace(err)
 	}
-	col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
+	col, checkConstraints, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -3252,6 +3336,18 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 	if col == nil {
 		return nil
 	}
+	var constraintInfos []*model.ConstraintInfo
+	if len(checkConstraints) > 0 {
+		// The check constraints are verified after the column is public, a multi-schema change can't be
+		// rolled back then.
+		if ctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil {
+			return errors.Trace(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD COLUMN with CONSTRAINT CHECK in a multi-schema change"))
+		}
+		constraintInfos, err = buildAddColumnConstraintInfos(ctx, t.Meta(), col.ToInfo(), checkConstraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -3259,7 +3355,7 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddColumn,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{col, spec.Position, 0},
+		Args:       []interface{}{col, spec.Position, 0, constraintInfos},
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -3307,10 +3403,13 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				ctx.GetSessionVars().StmtCtx.AppendNote(err)
 				continue
 			}
-			col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
+			col, checkConstraints, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 			if err != nil {
 				return errors.Trace(err)
 			}
+			if len(checkConstraints) > 0 {
+				return errors.Trace(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD COLUMNS with CONSTRAINT CHECK"))
+			}
 			// Added column has existed and if_not_exists flag is true.
 			if col == nil && spec.IfNotExists {
 				continue
@@ -3363,6 +3462,9 @@ func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	if pi == nil {
 		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
+	if pi.Type == model.PartitionTypeHash || pi.Type == model.PartitionTypeKey {
+		return d.reorganizeHashPartitions(ctx, schema, meta, spec)
+	}
 
 	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
 	if err != nil {
@@ -3407,6 +3509,248 @@ func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	return errors.Trace(err)
 }
 
+// ReorganizePartitions reorganizes the partitions of a range or list partitioned table into the new
+// partitions, the rows of the reorganized partitions are copied to the new ones online.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if spec.OnAllPartitions {
+		return errors.Trace(ErrReorgNoParam)
+	}
+	if pi.Type != model.PartitionTypeRange && pi.Type != model.PartitionTypeList {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if hasGlobalIndex(meta) {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	partNames := make([]string, len(spec.PartitionNames))
+	for i, partCIName := range spec.PartitionNames {
+		partNames[i] = partCIName.L
+	}
+	if err = checkReorganizePartition(ctx, meta, partNames, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+	return d.doReorganizePartitionJob(ctx, schema, meta, partNames, partInfo)
+}
+
+// reorganizeHashPartitions changes the number of partitions of a hash or key partitioned table by ADD
+// PARTITION or COALESCE PARTITION. Since the partition of a row depends on the number of partitions,
+// all the partitions are reorganized into the new ones, the remaining partitions keep their names,
+// comments and placement.
+func (d *ddl) reorganizeHashPartitions(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, spec *ast.AlterTableSpec) error {
+	pi := meta.Partition
+	if hasGlobalIndex(meta) {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+
+	oldNum := len(pi.Definitions)
+	newNum := oldNum
+	var addedDefs []model.PartitionDefinition
+	switch spec.Tp {
+	case ast.AlterTableAddPartitions:
+		if len(spec.PartDefinitions) > 0 {
+			for _, def := range spec.PartDefinitions {
+				if err := def.Clause.Validate(pi.Type, len(pi.Columns)); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			tmpMeta := meta.Clone()
+			tmpMeta.Partition.Num = uint64(len(spec.PartDefinitions))
+			defs, err := buildPartitionDefinitionsInfo(ctx, spec.PartDefinitions, tmpMeta)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			addedDefs = defs
+		} else {
+			if spec.Num == 0 {
+				return errors.Trace(ErrAddPartitionNoNewPartition)
+			}
+			for i := 0; i < int(spec.Num); i++ {
+				addedDefs = append(addedDefs, model.PartitionDefinition{Name: model.NewCIStr(fmt.Sprintf("p%d", oldNum+i))})
+			}
+		}
+		newNum = oldNum + len(addedDefs)
+	case ast.AlterTableCoalescePartitions:
+		if spec.Num == 0 {
+			return errors.Trace(ErrCoalescePartitionNoPartition)
+		}
+		if spec.Num >= uint64(oldNum) {
+			return errors.Trace(ErrDropLastPartition)
+		}
+		newNum = oldNum - int(spec.Num)
+	}
+
+	defs := make([]model.PartitionDefinition, 0, newNum)
+	for _, def := range pi.Definitions[:mathutil.Min(oldNum, newNum)] {
+		defs = append(defs, model.PartitionDefinition{
+			Name:                def.Name,
+			Comment:             def.Comment,
+			PlacementPolicyRef:  def.PlacementPolicyRef,
+			DirectPlacementOpts: def.DirectPlacementOpts,
+		})
+	}
+	defs = append(defs, addedDefs...)
+	partInfo := &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Enable:      pi.Enable,
+		Num:         uint64(len(defs)),
+		Definitions: defs,
+	}
+	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	partNames := make([]string, 0, oldNum)
+	for _, def := range pi.Definitions {
+		partNames = append(partNames, def.Name.L)
+	}
+	if err := checkReorganizePartition(ctx, meta, partNames, partInfo); err != nil {
+		if ErrSameNamePartition.Equal(err) && spec.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return d.doReorganizePartitionJob(ctx, schema, meta, partNames, partInfo)
+}
+
+// doReorganizePartitionJob runs the job which reorganizes the partitions of partNames into the partitions of partInfo.
+func (d *ddl) doReorganizePartitionJob(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, partNames []string, partInfo *model.PartitionInfo) error {
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{partNames, partInfo},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err := d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTablePartitioning repartitions the table by the partitioning method of ALTER TABLE ... PARTITION BY,
+// the table can be a non-partitioned one.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	meta := t.Meta()
+	if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedAlterTablePartitioning)
+	}
+	newMeta := meta.Clone()
+	newMeta.Partition = nil
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := newMeta.Partition
+	if partInfo == nil {
+		// The partitioning method is not supported or the partition is disabled.
+		return errors.Trace(errUnsupportedAlterTablePartitioning)
+	}
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitioningKeysOfTable(newMeta, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkTableInfoValid(newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTablePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{[]string{}, partInfo},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// RemovePartitioning converts the partitioned table to a non-partitioned one.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	meta := t.Meta()
+	if meta.GetPartitionInfo() == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedRemovePartition)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{[]string{}, newFullTablePartitionInfo(meta)},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 // CoalescePartitions coalesce partitions can be used with a table that is partitioned by hash or key to reduce the number of partitions by number.
 func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
@@ -3425,19 +3769,13 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	}
 
 	switch meta.Partition.Type {
-	// We don't support coalesce partitions hash type partition now.
-	case model.PartitionTypeHash:
-		return errors.Trace(ErrUnsupportedCoalescePartition)
-
-	// Key type partition cannot be constructed currently, ignoring it for now.
-	case model.PartitionTypeKey:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
+		return d.reorganizeHashPartitions(ctx, schema, meta, spec)
 
 	// Coalesce partition can only be used on hash/key partitions.
 	default:
 		return errors.Trace(ErrCoalesceOnlyOnHashPartition)
 	}
-
-	return errors.Trace(err)
 }
 
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
@@ -3861,7 +4199,8 @@ func checkIsDroppableColumn(ctx sessionctx.Context, t table.Table, spec *ast.Alt
 		return false, err
 	}
 
-	if err = isDroppableColumn(ctx.GetSessionVars().EnableChangeMultiSchema, tblInfo, colName); err != nil {
+	multiSchemaChange := ctx.GetSessionVars().EnableChangeMultiSchema || ctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil
+	if err = isDroppableColumn(multiSchemaChange, tblInfo, colName); err != nil {
 		return false, errors.Trace(err)
 	}
 	// We don't support dropping column with PK handle covered now.
@@ -4069,10 +4408,24 @@ func checkModifyTypes(ctx sessionctx.Context, origin *types.FieldType, to *types
 		if errUnsupportedModifyCharset.Equal(err) && canReorg {
 			return nil
 		}
+		// The string data is re-encoded and the indexes are rebuilt in the process of the reorg.
+		if (errUnsupportedModifyCharset.Equal(err) || errUnsupportedModifyCollation.Equal(err)) &&
+			types.IsString(origin.Tp) && types.IsString(to.Tp) && canRewriteCharsetAndCollation(origin.Charset, to.Charset) {
+			return nil
+		}
 	}
 	return errors.Trace(err)
 }
 
+// canRewriteCharsetAndCollation returns true if the string data can be converted from the original charset to
+// the target charset by the column-type-change reorg.
+func canRewriteCharsetAndCollation(origCharset, toCharset string) bool {
+	canRewrite := func(chs string) bool {
+		return len(chs) != 0 && chs != charset.CharsetBin && chs != charset.CharsetGBK
+	}
+	return canRewrite(origCharset) && canRewrite(toCharset)
+}
+
 func setDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.ColumnOption) (bool, error) {
 	hasDefaultValue := false
 	value, isSeqExpr, err := getDefaultValue(ctx, col, option)
@@ -4249,6 +4602,9 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if constrs := findDependentCheckConstraints(t.Meta(), originalColName); len(constrs) > 0 {
+			return nil, errDependentByCheckConstraint.GenWithStackByArgs(constrs[0].Name.O, originalColName.O)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -4335,7 +4691,10 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		}
 		return nil, errors.Trace(err)
 	}
-	if needChangeColumnData(col.ColumnInfo, newCol.ColumnInfo) {
+	if needChangeColumnData(t.Meta(), col.ColumnInfo, newCol.ColumnInfo) {
+		if mysql.HasPriKeyFlag(col.Flag) {
+			return nil, errUnsupportedModifyColumn.GenWithStackByArgs("this column has primary key flag")
+		}
 		if err = isGeneratedRelatedColumn(t.Meta(), newCol.ColumnInfo, col.ColumnInfo); err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -4367,6 +4726,10 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		return nil, err
 	}
 
+	if ttlInfo := t.Meta().TTLInfo; ttlInfo != nil && ttlInfo.ColumnName.L == originalColName.L && !types.IsTypeTime(newCol.Tp) {
+		return nil, ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(newCol.Name.O)
+	}
+
 	// As same with MySQL, we don't support modifying the stored status for generated columns.
 	if err = checkModifyGeneratedColumn(sctx, t, col, newCol, specNewColumn, spec.Position); err != nil {
 		return nil, errors.Trace(err)
@@ -4597,6 +4960,9 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if fkInfo := getColumnForeignKeyInfo(oldColName.L, tbl.Meta().ForeignKeys); fkInfo != nil {
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
+	if constrs := findDependentCheckConstraints(tbl.Meta(), oldColName); len(constrs) > 0 {
+		return errDependentByCheckConstraint.GenWithStackByArgs(constrs[0].Name.O, oldColName.O)
+	}
 
 	// Check generated expression.
 	for _, col := range allCols {
@@ -4683,6 +5049,8 @@ func (d *ddl) AlterColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Alt
 	if col == nil {
 		return ErrBadField.GenWithStackByArgs(colName, ident.Name)
 	}
+	// The column is shared with the info schema, clone it before changing the default value.
+	col = table.ToColumn(col.Clone())
 
 	// Clean the NoDefaultValueFlag value.
 	col.Flag &= ^mysql.NoDefaultValueFlag
@@ -4804,7 +5172,6 @@ func (d *ddl) AlterTableCharsetAndCollate(ctx sessionctx.Context, ident ast.Iden
 	if doNothing {
 		return nil
 	}
-
 	job := &model.Job{
 		SchemaID:   schema.ID,
 		TableID:    tb.Meta().ID,
@@ -4813,11 +5180,75 @@ func (d *ddl) AlterTableCharsetAndCollate(ctx sessionctx.Context, ident ast.Iden
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{toCharset, toCollate, needsOverwriteCols},
 	}
+	if needsOverwriteCols {
+		info, err := buildConvertColumnsCharsetInfo(tb.Meta(), toCharset, toCollate)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if info != nil {
+			// The columns and the table are converted by one job, so they are rolled back together if any of them fails.
+			info.SubJobs = append(info.SubJobs, &model.SubJob{
+				Type:       job.Type,
+				Args:       job.Args,
+				Revertible: true,
+			})
+			job.Type = model.ActionMultiSchemaChange
+			job.Args = nil
+			job.MultiSchemaInfo = info
+			job.ReorgMeta = &model.DDLReorgMeta{
+				SQLMode:       ctx.GetSessionVars().SQLMode,
+				Warnings:      make(map[errors.ErrorID]*terror.Error),
+				WarningsCount: make(map[errors.ErrorID]int64),
+			}
+			job.Priority = ctx.GetSessionVars().DDLReorgPriority
+		}
+	}
 	err = d.doDDLJob(ctx, job)
 	err = d.callHookOnChanged(err)
 	return errors.Trace(err)
 }
 
+// buildConvertColumnsCharsetInfo builds the column-type-change sub-jobs for the columns whose data needs to be
+// rewritten to the target charset and collation. It returns nil if no column needs to be rewritten, the charset and
+// collation of the other columns are changed by the ActionModifyTableCharsetAndCollate job.
+func buildConvertColumnsCharsetInfo(tblInfo *model.TableInfo, toCharset, toCollate string) (*model.MultiSchemaInfo, error) {
+	var info *model.MultiSchemaInfo
+	for _, col := range tblInfo.Columns {
+		if !field_types.HasCharset(&col.FieldType) {
+			continue
+		}
+		newCol := table.ToColumn(col.Clone())
+		newCol.Charset = toCharset
+		newCol.Collate = toCollate
+		if !needChangeColumnData(tblInfo, col, newCol.ColumnInfo) {
+			continue
+		}
+		if mysql.HasPriKeyFlag(col.Flag) {
+			return nil, errUnsupportedModifyColumn.GenWithStackByArgs(fmt.Sprintf("converting the charset of column '%s' which has primary key flag", col.Name.O))
+		}
+		if tblInfo.Partition != nil {
+			return nil, errUnsupportedModifyColumn.GenWithStackByArgs("table is partition table")
+		}
+		if err := isGeneratedRelatedColumn(tblInfo, newCol.ColumnInfo, col); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := checkColumnWithIndexConstraint(tblInfo, col, newCol.ColumnInfo); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if info == nil {
+			info = model.NewMultiSchemaInfo()
+		}
+		job := &model.Job{
+			Type: model.ActionModifyColumn,
+			Args: []interface{}{&newCol, col.Name, &ast.ColumnPosition{Tp: ast.ColumnPositionNone}, byte(0), uint64(0)},
+		}
+		if err := appendToSubJobs(info, job); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return info, nil
+}
+
 // AlterTableSetTiFlashReplica sets the TiFlash replicas info.
 func (d *ddl) AlterTableSetTiFlashReplica(ctx sessionctx.Context, ident ast.Ident, replicaInfo *ast.TiFlashReplicaSpec) error {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
@@ -4889,10 +5320,6 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
@@ -4922,11 +5349,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
-
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
 	return d.ddlCtx.statsHandle.InsertExtendedStats(stats.StatsName, colIDs, int(stats.StatsType), tblInfo.ID, ifNotExists)
@@ -5018,7 +5443,10 @@ func checkAlterTableCharset(tblInfo *model.TableInfo, dbInfo *model.DBInfo, toCh
 	}
 
 	if err = checkModifyCharsetAndCollation(toCharset, toCollate, origCharset, origCollate, false); err != nil {
-		return doNothing, err
+		// The columns are converted to the target charset by the column-type-change reorg.
+		if !needsOverwriteCols || !errUnsupportedModifyCharset.Equal(err) || !canRewriteCharsetAndCollation(origCharset, toCharset) {
+			return doNothing, err
+		}
 	}
 	if !needsOverwriteCols {
 		// If we don't change the charset and collation of columns, skip the next checks.
@@ -5038,6 +5466,11 @@ func checkAlterTableCharset(tblInfo *model.TableInfo, dbInfo *model.DBInfo, toCh
 			continue
 		}
 		if err = checkModifyCharsetAndCollation(toCharset, toCollate, col.Charset, col.Collate, isColumnWithIndex(col.Name.L, tblInfo.Indices)); err != nil {
+			if (errUnsupportedModifyCharset.Equal(err) || errUnsupportedModifyCollation.Equal(err)) &&
+				types.IsString(col.Tp) && canRewriteCharsetAndCollation(col.Charset, toCharset) {
+				// The column is converted by the column-type-change reorg.
+				continue
+			}
 			if strings.Contains(err.Error(), "Unsupported modifying collation") {
 				colErrMsg := "Unsupported converting collation of column '%s' from '%s' to '%s' when index is defined on it."
 				err = errUnsupportedModifyCollation.GenWithStack(colErrMsg, col.Name.L, col.Collate, toCollate)
@@ -5366,6 +5799,9 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 		return ErrUnsupportedModifyPrimaryKey.GenWithStack("Adding clustered primary key is not supported. " +
 			"Please consider adding NONCLUSTERED primary key instead")
 	}
+	if err := checkIndexOptionNotHypo(indexOption); err != nil {
+		return err
+	}
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
 		return errors.Trace(err)
@@ -5412,7 +5848,7 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 			return err
 		}
 		if !ck {
-			if !config.GetGlobalConfig().EnableGlobalIndex {
+			if !isGlobalIndexEnabled(ctx) {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY")
 			}
 			// index columns does not contain all partition columns, must set global
@@ -5533,6 +5969,9 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
 		return errUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
 	}
+	if err := checkIndexOptionNotHypo(indexOption); err != nil {
+		return err
+	}
 	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
@@ -5585,6 +6024,10 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	finalColumns := make([]*model.ColumnInfo, len(tblInfo.Columns), len(tblInfo.Columns)+len(hiddenCols))
 	copy(finalColumns, tblInfo.Columns)
 	finalColumns = append(finalColumns, hiddenCols...)
+	if mci := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo; mci != nil {
+		// The index can be built on the columns added by the same statement.
+		finalColumns = append(finalColumns, pendingAddedColumns(mci)...)
+	}
 	// Check before the job is put to the queue.
 	// This check is redundant, but useful. If DDL check fail before the job is put
 	// to job queue, the fail path logic is super fast.
@@ -5603,7 +6046,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 			return err
 		}
 		if !ck {
-			if !config.GetGlobalConfig().EnableGlobalIndex {
+			if !isGlobalIndexEnabled(ctx) {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
 			// index columns does not contain all partition columns, must set global
@@ -5624,6 +6067,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 			SQLMode:       ctx.GetSessionVars().SQLMode,
 			Warnings:      make(map[errors.ErrorID]*terror.Error),
 			WarningsCount: make(map[errors.ErrorID]int64),
+			ReorgTp:       pickReorgType(ctx, tblInfo, unique),
 		},
 		Args:     []interface{}{unique, indexName, indexPartSpecifications, indexOption, hiddenCols, global},
 		Priority: ctx.GetSessionVars().DDLReorgPriority,
@@ -5639,6 +6083,23 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	return errors.Trace(err)
 }
 
+// pickReorgType decides how to backfill the adding index. The index is added by ingesting the sorted index
+// data only if it's enabled, and the uniqueness of the index doesn't need to be checked during the backfill.
+func pickReorgType(ctx sessionctx.Context, tblInfo *model.TableInfo, unique bool) model.ReorgType {
+	if unique || tblInfo.TempTableType != model.TempTableNone || ctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil {
+		return model.ReorgTypeTxn
+	}
+	val, err := variable.GetGlobalSystemVar(ctx.GetSessionVars(), variable.TiDBDDLEnableFastReorg)
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] won't add index by ingesting", zap.Error(err))
+		return model.ReorgTypeTxn
+	}
+	if variable.TiDBOptOn(val) {
+		return model.ReorgTypeIngest
+	}
+	return model.ReorgTypeTxn
+}
+
 func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *ast.ReferenceDef, cols []*table.Column, tbInfo *model.TableInfo) (*model.FKInfo, error) {
 	if len(keys) != len(refer.IndexPartSpecifications) {
 		return nil, infoschema.ErrForeignKeyNotMatch.GenWithStackByArgs("foreign key without name")
@@ -5655,9 +6116,10 @@ func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *
 	}
 
 	fkInfo := &model.FKInfo{
-		Name:     fkName,
-		RefTable: refer.Table.Name,
-		Cols:     make([]model.CIStr, len(keys)),
+		Name:      fkName,
+		RefSchema: refer.Table.Schema,
+		RefTable:  refer.Table.Name,
+		Cols:      make([]model.CIStr, len(keys)),
 	}
 
 	for i, key := range keys {
@@ -5739,6 +6201,36 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkFKParentIndexes(is, schema.Name, t.Meta(), []*model.FKInfo{fkInfo}); err != nil {
+		return err
+	}
+	// Build the index to look up the child rows in the same job if there isn't one, the same as MySQL.
+	var submitMultiSchemaChange bool
+	mci := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo
+	if !hasFKLookupIndex(t.Meta(), fkInfo.Cols) && !hasPendingFKLookupIndex(mci, fkInfo.Cols) {
+		if mci == nil {
+			ctx.GetSessionVars().StmtCtx.MultiSchemaInfo = model.NewMultiSchemaInfo()
+			defer func() {
+				ctx.GetSessionVars().StmtCtx.MultiSchemaInfo = nil
+			}()
+			submitMultiSchemaChange = true
+		}
+		err = d.CreateIndex(ctx, ti, ast.IndexKeyTypeNone, fkIndexName(t.Meta(), fkInfo.Name), fkIndexKeys(fkInfo.Cols), nil, false)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// Put the referenced table in the args, so the job depends on the running jobs of the referenced table.
+	var refSchemaID, refTableID int64
+	refSchemaName := fkInfo.RefSchema
+	if refSchemaName.L == "" {
+		refSchemaName = schema.Name
+	}
+	if refSchema, ok := is.SchemaByName(refSchemaName); ok {
+		if refTbl, err := is.TableByName(refSchemaName, fkInfo.RefTable); err == nil {
+			refSchemaID, refTableID = refSchema.ID, refTbl.Meta().ID
+		}
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5746,10 +6238,13 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddForeignKey,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{fkInfo},
+		Args:       []interface{}{fkInfo, refSchemaID, refTableID, ctx.GetSessionVars().ForeignKeyChecks},
 	}
 
 	err = d.doDDLJob(ctx, job)
+	if err == nil && submitMultiSchemaChange {
+		return errors.Trace(d.multiSchemaChange(ctx, ti))
+	}
 	err = d.callHookOnChanged(err)
 	return errors.Trace(err)
 }
@@ -5780,6 +6275,93 @@ func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.
 	return errors.Trace(err)
 }
 
+// CreateCheckConstraint adds a check constraint to the table, the existing rows are verified by the DDL job.
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	if constr.Name == "" {
+		existNames := make(map[string]struct{}, len(tblInfo.Constraints))
+		for _, c := range tblInfo.Constraints {
+			existNames[c.Name.L] = struct{}{}
+		}
+		constr.Name = genConstraintName(tblInfo, existNames)
+	} else if tblInfo.FindConstraintInfoByName(constr.Name) != nil {
+		return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+	}
+
+	constrInfo, err := buildConstraintInfo(tblInfo, constr, model.StateNone)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkConstraintExpr(ctx, tblInfo, constr, constrInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropCheckConstraint drops the check constraint of the table.
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterCheckConstraint changes whether the check constraint is enforced.
+func (d *ddl) AlterCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, enforced bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName, enforced},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -5815,6 +6397,9 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkDropIndexOnForeignKey(is, schema.Name, t.Meta(), indexInfo); err != nil {
+		return err
+	}
 
 	jobTp := model.ActionDropIndex
 	if isPK {
@@ -5851,6 +6436,7 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 	}
 	indexNames := make([]model.CIStr, 0, len(specs))
 	ifExists := make([]bool, 0, len(specs))
+	droppedIndexes := make([]*model.IndexInfo, 0, len(specs))
 	for _, spec := range specs {
 		var indexName model.CIStr
 		if spec.Tp == ast.AlterTableDropPrimaryKey {
@@ -5868,11 +6454,15 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 			if err := checkDropIndexOnAutoIncrementColumn(t.Meta(), indexInfo); err != nil {
 				return errors.Trace(err)
 			}
+			droppedIndexes = append(droppedIndexes, indexInfo)
 		}
 
 		indexNames = append(indexNames, indexName)
 		ifExists = append(ifExists, spec.IfExists)
 	}
+	if err = checkDropIndexOnForeignKey(d.infoCache.GetLatest(), schema.Name, t.Meta(), droppedIndexes...); err != nil {
+		return err
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5932,6 +6522,18 @@ func isDroppableColumn(multiSchemaChange bool, tblInfo *model.TableInfo, colName
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
+	if tblInfo.TTLInfo != nil && tblInfo.TTLInfo.ColumnName.L == colName.L {
+		return ErrTTLColumnCannotDrop.GenWithStackByArgs(colName.O)
+	}
+	return checkDropColumnWithCheckConstraint(tblInfo, colName)
+}
+
+// checkIndexOptionNotHypo rejects the hypothetical index type. Hypothetical indexes are only kept in the session
+// by CREATE INDEX ... USING HYPO, and must never be built as real indexes.
+func checkIndexOptionNotHypo(indexOption *ast.IndexOption) error {
+	if indexOption != nil && indexOption.Tp == model.IndexTypeHypo {
+		return errUnsupportedIndexType.GenWithStack("HYPO index can only be created by CREATE INDEX")
+	}
 	return nil
 }
 
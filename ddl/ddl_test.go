@@ -45,6 +45,13 @@ type DDLForTest interface {
 	SetHook(h Callback)
 	// SetInterceptor sets the interceptor.
 	SetInterceptor(h Interceptor)
+	// ManageIntervalPartitions adds and drops the partitions of the INTERVAL partitioned tables as if it is `now`.
+	ManageIntervalPartitions(now time.Time)
+}
+
+// ManageIntervalPartitions implements DDLForTest.ManageIntervalPartitions interface.
+func (d *ddl) ManageIntervalPartitions(now time.Time) {
+	d.manageIntervalPartitions(now)
 }
 
 // SetInterceptor implements DDL.SetInterceptor interface.
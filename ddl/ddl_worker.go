@@ -15,6 +15,7 @@
 package ddl
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strconv"
@@ -79,10 +80,12 @@ const (
 )
 
 // worker is used for handling DDL jobs.
-// Now we have two kinds of workers.
+// Now we have two kinds of workers, and each worker handles the jobs in one job queue.
 type worker struct {
 	id              int32
 	tp              workerType
+	queueIdx        int
+	jobListKey      meta.JobListKeyType
 	addingDDLJobKey string
 	ddlJobCh        chan struct{}
 	ctx             context.Context
@@ -92,6 +95,8 @@ type worker struct {
 	reorgCtx        *reorgCtx    // reorgCtx is used for reorganization.
 	delRangeManager delRangeManager
 	logCtx          context.Context
+	// tryQuit is only set for the workers of the extra job queues, it returns true if the worker can quit.
+	tryQuit func() bool
 
 	ddlJobCache
 }
@@ -105,10 +110,11 @@ type ddlJobCache struct {
 	cacheDigest        *parser.Digest
 }
 
-func newWorker(ctx context.Context, tp workerType, sessPool *sessionPool, delRangeMgr delRangeManager) *worker {
+func newWorker(ctx context.Context, tp workerType, queueIdx int, sessPool *sessionPool, delRangeMgr delRangeManager) *worker {
 	worker := &worker{
 		id:       atomic.AddInt32(&ddlWorkerID, 1),
 		tp:       tp,
+		queueIdx: queueIdx,
 		ddlJobCh: make(chan struct{}, 1),
 		ctx:      ctx,
 		ddlJobCache: ddlJobCache{
@@ -120,9 +126,13 @@ func newWorker(ctx context.Context, tp workerType, sessPool *sessionPool, delRan
 		reorgCtx:        &reorgCtx{notifyCancelReorgJob: 0},
 		sessPool:        sessPool,
 		delRangeManager: delRangeMgr,
+		jobListKey:      meta.GetJobListKey(queueIdx, tp == addIdxWorker),
 	}
 
-	worker.addingDDLJobKey = addingDDLJobPrefix + worker.typeStr()
+	// The workers of the extra job queues are notified by the owner, see startJobQueueWorkers.
+	if queueIdx == 0 {
+		worker.addingDDLJobKey = addingDDLJobPrefix + worker.typeStr()
+	}
 	worker.logCtx = logutil.WithKeyValue(context.Background(), "worker", worker.String())
 	return worker
 }
@@ -141,6 +151,9 @@ func (w *worker) typeStr() string {
 }
 
 func (w *worker) String() string {
+	if w.queueIdx > 0 {
+		return fmt.Sprintf("worker %d, tp %s, queue %d", w.id, w.typeStr(), w.queueIdx)
+	}
 	return fmt.Sprintf("worker %d, tp %s", w.id, w.typeStr())
 }
 
@@ -169,7 +182,7 @@ func (w *worker) start(d *ddlCtx) {
 	ticker := time.NewTicker(checkTime)
 	defer ticker.Stop()
 	var notifyDDLJobByEtcdCh clientv3.WatchChan
-	if d.etcdCli != nil {
+	if d.etcdCli != nil && w.addingDDLJobKey != "" {
 		notifyDDLJobByEtcdCh = d.etcdCli.Watch(context.Background(), w.addingDDLJobKey)
 	}
 
@@ -200,6 +213,10 @@ func (w *worker) start(d *ddlCtx) {
 		if err != nil {
 			logutil.Logger(w.logCtx).Warn("[ddl] handle DDL job failed", zap.Error(err))
 		}
+		if w.tryQuit != nil && w.tryQuit() {
+			logutil.Logger(w.logCtx).Info("[ddl] DDL worker quits since its job queue is empty")
+			return
+		}
 	}
 }
 
@@ -226,33 +243,63 @@ func asyncNotify(ch chan struct{}) {
 
 // buildJobDependence sets the curjob's dependency-ID.
 // The dependency-job's ID must less than the current job's ID, and we need the largest one in the list.
-func buildJobDependence(t *meta.Meta, curJob *model.Job) error {
-	// Jobs in the same queue are ordered. If we want to find a job's dependency-job, we need to look for
-	// it from the other queue. So if the job is "ActionAddIndex" job, we need find its dependency-job from DefaultJobList.
-	jobListKey := meta.DefaultJobListKey
-	if !admin.MayNeedBackfill(curJob.Type) {
-		jobListKey = meta.AddIndexJobListKey
-	}
-	jobs, err := t.GetAllDDLJobsInQueue(jobListKey)
+// The current job may depend on the jobs in several queues, the smaller ones are found by isDependencyJobDone
+// after the dependency-job is finished.
+func buildJobDependence(t *meta.Meta, curJob *model.Job, curJobListKey meta.JobListKeyType) error {
+	dependencyID, err := findDependencyJob(t, curJob, curJobListKey)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	curJob.DependencyID = dependencyID
+	return nil
+}
 
-	for _, job := range jobs {
-		if curJob.ID < job.ID {
+// findDependencyJob returns the largest ID of the jobs that curJob depends on, it returns noneDependencyJob
+// if there isn't such a job.
+func findDependencyJob(t *meta.Meta, curJob *model.Job, curJobListKey meta.JobListKeyType) (int64, error) {
+	dependencyID := int64(noneDependencyJob)
+	// Jobs in the same queue are ordered. If we want to find a job's dependency-job, we need to look for
+	// it from the other queues.
+	for _, jobListKey := range meta.AllJobListKeys(variable.MaxDDLJobQueueCount) {
+		if bytes.Equal(jobListKey, curJobListKey) {
 			continue
 		}
-		isDependent, err := curJob.IsDependentOn(job)
+		// The jobs are got in reverse order, the first dependent one is the largest one in the queue.
+		jobs, err := t.GetAllDDLJobsInQueue(jobListKey)
 		if err != nil {
-			return errors.Trace(err)
+			return noneDependencyJob, errors.Trace(err)
 		}
-		if isDependent {
-			logutil.BgLogger().Info("[ddl] current DDL job depends on other job", zap.String("currentJob", curJob.String()), zap.String("dependentJob", job.String()))
-			curJob.DependencyID = job.ID
-			break
+		for _, job := range jobs {
+			if curJob.ID < job.ID {
+				continue
+			}
+			if job.ID <= dependencyID {
+				break
+			}
+			isDependent, err := curJob.IsDependentOn(job)
+			if err != nil {
+				return noneDependencyJob, errors.Trace(err)
+			}
+			if isDependent {
+				dependencyID = job.ID
+				break
+			}
 		}
 	}
-	return nil
+	return dependencyID, nil
+}
+
+// jobQueueIdx returns the index of the job queue that the job is put in.
+// The jobs of the same table are always put in the same queue if queueCnt isn't changed.
+func jobQueueIdx(job *model.Job, queueCnt int) int {
+	if queueCnt <= 1 {
+		return 0
+	}
+	id := job.TableID
+	if id == 0 {
+		id = job.SchemaID
+	}
+	return int(id % int64(queueCnt))
 }
 
 func (d *ddl) limitDDLJobs() {
@@ -291,12 +338,12 @@ func (d *ddl) addBatchDDLJobs(tasks []*limitJobTask) {
 			job.Version = currentVersion
 			job.StartTS = txn.StartTS()
 			job.ID = ids[i]
-			if err = buildJobDependence(t, job); err != nil {
+			jobListKey := meta.GetJobListKey(jobQueueIdx(job, task.queueCnt), admin.MayNeedBackfill(job.Type))
+			if err = buildJobDependence(t, job, jobListKey); err != nil {
 				return errors.Trace(err)
 			}
-			jobListKey := meta.DefaultJobListKey
-			if admin.MayNeedBackfill(job.Type) {
-				jobListKey = meta.AddIndexJobListKey
+			if job.DependencyID != noneDependencyJob {
+				logutil.BgLogger().Info("[ddl] current DDL job depends on other job", zap.String("currentJob", job.String()), zap.Int64("dependentJobID", job.DependencyID))
 			}
 			if err = t.EnQueueDDLJob(job, jobListKey); err != nil {
 				return errors.Trace(err)
@@ -402,13 +449,22 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 		switch job.Type {
 		case model.ActionAddIndex, model.ActionAddPrimaryKey:
 			if job.State != model.JobStateRollbackDone {
+				// The temporary index of the index added by ingesting isn't used any more.
+				if job.ReorgMeta != nil && job.ReorgMeta.ReorgTp == model.ReorgTypeIngest {
+					err = w.deleteRange(w.ddlJobCtx, job)
+				}
 				break
 			}
 
 			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
 			err = w.deleteRange(w.ddlJobCtx, job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes,
+			model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning,
+			model.ActionExchangeTablePartition:
+			err = w.deleteRange(w.ddlJobCtx, job)
+		case model.ActionMultiSchemaChange:
+			// The ranges are decided by the states of the sub-jobs.
 			err = w.deleteRange(w.ddlJobCtx, job)
 		}
 	}
@@ -461,7 +517,7 @@ func finishRecoverTable(w *worker, job *model.Job) error {
 	return nil
 }
 
-func isDependencyJobDone(t *meta.Meta, job *model.Job) (bool, error) {
+func isDependencyJobDone(t *meta.Meta, job *model.Job, jobListKey meta.JobListKeyType) (bool, error) {
 	if job.DependencyID == noneDependencyJob {
 		return true, nil
 	}
@@ -474,15 +530,16 @@ func isDependencyJobDone(t *meta.Meta, job *model.Job) (bool, error) {
 		return false, nil
 	}
 	logutil.BgLogger().Info("[ddl] current DDL job dependent job is finished", zap.String("currentJob", job.String()), zap.Int64("dependentJobID", job.DependencyID))
-	job.DependencyID = noneDependencyJob
-	return true, nil
-}
-
-func newMetaWithQueueTp(txn kv.Transaction, tp workerType) *meta.Meta {
-	if tp == addIdxWorker {
-		return meta.NewMeta(txn, meta.AddIndexJobListKey)
+	// The job may also depend on the smaller jobs in the other queues, it waits for them one by one.
+	job.DependencyID, err = findDependencyJob(t, job, jobListKey)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if job.DependencyID == noneDependencyJob {
+		return true, nil
 	}
-	return meta.NewMeta(txn)
+	logutil.BgLogger().Info("[ddl] current DDL job depends on other job", zap.String("currentJob", job.String()), zap.Int64("dependentJobID", job.DependencyID))
+	return false, errors.Trace(t.UpdateDDLJob(0, job, false, jobListKey))
 }
 
 func (w *worker) setDDLLabelForTopSQL(job *model.Job) {
@@ -520,7 +577,7 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 			}
 
 			var err error
-			t := newMetaWithQueueTp(txn, w.tp)
+			t := meta.NewMeta(txn, w.jobListKey)
 			// We become the owner. Get the first job and run it.
 			job, err = w.getFirstDDLJob(t)
 			if job == nil || err != nil {
@@ -533,10 +590,16 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 			}
 
 			w.setDDLLabelForTopSQL(job)
-			if isDone, err1 := isDependencyJobDone(t, job); err1 != nil || !isDone {
+			if isDone, err1 := isDependencyJobDone(t, job, w.jobListKey); err1 != nil || !isDone {
 				return errors.Trace(err1)
 			}
 
+			// Handle the pausing request before waiting for the schema to be synced, the pausing job returns
+			// from the loop below, so it would never be handled if it's paused while the reorganization runs.
+			if job.IsPausing() || job.IsPaused() {
+				return errors.Trace(w.pauseDDLJob(t, job))
+			}
+
 			if once {
 				w.waitSchemaSynced(d, job, waitTime)
 				once = false
@@ -596,6 +659,9 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 		} else if job == nil {
 			// No job now, return and retry getting later.
 			return nil
+		} else if job.IsPausing() || job.IsPaused() {
+			// The paused job blocks the queue until it's resumed, return and check it again later.
+			return nil
 		}
 		w.waitDependencyJobFinished(job, &waitDependencyJobCnt)
 
@@ -623,6 +689,25 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 	}
 }
 
+// pauseDDLJob turns the pausing job into paused. If the job is running a reorganization,
+// the backfilling goroutine is notified to quit like cancelling, and the state is changed
+// without waiting for it. The goroutine saves the processed handle to the reorg handle, and
+// its errPausedDDLJob is pulled out by runReorgJob after the job is resumed.
+func (w *worker) pauseDDLJob(t *meta.Meta, job *model.Job) error {
+	if job.IsPaused() {
+		return nil
+	}
+	if w.reorgCtx.doneCh != nil {
+		w.reorgCtx.notifyReorgPause()
+		rowCount, _, _ := w.reorgCtx.getRowCountAndKey()
+		job.SetRowCount(rowCount)
+	}
+	job.State = model.JobStatePaused
+	logutil.Logger(w.logCtx).Info("[ddl] pause DDL job", zap.String("job", job.String()))
+	// The job args aren't decoded here, so don't overwrite the raw args.
+	return errors.Trace(t.UpdateDDLJob(0, job, false))
+}
+
 func skipWriteBinlog(job *model.Job) bool {
 	switch job.Type {
 	// ActionUpdateTiFlashReplicaStatus is a TiDB internal DDL,
@@ -778,11 +863,13 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	case model.ActionDropTablePartition:
 		ver, err = w.onDropTablePartition(d, t, job)
 	case model.ActionTruncateTablePartition:
-		ver, err = onTruncateTablePartition(d, t, job)
+		ver, err = w.onTruncateTablePartition(d, t, job)
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
 	case model.ActionAddColumn:
-		ver, err = onAddColumn(d, t, job)
+		ver, err = w.onAddColumn(d, t, job)
 	case model.ActionAddColumns:
 		ver, err = onAddColumns(d, t, job)
 	case model.ActionDropColumn:
@@ -804,9 +891,15 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	case model.ActionRenameIndex:
 		ver, err = onRenameIndex(t, job)
 	case model.ActionAddForeignKey:
-		ver, err = onCreateForeignKey(t, job)
+		ver, err = w.onCreateForeignKey(t, job)
 	case model.ActionDropForeignKey:
 		ver, err = onDropForeignKey(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = w.onAddCheckConstraint(t, job)
+	case model.ActionDropCheckConstraint:
+		ver, err = onDropCheckConstraint(t, job)
+	case model.ActionAlterCheckConstraint:
+		ver, err = w.onAlterCheckConstraint(t, job)
 	case model.ActionTruncateTable:
 		ver, err = onTruncateTable(d, t, job)
 	case model.ActionRebaseAutoID:
@@ -861,6 +954,12 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterCacheTable(t, job)
 	case model.ActionAlterNoCacheTable:
 		ver, err = onAlterNoCacheTable(t, job)
+	case model.ActionMultiSchemaChange:
+		ver, err = onMultiSchemaChange(w, d, t, job)
+	case model.ActionAlterTTLInfo:
+		ver, err = onTTLInfoChange(t, job)
+	case model.ActionAlterTTLRemove:
+		ver, err = onTTLInfoRemove(t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -1074,6 +1173,10 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 			return 0, errors.Trace(err)
 		}
 		diff.OldTableID = job.TableID
+		if job.SchemaState == model.StateDeleteReorganization {
+			// The tables were exchanged in the previous state, the non-partitioned table is reloaded with its new ID.
+			diff.OldTableID = diff.TableID
+		}
 		affects := make([]*model.AffectedOption, 1)
 		affects[0] = &model.AffectedOption{
 			SchemaID:   ptSchemaID,
@@ -1088,7 +1191,8 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 			newIDs := job.CtxVars[1].([]int64)
 			diff.AffectedOpts = buildPlacementAffects(oldIDs, newIDs)
 		}
-	case model.ActionDropTablePartition, model.ActionRecoverTable, model.ActionDropTable:
+	case model.ActionDropTablePartition, model.ActionRecoverTable, model.ActionDropTable, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		// affects are used to update placement rule cache
 		diff.TableID = job.TableID
 		if len(job.CtxVars) > 0 {
@@ -1476,7 +1476,7 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	job4 := &model.Job{ID: 4, TableID: 1, Type: model.ActionAddIndex}
 	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
 		t := meta.NewMeta(txn)
-		err := buildJobDependence(t, job4)
+		err := buildJobDependence(t, job4, meta.AddIndexJobListKey)
 		c.Assert(err, IsNil)
 		c.Assert(job4.DependencyID, Equals, int64(2))
 		return nil
@@ -1485,7 +1485,7 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	job5 := &model.Job{ID: 5, TableID: 2, Type: model.ActionAddIndex}
 	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
 		t := meta.NewMeta(txn)
-		err := buildJobDependence(t, job5)
+		err := buildJobDependence(t, job5, meta.AddIndexJobListKey)
 		c.Assert(err, IsNil)
 		c.Assert(job5.DependencyID, Equals, int64(3))
 		return nil
@@ -1494,7 +1494,7 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	job8 := &model.Job{ID: 8, TableID: 3, Type: model.ActionAddIndex}
 	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
 		t := meta.NewMeta(txn)
-		err := buildJobDependence(t, job8)
+		err := buildJobDependence(t, job8, meta.AddIndexJobListKey)
 		c.Assert(err, IsNil)
 		c.Assert(job8.DependencyID, Equals, int64(0))
 		return nil
@@ -1503,7 +1503,7 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	job10 := &model.Job{ID: 10, SchemaID: 111, TableID: 3, Type: model.ActionAddIndex}
 	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
 		t := meta.NewMeta(txn)
-		err := buildJobDependence(t, job10)
+		err := buildJobDependence(t, job10, meta.AddIndexJobListKey)
 		c.Assert(err, IsNil)
 		c.Assert(job10.DependencyID, Equals, int64(9))
 		return nil
@@ -1512,7 +1512,7 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	job12 := &model.Job{ID: 12, SchemaID: 112, TableID: 2, Type: model.ActionAddIndex}
 	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
 		t := meta.NewMeta(txn)
-		err := buildJobDependence(t, job12)
+		err := buildJobDependence(t, job12, meta.AddIndexJobListKey)
 		c.Assert(err, IsNil)
 		c.Assert(job12.DependencyID, Equals, int64(11))
 		return nil
@@ -1520,8 +1520,58 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testDDLSuite) TestJobDependenceInSeveralQueues(c *C) {
+	store := testCreateStore(c, "test_job_dependence_in_several_queues")
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+	// The jobs of two tables in the schema are put in two queues.
+	queue1, queue2 := meta.GetJobListKey(1, false), meta.GetJobListKey(2, false)
+	job1 := &model.Job{ID: 1, SchemaID: 111, TableID: 1, Type: model.ActionAddColumn}
+	job2 := &model.Job{ID: 2, SchemaID: 111, TableID: 2, Type: model.ActionModifyColumn}
+	job3 := &model.Job{ID: 3, SchemaID: 111, Type: model.ActionDropSchema}
+	err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		c.Assert(t.EnQueueDDLJob(job1, queue1), IsNil)
+		c.Assert(t.EnQueueDDLJob(job2, queue2), IsNil)
+		c.Assert(buildJobDependence(t, job3, meta.DefaultJobListKey), IsNil)
+		c.Assert(job3.DependencyID, Equals, int64(2))
+		return t.EnQueueDDLJob(job3, meta.DefaultJobListKey)
+	})
+	c.Assert(err, IsNil)
+
+	checkDependency := func(finished *model.Job, finishedQueue meta.JobListKeyType, expectedDone bool, expectedID int64) {
+		err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+			t := meta.NewMeta(txn, finishedQueue)
+			_, err := t.DeQueueDDLJob()
+			c.Assert(err, IsNil)
+			c.Assert(t.AddHistoryDDLJob(finished, true), IsNil)
+
+			t = meta.NewMeta(txn)
+			job, err := t.GetDDLJobByIdx(0)
+			c.Assert(err, IsNil)
+			isDone, err := isDependencyJobDone(t, job, meta.DefaultJobListKey)
+			c.Assert(err, IsNil)
+			c.Assert(isDone, Equals, expectedDone)
+			c.Assert(job.DependencyID, Equals, expectedID)
+			if !isDone {
+				// The new dependency-job is saved.
+				job, err = t.GetDDLJobByIdx(0)
+				c.Assert(err, IsNil)
+				c.Assert(job.DependencyID, Equals, expectedID)
+			}
+			return nil
+		})
+		c.Assert(err, IsNil)
+	}
+	// The job still depends on the smaller job in the other queue after the largest one is finished.
+	checkDependency(job2, queue2, false, 1)
+	checkDependency(job1, queue1, true, noneDependencyJob)
+}
+
 func addDDLJob(c *C, d *ddl, job *model.Job) {
-	task := &limitJobTask{job, make(chan error)}
+	task := &limitJobTask{job, make(chan error), 1}
 	d.limitJobCh <- task
 	err := <-task.err
 	c.Assert(err, IsNil)
@@ -297,7 +297,8 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 		startKey = tablecodec.EncodeTablePrefix(tableID)
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		return doInsert(ctx, s, job.ID, tableID, startKey, endKey, now)
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
 			return errors.Trace(err)
@@ -309,26 +310,86 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 				return errors.Trace(err)
 			}
 		}
+	case model.ActionExchangeTablePartition:
+		// The partition ID and the global index IDs are only appended when the partitioned table has global indexes,
+		// the entries of the exchanged in table in these indexes are not used by the partition.
+		var (
+			ntID, ptSchemaID, ptID int64
+			partName               string
+			withValidation         bool
+			partitionID            int64
+			indexIDs               []int64
+		)
+		if err := job.DecodeArgs(&ntID, &ptSchemaID, &ptID, &partName, &withValidation, &partitionID, &indexIDs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, indexID := range indexIDs {
+			startKey := tablecodec.EncodeTableIndexPrefix(partitionID, indexID)
+			endKey := tablecodec.EncodeTableIndexPrefix(partitionID, indexID+1)
+			if err := doInsert(ctx, s, job.ID, indexID, startKey, endKey, now); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	case model.ActionMultiSchemaChange:
+		for _, sub := range job.MultiSchemaInfo.SubJobs {
+			if !subJobNeedDeleteRange(sub) {
+				continue
+			}
+			if err := insertJobIntoDeleteRangeTable(ctx, sctx, sub.ToProxyJob(job)); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	// ActionAddIndex, ActionAddPrimaryKey needs do it, because it needs to be rolled back when it's canceled.
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		tableID := job.TableID
 		var indexID int64
 		var partitionIDs []int64
+		if job.State != model.JobStateRollbackDone {
+			// The index is added by ingesting, only its temporary index needs to be deleted.
+			tblInfo := job.BinlogInfo.TableInfo
+			var indexName model.CIStr
+			if err := job.DecodeArgs(new(bool), &indexName); err != nil {
+				return errors.Trace(err)
+			}
+			idxInfo := tblInfo.FindIndexByName(indexName.L)
+			if idxInfo == nil {
+				return nil
+			}
+			tempIdxID := tablecodec.TempIndexPrefix | idxInfo.ID
+			physicalTableIDs := getPartitionIDs(tblInfo)
+			if len(physicalTableIDs) == 0 {
+				physicalTableIDs = []int64{tableID}
+			}
+			for _, pid := range physicalTableIDs {
+				startKey := tablecodec.EncodeTableIndexPrefix(pid, tempIdxID)
+				endKey := tablecodec.EncodeTableIndexPrefix(pid, tempIdxID+1)
+				if err := doInsert(ctx, s, job.ID, tempIdxID, startKey, endKey, now); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
+		}
 		if err := job.DecodeArgs(&indexID, &partitionIDs); err != nil {
 			return errors.Trace(err)
 		}
-		if len(partitionIDs) > 0 {
-			for _, pid := range partitionIDs {
-				startKey := tablecodec.EncodeTableIndexPrefix(pid, indexID)
-				endKey := tablecodec.EncodeTableIndexPrefix(pid, indexID+1)
-				if err := doInsert(ctx, s, job.ID, indexID, startKey, endKey, now); err != nil {
+		withTempIdx := job.ReorgMeta != nil && job.ReorgMeta.ReorgTp == model.ReorgTypeIngest
+		if len(partitionIDs) == 0 {
+			partitionIDs = []int64{tableID}
+		}
+		for _, pid := range partitionIDs {
+			startKey := tablecodec.EncodeTableIndexPrefix(pid, indexID)
+			endKey := tablecodec.EncodeTableIndexPrefix(pid, indexID+1)
+			if err := doInsert(ctx, s, job.ID, indexID, startKey, endKey, now); err != nil {
+				return errors.Trace(err)
+			}
+			if withTempIdx {
+				tempIdxID := tablecodec.TempIndexPrefix | indexID
+				startKey = tablecodec.EncodeTableIndexPrefix(pid, tempIdxID)
+				endKey = tablecodec.EncodeTableIndexPrefix(pid, tempIdxID+1)
+				if err := doInsert(ctx, s, job.ID, tempIdxID, startKey, endKey, now); err != nil {
 					return errors.Trace(err)
 				}
 			}
-		} else {
-			startKey := tablecodec.EncodeTableIndexPrefix(tableID, indexID)
-			endKey := tablecodec.EncodeTableIndexPrefix(tableID, indexID+1)
-			return doInsert(ctx, s, job.ID, indexID, startKey, endKey, now)
 		}
 	case model.ActionDropIndex, model.ActionDropPrimaryKey:
 		tableID := job.TableID
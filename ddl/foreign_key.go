@@ -15,33 +15,90 @@
 package ddl
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
 )
 
-func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+func (w *worker) onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
 
-	var fkInfo model.FKInfo
-	err = job.DecodeArgs(&fkInfo)
+	var (
+		fkInfoInJob             model.FKInfo
+		refSchemaID, refTableID int64
+		fkCheck                 bool
+	)
+	err = job.DecodeArgs(&fkInfoInJob, &refSchemaID, &refTableID, &fkCheck)
 	if err != nil {
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
-	fkInfo.ID = allocateIndexID(tblInfo)
-	tblInfo.ForeignKeys = append(tblInfo.ForeignKeys, &fkInfo)
+
+	if job.IsRollingback() {
+		return rollbackAddForeignKey(t, job, tblInfo, fkInfoInJob.Name)
+	}
+
+	fkInfo := findFKInfo(tblInfo, fkInfoInJob.Name)
+	if job.SchemaState == model.StateNone {
+		if fkInfo != nil {
+			job.State = model.JobStateCancelled
+			return ver, ErrFkDupName.GenWithStackByArgs(fkInfoInJob.Name.O)
+		}
+		fkInfo = &fkInfoInJob
+		fkInfo.ID = allocateIndexID(tblInfo)
+		fkInfo.State = model.StateNone
+		tblInfo.ForeignKeys = append(tblInfo.ForeignKeys, fkInfo)
+	} else if fkInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrForeignKeyNotExists.GenWithStackByArgs(fkInfoInJob.Name)
+	}
 
 	originalState := fkInfo.State
 	switch fkInfo.State {
 	case model.StateNone:
-		// We just support record the foreign key, so we just make it public.
-		// none -> public
+		// none -> write only
+		fkInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != fkInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+		return ver, nil
+	case model.StateWriteOnly:
+		// The foreign key of a non-revertible sub-job has been verified, it only waits for the other sub-jobs.
+		if job.MultiSchemaInfo == nil || job.MultiSchemaInfo.Revertible {
+			// All the new written rows are checked now, verify the existing rows unless foreign_key_checks is off.
+			if fkCheck {
+				dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+				if err != nil {
+					return ver, errors.Trace(err)
+				}
+				if err = w.verifyRemainRecordsForForeignKey(dbInfo, tblInfo, fkInfo); err != nil {
+					if ErrNoReferencedRow2.Equal(err) {
+						job.State = model.JobStateRollingback
+					}
+					return ver, errors.Trace(err)
+				}
+			}
+			if checkAndMarkNonRevertible(job) {
+				return ver, nil
+			}
+		}
+		// write only -> public
 		fkInfo.State = model.StatePublic
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != fkInfo.State)
 		if err != nil {
@@ -51,10 +108,81 @@ func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
 		return ver, nil
 	default:
-		return ver, ErrInvalidDDLState.GenWithStack("foreign key", fkInfo.State)
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("foreign key", fkInfo.State)
 	}
 }
 
+func findFKInfo(tblInfo *model.TableInfo, fkName model.CIStr) *model.FKInfo {
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.Name.L == fkName.L {
+			return fk
+		}
+	}
+	return nil
+}
+
+// verifyRemainRecordsForForeignKey checks whether the existing rows of the child table have the parent rows.
+// The rows with null values in the foreign key columns aren't checked, the same as MySQL.
+func (w *worker) verifyRemainRecordsForForeignKey(dbInfo *model.DBInfo, tblInfo *model.TableInfo, fkInfo *model.FKInfo) error {
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	refSchema := fkInfo.RefSchema
+	if refSchema.L == "" {
+		refSchema = dbInfo.Name
+	}
+	var buf strings.Builder
+	args := make([]interface{}, 0, 4+3*len(fkInfo.Cols))
+	buf.WriteString("select 1 from %n.%n as child where ")
+	args = append(args, dbInfo.Name.L, tblInfo.Name.L)
+	for _, col := range fkInfo.Cols {
+		buf.WriteString("child.%n is not null and ")
+		args = append(args, col.L)
+	}
+	buf.WriteString("not exists (select 1 from %n.%n as parent where ")
+	args = append(args, refSchema.L, fkInfo.RefTable.L)
+	for i, col := range fkInfo.Cols {
+		if i > 0 {
+			buf.WriteString(" and ")
+		}
+		buf.WriteString("parent.%n = child.%n")
+		args = append(args, fkInfo.RefCols[i].L, col.L)
+	}
+	buf.WriteString(") limit 1")
+	stmt, err := sctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(w.ddlJobCtx, true, buf.String(), args...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(w.ddlJobCtx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) != 0 {
+		return ErrNoReferencedRow2.GenWithStackByArgs(FKDescription(dbInfo.Name, tblInfo.Name, fkInfo))
+	}
+	return nil
+}
+
+// rollbackAddForeignKey removes the foreign key which is violated by the existing rows or cancelled.
+func rollbackAddForeignKey(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, fkName model.CIStr) (ver int64, err error) {
+	nfks := tblInfo.ForeignKeys[:0]
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.Name.L != fkName.L {
+			nfks = append(nfks, fk)
+		}
+	}
+	tblInfo.ForeignKeys = nfks
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
 func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -111,3 +239,169 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 
 }
+
+// FindFKLookupIndex finds how the rows of the table are looked up by cols in the foreign key checks.
+// isHandle is true if the leading columns of the handle are cols, otherwise idx is the index whose leading
+// columns are cols. It returns false and nil if there's no such handle or index.
+func FindFKLookupIndex(tblInfo *model.TableInfo, cols []model.CIStr) (isHandle bool, idx *model.IndexInfo) {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if col := model.FindColumnInfo(tblInfo.Columns, cols[0].L); col != nil && mysql.HasPriKeyFlag(col.Flag) {
+			return true, nil
+		}
+	}
+	if tblInfo.IsCommonHandle && isFKLookupIndex(tables.FindPrimaryIndex(tblInfo), cols) {
+		return true, nil
+	}
+	candidates := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic || idx.Global || (idx.Primary && tblInfo.IsCommonHandle) {
+			continue
+		}
+		if isFKLookupIndex(idx, cols) {
+			candidates = append(candidates, idx)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	// Prefer the unique indexes and the ones with fewer columns.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Unique != candidates[j].Unique {
+			return candidates[i].Unique
+		}
+		return len(candidates[i].Columns) < len(candidates[j].Columns)
+	})
+	return false, candidates[0]
+}
+
+// isFKLookupIndex checks whether the leading columns of the index are cols with full length.
+func isFKLookupIndex(idx *model.IndexInfo, cols []model.CIStr) bool {
+	if idx == nil || len(idx.Columns) < len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		if idx.Columns[i].Name.L != col.L || idx.Columns[i].Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+func hasFKLookupIndex(tblInfo *model.TableInfo, cols []model.CIStr) bool {
+	isHandle, idx := FindFKLookupIndex(tblInfo, cols)
+	return isHandle || idx != nil
+}
+
+// buildFKIndexes adds an index for each foreign key of the new table whose columns can't be looked up by the
+// existing indexes, so the foreign key checks on the parent table never scan the whole child table.
+func buildFKIndexes(tbInfo *model.TableInfo) error {
+	for _, fk := range tbInfo.ForeignKeys {
+		if hasFKLookupIndex(tbInfo, fk.Cols) {
+			continue
+		}
+		idxInfo, err := buildIndexInfo(tbInfo, fkIndexName(tbInfo, fk.Name), fkIndexKeys(fk.Cols), model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		idxInfo.Tp = model.IndexTypeBtree
+		idxInfo.ID = allocateIndexID(tbInfo)
+		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
+	}
+	return nil
+}
+
+// fkIndexKeys returns the index parts of the index built for the foreign key columns.
+func fkIndexKeys(cols []model.CIStr) []*ast.IndexPartSpecification {
+	keys := make([]*ast.IndexPartSpecification, 0, len(cols))
+	for _, col := range cols {
+		keys = append(keys, &ast.IndexPartSpecification{Column: &ast.ColumnName{Name: col}, Length: types.UnspecifiedLength})
+	}
+	return keys
+}
+
+// fkIndexName names the index built for the foreign key after the foreign key, the same as MySQL.
+func fkIndexName(tbInfo *model.TableInfo, fkName model.CIStr) model.CIStr {
+	name := fkName
+	for i := 2; tbInfo.FindIndexByName(name.L) != nil; i++ {
+		name = model.NewCIStr(fmt.Sprintf("%s_%d", fkName.O, i))
+	}
+	return name
+}
+
+// checkFKParentIndexes checks that the referenced columns of the foreign keys can be looked up by the handle or
+// an index of the parent tables. The parent tables which don't exist yet are skipped.
+func checkFKParentIndexes(is infoschema.InfoSchema, schemaName model.CIStr, tbInfo *model.TableInfo, fks []*model.FKInfo) error {
+	for _, fk := range fks {
+		refSchema := fk.RefSchema
+		if refSchema.L == "" {
+			refSchema = schemaName
+		}
+		parent := tbInfo
+		if refSchema.L != schemaName.L || fk.RefTable.L != tbInfo.Name.L {
+			tbl, err := is.TableByName(refSchema, fk.RefTable)
+			if err != nil {
+				continue
+			}
+			parent = tbl.Meta()
+		}
+		if !hasFKLookupIndex(parent, fk.RefCols) {
+			return ErrFkNoIndexParent.GenWithStackByArgs(fk.Name.O, fk.RefTable.O)
+		}
+	}
+	return nil
+}
+
+// checkDropIndexOnForeignKey checks that the foreign keys of the table and the ones referring to the table
+// can still look up the rows by the handle or the remained indexes after the indexes are dropped.
+func checkDropIndexOnForeignKey(is infoschema.InfoSchema, schemaName model.CIStr, tblInfo *model.TableInfo, idxInfos ...*model.IndexInfo) error {
+	dropped := make(map[int64]struct{}, len(idxInfos))
+	for _, idx := range idxInfos {
+		dropped[idx.ID] = struct{}{}
+	}
+	remained := *tblInfo
+	remained.Indices = make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idx := range tblInfo.Indices {
+		if _, ok := dropped[idx.ID]; !ok {
+			remained.Indices = append(remained.Indices, idx)
+		}
+	}
+	check := func(cols []model.CIStr) error {
+		isHandle, idx := FindFKLookupIndex(tblInfo, cols)
+		if isHandle || idx == nil || hasFKLookupIndex(&remained, cols) {
+			return nil
+		}
+		return ErrDropIndexFk.GenWithStackByArgs(idx.Name.O)
+	}
+	for _, fk := range tblInfo.ForeignKeys {
+		if err := check(fk.Cols); err != nil {
+			return err
+		}
+	}
+	for _, referredFK := range is.GetTableReferredForeignKeys(schemaName.L, tblInfo.Name.L) {
+		if err := check(referredFK.Cols); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FKDescription returns the foreign key description in the error messages, e.g.
+// `test`.`child`, CONSTRAINT `fk` FOREIGN KEY (`a`) REFERENCES `parent` (`id`) ON DELETE CASCADE
+func FKDescription(childSchema, childTable model.CIStr, fk *model.FKInfo) string {
+	quote := func(names []model.CIStr) string {
+		quoted := make([]string, 0, len(names))
+		for _, name := range names {
+			quoted = append(quoted, "`"+name.O+"`")
+		}
+		return strings.Join(quoted, ", ")
+	}
+	desc := fmt.Sprintf("`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+		childSchema.O, childTable.O, fk.Name.O, quote(fk.Cols), fk.RefTable.O, quote(fk.RefCols))
+	if onDelete := ast.ReferOptionType(fk.OnDelete); onDelete != ast.ReferOptionNoOption {
+		desc += " ON DELETE " + onDelete.String()
+	}
+	if onUpdate := ast.ReferOptionType(fk.OnUpdate); onUpdate != ast.ReferOptionNoOption {
+		desc += " ON UPDATE " + onUpdate.String()
+	}
+	return desc
+}
@@ -0,0 +1,263 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// countGlobalIndexEntries counts the entries of the global index which point to the partition.
+func countGlobalIndexEntries(t *testing.T, store kv.Storage, tblInfo *model.TableInfo, idxName string, pid int64) int {
+	idxInfo := tblInfo.FindIndexByName(idxName)
+	require.NotNil(t, idxInfo)
+	cnt := 0
+	err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		prefix := tablecodec.EncodeTableIndexPrefix(tblInfo.ID, idxInfo.ID)
+		it, err := txn.Iter(prefix, prefix.PrefixNext())
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+		for it.Valid() && it.Key().HasPrefix(prefix) {
+			id, ok, err := tablecodec.DecodePartitionIDInIndexValue(it.Value())
+			require.NoError(t, err)
+			require.True(t, ok)
+			if id == pid {
+				cnt++
+			}
+			if err = it.Next(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return cnt
+}
+
+func TestCreateGlobalIndex(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery("select @@tidb_enable_global_index").Check(testkit.Rows("0"))
+	tk.MustGetErrCode("create table t (a int, b int, unique key ub(b)) partition by hash(a) partitions 3", errno.ErrUniqueKeyNeedAllFieldsInPf)
+
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec("create table t (a int, b int, c int, unique key ub(b), unique key uab(a, b)) partition by hash(a) partitions 3")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.True(t, tbl.Meta().FindIndexByName("ub").Global)
+	require.False(t, tbl.Meta().FindIndexByName("uab").Global)
+	tk.MustExec("alter table t add unique index uc(c)")
+	tbl, err = dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.True(t, tbl.Meta().FindIndexByName("uc").Global)
+	// The primary key is used as the handle, it can't be global.
+	tk.MustGetErrCode("create table t1 (a int, b int, primary key(b)) partition by hash(a) partitions 3", errno.ErrUniqueKeyNeedAllFieldsInPf)
+}
+
+func TestGlobalIndexDML(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec(`create table t (a int, b int, c int, unique key ub(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 1, 1), (11, 11, 11), (21, 21, 21)")
+
+	tk.MustGetErrMsg("insert into t values (2, 11, 2)", "[kv:1062]Duplicate entry '11' for key 'ub'")
+	tk.MustExec("insert ignore into t values (2, 11, 2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1062 Duplicate entry '11' for key 'ub'"))
+	tk.MustExec("insert into t values (2, 11, 2) on duplicate key update c = 12")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 1", "11 11 12", "21 21 21"))
+	// The conflicted row in the other partition is replaced.
+	tk.MustExec("replace into t values (2, 21, 2)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 1", "2 21 2", "11 11 12"))
+	tk.MustExec("update t set a = 25 where b = 21")
+	tk.MustQuery("select * from t partition (p2)").Check(testkit.Rows("25 21 2"))
+	tk.MustExec("delete from t where b = 11")
+	tk.MustExec("insert into t values (15, 11, 15)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 1", "15 11 15", "25 21 2"))
+	tk.MustExec("admin check table t")
+}
+
+func TestGlobalIndexRead(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec(`create table t (a int, b int, c int, unique key ub(b), key kc(c)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 1, 1), (11, 11, 11), (21, 21, 21)")
+
+	for _, mode := range []string{"static", "dynamic"} {
+		tk.MustExec("set @@tidb_partition_prune_mode = '" + mode + "'")
+		tk.MustQuery("select * from t use index(ub) where b > 0 order by b").Check(testkit.Rows("1 1 1", "11 11 11", "21 21 21"))
+		tk.MustQuery("select b from t use index(ub) where b > 0 order by b").Check(testkit.Rows("1", "11", "21"))
+		tk.MustQuery("select * from t use index(ub) where b = 11").Check(testkit.Rows("11 11 11"))
+		tk.MustQuery("select * from t use index(ub) where a < 20 and b > 1").Check(testkit.Rows("11 11 11"))
+		tk.MustQuery("select * from t partition (p0) where b > 0").Check(testkit.Rows("1 1 1"))
+		tk.MustQuery("select /*+ use_index_merge(t, ub, kc) */ * from t where b = 1 or c = 11 order by a").Check(testkit.Rows("1 1 1", "11 11 11"))
+	}
+}
+
+func TestTruncateAndDropPartitionWithGlobalIndex(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec(`create table t (a int, b int, c int, unique key ub(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (11, 11, 11), (12, 12, 12), (21, 21, 21)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	p0, p1 := tbl.Meta().Partition.Definitions[0].ID, tbl.Meta().Partition.Definitions[1].ID
+	require.Equal(t, 2, countGlobalIndexEntries(t, store, tbl.Meta(), "ub", p1))
+
+	tk.MustExec("alter table t truncate partition p1")
+	require.Equal(t, 0, countGlobalIndexEntries(t, store, tbl.Meta(), "ub", p1))
+	tk.MustQuery("select * from t use index(ub) where b > 0 order by b").Check(testkit.Rows("1 1 1", "2 2 2", "21 21 21"))
+	tk.MustExec("insert into t values (3, 11, 3)")
+	tk.MustQuery("select * from t use index(ub) where b = 11").Check(testkit.Rows("3 11 3"))
+
+	tk.MustExec("alter table t drop partition p0")
+	require.Equal(t, 0, countGlobalIndexEntries(t, store, tbl.Meta(), "ub", p0))
+	tk.MustQuery("select * from t use index(ub) where b > 0 order by b").Check(testkit.Rows("21 21 21"))
+	tk.MustExec("insert into t values (15, 1, 15), (16, 11, 16)")
+	tk.MustQuery("select * from t use index(ub) where b > 0 order by b").Check(testkit.Rows("15 1 15", "16 11 16", "21 21 21"))
+	tk.MustExec("admin check table t")
+}
+
+func TestExchangePartitionWithGlobalIndex(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec("set @@tidb_enable_exchange_partition = on")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec(`create table pt (a int, b int, c int, unique key ub(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("create table nt (a int, b int, c int, unique key ub(b))")
+	tk.MustExec("insert into pt values (1, 1, 1), (2, 2, 2), (11, 11, 11)")
+	tk.MustExec("insert into nt values (3, 3, 3), (4, 11, 4)")
+
+	// The records of the non-partitioned table are duplicated with the other partitions.
+	tk.MustGetErrMsg("alter table pt exchange partition p0 with table nt", "[kv:1062]Duplicate entry '11' for key 'ub'")
+	tk.MustExec("update nt set b = 4 where a = 4")
+
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("pt"))
+	require.NoError(t, err)
+	p0 := tbl.Meta().Partition.Definitions[0].ID
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	require.Equal(t, 0, countGlobalIndexEntries(t, store, tbl.Meta(), "ub", p0))
+	tk.MustQuery("select * from pt use index(ub) where b > 0 order by b").Check(testkit.Rows("3 3 3", "4 4 4", "11 11 11"))
+	tk.MustQuery("select * from nt use index(ub) where b > 0 order by b").Check(testkit.Rows("1 1 1", "2 2 2"))
+	tk.MustQuery("select * from nt use index(ub) where b = 2").Check(testkit.Rows("2 2 2"))
+	tk.MustExec("insert into pt values (5, 1, 5)")
+	tk.MustGetErrMsg("insert into pt values (6, 3, 6)", "[kv:1062]Duplicate entry '3' for key 'ub'")
+	tk.MustExec("admin check table pt")
+	tk.MustExec("admin check table nt")
+}
+
+func TestExchangePartitionWithGlobalIndexRollback(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk.MustExec("set @@tidb_enable_exchange_partition = on")
+	tk.MustExec(`create table pt (a int, b int, c int, unique key ub(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("create table nt (a int, b int, c int, unique key ub(b))")
+	tk.MustExec("insert into pt values (1, 1, 1), (2, 2, 2), (11, 11, 11)")
+	tk.MustExec("insert into nt values (3, 3, 3), (4, 4, 4)")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	var insertPtErr, insertNtErr error
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	hook := &ddl.TestDDLCallback{Do: dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionExchangeTablePartition || job.SchemaState != model.StateWriteOnly || insertPtErr != nil {
+			return
+		}
+		// The records written under the fence may be duplicated with the exchanged records.
+		_, insertPtErr = tk1.Exec("insert into pt values (12, 4, 12)")
+		_, insertNtErr = tk1.Exec("insert into nt values (5, 11, 5)")
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	// The duplicated record rolls back the job, and the fence is removed.
+	tk.MustExec("insert into nt values (6, 11, 6)")
+	tk.MustGetErrMsg("alter table pt exchange partition p0 with table nt", "[kv:1062]Duplicate entry '11' for key 'ub'")
+	require.EqualError(t, insertPtErr, "[table:8257]Table 'pt' can't be written while EXCHANGE PARTITION is in progress")
+	require.EqualError(t, insertNtErr, "[table:8257]Table 'nt' can't be written while EXCHANGE PARTITION is in progress")
+	tk.MustQuery("select * from pt use index(ub) where b > 0 order by b").Check(testkit.Rows("1 1 1", "2 2 2", "11 11 11"))
+	tk.MustQuery("select * from nt use index(ub) where b > 0 order by b").Check(testkit.Rows("3 3 3", "4 4 4", "6 11 6"))
+	tk.MustExec("update pt set c = 0 where a = 1")
+	tk.MustExec("update nt set b = 6 where a = 6")
+	tk.MustExec("admin check table pt")
+	tk.MustExec("admin check table nt")
+
+	// The job is cancelled under the fence.
+	cancelled := false
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionExchangeTablePartition || job.SchemaState != model.StateWriteOnly || cancelled {
+			return
+		}
+		cancelled = true
+		tk1.MustQuery(fmt.Sprintf("admin cancel ddl jobs %d", job.ID)).Check(testkit.Rows(fmt.Sprintf("%d successful", job.ID)))
+	}
+	tk.MustGetErrMsg("alter table pt exchange partition p0 with table nt", "[ddl:8214]Cancelled DDL job")
+	require.True(t, cancelled)
+	tk.MustExec("insert into pt values (13, 13, 13)")
+	tk.MustExec("insert into nt values (7, 7, 7)")
+	tk.MustQuery("select * from pt use index(ub) where b > 0 order by b").Check(testkit.Rows("1 1 0", "2 2 2", "11 11 11", "13 13 13"))
+
+	// The exchange succeeds after the job is rolled back.
+	hook.OnJobRunBeforeExported = nil
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	tk.MustQuery("select * from pt use index(ub) where b > 0 order by b").Check(testkit.Rows("3 3 3", "4 4 4", "6 6 6", "7 7 7", "11 11 11", "13 13 13"))
+	tk.MustExec("insert into pt values (8, 8, 8)")
+	tk.MustExec("insert into nt values (5, 5, 5)")
+	tk.MustExec("admin check table pt")
+	tk.MustExec("admin check table nt")
+}
@@ -240,6 +240,30 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 	return idxInfo, nil
 }
 
+// BuildHypoIndexInfo builds the info of a hypothetical index. The hypothetical index is only kept in the session,
+// so it can't be built on expressions, which needs hidden columns to be added to the table.
+func BuildHypoIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, keyType ast.IndexKeyType, indexPartSpecifications []*ast.IndexPartSpecification) (*model.IndexInfo, error) {
+	if keyType != ast.IndexKeyTypeNone && keyType != ast.IndexKeyTypeUnique {
+		return nil, errUnsupportedIndexType
+	}
+	for _, ip := range indexPartSpecifications {
+		if ip.Expr != nil {
+			return nil, errUnsupportedHypoExpressionIndex
+		}
+	}
+	if tblInfo.FindIndexByName(indexName.L) != nil {
+		return nil, ErrDupKeyName.GenWithStack("index already exist %s", indexName)
+	}
+	idxInfo, err := buildIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StatePublic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	idxInfo.Table = tblInfo.Name
+	idxInfo.Tp = model.IndexTypeHypo
+	idxInfo.Unique = keyType == ast.IndexKeyTypeUnique
+	return idxInfo, nil
+}
+
 func addIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
 	if indexInfo.Primary {
 		for _, col := range indexInfo.Columns {
@@ -308,6 +332,9 @@ func onRenameIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		return ver, errors.Trace(ErrOptOnCacheTable.GenWithStackByArgs("Rename Index"))
 	}
 
+	if checkAndMarkNonRevertible(job) {
+		return ver, nil
+	}
 	idx := tblInfo.FindIndexByName(from.L)
 	idx.Name = to
 	if ver, err = updateVersionAndTableInfo(t, job, tblInfo, true); err != nil {
@@ -332,6 +359,9 @@ func onAlterIndexVisibility(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	if err != nil || tblInfo == nil {
 		return ver, errors.Trace(err)
 	}
+	if checkAndMarkNonRevertible(job) {
+		return ver, nil
+	}
 	idx := tblInfo.FindIndexByName(from.L)
 	idx.Invisible = invisible
 	if ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true); err != nil {
@@ -467,6 +497,10 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
+		if err = checkIndexOptionNotHypo(indexOption); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
 		indexInfo, err = buildIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StateNone)
 		if err != nil {
 			job.State = model.JobStateCancelled
@@ -517,6 +551,10 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	case model.StateNone:
 		// none -> delete only
 		indexInfo.State = model.StateDeleteOnly
+		if job.ReorgMeta != nil && job.ReorgMeta.ReorgTp == model.ReorgTypeIngest {
+			// The DML writes the temporary index until the backfill is done.
+			indexInfo.BackfillState = model.BackfillStateRunning
+		}
 		updateHiddenColumns(tblInfo, indexInfo, model.StatePublic)
 		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != indexInfo.State)
 		if err != nil {
@@ -547,49 +585,26 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		if err != nil {
 			return ver, err
 		}
-		// Initialize SnapshotVer to 0 for later reorganization check.
+		// SnapshotVer stays 0 until the reorganization takes the snapshot to backfill. The rollback regards the job
+		// or sub-job in write-reorganization with SnapshotVer 0 as not started, and rolls it back without waiting
+		// for the reorg workers.
 		job.SnapshotVer = 0
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
 		// reorganization -> public
-		tbl, err := getTable(d.store, schemaID, tblInfo)
-		if err != nil {
-			return ver, errors.Trace(err)
-		}
-
-		elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onCreateIndex",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
-				}, false)
-			return w.addTableIndex(tbl, indexInfo, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// if timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
+		// The index of a non-revertible sub-job has been backfilled, it only waits for the other sub-jobs.
+		if job.MultiSchemaInfo == nil || job.MultiSchemaInfo.Revertible {
+			var done bool
+			done, ver, err = w.doReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo)
+			if !done {
+				return ver, err
 			}
-			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
-				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-				ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
-				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-					logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
-				}
+			if checkAndMarkNonRevertible(job) {
+				// Reset the snapshot version, so the index is rolled back as a not started one if the job is cancelled.
+				job.SnapshotVer = 0
+				return ver, nil
 			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
 
 		indexInfo.State = model.StatePublic
 		// Set column index flag.
@@ -612,6 +627,57 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	return ver, errors.Trace(err)
 }
 
+func (w *worker) doReorgWorkForCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job,
+	tblInfo *model.TableInfo, indexInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	if indexInfo.BackfillState != model.BackfillStateInapplicable {
+		return w.doReorgWorkWithTempIndex(d, t, job, tblInfo, indexInfo)
+	}
+	return w.runReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo, w.addTableIndex)
+}
+
+func (w *worker) runReorgWorkForCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, indexInfo *model.IndexInfo,
+	reorgFn func(t table.Table, idx *model.IndexInfo, reorgInfo *reorgInfo) error) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onCreateIndex",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
+			}, false)
+		return reorgFn(tbl, indexInfo, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	tblInfo, indexInfo, err := checkDropIndex(t, job)
 	if err != nil {
@@ -631,6 +697,9 @@ func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := indexInfo.State
 	switch indexInfo.State {
 	case model.StatePublic:
+		if checkAndMarkNonRevertible(job) {
+			return ver, nil
+		}
 		// public -> write only
 		indexInfo.State = model.StateWriteOnly
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
@@ -1089,7 +1158,12 @@ func (w *baseIndexWorker) getIndexRecord(idxInfo *model.IndexInfo, handle kv.Han
 			idxVal[j] = idxColumnVal
 			continue
 		}
-		idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		if col.State != model.StatePublic {
+			// The column is added by the same multi-schema change, so the rows written before it lack the column.
+			idxColumnVal, err = table.GetColOriginDefaultValue(w.sessCtx, col.ToInfo())
+		} else {
+			idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1357,6 +1431,27 @@ func (w *addIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskC
 }
 
 func (w *worker) addPhysicalTableIndex(t table.PhysicalTable, indexInfo *model.IndexInfo, reorgInfo *reorgInfo) error {
+	if indexInfo.BackfillState == model.BackfillStateRunning {
+		// The sorted index entries which aren't ingested are lost if the backfill is restarted, so the reorg handle
+		// is only moved after the entries are imported, e.g. when the job is paused. The backfill goes on from it,
+		// or from the start of the physical table if it isn't in the table.
+		start, end, err := getTableRange(reorgInfo.d, t, reorgInfo.SnapshotVer, reorgInfo.Job.Priority)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if reorgInfo.StartKey.Cmp(start) > 0 && reorgInfo.StartKey.Cmp(end) <= 0 {
+			start = reorgInfo.StartKey
+		}
+		reorgInfo.StartKey, reorgInfo.EndKey = start, end
+		logutil.BgLogger().Info("[ddl] start to add table index", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		if backend := reorgInfo.d.getIngestBackend(); backend != nil {
+			return w.ingestPhysicalTableIndex(backend, t, indexInfo, reorgInfo)
+		}
+		// Backfill the index by transactions, the DML is still merged from the temporary index later.
+		indexInfo = indexInfo.Clone()
+		indexInfo.BackfillState = model.BackfillStateInapplicable
+		return w.writePhysicalTableRecord(t, typeAddIndexWorker, indexInfo, nil, nil, reorgInfo)
+	}
 	logutil.BgLogger().Info("[ddl] start to add table index", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
 	return w.writePhysicalTableRecord(t, typeAddIndexWorker, indexInfo, nil, nil, reorgInfo)
 }
@@ -1530,12 +1625,36 @@ func (w *cleanUpIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (t
 		txn.SetDiskFullOpt(kvrpcpb.DiskFullOpt_AllowedOnAlmostFull)
 
 		n := len(w.indexes)
+		keys := make([]kv.Key, 0, len(idxRecords))
 		for i, idxRecord := range idxRecords {
-			taskCtx.scanCount++
 			// we fetch records row by row, so records will belong to
 			// index[0], index[1] ... index[n-1], index[0], index[1] ...
 			// respectively. So indexes[i%n] is the index of idxRecords[i].
-			err := w.indexes[i%n].Delete(w.sessCtx.GetSessionVars().StmtCtx, txn, idxRecord.vals, idxRecord.handle)
+			key, _, err := w.indexes[i%n].GenIndexKey(w.sessCtx.GetSessionVars().StmtCtx, idxRecord.vals, idxRecord.handle, nil)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			keys = append(keys, key)
+		}
+		// The entries may have been overwritten by the rows of other partitions, only the ones of this partition are removed.
+		values, err := txn.BatchGet(ctx, keys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for i, idxRecord := range idxRecords {
+			taskCtx.scanCount++
+			val, ok := values[string(keys[i])]
+			if !ok {
+				continue
+			}
+			pid, _, err := tablecodec.DecodePartitionIDInIndexValue(val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if pid != w.table.(table.PhysicalTable).GetPhysicalID() {
+				continue
+			}
+			err = w.indexes[i%n].Delete(w.sessCtx.GetSessionVars().StmtCtx, txn, idxRecord.vals, idxRecord.handle)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -1586,13 +1705,14 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 	}
 
 	var pid int64
-	for i, pi := range partitionIDs {
-		if pi == reorg.PhysicalTableID {
+	for i, id := range partitionIDs {
+		if id == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
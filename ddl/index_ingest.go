@@ -0,0 +1,344 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"go.uber.org/zap"
+)
+
+// Adding an index by ingesting works as follows:
+//
+//  1. The backfill workers read the rows from a snapshot and write the index entries to an ingest engine,
+//     which sorts them locally and ingests them into the storage at the end of the backfill.
+//  2. The DML executed during the backfill doesn't write the index, but the temporary index instead,
+//     whose entries are marked as deleted if the index entries are deleted (BackfillStateRunning).
+//  3. After the backfill is done, the DML writes both the temporary index and the index (BackfillStateReadyToMerge).
+//  4. When all the TiDB servers write both of them, the entries of the temporary index are merged into the index
+//     in transactions (BackfillStateMerging), then the index becomes public.
+//
+// If there is no ingest backend, the entries are backfilled by transactions, the temporary index is still used.
+
+// IngestBackend is the backend to add indexes by ingesting the sorted index entries.
+type IngestBackend interface {
+	// OpenEngine opens an engine to write the entries of an index of a physical table.
+	OpenEngine(ctx context.Context, jobID, physicalID, indexID int64) (IngestEngine, error)
+	// Close closes the backend.
+	Close()
+}
+
+// IngestEngine sorts the written entries and ingests them into the storage.
+type IngestEngine interface {
+	// WriteKVs writes the entries to the engine, it's called by the backfill workers concurrently.
+	WriteKVs(ctx context.Context, kvs []kv.Entry) error
+	// Import ingests the written entries into the storage. The entries must be visible to the transactions
+	// started after Import returns.
+	Import(ctx context.Context) error
+	// Cleanup removes the local data of the engine.
+	Cleanup(ctx context.Context) error
+}
+
+// IngestBackendBuilder builds the ingest backend of the storage.
+type IngestBackendBuilder func(ctx context.Context, store kv.Storage) (IngestBackend, error)
+
+var ingestBackendBuilder struct {
+	sync.RWMutex
+	builder IngestBackendBuilder
+}
+
+// SetIngestBackendBuilder sets the builder of the ingest backend. If it's not set, the indexes are added by transactions.
+func SetIngestBackendBuilder(builder IngestBackendBuilder) {
+	ingestBackendBuilder.Lock()
+	ingestBackendBuilder.builder = builder
+	ingestBackendBuilder.Unlock()
+}
+
+// getIngestBackend builds the ingest backend at the first time it's used, nil is returned if it's unavailable.
+func (dc *ddlCtx) getIngestBackend() IngestBackend {
+	dc.ingest.Lock()
+	defer dc.ingest.Unlock()
+	if dc.ingest.backend != nil {
+		return dc.ingest.backend
+	}
+	ingestBackendBuilder.RLock()
+	builder := ingestBackendBuilder.builder
+	ingestBackendBuilder.RUnlock()
+	if builder == nil {
+		return nil
+	}
+	backend, err := builder(context.Background(), dc.store)
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] build ingest backend failed, add index by transactions", zap.Error(err))
+		return nil
+	}
+	dc.ingest.backend = backend
+	return backend
+}
+
+func (dc *ddlCtx) closeIngestBackend() {
+	dc.ingest.Lock()
+	defer dc.ingest.Unlock()
+	if dc.ingest.backend != nil {
+		dc.ingest.backend.Close()
+		dc.ingest.backend = nil
+	}
+}
+
+// doReorgWorkWithTempIndex handles the write reorganization state of an index added with the temporary index,
+// done is true when the index is ready to be public.
+func (w *worker) doReorgWorkWithTempIndex(d *ddlCtx, t *meta.Meta, job *model.Job,
+	tblInfo *model.TableInfo, indexInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	switch indexInfo.BackfillState {
+	case model.BackfillStateRunning:
+		done, ver, err = w.runReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo, w.addTableIndex)
+		if !done {
+			return false, ver, err
+		}
+		indexInfo.BackfillState = model.BackfillStateReadyToMerge
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		return false, ver, errors.Trace(err)
+	case model.BackfillStateReadyToMerge:
+		// The DML of the servers in the previous schema version may only write the temporary index,
+		// so the merge is started in the next schema version.
+		indexInfo.BackfillState = model.BackfillStateMerging
+		// Initialize SnapshotVer to 0 for the reorganization of the merge.
+		job.SnapshotVer = 0
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		return false, ver, errors.Trace(err)
+	case model.BackfillStateMerging:
+		done, ver, err = w.runReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo, w.mergeTempTableIndex)
+		if !done {
+			return false, ver, err
+		}
+		indexInfo.BackfillState = model.BackfillStateInapplicable
+		return true, ver, nil
+	default:
+		return false, ver, ErrInvalidDDLState.GenWithStackByArgs("backfill", indexInfo.BackfillState)
+	}
+}
+
+// ingestPhysicalTableIndex backfills the index of a physical table by ingesting.
+func (w *worker) ingestPhysicalTableIndex(backend IngestBackend, t table.PhysicalTable, indexInfo *model.IndexInfo, reorgInfo *reorgInfo) error {
+	ctx := w.ctx
+	engine, err := backend.OpenEngine(ctx, reorgInfo.Job.ID, t.GetPhysicalID(), indexInfo.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := engine.Cleanup(ctx); err != nil {
+			logutil.BgLogger().Warn("[ddl] clean up ingest engine failed", zap.Int64("jobID", reorgInfo.Job.ID),
+				zap.Int64("physicalTableID", t.GetPhysicalID()), zap.Error(err))
+		}
+	}()
+
+	reorgInfo.ingestEngine, reorgInfo.ingestNextKey = engine, nil
+	defer func() {
+		reorgInfo.ingestEngine, reorgInfo.ingestNextKey = nil, nil
+	}()
+	// The reorg handle saved periodically by runReorgJob stays where the backfill starts until the entries are imported.
+	w.reorgCtx.setNextKey(reorgInfo.StartKey)
+	err = w.writePhysicalTableRecord(t, typeAddIndexIngestWorker, indexInfo, nil, nil, reorgInfo)
+	if err != nil {
+		if errPausedDDLJob.Equal(err) && reorgInfo.ingestNextKey != nil {
+			// Import the written entries and save the reorg handle, so the resumed job goes on from where it's paused.
+			err1 := engine.Import(ctx)
+			if err1 == nil {
+				w.reorgCtx.setNextKey(reorgInfo.ingestNextKey)
+				err1 = reorgInfo.UpdateReorgMeta(reorgInfo.ingestNextKey)
+			}
+			logutil.BgLogger().Info("[ddl] import index entries of the paused job", zap.Int64("jobID", reorgInfo.Job.ID),
+				zap.Int64("physicalTableID", t.GetPhysicalID()),
+				zap.String("nextHandle", tryDecodeToHandleString(reorgInfo.ingestNextKey)), zap.Error(err1))
+		}
+		return errors.Trace(err)
+	}
+	startTime := time.Now()
+	err = engine.Import(ctx)
+	logutil.BgLogger().Info("[ddl] import index entries", zap.Int64("jobID", reorgInfo.Job.ID),
+		zap.Int64("physicalTableID", t.GetPhysicalID()), zap.Duration("takeTime", time.Since(startTime)), zap.Error(err))
+	return errors.Trace(err)
+}
+
+type addIndexIngestWorker struct {
+	baseIndexWorker
+	index            table.Index
+	engine           IngestEngine
+	needRestoredData bool
+}
+
+func newAddIndexIngestWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, indexInfo *model.IndexInfo,
+	decodeColMap map[int64]decoder.Column, sqlMode mysql.SQLMode, engine IngestEngine) *addIndexIngestWorker {
+	index := tables.NewIndex(t.GetPhysicalID(), t.Meta(), indexInfo)
+	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
+	return &addIndexIngestWorker{
+		baseIndexWorker: baseIndexWorker{
+			backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+			indexes:        []table.Index{index},
+			rowDecoder:     rowDecoder,
+			defaultVals:    make([]types.Datum, len(t.WritableCols())),
+			rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+			metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("add_idx_ingest_speed"),
+			sqlMode:        sqlMode,
+		},
+		index:            index,
+		engine:           engine,
+		needRestoredData: tables.NeedRestoredData(indexInfo.Columns, t.Meta().Columns),
+	}
+}
+
+// BackfillDataInTxn reads w.batchCnt rows from a snapshot and writes their index entries to the ingest engine.
+func (w *addIndexIngestWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), false, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		sc := w.sessCtx.GetSessionVars().StmtCtx
+		entries := make([]kv.Entry, 0, len(idxRecords))
+		for _, idxRecord := range idxRecords {
+			taskCtx.scanCount++
+			key, distinct, err := w.index.GenIndexKey(sc, idxRecord.vals, idxRecord.handle, nil)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			val, err := tablecodec.GenIndexValuePortal(sc, w.table.Meta(), w.index.Meta(), w.needRestoredData, distinct, false,
+				idxRecord.vals, idxRecord.handle, w.table.(table.PhysicalTable).GetPhysicalID(), idxRecord.rsData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			entries = append(entries, kv.Entry{Key: key, Value: val})
+		}
+		if err := w.engine.WriteKVs(ctx, entries); err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.addedCount = len(entries)
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "AddIndexIngestBackfillDataInTxn", 3000)
+	return
+}
+
+// mergeTempTableIndex merges the temporary index into the index for a table.
+func (w *worker) mergeTempTableIndex(t table.Table, idx *model.IndexInfo, reorgInfo *reorgInfo) error {
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		return errors.Trace(w.mergePhysicalTempIndex(t.(table.PhysicalTable), idx, reorgInfo))
+	}
+	for {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		if err := w.mergePhysicalTempIndex(p, idx, reorgInfo); err != nil {
+			return errors.Trace(err)
+		}
+		finish, err := w.updateReorgInfo(tbl, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if finish {
+			return nil
+		}
+	}
+}
+
+// mergePhysicalTempIndex merges the entries of the temporary index into the index of a physical table in batches.
+// The progress is kept by the key of the temporary index, so the merge can be resumed.
+func (w *worker) mergePhysicalTempIndex(t table.PhysicalTable, idx *model.IndexInfo, reorgInfo *reorgInfo) error {
+	start, end := tablecodec.GetTableIndexKeyRange(t.GetPhysicalID(), tablecodec.TempIndexPrefix|idx.ID)
+	startKey, endKey := kv.Key(start), kv.Key(end)
+	if reorgInfo.StartKey.Cmp(startKey) > 0 && reorgInfo.StartKey.Cmp(endKey) < 0 {
+		startKey = reorgInfo.StartKey
+	}
+	logutil.BgLogger().Info("[ddl] start to merge temporary index", zap.String("job", reorgInfo.Job.String()),
+		zap.Int64("physicalTableID", t.GetPhysicalID()), zap.Stringer("startKey", startKey))
+
+	for startKey.Cmp(endKey) < 0 {
+		if err := w.isReorgRunnable(reorgInfo.d); err != nil {
+			return errors.Trace(err)
+		}
+		batchCnt := int(variable.GetDDLReorgBatchSize())
+		var (
+			count   int
+			nextKey kv.Key
+		)
+		err := kv.RunInNewTxn(w.ctx, reorgInfo.d.store, true, func(ctx context.Context, txn kv.Transaction) error {
+			count, nextKey = 0, endKey
+			txn.SetOption(kv.Priority, reorgInfo.Job.Priority)
+			it, err := txn.Iter(startKey, endKey)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			defer it.Close()
+			for it.Valid() {
+				if count >= batchCnt {
+					nextKey = it.Key().Clone()
+					break
+				}
+				val, isDelete, err := tablecodec.DecodeTempIndexValue(it.Value())
+				if err != nil {
+					return errors.Trace(err)
+				}
+				key := it.Key().Clone()
+				tablecodec.TempIndexKey2IndexKey(idx.ID, key)
+				if isDelete {
+					err = txn.Delete(key)
+				} else {
+					err = txn.Set(key, val)
+				}
+				if err != nil {
+					return errors.Trace(err)
+				}
+				count++
+				if err = it.Next(); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w.reorgCtx.increaseRowCount(int64(count))
+		w.reorgCtx.setNextKey(nextKey)
+		startKey = nextKey
+	}
+	return nil
+}
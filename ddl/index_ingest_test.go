@@ -0,0 +1,328 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIngestBackend sorts the entries in memory and writes them by transactions when they're imported.
+type mockIngestBackend struct {
+	store kv.Storage
+
+	// beforeWrite is called before the entries are written.
+	beforeWrite func()
+	// beforeImport is called before the entries are imported.
+	beforeImport func()
+
+	mu       sync.Mutex
+	written  int
+	imported int
+}
+
+func (b *mockIngestBackend) OpenEngine(ctx context.Context, jobID, physicalID, indexID int64) (ddl.IngestEngine, error) {
+	return &mockIngestEngine{backend: b}, nil
+}
+
+func (b *mockIngestBackend) Close() {}
+
+func (b *mockIngestBackend) importedCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.imported
+}
+
+func (b *mockIngestBackend) writtenCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.written
+}
+
+type mockIngestEngine struct {
+	backend *mockIngestBackend
+
+	mu      sync.Mutex
+	entries []kv.Entry
+}
+
+func (e *mockIngestEngine) WriteKVs(ctx context.Context, kvs []kv.Entry) error {
+	if e.backend.beforeWrite != nil {
+		e.backend.beforeWrite()
+	}
+	e.mu.Lock()
+	e.entries = append(e.entries, kvs...)
+	e.mu.Unlock()
+	e.backend.mu.Lock()
+	e.backend.written += len(kvs)
+	e.backend.mu.Unlock()
+	return nil
+}
+
+func (e *mockIngestEngine) Import(ctx context.Context) error {
+	if e.backend.beforeImport != nil {
+		e.backend.beforeImport()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sort.Slice(e.entries, func(i, j int) bool {
+		return bytes.Compare(e.entries[i].Key, e.entries[j].Key) < 0
+	})
+	err := kv.RunInNewTxn(ctx, e.backend.store, false, func(ctx context.Context, txn kv.Transaction) error {
+		for _, entry := range e.entries {
+			if err := txn.Set(entry.Key, entry.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.backend.mu.Lock()
+	e.backend.imported += len(e.entries)
+	e.backend.mu.Unlock()
+	return nil
+}
+
+func (e *mockIngestEngine) Cleanup(ctx context.Context) error {
+	e.mu.Lock()
+	e.entries = nil
+	e.mu.Unlock()
+	return nil
+}
+
+func TestAddIndexByIngest(t *testing.T) {
+	for _, partitioned := range []bool{false, true} {
+		t.Run(fmt.Sprintf("partitioned=%v", partitioned), func(t *testing.T) {
+			store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+			defer clean()
+			tk2 := testkit.NewTestKit(t, store)
+			tk2.MustExec("use test")
+			var (
+				importErr error
+				once      sync.Once
+			)
+			backend := &mockIngestBackend{store: store}
+			// The rows are changed after they're read by the backfill, the changes are merged from the temporary index.
+			backend.beforeImport = func() {
+				once.Do(func() {
+					for _, sql := range []string{
+						"insert into t values (2000, 2000, 2000)",
+						"update t set b = b + 1000 where a = 150",
+						"delete from t where a = 160",
+					} {
+						if _, err := tk2.Exec(sql); err != nil && importErr == nil {
+							importErr = err
+						}
+					}
+				})
+			}
+			ddl.SetIngestBackendBuilder(func(ctx context.Context, store kv.Storage) (ddl.IngestBackend, error) {
+				return backend, nil
+			})
+			defer ddl.SetIngestBackendBuilder(nil)
+
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = 1")
+			defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+			if partitioned {
+				tk.MustExec("create table t (a int primary key, b int, c int) partition by hash(a) partitions 3")
+			} else {
+				tk.MustExec("create table t (a int primary key, b int, c int)")
+			}
+			testAddIndexWithDML(t, tk, dom, "alter table t add index idx(b)")
+			require.NoError(t, importErr)
+			require.Greater(t, backend.importedCount(), 0)
+			tk.MustQuery("select a from t use index(idx) where b in (2000, 1015, 16)").Check(testkit.Rows("150", "2000"))
+		})
+	}
+}
+
+func TestPauseAndResumeAddIndexByIngest(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = 1")
+	defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+	// Every batch backfills a region of 25 rows in a transaction.
+	tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = 1")
+	defer tk.MustExec("set @@global.tidb_ddl_reorg_worker_cnt = default")
+	tk.MustExec("set @@global.tidb_ddl_reorg_batch_size = 32")
+	defer tk.MustExec("set @@global.tidb_ddl_reorg_batch_size = default")
+	tk.MustExec("create table t (a int primary key, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+	tk.MustQuery("split table t by (25), (50), (75)").Check(testkit.Rows("3 1"))
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	var (
+		jobID    int64
+		pauseErr error
+		once     sync.Once
+	)
+	jobState := func(tk *testkit.TestKit) string {
+		rows := tk.MustQuery(fmt.Sprintf("admin show ddl jobs where job_id = %d", atomic.LoadInt64(&jobID))).Rows()
+		if len(rows) == 0 {
+			return ""
+		}
+		return rows[0][10].(string)
+	}
+	backend := &mockIngestBackend{store: store}
+	// Pause the job when the first region is backfilled, it's paused after the batch is done.
+	backend.beforeWrite = func() {
+		once.Do(func() {
+			if _, pauseErr = tk1.Exec(fmt.Sprintf("admin pause ddl jobs %d", atomic.LoadInt64(&jobID))); pauseErr != nil {
+				return
+			}
+			for start := time.Now(); jobState(tk1) != "paused"; time.Sleep(50 * time.Millisecond) {
+				if time.Since(start) > 30*time.Second {
+					pauseErr = fmt.Errorf("the job isn't paused")
+					return
+				}
+			}
+		})
+	}
+	ddl.SetIngestBackendBuilder(func(ctx context.Context, store kv.Storage) (ddl.IngestBackend, error) {
+		return backend, nil
+	})
+	defer ddl.SetIngestBackendBuilder(nil)
+
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	hook := &ddl.TestDDLCallback{Do: dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type == model.ActionAddIndex {
+			atomic.StoreInt64(&jobID, job.ID)
+		}
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := tk.Exec("alter table t add index idx(b)")
+		done <- err
+	}()
+	tk2 := testkit.NewTestKit(t, store)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&jobID) != 0 && jobState(tk2) == "paused"
+	}, 40*time.Second, 50*time.Millisecond)
+	require.NoError(t, pauseErr)
+	// The entries written before the job is paused are imported.
+	require.Eventually(t, func() bool {
+		return backend.importedCount() > 0
+	}, 10*time.Second, 50*time.Millisecond)
+	writtenBeforeResume := backend.writtenCount()
+	require.Equal(t, writtenBeforeResume, backend.importedCount())
+
+	tk2.MustQuery(fmt.Sprintf("admin resume ddl jobs %d", jobID)).Check(testkit.Rows(fmt.Sprintf("%d successful", jobID)))
+	require.NoError(t, <-done)
+	// The resumed job doesn't backfill the rows imported before it's paused again.
+	require.Less(t, backend.writtenCount()-writtenBeforeResume, 100)
+	require.Equal(t, backend.writtenCount(), backend.importedCount())
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t use index(idx)").Check(testkit.Rows("100"))
+}
+
+func TestAddIndexByIngestWithoutBackend(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = 1")
+	defer tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+	tk.MustExec("create table t (a int primary key, b int, c int)")
+	// The index is backfilled by transactions, the DML is still merged from the temporary index.
+	testAddIndexWithDML(t, tk, dom, "alter table t add index idx(b)")
+
+	// The unique index is always added by transactions.
+	tk.MustExec("alter table t add unique index uk(c)")
+	tk.MustExec("admin check table t")
+}
+
+// testAddIndexWithDML adds the index idx(b) to the table t(a, b, c), and inserts, updates and deletes
+// the records in every state of the index, then checks the records and the indexes.
+func testAddIndexWithDML(t *testing.T, tk *testkit.TestKit, dom *domain.Domain, ddlSQL string) {
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i*10, i, i))
+	}
+	tk1 := testkit.NewTestKit(t, tk.Session().GetStore())
+	tk1.MustExec("use test")
+
+	hook := &ddl.TestDDLCallback{Do: dom}
+	var checkErr error
+	states := make(map[string]struct{})
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionAddIndex || checkErr != nil {
+			return
+		}
+		tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+		if err != nil {
+			checkErr = err
+			return
+		}
+		backfillState := model.BackfillStateInapplicable
+		if idx := tbl.Meta().FindIndexByName("idx"); idx != nil {
+			backfillState = idx.BackfillState
+		}
+		state := fmt.Sprintf("%s-%s", job.SchemaState, backfillState)
+		if _, ok := states[state]; ok {
+			return
+		}
+		states[state] = struct{}{}
+		// Every state inserts, updates and deletes different rows.
+		base := len(states)
+		for _, sql := range []string{
+			fmt.Sprintf("insert into t values (%d, %d, %d)", 1000+base, base, 1000+base),
+			fmt.Sprintf("update t set b = b + 100 where a = %d", base*10),
+			fmt.Sprintf("update t set a = a + 500 where a = %d", 10+base*10),
+			fmt.Sprintf("delete from t where a = %d", 1000+base-1),
+		} {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				checkErr = fmt.Errorf("%s in state %s: %v", sql, state, checkErr)
+				return
+			}
+		}
+	}
+	originalHook := dom.DDL().GetHook()
+	dom.DDL().SetHook(hook)
+	tk.MustExec(ddlSQL)
+	dom.DDL().SetHook(originalHook)
+	require.NoError(t, checkErr)
+	for _, state := range []model.BackfillState{model.BackfillStateRunning, model.BackfillStateReadyToMerge, model.BackfillStateMerging} {
+		require.Contains(t, states, fmt.Sprintf("%s-%s", model.StateWriteReorganization, state))
+	}
+
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t use index(idx)").Check(tk.MustQuery("select count(*) from t ignore index(idx)").Rows())
+	tk.MustQuery("select a from t use index(idx) where b = 101").Check(testkit.Rows("10"))
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/docker/go-units"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/backend"
+	lightningkv "github.com/pingcap/tidb/br/pkg/lightning/backend/kv"
+	"github.com/pingcap/tidb/br/pkg/lightning/backend/local"
+	"github.com/pingcap/tidb/br/pkg/lightning/common"
+	lightningcfg "github.com/pingcap/tidb/br/pkg/lightning/config"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/kv"
+)
+
+const (
+	// indexEngineID is the engine ID lightning uses for the index engines.
+	indexEngineID = -1
+	// maxOpenFiles is the limit of the files opened by the engines.
+	maxOpenFiles = 1024
+)
+
+// localBackend adds indexes by sorting the index entries locally and ingesting them as SST files.
+type localBackend struct {
+	backend backend.Backend
+	dir     string
+}
+
+// NewBackend creates the ingest backend of the store, the sorted index entries are kept in the temporary storage path.
+func NewBackend(ctx context.Context, store kv.Storage) (ddl.IngestBackend, error) {
+	etcdStore, ok := store.(kv.EtcdBackend)
+	if !ok {
+		return nil, errors.New("the storage doesn't support ingesting")
+	}
+	addrs, err := etcdStore.EtcdAddrs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("the PD address of the storage is unknown")
+	}
+
+	globalCfg := config.GetGlobalConfig()
+	dir := filepath.Join(globalCfg.TempStoragePath, "ddl-ingest")
+	// The entries left by the previous process can't be imported any more.
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	security := globalCfg.Security
+	tls, err := common.NewTLS(security.ClusterSSLCA, security.ClusterSSLCert, security.ClusterSSLKey, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cfg := lightningcfg.NewConfig()
+	cfg.TiDB.PdAddr = strings.Join(addrs, ",")
+	cfg.Checkpoint.Enable = false
+	cfg.App.CheckRequirements = false
+	cfg.TikvImporter.Backend = lightningcfg.BackendLocal
+	cfg.TikvImporter.SortedKVDir = dir
+	cfg.TikvImporter.DuplicateResolution = lightningcfg.DupeResAlgNone
+	cfg.TikvImporter.RangeConcurrency = 16
+	cfg.TikvImporter.EngineMemCacheSize = 512 * units.MiB
+	cfg.TikvImporter.LocalWriterMemCacheSize = 128 * units.MiB
+	cfg.TikvImporter.RegionSplitSize = lightningcfg.SplitRegionSize
+	be, err := local.NewLocalBackend(ctx, tls, cfg, nil, maxOpenFiles, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &localBackend{backend: be, dir: dir}, nil
+}
+
+// OpenEngine implements ddl.IngestBackend interface.
+func (b *localBackend) OpenEngine(ctx context.Context, jobID, physicalID, indexID int64) (ddl.IngestEngine, error) {
+	tableName := fmt.Sprintf("ddl_%d_%d_%d", jobID, physicalID, indexID)
+	opened, err := b.backend.OpenEngine(ctx, &backend.EngineConfig{}, tableName, indexEngineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &localEngine{opened: opened}, nil
+}
+
+// Close implements ddl.IngestBackend interface.
+func (b *localBackend) Close() {
+	b.backend.Close()
+	_ = os.RemoveAll(b.dir)
+}
+
+type localEngine struct {
+	opened *backend.OpenedEngine
+	closed *backend.ClosedEngine
+
+	mu struct {
+		sync.Mutex
+		// idleWriters are the writers which aren't used by the backfill workers.
+		idleWriters []*backend.LocalEngineWriter
+		allWriters  []*backend.LocalEngineWriter
+	}
+}
+
+func (e *localEngine) getWriter(ctx context.Context) (*backend.LocalEngineWriter, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n := len(e.mu.idleWriters); n > 0 {
+		w := e.mu.idleWriters[n-1]
+		e.mu.idleWriters = e.mu.idleWriters[:n-1]
+		return w, nil
+	}
+	w, err := e.opened.LocalWriter(ctx, &backend.LocalWriterConfig{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e.mu.allWriters = append(e.mu.allWriters, w)
+	return w, nil
+}
+
+func (e *localEngine) putWriter(w *backend.LocalEngineWriter) {
+	e.mu.Lock()
+	e.mu.idleWriters = append(e.mu.idleWriters, w)
+	e.mu.Unlock()
+}
+
+// WriteKVs implements ddl.IngestEngine interface.
+func (e *localEngine) WriteKVs(ctx context.Context, kvs []kv.Entry) error {
+	if len(kvs) == 0 {
+		return nil
+	}
+	pairs := make([]common.KvPair, 0, len(kvs))
+	for _, entry := range kvs {
+		pairs = append(pairs, common.KvPair{Key: entry.Key, Val: entry.Value})
+	}
+	w, err := e.getWriter(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer e.putWriter(w)
+	return errors.Trace(w.WriteRows(ctx, nil, lightningkv.MakeRowsFromKvPairs(pairs)))
+}
+
+// closeEngine flushes the writers and closes the engine, no more entries can be written after it's closed.
+func (e *localEngine) closeEngine(ctx context.Context) error {
+	if e.closed != nil {
+		return nil
+	}
+	e.mu.Lock()
+	writers := e.mu.allWriters
+	e.mu.allWriters, e.mu.idleWriters = nil, nil
+	e.mu.Unlock()
+	for _, w := range writers {
+		if _, err := w.Close(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	closed, err := e.opened.Close(ctx, &backend.EngineConfig{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.closed = closed
+	return nil
+}
+
+// Import implements ddl.IngestEngine interface.
+func (e *localEngine) Import(ctx context.Context) error {
+	if err := e.closeEngine(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(e.closed.Import(ctx, int64(lightningcfg.SplitRegionSize)))
+}
+
+// Cleanup implements ddl.IngestEngine interface.
+func (e *localEngine) Cleanup(ctx context.Context) error {
+	if err := e.closeEngine(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(e.closed.Cleanup(ctx))
+}
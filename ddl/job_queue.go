@@ -0,0 +1,226 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	goutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.etcd.io/etcd/clientv3"
+	"go.uber.org/zap"
+)
+
+// How do the DDL jobs of independent tables run concurrently?
+//
+// Besides DefaultJobListKey and AddIndexJobListKey, there are at most variable.MaxDDLJobQueueCount-1 extra
+// job queues for each kind of jobs. A new job is put in the queue chosen by its table ID (or schema ID
+// if it's a schema level job) modulo tidb_ddl_job_worker_cnt, so the jobs of the same table are ordered
+// in the same queue. The owner runs a worker for every queue which has jobs, and the workers of the extra
+// queues quit when their queues become empty.
+//
+// A job may depend on the jobs in the other queues, for example, a DROP SCHEMA job depends on the jobs of
+// the tables in the schema. When a job is added, the unfinished job it depends on is looked for in the
+// other queues, and the worker waits for it before running the job. The job IDs are generated in the
+// transaction which adds the jobs, so all the jobs with smaller IDs are already in the queues, and a job
+// only waits for the jobs with smaller IDs, so the jobs can't wait for each other.
+//
+// The schema version is generated in the same transaction which runs a step of the job. If the workers
+// of two queues update the schema version at the same time, one of the transactions meets a write
+// conflict and the step is run again later, so the schema versions and their diffs are still in order.
+
+// getJobQueueCount returns the count of the job queues that the new jobs can be put in.
+func getJobQueueCount(ctx sessionctx.Context) int {
+	if ctx.GetSessionVars().GlobalVarsAccessor == nil {
+		// The internal sessions which run the DDLs while bootstrapping can't read the global variables.
+		return variable.DefTiDBDDLJobWorkerCount
+	}
+	val, err := variable.GetGlobalSystemVar(ctx.GetSessionVars(), variable.TiDBDDLJobWorkerCount)
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] get DDL job worker count failed", zap.Error(err))
+		return variable.DefTiDBDDLJobWorkerCount
+	}
+	cnt, err := strconv.Atoi(val)
+	if err != nil || cnt < 1 {
+		return variable.DefTiDBDDLJobWorkerCount
+	}
+	if cnt > variable.MaxDDLJobQueueCount {
+		cnt = variable.MaxDDLJobQueueCount
+	}
+	return cnt
+}
+
+func (d *ddl) asyncNotifyJobQueueWorkers(job *model.Job) {
+	// If the workers don't run, we needn't to notify workers.
+	if !RunWorker {
+		return
+	}
+
+	if d.ownerManager.IsOwner() {
+		asyncNotify(d.queueWorkerCh)
+	} else {
+		d.asyncNotifyByEtcd(addingDDLJobQueueKey, job)
+	}
+}
+
+// startJobQueueWorkers starts the workers of the extra job queues which have jobs.
+// Only the DDL owner does it.
+func (d *ddl) startJobQueueWorkers() {
+	defer func() {
+		goutil.Recover(metrics.LabelDDL, "startJobQueueWorkers", nil, false)
+		d.wg.Done()
+	}()
+
+	// The new jobs are notified by the channel or etcd, so checking all the queues with the ticker
+	// is only a fallback, and it's done rarely to keep the owner from reading the queues all the time.
+	checkTime := chooseLeaseTime(20*d.lease, 10*time.Second)
+	ticker := time.NewTicker(checkTime)
+	defer ticker.Stop()
+	var notifyDDLJobByEtcdCh clientv3.WatchChan
+	if d.etcdCli != nil {
+		notifyDDLJobByEtcdCh = d.etcdCli.Watch(context.Background(), addingDDLJobQueueKey)
+	}
+	ownerTicker := time.NewTicker(chooseLeaseTime(2*d.lease, 1*time.Second))
+	defer ownerTicker.Stop()
+	isOwner := false
+	for {
+		ok := true
+		select {
+		case <-ticker.C:
+		case <-d.queueWorkerCh:
+		case _, ok = <-notifyDDLJobByEtcdCh:
+		case <-ownerTicker.C:
+			// Check the queues at once when it becomes the owner, since the jobs may be left by the previous owner.
+			wasOwner := isOwner
+			isOwner = d.ownerManager.IsOwner()
+			if wasOwner || !isOwner {
+				continue
+			}
+		case <-d.ctx.Done():
+			return
+		}
+
+		if !ok {
+			logutil.BgLogger().Warn("[ddl] job queue workers watch channel closed", zap.String("watch key", addingDDLJobQueueKey))
+			notifyDDLJobByEtcdCh = d.etcdCli.Watch(context.Background(), addingDDLJobQueueKey)
+			time.Sleep(chooseLeaseTime(2*d.lease, 1*time.Second))
+			continue
+		}
+		if !d.ownerManager.IsOwner() {
+			continue
+		}
+		if err := d.checkJobQueues(); err != nil && d.ctx.Err() == nil {
+			logutil.BgLogger().Warn("[ddl] check DDL job queues failed", zap.Error(err))
+		}
+	}
+}
+
+// checkJobQueues starts or notifies the workers of the extra job queues which have jobs.
+func (d *ddl) checkJobQueues() error {
+	type jobQueue struct {
+		tp  workerType
+		idx int
+	}
+	var queues []jobQueue
+	err := kv.RunInNewTxn(context.Background(), d.store, false, func(ctx context.Context, txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		for _, tp := range []workerType{generalWorker, addIdxWorker} {
+			for idx := 1; idx < variable.MaxDDLJobQueueCount; idx++ {
+				cnt, err := t.DDLJobQueueLen(meta.GetJobListKey(idx, tp == addIdxWorker))
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if cnt > 0 {
+					queues = append(queues, jobQueue{tp: tp, idx: idx})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, q := range queues {
+		d.startJobQueueWorker(q.tp, q.idx)
+	}
+	return nil
+}
+
+func (d *ddl) startJobQueueWorker(tp workerType, idx int) {
+	d.queueWorkers.Lock()
+	defer d.queueWorkers.Unlock()
+	key := string(meta.GetJobListKey(idx, tp == addIdxWorker))
+	if w, ok := d.queueWorkers.workers[key]; ok {
+		asyncNotify(w.ddlJobCh)
+		return
+	}
+
+	w := newWorker(d.ctx, tp, idx, d.sessPool, d.delRangeMgr)
+	w.tryQuit = func() bool {
+		return d.tryQuitJobQueueWorker(w)
+	}
+	d.queueWorkers.workers[key] = w
+	w.wg.Add(1)
+	go w.start(d.ddlCtx)
+	metrics.DDLCounter.WithLabelValues(metrics.CreateDDL + "_" + w.String()).Inc()
+	asyncNotify(w.ddlJobCh)
+}
+
+// tryQuitJobQueueWorker removes the worker if its job queue is empty or it's not the owner.
+func (d *ddl) tryQuitJobQueueWorker(w *worker) bool {
+	// The reorganization is still running in the background.
+	if w.reorgCtx.doneCh != nil {
+		return false
+	}
+
+	d.queueWorkers.Lock()
+	defer d.queueWorkers.Unlock()
+	if d.ownerManager.IsOwner() {
+		var cnt int64
+		err := kv.RunInNewTxn(context.Background(), d.store, false, func(ctx context.Context, txn kv.Transaction) error {
+			var err error
+			cnt, err = meta.NewMeta(txn).DDLJobQueueLen(w.jobListKey)
+			return errors.Trace(err)
+		})
+		if err != nil || cnt > 0 {
+			return false
+		}
+	}
+	delete(d.queueWorkers.workers, string(w.jobListKey))
+	return true
+}
+
+func (d *ddl) closeJobQueueWorkers() {
+	d.queueWorkers.Lock()
+	workers := make([]*worker, 0, len(d.queueWorkers.workers))
+	for _, w := range d.queueWorkers.workers {
+		workers = append(workers, w)
+	}
+	d.queueWorkers.Unlock()
+
+	for _, w := range workers {
+		w.close()
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentDDLOnIndependentTables(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @@global.tidb_ddl_job_worker_cnt = 3")
+	defer tk.MustExec("set @@global.tidb_ddl_job_worker_cnt = default")
+	tk.MustExec("create database test_queue")
+	tk.MustExec("use test_queue")
+
+	tableID := func(name string) int64 {
+		tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test_queue"), model.NewCIStr(name))
+		require.NoError(t, err)
+		return tbl.Meta().ID
+	}
+	tk.MustExec("create table t1 (a int, b int)")
+	t1ID := tableID("t1")
+	// Find a table whose jobs are put in the other queue.
+	t2 := ""
+	for i := 2; t2 == "" && i < 10; i++ {
+		name := fmt.Sprintf("t%d", i)
+		tk.MustExec(fmt.Sprintf("create table %s (a int, b int)", name))
+		if tableID(name)%3 != t1ID%3 {
+			t2 = name
+		}
+	}
+	require.NotEmpty(t, t2)
+	for i := 0; i < 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values (%d, %d)", i, i))
+		tk.MustExec(fmt.Sprintf("insert into %s values (%d, %d)", t2, i, i))
+	}
+
+	tk1 := testkit.NewTestKit(t, store)
+	var (
+		jobID    int64
+		pauseErr atomic.Value
+	)
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	hook := &ddl.TestDDLCallback{Do: dom}
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if job.TableID != t1ID || job.Type != model.ActionAddIndex || job.SchemaState != model.StateWriteReorganization ||
+			!atomic.CompareAndSwapInt64(&jobID, 0, job.ID) {
+			return
+		}
+		if _, err := tk1.Exec(fmt.Sprintf("admin pause ddl jobs %d", job.ID)); err != nil {
+			pauseErr.Store(err)
+		}
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	addIdxDone := make(chan error, 1)
+	go func() {
+		_, err := tk.Exec("alter table t1 add index idx(b)")
+		addIdxDone <- err
+	}()
+	require.Eventually(t, func() bool {
+		id := atomic.LoadInt64(&jobID)
+		return id != 0 && len(tk1.MustQuery(fmt.Sprintf("admin show ddl jobs where job_id = %d and state = 'paused'", id)).Rows()) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Nil(t, pauseErr.Load())
+
+	// The paused job of t1 doesn't block the jobs of the independent table.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test_queue")
+	tk2.MustExec(fmt.Sprintf("alter table %s add index idx(b)", t2))
+	tk2.MustExec(fmt.Sprintf("admin check table %s", t2))
+
+	// Dropping the database depends on the paused job.
+	dropDBDone := make(chan error, 1)
+	go func() {
+		_, err := tk2.Exec("drop database test_queue")
+		dropDBDone <- err
+	}()
+	select {
+	case err := <-dropDBDone:
+		require.FailNow(t, "the dependent job shouldn't be finished", "err: %v", err)
+	case err := <-addIdxDone:
+		require.FailNow(t, "the paused job shouldn't be finished", "err: %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	tk1.MustExec(fmt.Sprintf("admin resume ddl jobs %d", atomic.LoadInt64(&jobID)))
+	require.NoError(t, <-addIdxDone)
+	require.NoError(t, <-dropDBDone)
+	tk1.MustQuery("show databases like 'test_queue'").Check(testkit.Rows())
+}
+
+func TestExchangePartitionDependsOnPartitionedTable(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @@global.tidb_ddl_job_worker_cnt = 3")
+	defer tk.MustExec("set @@global.tidb_ddl_job_worker_cnt = default")
+	tk.MustExec("use test")
+
+	tableID := func(name string) int64 {
+		tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr(name))
+		require.NoError(t, err)
+		return tbl.Meta().ID
+	}
+	tk.MustExec("create table pt (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	ptID := tableID("pt")
+	// Find a non-partitioned table whose jobs are put in the other queue.
+	nt := ""
+	for i := 0; nt == "" && i < 10; i++ {
+		name := fmt.Sprintf("nt%d", i)
+		tk.MustExec(fmt.Sprintf("create table %s (a int)", name))
+		if tableID(name)%3 != ptID%3 {
+			nt = name
+		}
+	}
+	require.NotEmpty(t, nt)
+	tk.MustExec("insert into pt values (1), (11)")
+	tk.MustExec(fmt.Sprintf("insert into %s values (2)", nt))
+
+	var blocked, exchRunEarly int32
+	unblock := make(chan struct{})
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	hook := &ddl.TestDDLCallback{Do: dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		switch job.Type {
+		case model.ActionModifyTableComment:
+			if job.TableID == ptID && atomic.CompareAndSwapInt32(&blocked, 0, 1) {
+				<-unblock
+				atomic.StoreInt32(&blocked, 2)
+			}
+		case model.ActionExchangeTablePartition:
+			if atomic.LoadInt32(&blocked) == 1 {
+				atomic.StoreInt32(&exchRunEarly, 1)
+			}
+		}
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	alterDone := make(chan error, 1)
+	go func() {
+		_, err := tk1.Exec("alter table pt comment 'pt'")
+		alterDone <- err
+	}()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&blocked) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// The job of the non-partitioned table isn't run until the job of the partitioned table is done.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@tidb_enable_exchange_partition = 1")
+	exchDone := make(chan error, 1)
+	go func() {
+		_, err := tk2.Exec(fmt.Sprintf("alter table pt exchange partition p0 with table %s", nt))
+		exchDone <- err
+	}()
+	time.Sleep(500 * time.Millisecond)
+	close(unblock)
+	require.NoError(t, <-alterDone)
+	require.NoError(t, <-exchDone)
+	require.Equal(t, int32(0), atomic.LoadInt32(&exchRunEarly))
+
+	tk.MustQuery("select a from pt order by a").Check(testkit.Rows("2", "11"))
+	tk.MustQuery(fmt.Sprintf("select a from %s", nt)).Check(testkit.Rows("1"))
+	tk.MustQuery("select table_comment from information_schema.tables where table_schema = 'test' and table_name = 'pt'").Check(testkit.Rows("pt"))
+}
@@ -0,0 +1,360 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// multiSchemaChange submits the sub-jobs collected from an ALTER TABLE statement as one DDL job.
+func (d *ddl) multiSchemaChange(ctx sessionctx.Context, ti ast.Ident) error {
+	info := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo
+	ctx.GetSessionVars().StmtCtx.MultiSchemaInfo = nil
+	if len(info.SubJobs) == 0 {
+		return nil
+	}
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkMultiSchemaInfo(info, t); err != nil {
+		return errors.Trace(err)
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionMultiSchemaChange,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       nil,
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		MultiSchemaInfo: info,
+		Priority:        ctx.GetSessionVars().DDLReorgPriority,
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// appendToSubJobs appends the job to the sub-jobs of a multi-schema change
+// instead of submitting it to the DDL job queue.
+func appendToSubJobs(m *model.MultiSchemaInfo, job *model.Job) error {
+	switch job.Type {
+	case model.ActionAddColumn, model.ActionDropColumn, model.ActionModifyColumn, model.ActionSetDefaultValue,
+		model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionDropIndex, model.ActionDropPrimaryKey,
+		model.ActionRenameIndex, model.ActionAlterIndexVisibility, model.ActionAddForeignKey:
+	default:
+		return errRunMultiSchemaChanges.FastGenByArgs(job.Type.String())
+	}
+	m.SubJobs = append(m.SubJobs, &model.SubJob{
+		Type:        job.Type,
+		Args:        job.Args,
+		RawArgs:     job.RawArgs,
+		SchemaState: job.SchemaState,
+		SnapshotVer: job.SnapshotVer,
+		Revertible:  true,
+		CtxVars:     job.CtxVars,
+	})
+	return nil
+}
+
+// pendingAddedColumns returns the columns added by the sub-jobs collected so far.
+func pendingAddedColumns(m *model.MultiSchemaInfo) []*model.ColumnInfo {
+	var cols []*model.ColumnInfo
+	for _, sub := range m.SubJobs {
+		if sub.Type == model.ActionAddColumn {
+			cols = append(cols, sub.Args[0].(*table.Column).ColumnInfo)
+		}
+	}
+	return cols
+}
+
+// hasPendingFKLookupIndex returns whether an index added by the sub-jobs collected so far
+// can be used to look up the rows by the foreign key columns.
+func hasPendingFKLookupIndex(m *model.MultiSchemaInfo, cols []model.CIStr) bool {
+	if m == nil {
+		return false
+	}
+	for _, sub := range m.SubJobs {
+		if sub.Type != model.ActionAddIndex && sub.Type != model.ActionAddPrimaryKey {
+			continue
+		}
+		parts := sub.Args[2].([]*ast.IndexPartSpecification)
+		if len(parts) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			if parts[i].Column == nil || parts[i].Column.Name.L != col.L || parts[i].Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMultiSchemaInfo checks the conflicts between the sub-jobs. A column or an index can only
+// be operated by one sub-job, and the columns referenced by the other sub-jobs can't be changed.
+func checkMultiSchemaInfo(info *model.MultiSchemaInfo, t table.Table) error {
+	var addColumns, dropColumns, modifyColumns, relativeColumns []model.CIStr
+	var addIndexes, dropIndexes, alterIndexes []model.CIStr
+	for _, sub := range info.SubJobs {
+		switch sub.Type {
+		case model.ActionAddColumn:
+			addColumns = append(addColumns, sub.Args[0].(*table.Column).Name)
+			relativeColumns = appendPositionColumn(relativeColumns, sub.Args[1].(*ast.ColumnPosition))
+		case model.ActionDropColumn:
+			colName := sub.Args[0].(model.CIStr)
+			dropColumns = append(dropColumns, colName)
+			// The indexes only covering the dropped column are dropped with it.
+			for _, idx := range listIndicesWithColumn(colName.L, t.Meta().Indices) {
+				dropIndexes = append(dropIndexes, idx.Name)
+			}
+		case model.ActionModifyColumn:
+			newCol := *sub.Args[0].(**table.Column)
+			oldColName := sub.Args[1].(model.CIStr)
+			modifyColumns = append(modifyColumns, oldColName)
+			if newCol.Name.L != oldColName.L {
+				modifyColumns = append(modifyColumns, newCol.Name)
+			}
+			relativeColumns = appendPositionColumn(relativeColumns, sub.Args[2].(*ast.ColumnPosition))
+			oldCol := model.FindColumnInfo(t.Meta().Columns, oldColName.L)
+			if oldCol != nil && needChangeColumnData(t.Meta(), oldCol, newCol.ColumnInfo) {
+				return errRunMultiSchemaChanges.FastGenByArgs("modify column with data reorganization")
+			}
+		case model.ActionSetDefaultValue:
+			modifyColumns = append(modifyColumns, sub.Args[0].(*table.Column).Name)
+		case model.ActionAddIndex, model.ActionAddPrimaryKey:
+			addIndexes = append(addIndexes, sub.Args[1].(model.CIStr))
+			for _, part := range sub.Args[2].([]*ast.IndexPartSpecification) {
+				if part.Column != nil {
+					relativeColumns = append(relativeColumns, part.Column.Name)
+				}
+			}
+		case model.ActionDropIndex, model.ActionDropPrimaryKey:
+			dropIndexes = append(dropIndexes, sub.Args[0].(model.CIStr))
+		case model.ActionRenameIndex:
+			alterIndexes = append(alterIndexes, sub.Args[0].(model.CIStr), sub.Args[1].(model.CIStr))
+		case model.ActionAlterIndexVisibility:
+			alterIndexes = append(alterIndexes, sub.Args[0].(model.CIStr))
+		case model.ActionAddForeignKey:
+			relativeColumns = append(relativeColumns, sub.Args[0].(*model.FKInfo).Cols...)
+		}
+	}
+	if name, ok := findDuplicateName(addColumns, dropColumns, modifyColumns); ok {
+		return ErrOperateSameColumn.GenWithStackByArgs(name)
+	}
+	if name, ok := findDuplicateName(addIndexes, dropIndexes, alterIndexes); ok {
+		return ErrOperateSameIndex.GenWithStackByArgs(name)
+	}
+	// The columns referenced by the positions or the new indexes must be stable.
+	changedColumns := make(map[string]struct{}, len(dropColumns)+len(modifyColumns))
+	for _, names := range [][]model.CIStr{dropColumns, modifyColumns} {
+		for _, name := range names {
+			changedColumns[name.L] = struct{}{}
+		}
+	}
+	for _, name := range relativeColumns {
+		if _, ok := changedColumns[name.L]; ok {
+			return ErrOperateSameColumn.GenWithStackByArgs(name.O)
+		}
+	}
+	return nil
+}
+
+func appendPositionColumn(names []model.CIStr, pos *ast.ColumnPosition) []model.CIStr {
+	if pos != nil && pos.Tp == ast.ColumnPositionAfter {
+		return append(names, pos.RelativeColumn.Name)
+	}
+	return names
+}
+
+// findDuplicateName returns the first name that appears more than once in the lists.
+func findDuplicateName(lists ...[]model.CIStr) (string, bool) {
+	seen := make(map[string]struct{})
+	for _, names := range lists {
+		for _, name := range names {
+			if _, ok := seen[name.L]; ok {
+				return name.O, true
+			}
+			seen[name.L] = struct{}{}
+		}
+	}
+	return "", false
+}
+
+// onMultiSchemaChange runs the sub-jobs of a multi-schema change job.
+// While the job is revertible, the sub-jobs are run one by one until each of them reaches
+// its last revertible state, so any failure can roll all of them back in reverse order.
+// After that, the remaining states of the sub-jobs are run one by one and can't be cancelled.
+func onMultiSchemaChange(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	subJobs := job.MultiSchemaInfo.SubJobs
+	if job.MultiSchemaInfo.Revertible {
+		// Handle the rolling back job.
+		if job.IsRollingback() {
+			// Roll back or cancel the sub-jobs in reverse order.
+			for i := len(subJobs) - 1; i >= 0; i-- {
+				sub := subJobs[i]
+				if sub.IsFinished() {
+					continue
+				}
+				proxyJob := sub.ToProxyJob(job)
+				ver, err = w.runDDLJob(d, t, proxyJob)
+				sub.FromProxyJob(proxyJob)
+				return ver, err
+			}
+			// All the sub-jobs are rolled back or cancelled.
+			if allSubJobsCancelled(job) {
+				job.State = model.JobStateCancelled
+			} else {
+				job.State = model.JobStateRollbackDone
+			}
+			return ver, nil
+		}
+
+		// Run the first sub-job that hasn't reached its last revertible state.
+		for _, sub := range subJobs {
+			if !sub.Revertible || sub.IsFinished() {
+				continue
+			}
+			proxyJob := sub.ToProxyJob(job)
+			ver, err = w.runDDLJob(d, t, proxyJob)
+			sub.FromProxyJob(proxyJob)
+			handleRevertibleException(job, sub, proxyJob.Error)
+			return ver, err
+		}
+		// All the sub-jobs are in their last revertible states.
+		job.MarkNonRevertible()
+	}
+
+	// Run the rest states of the sub-jobs one by one.
+	for i, sub := range subJobs {
+		if sub.IsFinished() {
+			continue
+		}
+		proxyJob := sub.ToProxyJob(job)
+		ver, err = w.runDDLJob(d, t, proxyJob)
+		sub.FromProxyJob(proxyJob)
+		if !sub.IsNormal() {
+			logutil.Logger(w.logCtx).Warn("[ddl] non-revertible sub-job of multi-schema change is not finished normally",
+				zap.Int64("jobID", job.ID), zap.Stringer("subJobType", sub.Type), zap.Stringer("state", sub.State), zap.Error(proxyJob.Error))
+		}
+		if err != nil || !sub.IsFinished() || i != len(subJobs)-1 {
+			return ver, errors.Trace(err)
+		}
+	}
+	// All the sub-jobs are done.
+	tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// handleRevertibleException rolls back the multi-schema change job if a sub-job fails in the revertible phase.
+func handleRevertibleException(job *model.Job, subJob *model.SubJob, err *terror.Error) {
+	if subJob.IsNormal() {
+		return
+	}
+	job.State = model.JobStateRollingback
+	job.Error = err
+	// The other sub-jobs are cancelled as well.
+	for _, sub := range job.MultiSchemaInfo.SubJobs {
+		switch sub.State {
+		case model.JobStateRunning:
+			sub.State = model.JobStateCancelling
+		case model.JobStateNone:
+			sub.State = model.JobStateCancelled
+		}
+	}
+	if allSubJobsCancelled(job) {
+		job.State = model.JobStateCancelled
+	}
+}
+
+func allSubJobsCancelled(job *model.Job) bool {
+	for _, sub := range job.MultiSchemaInfo.SubJobs {
+		if sub.State != model.JobStateCancelled {
+			return false
+		}
+	}
+	return true
+}
+
+func rollingbackMultiSchemaChange(job *model.Job) (ver int64, err error) {
+	if !job.MultiSchemaInfo.Revertible {
+		// The sub-jobs can't be rolled back any more, keep running them.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	for _, sub := range job.MultiSchemaInfo.SubJobs {
+		switch sub.State {
+		case model.JobStateRunning:
+			sub.State = model.JobStateCancelling
+		case model.JobStateNone:
+			sub.State = model.JobStateCancelled
+		}
+	}
+	if allSubJobsCancelled(job) {
+		job.State = model.JobStateCancelled
+	} else {
+		job.State = model.JobStateRollingback
+	}
+	return ver, errCancelledDDLJob
+}
+
+// checkAndMarkNonRevertible marks the sub-job of a multi-schema change as non-revertible
+// when it reaches its last revertible state. It returns true if the sub-job is just marked,
+// then the handler should wait for the other sub-jobs before going on.
+func checkAndMarkNonRevertible(job *model.Job) bool {
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		job.MarkNonRevertible()
+		return true
+	}
+	return false
+}
+
+// subJobNeedDeleteRange returns whether the data of the sub-job should be cleaned by delete-range.
+func subJobNeedDeleteRange(sub *model.SubJob) bool {
+	switch sub.Type {
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		// The half-done index of a rolled back sub-job.
+		return sub.State == model.JobStateRollbackDone
+	case model.ActionDropIndex, model.ActionDropPrimaryKey, model.ActionDropColumn:
+		return sub.State == model.JobStateDone
+	case model.ActionModifyColumn:
+		// The old indexes of a done sub-job, or the changing indexes of a rolled back one.
+		return sub.State == model.JobStateDone || sub.State == model.JobStateRollbackDone
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSchemaChangeAddColumnsAndIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 2)")
+	tk.MustExec("alter table t add column c int default 3, add column d int default 4 first, add index idx_c(c), drop column b")
+	tk.MustQuery("select * from t").Check(testkit.Rows("4 1 3"))
+	tk.MustQuery("select c from t use index(idx_c) where c = 3").Check(testkit.Rows("3"))
+	tk.MustExec("admin check table t")
+
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "alter table multi-schema change", rows[0][3])
+	require.Equal(t, "synced", rows[0][len(rows[0])-1])
+
+	tk.MustExec("alter table t add column e int after a, add column f int first, add column g int")
+	tk.MustQuery("select * from t").Check(testkit.Rows("<nil> 4 1 <nil> 3 <nil>"))
+	tk.MustQuery("select column_name from information_schema.columns where table_name = 't' order by ordinal_position").
+		Check(testkit.Rows("f", "d", "a", "e", "c", "g"))
+	tk.MustExec("admin check table t")
+}
+
+func TestMultiSchemaChangeRollback(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx_b(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 1)")
+
+	// The duplicate entries fail the unique index, so all the changes are rolled back.
+	tk.MustGetErrCode("alter table t add column c int, drop index idx_b, add unique index idx_a_b(b)", errno.ErrDupEntry)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "2 1"))
+	tk.MustExec("admin check table t")
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	require.Equal(t, "alter table multi-schema change", rows[0][3])
+	require.Equal(t, "rollback done", rows[0][len(rows[0])-1])
+}
+
+func TestMultiSchemaChangeInvalid(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int, index idx_b(b))")
+
+	tk.MustGetErrCode("alter table t modify column a bigint, alter column a set default 1", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t modify column a bigint, drop column a", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t add column d int after a, drop column a", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t add index idx_c(c), drop column c", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t drop index idx_b, rename index idx_b to idx_c", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t add column d int, rename to t1", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t add column d int, modify column a varchar(10)", errno.ErrUnsupportedDDLOperation)
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+}
+
+func TestMultiSchemaChangeNonReorg(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int default 1, c int, index idx_b(b), index idx_c(c))")
+	tk.MustExec("insert into t (a, c) values (1, 1)")
+	tk.MustExec("alter table t alter column b set default 2, rename index idx_b to idx_b1, alter index idx_c invisible, modify column a bigint not null")
+	tk.MustExec("insert into t (a, c) values (2, 2)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 1", "2 2 2"))
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` bigint(20) NOT NULL,\n" +
+		"  `b` int(11) DEFAULT '2',\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_b1` (`b`),\n" +
+		"  KEY `idx_c` (`c`) /*!80000 INVISIBLE */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("admin check table t")
+}
+
+func TestMultiSchemaChangeConvertCharset(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a varchar(10), b varchar(10), c int, index idx_a_b(a, b), index idx_c(c)) charset latin1")
+	tk.MustExec("insert into t values (1, 'café', 'x', 1), (2, 'abc', 0xE9, 2)")
+
+	// The invalid data of b fails the conversion, the converted a is rolled back as well.
+	tk.MustGetErrMsg("alter table t convert to charset utf8mb4", "[table:1366]Incorrect string value '\\xE9' for column 'b'")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `a` varchar(10) DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `idx_a_b` (`a`,`b`),\n" +
+		"  KEY `idx_c` (`c`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_bin"))
+	tk.MustExec("admin check table t")
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	require.Equal(t, "alter table multi-schema change", rows[0][3])
+	require.Equal(t, "rollback done", rows[0][len(rows[0])-1])
+
+	// All the columns and the table are converted by one job.
+	tk.MustExec("update t set b = 'y' where id = 2")
+	tk.MustExec("alter table t convert to charset utf8mb4")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `a` varchar(10) DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `idx_a_b` (`a`,`b`),\n" +
+		"  KEY `idx_c` (`c`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	rows = tk.MustQuery("admin show ddl jobs 1").Rows()
+	require.Equal(t, "alter table multi-schema change", rows[0][3])
+	require.Equal(t, "synced", rows[0][len(rows[0])-1])
+	tk.MustQuery("select id from t use index(idx_a_b) where a = 'café' and b = 'x'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a, b from t use index(idx_a_b) order by a").Check(testkit.Rows("abc y", "café x"))
+	tk.MustExec("insert into t values (3, 'ü', 'z', 3)")
+	tk.MustExec("admin check table t")
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser"
@@ -41,7 +42,9 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -50,8 +53,11 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/slice"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -384,6 +390,12 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 		if !s.Linear && s.Sub == nil {
 			enable = true
 		}
+	case model.PartitionTypeKey:
+		// Partition by key is enabled by default.
+		// Note that linear key and the key algorithm of MySQL 5.1 are not enabled.
+		if !s.Linear && s.Sub == nil && (s.KeyAlgorithm == nil || s.KeyAlgorithm.Type == 2) {
+			enable = true
+		}
 	case model.PartitionTypeList:
 		// Partition by list is enabled only when tidb_enable_list_partition is 'ON'.
 		enable = ctx.GetSessionVars().EnableListTablePartition
@@ -415,12 +427,27 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 		for _, cn := range s.ColumnNames {
 			pi.Columns = append(pi.Columns, cn.Name)
 		}
-		if err := checkColumnsPartitionType(tbInfo); err != nil {
-			return err
+		if s.Tp != model.PartitionTypeKey {
+			if err := checkColumnsPartitionType(tbInfo); err != nil {
+				return err
+			}
+		}
+	}
+	if s.Tp == model.PartitionTypeKey {
+		if err := buildKeyPartitionColumns(tbInfo); err != nil {
+			return errors.Trace(err)
 		}
 	}
 
-	defs, err := buildPartitionDefinitionsInfo(ctx, s.Definitions, tbInfo)
+	partDefs := s.Definitions
+	if s.Interval != nil {
+		var err error
+		if partDefs, err = buildPartitionIntervalInfo(ctx, s, tbInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	defs, err := buildPartitionDefinitionsInfo(ctx, partDefs, tbInfo)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -429,12 +456,56 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 	return nil
 }
 
+// buildKeyPartitionColumns resolves and checks the partitioning columns of a KEY partitioned table.
+// Like MySQL, the primary key is used when no column is given, or else the first unique key on NOT NULL columns.
+func buildKeyPartitionColumns(tbInfo *model.TableInfo) error {
+	pi := tbInfo.Partition
+	if len(pi.Columns) == 0 {
+		pi.Columns = findDefaultKeyPartitionColumns(tbInfo)
+		if len(pi.Columns) == 0 {
+			return errors.Trace(ErrFieldNotFoundPart)
+		}
+	}
+	for i, col := range pi.Columns {
+		colInfo := getColumnInfoByName(tbInfo, col.L)
+		if colInfo == nil {
+			return errors.Trace(ErrFieldNotFoundPart)
+		}
+		for _, prev := range pi.Columns[:i] {
+			if prev.L == col.L {
+				return errors.Trace(ErrSameNamePartitionField.GenWithStackByArgs(col.O))
+			}
+		}
+		if !tables.IsKeyPartitionColumnType(&colInfo.FieldType) {
+			return errors.Trace(ErrNotAllowedTypeInPartition.GenWithStackByArgs(col.O))
+		}
+	}
+	return nil
+}
+
+func findDefaultKeyPartitionColumns(tbInfo *model.TableInfo) []model.CIStr {
+	if tbInfo.PKIsHandle {
+		if pkCol := tbInfo.GetPkColInfo(); pkCol != nil {
+			return []model.CIStr{pkCol.Name}
+		}
+	}
+	pk := getPrimaryKey(tbInfo)
+	if pk == nil {
+		return nil
+	}
+	names := make([]model.CIStr, 0, len(pk.Columns))
+	for _, idxCol := range pk.Columns {
+		names = append(names, idxCol.Name)
+	}
+	return names
+}
+
 // buildPartitionDefinitionsInfo build partition definitions info without assign partition id. tbInfo will be constant
 func buildPartitionDefinitionsInfo(ctx sessionctx.Context, defs []*ast.PartitionDefinition, tbInfo *model.TableInfo) (partitions []model.PartitionDefinition, err error) {
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
 		partitions, err = buildRangePartitionDefinitions(ctx, defs, tbInfo)
-	case model.PartitionTypeHash:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
 		partitions, err = buildHashPartitionDefinitions(ctx, defs, tbInfo)
 	case model.PartitionTypeList:
 		partitions, err = buildListPartitionDefinitions(ctx, defs, tbInfo)
@@ -696,8 +767,8 @@ func checkAndOverridePartitionID(newTableInfo, oldTableInfo *model.TableInfo) er
 	if newTableInfo.Partition.Type != oldTableInfo.Partition.Type {
 		return ErrRepairTableFail.GenWithStackByArgs("Partition type should be the same")
 	}
-	// Check whether partitionType is hash partition.
-	if newTableInfo.Partition.Type == model.PartitionTypeHash {
+	// Check whether partitionType is hash or key partition.
+	if newTableInfo.Partition.Type == model.PartitionTypeHash || newTableInfo.Partition.Type == model.PartitionTypeKey {
 		if newTableInfo.Partition.Num != oldTableInfo.Partition.Num {
 			return ErrRepairTableFail.GenWithStackByArgs("Hash partition num should be the same")
 		}
@@ -1002,6 +1073,12 @@ func getPartitionIDsFromDefinitions(defs []model.PartitionDefinition) []int64 {
 	return pids
 }
 
+// isGlobalIndexEnabled checks whether the unique keys that do not include all the partitioning columns
+// can be created as global indexes.
+func isGlobalIndexEnabled(ctx sessionctx.Context) bool {
+	return ctx.GetSessionVars().EnableGlobalIndex || config.GetGlobalConfig().EnableGlobalIndex
+}
+
 func hasGlobalIndex(tblInfo *model.TableInfo) bool {
 	for _, idxInfo := range tblInfo.Indices {
 		if idxInfo.Global {
@@ -1025,6 +1102,20 @@ func getTableInfoWithDroppingPartitions(t *model.TableInfo) *model.TableInfo {
 	return nt
 }
 
+// getTableInfoWithExtraPartitions returns a copy of the table info with the extra partition definitions,
+// it is used to read the partitions which are not in the table any more, e.g. the truncated partitions.
+func getTableInfoWithExtraPartitions(t *model.TableInfo, defs []model.PartitionDefinition) *model.TableInfo {
+	p := t.Partition
+	nt := t.Clone()
+	np := *p
+	npd := make([]model.PartitionDefinition, 0, len(p.Definitions)+len(defs))
+	npd = append(npd, p.Definitions...)
+	npd = append(npd, defs...)
+	np.Definitions = npd
+	nt.Partition = &np
+	return nt
+}
+
 func dropLabelRules(d *ddlCtx, schemaName, tableName string, partNames []string) error {
 	deleteRules := make([]string, 0, len(partNames))
 	for _, partName := range partNames {
@@ -1157,220 +1248,1223 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	return ver, errors.Trace(err)
 }
 
-// onTruncateTablePartition truncates old partition meta.
-func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
-	var ver int64
-	var oldIDs []int64
-	if err := job.DecodeArgs(&oldIDs); err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
+// checkReorganizePartition checks the partitions to reorganize exist and are consecutive, and the new
+// partitions can replace them. The range of the new range partitions must be the same as the old ones,
+// except that the range of the last partition of the table can be extended.
+func checkReorganizePartition(ctx sessionctx.Context, tblInfo *model.TableInfo, partNames []string, partInfo *model.PartitionInfo) error {
+	pi := tblInfo.Partition
+	first, last, err := findReorganizedPartitions(pi, partNames)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)-(last-first+1)+len(partInfo.Definitions))
+	defs = append(defs, pi.Definitions[:first]...)
+	defs = append(defs, partInfo.Definitions...)
+	defs = append(defs, pi.Definitions[last+1:]...)
+	clonedMeta := tblInfo.Clone()
+	tmp := *pi
+	tmp.Definitions = defs
+	tmp.Num = uint64(len(defs))
+	clonedMeta.Partition = &tmp
+	if err = checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if pi.Type != model.PartitionTypeRange {
+		return nil
+	}
+	cmp, err := compareRangeBound(ctx, tblInfo, &partInfo.Definitions[len(partInfo.Definitions)-1], &pi.Definitions[last])
 	if err != nil {
-		return ver, errors.Trace(err)
+		return errors.Trace(err)
 	}
-	pi := tblInfo.GetPartitionInfo()
-	if pi == nil {
-		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	if cmp == 0 || (cmp > 0 && last == len(pi.Definitions)-1) {
+		return nil
 	}
+	return errors.Trace(ErrReorgOutsideRange)
+}
 
-	newPartitions := make([]model.PartitionDefinition, 0, len(oldIDs))
-	for _, oldID := range oldIDs {
-		for i := 0; i < len(pi.Definitions); i++ {
-			def := &pi.Definitions[i]
-			if def.ID == oldID {
-				pid, err1 := t.GenGlobalID()
-				if err1 != nil {
-					return ver, errors.Trace(err1)
-				}
-				def.ID = pid
-				// Shallow copy only use the def.ID in event handle.
-				newPartitions = append(newPartitions, *def)
+// findReorganizedPartitions returns the offsets of the first and the last partitions to reorganize.
+func findReorganizedPartitions(pi *model.PartitionInfo, partNames []string) (first, last int, _ error) {
+	first, last = len(pi.Definitions), -1
+	for i, name := range partNames {
+		idx := -1
+		for j := range pi.Definitions {
+			if pi.Definitions[j].Name.L == name {
+				idx = j
 				break
 			}
 		}
+		if idx < 0 || slice.AnyOf(partNames[:i], func(k int) bool { return partNames[k] == name }) {
+			return 0, 0, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+		first = mathutil.Min(first, idx)
+		last = mathutil.Max(last, idx)
 	}
-	if len(newPartitions) == 0 {
-		job.State = model.JobStateCancelled
-		return ver, table.ErrUnknownPartition.GenWithStackByArgs(fmt.Sprintf("pid:%v", oldIDs), tblInfo.Name.O)
+	if last-first+1 != len(partNames) {
+		return 0, 0, errors.Trace(ErrConsecutiveReorgPartitions)
 	}
+	return first, last, nil
+}
 
-	// Clear the tiflash replica available status.
-	if tblInfo.TiFlashReplica != nil {
-		tblInfo.TiFlashReplica.Available = false
-		// Set partition replica become unavailable.
-		for _, oldID := range oldIDs {
-			for i, id := range tblInfo.TiFlashReplica.AvailablePartitionIDs {
-				if id == oldID {
-					newIDs := tblInfo.TiFlashReplica.AvailablePartitionIDs[:i]
-					newIDs = append(newIDs, tblInfo.TiFlashReplica.AvailablePartitionIDs[i+1:]...)
-					tblInfo.TiFlashReplica.AvailablePartitionIDs = newIDs
-					break
-				}
-			}
+// compareRangeBound compares the upper bounds of two range partitions.
+func compareRangeBound(ctx sessionctx.Context, tblInfo *model.TableInfo, a, b *model.PartitionDefinition) (int, error) {
+	pi := tblInfo.Partition
+	if len(pi.Columns) > 0 {
+		greater, err := checkTwoRangeColumns(ctx, a, b, pi, tblInfo)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		less, err := checkTwoRangeColumns(ctx, b, a, pi, tblInfo)
+		if err != nil {
+			return 0, errors.Trace(err)
 		}
+		switch {
+		case greater && !less:
+			return 1, nil
+		case less && !greater:
+			return -1, nil
+		}
+		return 0, nil
 	}
 
-	bundles, err := placement.NewPartitionListBundles(t, newPartitions)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
+	aMax, bMax := strings.EqualFold(a.LessThan[0], partitionMaxValue), strings.EqualFold(b.LessThan[0], partitionMaxValue)
+	switch {
+	case aMax && bMax:
+		return 0, nil
+	case aMax:
+		return 1, nil
+	case bMax:
+		return -1, nil
 	}
-
-	err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
+	isUnsigned := isColUnsigned(tblInfo.Columns, pi)
+	aVal, _, err := getRangeValue(ctx, a.LessThan[0], isUnsigned)
 	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
-	}
-
-	tableID := fmt.Sprintf(label.TableIDFormat, label.IDPrefix, job.SchemaName, tblInfo.Name.L)
-	oldPartRules := make([]string, 0, len(oldIDs))
-	for _, newPartition := range newPartitions {
-		oldPartRuleID := fmt.Sprintf(label.PartitionIDFormat, label.IDPrefix, job.SchemaName, tblInfo.Name.L, newPartition.Name.L)
-		oldPartRules = append(oldPartRules, oldPartRuleID)
+		return 0, errors.Trace(err)
 	}
-
-	rules, err := infosync.GetLabelRules(context.TODO(), append(oldPartRules, tableID))
+	bVal, _, err := getRangeValue(ctx, b.LessThan[0], isUnsigned)
 	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to get label rules from PD")
+		return 0, errors.Trace(err)
 	}
-
-	newPartIDs := getPartitionIDs(tblInfo)
-	newRules := make([]*label.Rule, 0, len(oldIDs)+1)
-	if tr, ok := rules[tableID]; ok {
-		newRules = append(newRules, tr.Clone().Reset(job.SchemaName, tblInfo.Name.L, "", append(newPartIDs, tblInfo.ID)...))
+	if isUnsigned {
+		return types.CompareUint64(aVal.(uint64), bVal.(uint64)), nil
 	}
+	return types.CompareInt64(aVal.(int64), bVal.(int64)), nil
+}
 
-	for idx, newPartition := range newPartitions {
-		if pr, ok := rules[oldPartRules[idx]]; ok {
-			newRules = append(newRules, pr.Clone().Reset(job.SchemaName, tblInfo.Name.L, newPartition.Name.L, newPartition.ID))
+// checkReorganizePartitionTable checks whether the partitions of the table can still be reorganized into
+// partInfo when the job starts, since the table may be changed after the job is submitted.
+func checkReorganizePartitionTable(tblInfo *model.TableInfo, tp model.ActionType, partInfo *model.PartitionInfo) error {
+	switch tp {
+	case model.ActionAlterTablePartitioning:
+		if hasGlobalIndex(tblInfo) || tblInfo.TiFlashReplica != nil {
+			return errors.Trace(errUnsupportedAlterTablePartitioning)
+		}
+		return errors.Trace(checkPartitioningKeysOfTable(tblInfo, partInfo))
+	case model.ActionRemovePartitioning:
+		if hasGlobalIndex(tblInfo) || tblInfo.TiFlashReplica != nil {
+			return errors.Trace(errUnsupportedRemovePartition)
+		}
+	default:
+		if hasGlobalIndex(tblInfo) {
+			return errors.Trace(errUnsupportedReorganizePartition)
 		}
 	}
-
-	patch := label.NewRulePatch(newRules, []string{})
-	err = infosync.UpdateLabelRules(context.TODO(), patch)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Wrapf(err, "failed to notify PD the label rules")
-	}
-
-	newIDs := make([]int64, len(oldIDs))
-	for i := range oldIDs {
-		newIDs[i] = newPartitions[i].ID
-	}
-	job.CtxVars = []interface{}{oldIDs, newIDs}
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
-	if err != nil {
-		return ver, errors.Trace(err)
-	}
-
-	// Finish this job.
-	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
-	asyncNotifyEvent(d, &util.Event{Tp: model.ActionTruncateTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: newPartitions}})
-	// A background job will be created to delete old partition data.
-	job.Args = []interface{}{oldIDs}
-	return ver, nil
+	return nil
 }
 
-// onExchangeTablePartition exchange partition data
-func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
-	var (
-		// defID only for updateSchemaVersion
-		defID          int64
-		ptSchemaID     int64
-		ptID           int64
-		partName       string
-		withValidation bool
-	)
-
-	if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation); err != nil {
+// onReorganizePartition reorganizes the partitions into the new ones.
+// The new partitions are added as the AddingDefinitions, and the old ones are marked as the DroppingDefinitions.
+// The DML writes the records to both of them in the mid states, while the records of the old partitions are
+// copied to the new ones in the write reorganization state. Then the new partitions replace the old ones,
+// and the old partitions are removed by the delete range after all the TiDB servers know it.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	if err := job.DecodeArgs(&partNames, &partInfo); err != nil {
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
-
-	ntDbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
 	if err != nil {
-		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
-
-	nt, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
-	if err != nil {
-		return ver, errors.Trace(err)
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(t, job, tblInfo)
 	}
 
-	pt, err := getTableInfo(t, ptID, ptSchemaID)
-	if err != nil {
-		if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err) {
+	pi := tblInfo.GetPartitionInfo()
+	switch job.SchemaState {
+	case model.StateNone:
+		if err = checkReorganizePartitionTable(tblInfo, job.Type, partInfo); err != nil {
 			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
 		}
-		return ver, errors.Trace(err)
-	}
-
-	if pt.State != model.StatePublic {
-		job.State = model.JobStateCancelled
-		return ver, ErrInvalidDDLState.GenWithStack("table %s is not in public, but %s", pt.Name, pt.State)
-	}
-
-	err = checkExchangePartition(pt, nt)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
-
-	err = checkTableDefCompatible(pt, nt)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
-
-	index, _, err := getPartitionDef(pt, partName)
-	if err != nil {
-		return ver, errors.Trace(err)
-	}
+		switch job.Type {
+		case model.ActionReorganizePartition:
+			first, last, err := findReorganizedPartitions(pi, partNames)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+			pi.DroppingDefinitions = append([]model.PartitionDefinition{}, pi.Definitions[first:last+1]...)
+		default:
+			if pi == nil {
+				if job.Type == model.ActionRemovePartitioning {
+					job.State = model.JobStateCancelled
+					return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+				}
+				// The non-partitioned table is reorganized as a table with only one partition.
+				pi = newFullTablePartitionInfo(tblInfo)
+				tblInfo.Partition = pi
+			}
+			pi.DroppingDefinitions = append([]model.PartitionDefinition{}, pi.Definitions...)
+		}
+		for _, def := range partInfo.Definitions {
+			if _, err = checkPlacementPolicyRefValidAndCanNonValidJob(t, job, def.PlacementPolicyRef); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+		pi.AddingDefinitions = partInfo.Definitions
+		pi.DDLType, pi.DDLExpr, pi.DDLColumns = partInfo.Type, partInfo.Expr, partInfo.Columns
+		if job.Type == model.ActionReorganizePartition {
+			pi.DDLType, pi.DDLExpr, pi.DDLColumns = pi.Type, pi.Expr, pi.Columns
+		}
+		pi.DDLAction = job.Type
+		pi.DDLState = model.StateDeleteOnly
 
-	if withValidation {
-		err = checkExchangePartitionRecordValidation(w, pt, index, ntDbInfo.Name, nt.Name)
+		bundles, err := alterTablePartitionBundles(t, tblInfo, pi.AddingDefinitions)
 		if err != nil {
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
-	}
-
-	// partition table auto IDs.
-	ptAutoIDs, err := t.GetAutoIDAccessors(ptSchemaID, ptID).Get()
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
-	// non-partition table auto IDs.
-	ntAutoIDs, err := t.GetAutoIDAccessors(job.SchemaID, nt.ID).Get()
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
-
-	_, partDef, err := getPartitionDef(pt, partName)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
-
-	if pt.TiFlashReplica != nil {
-		for i, id := range pt.TiFlashReplica.AvailablePartitionIDs {
-			if id == partDef.ID {
-				pt.TiFlashReplica.AvailablePartitionIDs[i] = nt.ID
-				break
-			}
+		if err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
 		}
-	}
-
-	// exchange table meta id
-	partDef.ID, nt.ID = nt.ID, partDef.ID
 
-	err = t.UpdateTable(ptSchemaID, pt)
-	if err != nil {
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	case model.StateDeleteOnly:
+		// The new partitions are only removed from by the DML in this state, it makes sure all the TiDB
+		// servers know the new partitions before the records are written to them.
+		pi.DDLState = model.StateWriteOnly
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteOnly:
+		pi.DDLState = model.StateWriteReorganization
+		job.SchemaState = model.StateWriteReorganization
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteReorganization:
+		if tblInfo.TiFlashReplica != nil && tblInfo.TiFlashReplica.Available {
+			// The new partitions should wait for their replicas to be finished,
+			// otherwise the queries to them will be blocked.
+			needRetry, err := checkPartitionReplica(tblInfo.TiFlashReplica.Count, pi.AddingDefinitions, d)
+			if err != nil {
+				job.State = model.JobStateRollingback
+				return ver, errors.Trace(err)
+			}
+			if needRetry {
+				time.Sleep(tiflashCheckTiDBHTTPAPIHalfInterval)
+				return ver, errors.Errorf("[ddl] reorganize partition wait for tiflash replica to complete")
+			}
+		}
+
+		tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		// Build elements for compatible with the reorg handle, the element will not be used when reorganizing.
+		elements := []*meta.Element{{ID: pi.AddingDefinitions[0].ID, TypeKey: meta.ColumnElementKey}}
+		reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+		if err != nil || reorgInfo.first {
+			// If we run reorg firstly, we should update the job snapshot version
+			// and then run the reorg next time.
+			return ver, errors.Trace(err)
+		}
+		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (reorgErr error) {
+			defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+				func() {
+					reorgErr = errCancelledDDLJob.GenWithStack("reorganize partition panic")
+				}, false)
+			return w.reorgPartitionData(tbl.(table.PartitionedTable), physicalTableIDs, reorgInfo)
+		})
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// If timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			if kv.IsTxnRetryableError(err) {
+				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+				w.reorgCtx.cleanNotifyReorgCancel()
+				return ver, errors.Trace(err)
+			}
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+					zap.String("job", job.String()), zap.Error(err1))
+			}
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+
+		// The new partitions replace the old ones. The old ones are still written by the DML
+		// until all the TiDB servers don't read them.
+		newPi := tables.ReorganizedTableInfo(tblInfo).Partition
+		pi.Definitions, pi.Num = newPi.Definitions, newPi.Num
+		pi.Type, pi.DDLType = pi.DDLType, pi.Type
+		pi.Expr, pi.DDLExpr = pi.DDLExpr, pi.Expr
+		pi.Columns, pi.DDLColumns = pi.DDLColumns, pi.Columns
+		if job.Type != model.ActionReorganizePartition {
+			// The INTERVAL config belongs to the new partitioning method.
+			pi.Interval = partInfo.Interval
+		}
+		pi.DDLState = model.StateDeleteReorganization
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateDeleteReorganization:
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		droppedNames := make([]string, 0, len(pi.DroppingDefinitions))
+		for _, def := range pi.DroppingDefinitions {
+			if _, _, err := getPartitionDef(tblInfo, def.Name.L); err != nil {
+				droppedNames = append(droppedNames, def.Name.L)
+			}
+		}
+		if err = dropLabelRules(d, job.SchemaName, tblInfo.Name.L, droppedNames); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the label rules")
+		}
+		pi.AddingDefinitions = nil
+		pi.DroppingDefinitions = nil
+		pi.DDLState = model.StateNone
+		pi.DDLAction = model.ActionNone
+		pi.DDLType, pi.DDLExpr, pi.DDLColumns = model.PartitionTypeNone, "", nil
+		if pi.Type == model.PartitionTypeNone {
+			tblInfo.Partition = nil
+		}
+
+		// The bundle of the table should be recomputed because it includes the configs of the partitions.
+		tblBundle, err := placement.NewTableBundle(t, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if tblBundle != nil {
+			if err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), []*placement.Bundle{tblBundle}); err != nil {
+				return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+			}
+		}
+
+		// used by ApplyDiff in updateSchemaVersion
+		job.CtxVars = []interface{}{excludeTableID(physicalTableIDs, tblInfo.ID)}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		asyncNotifyEvent(d, &util.Event{Tp: job.Type, TableInfo: tblInfo, PartInfo: partInfo})
+		// A background job will be created to delete old partition data.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// rollbackReorganizePartition removes the new partitions of a rolled back REORGANIZE PARTITION,
+// the records written to them are removed by the delete range.
+func rollbackReorganizePartition(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	physicalTableIDs, _, rollbackBundles := rollbackAddingPartitionInfo(tblInfo)
+	pi := tblInfo.Partition
+	pi.DroppingDefinitions = nil
+	pi.DDLState = model.StateNone
+	pi.DDLAction = model.ActionNone
+	pi.DDLType, pi.DDLExpr, pi.DDLColumns = model.PartitionTypeNone, "", nil
+	if pi.Type == model.PartitionTypeNone {
+		tblInfo.Partition = nil
+	}
+
+	tblBundle, err := placement.NewTableBundle(t, tblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tblBundle != nil {
+		rollbackBundles = append(rollbackBundles, tblBundle)
+	}
+	if err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), rollbackBundles); err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	// used by ApplyDiff in updateSchemaVersion
+	job.CtxVars = []interface{}{excludeTableID(physicalTableIDs, tblInfo.ID)}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
+
+// fullTablePartitionName is the name of the only partition of a non-partitioned table being converted
+// from or to a partitioned table.
+const fullTablePartitionName = "pFullTable"
+
+// newFullTablePartitionInfo returns the partition info which has the whole table as its only partition,
+// the partition has the same ID as the table, so the records of the non-partitioned table are in it.
+func newFullTablePartitionInfo(tblInfo *model.TableInfo) *model.PartitionInfo {
+	return &model.PartitionInfo{
+		Type:        model.PartitionTypeNone,
+		Enable:      true,
+		Definitions: []model.PartitionDefinition{{ID: tblInfo.ID, Name: model.NewCIStr(fullTablePartitionName)}},
+		Num:         1,
+	}
+}
+
+// excludeTableID removes the table ID from the IDs of the dropped partitions. The table ID is one of
+// them when the table is converted from or to a non-partitioned table, its records are removed, but
+// its placement rules are kept.
+func excludeTableID(physicalTableIDs []int64, tableID int64) []int64 {
+	ids := make([]int64, 0, len(physicalTableIDs))
+	for _, id := range physicalTableIDs {
+		if id != tableID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// checkPartitioningKeysOfTable checks every unique key of the table includes all the columns in the
+// partitioning expression, the global indexes can't be built by ALTER TABLE ... PARTITION BY.
+func checkPartitioningKeysOfTable(tblInfo *model.TableInfo, pi *model.PartitionInfo) error {
+	for _, idx := range tblInfo.Indices {
+		if !idx.Unique {
+			continue
+		}
+		ok, err := checkPartitionKeysConstraint(pi, idx.Columns, tblInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			if idx.Primary {
+				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			}
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+	}
+	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
+	if tblInfo.PKIsHandle {
+		indexCols := []*model.IndexColumn{{
+			Name:   tblInfo.GetPkName(),
+			Length: types.UnspecifiedLength,
+		}}
+		ok, err := checkPartitionKeysConstraint(pi, indexCols, tblInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+		}
+	}
+	return nil
+}
+
+// reorgPartitionData copies the records of the reorganized partitions to the new partitions.
+func (w *worker) reorgPartitionData(tbl table.PartitionedTable, partitionIDs []int64, reorgInfo *reorgInfo) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, partitionIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+type reorgPartitionRecord struct {
+	key       kv.Key // The record key in the reorganized partition.
+	newKey    kv.Key // The record key in the new partition.
+	handle    kv.Handle
+	vals      []byte
+	row       []types.Datum
+	partition table.PhysicalTable
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	// reorgedTbl is the table with the new partition layout, the records are copied to its partitions.
+	reorgedTbl    table.PartitionedTable
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*reorgPartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo) (*reorgPartitionWorker, error) {
+	reorgedTbl, err := getTable(reorgInfo.d.store, reorgInfo.Job.SchemaID, tables.ReorganizedTableInfo(t.Meta()))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgedTbl:     reorgedTbl.(table.PartitionedTable),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+// BackfillDataInTxn copies the records in the handle range to the new partitions, and creates the index entries for them.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		newKeys := make([]kv.Key, 0, len(rowRecords))
+		for _, record := range rowRecords {
+			newKeys = append(newKeys, record.newKey)
+		}
+		found, err := txn.BatchGet(ctx, newKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+			// The record is already written to the new partition by the DML, skip it.
+			if _, ok := found[string(record.newKey)]; ok {
+				continue
+			}
+			// Lock the record in the reorganized partition, so the concurrent DML on it conflicts with the copying.
+			if err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key); err != nil {
+				return errors.Trace(err)
+			}
+			if err = txn.Set(record.newKey, record.vals); err != nil {
+				return errors.Trace(err)
+			}
+			for _, idx := range record.partition.Indices() {
+				if idx.Meta().Primary && w.table.Meta().IsCommonHandle {
+					continue
+				}
+				vals, err := idx.FetchValues(record.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				rsData := tables.TryGetHandleRestoredDataWrapper(w.table, record.row, nil, idx.Meta())
+				handle, err := idx.Create(w.sessCtx, txn, vals, record.handle, rsData)
+				if err != nil {
+					if kv.ErrKeyExists.Equal(err) && record.handle.Equal(handle) {
+						continue
+					}
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ReorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.rowRecords, nextKey, taskDone, errors.Trace(err)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[i] = val
+	}
+	w.cleanRowMap()
+
+	p, err := w.reorgedTbl.GetPartitionByRow(w.sessCtx, row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowRecords = append(w.rowRecords, &reorgPartitionRecord{
+		key:       recordKey,
+		newKey:    tablecodec.EncodeRecordKey(p.RecordPrefix(), handle),
+		handle:    handle,
+		vals:      append([]byte{}, rawRow...),
+		row:       row,
+		partition: p,
+	})
+	return nil
+}
+
+func (w *reorgPartitionWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+// exchangeGlobalIndexData rebuilds the global index entries of the records in the exchanged partition.
+func (w *worker) exchangeGlobalIndexData(tbl table.PartitionedTable, physicalID int64, reorgInfo *reorgInfo) error {
+	p := tbl.GetPartition(physicalID)
+	if p == nil {
+		return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", physicalID, tbl.Meta().ID)
+	}
+	logutil.BgLogger().Info("[ddl] start to rebuild global indexes for exchanged partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+	return w.writePhysicalTableRecord(p, typeExchangePartitionWorker, nil, nil, nil, reorgInfo)
+}
+
+type exchangePartitionRecord struct {
+	key    kv.Key
+	handle kv.Handle
+	row    []types.Datum
+}
+
+type exchangePartitionWorker struct {
+	*backfillWorker
+	// globalIndexes are the global indexes of the partitioned table.
+	globalIndexes []table.Index
+	// localIndexes are the indexes of the non-partitioned table which are the same as the global indexes,
+	// they are only set when the records are exchanged out of the partitioned table.
+	localIndexes  []table.Index
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*exchangePartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+func newExchangePartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo) *exchangePartitionWorker {
+	exchangeOut := reorgInfo.Job.SchemaState == model.StateDeleteReorganization
+	globalIndexes := make([]table.Index, 0, len(t.Indices()))
+	var localIndexes []table.Index
+	for _, idx := range t.Indices() {
+		if !idx.Meta().Global {
+			continue
+		}
+		globalIndexes = append(globalIndexes, idx)
+		if exchangeOut {
+			idxInfo := idx.Meta().Clone()
+			idxInfo.Global = false
+			localIndexes = append(localIndexes, tables.NewIndex(t.GetPhysicalID(), t.Meta(), idxInfo))
+		}
+	}
+	return &exchangePartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		globalIndexes:  globalIndexes,
+		localIndexes:   localIndexes,
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("exchange_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}
+}
+
+func (w *exchangePartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+// BackfillDataInTxn adds the global index entries for the records in the handle range when they are exchanged in,
+// or moves the entries to the indexes of the non-partitioned table when they are exchanged out.
+func (w *exchangePartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		sc := w.sessCtx.GetSessionVars().StmtCtx
+		physicalID := w.table.(table.PhysicalTable).GetPhysicalID()
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+			// Lock the record, so the concurrent DML on it conflicts with the rebuilding.
+			if err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key); err != nil {
+				return errors.Trace(err)
+			}
+			for i, idx := range w.globalIndexes {
+				vals, err := idx.FetchValues(record.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if w.localIndexes == nil {
+					rsData := tables.TryGetHandleRestoredDataWrapper(w.table, record.row, nil, idx.Meta())
+					handle, err := idx.Create(w.sessCtx, txn, vals, record.handle, rsData)
+					if err == nil {
+						continue
+					}
+					if !kv.ErrKeyExists.Equal(err) {
+						return errors.Trace(err)
+					}
+					// The records of the other partitions may have the same handle,
+					// so the entry is skipped only if it is added for the record itself.
+					if record.handle.Equal(handle) {
+						pid, found, err := getGlobalIndexEntryPartitionID(ctx, sc, txn, idx, vals, record.handle)
+						if err != nil {
+							return errors.Trace(err)
+						}
+						if found && pid == physicalID {
+							continue
+						}
+					}
+					// The records are checked under the fence before the exchange, the duplicated entry
+					// means the global index is inconsistent with the records.
+					return kv.ErrKeyExists.FastGenByArgs(genExchangeIndexValueStr(vals), idx.Meta().Name.O)
+				}
+				// The entry may have been overwritten by the records of other partitions.
+				pid, found, err := getGlobalIndexEntryPartitionID(ctx, sc, txn, idx, vals, record.handle)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if found && pid == physicalID {
+					if err = idx.Delete(sc, txn, vals, record.handle); err != nil {
+						return errors.Trace(err)
+					}
+				}
+				rsData := tables.TryGetHandleRestoredDataWrapper(w.table, record.row, nil, idx.Meta())
+				handle, err := w.localIndexes[i].Create(w.sessCtx, txn, vals, record.handle, rsData)
+				if err != nil && !(kv.ErrKeyExists.Equal(err) && record.handle.Equal(handle)) {
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ExchangePartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
+// getGlobalIndexEntryPartitionID returns the partition ID in the global index entry of the values and the handle.
+func getGlobalIndexEntryPartitionID(ctx context.Context, sc *stmtctx.StatementContext, txn kv.Transaction, idx table.Index, vals []types.Datum, h kv.Handle) (int64, bool, error) {
+	key, _, err := idx.GenIndexKey(sc, vals, h, nil)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	val, err := txn.Get(ctx, key)
+	if err != nil {
+		if kv.IsErrNotFound(err) {
+			return 0, false, nil
+		}
+		return 0, false, errors.Trace(err)
+	}
+	pid, _, err := tablecodec.DecodePartitionIDInIndexValue(val)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	return pid, true, nil
+}
+
+// genExchangeIndexValueStr formats the index values in the same way as checkExchangePartitionGlobalIndexes.
+func genExchangeIndexValueStr(vals []types.Datum) string {
+	strs := make([]string, 0, len(vals))
+	for _, val := range vals {
+		if val.IsNull() {
+			strs = append(strs, "NULL")
+			continue
+		}
+		str, err := val.ToString()
+		if err != nil {
+			str = val.String()
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, "-")
+}
+
+func (w *exchangePartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*exchangePartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in exchangePartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.rowRecords, nextKey, taskDone, errors.Trace(err)
+}
+
+func (w *exchangePartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[i] = val
+	}
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+	w.rowRecords = append(w.rowRecords, &exchangePartitionRecord{
+		key:    recordKey,
+		handle: handle,
+		row:    row,
+	})
+	return nil
+}
+
+// onTruncateTablePartition truncates old partition meta.
+func (w *worker) onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
+	var ver int64
+	var oldIDs, newIDs []int64
+	if err := job.DecodeArgs(&oldIDs, &newIDs); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if job.SchemaState == model.StateDeleteReorganization {
+		return w.cleanupTruncatedPartitions(d, t, job, tblInfo, oldIDs, newIDs)
+	}
+
+	newPartitions := make([]model.PartitionDefinition, 0, len(oldIDs))
+	for _, oldID := range oldIDs {
+		for i := 0; i < len(pi.Definitions); i++ {
+			def := &pi.Definitions[i]
+			if def.ID == oldID {
+				pid, err1 := t.GenGlobalID()
+				if err1 != nil {
+					return ver, errors.Trace(err1)
+				}
+				def.ID = pid
+				// Shallow copy only use the def.ID in event handle.
+				newPartitions = append(newPartitions, *def)
+				break
+			}
+		}
+	}
+	if len(newPartitions) == 0 {
+		job.State = model.JobStateCancelled
+		return ver, table.ErrUnknownPartition.GenWithStackByArgs(fmt.Sprintf("pid:%v", oldIDs), tblInfo.Name.O)
+	}
+
+	// Clear the tiflash replica available status.
+	if tblInfo.TiFlashReplica != nil {
+		tblInfo.TiFlashReplica.Available = false
+		// Set partition replica become unavailable.
+		for _, oldID := range oldIDs {
+			for i, id := range tblInfo.TiFlashReplica.AvailablePartitionIDs {
+				if id == oldID {
+					newIDs := tblInfo.TiFlashReplica.AvailablePartitionIDs[:i]
+					newIDs = append(newIDs, tblInfo.TiFlashReplica.AvailablePartitionIDs[i+1:]...)
+					tblInfo.TiFlashReplica.AvailablePartitionIDs = newIDs
+					break
+				}
+			}
+		}
+	}
+
+	bundles, err := placement.NewPartitionListBundles(t, newPartitions)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+
+	tableID := fmt.Sprintf(label.TableIDFormat, label.IDPrefix, job.SchemaName, tblInfo.Name.L)
+	oldPartRules := make([]string, 0, len(oldIDs))
+	for _, newPartition := range newPartitions {
+		oldPartRuleID := fmt.Sprintf(label.PartitionIDFormat, label.IDPrefix, job.SchemaName, tblInfo.Name.L, newPartition.Name.L)
+		oldPartRules = append(oldPartRules, oldPartRuleID)
+	}
+
+	rules, err := infosync.GetLabelRules(context.TODO(), append(oldPartRules, tableID))
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Wrapf(err, "failed to get label rules from PD")
+	}
+
+	newPartIDs := getPartitionIDs(tblInfo)
+	newRules := make([]*label.Rule, 0, len(oldIDs)+1)
+	if tr, ok := rules[tableID]; ok {
+		newRules = append(newRules, tr.Clone().Reset(job.SchemaName, tblInfo.Name.L, "", append(newPartIDs, tblInfo.ID)...))
+	}
+
+	for idx, newPartition := range newPartitions {
+		if pr, ok := rules[oldPartRules[idx]]; ok {
+			newRules = append(newRules, pr.Clone().Reset(job.SchemaName, tblInfo.Name.L, newPartition.Name.L, newPartition.ID))
+		}
+	}
+
+	patch := label.NewRulePatch(newRules, []string{})
+	err = infosync.UpdateLabelRules(context.TODO(), patch)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Wrapf(err, "failed to notify PD the label rules")
+	}
+
+	newIDs = make([]int64, len(oldIDs))
+	for i := range oldIDs {
+		newIDs[i] = newPartitions[i].ID
+	}
+	job.CtxVars = []interface{}{oldIDs, newIDs}
+	if hasGlobalIndex(tblInfo) {
+		// The global index entries of the truncated partitions are cleaned up in the next state,
+		// so the new partition IDs are kept to find the old partitions again.
+		job.SchemaState = model.StateDeleteReorganization
+		job.Args = []interface{}{oldIDs, newIDs}
+		return updateVersionAndTableInfo(t, job, tblInfo, true)
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	asyncNotifyEvent(d, &util.Event{Tp: model.ActionTruncateTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: newPartitions}})
+	// A background job will be created to delete old partition data.
+	job.Args = []interface{}{oldIDs}
+	return ver, nil
+}
+
+// cleanupTruncatedPartitions removes the global index entries of the truncated partitions.
+// The old partitions are still readable by their IDs until the job is finished.
+func (w *worker) cleanupTruncatedPartitions(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, oldIDs, newIDs []int64) (ver int64, err error) {
+	newPartitions := make([]model.PartitionDefinition, 0, len(newIDs))
+	oldPartitions := make([]model.PartitionDefinition, 0, len(newIDs))
+	for i, newID := range newIDs {
+		for _, def := range tblInfo.Partition.Definitions {
+			if def.ID == newID {
+				newPartitions = append(newPartitions, def)
+				def.ID = oldIDs[i]
+				oldPartitions = append(oldPartitions, def)
+				break
+			}
+		}
+	}
+	tbl, err := getTable(d.store, job.SchemaID, getTableInfoWithExtraPartitions(tblInfo, oldPartitions))
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	elements := make([]*meta.Element, 0, len(tblInfo.Indices))
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.Global {
+			elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
+		}
+	}
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, oldIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return ver, errors.Trace(err)
+	}
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (truncateErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onTruncateTablePartition",
+			func() {
+				truncateErr = errCancelledDDLJob.GenWithStack("truncate partition panic")
+			}, false)
+		return w.cleanupGlobalIndexes(tbl.(table.PartitionedTable), oldIDs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return ver, nil
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	asyncNotifyEvent(d, &util.Event{Tp: model.ActionTruncateTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: newPartitions}})
+	// A background job will be created to delete old partition data.
+	job.Args = []interface{}{oldIDs}
+	return ver, nil
+}
+
+// onExchangeTablePartition exchange partition data
+func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var (
+		// defID only for updateSchemaVersion
+		defID          int64
+		ptSchemaID     int64
+		ptID           int64
+		partName       string
+		withValidation bool
+	)
+
+	if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackExchangeTablePartition(t, job, defID, ptSchemaID, ptID, partName, withValidation)
+	}
+	switch job.SchemaState {
+	case model.StateWriteReorganization, model.StateDeleteReorganization:
+		// The partition is already exchanged, the global indexes are being rebuilt.
+		// Now defID is the ID of the non-partitioned table, and job.TableID is the ID of the partition.
+		return w.onExchangeGlobalIndexes(d, t, job, defID, ptSchemaID, ptID, partName, withValidation)
+	case model.StateWriteOnly:
+		// The writes to the tables are fenced, the fence must be removed if the job fails.
+		defer func() {
+			if job.State == model.JobStateCancelled {
+				job.State = model.JobStateRollingback
+			}
+		}()
+	}
+
+	ntDbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	nt, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	pt, err := getTableInfo(t, ptID, ptSchemaID)
+	if err != nil {
+		if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err) {
+			job.State = model.JobStateCancelled
+		}
+		return ver, errors.Trace(err)
+	}
+
+	if pt.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrInvalidDDLState.GenWithStack("table %s is not in public, but %s", pt.Name, pt.State)
+	}
+
+	err = checkExchangePartition(pt, nt)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	err = checkTableDefCompatible(pt, nt)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	index, _, err := getPartitionDef(pt, partName)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	ptGlobal := hasGlobalIndex(pt)
+	if ptGlobal && job.SchemaState == model.StateNone {
+		// The records written after the exchange is checked may be duplicated with the records of
+		// the other partitions before the global indexes are rebuilt, so the writes are fenced first.
+		info := &model.ExchangePartitionInfo{PartitionTableID: pt.ID, PartitionName: model.NewCIStr(partName)}
+		job.SchemaState = model.StateWriteOnly
+		return updateExchangePartitionFence(t, job, pt, nt, info, defID, ptSchemaID, partName, withValidation)
+	}
+
+	if withValidation {
+		err = checkExchangePartitionRecordValidation(w, pt, index, ntDbInfo.Name, nt.Name)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+	}
+
+	if ptGlobal {
+		ptDbInfo, err := t.GetDatabase(ptSchemaID)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		err = checkExchangePartitionGlobalIndexes(w, pt, partName, ptDbInfo.Name, ntDbInfo.Name, nt.Name)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+	}
+
+	// partition table auto IDs.
+	ptAutoIDs, err := t.GetAutoIDAccessors(ptSchemaID, ptID).Get()
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	// non-partition table auto IDs.
+	ntAutoIDs, err := t.GetAutoIDAccessors(job.SchemaID, nt.ID).Get()
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	_, partDef, err := getPartitionDef(pt, partName)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	if pt.TiFlashReplica != nil {
+		for i, id := range pt.TiFlashReplica.AvailablePartitionIDs {
+			if id == partDef.ID {
+				pt.TiFlashReplica.AvailablePartitionIDs[i] = nt.ID
+				break
+			}
+		}
+	}
+
+	if ptGlobal {
+		// The global indexes and the same indexes of the non-partitioned table are not complete
+		// until the index entries of the exchanged records are rebuilt, so they can't be read.
+		for _, idx := range pt.Indices {
+			if !idx.Global {
+				continue
+			}
+			idx.State = model.StateWriteReorganization
+			if ntIdx := nt.FindIndexByName(idx.Name.L); ntIdx != nil {
+				ntIdx.State = model.StateWriteReorganization
+			}
+		}
+	}
+
+	// exchange table meta id
+	partDef.ID, nt.ID = nt.ID, partDef.ID
+
+	err = t.UpdateTable(ptSchemaID, pt)
+	if err != nil {
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
@@ -1462,15 +2556,246 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		return ver, errors.Wrapf(err, "failed to notify PD the label rules")
 	}
 
+	if ptGlobal {
+		job.SchemaState = model.StateWriteReorganization
+	}
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if ptGlobal {
+		// The arguments are decoded partially by updateSchemaVersion, keep all of them for the next states.
+		job.Args = []interface{}{defID, ptSchemaID, ptID, partName, withValidation}
+		return ver, nil
+	}
+
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	return ver, nil
+}
+
+// updateExchangePartitionFence sets or removes the ExchangePartitionInfo of both tables of the exchange.
+func updateExchangePartitionFence(t *meta.Meta, job *model.Job, pt, nt *model.TableInfo, info *model.ExchangePartitionInfo,
+	defID, ptSchemaID int64, partName string, withValidation bool) (ver int64, err error) {
+	pt.ExchangePartitionInfo = info
+	nt.ExchangePartitionInfo = info
+	if err = t.UpdateTable(ptSchemaID, pt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.UpdateTable(job.SchemaID, nt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The table IDs are not exchanged, the schema diff reloads the non-partitioned table with the same ID.
+	job.Args = []interface{}{nt.ID, ptSchemaID, pt.ID, partName, withValidation}
 	ver, err = updateSchemaVersion(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
+	job.Args = []interface{}{defID, ptSchemaID, pt.ID, partName, withValidation}
+	return ver, nil
+}
+
+// rollbackExchangeTablePartition removes the fence of the tables when the exchange fails or is cancelled
+// before the partition is exchanged.
+func rollbackExchangeTablePartition(t *meta.Meta, job *model.Job, defID, ptSchemaID, ptID int64, partName string, withValidation bool) (ver int64, err error) {
+	nt, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pt, err := getTableInfo(t, ptID, ptSchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateExchangePartitionFence(t, job, pt, nt, nil, defID, ptSchemaID, partName, withValidation)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, pt)
+	return ver, nil
+}
+
+// onExchangeGlobalIndexes rebuilds the global indexes after the partition is exchanged.
+// In StateWriteReorganization, the records exchanged into the partition are added to the global indexes.
+// In StateDeleteReorganization, the records exchanged out of the partition are removed from the global indexes,
+// and added to the same indexes of the non-partitioned table.
+// The records can't be inserted or updated until the job finishes, but the entries of the exchanged out records
+// deleted in the states may be left in the global indexes, they are ignored by the readers and overwritten by
+// the writers since their partition does not exist any more.
+func (w *worker) onExchangeGlobalIndexes(d *ddlCtx, t *meta.Meta, job *model.Job, ntID, ptSchemaID, ptID int64, partName string, withValidation bool) (ver int64, err error) {
+	pt, err := getTableInfo(t, ptID, ptSchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	nt, err := getTableInfo(t, ntID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	_, partDef, err := getPartitionDef(pt, partName)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var tblInfo *model.TableInfo
+	var physicalID int64
+	switch job.SchemaState {
+	case model.StateWriteReorganization:
+		tblInfo, physicalID = pt, partDef.ID
+	case model.StateDeleteReorganization:
+		// The records exchanged out are read as a partition, so the global indexes can be built for them.
+		def := *partDef
+		def.ID = nt.ID
+		tblInfo, physicalID = getTableInfoWithExtraPartitions(pt, []model.PartitionDefinition{def}), nt.ID
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	tbl, err := getTable(d.store, ptSchemaID, tblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	elements := make([]*meta.Element, 0, len(pt.Indices))
+	indexIDs := make([]int64, 0, len(pt.Indices))
+	for _, idxInfo := range pt.Indices {
+		if idxInfo.Global {
+			elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
+			indexIDs = append(indexIDs, idxInfo.ID)
+		}
+	}
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, []int64{physicalID}, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return ver, errors.Trace(err)
+	}
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (exchangeErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onExchangeTablePartition",
+			func() {
+				exchangeErr = errCancelledDDLJob.GenWithStack("exchange partition panic")
+			}, false)
+		return w.exchangeGlobalIndexData(tbl.(table.PartitionedTable), physicalID, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return ver, nil
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+
+	if job.SchemaState == model.StateWriteReorganization {
+		// Run the reorganization again for the records exchanged out.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateSchemaVersion(t, job)
+		job.Args = []interface{}{ntID, ptSchemaID, ptID, partName, withValidation}
+		return ver, errors.Trace(err)
+	}
 
+	for _, idx := range pt.Indices {
+		if !idx.Global {
+			continue
+		}
+		idx.State = model.StatePublic
+		if ntIdx := nt.FindIndexByName(idx.Name.L); ntIdx != nil {
+			ntIdx.State = model.StatePublic
+		}
+	}
+	pt.ExchangePartitionInfo = nil
+	nt.ExchangePartitionInfo = nil
+	if err = t.UpdateTable(ptSchemaID, pt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.UpdateTable(job.SchemaID, nt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
 	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	// A background job will be created to delete the entries of the non-partitioned table in the indexes,
+	// which become the global indexes, since the partition doesn't use them.
+	job.Args = []interface{}{ntID, ptSchemaID, ptID, partName, withValidation, partDef.ID, indexIDs}
 	return ver, nil
 }
 
+// checkExchangePartitionGlobalIndexes checks the records of the non-partitioned table are not duplicated
+// with the records of the other partitions in the global indexes.
+func checkExchangePartitionGlobalIndexes(w *worker, pt *model.TableInfo, partName string, ptSchemaName, ntSchemaName, ntName model.CIStr) error {
+	otherParts := make([]string, 0, len(pt.Partition.Definitions))
+	for _, def := range pt.Partition.Definitions {
+		if def.Name.L != partName {
+			otherParts = append(otherParts, def.Name.L)
+		}
+	}
+	if len(otherParts) == 0 {
+		return nil
+	}
+
+	var ctx sessionctx.Context
+	ctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(ctx)
+
+	for _, idx := range pt.Indices {
+		if !idx.Global {
+			continue
+		}
+		var buf strings.Builder
+		paramList := make([]interface{}, 0, 4*len(idx.Columns)+len(otherParts)+4)
+		writeCol := func(alias string, col *model.IndexColumn) {
+			if col.Length != types.UnspecifiedLength {
+				buf.WriteString("left(" + alias + ".%n, %?)")
+				paramList = append(paramList, col.Name.L, col.Length)
+			} else {
+				buf.WriteString(alias + ".%n")
+				paramList = append(paramList, col.Name.L)
+			}
+		}
+		buf.WriteString("select concat_ws('-'")
+		for _, col := range idx.Columns {
+			buf.WriteString(", ")
+			writeCol("nt", col)
+		}
+		buf.WriteString(") from %n.%n as nt join %n.%n partition(")
+		paramList = append(paramList, ntSchemaName.L, ntName.L, ptSchemaName.L, pt.Name.L)
+		for i, name := range otherParts {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("%n")
+			paramList = append(paramList, name)
+		}
+		buf.WriteString(") as pt on ")
+		for i, col := range idx.Columns {
+			if i > 0 {
+				buf.WriteString(" and ")
+			}
+			writeCol("nt", col)
+			buf.WriteString(" = ")
+			writeCol("pt", col)
+		}
+		buf.WriteString(" limit 1")
+
+		stmt, err := ctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(w.ddlJobCtx, true, buf.String(), paramList...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rows, _, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(w.ddlJobCtx, stmt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rows) != 0 {
+			return kv.ErrKeyExists.FastGenByArgs(rows[0].GetString(0), idx.Name.O)
+		}
+	}
+	return nil
+}
+
 func bundlesForExchangeTablePartition(t *meta.Meta, job *model.Job, pt *model.TableInfo, newPar *model.PartitionDefinition, nt *model.TableInfo) ([]*placement.Bundle, error) {
 	bundles := make([]*placement.Bundle, 0, 3)
 
@@ -1712,8 +3037,15 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 		partCols = columnInfoSlice(partColumns)
 	} else if len(s.Partition.ColumnNames) > 0 {
 		partCols = columnNameSlice(s.Partition.ColumnNames)
+	} else if s.Partition.Tp == model.PartitionTypeKey {
+		// The columns of KEY() are resolved from the primary key or a unique key.
+		partColumns := make([]*model.ColumnInfo, 0, len(tblInfo.Partition.Columns))
+		for _, col := range tblInfo.Partition.Columns {
+			partColumns = append(partColumns, getColumnInfoByName(tblInfo, col.L))
+		}
+		partCols = columnInfoSlice(partColumns)
 	} else {
-		// TODO: Check keys constraints for list, key partition type and so on.
+		// TODO: Check keys constraints for list partition type and so on.
 		return nil
 	}
 
@@ -1725,9 +3057,11 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 			if index.Primary {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
 			}
-			if !config.GetGlobalConfig().EnableGlobalIndex {
+			if !isGlobalIndexEnabled(sctx) {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
+			// The unique key can not be checked in a single partition, so it must be global.
+			index.Global = true
 		}
 	}
 	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
@@ -1769,7 +3103,7 @@ func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model
 
 	// In MySQL, every unique key on the table must use every column in the table's partitioning expression.(This
 	// also includes the table's primary key.)
-	// In TiDB, global index will be built when this constraint is not satisfied and EnableGlobalIndex is set.
+	// In TiDB, global index will be built when this constraint is not satisfied and global index is enabled.
 	// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations-partitioning-keys-unique-keys.html
 	return checkUniqueKeyIncludePartKey(columnInfoSlice(partCols), indexColumns), nil
 }
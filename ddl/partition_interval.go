@@ -0,0 +1,664 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	goutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	intervalPartitionNamePrefix = "P_LT_"
+	intervalNullPartitionName   = "P_NULL"
+	intervalMaxValPartitionName = "P_MAXVALUE"
+)
+
+// intervalPartitionCheckInterval is the interval for the DDL owner to check whether the partitions
+// of the INTERVAL partitioned tables should be added or dropped.
+var intervalPartitionCheckInterval = 10 * time.Minute
+
+// partitionIntervalBound evaluates and computes the range bounds of an INTERVAL partitioned table.
+// The bounds are integers for RANGE (expr), or the values of the partitioning column for RANGE COLUMNS.
+type partitionIntervalBound struct {
+	tbInfo   *model.TableInfo
+	ft       *types.FieldType
+	interval int64
+	unit     ast.TimeUnitType
+}
+
+func newPartitionIntervalBound(tbInfo *model.TableInfo, interval int64, unit ast.TimeUnitType) (*partitionIntervalBound, error) {
+	pi := tbInfo.Partition
+	ft := types.NewFieldType(mysql.TypeLonglong)
+	if len(pi.Columns) > 0 {
+		if len(pi.Columns) != 1 {
+			return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("only one partitioning column is supported")
+		}
+		colInfo := getColumnInfoByName(tbInfo, pi.Columns[0].L)
+		if colInfo == nil {
+			return nil, errors.Trace(ErrFieldNotFoundPart)
+		}
+		ft = &colInfo.FieldType
+	} else if isColUnsigned(tbInfo.Columns, pi) {
+		ft.Flag |= mysql.UnsignedFlag
+	}
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		if unit != ast.TimeUnitInvalid {
+			return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the interval of an integer partitioning column must be an integer")
+		}
+	case mysql.TypeDate, mysql.TypeDatetime:
+		switch unit {
+		case ast.TimeUnitYear, ast.TimeUnitQuarter, ast.TimeUnitMonth, ast.TimeUnitWeek, ast.TimeUnitDay:
+		case ast.TimeUnitHour, ast.TimeUnitMinute, ast.TimeUnitSecond:
+			if ft.Tp == mysql.TypeDate {
+				return nil, ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("the interval unit %s is not supported for DATE", unit.String()))
+			}
+		case ast.TimeUnitInvalid:
+			return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the interval of a DATE or DATETIME partitioning column must have a time unit")
+		default:
+			return nil, ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("the interval unit %s is not supported", unit.String()))
+		}
+	default:
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the partitioning column must be an integer, DATE or DATETIME column")
+	}
+	if interval <= 0 {
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the interval must be positive")
+	}
+	return &partitionIntervalBound{tbInfo: tbInfo, ft: ft, interval: interval, unit: unit}, nil
+}
+
+// newPartitionIntervalBoundFromInfo creates the partitionIntervalBound of a table created with INTERVAL.
+func newPartitionIntervalBoundFromInfo(tbInfo *model.TableInfo) (*partitionIntervalBound, error) {
+	intervalInfo := tbInfo.Partition.Interval
+	interval, err := strconv.ParseInt(intervalInfo.IntervalExprStr, 10, 64)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newPartitionIntervalBound(tbInfo, interval, ast.TimeUnitType(intervalInfo.IntervalTimeUnit))
+}
+
+// isTime returns whether the bounds are DATE or DATETIME values.
+func (b *partitionIntervalBound) isTime() bool {
+	return b.unit != ast.TimeUnitInvalid
+}
+
+// eval evaluates a bound, which is a restored expression or the LESS THAN value of a partition.
+func (b *partitionIntervalBound) eval(ctx sessionctx.Context, str string) (types.Datum, error) {
+	e, err := expression.ParseSimpleExprCastWithTableInfo(ctx, str, b.tbInfo, b.ft)
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	d, err := e.Eval(chunk.Row{})
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	if d.IsNull() {
+		return types.Datum{}, ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("the range bound %s is invalid", str))
+	}
+	return d, nil
+}
+
+func (b *partitionIntervalBound) evalExpr(ctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error) {
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreNameBackQuotes, &sb)
+	if err := expr.Restore(restoreCtx); err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	return b.eval(ctx, sb.String())
+}
+
+// add returns the bound which is n intervals after d, n can be negative.
+func (b *partitionIntervalBound) add(d types.Datum, n int64) (types.Datum, error) {
+	if !b.isTime() {
+		if mysql.HasUnsignedFlag(b.ft.Flag) {
+			v, delta := d.GetUint64(), uint64(n*b.interval)
+			if (n > 0 && v+delta < v) || (n < 0 && v < -delta) {
+				return d, ErrInvalidIntervalPartition.GenWithStackByArgs("the range bound is out of range")
+			}
+			return types.NewUintDatum(v + delta), nil
+		}
+		v := d.GetInt64()
+		if n != 0 && (b.interval > math.MaxInt64/abs64(n)) {
+			return d, ErrInvalidIntervalPartition.GenWithStackByArgs("the range bound is out of range")
+		}
+		delta := n * b.interval
+		if (delta > 0 && v > math.MaxInt64-delta) || (delta < 0 && v < math.MinInt64-delta) {
+			return d, ErrInvalidIntervalPartition.GenWithStackByArgs("the range bound is out of range")
+		}
+		return types.NewIntDatum(v + delta), nil
+	}
+	t, err := addTimeInterval(d.GetMysqlTime(), n*b.interval, b.unit)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	return types.NewTimeDatum(t), nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// addTimeInterval adds n units to t. Like DATE_ADD, the day is truncated to the last day of the month
+// when adding months to a day which doesn't exist in the result month.
+func addTimeInterval(t types.Time, n int64, unit ast.TimeUnitType) (types.Time, error) {
+	var ct types.CoreTime
+	switch unit {
+	case ast.TimeUnitYear, ast.TimeUnitQuarter, ast.TimeUnitMonth:
+		months := n
+		if unit == ast.TimeUnitYear {
+			months = n * 12
+		} else if unit == ast.TimeUnitQuarter {
+			months = n * 3
+		}
+		total := int64(t.Year())*12 + int64(t.Month()-1) + months
+		year, month := int(total/12), int(total%12)+1
+		if total < 0 || year > 9999 {
+			return t, ErrInvalidIntervalPartition.GenWithStackByArgs("the range bound is out of range")
+		}
+		day := t.Day()
+		if lastDay := types.GetLastDay(year, month); day > lastDay {
+			day = lastDay
+		}
+		ct = types.FromDate(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+	default:
+		goTime, err := t.CoreTime().GoTime(time.UTC)
+		if err != nil {
+			return t, errors.Trace(err)
+		}
+		switch unit {
+		case ast.TimeUnitWeek:
+			goTime = goTime.AddDate(0, 0, int(n*7))
+		case ast.TimeUnitDay:
+			goTime = goTime.AddDate(0, 0, int(n))
+		case ast.TimeUnitHour:
+			goTime = goTime.Add(time.Duration(n) * time.Hour)
+		case ast.TimeUnitMinute:
+			goTime = goTime.Add(time.Duration(n) * time.Minute)
+		case ast.TimeUnitSecond:
+			goTime = goTime.Add(time.Duration(n) * time.Second)
+		}
+		if goTime.Year() < 0 || goTime.Year() > 9999 {
+			return t, ErrInvalidIntervalPartition.GenWithStackByArgs("the range bound is out of range")
+		}
+		ct = types.FromGoTime(goTime)
+	}
+	return types.NewTime(ct, t.Type(), t.Fsp()), nil
+}
+
+func (b *partitionIntervalBound) compare(sc *stmtctx.StatementContext, x, y types.Datum) (int, error) {
+	return x.Compare(sc, &y, collate.GetBinaryCollator())
+}
+
+// boundsBetween returns the bounds `anchor + i * interval` (i >= 0) which are greater than lower and
+// less than or equal to upper, lower is ignored if it is nil. Upper must be one of the bounds.
+func (b *partitionIntervalBound) boundsBetween(sc *stmtctx.StatementContext, anchor types.Datum, lower *types.Datum, upper types.Datum) ([]types.Datum, error) {
+	var bounds []types.Datum
+	for i := int64(0); ; i++ {
+		bound, err := b.add(anchor, i)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cmp, err := b.compare(sc, bound, upper)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cmp > 0 {
+			return nil, ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("the range bound %s is not aligned to the interval", b.boundString(upper)))
+		}
+		if lower != nil {
+			if cmp, err = b.compare(sc, bound, *lower); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if lower == nil || cmp > 0 {
+			bounds = append(bounds, bound)
+			if len(bounds) > PartitionCountLimit {
+				return nil, errors.Trace(ErrTooManyPartitions)
+			}
+		}
+		if cmp, err = b.compare(sc, bound, upper); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cmp == 0 {
+			return bounds, nil
+		}
+	}
+}
+
+// nullBound returns the bound of the NULL partition, which holds the NULL values and the minimum values.
+func (b *partitionIntervalBound) nullBound() types.Datum {
+	if !b.isTime() {
+		if mysql.HasUnsignedFlag(b.ft.Flag) {
+			return types.NewUintDatum(0)
+		}
+		return types.NewIntDatum(math.MinInt64)
+	}
+	return types.NewTimeDatum(types.NewTime(types.FromDate(0, 1, 1, 0, 0, 0, 0), b.ft.Tp, 0))
+}
+
+func (b *partitionIntervalBound) boundString(d types.Datum) string {
+	if b.isTime() {
+		return d.GetMysqlTime().String()
+	}
+	str, err := d.ToString()
+	if err != nil {
+		return ""
+	}
+	return str
+}
+
+func (b *partitionIntervalBound) boundExpr(d types.Datum) ast.ExprNode {
+	if b.isTime() {
+		return ast.NewValueExpr(d.GetMysqlTime().String(), "", "")
+	}
+	return ast.NewValueExpr(d.GetValue(), "", "")
+}
+
+// partitionDefinition returns the partition `P_LT_<bound> VALUES LESS THAN (<bound>)`.
+func (b *partitionIntervalBound) partitionDefinition(name string, d types.Datum) *ast.PartitionDefinition {
+	if len(name) == 0 {
+		name = intervalPartitionNamePrefix + b.boundString(d)
+	}
+	return &ast.PartitionDefinition{
+		Name:   model.NewCIStr(name),
+		Clause: &ast.PartitionDefinitionClauseLessThan{Exprs: []ast.ExprNode{b.boundExpr(d)}},
+	}
+}
+
+// buildPartitionIntervalInfo checks the INTERVAL clause and sets the INTERVAL config of the table.
+// It returns the partition definitions generated from FIRST to LAST, or the given definitions if there is no FIRST and LAST.
+func buildPartitionIntervalInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tbInfo *model.TableInfo) ([]*ast.PartitionDefinition, error) {
+	interval := s.Interval
+	intervalVal, err := expression.EvalAstExpr(ctx, interval.IntervalExpr.Expr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if intervalVal.Kind() != types.KindInt64 && intervalVal.Kind() != types.KindUint64 {
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the interval must be an integer")
+	}
+	bound, err := newPartitionIntervalBound(tbInfo, intervalVal.GetInt64(), interval.IntervalExpr.TimeUnit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if (interval.Precreate > 0 || interval.Retention > 0) && !bound.isTime() {
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("PRECREATE and RETENTION are only supported for DATE or DATETIME partitioning columns")
+	}
+	if interval.Precreate > 0 && interval.MaxValPart {
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("PRECREATE is not supported with MAXVALUE PARTITION")
+	}
+	tbInfo.Partition.Interval = &model.PartitionIntervalInfo{
+		IntervalExprStr:  strconv.FormatInt(bound.interval, 10),
+		IntervalTimeUnit: int(bound.unit),
+		NullPart:         interval.NullPart,
+		MaxValPart:       interval.MaxValPart,
+		Precreate:        interval.Precreate,
+		Retention:        interval.Retention,
+	}
+
+	if interval.FirstRangeEnd == nil {
+		return s.Definitions, nil
+	}
+	if len(s.Definitions) > 0 {
+		return nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the partitions can't be defined with FIRST and LAST PARTITION")
+	}
+	first, err := bound.evalExpr(ctx, interval.FirstRangeEnd)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	last, err := bound.evalExpr(ctx, interval.LastRangeEnd)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bounds, err := bound.boundsBetween(ctx.GetSessionVars().StmtCtx, first, nil, last)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defs := make([]*ast.PartitionDefinition, 0, len(bounds)+2)
+	if interval.NullPart {
+		defs = append(defs, bound.partitionDefinition(intervalNullPartitionName, bound.nullBound()))
+	}
+	for _, d := range bounds {
+		defs = append(defs, bound.partitionDefinition("", d))
+	}
+	if interval.MaxValPart {
+		defs = append(defs, &ast.PartitionDefinition{
+			Name:   model.NewCIStr(intervalMaxValPartitionName),
+			Clause: &ast.PartitionDefinitionClauseLessThan{Exprs: []ast.ExprNode{&ast.MaxValueExpr{}}},
+		})
+	}
+	return defs, nil
+}
+
+// getIntervalPartitionBounds returns the bounds of the partitions generated by the interval, which
+// excludes the NULL partition and the MAXVALUE partition.
+func getIntervalPartitionBounds(ctx sessionctx.Context, tbInfo *model.TableInfo, bound *partitionIntervalBound) ([]model.PartitionDefinition, []types.Datum, error) {
+	pi := tbInfo.Partition
+	defs := pi.Definitions
+	if pi.Interval.NullPart && len(defs) > 0 {
+		defs = defs[1:]
+	}
+	if len(defs) > 0 && strings.EqualFold(defs[len(defs)-1].LessThan[0], partitionMaxValue) {
+		defs = defs[:len(defs)-1]
+	}
+	if len(defs) == 0 {
+		return nil, nil, ErrInvalidIntervalPartition.GenWithStackByArgs("no partition is generated by the interval")
+	}
+	bounds := make([]types.Datum, 0, len(defs))
+	for _, def := range defs {
+		d, err := bound.eval(ctx, def.LessThan[0])
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		bounds = append(bounds, d)
+	}
+	return defs, bounds, nil
+}
+
+func getIntervalPartitionedTable(is infoschema.InfoSchema, ident ast.Ident) (*model.DBInfo, *model.TableInfo, error) {
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return nil, nil, errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return nil, nil, errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+	meta := t.Meta()
+	if meta.GetPartitionInfo() == nil {
+		return nil, nil, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if meta.Partition.Interval == nil {
+		return nil, nil, ErrInvalidIntervalPartition.GenWithStackByArgs("the table is not partitioned by INTERVAL")
+	}
+	return schema, meta, nil
+}
+
+// AddLastPartition adds the partitions by the interval until the last one is `LESS THAN (LastRangeEnd)`,
+// it is `ALTER TABLE ... LAST PARTITION LESS THAN (expr)`.
+func (d *ddl) AddLastPartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	_, meta, err := getIntervalPartitionedTable(d.infoCache.GetLatest(), ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if meta.Partition.Interval.MaxValPart {
+		return ErrInvalidIntervalPartition.GenWithStackByArgs("LAST PARTITION is not supported with MAXVALUE PARTITION")
+	}
+	bound, err := newPartitionIntervalBoundFromInfo(meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, bounds, err := getIntervalPartitionBounds(ctx, meta, bound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	lastEnd, err := bound.evalExpr(ctx, spec.Partition.Interval.LastRangeEnd)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	cmp, err := bound.compare(sc, lastEnd, bounds[len(bounds)-1])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cmp <= 0 {
+		return ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("LAST PARTITION LESS THAN (%s) must be greater than the last partition", bound.boundString(lastEnd)))
+	}
+	added, err := bound.boundsBetween(sc, bounds[0], &bounds[len(bounds)-1], lastEnd)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	addSpec := &ast.AlterTableSpec{
+		Tp:              ast.AlterTableAddPartitions,
+		PartDefinitions: make([]*ast.PartitionDefinition, 0, len(added)),
+	}
+	for _, d := range added {
+		addSpec.PartDefinitions = append(addSpec.PartDefinitions, bound.partitionDefinition("", d))
+	}
+	return d.AddTablePartitions(ctx, ident, addSpec)
+}
+
+// DropFirstPartition drops the partitions before the one which is `LESS THAN (FirstRangeEnd)`,
+// it is `ALTER TABLE ... FIRST PARTITION LESS THAN (expr)`. The NULL partition is kept.
+func (d *ddl) DropFirstPartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	_, meta, err := getIntervalPartitionedTable(d.infoCache.GetLatest(), ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bound, err := newPartitionIntervalBoundFromInfo(meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defs, bounds, err := getIntervalPartitionBounds(ctx, meta, bound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	firstEnd, err := bound.evalExpr(ctx, spec.Partition.Interval.FirstRangeEnd)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	dropSpec := &ast.AlterTableSpec{Tp: ast.AlterTableDropPartition}
+	for i, end := range bounds {
+		cmp, err := bound.compare(sc, end, firstEnd)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if cmp == 0 {
+			if len(dropSpec.PartitionNames) == 0 {
+				return ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("FIRST PARTITION LESS THAN (%s) doesn't drop any partition", bound.boundString(firstEnd)))
+			}
+			return d.DropTablePartition(ctx, ident, dropSpec)
+		}
+		if cmp > 0 {
+			break
+		}
+		dropSpec.PartitionNames = append(dropSpec.PartitionNames, defs[i].Name)
+	}
+	return ErrInvalidIntervalPartition.GenWithStackByArgs(fmt.Sprintf("FIRST PARTITION LESS THAN (%s) doesn't match any partition", bound.boundString(firstEnd)))
+}
+
+// startIntervalPartitionManagement pre-creates and drops the partitions of the INTERVAL partitioned
+// tables by their PRECREATE and RETENTION config. Only the DDL owner does it.
+func (d *ddl) startIntervalPartitionManagement() {
+	defer func() {
+		goutil.Recover(metrics.LabelDDL, "startIntervalPartitionManagement", nil, false)
+		d.wg.Done()
+	}()
+
+	ticker := time.NewTicker(intervalPartitionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !d.ownerManager.IsOwner() {
+				continue
+			}
+			d.manageIntervalPartitions(time.Now())
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// manageIntervalPartitions adds and drops the partitions of all the INTERVAL partitioned tables once.
+func (d *ddl) manageIntervalPartitions(now time.Time) {
+	ctx, err := d.sessPool.get()
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] get session failed when managing interval partitions", zap.Error(err))
+		return
+	}
+	defer d.sessPool.put(ctx)
+
+	is := d.infoCache.GetLatest()
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tbInfo := tbl.Meta()
+			if tbInfo.Partition == nil || tbInfo.Partition.Interval == nil || !tbInfo.Partition.Interval.IsAutoManaged() {
+				continue
+			}
+			ident := ast.Ident{Schema: db.Name, Name: tbInfo.Name}
+			if err := d.manageIntervalPartitionsOfTable(ctx, ident, tbInfo, now); err != nil {
+				logutil.BgLogger().Warn("[ddl] manage interval partitions failed",
+					zap.String("table", fmt.Sprintf("%s.%s", db.Name.O, tbInfo.Name.O)), zap.Error(err))
+			}
+		}
+	}
+}
+
+// manageIntervalPartitionsOfTable keeps PRECREATE partitions after the one which the current time belongs to,
+// and drops the partitions whose values are all older than RETENTION intervals before the current time.
+func (d *ddl) manageIntervalPartitionsOfTable(ctx sessionctx.Context, ident ast.Ident, tbInfo *model.TableInfo, now time.Time) error {
+	intervalInfo := tbInfo.Partition.Interval
+	bound, err := newPartitionIntervalBoundFromInfo(tbInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, bounds, err := getIntervalPartitionBounds(ctx, tbInfo, bound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	nowTime := types.NewTime(types.FromGoTime(now.In(ctx.GetSessionVars().Location())), bound.ft.Tp, int8(bound.ft.Decimal))
+	if bound.ft.Tp == mysql.TypeDate {
+		nowTime = types.NewTime(types.FromDate(nowTime.Year(), nowTime.Month(), nowTime.Day(), 0, 0, 0, 0), mysql.TypeDate, 0)
+	}
+	nowDatum := types.NewTimeDatum(nowTime)
+
+	if intervalInfo.Precreate > 0 && !intervalInfo.MaxValPart {
+		// The bound of the partition which the current time belongs to is the first bound greater than it.
+		current, err := bound.firstBoundAfter(sc, bounds[0], nowDatum)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		lastEnd, err := bound.add(current, int64(intervalInfo.Precreate))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cmp, err := bound.compare(sc, lastEnd, bounds[len(bounds)-1])
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if cmp > 0 {
+			spec := &ast.AlterTableSpec{
+				Tp:        ast.AlterTableAddLastPartition,
+				Partition: &ast.PartitionOptions{PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, Interval: &ast.PartitionInterval{LastRangeEnd: bound.boundExpr(lastEnd)}}},
+			}
+			if err = d.AddLastPartition(ctx, ident, spec); err != nil {
+				return errors.Trace(err)
+			}
+			logutil.BgLogger().Info("[ddl] pre-create interval partitions", zap.String("table", ident.String()), zap.String("last partition less than", bound.boundString(lastEnd)))
+		}
+	}
+
+	if intervalInfo.Retention > 0 {
+		// The partitions may be changed by the pre-creation above.
+		_, tbInfo, err = getIntervalPartitionedTable(d.infoCache.GetLatest(), ident)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, bounds, err = getIntervalPartitionBounds(ctx, tbInfo, bound)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		expired, err := bound.add(nowDatum, -int64(intervalInfo.Retention))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// The partitions whose bounds are not greater than the expired time only hold the expired values,
+		// but the last partition is always kept.
+		firstKept := len(bounds) - 1
+		for i, b := range bounds {
+			cmp, err := bound.compare(sc, b, expired)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if cmp > 0 {
+				firstKept = i
+				break
+			}
+		}
+		if firstKept > 0 {
+			spec := &ast.AlterTableSpec{
+				Tp:        ast.AlterTableDropFirstPartition,
+				Partition: &ast.PartitionOptions{PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, Interval: &ast.PartitionInterval{FirstRangeEnd: bound.boundExpr(bounds[firstKept])}}},
+			}
+			if err = d.DropFirstPartition(ctx, ident, spec); err != nil {
+				return errors.Trace(err)
+			}
+			logutil.BgLogger().Info("[ddl] drop expired interval partitions", zap.String("table", ident.String()), zap.String("first partition less than", bound.boundString(bounds[firstKept])))
+		}
+	}
+	return nil
+}
+
+// firstBoundAfter returns the first bound `anchor + i * interval` which is greater than d, i can be negative.
+func (b *partitionIntervalBound) firstBoundAfter(sc *stmtctx.StatementContext, anchor, d types.Datum) (types.Datum, error) {
+	cmp, err := b.compare(sc, anchor, d)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	step := int64(1)
+	if cmp > 0 {
+		step = -1
+	}
+	for i := int64(0); ; i += step {
+		curr, err := b.add(anchor, i)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		next, err := b.add(anchor, i+step)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		if step < 0 {
+			curr, next = next, curr
+		}
+		// curr <= d < next
+		cmpCurr, err := b.compare(sc, curr, d)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		cmpNext, err := b.compare(sc, next, d)
+		if err != nil {
+			return d, errors.Trace(err)
+		}
+		if cmpCurr <= 0 && cmpNext > 0 {
+			return next, nil
+		}
+	}
+}
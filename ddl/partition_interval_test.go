@@ -0,0 +1,165 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+)
+
+func TestCreateIntervalPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec(`create table t (a int, b varchar(20)) partition by range (a)
+		interval (100) first partition less than (100) last partition less than (400) null partition maxvalue partition`)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`) /*T![interval_partition] INTERVAL (100) NULL PARTITION MAXVALUE PARTITION */\n" +
+		"(PARTITION `P_NULL` VALUES LESS THAN (-9223372036854775808),\n" +
+		" PARTITION `P_LT_100` VALUES LESS THAN (100),\n" +
+		" PARTITION `P_LT_200` VALUES LESS THAN (200),\n" +
+		" PARTITION `P_LT_300` VALUES LESS THAN (300),\n" +
+		" PARTITION `P_LT_400` VALUES LESS THAN (400),\n" +
+		" PARTITION `P_MAXVALUE` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustExec("insert into t values (null, 'a'), (-1, 'b'), (150, 'c'), (399, 'd'), (1000, 'e')")
+	tk.MustQuery("select a from t partition (P_NULL)").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select a from t partition (P_LT_100)").Check(testkit.Rows("-1"))
+	tk.MustQuery("select a from t partition (P_LT_400)").Check(testkit.Rows("399"))
+	tk.MustQuery("select a from t partition (P_MAXVALUE)").Check(testkit.Rows("1000"))
+
+	tk.MustExec(`create table t1 (id int, d date) partition by range columns (d)
+		interval (1 month) first partition less than ('2022-01-31') last partition less than ('2022-05-31')`)
+	tk.MustQuery("select partition_name, partition_description from information_schema.partitions where table_name = 't1' order by partition_ordinal_position").Check(testkit.Rows(
+		"P_LT_2022-01-31 \"2022-01-31\"",
+		"P_LT_2022-02-28 \"2022-02-28\"",
+		"P_LT_2022-03-31 \"2022-03-31\"",
+		"P_LT_2022-04-30 \"2022-04-30\"",
+		"P_LT_2022-05-31 \"2022-05-31\""))
+	tk.MustQuery("show create table t1").Check(testkit.Rows("t1 CREATE TABLE `t1` (\n" +
+		"  `id` int(11) DEFAULT NULL,\n" +
+		"  `d` date DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE COLUMNS(`d`) /*T![interval_partition] INTERVAL (1 MONTH) */\n" +
+		"(PARTITION `P_LT_2022-01-31` VALUES LESS THAN (\"2022-01-31\"),\n" +
+		" PARTITION `P_LT_2022-02-28` VALUES LESS THAN (\"2022-02-28\"),\n" +
+		" PARTITION `P_LT_2022-03-31` VALUES LESS THAN (\"2022-03-31\"),\n" +
+		" PARTITION `P_LT_2022-04-30` VALUES LESS THAN (\"2022-04-30\"),\n" +
+		" PARTITION `P_LT_2022-05-31` VALUES LESS THAN (\"2022-05-31\"))"))
+
+	// The partitions can be defined explicitly, then they are checked when adding or dropping partitions by the interval.
+	tk.MustExec(`create table t2 (a int) partition by range (a) interval (10) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("alter table t2 last partition less than (40)")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_name = 't2' order by partition_ordinal_position").Check(testkit.Rows(
+		"p0", "p1", "P_LT_30", "P_LT_40"))
+
+	tk.MustGetErrCode(`create table t3 (a int) partition by range (a)
+		interval (100) first partition less than (100) last partition less than (350)`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (a int) partition by range (a)
+		interval (1 month) first partition less than (100) last partition less than (300)`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (a int) partition by range (a)
+		interval (0) first partition less than (100) last partition less than (300)`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (d date) partition by range columns (d)
+		interval (1) first partition less than ('2022-01-01') last partition less than ('2022-03-01')`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (d date) partition by range columns (d)
+		interval (1 hour) first partition less than ('2022-01-01') last partition less than ('2022-03-01')`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (a int) partition by range (a)
+		interval (100) first partition less than (100) last partition less than (300) precreate 2`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (d date) partition by range columns (d)
+		interval (1 day) first partition less than ('2022-01-01') last partition less than ('2022-03-01') maxvalue partition precreate 2`, errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode(`create table t3 (a int) partition by range (a)
+		interval (100) first partition less than (100) last partition less than (300) (partition p0 values less than (100))`, errno.ErrInvalidIntervalPartition)
+}
+
+func TestAlterIntervalPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec(`create table t (a int, b int) partition by range (a)
+		interval (10) first partition less than (10) last partition less than (30) null partition`)
+	tk.MustExec("insert into t values (null, 0), (5, 1), (15, 2), (25, 3)")
+	tk.MustExec("alter table t last partition less than (50)")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_name = 't' order by partition_ordinal_position").Check(testkit.Rows(
+		"P_NULL", "P_LT_10", "P_LT_20", "P_LT_30", "P_LT_40", "P_LT_50"))
+	tk.MustExec("insert into t values (45, 4)")
+	tk.MustGetErrCode("alter table t last partition less than (50)", errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode("alter table t last partition less than (65)", errno.ErrInvalidIntervalPartition)
+
+	// The NULL partition is always kept.
+	tk.MustExec("alter table t first partition less than (30)")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_name = 't' order by partition_ordinal_position").Check(testkit.Rows(
+		"P_NULL", "P_LT_30", "P_LT_40", "P_LT_50"))
+	tk.MustQuery("select b from t order by b").Check(testkit.Rows("0", "3", "4"))
+	tk.MustGetErrCode("alter table t first partition less than (30)", errno.ErrInvalidIntervalPartition)
+	tk.MustGetErrCode("alter table t first partition less than (35)", errno.ErrInvalidIntervalPartition)
+
+	tk.MustExec("create table t1 (a int) partition by range (a) (partition p0 values less than (10))")
+	tk.MustGetErrCode("alter table t1 last partition less than (20)", errno.ErrInvalidIntervalPartition)
+	tk.MustExec("create table t2 (a int)")
+	tk.MustGetErrCode("alter table t2 first partition less than (20)", errno.ErrPartitionMgmtOnNonpartitioned)
+	tk.MustExec(`create table t3 (a int) partition by range (a)
+		interval (10) first partition less than (10) last partition less than (30) maxvalue partition`)
+	tk.MustGetErrCode("alter table t3 last partition less than (50)", errno.ErrInvalidIntervalPartition)
+}
+
+func TestManageIntervalPartitions(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec(`create table t (id int, d date) partition by range columns (d)
+		interval (1 month) first partition less than ('2022-02-01') last partition less than ('2022-04-01') precreate 2 retention 3`)
+	tk.MustExec(`create table t1 (id int, d date) partition by range columns (d)
+		interval (1 month) first partition less than ('2022-02-01') last partition less than ('2022-04-01')`)
+	tk.MustExec("insert into t values (1, '2022-01-15'), (2, '2022-02-15'), (3, '2022-03-15')")
+
+	checkPartitions := func(tbl string, names ...string) {
+		tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = ? order by partition_ordinal_position", tbl).
+			Check(testkit.Rows(names...))
+	}
+	d := dom.DDL().(ddl.DDLForTest)
+
+	// 2022-03-10 is in P_LT_2022-04-01, so 2 partitions are created after it.
+	d.ManageIntervalPartitions(time.Date(2022, 3, 10, 0, 0, 0, 0, time.Local))
+	checkPartitions("t", "P_LT_2022-02-01", "P_LT_2022-03-01", "P_LT_2022-04-01", "P_LT_2022-05-01", "P_LT_2022-06-01")
+	checkPartitions("t1", "P_LT_2022-02-01", "P_LT_2022-03-01", "P_LT_2022-04-01")
+
+	// Nothing changes if it runs again at the same time.
+	d.ManageIntervalPartitions(time.Date(2022, 3, 20, 0, 0, 0, 0, time.Local))
+	checkPartitions("t", "P_LT_2022-02-01", "P_LT_2022-03-01", "P_LT_2022-04-01", "P_LT_2022-05-01", "P_LT_2022-06-01")
+
+	// The values before 2022-03-01 are expired at 2022-06-01.
+	d.ManageIntervalPartitions(time.Date(2022, 6, 1, 0, 0, 0, 0, time.Local))
+	checkPartitions("t", "P_LT_2022-04-01", "P_LT_2022-05-01", "P_LT_2022-06-01", "P_LT_2022-07-01", "P_LT_2022-08-01", "P_LT_2022-09-01")
+	tk.MustQuery("select id from t").Check(testkit.Rows("3"))
+	checkPartitions("t1", "P_LT_2022-02-01", "P_LT_2022-03-01", "P_LT_2022-04-01")
+
+	// The missing partitions are all created, and the expired ones are all dropped.
+	d.ManageIntervalPartitions(time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local))
+	checkPartitions("t", "P_LT_2023-04-01", "P_LT_2023-05-01", "P_LT_2023-06-01", "P_LT_2023-07-01", "P_LT_2023-08-01", "P_LT_2023-09-01")
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseAndResumeAddIndex(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	for i := 0; i < 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	var (
+		jobID    int64
+		pauseErr error
+	)
+	originalHook := dom.DDL().GetHook()
+	defer dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	hook := &ddl.TestDDLCallback{Do: dom}
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if atomic.LoadInt64(&jobID) != 0 || job.Type != model.ActionAddIndex || job.SchemaState != model.StateWriteReorganization {
+			return
+		}
+		atomic.StoreInt64(&jobID, job.ID)
+		rs, err := tk1.Exec(fmt.Sprintf("admin pause ddl jobs %d", job.ID))
+		if err != nil {
+			pauseErr = err
+			return
+		}
+		rows := tk1.ResultSetToResult(rs, "pause ddl job").Rows()
+		if rows[0][1] != "successful" {
+			pauseErr = fmt.Errorf("pause ddl job failed: %v", rows[0][1])
+		}
+	}
+	dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := tk.Exec("alter table t add index idx(b)")
+		done <- err
+	}()
+
+	jobState := func() string {
+		rows := tk1.MustQuery(fmt.Sprintf("admin show ddl jobs where job_id = %d", jobID)).Rows()
+		if len(rows) == 0 {
+			return ""
+		}
+		return rows[0][10].(string)
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&jobID) != 0 && jobState() == "paused"
+	}, 10*time.Second, 50*time.Millisecond)
+	require.NoError(t, pauseErr)
+
+	// The paused job doesn't go on.
+	select {
+	case err := <-done:
+		require.FailNow(t, "the paused job shouldn't be finished", "err: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	require.Equal(t, "paused", jobState())
+	tk1.MustQuery(fmt.Sprintf("admin pause ddl jobs %d", jobID)).Check(testkit.Rows(fmt.Sprintf("%d successful", jobID)))
+
+	tk1.MustQuery(fmt.Sprintf("admin resume ddl jobs %d", jobID)).Check(testkit.Rows(fmt.Sprintf("%d successful", jobID)))
+	require.NoError(t, <-done)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t use index(idx)").Check(testkit.Rows("10"))
+
+	// The finished job can't be paused or resumed.
+	tk1.MustQuery(fmt.Sprintf("admin pause ddl jobs %d", jobID)).Check(testkit.Rows(
+		fmt.Sprintf("%d error: [admin:8224]DDL Job:%d not found", jobID, jobID)))
+	tk1.MustQuery(fmt.Sprintf("admin resume ddl jobs %d", jobID)).Check(testkit.Rows(
+		fmt.Sprintf("%d error: [admin:8224]DDL Job:%d not found", jobID, jobID)))
+}
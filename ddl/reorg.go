@@ -61,6 +61,10 @@ type reorgCtx struct {
 	// 0: job is not canceled.
 	// 1: job is canceled.
 	notifyCancelReorgJob int32
+	// notifyPauseReorgJob is used to notify the backfilling goroutine if the DDL job is paused.
+	// 0: job is not paused.
+	// 1: job is paused.
+	notifyPauseReorgJob int32
 	// doneHandle is used to simulate the handle that has been processed.
 
 	doneKey atomic.Value // nullable kv.Key
@@ -110,6 +114,18 @@ func (rc *reorgCtx) isReorgCanceled() bool {
 	return atomic.LoadInt32(&rc.notifyCancelReorgJob) == 1
 }
 
+func (rc *reorgCtx) notifyReorgPause() {
+	atomic.StoreInt32(&rc.notifyPauseReorgJob, 1)
+}
+
+func (rc *reorgCtx) cleanNotifyReorgPause() {
+	atomic.StoreInt32(&rc.notifyPauseReorgJob, 0)
+}
+
+func (rc *reorgCtx) isReorgPaused() bool {
+	return atomic.LoadInt32(&rc.notifyPauseReorgJob) == 1
+}
+
 func (rc *reorgCtx) setRowCount(count int64) {
 	atomic.StoreInt64(&rc.rowCount, count)
 }
@@ -190,6 +206,13 @@ func (rc *reorgCtx) clean() {
 // the additional ddl round.
 //
 // After that, we can make sure that the worker goroutine is correctly shut down.
+//
+// How can we pause reorg job?
+//
+// When `admin pause ddl jobs xxx` takes effect, the ddl goroutine sets the atomic pause variable in `pauseDDLJob`
+// and commits the paused state without waiting. The backfilling goroutine saves the processed handle to the reorg
+// handle before it quits, and errPausedDDLJob is fetched from doneCh after the job is resumed, then the reorg work
+// is restarted from that handle.
 func (w *worker) runReorgJob(t *meta.Meta, reorgInfo *reorgInfo, tblInfo *model.TableInfo, lease time.Duration, f func() error) error {
 	job := reorgInfo.Job
 	// This is for tests compatible, because most of the early tests try to build the reorg job manually
@@ -245,6 +268,13 @@ func (w *worker) runReorgJob(t *meta.Meta, reorgInfo *reorgInfo, tblInfo *model.
 		w.mergeWarningsIntoJob(job)
 
 		w.reorgCtx.clean()
+		// Clean up the pause signal whatever the result is. Make sure it can't affect the later reorg work.
+		w.reorgCtx.cleanNotifyReorgPause()
+		if errPausedDDLJob.Equal(err) {
+			// The job has been resumed after the paused backfilling goroutine quit,
+			// restart the reorg work from the saved handle in the next round.
+			return errWaitReorgTimeout
+		}
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -365,6 +395,11 @@ func (w *worker) isReorgRunnable(d *ddlCtx) error {
 		return errCancelledDDLJob
 	}
 
+	if w.reorgCtx.isReorgPaused() {
+		// Job is paused. The backfilling goroutine quits and the progress is kept in the reorg handle.
+		return errPausedDDLJob
+	}
+
 	if !d.isOwner() {
 		// If it's not the owner, we will try later, so here just returns an error.
 		logutil.BgLogger().Info("[ddl] DDL worker is not the DDL owner", zap.String("ID", d.uuid))
@@ -387,6 +422,11 @@ type reorgInfo struct {
 	PhysicalTableID int64
 	elements        []*meta.Element
 	currElement     *meta.Element
+	// ingestEngine is the engine the index entries are written to when the index is added by ingesting.
+	ingestEngine IngestEngine
+	// ingestNextKey is the key the entries have been written to the ingest engine up to. The reorg handle isn't
+	// updated to it until the entries are imported.
+	ingestNextKey kv.Key
 }
 
 func (r *reorgInfo) String() string {
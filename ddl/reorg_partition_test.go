@@ -0,0 +1,500 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorganizeRangePartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec(`create table t (a int, b varchar(20), key(b)) partition by range(a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 'a'), (11, 'b'), (15, 'c'), (19, 'd'), (25, 'e')")
+
+	// Split a partition.
+	tk.MustExec("alter table t reorganize partition p1 into (partition p1a values less than (15), partition p1b values less than (20))")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL,\n" +
+		"  KEY `b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (10),\n" +
+		" PARTITION `p1a` VALUES LESS THAN (15),\n" +
+		" PARTITION `p1b` VALUES LESS THAN (20),\n" +
+		" PARTITION `p2` VALUES LESS THAN (30))"))
+	tk.MustQuery("select * from t partition (p1a)").Check(testkit.Rows("11 b"))
+	tk.MustQuery("select * from t partition (p1b) order by a").Check(testkit.Rows("15 c", "19 d"))
+	tk.MustQuery("select a from t use index(b) where b = 'c'").Check(testkit.Rows("15"))
+	tk.MustExec("admin check table t")
+
+	// Merge partitions.
+	tk.MustExec("alter table t reorganize partition p0, p1a, p1b into (partition p0 values less than (20))")
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("1 a", "11 b", "15 c", "19 d"))
+	tk.MustQuery("select * from t partition (p2)").Check(testkit.Rows("25 e"))
+	tk.MustExec("admin check table t")
+
+	// The range of the last partition can be extended.
+	tk.MustExec("alter table t reorganize partition p2 into (partition p2 values less than (40), partition p3 values less than (maxvalue))")
+	tk.MustExec("insert into t values (35, 'f'), (100, 'g')")
+	tk.MustQuery("select * from t partition (p2) order by a").Check(testkit.Rows("25 e", "35 f"))
+	tk.MustQuery("select * from t partition (p3)").Check(testkit.Rows("100 g"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("7"))
+	tk.MustExec("admin check table t")
+
+	// Reorganize the range columns partitions.
+	tk.MustExec(`create table tc (a datetime, b int, primary key(a, b) clustered) partition by range columns(a) (
+		partition p0 values less than ('2022-01-01'),
+		partition p1 values less than ('2022-03-01'))`)
+	tk.MustExec("insert into tc values ('2021-12-31', 1), ('2022-01-15', 2), ('2022-02-15', 3)")
+	tk.MustExec("alter table tc reorganize partition p1 into (partition p202201 values less than ('2022-02-01'), partition p202202 values less than ('2022-03-01'))")
+	tk.MustQuery("select b from tc partition (p202201)").Check(testkit.Rows("2"))
+	tk.MustQuery("select b from tc partition (p202202)").Check(testkit.Rows("3"))
+	tk.MustExec("admin check table tc")
+}
+
+func TestReorganizeListPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+
+	tk.MustExec(`create table t (a int, b int, primary key(a) nonclustered, unique key(b, a)) partition by list(a) (
+		partition p0 values in (1, 2),
+		partition p1 values in (3, 4),
+		partition p2 values in (5, 6))`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	tk.MustExec("alter table t reorganize partition p0, p1 into (partition p0 values in (1, 3), partition p1 values in (2, 4))")
+	tk.MustQuery("select a from t partition (p0) order by a").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select a from t partition (p1) order by a").Check(testkit.Rows("2", "4"))
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("5"))
+	tk.MustExec("admin check table t")
+
+	// The rows which don't belong to any new partition make the job roll back.
+	tk.MustGetErrCode("alter table t reorganize partition p2 into (partition p2 values in (6))", errno.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("5"))
+	tk.MustExec("admin check table t")
+}
+
+func TestReorganizePartitionErrors(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t_normal (a int)")
+	tk.MustGetErrCode("alter table t_normal reorganize partition p0 into (partition p0 values less than (10))", errno.ErrPartitionMgmtOnNonpartitioned)
+
+	tk.MustExec("create table t_hash (a int) partition by hash(a) partitions 4")
+	tk.MustGetErrCode("alter table t_hash reorganize partition p0 into (partition p0)", errno.ErrUnsupportedDDLOperation)
+
+	tk.MustExec(`create table t (a int) partition by range(a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustGetErrCode("alter table t reorganize partition", errno.ErrReorgNoParam)
+	tk.MustGetErrCode("alter table t reorganize partition p3 into (partition p3 values less than (40))", errno.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode("alter table t reorganize partition p0, p0 into (partition p0 values less than (10))", errno.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode("alter table t reorganize partition p0, p2 into (partition p0 values less than (30))", errno.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0 values less than (5))", errno.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0 values less than (25))", errno.ErrRangeNotIncreasing)
+	tk.MustGetErrCode("alter table t reorganize partition p2 into (partition p2 values less than (25))", errno.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p1 values less than (10))", errno.ErrSameNamePartition)
+	tk.MustGetErrCode("alter table t reorganize partition p1 into (partition p1a values less than (15), partition p1b values less than (12))", errno.ErrRangeNotIncreasing)
+}
+
+func TestReorganizePartitionWithDML(t *testing.T) {
+	for _, clustered := range []string{"clustered", "nonclustered"} {
+		t.Run(clustered, func(t *testing.T) {
+			store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+			defer clean()
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec(fmt.Sprintf(`create table t (a int, b int, c int, primary key(a) %s, unique key(c, a), key(b)) partition by range(a) (
+				partition p0 values less than (100),
+				partition p1 values less than (200))`, clustered))
+			testPartitionDDLWithDML(t, tk, dom, model.ActionReorganizePartition,
+				"alter table t reorganize partition p0 into (partition p0a values less than (50), partition p0b values less than (100))")
+
+			result := tk.MustQuery("select * from t order by a").Rows()
+			tk.MustQuery("select * from t partition (p0a, p0b, p1) order by a").Check(result)
+			tk.MustQuery("select count(*) from t partition (p0a) where a >= 50").Check(testkit.Rows("0"))
+			tk.MustQuery("select count(*) from t partition (p0b) where a < 50 or a >= 100").Check(testkit.Rows("0"))
+		})
+	}
+}
+
+// testPartitionDDLWithDML runs the DDL which reorganizes the partitions of the table t(a, b, c), and inserts,
+// updates and deletes the records in every state of the DDL job, then checks the records and the indexes.
+func testPartitionDDLWithDML(t *testing.T, tk *testkit.TestKit, dom *domain.Domain, tp model.ActionType, ddlSQL string) {
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i*10, i, i))
+	}
+	tk1 := testkit.NewTestKit(t, tk.Session().GetStore())
+	tk1.MustExec("use test")
+
+	hook := &ddl.TestDDLCallback{Do: dom}
+	var checkErr error
+	states := make(map[model.SchemaState]struct{})
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != tp || checkErr != nil {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// Every state inserts, updates and deletes different rows.
+		base := 1 + len(states)
+		for _, sql := range []string{
+			fmt.Sprintf("insert into t values (%d, %d, %d)", base, base, base),
+			fmt.Sprintf("insert into t values (%d, %d, %d)", 100+base, base, 100+base),
+			fmt.Sprintf("update t set b = b + 100 where a = %d", base*10),
+			fmt.Sprintf("update t set a = a - 99 where a = %d", 100+base*10),
+			fmt.Sprintf("update t set a = a + 107 where a = %d", base*10),
+			fmt.Sprintf("update t set a = a + 50 where a = %d", base),
+			fmt.Sprintf("delete from t where a = %d", 50+base-1),
+		} {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				checkErr = fmt.Errorf("%s in state %s: %v", sql, job.SchemaState, checkErr)
+				return
+			}
+		}
+	}
+	originalHook := dom.DDL().GetHook()
+	dom.DDL().SetHook(hook)
+	tk.MustExec(ddlSQL)
+	dom.DDL().SetHook(originalHook)
+	require.NoError(t, checkErr)
+	require.Len(t, states, 5)
+
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("26"))
+	tk.MustQuery("select a from t use index(c) where c = 3").Check(testkit.Rows("137"))
+	tk.MustQuery("select a from t use index(b) where b = 105").Check(testkit.Rows("157"))
+}
+
+func TestAlterTablePartitioning(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (a int, b varchar(20), primary key(a) nonclustered, key(b))")
+	tk.MustExec("insert into t values (1, 'a'), (11, 'b'), (21, 'c')")
+	tk.MustExec("alter table t partition by range(a) (partition p0 values less than (10), partition p1 values less than (maxvalue))")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] NONCLUSTERED */,\n" +
+		"  KEY `b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (10),\n" +
+		" PARTITION `p1` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustQuery("select a from t partition (p0)").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t partition (p1) order by a").Check(testkit.Rows("11", "21"))
+	tk.MustQuery("select a from t use index(b) where b = 'b'").Check(testkit.Rows("11"))
+	tk.MustExec("admin check table t")
+
+	// Change the partitioning method.
+	tk.MustExec("alter table t partition by hash(a) partitions 3")
+	tk.MustQuery("select a from t partition (p0)").Check(testkit.Rows("21"))
+	tk.MustQuery("select a from t partition (p1)").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("11"))
+	tk.MustExec("insert into t values (5, 'd')")
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("5", "11"))
+	tk.MustExec("admin check table t")
+
+	tk.MustGetErrCode("alter table t partition by range columns(b) (partition p0 values less than ('m'), partition p1 values less than (maxvalue))", errno.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustGetErrCode("alter table t partition by range(a) (partition p0 values less than (10))", errno.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("4"))
+	tk.MustGetErrCode("alter table t partition by linear hash(a) partitions 3", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("admin check table t")
+}
+
+func TestRemovePartitioning(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t_normal (a int)")
+	tk.MustGetErrCode("alter table t_normal remove partitioning", errno.ErrPartitionMgmtOnNonpartitioned)
+
+	tk.MustExec(`create table t (a int, b int, unique key(a)) partition by range(a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("insert into t values (1, 1), (11, 11), (null, 2)")
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  UNIQUE KEY `a` (`a`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select a, b from t order by b").Check(testkit.Rows("1 1", "<nil> 2", "11 11"))
+	tk.MustExec("insert into t values (25, 25)")
+	tk.MustGetErrCode("insert into t values (11, 12)", errno.ErrDupEntry)
+	tk.MustQuery("select b from t use index(a) where a = 25").Check(testkit.Rows("25"))
+	tk.MustExec("admin check table t")
+}
+
+func TestAlterTablePartitioningWithDML(t *testing.T) {
+	for _, clustered := range []string{"clustered", "nonclustered"} {
+		t.Run(clustered, func(t *testing.T) {
+			store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+			defer clean()
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec(fmt.Sprintf("create table t (a int, b int, c int, primary key(a) %s, unique key(c, a), key(b))", clustered))
+			testPartitionDDLWithDML(t, tk, dom, model.ActionAlterTablePartitioning, "alter table t partition by hash(a) partitions 3")
+
+			result := tk.MustQuery("select * from t order by a").Rows()
+			tk.MustQuery("select * from t partition (p0, p1, p2) order by a").Check(result)
+			tk.MustQuery("select count(*) from t partition (p0) where a % 3 != 0").Check(testkit.Rows("0"))
+			tk.MustQuery("select count(*) from t partition (p1) where a % 3 != 1").Check(testkit.Rows("0"))
+		})
+	}
+}
+
+func TestRemovePartitioningWithDML(t *testing.T) {
+	for _, clustered := range []string{"clustered", "nonclustered"} {
+		t.Run(clustered, func(t *testing.T) {
+			store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+			defer clean()
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec(fmt.Sprintf(`create table t (a int, b int, c int, primary key(a) %s, unique key(c, a), key(b)) partition by range(a) (
+				partition p0 values less than (100),
+				partition p1 values less than (200))`, clustered))
+			testPartitionDDLWithDML(t, tk, dom, model.ActionRemovePartitioning, "alter table t remove partitioning")
+
+			is := dom.InfoSchema()
+			tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+			require.NoError(t, err)
+			require.Nil(t, tbl.Meta().Partition)
+		})
+	}
+}
+
+func TestPartitionDDLCheckTableWhenJobStarts(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_global_index = on")
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+
+	const rangeTable = "create table t (a int, b int) partition by range(a) (partition p0 values less than (10), partition p1 values less than (20))"
+	for _, c := range []struct {
+		createSQL    string
+		partitionSQL string
+		errCode      int
+	}{
+		{"create table t (a int, b int)", "alter table t partition by hash(a) partitions 3", errno.ErrUniqueKeyNeedAllFieldsInPf},
+		{rangeTable, "alter table t partition by hash(a) partitions 3", errno.ErrUnsupportedDDLOperation},
+		{rangeTable, "alter table t remove partitioning", errno.ErrUnsupportedDDLOperation},
+		{rangeTable, "alter table t reorganize partition p1 into (partition p1a values less than (15), partition p1b values less than (20))", errno.ErrUnsupportedDDLOperation},
+	} {
+		tk.MustExec("drop table if exists t")
+		tk.MustExec(c.createSQL)
+		tk.MustExec("insert into t values (1, 1), (11, 11)")
+
+		// The partition DDL is submitted before the unique index is added, it waits for the add index job.
+		var (
+			once         sync.Once
+			partitionErr = make(chan error, 1)
+			queueErr     error
+		)
+		hook := &ddl.TestDDLCallback{Do: dom}
+		hook.OnJobRunBeforeExported = func(job *model.Job) {
+			if job.Type != model.ActionAddIndex {
+				return
+			}
+			once.Do(func() {
+				go func() {
+					_, err := tk1.Exec(c.partitionSQL)
+					partitionErr <- err
+				}()
+				tk2 := testkit.NewTestKit(t, store)
+				for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+					if len(tk2.MustQuery(fmt.Sprintf("admin show ddl jobs where job_id > %d", job.ID)).Rows()) > 0 {
+						return
+					}
+					if time.Since(start) > 10*time.Second {
+						queueErr = fmt.Errorf("the partition DDL isn't queued")
+						return
+					}
+				}
+			})
+		}
+		originalHook := dom.DDL().GetHook()
+		dom.DDL().SetHook(hook)
+		tk.MustExec("alter table t add unique index idx(b)")
+		dom.DDL().SetHook(originalHook)
+		require.NoError(t, queueErr)
+
+		err := <-partitionErr
+		tErr, ok := errors.Cause(err).(*terror.Error)
+		require.Truef(t, ok, "%s: %v", c.partitionSQL, err)
+		require.Equal(t, c.errCode, int(terror.ToSQLError(tErr).Code), c.partitionSQL)
+		tk.MustExec("admin check table t")
+	}
+}
+
+func TestAddCoalesceHashPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (a int, b varchar(10), primary key(a), key(b)) partition by hash(a) partitions 3")
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, 'b%d')", i, i))
+	}
+	tk.MustExec("alter table t add partition partitions 2")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = 't' order by partition_name").Check(
+		testkit.Rows("p0", "p1", "p2", "p3", "p4"))
+	for i := 0; i < 5; i++ {
+		tk.MustQuery(fmt.Sprintf("select count(*) from t partition (p%d) where a %% 5 != %d", i, i)).Check(testkit.Rows("0"))
+	}
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("20"))
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("alter table t add partition (partition p5 comment 'new')")
+	tk.MustQuery("select count(*) from t partition (p5) where a % 6 != 5").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t partition (p5) order by a").Check(testkit.Rows("5", "11", "17"))
+	tk.MustGetErrCode("alter table t add partition (partition p6 values less than (100))", errno.ErrPartitionWrongValues)
+	tk.MustGetErrCode("alter table t add partition (partition p0)", errno.ErrSameNamePartition)
+	tk.MustGetErrCode("alter table t add partition partitions 0", errno.ErrAddPartitionNoNewPartition)
+
+	tk.MustExec("alter table t coalesce partition 4")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY HASH (`a`) PARTITIONS 2"))
+	tk.MustQuery("select count(*) from t partition (p1) where a % 2 != 1").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("20"))
+	tk.MustQuery("select a from t use index(b) where b = 'b7'").Check(testkit.Rows("7"))
+	tk.MustGetErrCode("alter table t coalesce partition 2", errno.ErrDropLastPartition)
+	tk.MustGetErrCode("alter table t coalesce partition 0", errno.ErrCoalescePartitionNoPartition)
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0)", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("admin check table t")
+}
+
+func TestKeyPartition(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (a varchar(10), b int, c datetime, primary key(a, b) nonclustered) partition by key(a, b) partitions 4")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` varchar(10) NOT NULL,\n" +
+		"  `b` int(11) NOT NULL,\n" +
+		"  `c` datetime DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`,`b`) /*T![clustered_index] NONCLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY KEY (`a`,`b`) PARTITIONS 4"))
+	tk.MustQuery("select partition_method, partition_expression from information_schema.partitions where table_schema = 'test' and table_name = 't' and partition_name = 'p0'").Check(
+		testkit.Rows("KEY a,b"))
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values ('a%d', %d, '2021-01-01 00:00:%02d')", i%5, i, i))
+	}
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("20"))
+	// The rows are found in the partitions located by the key.
+	for i := 0; i < 20; i++ {
+		tk.MustQuery(fmt.Sprintf("select b from t where a = 'a%d' and b = %d", i%5, i)).Check(testkit.Rows(fmt.Sprintf("%d", i)))
+	}
+	tk.MustGetErrCode("insert into t values ('a0', 0, null)", errno.ErrDupEntry)
+	tk.MustExec("admin check table t")
+
+	// The columns of the primary key are the partitioning columns of KEY().
+	tk.MustExec("create table t1 (a int primary key, b int) partition by key() partitions 3")
+	tk.MustQuery("show create table t1").Check(testkit.Rows("t1 CREATE TABLE `t1` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY KEY (`a`) PARTITIONS 3"))
+	tk.MustExec("create table t2 (a int, b int) partition by key(b) (partition p0, partition pOther comment 'other')")
+	tk.MustQuery("show create table t2").Check(testkit.Rows("t2 CREATE TABLE `t2` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY KEY (`b`)\n" +
+		"(PARTITION `p0`,\n" +
+		" PARTITION `pOther` COMMENT 'other')"))
+
+	// Linear key is not supported and the table is created as a normal table.
+	tk.MustExec("create table t3 (a int) partition by linear key(a) partitions 3")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8200 Unsupported partition type KEY, treat as normal table"))
+}
+
+func TestAddCoalesceKeyPartitionWithDML(t *testing.T) {
+	for _, clustered := range []string{"clustered", "nonclustered"} {
+		t.Run(clustered, func(t *testing.T) {
+			store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+			defer clean()
+			tk := testkit.NewTestKit(t, store)
+			tk.MustExec("use test")
+			tk.MustExec(fmt.Sprintf("create table t (a int, b int, c int, primary key(a) %s, unique key(c, a), key(b)) partition by key(a) partitions 3", clustered))
+			testPartitionDDLWithDML(t, tk, dom, model.ActionReorganizePartition, "alter table t add partition partitions 2")
+
+			result := tk.MustQuery("select * from t order by a").Rows()
+			tk.MustQuery("select * from t partition (p0, p1, p2, p3, p4) order by a").Check(result)
+			for _, row := range result {
+				tk.MustQuery(fmt.Sprintf("select c from t where a = %s", row[0])).Check(testkit.Rows(fmt.Sprintf("%s", row[2])))
+			}
+		})
+	}
+}
+
+func TestCoalesceHashPartitionWithDML(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int, primary key(a), unique key(c, a), key(b)) partition by hash(a) partitions 5")
+	testPartitionDDLWithDML(t, tk, dom, model.ActionReorganizePartition, "alter table t coalesce partition 2")
+
+	tk.MustQuery("select count(*) from t partition (p0) where a % 3 != 0").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t partition (p1) where a % 3 != 1").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t partition (p2) where a % 3 != 2").Check(testkit.Rows("0"))
+}
@@ -259,3 +259,63 @@ func (s *testDDLSuite) TestReorgOwner(c *C) {
 	})
 	c.Assert(err, IsNil)
 }
+
+func (s *testDDLSuite) TestPauseReorgJob(c *C) {
+	store := testCreateStore(c, "test_pause_reorg")
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+
+	d, err := testNewDDLAndStart(
+		context.Background(),
+		WithStore(store),
+		WithLease(testLease),
+	)
+	c.Assert(err, IsNil)
+	defer func() {
+		err := d.Stop()
+		c.Assert(err, IsNil)
+	}()
+
+	w := d.generalWorker()
+	// Mock a backfilling goroutine which hasn't quit yet.
+	w.reorgCtx.doneCh = make(chan error, 1)
+	w.reorgCtx.setRowCount(10)
+	job := &model.Job{
+		ID:          1,
+		State:       model.JobStatePausing,
+		SnapshotVer: 1,
+	}
+	txn, err := store.Begin()
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(txn.Rollback(), IsNil)
+	}()
+	m := meta.NewMeta(txn)
+	c.Assert(m.EnQueueDDLJob(job), IsNil)
+
+	// The job is paused without waiting for the backfilling goroutine.
+	c.Assert(w.pauseDDLJob(m, job), IsNil)
+	c.Assert(job.IsPaused(), IsTrue)
+	c.Assert(job.RowCount, Equals, int64(10))
+	c.Assert(w.reorgCtx.isReorgPaused(), IsTrue)
+	c.Assert(w.reorgCtx.doneCh, NotNil)
+	c.Assert(w.isReorgRunnable(d.ddlCtx), ErrorMatches, ".*Paused DDL job.*")
+
+	// The backfilling goroutine quits, and the error is pulled out after the job is resumed.
+	w.reorgCtx.doneCh <- errPausedDDLJob
+	job.State = model.JobStateRunning
+	rInfo := &reorgInfo{
+		Job:         job,
+		currElement: &meta.Element{ID: 333, TypeKey: meta.IndexElementKey},
+	}
+	mockTbl := tables.MockTableFromMeta(&model.TableInfo{IsCommonHandle: s.IsCommonHandle, CommonHandleVersion: 1})
+	err = w.runReorgJob(m, rInfo, mockTbl.Meta(), d.lease, func() error {
+		c.Fatal("the reorg work shouldn't be restarted in this round")
+		return nil
+	})
+	c.Assert(errWaitReorgTimeout.Equal(err), IsTrue)
+	c.Assert(w.reorgCtx.isReorgPaused(), IsFalse)
+	c.Assert(w.reorgCtx.doneCh, IsNil)
+}
@@ -122,7 +122,7 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	if err != nil {
 		return ver, err
 	}
-	if !needChangeColumnData(oldCol, jp.newCol) {
+	if !needChangeColumnData(tblInfo, oldCol, jp.newCol) {
 		// Normal-type rolling back
 		if job.SchemaState == model.StateNone {
 			// When change null to not null, although state is unchanged with none, the oldCol flag's has been changed to preNullInsertFlag.
@@ -151,7 +151,7 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 }
 
 func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, _, _, constraints, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -161,11 +161,19 @@ func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
 	}
 
 	originalState := columnInfo.State
-	columnInfo.State = model.StateDeleteOnly
-	job.SchemaState = model.StateDeleteOnly
+	if columnInfo.State == model.StatePublic {
+		// The column is public and its check constraints are being verified,
+		// remove them and drop the column from the public state.
+		for _, constr := range constraints {
+			removeCheckConstraint(tblInfo, constr.Name)
+		}
+	} else {
+		columnInfo.State = model.StateDeleteOnly
+		job.SchemaState = model.StateDeleteOnly
+	}
 
 	job.Args = []interface{}{col.Name}
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State || len(constraints) > 0)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -366,6 +374,55 @@ func rollingbackAddTablePartition(t *meta.Meta, job *model.Job) (ver int64, err
 	return convertAddTablePartitionJob2RollbackJob(t, job, errCancelledDDLJob, tblInfo)
 }
 
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// Reorg workers are started, we have to ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		// Give the this kind of ddl one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+		return w.onReorganizePartition(d, t, job)
+	}
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateDeleteReorganization:
+		// The new partitions have replaced the old ones, the job can't be rolled back.
+		// Normally won't fetch here, because there is check when cancel ddl jobs. see function: isJobRollbackable.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	// The new partitions are added, remove them in the next round.
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
+func rollingbackExchangeTablePartition(job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateWriteOnly:
+		// Decode the arguments, so they are kept for removing the fence in the next round.
+		var (
+			defID, ptSchemaID, ptID int64
+			partName                string
+			withValidation          bool
+		)
+		if err = job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		job.State = model.JobStateRollingback
+		return ver, errCancelledDDLJob
+	}
+	// The partition has been exchanged, the job can't be rolled back.
+	// Normally won't fetch here, because there is check when cancel ddl jobs. see function: isJobRollbackable.
+	job.State = model.JobStateRunning
+	return ver, nil
+}
+
 func rollingbackDropTableOrView(t *meta.Meta, job *model.Job) error {
 	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
 	if err != nil {
@@ -431,6 +488,16 @@ func cancelOnlyNotHandledJob(job *model.Job) (ver int64, err error) {
 	return ver, nil
 }
 
+// rollingbackAddForeignKey cancels the foreign key which isn't added yet, or rolls back the one in write only state.
+func rollingbackAddForeignKey(job *model.Job) (ver int64, err error) {
+	if job.SchemaState == model.StateNone {
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackTruncateTable(t *meta.Meta, job *model.Job) (ver int64, err error) {
 	_, err = getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
 	if err != nil {
@@ -463,6 +530,10 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		err = rollingbackDropTableOrView(t, job)
 	case model.ActionDropTablePartition:
 		ver, err = rollingbackDropTablePartition(t, job)
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
+	case model.ActionExchangeTablePartition:
+		ver, err = rollingbackExchangeTablePartition(job)
 	case model.ActionDropSchema:
 		err = rollingbackDropSchema(t, job)
 	case model.ActionRenameIndex:
@@ -471,12 +542,17 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
-	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
-		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
+	case model.ActionMultiSchemaChange:
+		ver, err = rollingbackMultiSchemaChange(job)
+	case model.ActionAddForeignKey:
+		ver, err = rollingbackAddForeignKey(job)
+	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition, model.ActionModifySchemaDefaultPlacement:
+		model.ActionModifySchemaDefaultPlacement,
+		model.ActionAddCheckConstraint, model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint,
+		model.ActionAlterTTLInfo, model.ActionAlterTTLRemove:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
@@ -604,7 +604,7 @@ func (s *testSerialSuite) TestCreateTableWithLikeAtTemporaryMode(c *C) {
 
 	// Test foreign key.
 	tk.MustExec("drop table if exists test_foreign_key, t1")
-	tk.MustExec("create table t1 (a int, b int);")
+	tk.MustExec("create table t1 (a int, b int, index(b));")
 	tk.MustExec("create table test_foreign_key (c int,d int,foreign key (d) references t1 (b));")
 	defer tk.MustExec("drop table if exists test_foreign_key, t1;")
 	tk.MustExec("create global temporary table test_foreign_key_temp like test_foreign_key on commit delete rows;")
@@ -689,7 +689,7 @@ func (s *testSerialSuite) TestCreateTableWithLikeAtTemporaryMode(c *C) {
 	defer tk.MustExec("drop table if exists partition_table, tmp_partition_table")
 
 	tk.MustExec("drop table if exists foreign_key_table1, foreign_key_table2, foreign_key_tmp;")
-	tk.MustExec("create table foreign_key_table1 (a int, b int);")
+	tk.MustExec("create table foreign_key_table1 (a int, b int, index(b));")
 	tk.MustExec("create table foreign_key_table2 (c int,d int,foreign key (d) references foreign_key_table1 (b));")
 	tk.MustExec("create temporary table foreign_key_tmp like foreign_key_table2")
 	is = tk.Se.(sessionctx.Context).GetInfoSchema().(infoschema.InfoSchema)
@@ -1548,10 +1548,16 @@ func (s *testSerialSuite) TestModifyingColumn4NewCollations(c *C) {
 
 	tk.MustExec("alter table t add index b_idx(b)")
 	tk.MustExec("alter table t add index c_idx(c)")
-	tk.MustGetErrMsg("alter table t modify b varchar(10) collate utf8_general_ci", "[ddl:8200]Unsupported modifying collation of column 'b' from 'utf8_bin' to 'utf8_general_ci' when index is defined on it.")
-	tk.MustGetErrMsg("alter table t modify c varchar(10) collate utf8_bin", "[ddl:8200]Unsupported modifying collation of column 'c' from 'utf8_general_ci' to 'utf8_bin' when index is defined on it.")
-	tk.MustGetErrMsg("alter table t modify c varchar(10) collate utf8_unicode_ci", "[ddl:8200]Unsupported modifying collation of column 'c' from 'utf8_general_ci' to 'utf8_unicode_ci' when index is defined on it.")
-	tk.MustGetErrMsg("alter table t convert to charset utf8 collate utf8_general_ci", "[ddl:8200]Unsupported converting collation of column 'b' from 'utf8_bin' to 'utf8_general_ci' when index is defined on it.")
+	// The indexes are rebuilt when the collation of the column is changed.
+	tk.MustExec("alter table t modify b varchar(10) collate utf8_general_ci")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_bin")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_unicode_ci")
+	tk.MustExec("alter table t convert to charset utf8 collate utf8_bin")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_general_ci")
+	tk.MustExec("admin check table t")
+	// The partitioned table doesn't support rebuilding the indexes.
+	tk.MustExec("create table tp(a int, b varchar(10) collate utf8_bin, index b_idx(b)) partition by hash(a) partitions 2")
+	tk.MustGetErrCode("alter table tp modify b varchar(10) collate utf8_general_ci", errno.ErrUnsupportedDDLOperation)
 	// Change to a compatible collation is allowed.
 	tk.MustExec("alter table t modify c varchar(10) collate utf8mb4_general_ci")
 	// Change the default collation of table is allowed.
@@ -977,6 +977,10 @@ func onModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job) (ver int64, _
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
+	// The columns are converted by the column-type-change sub-jobs before it in the multi-schema change.
+	if checkAndMarkNonRevertible(job) {
+		return ver, nil
+	}
 
 	tblInfo.Charset = toCharset
 	tblInfo.Collate = toCollate
@@ -985,6 +989,14 @@ func onModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job) (ver int64, _
 		// update column charset.
 		for _, col := range tblInfo.Columns {
 			if field_types.HasCharset(&col.FieldType) {
+				newCol := col.Clone()
+				newCol.Charset, newCol.Collate = toCharset, toCollate
+				// The data of the column should have been rewritten by the column-type-change sub-jobs.
+				if needChangeColumnData(tblInfo, col, newCol) {
+					job.State = model.JobStateCancelled
+					msg := fmt.Sprintf("charset of column '%s' from %s to %s without data reorganization", col.Name.O, col.Charset, toCharset)
+					return ver, errors.Trace(errUnsupportedModifyCharset.GenWithStackByArgs(msg))
+				}
 				col.Charset = toCharset
 				col.Collate = toCollate
 			} else {
@@ -0,0 +1,228 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// getTTLInfoInOptions gets the TTL config in the table options. The TTL_ENABLE and TTL_JOB_INTERVAL
+// options are returned separately, because they can be altered without the TTL option.
+func getTTLInfoInOptions(options []*ast.TableOption) (ttlInfo *model.TTLInfo, ttlEnable *bool, ttlJobInterval *string, err error) {
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionTTL:
+			var sb strings.Builder
+			restoreCtx := format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreNameBackQuotes, &sb)
+			if err = op.Expr.Restore(restoreCtx); err != nil {
+				return nil, nil, nil, errors.Trace(err)
+			}
+			ttlInfo = &model.TTLInfo{
+				ColumnName:       op.ColumnName.Name,
+				IntervalExprStr:  sb.String(),
+				IntervalTimeUnit: int(op.TimeUnitValue.Unit),
+				Enable:           true,
+				JobInterval:      model.DefaultTTLJobInterval,
+			}
+		case ast.TableOptionTTLEnable:
+			enable := op.BoolValue
+			ttlEnable = &enable
+		case ast.TableOptionTTLJobInterval:
+			interval := op.StrValue
+			ttlJobInterval = &interval
+		}
+	}
+	return ttlInfo, ttlEnable, ttlJobInterval, nil
+}
+
+// updateTTLInfo applies the TTL options to the table. The TTL_ENABLE and TTL_JOB_INTERVAL of
+// the old TTL config are kept if they are not set explicitly.
+func updateTTLInfo(tblInfo *model.TableInfo, ttlInfo *model.TTLInfo, ttlEnable *bool, ttlJobInterval *string) error {
+	if ttlInfo != nil {
+		ttlInfo = ttlInfo.Clone()
+		if tblInfo.TTLInfo != nil {
+			ttlInfo.Enable = tblInfo.TTLInfo.Enable
+			ttlInfo.JobInterval = tblInfo.TTLInfo.JobInterval
+		}
+		tblInfo.TTLInfo = ttlInfo
+	}
+	if ttlEnable != nil {
+		if tblInfo.TTLInfo == nil {
+			return ErrSetTTLOptionForNonTTLTable.GenWithStackByArgs("TTL_ENABLE")
+		}
+		tblInfo.TTLInfo.Enable = *ttlEnable
+	}
+	if ttlJobInterval != nil {
+		if tblInfo.TTLInfo == nil {
+			return ErrSetTTLOptionForNonTTLTable.GenWithStackByArgs("TTL_JOB_INTERVAL")
+		}
+		tblInfo.TTLInfo.JobInterval = *ttlJobInterval
+	}
+	return nil
+}
+
+// checkTTLInfoValid checks the TTL config of the table.
+func checkTTLInfoValid(ctx sessionctx.Context, tblInfo *model.TableInfo) error {
+	ttlInfo := tblInfo.TTLInfo
+	if ttlInfo == nil {
+		return nil
+	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		return ErrTempTableNotAllowedWithTTL
+	}
+	if err := checkTTLColumn(tblInfo, ttlInfo.ColumnName); err != nil {
+		return err
+	}
+	if interval, err := ttlInfo.GetJobInterval(); err != nil || interval <= 0 {
+		return ErrInvalidTTLOption.GenWithStackByArgs(fmt.Sprintf("TTL_JOB_INTERVAL '%s'", ttlInfo.JobInterval))
+	}
+	// The interval is added to a fixed time, so only the constant intervals are accepted.
+	unit := ast.TimeUnitType(ttlInfo.IntervalTimeUnit).String()
+	exprStr := fmt.Sprintf("DATE_ADD('2000-01-01 00:00:00', INTERVAL %s %s)", ttlInfo.IntervalExprStr, unit)
+	expr, err := expression.ParseSimpleExprWithTableInfo(ctx, exprStr, &model.TableInfo{})
+	if err != nil {
+		return ErrInvalidTTLOption.GenWithStackByArgs(fmt.Sprintf("INTERVAL %s %s", ttlInfo.IntervalExprStr, unit))
+	}
+	d, err := expr.Eval(chunk.Row{})
+	if err != nil || d.IsNull() {
+		return ErrInvalidTTLOption.GenWithStackByArgs(fmt.Sprintf("INTERVAL %s %s", ttlInfo.IntervalExprStr, unit))
+	}
+	return nil
+}
+
+// checkTTLColumn checks the column can be used as the TTL column.
+func checkTTLColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
+	col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+	if col == nil {
+		return ErrBadField.GenWithStackByArgs(colName.O, "TTL config")
+	}
+	if !types.IsTypeTime(col.Tp) {
+		return ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(colName.O)
+	}
+	return nil
+}
+
+// AlterTableTTLInfoOrEnable alters the TTL config of the table.
+func (d *ddl) AlterTableTTLInfoOrEnable(ctx sessionctx.Context, ident ast.Ident, ttlInfo *model.TTLInfo, ttlEnable *bool, ttlJobInterval *string) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check the TTL config on a copy of the table info.
+	tblInfo := tb.Meta().Clone()
+	if err = updateTTLInfo(tblInfo, ttlInfo, ttlEnable, ttlJobInterval); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkTTLInfoValid(ctx, tblInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLInfo,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{ttlInfo, ttlEnable, ttlJobInterval},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTableRemoveTTL removes the TTL config of the table.
+func (d *ddl) AlterTableRemoveTTL(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tb.Meta().TTLInfo == nil {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLRemove,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func onTTLInfoChange(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	var (
+		ttlInfo        *model.TTLInfo
+		ttlEnable      *bool
+		ttlJobInterval *string
+	)
+	if err := job.DecodeArgs(&ttlInfo, &ttlEnable, &ttlJobInterval); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = updateTTLInfo(tblInfo, ttlInfo, ttlEnable, ttlJobInterval); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	// The column may be changed by the DDL jobs running before this one.
+	if err = checkTTLColumn(tblInfo, tblInfo.TTLInfo.ColumnName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onTTLInfoRemove(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo.TTLInfo = nil
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
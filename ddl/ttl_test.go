@@ -0,0 +1,87 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+)
+
+func TestCreateTableWithTTL(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id int primary key, created_at datetime) ttl = created_at + interval 30 day")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `created_at` datetime DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![ttl] TTL=`created_at` + INTERVAL 30 DAY */ /*T![ttl] TTL_ENABLE='ON' */ /*T![ttl] TTL_JOB_INTERVAL='1h' */"))
+
+	tk.MustExec("create table t1 (id int, created_at date) ttl = `created_at` + interval 2 month ttl_enable = 'off' ttl_job_interval = '24h'")
+	tk.MustQuery("show create table t1").Check(testkit.Rows("t1 CREATE TABLE `t1` (\n" +
+		"  `id` int(11) DEFAULT NULL,\n" +
+		"  `created_at` date DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![ttl] TTL=`created_at` + INTERVAL 2 MONTH */ /*T![ttl] TTL_ENABLE='OFF' */ /*T![ttl] TTL_JOB_INTERVAL='24h' */"))
+
+	tk.MustGetErrCode("create table t2 (id int, created_at int) ttl = created_at + interval 1 day", errno.ErrUnsupportedColumnInTTLConfig)
+	tk.MustGetErrCode("create table t2 (id int) ttl = created_at + interval 1 day", errno.ErrBadField)
+	tk.MustGetErrCode("create table t2 (id int, created_at datetime) ttl_enable = 'on'", errno.ErrSetTTLOptionForNonTTLTable)
+	tk.MustGetErrCode("create table t2 (id int, created_at datetime) ttl = created_at + interval 1 day ttl_job_interval = 'abc'", errno.ErrInvalidTTLOption)
+	tk.MustGetErrCode("create table t2 (id int, created_at datetime) ttl = created_at + interval id day", errno.ErrInvalidTTLOption)
+	tk.MustGetErrCode("create global temporary table t2 (id int, created_at datetime) ttl = created_at + interval 1 day on commit delete rows", errno.ErrTempTableNotAllowedWithTTL)
+}
+
+func TestAlterTableTTL(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, created_at datetime, updated_at timestamp, v int)")
+
+	tk.MustGetErrCode("alter table t ttl_enable = 'off'", errno.ErrSetTTLOptionForNonTTLTable)
+	tk.MustGetErrCode("alter table t ttl = v + interval 1 day", errno.ErrUnsupportedColumnInTTLConfig)
+	tk.MustExec("alter table t ttl = created_at + interval 1 day ttl_job_interval = '2h'")
+	tk.MustExec("alter table t ttl_enable = 'off'")
+	// Resetting the TTL keeps the TTL_ENABLE and TTL_JOB_INTERVAL options.
+	tk.MustExec("alter table t ttl = updated_at + interval 10 hour")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `created_at` datetime DEFAULT NULL,\n" +
+		"  `updated_at` timestamp NULL DEFAULT NULL,\n" +
+		"  `v` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![ttl] TTL=`updated_at` + INTERVAL 10 HOUR */ /*T![ttl] TTL_ENABLE='OFF' */ /*T![ttl] TTL_JOB_INTERVAL='2h' */"))
+
+	// The TTL column can't be dropped or changed to a non-time type, and is renamed with the column.
+	tk.MustGetErrCode("alter table t drop column updated_at", errno.ErrTTLColumnCannotDrop)
+	tk.MustGetErrCode("alter table t modify column updated_at int", errno.ErrUnsupportedColumnInTTLConfig)
+	tk.MustExec("alter table t change column updated_at expired_at datetime")
+	tk.MustExec("alter table t drop column created_at")
+
+	tk.MustExec("alter table t remove ttl")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `expired_at` datetime DEFAULT NULL,\n" +
+		"  `v` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("alter table t remove ttl")
+	tk.MustExec("alter table t drop column expired_at")
+}
@@ -50,12 +50,15 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/telemetry"
+	"github.com/pingcap/tidb/ttl"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/domainutil"
 	"github.com/pingcap/tidb/util/expensivequery"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/tikv/client-go/v2/txnkv/transaction"
 	"go.etcd.io/etcd/clientv3"
@@ -90,7 +93,9 @@ type Domain struct {
 	cancel               context.CancelFunc
 	indexUsageSyncLease  time.Duration
 	planReplayer         *planReplayer
+	ttlJobManager        *ttl.JobManager
 	expiredTimeStamp4PC  types.Time
+	instancePlanCache    *kvcache.SyncLRUCache
 
 	serverID             uint64
 	serverIDSession      *concurrency.Session
@@ -353,6 +358,11 @@ func (do *Domain) SetExpiredTimeStamp4PC(time types.Time) {
 	do.expiredTimeStamp4PC = time
 }
 
+// InstancePlanCache gets the plan cache shared by all the sessions of the instance.
+func (do *Domain) InstancePlanCache() *kvcache.SyncLRUCache {
+	return do.instancePlanCache
+}
+
 // DDL gets DDL from domain.
 func (do *Domain) DDL() ddl.DDL {
 	return do.ddl
@@ -731,6 +741,7 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 		onClose:             onClose,
 		renewLeaseCh:        make(chan func(), 10),
 		expiredTimeStamp4PC: types.NewTime(types.ZeroCoreTime, mysql.TypeTimestamp, types.DefaultFsp),
+		instancePlanCache:   kvcache.NewSyncLRUCache(memory.LabelForInstancePlanCache, variable.InstancePlanCacheMaxMemSize.Load()),
 	}
 
 	do.SchemaValidator = NewSchemaValidator(ddlLease, do)
@@ -1230,6 +1241,52 @@ func (do *Domain) PlanReplayerLoop() {
 	}()
 }
 
+// StartTTLJobManager starts the loop to run the jobs which delete the expired rows of the TTL tables.
+// Only the TTL job owner runs the jobs. It should be called only once in BootstrapSession.
+func (do *Domain) StartTTLJobManager() {
+	owner := do.newOwnerManager(ttl.Prompt, ttl.OwnerKey)
+	do.ttlJobManager = ttl.NewJobManager(do.store, do.sysSessionPool, owner)
+	do.wg.Add(1)
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		ticker := time.NewTicker(ttl.CheckInterval)
+		defer func() {
+			ticker.Stop()
+			cancel()
+			do.wg.Done()
+			logutil.BgLogger().Info("ttlJobLoop exited.")
+			util.Recover(metrics.LabelDomain, "ttlJobLoop", nil, false)
+		}()
+		// The running jobs are stopped when the domain exits.
+		go func() {
+			select {
+			case <-do.exit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		for {
+			select {
+			case <-do.exit:
+				owner.Cancel()
+				return
+			case <-ticker.C:
+				if !owner.IsOwner() {
+					continue
+				}
+				if err := do.ttlJobManager.RunJobsOnce(ctx, do.InfoSchema()); err != nil {
+					logutil.BgLogger().Warn("run TTL jobs failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// TTLJobManager returns the manager of the TTL jobs.
+func (do *Domain) TTLJobManager() *ttl.JobManager {
+	return do.ttlJobManager
+}
+
 // StatsHandle returns the statistic handle.
 func (do *Domain) StatsHandle() *handle.Handle {
 	return (*handle.Handle)(atomic.LoadPointer(&do.statsHandle))
@@ -822,6 +822,7 @@ const (
 	ErrRowInWrongPartition                                   = 1863
 	ErrErrorLast                                             = 1863
 	ErrMaxExecTimeExceeded                                   = 1907
+	ErrFkDepthExceeded                                       = 3008
 	ErrInvalidFieldSize                                      = 3013
 	ErrInvalidArgumentForLogarithm                           = 3020
 	ErrAggregateOrderNonAggQuery                             = 3029
@@ -901,6 +902,14 @@ const (
 	ErrGeneratedColumnRowValueIsNotAllowed                   = 3764
 	ErrFKIncompatibleColumns                                 = 3780
 	ErrFunctionalIndexRowValueIsNotAllowed                   = 3800
+	ErrColumnCheckConstraintReferencesOtherColumn            = 3813
+	ErrCheckConstraintNamedFunctionIsNotAllowed              = 3814
+	ErrCheckConstraintFunctionIsNotAllowed                   = 3815
+	ErrCheckConstraintVariables                              = 3816
+	ErrCheckConstraintRefersAutoIncrementColumn              = 3818
+	ErrCheckConstraintViolated                               = 3819
+	ErrCheckConstraintRefersUnknownColumn                    = 3820
+	ErrCheckConstraintDupName                                = 3822
 	ErrDependentByFunctionalIndex                            = 3837
 	ErrCannotConvertString                                   = 3854
 	ErrInvalidJSONValueForFuncIndex                          = 3903
@@ -908,6 +917,8 @@ const (
 	ErrFunctionalIndexDataIsTooLong                          = 3907
 	ErrFunctionalIndexNotApplicable                          = 3909
 	ErrDynamicPrivilegeNotRegistered                         = 3929
+	ErrConstraintNotFound                                    = 3940
+	ErrDependentByCheckConstraint                            = 3959
 	// MariaDB errors.
 	ErrOnlyOneDefaultPartionAllowed         = 4030
 	ErrWrongPartitionTypeExpectedSystemTime = 4113
@@ -1057,6 +1068,20 @@ const (
 	ErrPlacementPolicyInUse               = 8241
 	ErrOptOnCacheTable                    = 8242
 	ErrHTTPServiceError                   = 8243
+	ErrOperateSameColumn                  = 8245
+	ErrOperateSameIndex                   = 8246
+	ErrUnsupportedColumnInTTLConfig       = 8247
+	ErrTTLColumnCannotDrop                = 8248
+	ErrSetTTLOptionForNonTTLTable         = 8249
+	ErrTempTableNotAllowedWithTTL         = 8250
+	ErrInvalidTTLOption                   = 8251
+	ErrSavepointNotSupportedWithBinlog    = 8252
+	ErrInvalidIntervalPartition           = 8253
+	ErrPausedDDLJob                       = 8254
+	ErrCannotPauseDDLJob                  = 8255
+	ErrCannotResumeDDLJob                 = 8256
+	ErrExchangePartitionInProgress        = 8257
+	ErrNoHistoricalStats                  = 8258
 	// TiKV/PD/TiFlash errors.
 	ErrPDServerTimeout           = 9001
 	ErrTiKVServerTimeout         = 9002
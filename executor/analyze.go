@@ -124,6 +124,8 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	// The meaning of key in map is the structure that used to store the tableID and indexID.
 	// The meaning of value in map is some additional information needed to build global-level stats.
 	globalStatsMap := make(map[globalStatsKey]globalStatsInfo)
+	// analyzedTables records the tables whose stats are saved, their stats are kept in the stats history if needed.
+	analyzedTables := make(map[int64]struct{})
 	finishJobWithLogFn := func(ctx context.Context, job *statistics.AnalyzeJob, meetError bool) {
 		job.Finish(meetError)
 		if job != nil {
@@ -157,7 +159,7 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 					globalStatsID := globalStatsKey{tableID: results.TableID.TableID, indexID: int64(-1)}
 					histIDs := make([]int64, 0, len(result.Hist))
 					for _, hg := range result.Hist {
-						// It's normal virtual column, skip.
+						// The column has no histogram, skip.
 						if hg == nil {
 							continue
 						}
@@ -177,6 +179,7 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			logutil.Logger(ctx).Error("save table stats to storage failed", zap.Error(err))
 			finishJobWithLogFn(ctx, results.Job, true)
 		} else {
+			analyzedTables[results.TableID.TableID] = struct{}{}
 			finishJobWithLogFn(ctx, results.Job, false)
 		}
 	}
@@ -217,7 +220,39 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if err != nil {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 	}
-	return statsHandle.Update(e.ctx.GetInfoSchema().(infoschema.InfoSchema))
+	if err = statsHandle.Update(e.ctx.GetInfoSchema().(infoschema.InfoSchema)); err != nil {
+		return err
+	}
+	e.recordHistoricalStats(ctx, analyzedTables)
+	return nil
+}
+
+// recordHistoricalStats keeps the new stats of the analyzed tables in mysql.stats_history when
+// tidb_enable_historical_stats is on, so that they can be restored by RESTORE STATS later.
+func (e *AnalyzeExec) recordHistoricalStats(ctx context.Context, tableIDs map[int64]struct{}) {
+	if len(tableIDs) == 0 {
+		return
+	}
+	val, err := e.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBEnableHistoricalStats)
+	if err != nil || !variable.TiDBOptOn(val) {
+		return
+	}
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	for tableID := range tableIDs {
+		tbl, ok := is.TableByID(tableID)
+		if !ok {
+			continue
+		}
+		db, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		if _, err := statsHandle.RecordHistoricalStatsToStorage(db.Name.O, tbl.Meta()); err != nil {
+			logutil.Logger(ctx).Warn("record historical stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		}
+	}
 }
 
 func (e *AnalyzeExec) saveAnalyzeOptsV2() error {
@@ -674,7 +709,6 @@ func analyzeColumnsPushdown(colExec *AnalyzeColumnsExec) *statistics.AnalyzeResu
 		// Because the process of analyzing will keep the order of results be the same as the colsInfo in the analyze task,
 		// and in `buildAnalyzeFullSamplingTask` we always place the _tidb_rowid at the last of colsInfo, so if there are
 		// stats for _tidb_rowid, it must be at the end of the column stats.
-		// Virtual column has no histogram yet. So we check nil here.
 		if hists[cLen-1] != nil && hists[cLen-1].ID == -1 {
 			cLen -= 1
 		}
@@ -860,6 +894,55 @@ func (e AnalyzeColumnsExec) decodeSampleDataWithVirtualColumn(
 	return nil
 }
 
+// buildVirtualColumnStats fills the null count, FM sketch and total size of the virtual generated columns.
+// TiKV can't evaluate the virtual columns, so the collector only contains NULLs for them. We use the result of
+// the special index if the virtual column is the only column of it, otherwise we estimate them from the samples.
+func (e *AnalyzeColumnsExec) buildVirtualColumnStats(collector statistics.RowSampleCollector, indexesWithVirtualColOffsets []int) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	base := collector.Base()
+	colLen := len(e.colsInfo)
+	for i, col := range e.colsInfo {
+		if !col.IsGenerated() || col.GeneratedStored {
+			continue
+		}
+		nullCount, totalSize := int64(0), int64(0)
+		fms := statistics.NewFMSketch(maxSketchSize)
+		for _, sample := range base.Samples {
+			d := sample.Columns[i]
+			if d.IsNull() {
+				nullCount++
+				continue
+			}
+			b, err := codec.EncodeValue(sc, nil, d)
+			if err != nil {
+				return err
+			}
+			// Minus one is to remove the flag byte.
+			totalSize += int64(len(b) - 1)
+			if err = fms.InsertValue(sc, d); err != nil {
+				return err
+			}
+		}
+		// The samples are only part of the rows, so scale the null count and total size to the row count.
+		if sampleCnt := int64(len(base.Samples)); sampleCnt > 0 && base.Count > sampleCnt {
+			nullCount = int64(float64(nullCount) * float64(base.Count) / float64(sampleCnt))
+			totalSize = int64(float64(totalSize) * float64(base.Count) / float64(sampleCnt))
+		}
+		base.NullCount[i] = nullCount
+		base.TotalSizes[i] = totalSize
+		base.FMSketches[i] = fms
+		for _, offset := range indexesWithVirtualColOffsets {
+			idx := e.indexes[offset]
+			if len(idx.Columns) == 1 && idx.Columns[0].Offset == i && idx.Columns[0].Length == types.UnspecifiedLength {
+				base.NullCount[i] = base.NullCount[colLen+offset]
+				base.FMSketches[i] = base.FMSketches[colLen+offset].Copy()
+				break
+			}
+		}
+	}
+	return nil
+}
+
 func readDataAndSendTask(handler *tableResultHandler, mergeTaskCh chan []byte) error {
 	defer close(mergeTaskCh)
 	for {
@@ -970,6 +1053,21 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 
 	colLen := len(e.colsInfo)
 
+	indexPushedDownResult := <-idxNDVPushDownCh
+	if indexPushedDownResult.err != nil {
+		return 0, nil, nil, nil, nil, indexPushedDownResult.err
+	}
+	for _, offset := range indexesWithVirtualColOffsets {
+		ret := indexPushedDownResult.results[e.indexes[offset].ID]
+		rootRowCollector.Base().NullCount[colLen+offset] = ret.Count
+		rootRowCollector.Base().FMSketches[colLen+offset] = ret.Ars[0].Fms[0]
+	}
+	if len(virtualColIdx) > 0 {
+		if err = e.buildVirtualColumnStats(rootRowCollector, indexesWithVirtualColOffsets); err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+	}
+
 	// The order of the samples are broken when merging samples from sub-collectors.
 	// So now we need to sort the samples according to the handle in order to calculate correlation.
 	sort.Slice(rootRowCollector.Base().Samples, func(i, j int) bool {
@@ -1000,16 +1098,6 @@ func (e *AnalyzeColumnsExec) buildSamplingStats(
 		fmSketches = append(fmSketches, rootRowCollector.Base().FMSketches[i])
 	}
 
-	indexPushedDownResult := <-idxNDVPushDownCh
-	if indexPushedDownResult.err != nil {
-		return 0, nil, nil, nil, nil, indexPushedDownResult.err
-	}
-	for _, offset := range indexesWithVirtualColOffsets {
-		ret := indexPushedDownResult.results[e.indexes[offset].ID]
-		rootRowCollector.Base().NullCount[colLen+offset] = ret.Count
-		rootRowCollector.Base().FMSketches[colLen+offset] = ret.Ars[0].Fms[0]
-	}
-
 	// build index stats
 	for i, idx := range e.indexes {
 		buildTaskChan <- &samplingBuildTask{
@@ -1305,11 +1393,6 @@ workLoop:
 		}
 		var collector *statistics.SampleCollector
 		if task.isColumn {
-			if e.colsInfo[task.slicePos].IsGenerated() && !e.colsInfo[task.slicePos].GeneratedStored {
-				hists[task.slicePos] = nil
-				topns[task.slicePos] = nil
-				continue
-			}
 			sampleItems := make([]*statistics.SampleItem, 0, task.rootRowCollector.Base().Samples.Len())
 			for j, row := range task.rootRowCollector.Base().Samples {
 				if row.Columns[task.slicePos].IsNull() {
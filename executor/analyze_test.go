@@ -887,6 +887,53 @@ func TestAnalyzeFullSamplingOnIndexWithVirtualColumnOrPrefixColumn(t *testing.T)
 	tk.MustQuery("show stats_topn where table_name = 'sampling_index_prefix_col' and column_name = 'idx'").Check(testkit.Rows("test sampling_index_prefix_col  idx 1 a 3"))
 }
 
+func TestAnalyzeVirtualColumnAndExpressionIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int as (a*2) virtual, index idx((a+1)))")
+	tk.MustExec("insert into t (a) values (1), (2), (2), (3), (3), (3), (null), (4), (4), (4), (4)")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("analyze table t with 2 topn, 2 buckets")
+	// The hidden column of the expression index and the virtual column have their own stats now.
+	rows := tk.MustQuery("show stats_histograms where db_name = 'test' and table_name = 't'").Sort().Rows()
+	require.Len(t, rows, 4)
+	for i, name := range []string{"_V$_idx_0", "a", "b", "idx"} {
+		// The column name, NDV and NULLs.
+		require.Equal(t, []interface{}{name, "4", "1"}, []interface{}{rows[i][3], rows[i][6], rows[i][7]})
+	}
+	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
+		"test t  _V$_idx_0 0 4 3",
+		"test t  _V$_idx_0 0 5 4",
+		"test t  a 0 3 3",
+		"test t  a 0 4 4",
+		"test t  b 0 6 3",
+		"test t  b 0 8 4",
+		"test t  idx 1 4 3",
+		"test t  idx 1 5 4"))
+	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
+		"test t  _V$_idx_0 0 0 3 2 2 3 0",
+		"test t  a 0 0 3 2 1 2 0",
+		"test t  b 0 0 3 2 2 4 0",
+		"test t  idx 1 0 3 2 2 3 0"))
+	tk.MustQuery("explain format = 'brief' select * from t where a+1 = 4").Check(testkit.Rows(
+		"Projection 3.00 root  test.t.a, test.t.b",
+		"└─Selection 3.00 root  eq(plus(test.t.a, 1), 4)",
+		"  └─TableReader 11.00 root  data:TableFullScan",
+		"    └─TableFullScan 11.00 cop[tikv] table:t keep order:false"))
+	tk.MustQuery("explain format = 'brief' select * from t where a*2 > 4").Check(testkit.Rows(
+		"TableReader 8.50 root  data:Selection",
+		"└─Selection 8.50 cop[tikv]  gt(mul(test.t.a, 2), 4)",
+		"  └─TableFullScan 11.00 cop[tikv] table:t keep order:false"))
+	tk.MustQuery("explain format = 'brief' select * from t use index() where a+1 in (2, 5)").Check(testkit.Rows(
+		"TableReader 5.50 root  data:Selection",
+		"└─Selection 5.50 cop[tikv]  in(plus(test.t.a, 1), 2, 5)",
+		"  └─TableFullScan 11.00 cop[tikv] table:t keep order:false"))
+}
+
 func TestSnapshotAnalyze(t *testing.T) {
 	store, clean := testkit.CreateMockStore(t)
 	defer clean()
@@ -2421,10 +2468,11 @@ func TestAnalyzeColumnsWithVirtualColumnIndex(t *testing.T) {
 				require.Equal(t, "b", rows[0][3])
 				tk.MustExec("analyze table t predicate columns with 2 topn, 2 buckets")
 			}
-			// virtual column c is skipped when dumping stats into disk, so only the stats of column b are updated
-			rows := tk.MustQuery("show column_stats_usage where db_name = 'test' and table_name = 't' and last_analyzed_at is not null").Rows()
-			require.Equal(t, 1, len(rows))
+			// virtual column c is analyzed together with column b since index idx needs its stats
+			rows := tk.MustQuery("show column_stats_usage where db_name = 'test' and table_name = 't' and last_analyzed_at is not null").Sort().Rows()
+			require.Equal(t, 2, len(rows))
 			require.Equal(t, "b", rows[0][3])
+			require.Equal(t, "c", rows[1][3])
 
 			tk.MustQuery(fmt.Sprintf("select modify_count, count from mysql.stats_meta where table_id = %d", tblID)).Sort().Check(
 				testkit.Rows("0 9"))
@@ -2432,17 +2480,21 @@ func TestAnalyzeColumnsWithVirtualColumnIndex(t *testing.T) {
 				// db, tbl, part, col, is_idx, value, count
 				testkit.Rows("test t  b 0 4 2",
 					"test t  b 0 5 3",
+					"test t  c 0 5 2",
+					"test t  c 0 6 3",
 					"test t  idx 1 5 2",
 					"test t  idx 1 6 3"))
 			tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count, stats_ver, truncate(correlation,2) from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
 				testkit.Rows("0 1 0 0 0 0", // column a is not analyzed
 					"0 2 5 1 2 1",
-					"0 3 0 0 0 0", // column c is not analyzed
+					"0 3 5 1 2 1",
 					"1 1 5 1 2 0"))
 			tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(
 				// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
 				testkit.Rows("test t  b 0 0 2 1 1 2 0",
 					"test t  b 0 1 3 1 3 3 0",
+					"test t  c 0 0 2 1 2 3 0",
+					"test t  c 0 1 3 1 4 4 0",
 					"test t  idx 1 0 2 1 2 3 0",
 					"test t  idx 1 1 3 1 4 4 0"))
 		}(val)
@@ -39,6 +39,8 @@ type keyValueWithDupInfo struct {
 	newKey       kv.Key
 	dupErr       error
 	commonHandle bool
+	// global indicates the key is of a global index, the duplicate row may be in any partition.
+	global bool
 }
 
 type toBeCheckedRow struct {
@@ -46,7 +48,9 @@ type toBeCheckedRow struct {
 	handleKey  *keyValueWithDupInfo
 	uniqueKeys []*keyValueWithDupInfo
 	// t is the table or partition this row belongs to.
-	t       table.Table
+	t table.Table
+	// pt is the partitioned table if t is a partition.
+	pt      table.PartitionedTable
 	ignored bool
 }
 
@@ -96,7 +100,8 @@ func getKeysNeedCheck(ctx context.Context, sctx sessionctx.Context, t table.Tabl
 func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.Datum, nUnique int, handleCols []*table.Column,
 	pkIdxInfo *model.IndexInfo, result []toBeCheckedRow) ([]toBeCheckedRow, error) {
 	var err error
-	if p, ok := t.(table.PartitionedTable); ok {
+	p, isPartitioned := t.(table.PartitionedTable)
+	if isPartitioned {
 		t, err = p.GetPartitionByRow(ctx, row)
 		if err != nil {
 			if terr, ok := errors.Cause(err).(*terror.Error); ctx.GetSessionVars().StmtCtx.IgnoreNoPartition && ok && (terr.Code() == errno.ErrNoPartitionForGivenValue || terr.Code() == errno.ErrRowDoesNotMatchGivenPartitionSet) {
@@ -187,6 +192,7 @@ func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.D
 			newKey:       key,
 			dupErr:       kv.ErrKeyExists.FastGenByArgs(colValStr, v.Meta().Name),
 			commonHandle: t.Meta().IsCommonHandle,
+			global:       v.Meta().Global,
 		})
 	}
 	if addChangingColTimes == 1 {
@@ -197,10 +203,27 @@ func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.D
 		handleKey:  handleKey,
 		uniqueKeys: uniqueKeys,
 		t:          t,
+		pt:         p,
 	})
 	return result, nil
 }
 
+// getDupRowTable gets the table or partition the duplicate row of the unique key is in by the index value.
+// It returns nil if the value is a stale entry of a global index, which means there is no duplicate row.
+func (r *toBeCheckedRow) getDupRowTable(uk *keyValueWithDupInfo, val []byte) (table.Table, error) {
+	if !uk.global || r.pt == nil {
+		return r.t, nil
+	}
+	pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(val)
+	if err != nil || !ok {
+		return r.t, err
+	}
+	if p := r.pt.GetPartition(pid); p != nil {
+		return p, nil
+	}
+	return nil, nil
+}
+
 func buildHandleFromDatumRow(sctx *stmtctx.StatementContext, row []types.Datum, tblHandleCols []*table.Column, pkIdxInfo *model.IndexInfo) (kv.Handle, error) {
 	pkDts := make([]types.Datum, 0, len(tblHandleCols))
 	for i, col := range tblHandleCols {
@@ -37,6 +37,7 @@ type SQLBindExec struct {
 	db           string
 	isGlobal     bool
 	bindAst      ast.StmtNode
+	source       string
 }
 
 // Next implements the Executor Next interface.
@@ -85,12 +86,16 @@ func (e *SQLBindExec) createSQLBind() error {
 		e.ctx.GetSessionVars().StmtCtx = saveStmtCtx
 	}()
 
+	source := e.source
+	if source == "" {
+		source = bindinfo.Manual
+	}
 	bindInfo := bindinfo.Binding{
 		BindSQL:   e.bindSQL,
 		Charset:   e.charset,
 		Collation: e.collation,
 		Status:    bindinfo.Using,
-		Source:    bindinfo.Manual,
+		Source:    source,
 	}
 	record := &bindinfo.BindRecord{
 		OriginalSQL: e.normdOrigSQL,
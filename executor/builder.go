@@ -186,6 +186,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildLoadData(v)
 	case *plannercore.LoadStats:
 		return b.buildLoadStats(v)
+	case *plannercore.RestoreStats:
+		return b.buildRestoreStats(v)
 	case *plannercore.IndexAdvise:
 		return b.buildIndexAdvise(v)
 	case *plannercore.PlanReplayer:
@@ -198,6 +200,10 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildSelectLock(v)
 	case *plannercore.CancelDDLJobs:
 		return b.buildCancelDDLJobs(v)
+	case *plannercore.PauseDDLJobs:
+		return b.buildPauseDDLJobs(v)
+	case *plannercore.ResumeDDLJobs:
+		return b.buildResumeDDLJobs(v)
 	case *plannercore.ShowNextRowID:
 		return b.buildShowNextRowID(v)
 	case *plannercore.ShowDDL:
@@ -296,14 +302,15 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 	}
 }
 
-func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Executor {
-	e := &CancelDDLJobsExec{
-		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
-		jobIDs:       v.JobIDs,
+func (b *executorBuilder) buildCommandDDLJobs(schema *expression.Schema, id int, jobIDs []int64,
+	command func(txn kv.Transaction, ids []int64) ([]error, error)) *CommandDDLJobsExec {
+	e := &CommandDDLJobsExec{
+		baseExecutor: newBaseExecutor(b.ctx, schema, id),
+		jobIDs:       jobIDs,
 	}
 	// Run within a new transaction. If it runs within the session transaction, commit failure won't be reported to the user.
 	errInTxn := kv.RunInNewTxn(context.Background(), e.ctx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) (err error) {
-		e.errs, err = admin.CancelJobs(txn, e.jobIDs)
+		e.errs, err = command(txn, e.jobIDs)
 		return
 	})
 	if errInTxn != nil {
@@ -312,6 +319,18 @@ func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Execu
 	return e
 }
 
+func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Executor {
+	return &CancelDDLJobsExec{b.buildCommandDDLJobs(v.Schema(), v.ID(), v.JobIDs, admin.CancelJobs)}
+}
+
+func (b *executorBuilder) buildPauseDDLJobs(v *plannercore.PauseDDLJobs) Executor {
+	return &PauseDDLJobsExec{b.buildCommandDDLJobs(v.Schema(), v.ID(), v.JobIDs, admin.PauseJobs)}
+}
+
+func (b *executorBuilder) buildResumeDDLJobs(v *plannercore.ResumeDDLJobs) Executor {
+	return &ResumeDDLJobsExec{b.buildCommandDDLJobs(v.Schema(), v.ID(), v.JobIDs, admin.ResumeJobs)}
+}
+
 func (b *executorBuilder) buildChange(v *plannercore.Change) Executor {
 	return &ChangeExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -845,6 +864,7 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		fkTriggers:                b.buildFKTriggerExecs(v.FKChecks, v.FKCascades),
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -915,6 +935,14 @@ func (b *executorBuilder) buildLoadStats(v *plannercore.LoadStats) Executor {
 	return e
 }
 
+func (b *executorBuilder) buildRestoreStats(v *plannercore.RestoreStats) Executor {
+	return &RestoreStatsExec{
+		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
+		table:        v.Table,
+		version:      v.Version,
+	}
+}
+
 func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) Executor {
 	e := &IndexAdviseExec{
 		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
@@ -1650,6 +1678,8 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableTiDBHotRegions),
 			strings.ToLower(infoschema.TableSessionVar),
 			strings.ToLower(infoschema.TableConstraints),
+			strings.ToLower(infoschema.TableCheckConstraints),
+			strings.ToLower(infoschema.TableInstancePlanCache),
 			strings.ToLower(infoschema.TableTiFlashReplica),
 			strings.ToLower(infoschema.TableTiDBServersInfo),
 			strings.ToLower(infoschema.TableTiKVStoreStatus),
@@ -2035,6 +2065,7 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		fkTriggers:                b.buildFKTriggerExecsByTable(v.FKChecks, v.FKCascades),
 	}
 	return updateExec
 }
@@ -2078,6 +2109,7 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 		tblID2Table:    tblID2table,
 		IsMultiTable:   v.IsMultiTable,
 		tblColPosInfos: v.TblColPosInfos,
+		fkTriggers:     b.buildFKTriggerExecsByTable(v.FKChecks, v.FKCascades),
 	}
 	return deleteExec
 }
@@ -3408,6 +3440,33 @@ func (b *executorBuilder) buildIndexReader(v *plannercore.PhysicalIndexReader) E
 	return ret
 }
 
+// getGlobalIndexPartitions gets the IDs of the partitions whose rows are read by a global index.
+// The entries of the other partitions, including the stale ones of the dropped or truncated partitions,
+// should be skipped.
+func (b *executorBuilder) getGlobalIndexPartitions(tblInfo *model.TableInfo, partInfo *plannercore.PartitionInfo) (map[int64]struct{}, error) {
+	tmp, ok := b.is.TableByID(tblInfo.ID)
+	if !ok {
+		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(b.ctx.GetSessionVars().CurrentDB, tblInfo.Name.O)
+	}
+	tbl := tmp.(table.PartitionedTable)
+	pids := make(map[int64]struct{}, len(tblInfo.Partition.Definitions))
+	// The readers built for ADMIN CHECK TABLE have no pruning information, all the partitions are read.
+	if !b.ctx.GetSessionVars().UseDynamicPartitionPrune() || len(partInfo.Columns) == 0 {
+		for _, def := range tbl.Meta().Partition.Definitions {
+			pids[def.ID] = struct{}{}
+		}
+		return pids, nil
+	}
+	partitions, err := partitionPruning(b.ctx, tbl, partInfo.PruningConds, partInfo.PartitionNames, partInfo.Columns, partInfo.ColumnNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range partitions {
+		pids[p.GetPhysicalID()] = struct{}{}
+	}
+	return pids, nil
+}
+
 func buildTableReq(b *executorBuilder, schemaLen int, plans []plannercore.PhysicalPlan) (dagReq *tipb.DAGRequest, streaming bool, val table.Table, err error) {
 	tableReq, tableStreaming, err := constructDAGReq(b.ctx, plans, kv.TiKV)
 	if err != nil {
@@ -3497,6 +3556,12 @@ func buildNoRangeIndexLookUpReader(b *executorBuilder, v *plannercore.PhysicalIn
 	if ok, _ := ts.IsPartition(); ok {
 		e.extraPIDColumnIndex = extraPIDColumnIndex(v.Schema())
 	}
+	if is.Index.Global {
+		e.globalIndexPartitions, err = b.getGlobalIndexPartitions(is.Table, &v.PartitionInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if containsLimit(indexReq.Executors) {
 		e.feedback = statistics.NewQueryFeedback(0, nil, 0, is.Desc)
@@ -3598,7 +3663,12 @@ func buildNoRangeIndexMergeReader(b *executorBuilder, v *plannercore.PhysicalInd
 		feedbacks = append(feedbacks, feedback)
 
 		if is, ok := v.PartialPlans[i][0].(*plannercore.PhysicalIndexScan); ok {
-			tempReq, tempStreaming, err = buildIndexReq(b.ctx, len(is.Index.Columns), ts.HandleCols.NumCols(), v.PartialPlans[i])
+			handleLen := ts.HandleCols.NumCols()
+			if is.Index.Global {
+				// Should output pid col.
+				handleLen++
+			}
+			tempReq, tempStreaming, err = buildIndexReq(b.ctx, len(is.Index.Columns), handleLen, v.PartialPlans[i])
 			descs = append(descs, is.Desc)
 			indexes = append(indexes, is.Index)
 		} else {
@@ -4418,6 +4488,7 @@ func (b *executorBuilder) buildSQLBindExec(v *plannercore.SQLBindPlan) Executor
 		db:           v.Db,
 		isGlobal:     v.IsGlobal,
 		bindAst:      v.BindStmt,
+		source:       v.Source,
 	}
 	return e
 }
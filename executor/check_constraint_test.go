@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConstraintOnWrite(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int check (a > 0), b varchar(10), constraint ck_ab check (a < length(b) or b is null))")
+
+	tk.MustExec("insert into t values (1, 1, 'abc'), (2, null, null)")
+	tk.MustGetErrCode("insert into t values (3, 0, null)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t values (3, 5, 'abc')", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t set a = -1 where id = 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t set id = 10, a = -1 where id = 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("replace into t values (1, 0, null)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t values (1, 1, 'abc') on duplicate key update a = 0", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1 abc", "2 <nil> <nil>"))
+
+	// The IGNORE modifier turns the violations into warnings.
+	tk.MustExec("insert ignore into t values (3, 0, null), (4, 2, 'abc')")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_1' is violated."))
+	tk.MustExec("update ignore t set a = a + 2 where id in (1, 4)")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 3819 Check constraint 'ck_ab' is violated.",
+		"Warning 3819 Check constraint 'ck_ab' is violated."))
+	tk.MustExec("insert ignore into t values (1, 1, 'abc') on duplicate key update a = -1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_1' is violated."))
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1 abc", "2 <nil> <nil>", "4 2 abc"))
+
+	// The rows written in a transaction are checked one by one.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 1, null)")
+	tk.MustGetErrCode("update t set a = 0 where id = 5", errno.ErrCheckConstraintViolated)
+	tk.MustExec("commit")
+	tk.MustQuery("select a from t where id = 5").Check(testkit.Rows("1"))
+}
+
+func TestCheckConstraintOnPartitionedTable(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int, a int, check (a between 0 and id)) partition by hash(id) partitions 4")
+	tk.MustExec("insert into t values (1, 1), (2, 0), (3, 2)")
+	tk.MustGetErrCode("insert into t values (4, 5)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t set id = 0 where id = 3", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table t drop check t_chk_1")
+	tk.MustExec("insert into t values (4, 5)")
+	tk.MustGetErrCode("alter table t add constraint c check (a <= id)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = 'test'").Check(testkit.Rows("0"))
+}
+
+func TestCheckConstraintOnAddColumn(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int)")
+	tk.MustExec("insert into t values (1)")
+	// The existing rows are verified with the default value of the new column.
+	tk.MustGetErrCode("alter table t add column a int default 0 check (a > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = 'test'").Check(testkit.Rows("0"))
+	tk.MustGetErrCode("alter table t add column (a int check (a > 0), b int)", errno.ErrUnsupportedConstraintCheck)
+
+	tk.MustExec("alter table t add column a int default 1 constraint ck_a check (a > 0)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = 'test'").
+		Check(testkit.Rows("ck_a (`a` > 0)"))
+	tk.MustGetErrCode("insert into t values (2, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into t values (2, 2)")
+	tk.MustGetErrCode("alter table t add column b int default 1 constraint ck_a check (b > 0)", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("alter table t add column b int default 2 check (b > 5)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = 'test'").Check(testkit.Rows("1"))
+}
+
+func TestCheckConstraintOnLoadData(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, a int check (a > 0))")
+	tk.MustExec("load data local infile '/tmp/nonexistence.csv' into table t")
+	ctx := tk.Session().(sessionctx.Context)
+	ld, ok := ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	require.True(t, ok)
+	defer ctx.SetValue(executor.LoadDataVarKey, nil)
+	require.NotNil(t, ld)
+
+	// The rows violating the check constraints are skipped with warnings.
+	tests := []testCase{
+		{nil, []byte("1\t1\n2\t0\n3\t\\N\n"), []string{"1|1", "3|<nil>"}, nil, "Records: 3  Deleted: 0  Skipped: 1  Warnings: 1"},
+	}
+	checkCases(tests, ld, t, tk, ctx, "select * from t order by id", "delete from t")
+}
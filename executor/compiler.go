@@ -375,6 +375,10 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "LoadData"
 	case *ast.RollbackStmt:
 		return "RollBack"
+	case *ast.SavepointStmt:
+		return "Savepoint"
+	case *ast.ReleaseSavepointStmt:
+		return "ReleaseSavepoint"
 	case *ast.SelectStmt:
 		return "Select"
 	case *ast.SetStmt, *ast.SetPwdStmt:
@@ -98,6 +98,14 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		if s.TemporaryKeyword == ast.TemporaryLocal {
 			return e.createSessionTemporaryTable(s)
 		}
+	case *ast.CreateIndexStmt:
+		if s.IndexOption != nil && s.IndexOption.Tp == model.IndexTypeHypo {
+			return e.createHypoIndex(s)
+		}
+	case *ast.DropIndexStmt:
+		if s.IsHypo {
+			return e.dropHypoIndex(s)
+		}
 	case *ast.DropTableStmt:
 		if s.IsView {
 			break
@@ -375,6 +383,77 @@ func (e *DDLExec) createSessionTemporaryTable(s *ast.CreateTableStmt) error {
 	return e.tempTableDDL.CreateLocalTemporaryTable(dbInfo, tbInfo)
 }
 
+// createHypoIndex creates a hypothetical index in the session. The hypothetical index only lives in the session
+// and is never written to the storage, so no DDL job is needed.
+func (e *DDLExec) createHypoIndex(s *ast.CreateIndexStmt) error {
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(s.Table.Schema, s.Table.Name)
+	if err != nil {
+		return err
+	}
+	tblInfo := tbl.Meta()
+	if tblInfo.TempTableType == model.TempTableLocal {
+		return ddl.ErrUnsupportedLocalTempTableDDL.GenWithStackByArgs("CREATE INDEX")
+	}
+	if tbl.Type().IsVirtualTable() || tblInfo.IsView() || tblInfo.IsSequence() {
+		return infoschema.ErrWrongObject.GenWithStackByArgs(s.Table.Schema.O, s.Table.Name.O, "BASE TABLE")
+	}
+	indexName := model.NewCIStr(s.IndexName)
+	sessVars := e.ctx.GetSessionVars()
+	for _, idx := range sessVars.HypoIndexes[tblInfo.ID] {
+		if idx.Name.L == indexName.L {
+			err = ddl.ErrDupKeyName.GenWithStack("index already exist %s", indexName)
+			if s.IfNotExists {
+				sessVars.StmtCtx.AppendNote(err)
+				return nil
+			}
+			return err
+		}
+	}
+	idxInfo, err := ddl.BuildHypoIndexInfo(tblInfo, indexName, s.KeyType, s.IndexPartSpecifications)
+	if err != nil {
+		if ddl.ErrDupKeyName.Equal(err) && s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	if s.IndexOption != nil {
+		idxInfo.Comment = s.IndexOption.Comment
+	}
+	if sessVars.HypoIndexes == nil {
+		sessVars.HypoIndexes = make(map[int64][]*model.IndexInfo)
+	}
+	sessVars.HypoIndexes[tblInfo.ID] = append(sessVars.HypoIndexes[tblInfo.ID], idxInfo)
+	return nil
+}
+
+func (e *DDLExec) dropHypoIndex(s *ast.DropIndexStmt) error {
+	indexName := model.NewCIStr(s.IndexName)
+	sessVars := e.ctx.GetSessionVars()
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := is.TableByName(s.Table.Schema, s.Table.Name)
+	if err == nil {
+		tblID := tbl.Meta().ID
+		hypoIndexes := sessVars.HypoIndexes[tblID]
+		for i, idx := range hypoIndexes {
+			if idx.Name.L == indexName.L {
+				sessVars.HypoIndexes[tblID] = append(hypoIndexes[:i:i], hypoIndexes[i+1:]...)
+				if len(sessVars.HypoIndexes[tblID]) == 0 {
+					delete(sessVars.HypoIndexes, tblID)
+				}
+				return nil
+			}
+		}
+		err = ddl.ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", indexName)
+	}
+	if s.IfExists {
+		sessVars.StmtCtx.AppendNote(err)
+		return nil
+	}
+	return err
+}
+
 func (e *DDLExec) executeCreateView(s *ast.CreateViewStmt) error {
 	ret := &core.PreprocessorReturn{}
 	err := core.Preprocess(e.ctx, s.Select, core.WithPreprocessorReturn(ret))
@@ -339,6 +339,8 @@ type IndexLookUpExecutor struct {
 	prunedPartitions   []table.PhysicalTable // partition tables need to access
 	partitionRangeMap  map[int64][]*ranger.Range
 	partitionKVRanges  [][]kv.KeyRange // kvRanges of each partition table
+	// globalIndexPartitions are the partitions read by the global index, the entries of other partitions are skipped.
+	globalIndexPartitions map[int64]struct{}
 
 	// All fields above are immutable.
 
@@ -926,6 +928,11 @@ func (w *indexWorker) extractTaskHandles(ctx context.Context, chk *chunk.Chunk,
 			if err != nil {
 				return handles, retChk, scannedKeys, err
 			}
+			if w.idxLookup.globalIndexPartitions != nil && w.checkIndexValue == nil {
+				if _, ok := w.idxLookup.globalIndexPartitions[h.(kv.PartitionHandle).PartitionID]; !ok {
+					continue
+				}
+			}
 			handles = append(handles, h)
 		}
 		if w.checkIndexValue != nil {
@@ -295,8 +295,9 @@ func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
 	return err
 }
 
-// CancelDDLJobsExec represents a cancel DDL jobs executor.
-type CancelDDLJobsExec struct {
+// CommandDDLJobsExec is the general executor for the admin commands on DDL jobs,
+// such as cancel, pause and resume. It returns the result of the command on each job.
+type CommandDDLJobsExec struct {
 	baseExecutor
 
 	cursor int
@@ -304,8 +305,23 @@ type CancelDDLJobsExec struct {
 	errs   []error
 }
 
+// CancelDDLJobsExec represents a cancel DDL jobs executor.
+type CancelDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
+// PauseDDLJobsExec represents a pause DDL jobs executor.
+type PauseDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
+// ResumeDDLJobsExec represents a resume DDL jobs executor.
+type ResumeDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
 // Next implements the Executor Next interface.
-func (e *CancelDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
+func (e *CommandDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(e.maxChunkSize)
 	if e.cursor >= len(e.jobIDs) {
 		return nil
@@ -364,6 +364,11 @@ func (s *testSuite) TestExplainStatementsSummary(c *C) {
 		`MemTableScan_5 10000.00 root table:STATEMENTS_SUMMARY digests: ["abcdefg"]`))
 	tk.MustQuery("desc select * from information_schema.statements_summary where digest in ('a','b','c')").Check(testutil.RowsWithSep(" ",
 		`MemTableScan_5 10000.00 root table:STATEMENTS_SUMMARY digests: ["a","b","c"]`))
+	tk.MustQuery("desc select * from information_schema.statements_summary_history where digest = 'a' and schema_name = 'test'").Check(testutil.RowsWithSep(" ",
+		`MemTableScan_5 10000.00 root table:STATEMENTS_SUMMARY_HISTORY digests: ["a"], schema_names: ["test"]`))
+	tk.MustQuery("desc select * from information_schema.statements_summary_history where summary_begin_time >= '2022-01-01 00:00:00'").Check(testutil.RowsWithSep("|",
+		`Selection_5|8000.00|root| ge(Column#1, 2022-01-01 00:00:00.000000)`,
+		`└─MemTableScan_6|10000.00|root|table:STATEMENTS_SUMMARY_HISTORY|summary_begin_time_start: 2022-01-01 00:00:00.000000`))
 }
 
 func (s *testSuite) TestFix29401(c *C) {
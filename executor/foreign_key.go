@@ -0,0 +1,530 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/execdetails"
+)
+
+// maxForeignKeyCascadeDepth is the max depth of the nested cascade actions, the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// fkTriggerExecs contains the foreign key checkers and cascades of a modified table.
+// They're triggered row by row in the same transaction as the modification.
+type fkTriggerExecs struct {
+	checks   []*FKCheckExec
+	cascades []*FKCascadeExec
+}
+
+// buildFKTriggerExecs builds the foreign key checkers and cascades of the top level statement.
+// It returns nil if `foreign_key_checks` is disabled or there's nothing to check.
+func (b *executorBuilder) buildFKTriggerExecs(checks []*plannercore.FKCheck, cascades []*plannercore.FKCascade) *fkTriggerExecs {
+	if !b.ctx.GetSessionVars().ForeignKeyChecks || (len(checks) == 0 && len(cascades) == 0) {
+		return nil
+	}
+	return newFKTriggerExecs(b.ctx, b.is, checks, cascades, 1, true)
+}
+
+// buildFKTriggerExecsByTable builds the foreign key checkers and cascades for each modified table.
+func (b *executorBuilder) buildFKTriggerExecsByTable(checks map[int64][]*plannercore.FKCheck, cascades map[int64][]*plannercore.FKCascade) map[int64]*fkTriggerExecs {
+	var triggers map[int64]*fkTriggerExecs
+	build := func(tblID int64) {
+		if _, ok := triggers[tblID]; ok {
+			return
+		}
+		if t := b.buildFKTriggerExecs(checks[tblID], cascades[tblID]); t != nil {
+			if triggers == nil {
+				triggers = make(map[int64]*fkTriggerExecs)
+			}
+			triggers[tblID] = t
+		}
+	}
+	for tblID := range checks {
+		build(tblID)
+	}
+	for tblID := range cascades {
+		build(tblID)
+	}
+	return triggers
+}
+
+func newFKTriggerExecs(sctx sessionctx.Context, is infoschema.InfoSchema, checks []*plannercore.FKCheck,
+	cascades []*plannercore.FKCascade, depth int, withStats bool) *fkTriggerExecs {
+	t := &fkTriggerExecs{
+		checks:   make([]*FKCheckExec, 0, len(checks)),
+		cascades: make([]*FKCascadeExec, 0, len(cascades)),
+	}
+	var statsColl *execdetails.RuntimeStatsColl
+	if withStats {
+		statsColl = sctx.GetSessionVars().StmtCtx.RuntimeStatsColl
+	}
+	for _, check := range checks {
+		e := &FKCheckExec{FKCheck: check, ctx: sctx}
+		if statsColl != nil {
+			e.stats = &execdetails.BasicRuntimeStats{}
+			statsColl.RegisterStats(check.ID(), e.stats)
+		}
+		t.checks = append(t.checks, e)
+	}
+	for _, cascade := range cascades {
+		e := &FKCascadeExec{FKCascade: cascade, ctx: sctx, is: is, depth: depth}
+		if statsColl != nil {
+			e.stats = &execdetails.BasicRuntimeStats{}
+			statsColl.RegisterStats(cascade.ID(), e.stats)
+		}
+		t.cascades = append(t.cascades, e)
+	}
+	return t
+}
+
+// onAddRow checks that the parent rows of the added row exist.
+func (t *fkTriggerExecs) onAddRow(ctx context.Context, row []types.Datum) error {
+	for _, check := range t.checks {
+		if !check.CheckExist {
+			continue
+		}
+		if err := check.check(ctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onRemoveRow checks or cascades the child rows of the removed row.
+func (t *fkTriggerExecs) onRemoveRow(ctx context.Context, row []types.Datum) error {
+	for _, check := range t.checks {
+		if check.CheckExist {
+			continue
+		}
+		if err := check.check(ctx, row); err != nil {
+			return err
+		}
+	}
+	for _, cascade := range t.cascades {
+		if err := cascade.onParentRowModified(ctx, row, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onUpdateRow checks the parent rows of the updated row, and checks or cascades the child rows of the updated row.
+// Only the foreign keys whose columns are changed are triggered.
+func (t *fkTriggerExecs) onUpdateRow(ctx context.Context, oldRow, newRow []types.Datum) error {
+	for _, check := range t.checks {
+		changed, err := fkColsChanged(check.ColOffsets, oldRow, newRow)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		row := oldRow
+		if check.CheckExist {
+			row = newRow
+		}
+		if err := check.check(ctx, row); err != nil {
+			return err
+		}
+	}
+	for _, cascade := range t.cascades {
+		changed, err := fkColsChanged(cascade.ColOffsets, oldRow, newRow)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err := cascade.onParentRowModified(ctx, oldRow, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FKCheckExec checks the foreign key constraint on a modified row.
+type FKCheckExec struct {
+	*plannercore.FKCheck
+
+	ctx   sessionctx.Context
+	stats *execdetails.BasicRuntimeStats
+}
+
+func (e *FKCheckExec) check(ctx context.Context, row []types.Datum) error {
+	vals, ok := fkValues(row, e.ColOffsets)
+	if !ok {
+		// The constraint is always satisfied if any column of the foreign key is NULL.
+		return nil
+	}
+	if e.stats != nil {
+		start := time.Now()
+		defer func() { e.stats.Record(time.Since(start), 1) }()
+	}
+	if e.Tbl == nil {
+		if e.CheckExist {
+			return e.FailedErr
+		}
+		return nil
+	}
+	rows, err := lookupFKRows(ctx, e.ctx, &e.FKLookup, vals, 1)
+	if err != nil {
+		return err
+	}
+	if !e.CheckExist {
+		if len(rows) > 0 {
+			return e.FailedErr
+		}
+		return nil
+	}
+	if len(rows) == 0 {
+		return e.FailedErr
+	}
+	// Lock the parent row to prevent it from being deleted or updated by other transactions.
+	if txnCtx := e.ctx.GetSessionVars().TxnCtx; txnCtx.IsPessimistic {
+		txnCtx.AddUnchangedRowKey(rows[0].key)
+		return nil
+	}
+	// The optimistic transaction only marks the key as locked in the membuffer, the key is prewritten
+	// as a lock mutation, so the commit fails if the parent row is modified after the start ts.
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	return txn.LockKeys(ctx, &kv.LockCtx{}, rows[0].key)
+}
+
+// FKCascadeExec executes the CASCADE and SET NULL actions on the child rows of a modified parent row.
+type FKCascadeExec struct {
+	*plannercore.FKCascade
+
+	ctx sessionctx.Context
+	is  infoschema.InfoSchema
+	// depth is the depth of the nested cascade actions, it's 1 for the top level statement.
+	depth int
+	stats *execdetails.BasicRuntimeStats
+	// childTriggers are the foreign key checkers and cascades of the modified child rows, they're built lazily.
+	childTriggers *fkTriggerExecs
+}
+
+// onParentRowModified modifies the child rows of oldRow. newRow is nil if the parent row is deleted.
+func (e *FKCascadeExec) onParentRowModified(ctx context.Context, oldRow, newRow []types.Datum) error {
+	vals, ok := fkValues(oldRow, e.ColOffsets)
+	if !ok || e.Tbl == nil {
+		return nil
+	}
+	if e.depth > maxForeignKeyCascadeDepth {
+		return ErrFkDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+	}
+	if e.stats != nil {
+		start := time.Now()
+		defer func() { e.stats.Record(time.Since(start), 1) }()
+	}
+	rows, err := lookupFKRows(ctx, e.ctx, &e.FKLookup, vals, 0)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	triggers := e.getChildTriggers()
+	for _, r := range rows {
+		// The row may have been modified by the cascade actions of the previous rows.
+		if _, err := txn.Get(ctx, r.key); err != nil {
+			if kv.IsErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if e.Tp == plannercore.FKCascadeOnDelete && e.Action == ast.ReferOptionCascade {
+			if err := e.Tbl.RemoveRecord(e.ctx, r.handle, r.row); err != nil {
+				return err
+			}
+			if err := triggers.onRemoveRow(ctx, r.row); err != nil {
+				return err
+			}
+			continue
+		}
+		newChildRow, err := e.buildNewChildRow(r.row, newRow)
+		if err != nil {
+			return err
+		}
+		if err := e.updateChildRow(ctx, r, newChildRow); err != nil {
+			return err
+		}
+		if err := triggers.onUpdateRow(ctx, r.row, newChildRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *FKCascadeExec) getChildTriggers() *fkTriggerExecs {
+	if e.childTriggers != nil {
+		return e.childTriggers
+	}
+	var (
+		checks   []*plannercore.FKCheck
+		cascades []*plannercore.FKCascade
+	)
+	if e.Tp == plannercore.FKCascadeOnDelete && e.Action == ast.ReferOptionCascade {
+		checks, cascades = plannercore.BuildOnDeleteFKTriggers(e.ctx, e.is, e.Tbl)
+	} else {
+		updatedCols := make(map[string]struct{}, len(e.FK.Cols))
+		for _, col := range e.FK.Cols {
+			updatedCols[col.L] = struct{}{}
+		}
+		checks, cascades = plannercore.BuildOnUpdateFKTriggers(e.ctx, e.is, e.Tbl, updatedCols)
+	}
+	e.childTriggers = newFKTriggerExecs(e.ctx, e.is, checks, cascades, e.depth+1, false)
+	return e.childTriggers
+}
+
+// buildNewChildRow sets the foreign key columns of the child row to NULL for SET NULL,
+// or to the new values of the parent row for CASCADE.
+func (e *FKCascadeExec) buildNewChildRow(oldChildRow, newParentRow []types.Datum) ([]types.Datum, error) {
+	newChildRow := make([]types.Datum, len(oldChildRow))
+	copy(newChildRow, oldChildRow)
+	for i, col := range e.Cols {
+		if e.Action == ast.ReferOptionSetNull || newParentRow == nil {
+			if mysql.HasNotNullFlag(col.Flag) {
+				return nil, table.ErrColumnCantNull.GenWithStackByArgs(col.Name.O)
+			}
+			newChildRow[col.Offset].SetNull()
+			continue
+		}
+		v, err := table.CastValue(e.ctx, newParentRow[e.ColOffsets[i]], col, false, false)
+		if err != nil {
+			return nil, err
+		}
+		newChildRow[col.Offset] = v
+	}
+	return newChildRow, nil
+}
+
+func (e *FKCascadeExec) updateChildRow(ctx context.Context, r fkRow, newRow []types.Datum) error {
+	tblInfo := e.Tbl.Meta()
+	touched := make([]bool, len(newRow))
+	handleChanged := false
+	for _, col := range e.Cols {
+		touched[col.Offset] = true
+		if (tblInfo.PKIsHandle || tblInfo.IsCommonHandle) && mysql.HasPriKeyFlag(col.Flag) {
+			handleChanged = true
+		}
+	}
+	if !handleChanged {
+		return e.Tbl.UpdateRecord(ctx, e.ctx, r.handle, r.row, newRow, touched)
+	}
+	if err := e.Tbl.RemoveRecord(e.ctx, r.handle, r.row); err != nil {
+		return err
+	}
+	_, err := e.Tbl.AddRecord(e.ctx, newRow, table.IsUpdate, table.WithCtx(ctx))
+	return err
+}
+
+// fkRow is a row found by the foreign key lookup.
+type fkRow struct {
+	key    kv.Key
+	handle kv.Handle
+	row    []types.Datum
+}
+
+// fkValues returns the values of the foreign key columns, it returns false if any of them is NULL.
+func fkValues(row []types.Datum, offsets []int) ([]types.Datum, bool) {
+	vals := make([]types.Datum, 0, len(offsets))
+	for _, offset := range offsets {
+		if row[offset].IsNull() {
+			return nil, false
+		}
+		vals = append(vals, row[offset])
+	}
+	return vals, true
+}
+
+func fkColsChanged(offsets []int, oldRow, newRow []types.Datum) (bool, error) {
+	for _, offset := range offsets {
+		cmp, err := oldRow[offset].Compare(nil, &newRow[offset], collate.GetBinaryCollator())
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lookupFKRows finds at most limit rows whose looked up columns equal to vals in the current transaction.
+// All the matched rows are returned if limit is 0.
+func lookupFKRows(ctx context.Context, sctx sessionctx.Context, l *plannercore.FKLookup, vals []types.Datum, limit int) ([]fkRow, error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	tblInfo := l.Tbl.Meta()
+	convertedVals := make([]types.Datum, 0, len(vals))
+	for i, val := range vals {
+		v, err := val.ConvertTo(sc, &l.Cols[i].FieldType)
+		if err != nil {
+			// The value can't be stored in the looked up column, so no row matches it.
+			return nil, nil
+		}
+		convertedVals = append(convertedVals, v)
+	}
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	physicalIDs := []int64{tblInfo.ID}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		physicalIDs = physicalIDs[:0]
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+		}
+	}
+	cols := l.Tbl.WritableCols()
+	var rows []fkRow
+	// The matched rows are collected first and modified by the caller later, so the iterators are not affected.
+	addCandidate := func(key kv.Key, h kv.Handle, value []byte) (bool, error) {
+		row, _, err := tables.DecodeRawRowData(sctx, tblInfo, h, cols, value)
+		if err != nil {
+			return false, err
+		}
+		for i, col := range l.Cols {
+			if row[col.Offset].IsNull() {
+				return true, nil
+			}
+			cmp, err := row[col.Offset].Compare(sc, &convertedVals[i], collate.GetCollator(col.Collate))
+			if err != nil {
+				return false, err
+			}
+			if cmp != 0 {
+				return true, nil
+			}
+		}
+		rows = append(rows, fkRow{key: key, handle: h, row: row})
+		return limit <= 0 || len(rows) < limit, nil
+	}
+	for _, pid := range physicalIDs {
+		var err error
+		switch {
+		case l.IsHandle && tblInfo.PKIsHandle:
+			h := kv.IntHandle(convertedVals[0].GetInt64())
+			key := tablecodec.EncodeRowKeyWithHandle(pid, h)
+			var value []byte
+			value, err = txn.Get(ctx, key)
+			if kv.IsErrNotFound(err) {
+				continue
+			}
+			if err == nil {
+				_, err = addCandidate(key, h, value)
+			}
+		case l.IsHandle:
+			var encoded []byte
+			encoded, err = codec.EncodeKey(sc, nil, convertedVals...)
+			if err != nil {
+				return nil, err
+			}
+			err = iterFKKeys(txn, tablecodec.EncodeRowKey(pid, encoded), func(key kv.Key, value []byte) (bool, error) {
+				_, h, err := tablecodec.DecodeRecordKey(key)
+				if err != nil {
+					return false, err
+				}
+				return addCandidate(key.Clone(), h, value)
+			})
+		case l.Idx != nil:
+			idxVals := make([]types.Datum, len(convertedVals))
+			copy(idxVals, convertedVals)
+			tablecodec.TruncateIndexValues(tblInfo, l.Idx, idxVals)
+			var encoded []byte
+			encoded, err = codec.EncodeKey(sc, nil, idxVals...)
+			if err != nil {
+				return nil, err
+			}
+			err = iterFKKeys(txn, tablecodec.EncodeIndexSeekKey(pid, l.Idx.ID, encoded), func(key kv.Key, value []byte) (bool, error) {
+				h, err := tablecodec.DecodeIndexHandle(key, value, len(l.Idx.Columns))
+				if err != nil {
+					return false, err
+				}
+				rowKey := tablecodec.EncodeRowKeyWithHandle(pid, h)
+				rowValue, err := txn.Get(ctx, rowKey)
+				if err != nil {
+					if kv.IsErrNotFound(err) {
+						return true, nil
+					}
+					return false, err
+				}
+				return addCandidate(rowKey, h, rowValue)
+			})
+		default:
+			// The foreign keys created before the indexes are required have to scan the whole table.
+			err = iterFKKeys(txn, tablecodec.GenTableRecordPrefix(pid), func(key kv.Key, value []byte) (bool, error) {
+				_, h, err := tablecodec.DecodeRecordKey(key)
+				if err != nil {
+					return false, err
+				}
+				return addCandidate(key.Clone(), h, value)
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if limit > 0 && len(rows) >= limit {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// iterFKKeys iterates the keys with the prefix until fn returns false.
+func iterFKKeys(txn kv.Transaction, prefix kv.Key, fn func(key kv.Key, value []byte) (bool, error)) error {
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		more, err := fn(it.Key(), it.Value())
+		if err != nil || !more {
+			return err
+		}
+		if err = it.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isFKError checks whether the error is caused by violating the foreign key constraints.
+func isFKError(err error) bool {
+	return plannercore.ErrNoReferencedRow2.Equal(err) || plannercore.ErrRowIsReferenced2.Equal(err)
+}
+
+// isIgnorableConstraintErr checks whether the row violating the constraints can be skipped by `INSERT IGNORE`.
+func isIgnorableConstraintErr(err error) bool {
+	return isFKError(err) || table.ErrCheckConstraintViolated.Equal(err)
+}
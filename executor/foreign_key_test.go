@@ -0,0 +1,285 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignKeyCheckOnChild(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int primary key, name varchar(10), unique key uk(name))")
+	tk.MustExec("create table child(id int primary key, pid int, pname varchar(10), " +
+		"foreign key fk_id(pid) references parent(id), foreign key fk_name(pname) references parent(name))")
+	tk.MustExec("insert into parent values (1, 'a'), (2, 'b')")
+
+	tk.MustExec("insert into child values (1, 1, 'a'), (2, null, null), (3, 2, null)")
+	tk.MustGetErrCode("insert into child values (4, 3, null)", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("insert into child values (4, null, 'c')", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("update child set pid = 3 where id = 1", errno.ErrNoReferencedRow2)
+	tk.MustExec("update child set pid = 2 where id = 1")
+	tk.MustGetErrCode("replace into child values (1, 5, null)", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("insert into child values (1, 1, 'a') on duplicate key update pid = 4", errno.ErrNoReferencedRow2)
+	tk.MustExec("insert into child values (1, 1, 'a') on duplicate key update pid = 1")
+
+	// INSERT IGNORE skips the invalid rows with warnings.
+	tk.MustExec("insert ignore into child values (5, 1, 'a'), (6, 9, null)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`child`, CONSTRAINT `fk_id` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`))"))
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("1 1 a", "2 <nil> <nil>", "3 2 <nil>", "5 1 a"))
+
+	// The rows inserted in the same transaction are visible to the checks.
+	tk.MustExec("begin")
+	tk.MustExec("insert into parent values (3, 'c')")
+	tk.MustExec("insert into child values (7, 3, 'c')")
+	tk.MustExec("commit")
+
+	// The checks are skipped if foreign_key_checks is disabled.
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("insert into child values (8, 100, 'z')")
+	tk.MustExec("delete from parent")
+	tk.MustQuery("select count(*) from child").Check(testkit.Rows("6"))
+}
+
+func TestForeignKeyCheckOnParent(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int, a int, primary key(id, a) clustered)")
+	tk.MustExec("create table child(id int primary key, pid int, pa int, foreign key fk(pid, pa) references parent(id, a))")
+	tk.MustExec("insert into parent values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into child values (1, 1, 1), (2, 2, null)")
+
+	tk.MustGetErrCode("delete from parent where id = 1", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("update parent set a = 10 where id = 1", errno.ErrRowIsReferenced2)
+	// The child row with NULL doesn't reference any parent row.
+	tk.MustExec("delete from parent where id = 2")
+	tk.MustExec("update parent set a = 30 where id = 3")
+	tk.MustQuery("select * from parent order by id").Check(testkit.Rows("1 1", "3 30"))
+
+	tk.MustGetErrCode("delete parent from child join parent on child.pid = parent.id", errno.ErrRowIsReferenced2)
+	tk.MustExec("delete from child where id = 1")
+	tk.MustExec("delete from parent where id = 1")
+	tk.MustQuery("select * from parent").Check(testkit.Rows("3 30"))
+}
+
+func TestForeignKeyCascade(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int primary key)")
+	tk.MustExec("create table child(id int primary key, pid int, index idx(pid), " +
+		"foreign key fk(pid) references parent(id) on delete cascade on update cascade)")
+	tk.MustExec("create table grandchild(id int primary key, cid int, " +
+		"foreign key fk(cid) references child(id) on delete set null on update cascade)")
+	tk.MustExec("insert into parent values (1), (2)")
+	tk.MustExec("insert into child values (10, 1), (11, 1), (20, 2)")
+	tk.MustExec("insert into grandchild values (100, 10), (101, 20)")
+
+	tk.MustExec("update parent set id = 3 where id = 1")
+	require.Equal(t, uint64(1), tk.Session().AffectedRows())
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("10 3", "11 3", "20 2"))
+
+	tk.MustExec("update child set id = 12 where id = 10")
+	tk.MustQuery("select * from grandchild order by id").Check(testkit.Rows("100 12", "101 20"))
+
+	tk.MustExec("delete from parent where id = 3")
+	require.Equal(t, uint64(1), tk.Session().AffectedRows())
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("20 2"))
+	tk.MustQuery("select * from grandchild order by id").Check(testkit.Rows("100 <nil>", "101 20"))
+
+	// The cascade actions are rolled back with the failed statement.
+	tk.MustExec("create table t(id int primary key, cid int, foreign key fk(cid) references child(id))")
+	tk.MustExec("insert into t values (1, 20)")
+	tk.MustGetErrCode("delete from parent where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("20 2"))
+	tk.MustQuery("select * from grandchild order by id").Check(testkit.Rows("100 <nil>", "101 20"))
+
+	// SET NULL on a NOT NULL column fails.
+	tk.MustExec("create table p2(id int primary key)")
+	tk.MustExec("create table c2(id int primary key, pid int not null, foreign key fk(pid) references p2(id) on delete set null)")
+	tk.MustExec("insert into p2 values (1)")
+	tk.MustExec("insert into c2 values (1, 1)")
+	tk.MustGetErrCode("delete from p2", errno.ErrBadNull)
+}
+
+func TestForeignKeyCascadeSelfReference(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t(id int primary key, pid int, foreign key fk(pid) references t(id) on delete cascade)")
+	tk.MustExec("insert into t values (1, null)")
+	for i := 2; i <= 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i-1))
+	}
+	tk.MustExec("delete from t where id = 5")
+	tk.MustQuery("select id from t order by id").Check(testkit.Rows("1", "2", "3", "4"))
+
+	for i := 5; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i-1))
+	}
+	tk.MustGetErrCode("delete from t where id = 1", errno.ErrFkDepthExceeded)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("20"))
+}
+
+func TestForeignKeyExplainAnalyze(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database other")
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table other.parent(id int primary key)")
+	tk.MustExec("create table child(id int primary key, pid int, foreign key fk(pid) references other.parent(id) on delete cascade)")
+	require.Contains(t, tk.MustQuery("show create table child").Rows()[0][1],
+		"CONSTRAINT `fk` FOREIGN KEY (`pid`) REFERENCES `other`.`parent` (`id`) ON DELETE CASCADE")
+	tk.MustExec("insert into other.parent values (1), (2)")
+
+	explainContains := func(sql string, ops ...string) {
+		rows := tk.MustQuery(sql).Rows()
+		result := fmt.Sprintf("%v", rows)
+		for _, op := range ops {
+			require.True(t, strings.Contains(result, op), "%s not found in %s", op, result)
+		}
+	}
+	explainContains("explain analyze insert into child values (1, 1), (2, 2)",
+		"Foreign_Key_Check", "table:parent, handle", "foreign_key:fk, check_exist")
+	explainContains("explain analyze delete from other.parent where id = 1",
+		"Foreign_Key_Cascade", "table:child", "foreign_key:fk, on_delete:CASCADE")
+	tk.MustQuery("select * from child").Check(testkit.Rows("2 2"))
+
+	tk.MustExec("set @@foreign_key_checks = 0")
+	rows := tk.MustQuery("explain insert into child values (3, 3)").Rows()
+	require.NotContains(t, fmt.Sprintf("%v", rows), "Foreign_Key_Check")
+}
+
+func TestForeignKeyIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int primary key, a int, b int, key idx_a(a))")
+	// The index on the foreign key columns is created with the child table.
+	tk.MustExec("create table child(id int primary key, pid int, pa int, foreign key fk(pid) references parent(id), " +
+		"foreign key fk_a(pa) references parent(a))")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 'child' and index_name != 'PRIMARY' order by index_name").Check(testkit.Rows(
+		"fk pid", "fk_a pa"))
+	rows := tk.MustQuery("explain format = 'brief' delete from parent where id = 1").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "table:child, index:fk(pid)")
+
+	tk.MustGetErrCode("create table child2(id int, pb int, foreign key fk(pb) references parent(b))", errno.ErrFkNoIndexParent)
+	tk.MustExec("create table child2(id int, pb int, pc int)")
+	// The index on the foreign key columns is built when the foreign key is added.
+	tk.MustExec("alter table child2 add foreign key fk_c(pc) references parent(id)")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 'child2'").Check(testkit.Rows(
+		"fk_c pc"))
+	tk.MustExec("alter table child2 add index idx_pb(pb)")
+	tk.MustGetErrCode("alter table child2 add foreign key fk(pb) references parent(b)", errno.ErrFkNoIndexParent)
+	tk.MustExec("alter table child2 add foreign key fk(pb) references parent(a)")
+
+	// The indexes needed by the foreign keys can't be dropped.
+	tk.MustGetErrCode("alter table child drop index fk", errno.ErrDropIndexFk)
+	tk.MustGetErrCode("alter table child2 drop index idx_pb", errno.ErrDropIndexFk)
+	tk.MustGetErrCode("alter table parent drop index idx_a", errno.ErrDropIndexFk)
+	tk.MustExec("alter table child add index idx_pid(pid, pa)")
+	tk.MustExec("alter table child drop index fk")
+	tk.MustExec("alter table child drop foreign key fk_a")
+	tk.MustExec("alter table child drop index fk_a")
+}
+
+func TestForeignKeyCheckInOptimisticTxn(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int primary key)")
+	tk.MustExec("create table child(id int primary key, pid int, foreign key fk(pid) references parent(id))")
+	tk.MustExec("insert into parent values (1), (2)")
+
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into child values (1, 1)")
+	// The parent row is deleted by another transaction before the child row is committed.
+	tk2.MustExec("delete from parent where id = 1")
+	require.Error(t, tk.ExecToErr("commit"))
+	tk.MustQuery("select * from child").Check(testkit.Rows())
+
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into child values (2, 2)")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from child").Check(testkit.Rows("2 2"))
+}
+
+func TestAddForeignKeyCheckExistingRows(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table parent(id int primary key)")
+	tk.MustExec("create table child(id int primary key, pid int, index idx_pid(pid))")
+	tk.MustExec("insert into parent values (1)")
+	tk.MustExec("insert into child values (1, 1), (2, null), (3, 2)")
+	// The existing child row (3, 2) has no parent row.
+	tk.MustGetErrCode("alter table child add foreign key fk(pid) references parent(id)", errno.ErrNoReferencedRow2)
+	tk.MustQuery("select count(*) from information_schema.key_column_usage where table_name = 'child' and constraint_name = 'fk'").Check(testkit.Rows("0"))
+	tk.MustExec("insert into child values (4, 3)")
+	// The index built for the foreign key is rolled back as well.
+	tk.MustExec("create table child2(id int primary key, pid int)")
+	tk.MustExec("insert into child2 values (1, 2)")
+	tk.MustGetErrCode("alter table child2 add foreign key fk(pid) references parent(id)", errno.ErrNoReferencedRow2)
+	tk.MustQuery("select count(*) from information_schema.statistics where table_name = 'child2' and index_name = 'fk'").Check(testkit.Rows("0"))
+
+	tk.MustExec("delete from child where pid > 1")
+	tk.MustExec("alter table child add foreign key fk(pid) references parent(id)")
+	tk.MustGetErrCode("insert into child values (5, 2)", errno.ErrNoReferencedRow2)
+	tk.MustExec("alter table child drop foreign key fk")
+
+	// The existing rows aren't checked when foreign_key_checks is off.
+	tk.MustExec("insert into child values (5, 2)")
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("alter table child add foreign key fk(pid) references parent(id)")
+	require.Contains(t, tk.MustQuery("show create table child").Rows()[0][1],
+		"CONSTRAINT `fk` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`)")
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHypoIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c varchar(20), index idx_b(b))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, '%d')", i, i%10, i))
+	}
+	tk.MustExec("analyze table t")
+
+	hasIndex := func(sql, index string) bool {
+		for _, row := range tk.MustQuery(sql).Rows() {
+			if strings.Contains(fmt.Sprintf("%v", row), index) {
+				return true
+			}
+		}
+		return false
+	}
+	require.False(t, hasIndex("explain select * from t where a = 1", "idx_a"))
+
+	tk.MustExec("create index idx_a on t(a) type hypo")
+	require.True(t, hasIndex("explain select * from t where a = 1", "index:idx_a(a)"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 the plan uses the hypothetical index 'idx_a' of table 't'"))
+	require.True(t, hasIndex("explain update t set b = 1 where a = 1", "index:idx_a(a)"))
+	// The hypothetical index is never used to execute statements.
+	require.False(t, hasIndex("explain analyze select * from t where a = 1", "idx_a"))
+	tk.MustQuery("select b from t where a = 11").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from t use index(idx_b)").Check(testkit.Rows("100"))
+	// The hypothetical index doesn't exist in the storage or the schema.
+	tk.MustQuery("show index from t where key_name = 'idx_a'").Check(testkit.Rows())
+	require.NotContains(t, tk.MustQuery("show create table t").Rows()[0][1], "idx_a")
+	tk.MustExec("admin check table t")
+
+	// The hypothetical index is only visible to the session which creates it.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	for _, row := range tk2.MustQuery("explain select * from t where a = 1").Rows() {
+		require.NotContains(t, fmt.Sprintf("%v", row), "idx_a")
+	}
+
+	tk.MustGetErrMsg("create index idx_a on t(c) type hypo", "[ddl:1061]index already exist idx_a")
+	tk.MustExec("create index if not exists idx_a on t(c) type hypo")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1061 index already exist idx_a"))
+	tk.MustGetErrMsg("create index idx_b on t(c) type hypo", "[ddl:1061]index already exist idx_b")
+	tk.MustGetErrCode("create index idx_d on t(d) type hypo", 1072)
+	tk.MustGetErrCode("create index idx_e on t((a + 1)) type hypo", 8200)
+	tk.MustGetErrCode("create index idx_a on t_not_exists(a) type hypo", 1146)
+
+	tk.MustExec("create unique index idx_c on t(c) type hypo")
+	require.True(t, hasIndex("explain select * from t where c = '1'", "idx_c"))
+	tk.MustExec("drop hypo index idx_c on t")
+	require.False(t, hasIndex("explain select * from t where c = '1'", "idx_c"))
+	tk.MustGetErrMsg("drop hypo index idx_c on t", "[ddl:1091]index idx_c doesn't exist")
+	tk.MustExec("drop hypo index if exists idx_c on t")
+	tk.MustGetErrMsg("drop hypo index idx_b on t", "[ddl:1091]index idx_b doesn't exist")
+
+	// The hypothetical index which refers to a dropped column is ignored.
+	tk.MustExec("alter table t drop column a")
+	require.False(t, hasIndex("explain select * from t where b = 1 and c = '1'", "idx_a"))
+}
+
+func TestHypoIndexOnlyByCreateIndex(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	errMsg := "[ddl:8200]HYPO index can only be created by CREATE INDEX"
+	tk.MustGetErrMsg("alter table t add index idx_a(a) using hypo", errMsg)
+	tk.MustGetErrMsg("alter table t add unique index idx_a(a) type hypo", errMsg)
+	tk.MustGetErrMsg("alter table t add primary key(a) nonclustered using hypo", errMsg)
+	tk.MustGetErrMsg("create table t1(a int, b int, key idx_a(a) using hypo)", errMsg)
+	tk.MustGetErrMsg("create table t1(a int, primary key(a) using hypo)", errMsg)
+	tk.MustQuery("show index from t").Check(testkit.Rows())
+	tk.MustQuery("show tables like 't1'").Check(testkit.Rows())
+}
@@ -16,12 +16,16 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -123,13 +127,114 @@ func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	advisor, err := newIndexAdvisor(ctx, e)
+	if err != nil {
+		return err
+	}
+	items, err := advisor.advise(ctx)
+	if err != nil {
+		return err
+	}
+	e.Result = &IndexAdvice{Items: items}
 	return nil
 }
 
-// IndexAdvice represents the index advice.
+// IndexAdviceItem represents an index recommended by the index advisor.
+type IndexAdviceItem struct {
+	Database  string
+	Table     string
+	IndexName string
+	Columns   []string
+	// Benefit is the estimated cost reduction of the workload if the index is created.
+	Benefit float64
+	// Statements are the statements in the workload whose plans benefit from the index.
+	Statements []string
+}
+
+// DDL returns the statement to create the recommended index.
+func (item *IndexAdviceItem) DDL() string {
+	cols := make([]string, 0, len(item.Columns))
+	for _, col := range item.Columns {
+		cols = append(cols, quoteIdentifier(col))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s(%s)", quoteIdentifier(item.IndexName),
+		quoteIdentifier(item.Database), quoteIdentifier(item.Table), strings.Join(cols, ", "))
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// IndexAdvice represents the index advice. It implements the sqlexec.RecordSet interface, and the rows are
+// ordered by the estimated benefit of the indexes.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	Items []*IndexAdviceItem
+
+	fields []*ast.ResultField
+	cursor int
+}
+
+var indexAdviceFields = []struct {
+	name string
+	tp   byte
+}{
+	{"DATABASE", mysql.TypeVarchar},
+	{"TABLE", mysql.TypeVarchar},
+	{"INDEX_NAME", mysql.TypeVarchar},
+	{"INDEX_COLUMNS", mysql.TypeVarchar},
+	{"DDL", mysql.TypeVarchar},
+	{"ESTIMATED_BENEFIT", mysql.TypeDouble},
+	{"AFFECTED_STATEMENTS", mysql.TypeLongBlob},
+}
+
+// Fields implements the sqlexec.RecordSet Fields interface.
+func (e *IndexAdvice) Fields() []*ast.ResultField {
+	if e.fields == nil {
+		e.fields = make([]*ast.ResultField, 0, len(indexAdviceFields))
+		for _, field := range indexAdviceFields {
+			name := model.NewCIStr(field.name)
+			e.fields = append(e.fields, &ast.ResultField{
+				Column:       &model.ColumnInfo{Name: name, FieldType: *types.NewFieldType(field.tp)},
+				ColumnAsName: name,
+			})
+		}
+	}
+	return e.fields
+}
+
+// Next implements the sqlexec.RecordSet Next interface.
+func (e *IndexAdvice) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; e.cursor < len(e.Items) && !req.IsFull(); e.cursor++ {
+		item := e.Items[e.cursor]
+		req.AppendString(0, item.Database)
+		req.AppendString(1, item.Table)
+		req.AppendString(2, item.IndexName)
+		req.AppendString(3, strings.Join(item.Columns, ","))
+		req.AppendString(4, item.DDL())
+		req.AppendFloat64(5, item.Benefit)
+		req.AppendString(6, strings.Join(item.Statements, "\n"))
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet NewChunk interface.
+func (e *IndexAdvice) NewChunk(alloc chunk.Allocator) *chunk.Chunk {
+	fields := e.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, field := range fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	if alloc == nil {
+		return chunk.New(fieldTypes, len(e.Items), len(e.Items))
+	}
+	return alloc.Alloc(fieldTypes, len(e.Items), len(e.Items))
+}
+
+// Close implements the sqlexec.RecordSet Close interface.
+func (e *IndexAdvice) Close() error {
+	e.cursor = 0
+	return nil
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
@@ -15,10 +15,13 @@
 package executor_test
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/stretchr/testify/require"
@@ -29,6 +32,16 @@ func TestIndexAdvise(t *testing.T) {
 	defer clean()
 
 	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	for i := 0; i < 100; i++ {
+		for _, tbl := range []string{"t", "t1", "t2"} {
+			tk.MustExec(fmt.Sprintf("insert into %s values (%d, %d)", tbl, i, i))
+		}
+	}
+	tk.MustExec("analyze table t, t1, t2")
 
 	_, err := tk.Exec("index advise infile '/tmp/nonexistence.sql'")
 	require.EqualError(t, err, "Index Advise: don't support load file without local field")
@@ -51,15 +64,14 @@ func TestIndexAdvise(t *testing.T) {
 	_, err = fp.WriteString("\n" +
 		"select * from t;\n" +
 		"\n" +
-		"select * from t where a > 1;\n" +
-		"select a from t where a > 1 and a < 100;\n" +
+		"select * from t where a = 1;\n" +
+		"select a from t where a > 1 and a < 5;\n" +
 		"\n" +
 		"\n" +
-		"select a,b from t1,t2 where t1.a = t2.b;\n" +
+		"select t1.a, t2.b from t1, t2 where t1.a = t2.b and t1.b = 1;\n" +
 		"\n")
 	require.NoError(t, err)
 
-	// TODO: Using "tastCase" to do more test when we finish the index advisor completely.
 	tk.MustExec("index advise local infile '/tmp/index_advise.sql' max_minutes 3 max_idxnum per_table 4 per_db 5")
 	ctx := tk.Session().(sessionctx.Context)
 	ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
@@ -68,4 +80,86 @@ func TestIndexAdvise(t *testing.T) {
 	require.Equal(t, uint64(3), ia.MaxMinutes)
 	require.Equal(t, uint64(4), ia.MaxIndexNum.PerTable)
 	require.Equal(t, uint64(5), ia.MaxIndexNum.PerDB)
+
+	// The file is sent by the client and the advice is returned as a result set.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), data))
+	// "select * from t" reads the whole table, so no index is recommended for it. The join uses the index on
+	// its filter column t1.b and the index on its join column t2.b.
+	require.Len(t, ia.Result.Items, 3)
+	require.Equal(t, "CREATE INDEX `idx_a` ON `test`.`t`(`a`)", ia.Result.Items[0].DDL())
+	require.Equal(t, []string{"select * from t where a = 1;", "select a from t where a > 1 and a < 5;"}, ia.Result.Items[0].Statements)
+	require.Equal(t, "CREATE INDEX `idx_b` ON `test`.`t2`(`b`)", ia.Result.Items[1].DDL())
+	require.Equal(t, "CREATE INDEX `idx_b` ON `test`.`t1`(`b`)", ia.Result.Items[2].DDL())
+	for _, item := range ia.Result.Items[1:] {
+		require.Equal(t, []string{"select t1.a, t2.b from t1, t2 where t1.a = t2.b and t1.b = 1;"}, item.Statements)
+	}
+	for _, item := range ia.Result.Items {
+		require.Greater(t, item.Benefit, 0.0)
+	}
+}
+
+func TestIndexAdviseRecommend(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, c int, d text, index idx_c(c))")
+	tk.MustExec("create table t2(a int primary key, b int, c int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values (%d, %d, %d, 'x')", i, i%10, i))
+		tk.MustExec(fmt.Sprintf("insert into t2 values (%d, %d, %d)", i, i%10, i))
+	}
+	tk.MustExec("analyze table t1, t2")
+
+	workload := "select * from t1 where a = 1;\n" +
+		"select * from t1 where a = 2 and b > 3;\n" +
+		"select * from t1 where c = 5;\n" +
+		"select * from t1 where d = 'x';\n" +
+		"select * from t2 where a = 1;\n" +
+		"insert into t1 values (1, 1, 1, 'x');\n" +
+		"select * from t_not_exists where a = 1;\n"
+	ctx := tk.Session().(sessionctx.Context)
+	ia := &executor.IndexAdviseInfo{
+		MaxMinutes: ast.UnspecifiedSize,
+		LinesInfo:  &ast.LinesClause{Terminated: "\n"},
+		Ctx:        ctx,
+	}
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), []byte(workload)))
+	require.NotNil(t, ia.Result)
+	// Only the index of t1(a, b) is useful: t1.c is already indexed, t1.d can't be indexed without the prefix
+	// length, t2.a is the primary key, and t1(a) is the prefix of t1(a, b).
+	require.Len(t, ia.Result.Items, 1)
+	first := ia.Result.Items[0]
+	require.Equal(t, "test", first.Database)
+	require.Equal(t, "t1", first.Table)
+	require.Equal(t, []string{"a", "b"}, first.Columns)
+	require.Greater(t, first.Benefit, 0.0)
+	require.Equal(t, "CREATE INDEX `idx_a_b` ON `test`.`t1`(`a`, `b`)", first.DDL())
+	require.Equal(t, []string{"select * from t1 where a = 1;", "select * from t1 where a = 2 and b > 3;"}, first.Statements)
+	warnings := ctx.GetSessionVars().StmtCtx.GetWarnings()
+	require.Contains(t, warnings[len(warnings)-1].Err.Error(), "Table 'test.t_not_exists' doesn't exist")
+
+	// The recommended indexes are limited by MAX_IDXNUM.
+	workload += "select * from t2 where b = 1 and c = 2;\n"
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), []byte(workload)))
+	require.Len(t, ia.Result.Items, 2)
+	ia.MaxIndexNum = &ast.MaxIndexNumClause{PerTable: 1, PerDB: 1}
+	require.NoError(t, ia.GetIndexAdvice(context.Background(), []byte(workload)))
+	require.Len(t, ia.Result.Items, 1)
+	first = ia.Result.Items[0]
+
+	// The result is returned as a record set.
+	rs := ia.Result
+	require.Len(t, rs.Fields(), 7)
+	chk := rs.NewChunk(nil)
+	require.NoError(t, rs.Next(context.Background(), chk))
+	require.Equal(t, 1, chk.NumRows())
+	require.Equal(t, "t1", chk.GetRow(0).GetString(1))
+	require.Equal(t, first.DDL(), chk.GetRow(0).GetString(4))
+	require.NoError(t, rs.Next(context.Background(), chk))
+	require.Equal(t, 0, chk.NumRows())
+	require.NoError(t, rs.Close())
 }
@@ -0,0 +1,603 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	// defaultMaxIndexNumPerTable is the maximum number of the recommended indexes of a table if it's not specified.
+	defaultMaxIndexNumPerTable = 5
+	// maxAdvisedIndexColumns is the maximum number of columns of a recommended index.
+	maxAdvisedIndexColumns = 3
+)
+
+// indexCandidate is a hypothetical index which may reduce the cost of the workload.
+type indexCandidate struct {
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+	columns []*model.ColumnInfo
+}
+
+func (c *indexCandidate) key() string {
+	cols := make([]string, 0, len(c.columns))
+	for _, col := range c.columns {
+		cols = append(cols, col.Name.L)
+	}
+	return c.dbName.L + "." + c.tblInfo.Name.L + "(" + strings.Join(cols, ",") + ")"
+}
+
+// coveredBy checks whether an existing index of the table starts with the columns of the candidate, in which
+// case the candidate is useless.
+func (c *indexCandidate) coveredBy(idx *model.IndexInfo) bool {
+	if len(idx.Columns) < len(c.columns) {
+		return false
+	}
+	for i, col := range c.columns {
+		if idx.Columns[i].Name.L != col.Name.L || idx.Columns[i].Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *indexCandidate) isPrefixOf(other *indexCandidate) bool {
+	if c.tblInfo.ID != other.tblInfo.ID || len(c.columns) > len(other.columns) {
+		return false
+	}
+	for i, col := range c.columns {
+		if other.columns[i].ID != col.ID {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *indexCandidate) indexName() string {
+	name := "idx"
+	for _, col := range c.columns {
+		name += "_" + col.Name.O
+	}
+	// Avoid conflicting with the names of the existing indexes.
+	for i, newName := 2, name; ; i++ {
+		if c.tblInfo.FindIndexByName(strings.ToLower(newName)) == nil {
+			return newName
+		}
+		newName = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+// adviseStmt is a statement of the workload.
+type adviseStmt struct {
+	node ast.StmtNode
+	text string
+	// tableIDs are the IDs of the tables accessed by the statement.
+	tableIDs map[int64]struct{}
+	// cost is the estimated cost of the statement with the chosen indexes.
+	cost float64
+}
+
+// indexAdvisor recommends indexes for the workload by the what-if analysis. It generates the index candidates
+// from the predicates, the join keys and the ORDER BY / GROUP BY items of the statements, and then greedily picks
+// the candidate which reduces the estimated cost of the workload most, until the limits are reached.
+type indexAdvisor struct {
+	sctx        sessionctx.Context
+	is          infoschema.InfoSchema
+	info        *IndexAdviseInfo
+	deadline    time.Time
+	maxPerTable uint64
+	maxPerDB    uint64
+
+	stmts      []*adviseStmt
+	candidates []*indexCandidate
+}
+
+func newIndexAdvisor(ctx context.Context, info *IndexAdviseInfo) (*indexAdvisor, error) {
+	advisor := &indexAdvisor{
+		sctx:        info.Ctx,
+		is:          info.Ctx.GetInfoSchema().(infoschema.InfoSchema),
+		info:        info,
+		maxPerTable: defaultMaxIndexNumPerTable,
+		maxPerDB:    ast.UnspecifiedSize,
+	}
+	if info.MaxMinutes != ast.UnspecifiedSize {
+		advisor.deadline = time.Now().Add(time.Duration(info.MaxMinutes) * time.Minute)
+	}
+	if info.MaxIndexNum != nil {
+		if info.MaxIndexNum.PerTable != ast.UnspecifiedSize {
+			advisor.maxPerTable = info.MaxIndexNum.PerTable
+		}
+		advisor.maxPerDB = info.MaxIndexNum.PerDB
+	}
+	candidateKeys := make(map[string]struct{})
+	sc := advisor.sctx.GetSessionVars().StmtCtx
+	for _, stmtNodes := range info.StmtNodes {
+		for _, node := range stmtNodes {
+			switch node.(type) {
+			case *ast.SelectStmt, *ast.SetOprStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+			default:
+				continue
+			}
+			ret := &plannercore.PreprocessorReturn{InfoSchema: advisor.is}
+			if err := plannercore.Preprocess(advisor.sctx, node, plannercore.WithPreprocessorReturn(ret)); err != nil {
+				sc.AppendWarning(errors.Errorf("Index Advise: skip statement '%s': %v", node.Text(), err))
+				continue
+			}
+			collector := newIndexableColumnsCollector()
+			node.Accept(collector)
+			stmt := &adviseStmt{
+				node:     node,
+				text:     strings.TrimSpace(node.Text()),
+				tableIDs: make(map[int64]struct{}, len(collector.tables)),
+			}
+			for _, tbl := range collector.tables {
+				stmt.tableIDs[tbl.TableInfo.ID] = struct{}{}
+			}
+			for _, c := range collector.candidates() {
+				if _, ok := candidateKeys[c.key()]; ok {
+					continue
+				}
+				candidateKeys[c.key()] = struct{}{}
+				advisor.candidates = append(advisor.candidates, c)
+			}
+			advisor.stmts = append(advisor.stmts, stmt)
+		}
+	}
+	return advisor, nil
+}
+
+func (a *indexAdvisor) timeout() bool {
+	return !a.deadline.IsZero() && time.Now().After(a.deadline)
+}
+
+// hypoInfoSchema returns an InfoSchema which attaches the indexes to the tables as the hypothetical indexes.
+func (a *indexAdvisor) hypoInfoSchema(indexes []*indexCandidate) infoschema.InfoSchema {
+	hypoIndexes := make(map[int64][]*model.IndexInfo)
+	for _, c := range indexes {
+		idxInfo := &model.IndexInfo{
+			Name:  model.NewCIStr(c.indexName()),
+			Table: c.tblInfo.Name,
+			State: model.StatePublic,
+			Tp:    model.IndexTypeHypo,
+		}
+		for _, col := range c.columns {
+			idxInfo.Columns = append(idxInfo.Columns, &model.IndexColumn{
+				Name:   col.Name,
+				Offset: col.Offset,
+				Length: types.UnspecifiedLength,
+			})
+		}
+		hypoIndexes[c.tblInfo.ID] = append(hypoIndexes[c.tblInfo.ID], idxInfo)
+	}
+	return infoschema.AttachHypoIndexes(a.is, hypoIndexes)
+}
+
+func (a *indexAdvisor) estimateCost(ctx context.Context, stmt *adviseStmt, is infoschema.InfoSchema) (float64, error) {
+	_, cost, err := planner.OptimizeForCost(ctx, a.sctx, stmt.node, is)
+	return cost, err
+}
+
+func (a *indexAdvisor) advise(ctx context.Context) ([]*IndexAdviceItem, error) {
+	sc := a.sctx.GetSessionVars().StmtCtx
+	validStmts := a.stmts[:0]
+	for _, stmt := range a.stmts {
+		cost, err := a.estimateCost(ctx, stmt, a.is)
+		if err != nil {
+			sc.AppendWarning(errors.Errorf("Index Advise: skip statement '%s': %v", stmt.text, err))
+			continue
+		}
+		stmt.cost = cost
+		validStmts = append(validStmts, stmt)
+	}
+	a.stmts = validStmts
+
+	var (
+		chosen      []*indexCandidate
+		items       []*IndexAdviceItem
+		numPerTable = make(map[int64]uint64)
+		numPerDB    = make(map[string]uint64)
+		remaining   = a.candidates
+	)
+	for timeout := false; len(remaining) > 0 && !timeout; {
+		var (
+			best         *indexCandidate
+			bestBenefit  float64
+			bestCosts    map[*adviseStmt]float64
+			nextRemained = remaining[:0]
+		)
+		for _, c := range remaining {
+			if numPerTable[c.tblInfo.ID] >= a.maxPerTable || numPerDB[c.dbName.L] >= a.maxPerDB {
+				continue
+			}
+			if timeout = a.timeout(); timeout {
+				sc.AppendWarning(errors.New("Index Advise: the time limit is reached, the advice may be incomplete"))
+				break
+			}
+			is := a.hypoInfoSchema(append(chosen[:len(chosen):len(chosen)], c))
+			benefit, costs := 0.0, make(map[*adviseStmt]float64)
+			for _, stmt := range a.stmts {
+				if _, ok := stmt.tableIDs[c.tblInfo.ID]; !ok {
+					continue
+				}
+				cost, err := a.estimateCost(ctx, stmt, is)
+				if err != nil {
+					return nil, err
+				}
+				if cost < stmt.cost {
+					benefit += stmt.cost - cost
+					costs[stmt] = cost
+				}
+			}
+			// The candidate which benefits nothing now is unlikely to benefit when more indexes are chosen.
+			if benefit <= 0 {
+				continue
+			}
+			nextRemained = append(nextRemained, c)
+			if best == nil || benefit > bestBenefit {
+				best, bestBenefit, bestCosts = c, benefit, costs
+			}
+		}
+		if best == nil {
+			break
+		}
+		chosen = append(chosen, best)
+		numPerTable[best.tblInfo.ID]++
+		numPerDB[best.dbName.L]++
+		item := &IndexAdviceItem{
+			Database:  best.dbName.O,
+			Table:     best.tblInfo.Name.O,
+			IndexName: best.indexName(),
+			Benefit:   bestBenefit,
+		}
+		for _, col := range best.columns {
+			item.Columns = append(item.Columns, col.Name.O)
+		}
+		for _, stmt := range a.stmts {
+			if cost, ok := bestCosts[stmt]; ok {
+				stmt.cost = cost
+				item.Statements = append(item.Statements, stmt.text)
+			}
+		}
+		items = append(items, item)
+		remaining = remaining[:0]
+		for _, c := range nextRemained {
+			// The candidate whose columns are the prefix of the chosen index is redundant.
+			if c != best && !c.isPrefixOf(best) {
+				remaining = append(remaining, c)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Benefit > items[j].Benefit
+	})
+	return items, nil
+}
+
+// tableIndexableColumns records the columns of a table that may benefit from indexes in a statement.
+type tableIndexableColumns struct {
+	tbl *ast.TableName
+	// eqCols are the columns in the equal conditions, IN conditions and the join keys.
+	eqCols []*model.ColumnInfo
+	// rangeCols are the columns in the range conditions.
+	rangeCols []*model.ColumnInfo
+	// orderCols are the columns in the ORDER BY or GROUP BY items.
+	orderCols []*model.ColumnInfo
+}
+
+// indexableColumnsCollector is an ast.Visitor which collects the columns that may benefit from indexes.
+// The statement must be preprocessed, so the table names are resolved.
+type indexableColumnsCollector struct {
+	// tables maps the alias of a table to its name.
+	tables  map[string]*ast.TableName
+	columns map[int64]*tableIndexableColumns
+	// tblIDs keeps the order in which the tables are found.
+	tblIDs []int64
+	// pending saves the expressions and the order by items until all the tables are known.
+	conds     []ast.ExprNode
+	orderings [][]*ast.ByItem
+}
+
+func newIndexableColumnsCollector() *indexableColumnsCollector {
+	return &indexableColumnsCollector{
+		tables:  make(map[string]*ast.TableName),
+		columns: make(map[int64]*tableIndexableColumns),
+	}
+}
+
+// Enter implements ast.Visitor interface.
+func (c *indexableColumnsCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.TableSource:
+		if tn, ok := x.Source.(*ast.TableName); ok && tn.TableInfo != nil {
+			alias := tn.Name.L
+			if x.AsName.L != "" {
+				alias = x.AsName.L
+			}
+			c.tables[alias] = tn
+			if _, ok := c.columns[tn.TableInfo.ID]; !ok {
+				c.columns[tn.TableInfo.ID] = &tableIndexableColumns{tbl: tn}
+				c.tblIDs = append(c.tblIDs, tn.TableInfo.ID)
+			}
+		}
+	case *ast.SelectStmt:
+		if x.Where != nil {
+			c.conds = append(c.conds, x.Where)
+		}
+		if x.OrderBy != nil {
+			c.orderings = append(c.orderings, x.OrderBy.Items)
+		}
+		if x.GroupBy != nil {
+			c.orderings = append(c.orderings, x.GroupBy.Items)
+		}
+	case *ast.UpdateStmt:
+		if x.Where != nil {
+			c.conds = append(c.conds, x.Where)
+		}
+		if x.Order != nil {
+			c.orderings = append(c.orderings, x.Order.Items)
+		}
+	case *ast.DeleteStmt:
+		if x.Where != nil {
+			c.conds = append(c.conds, x.Where)
+		}
+		if x.Order != nil {
+			c.orderings = append(c.orderings, x.Order.Items)
+		}
+	case *ast.OnCondition:
+		c.conds = append(c.conds, x.Expr)
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *indexableColumnsCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// resolveColumn finds the table and the column info of the column name.
+// The column which can't be resolved or is ambiguous is ignored.
+func (c *indexableColumnsCollector) resolveColumn(expr ast.ExprNode) (*tableIndexableColumns, *model.ColumnInfo) {
+	colExpr, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	name := colExpr.Name
+	if name.Table.L != "" {
+		tn, ok := c.tables[name.Table.L]
+		if !ok {
+			return nil, nil
+		}
+		colInfo := model.FindColumnInfo(tn.TableInfo.Columns, name.Name.L)
+		if colInfo == nil {
+			return nil, nil
+		}
+		return c.columns[tn.TableInfo.ID], colInfo
+	}
+	var (
+		tblCols *tableIndexableColumns
+		colInfo *model.ColumnInfo
+	)
+	for _, id := range c.tblIDs {
+		cols := c.columns[id]
+		if col := model.FindColumnInfo(cols.tbl.TableInfo.Columns, name.Name.L); col != nil {
+			if colInfo != nil {
+				return nil, nil
+			}
+			tblCols, colInfo = cols, col
+		}
+	}
+	return tblCols, colInfo
+}
+
+func isConstantExpr(expr ast.ExprNode) bool {
+	switch x := expr.(type) {
+	case ast.ValueExpr:
+		return true
+	case *ast.ParenthesesExpr:
+		return isConstantExpr(x.Expr)
+	case *ast.UnaryOperationExpr:
+		return isConstantExpr(x.V)
+	case *ast.FuncCallExpr:
+		for _, arg := range x.Args {
+			if !isConstantExpr(arg) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func appendIndexableColumn(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	switch col.Tp {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeJSON:
+		// These columns can only be indexed with the prefix length.
+		return cols
+	}
+	for _, c := range cols {
+		if c.ID == col.ID {
+			return cols
+		}
+	}
+	return append(cols, col)
+}
+
+func (c *indexableColumnsCollector) collectCond(expr ast.ExprNode) {
+	switch x := expr.(type) {
+	case *ast.ParenthesesExpr:
+		c.collectCond(x.Expr)
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.LogicAnd, opcode.LogicOr:
+			c.collectCond(x.L)
+			c.collectCond(x.R)
+		case opcode.EQ, opcode.NullEQ:
+			lTbl, lCol := c.resolveColumn(x.L)
+			rTbl, rCol := c.resolveColumn(x.R)
+			switch {
+			case lCol != nil && rCol != nil:
+				// The join keys can be used by the index join.
+				if lTbl != rTbl {
+					lTbl.eqCols = appendIndexableColumn(lTbl.eqCols, lCol)
+					rTbl.eqCols = appendIndexableColumn(rTbl.eqCols, rCol)
+				}
+			case lCol != nil && isConstantExpr(x.R):
+				lTbl.eqCols = appendIndexableColumn(lTbl.eqCols, lCol)
+			case rCol != nil && isConstantExpr(x.L):
+				rTbl.eqCols = appendIndexableColumn(rTbl.eqCols, rCol)
+			}
+		case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+			if tbl, col := c.resolveColumn(x.L); col != nil && isConstantExpr(x.R) {
+				tbl.rangeCols = appendIndexableColumn(tbl.rangeCols, col)
+			} else if tbl, col := c.resolveColumn(x.R); col != nil && isConstantExpr(x.L) {
+				tbl.rangeCols = appendIndexableColumn(tbl.rangeCols, col)
+			}
+		}
+	case *ast.PatternInExpr:
+		if x.Not || x.Sel != nil {
+			return
+		}
+		tbl, col := c.resolveColumn(x.Expr)
+		if col == nil {
+			return
+		}
+		for _, item := range x.List {
+			if !isConstantExpr(item) {
+				return
+			}
+		}
+		tbl.eqCols = appendIndexableColumn(tbl.eqCols, col)
+	case *ast.IsNullExpr:
+		if tbl, col := c.resolveColumn(x.Expr); col != nil && !x.Not {
+			tbl.eqCols = appendIndexableColumn(tbl.eqCols, col)
+		}
+	case *ast.BetweenExpr:
+		if tbl, col := c.resolveColumn(x.Expr); col != nil && !x.Not && isConstantExpr(x.Left) && isConstantExpr(x.Right) {
+			tbl.rangeCols = appendIndexableColumn(tbl.rangeCols, col)
+		}
+	case *ast.PatternLikeExpr:
+		if x.Not {
+			return
+		}
+		tbl, col := c.resolveColumn(x.Expr)
+		if col == nil {
+			return
+		}
+		// Only the pattern with a constant prefix can be converted to a range.
+		if v, ok := x.Pattern.(ast.ValueExpr); ok {
+			if pattern, ok := v.GetValue().(string); ok && len(pattern) > 0 && pattern[0] != '%' && pattern[0] != '_' {
+				tbl.rangeCols = appendIndexableColumn(tbl.rangeCols, col)
+			}
+		}
+	}
+}
+
+func (c *indexableColumnsCollector) collectOrdering(items []*ast.ByItem) {
+	var (
+		tbl  *tableIndexableColumns
+		cols []*model.ColumnInfo
+	)
+	// Only the leading items from the same table can be satisfied by an index.
+	for _, item := range items {
+		itemTbl, col := c.resolveColumn(item.Expr)
+		if col == nil || (tbl != nil && itemTbl != tbl) {
+			break
+		}
+		tbl = itemTbl
+		cols = appendIndexableColumn(cols, col)
+	}
+	if tbl != nil && len(tbl.orderCols) == 0 {
+		tbl.orderCols = cols
+	}
+}
+
+// candidates generates the index candidates by the collected columns.
+func (c *indexableColumnsCollector) candidates() []*indexCandidate {
+	for _, cond := range c.conds {
+		c.collectCond(cond)
+	}
+	for _, items := range c.orderings {
+		c.collectOrdering(items)
+	}
+	var candidates []*indexCandidate
+	for _, id := range c.tblIDs {
+		cols := c.columns[id]
+		var colsList [][]*model.ColumnInfo
+		for _, col := range cols.eqCols {
+			colsList = append(colsList, []*model.ColumnInfo{col})
+		}
+		for _, col := range cols.rangeCols {
+			colsList = append(colsList, []*model.ColumnInfo{col})
+		}
+		eqCols := cols.eqCols
+		if len(eqCols) > maxAdvisedIndexColumns {
+			eqCols = eqCols[:maxAdvisedIndexColumns]
+		}
+		if len(eqCols) > 1 {
+			colsList = append(colsList, eqCols)
+		}
+		if len(eqCols) < maxAdvisedIndexColumns {
+			for _, col := range cols.rangeCols {
+				colsList = append(colsList, appendIndexableColumn(eqCols[:len(eqCols):len(eqCols)], col))
+			}
+		}
+		if len(cols.orderCols) > 0 {
+			orderCols := eqCols[:len(eqCols):len(eqCols)]
+			for _, col := range cols.orderCols {
+				if len(orderCols) >= maxAdvisedIndexColumns {
+					break
+				}
+				orderCols = appendIndexableColumn(orderCols, col)
+			}
+			colsList = append(colsList, orderCols)
+		}
+		for _, idxCols := range colsList {
+			candidate := &indexCandidate{dbName: cols.tbl.Schema, tblInfo: cols.tbl.TableInfo, columns: idxCols}
+			if !candidate.coveredByTable() {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+// coveredByTable checks whether the candidate is already covered by the existing indexes or the primary key.
+func (c *indexCandidate) coveredByTable() bool {
+	if len(c.columns) == 1 && c.tblInfo.PKIsHandle && mysql.HasPriKeyFlag(c.columns[0].Flag) {
+		return true
+	}
+	for _, idx := range c.tblInfo.Indices {
+		if idx.State == model.StatePublic && c.coveredBy(idx) {
+			return true
+		}
+	}
+	return false
+}
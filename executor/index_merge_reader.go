@@ -30,11 +30,13 @@ import (
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
@@ -191,7 +193,12 @@ func (e *IndexMergeReaderExecutor) buildKeyRangesForTable(tbl table.Table) (rang
 			ranges = append(ranges, keyRanges)
 			continue
 		}
-		keyRange, err := distsql.IndexRangesToKVRanges(sc, getPhysicalTableID(tbl), e.indexes[i].ID, e.ranges[i], e.feedbacks[i])
+		physicalID := getPhysicalTableID(tbl)
+		if e.indexes[i].Global {
+			// The global index is scanned once for all the partitions.
+			physicalID = tbl.Meta().ID
+		}
+		keyRange, err := distsql.IndexRangesToKVRanges(sc, physicalID, e.indexes[i].ID, e.ranges[i], e.feedbacks[i])
 		if err != nil {
 			return nil, err
 		}
@@ -260,7 +267,17 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 	}
 
 	var keyRanges [][]kv.KeyRange
-	if e.partitionTableMode {
+	var globalIndexPartitions map[int64]table.PhysicalTable
+	if e.partitionTableMode && e.indexes[workID].Global {
+		// The entries of all the pruned partitions are read by one scan, then dispatched by the partition IDs.
+		globalIndexPartitions = make(map[int64]table.PhysicalTable, len(e.prunedPartitions))
+		for _, p := range e.prunedPartitions {
+			globalIndexPartitions[p.GetPhysicalID()] = p
+		}
+		if len(e.partitionKeyRanges) > 0 {
+			keyRanges = [][]kv.KeyRange{e.partitionKeyRanges[0][workID]}
+		}
+	} else if e.partitionTableMode {
 		for _, pKeyRanges := range e.partitionKeyRanges { // get all keyRanges related to this PartialIndex
 			keyRanges = append(keyRanges, pKeyRanges[workID])
 		}
@@ -278,12 +295,17 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 		util.WithRecovery(
 			func() {
 				worker := &partialIndexWorker{
-					stats:        e.stats,
-					idxID:        e.getPartitalPlanID(workID),
-					sc:           e.ctx,
-					batchSize:    e.maxChunkSize,
-					maxBatchSize: e.ctx.GetSessionVars().IndexLookupSize,
-					maxChunkSize: e.maxChunkSize,
+					stats:                 e.stats,
+					idxID:                 e.getPartitalPlanID(workID),
+					sc:                    e.ctx,
+					batchSize:             e.maxChunkSize,
+					maxBatchSize:          e.ctx.GetSessionVars().IndexLookupSize,
+					maxChunkSize:          e.maxChunkSize,
+					globalIndexPartitions: globalIndexPartitions,
+				}
+				retTps := e.handleCols.GetFieldsTypes()
+				if e.indexes[workID].Global {
+					retTps = append(retTps, types.NewFieldType(mysql.TypeLonglong))
 				}
 
 				if e.isCorColInPartialFilters[workID] {
@@ -324,7 +346,7 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 						worker.syncErr(e.resultCh, err)
 						return
 					}
-					result, err := distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, e.handleCols.GetFieldsTypes(), e.feedbacks[workID], getPhysicalPlanIDs(e.partialPlans[workID]), e.getPartitalPlanID(workID))
+					result, err := distsql.SelectWithRuntimeStats(ctx, e.ctx, kvReq, retTps, e.feedbacks[workID], getPhysicalPlanIDs(e.partialPlans[workID]), e.getPartitalPlanID(workID))
 					if err != nil {
 						worker.syncErr(e.resultCh, err)
 						return
@@ -333,13 +355,13 @@ func (e *IndexMergeReaderExecutor) startPartialIndexWorker(ctx context.Context,
 					if worker.batchSize > worker.maxBatchSize {
 						worker.batchSize = worker.maxBatchSize
 					}
-					if e.partitionTableMode {
+					if e.partitionTableMode && globalIndexPartitions == nil {
 						worker.partition = e.prunedPartitions[parTblIdx]
 					}
 
 					// fetch all data from this partition
 					ctx1, cancel := context.WithCancel(ctx)
-					_, fetchErr := worker.fetchHandles(ctx1, result, exitCh, fetchCh, e.resultCh, e.finished, e.handleCols)
+					_, fetchErr := worker.fetchHandles(ctx1, result, exitCh, fetchCh, e.resultCh, e.finished, e.handleCols, retTps)
 					if fetchErr != nil { // this error is synced in fetchHandles(), don't sync it again
 						e.feedbacks[workID].Invalidate()
 					}
@@ -787,6 +809,9 @@ type partialIndexWorker struct {
 	maxBatchSize int
 	maxChunkSize int
 	partition    table.PhysicalTable // it indicates if this worker is accessing a particular partition table
+	// globalIndexPartitions indicates this worker is accessing a global index, the handles are dispatched to
+	// these partitions, and the ones of other partitions are skipped.
+	globalIndexPartitions map[int64]table.PhysicalTable
 }
 
 func (w *partialIndexWorker) syncErr(resultCh chan<- *lookupTableTask, err error) {
@@ -804,8 +829,9 @@ func (w *partialIndexWorker) fetchHandles(
 	fetchCh chan<- *lookupTableTask,
 	resultCh chan<- *lookupTableTask,
 	finished <-chan struct{},
-	handleCols plannercore.HandleCols) (count int64, err error) {
-	chk := chunk.NewChunkWithCapacity(handleCols.GetFieldsTypes(), w.maxChunkSize)
+	handleCols plannercore.HandleCols,
+	retTps []*types.FieldType) (count int64, err error) {
+	chk := chunk.NewChunkWithCapacity(retTps, w.maxChunkSize)
 	var basicStats *execdetails.BasicRuntimeStats
 	if w.stats != nil {
 		if w.idxID != 0 {
@@ -824,18 +850,25 @@ func (w *partialIndexWorker) fetchHandles(
 			return count, nil
 		}
 		count += int64(len(handles))
-		task := w.buildTableTask(handles, retChunk)
+		var tasks []*lookupTableTask
+		if w.globalIndexPartitions != nil {
+			tasks = w.buildGlobalIndexTableTasks(handles)
+		} else {
+			tasks = []*lookupTableTask{w.buildTableTask(handles, retChunk)}
+		}
 		if w.stats != nil {
 			atomic.AddInt64(&w.stats.FetchIdxTime, int64(time.Since(start)))
 		}
-		select {
-		case <-ctx.Done():
-			return count, ctx.Err()
-		case <-exitCh:
-			return count, nil
-		case <-finished:
-			return count, nil
-		case fetchCh <- task:
+		for _, task := range tasks {
+			select {
+			case <-ctx.Done():
+				return count, ctx.Err()
+			case <-exitCh:
+				return count, nil
+			case <-finished:
+				return count, nil
+			case fetchCh <- task:
+			}
 		}
 		if basicStats != nil {
 			basicStats.Record(time.Since(start), chk.NumRows())
@@ -856,6 +889,16 @@ func (w *partialIndexWorker) extractTaskHandles(ctx context.Context, chk *chunk.
 			return handles, retChk, nil
 		}
 		for i := 0; i < chk.NumRows(); i++ {
+			if w.globalIndexPartitions != nil {
+				handle, err := handleCols.BuildPartitionHandleFromIndexRow(chk.GetRow(i))
+				if err != nil {
+					return nil, nil, err
+				}
+				if _, ok := w.globalIndexPartitions[handle.PartitionID]; ok {
+					handles = append(handles, handle)
+				}
+				continue
+			}
 			handle, err := handleCols.BuildHandleFromIndexRow(chk.GetRow(i))
 			if err != nil {
 				return nil, nil, err
@@ -882,6 +925,24 @@ func (w *partialIndexWorker) buildTableTask(handles []kv.Handle, retChk *chunk.C
 	return task
 }
 
+// buildGlobalIndexTableTasks builds a task for each partition the handles of the global index belong to.
+func (w *partialIndexWorker) buildGlobalIndexTableTasks(handles []kv.Handle) []*lookupTableTask {
+	tasks := make([]*lookupTableTask, 0, 1)
+	partitionTasks := make(map[int64]*lookupTableTask)
+	for _, h := range handles {
+		ph := h.(kv.PartitionHandle)
+		task, ok := partitionTasks[ph.PartitionID]
+		if !ok {
+			task = w.buildTableTask(nil, nil)
+			task.partitionTable = w.globalIndexPartitions[ph.PartitionID]
+			partitionTasks[ph.PartitionID] = task
+			tasks = append(tasks, task)
+		}
+		task.handles = append(task.handles, ph.Handle)
+	}
+	return tasks
+}
+
 type indexMergeTableScanWorker struct {
 	stats          *IndexMergeRuntimeStat
 	workCh         <-chan *lookupTableTask
@@ -145,6 +145,10 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForTiDBHotRegions(sctx)
 		case infoschema.TableConstraints:
 			e.setDataFromTableConstraints(sctx, dbs)
+		case infoschema.TableCheckConstraints:
+			e.setDataFromCheckConstraints(sctx, dbs)
+		case infoschema.TableInstancePlanCache:
+			err = e.setDataForInstancePlanCache(sctx)
 		case infoschema.TableSessionVar:
 			err = e.setDataFromSessionVar(sctx)
 		case infoschema.TableTiDBServersInfo:
@@ -486,6 +490,9 @@ func (e *memtableRetriever) setDataFromReferConst(ctx context.Context, sctx sess
 				continue
 			}
 			for _, fk := range table.ForeignKeys {
+				if fk.State != model.StatePublic {
+					continue
+				}
 				updateRule, deleteRule := "NO ACTION", "NO ACTION"
 				if ast.ReferOptionType(fk.OnUpdate) != 0 {
 					updateRule = ast.ReferOptionType(fk.OnUpdate).String()
@@ -881,8 +888,10 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 					if table.Partition.Type == model.PartitionTypeRange && len(table.Partition.Columns) > 0 {
 						partitionMethod = "RANGE COLUMNS"
 						partitionExpr = table.Partition.Columns[0].String()
-					} else if table.Partition.Type == model.PartitionTypeList && len(table.Partition.Columns) > 0 {
-						partitionMethod = "LIST COLUMNS"
+					} else if table.Partition.Type == model.PartitionTypeKey || (table.Partition.Type == model.PartitionTypeList && len(table.Partition.Columns) > 0) {
+						if table.Partition.Type == model.PartitionTypeList {
+							partitionMethod = "LIST COLUMNS"
+						}
 						buf := bytes.NewBuffer(nil)
 						for i, col := range table.Partition.Columns {
 							if i > 0 {
@@ -1411,6 +1420,9 @@ func keyColumnUsageInTable(schema *model.DBInfo, table *model.TableInfo) [][]typ
 		}
 	}
 	for _, fk := range table.ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
 		fkRefCol := ""
 		if len(fk.RefCols) > 0 {
 			fkRefCol = fk.RefCols[0].O
@@ -1653,6 +1665,51 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 				)
 				rows = append(rows, record)
 			}
+
+			for _, constr := range tbl.Constraints {
+				if constr.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,          // CONSTRAINT_CATALOG
+					schema.Name.O,                  // CONSTRAINT_SCHEMA
+					constr.Name.O,                  // CONSTRAINT_NAME
+					schema.Name.O,                  // TABLE_SCHEMA
+					tbl.Name.O,                     // TABLE_NAME
+					infoschema.CheckConstraintType, // CONSTRAINT_TYPE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataFromCheckConstraints constructs data for table information_schema.check_constraints.
+// See https://dev.mysql.com/doc/refman/8.0/en/information-schema-check-constraints-table.html
+func (e *memtableRetriever) setDataFromCheckConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if len(tbl.Constraints) == 0 {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, constr := range tbl.Constraints {
+				if constr.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,                  // CONSTRAINT_CATALOG
+					schema.Name.O,                          // CONSTRAINT_SCHEMA
+					constr.Name.O,                          // CONSTRAINT_NAME
+					fmt.Sprintf("(%s)", constr.ExprString), // CHECK_CLAUSE
+				)
+				rows = append(rows, record)
+			}
 		}
 	}
 	e.rows = rows
@@ -2004,6 +2061,39 @@ func (e *memtableRetriever) setDataForStatementsSummaryEvicted(ctx sessionctx.Co
 	return nil
 }
 
+func (e *memtableRetriever) setDataForInstancePlanCache(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	entries := plannercore.GetInstancePlanCacheEntries(ctx)
+	rows := make([][]types.Datum, 0, len(entries))
+	for _, entry := range entries {
+		hitRate := float64(entry.HitCount) / float64(entry.HitCount+entry.MissCount)
+		createTime := types.NewTime(types.FromGoTime(entry.CreateTime.In(ctx.GetSessionVars().Location())), mysql.TypeTimestamp, types.MaxFsp)
+		var lastHitTime interface{}
+		if !entry.LastHitTime.IsZero() {
+			lastHitTime = types.NewTime(types.FromGoTime(entry.LastHitTime.In(ctx.GetSessionVars().Location())), mysql.TypeTimestamp, types.MaxFsp)
+		}
+		row := types.MakeDatums(
+			entry.SQLDigest,     // SQL_DIGEST
+			entry.SQLText,       // SQL_TEXT
+			entry.SchemaName,    // SCHEMA_NAME
+			entry.SchemaVersion, // SCHEMA_VERSION
+			entry.ParamTypes,    // PARAM_TYPES
+			entry.PlanDigest,    // PLAN_DIGEST
+			entry.MemBytes,      // MEM_BYTES
+			entry.HitCount,      // HIT_COUNT
+			entry.MissCount,     // MISS_COUNT
+			hitRate,             // HIT_RATE
+			createTime,          // CREATE_TIME
+			lastHitTime,         // LAST_HIT_TIME
+		)
+		rows = append(rows, row)
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) setDataForClientErrorsSummary(ctx sessionctx.Context, tableName string) error {
 	// Seeing client errors should require the PROCESS privilege, with the exception of errors for your own user.
 	// This is similar to information_schema.processlist, which is the closest comparison.
@@ -2103,7 +2193,7 @@ func (e *stmtSummaryTableRetriever) retrieve(ctx context.Context, sctx sessionct
 	user := sctx.GetSessionVars().User
 	reader := stmtsummary.NewStmtSummaryReader(user, hasPriv(sctx, mysql.ProcessPriv), e.columns, instanceAddr)
 	if e.extractor.Enable {
-		checker := stmtsummary.NewStmtSummaryChecker(e.extractor.Digests)
+		checker := stmtsummary.NewStmtSummaryChecker(e.extractor.Digests, e.extractor.SchemaNames)
 		reader.SetChecker(checker)
 	}
 	var rows [][]types.Datum
@@ -2113,10 +2203,16 @@ func (e *stmtSummaryTableRetriever) retrieve(ctx context.Context, sctx sessionct
 		rows = reader.GetStmtSummaryCurrentRows()
 	case infoschema.TableStatementsSummaryHistory,
 		infoschema.ClusterTableStatementsSummaryHistory:
-		rows = reader.GetStmtSummaryHistoryRows()
+		reader.SetTimeRange(e.extractor.BeginTimeRange.StartTime, e.extractor.BeginTimeRange.EndTime,
+			e.extractor.EndTimeRange.StartTime, e.extractor.EndTimeRange.EndTime)
+		if reader.PersistenceEnabled() {
+			rows, err = reader.GetPersistedStmtSummaryHistoryRows(ctx)
+		} else {
+			rows = reader.GetStmtSummaryHistoryRows()
+		}
 	}
 
-	return rows, nil
+	return rows, err
 }
 
 // tidbTrxTableRetriever is the memtable retriever for the TIDB_TRX and CLUSTER_TIDB_TRX table.
@@ -162,7 +162,14 @@ func prefetchConflictedOldRows(ctx context.Context, txn kv.Transaction, rows []t
 				if err != nil {
 					return err
 				}
-				batchKeys = append(batchKeys, tablecodec.EncodeRecordKey(r.t.RecordPrefix(), handle))
+				t, err := r.getDupRowTable(uk, val)
+				if err != nil {
+					return err
+				}
+				if t == nil {
+					continue
+				}
+				batchKeys = append(batchKeys, tablecodec.EncodeRecordKey(t.RecordPrefix(), handle))
 			}
 		}
 	}
@@ -189,8 +196,9 @@ func (e *InsertValues) prefetchDataCache(ctx context.Context, txn kv.Transaction
 }
 
 // updateDupRow updates a duplicate row to a new row.
-func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Transaction, row toBeCheckedRow, handle kv.Handle, onDuplicate []*expression.Assignment) error {
-	oldRow, err := getOldRow(ctx, e.ctx, txn, row.t, handle, e.GenExprs)
+// t is the table or partition the duplicate row is in.
+func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Transaction, row toBeCheckedRow, t table.Table, handle kv.Handle, onDuplicate []*expression.Assignment) error {
+	oldRow, err := getOldRow(ctx, e.ctx, txn, t, handle, e.GenExprs)
 	if err != nil {
 		return err
 	}
@@ -201,7 +209,7 @@ func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Tr
 	}
 
 	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
-	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
+	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && (kv.ErrKeyExists.Equal(err) || table.ErrCheckConstraintViolated.Equal(err)) {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 		return nil
 	}
@@ -245,7 +253,7 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 				return err
 			}
 
-			err = e.updateDupRow(ctx, i, txn, r, handle, e.OnDuplicate)
+			err = e.updateDupRow(ctx, i, txn, r, r.t, handle, e.OnDuplicate)
 			if err == nil {
 				continue
 			}
@@ -266,8 +274,15 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 			if err != nil {
 				return err
 			}
+			t, err := r.getDupRowTable(uk, val)
+			if err != nil {
+				return err
+			}
+			if t == nil {
+				continue
+			}
 
-			err = e.updateDupRow(ctx, i, txn, r, handle, e.OnDuplicate)
+			err = e.updateDupRow(ctx, i, txn, r, t, handle, e.OnDuplicate)
 			if err != nil {
 				if kv.IsErrNotFound(err) {
 					// Data index inconsistent? A unique key provide the handle information, but the
@@ -291,7 +306,10 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 		if newRows[i] != nil {
 			err := e.addRecord(ctx, newRows[i])
 			if err != nil {
-				return err
+				if !e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning || !isIgnorableConstraintErr(err) {
+					return err
+				}
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 			}
 		}
 	}
@@ -406,10 +424,13 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
-	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
+	changed, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
 	if err != nil {
 		return err
 	}
+	if e.fkTriggers != nil && changed {
+		return e.fkTriggers.onUpdateRow(ctx, oldRow, newData)
+	}
 	return nil
 }
 
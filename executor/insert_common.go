@@ -87,6 +87,10 @@ type InsertValues struct {
 
 	stats *InsertRuntimeStat
 
+	// fkTriggers checks the foreign key constraints on the inserted rows. For REPLACE and
+	// INSERT ... ON DUPLICATE KEY UPDATE, it also checks or cascades the replaced or updated rows.
+	fkTriggers *fkTriggerExecs
+
 	// isLoadData indicates whatever current goroutine is use for generating batch data. LoadData use two goroutines. One for generate batch data,
 	// The other one for commit task, which will invalid txn.
 	// We use mutex to protect routine from using invalid txn.
@@ -1095,7 +1099,18 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 			}
 		}
 		for _, uk := range r.uniqueKeys {
-			_, err := txn.Get(ctx, uk.newKey)
+			val, err := txn.Get(ctx, uk.newKey)
+			if err == nil && uk.global {
+				var t table.Table
+				t, err = r.getDupRowTable(uk, val)
+				if err != nil {
+					return err
+				}
+				if t == nil {
+					// The stale entry of a global index is not a duplicate key.
+					continue
+				}
+			}
 			if err == nil {
 				// If duplicate keys were found in BatchGet, mark row = nil.
 				e.ctx.GetSessionVars().StmtCtx.AppendWarning(uk.dupErr)
@@ -1111,11 +1126,16 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 		// it should be add to values map for the further row check.
 		// There may be duplicate keys inside the insert statement.
 		if !skip {
-			e.ctx.GetSessionVars().StmtCtx.AddCopiedRows(1)
 			err = addRecord(ctx, rows[i])
 			if err != nil {
-				return err
+				if !isIgnorableConstraintErr(err) {
+					return err
+				}
+				// The row violating the foreign key or check constraints is skipped with a warning.
+				e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+				continue
 			}
+			e.ctx.GetSessionVars().StmtCtx.AddCopiedRows(1)
 		}
 	}
 	if e.stats != nil {
@@ -1129,6 +1149,32 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 }
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
+	vars := e.ctx.GetSessionVars()
+	if e.fkTriggers != nil && vars.StmtCtx.DupKeyAsWarning {
+		// For `INSERT IGNORE`, the row violating the foreign key constraints is skipped with a warning,
+		// so the row is added in a staging buffer which is discarded if the check fails.
+		txn, err := e.ctx.Txn(true)
+		if err != nil {
+			return err
+		}
+		memBuffer := txn.GetMemBuffer()
+		sh := memBuffer.Staging()
+		defer memBuffer.Cleanup(sh)
+		if err = e.doAddRecord(ctx, row, reserveAutoIDCount); err != nil {
+			return err
+		}
+		memBuffer.Release(sh)
+	} else if err = e.doAddRecord(ctx, row, reserveAutoIDCount); err != nil {
+		return err
+	}
+	vars.StmtCtx.AddAffectedRows(1)
+	if e.lastInsertID != 0 {
+		vars.SetLastInsertID(e.lastInsertID)
+	}
+	return nil
+}
+
+func (e *InsertValues) doAddRecord(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
@@ -1142,9 +1188,8 @@ func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.
 	if err != nil {
 		return err
 	}
-	vars.StmtCtx.AddAffectedRows(1)
-	if e.lastInsertID != 0 {
-		vars.SetLastInsertID(e.lastInsertID)
+	if e.fkTriggers != nil {
+		return e.fkTriggers.onAddRow(ctx, row)
 	}
 	return nil
 }
@@ -721,7 +721,10 @@ func (e *LoadDataInfo) addRecordLD(ctx context.Context, row []types.Datum) error
 	}
 	err := e.addRecord(ctx, row)
 	if err != nil {
-		e.handleWarning(err)
+		// The constraint violations are turned into warnings by batchCheckAndInsert.
+		if !isIgnorableConstraintErr(err) {
+			e.handleWarning(err)
+		}
 		return err
 	}
 	return nil
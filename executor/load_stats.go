@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/util/chunk"
@@ -89,3 +90,35 @@ func (e *LoadStatsInfo) Update(data []byte) error {
 	}
 	return h.LoadStatsFromJSON(e.Ctx.GetInfoSchema().(infoschema.InfoSchema), jsonTbl)
 }
+
+var _ Executor = &RestoreStatsExec{}
+
+// RestoreStatsExec represents a restore statistic executor, it restores the stats of the table
+// from the historical stats in mysql.stats_history.
+type RestoreStatsExec struct {
+	baseExecutor
+	table   *ast.TableName
+	version uint64
+	done    bool
+}
+
+// Next implements the Executor Next interface.
+func (e *RestoreStatsExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	do := domain.GetDomain(e.ctx)
+	h := do.StatsHandle()
+	if h == nil {
+		return errors.New("Restore Stats: handle is nil")
+	}
+	is := e.ctx.GetInfoSchema().(infoschema.InfoSchema)
+	version, err := h.RestoreHistoricalStats(is, e.table.Schema.O, e.table.TableInfo, e.version)
+	if err != nil {
+		return err
+	}
+	e.ctx.GetSessionVars().StmtCtx.AppendNote(errors.Errorf("Restore Stats: the stats of version %d are restored", version))
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -63,9 +64,10 @@ func (e *ReplaceExec) Open(ctx context.Context) error {
 
 // removeRow removes the duplicate row and cleanup its keys in the key-value map,
 // but if the to-be-removed row equals to the to-be-added row, no remove or add things to do.
-func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle kv.Handle, r toBeCheckedRow) (bool, error) {
+// t is the table or partition the duplicate row is in.
+func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, t table.Table, handle kv.Handle, r toBeCheckedRow) (bool, error) {
 	newRow := r.row
-	oldRow, err := getOldRow(ctx, e.ctx, txn, r.t, handle, e.GenExprs)
+	oldRow, err := getOldRow(ctx, e.ctx, txn, t, handle, e.GenExprs)
 	if err != nil {
 		logutil.BgLogger().Error("get old row failed when replace",
 			zap.String("handle", handle.String()),
@@ -85,10 +87,15 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 		return true, nil
 	}
 
-	err = r.t.RemoveRecord(e.ctx, handle, oldRow)
+	err = t.RemoveRecord(e.ctx, handle, oldRow)
 	if err != nil {
 		return false, err
 	}
+	if e.fkTriggers != nil {
+		if err = e.fkTriggers.onRemoveRow(ctx, oldRow); err != nil {
+			return false, err
+		}
+	}
 	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return false, nil
 }
@@ -124,7 +131,7 @@ func (e *ReplaceExec) replaceRow(ctx context.Context, r toBeCheckedRow) error {
 		}
 
 		if _, err := txn.Get(ctx, r.handleKey.newKey); err == nil {
-			rowUnchanged, err := e.removeRow(ctx, txn, handle, r)
+			rowUnchanged, err := e.removeRow(ctx, txn, r.t, handle, r)
 			if err != nil {
 				return err
 			}
@@ -180,7 +187,14 @@ func (e *ReplaceExec) removeIndexRow(ctx context.Context, txn kv.Transaction, r
 		if err != nil {
 			return false, true, err
 		}
-		rowUnchanged, err := e.removeRow(ctx, txn, handle, r)
+		t, err := r.getDupRowTable(uk, val)
+		if err != nil {
+			return false, true, err
+		}
+		if t == nil {
+			continue
+		}
+		rowUnchanged, err := e.removeRow(ctx, txn, t, handle, r)
 		if err != nil {
 			return false, true, err
 		}
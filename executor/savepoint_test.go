@@ -0,0 +1,240 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavepoint(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int, unique key uk (v))")
+
+	for _, txnMode := range []string{"optimistic", "pessimistic"} {
+		tk.MustExec("truncate table t")
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("insert into t values (1, 1)")
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (2, 2)")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("update t set v = 10 where id = 1")
+		tk.MustExec("rollback to savepoint s2")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2"))
+		tk.MustExec("insert into t values (3, 3)")
+		tk.MustExec("rollback to s1")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1"))
+		// The later savepoints are removed by rolling back to an earlier savepoint.
+		tk.MustGetErrCode("rollback to s2", errno.ErrSpDoesNotExist)
+		// The savepoint is kept after rolling back to it.
+		tk.MustExec("insert into t values (2, 2)")
+		tk.MustExec("rollback to s1")
+		tk.MustExec("insert into t values (4, 2)")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "4 2"))
+		tk.MustExec("admin check table t")
+	}
+
+	// The existing rows are still checked after rolling back the changes on them.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("update t set v = 5 where id = 4")
+	tk.MustExec("delete from t where id = 1")
+	tk.MustExec("rollback to s1")
+	tk.MustGetErrCode("insert into t values (4, 6)", errno.ErrDupEntry)
+	tk.MustGetErrCode("insert into t values (5, 1)", errno.ErrDupEntry)
+	tk.MustExec("insert into t values (5, 5)")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "4 2", "5 5"))
+	tk.MustExec("admin check table t")
+}
+
+func TestSavepointAtTxnStart(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+
+	// All the changes are made after the savepoint.
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+
+	tk.MustExec("set autocommit = 0")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (2)")
+	tk.MustExec("rollback to s1")
+	tk.MustExec("insert into t values (3)")
+	tk.MustExec("commit")
+	tk.MustExec("set autocommit = 1")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1", "3"))
+
+	// The savepoint outside a transaction is ignored.
+	tk.MustExec("savepoint s1")
+	tk.MustGetErrCode("rollback to s1", errno.ErrSpDoesNotExist)
+	tk.MustGetErrCode("release savepoint s1", errno.ErrSpDoesNotExist)
+}
+
+func TestReleaseSavepoint(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (2)")
+	tk.MustExec("savepoint S2")
+	tk.MustExec("insert into t values (3)")
+	tk.MustExec("release savepoint s1")
+	tk.MustGetErrCode("rollback to s1", errno.ErrSpDoesNotExist)
+	tk.MustGetErrCode("rollback to s2", errno.ErrSpDoesNotExist)
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1", "2", "3"))
+
+	// The savepoint names are case insensitive, and the old savepoint is replaced by the new one with the same name.
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (4)")
+	tk.MustExec("savepoint s2")
+	tk.MustExec("insert into t values (5)")
+	tk.MustExec("savepoint S1")
+	tk.MustExec("insert into t values (6)")
+	tk.MustExec("rollback to s2")
+	tk.MustGetErrCode("release savepoint s1", errno.ErrSpDoesNotExist)
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1", "2", "3", "4"))
+}
+
+func TestSavepointTableDelta(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (2), (3)")
+	deltaCount := func() int64 {
+		var count int64
+		for _, delta := range tk.Session().GetSessionVars().TxnCtx.TableDeltaMap {
+			count += delta.Count
+		}
+		return count
+	}
+	require.Equal(t, int64(3), deltaCount())
+	tk.MustExec("rollback to s1")
+	require.Equal(t, int64(1), deltaCount())
+	tk.MustExec("commit")
+}
+
+func TestSavepointReuseName(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+
+	for _, txnMode := range []string{"optimistic", "pessimistic"} {
+		tk.MustExec("truncate table t")
+		tk.MustExec("begin " + txnMode)
+		tk.MustExec("insert into t values (1, 1)")
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (2, 2)")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("update t set v = 10 where id = 1")
+		// Redeclare the savepoint before s2, s2 is kept.
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (3, 3)")
+		tk.MustExec("rollback to s1")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 10", "2 2"))
+		tk.MustExec("update t set v = 20 where id = 2")
+		tk.MustExec("rollback to s2")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2"))
+		tk.MustGetErrCode("rollback to s1", errno.ErrSpDoesNotExist)
+
+		// Redeclare the latest savepoint.
+		tk.MustExec("savepoint s2")
+		tk.MustExec("insert into t values (4, 4)")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("insert into t values (5, 5)")
+		tk.MustExec("rollback to s2")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "4 4"))
+		tk.MustExec("release savepoint s2")
+		tk.MustGetErrCode("rollback to s2", errno.ErrSpDoesNotExist)
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 1", "2 2", "4 4"))
+		tk.MustExec("admin check table t")
+	}
+}
+
+func TestSavepointPessimisticLockCache(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from t where id = 1 for update").Check(testkit.Rows("1 1"))
+	tk.MustExec("savepoint s1")
+	tk.MustQuery("select * from t where id = 2 for update").Check(testkit.Rows("2 2"))
+	tk.MustExec("rollback to s1")
+	// The lock acquired after the savepoint is kept, but its cached value is dropped.
+	tk2.MustExec("begin pessimistic")
+	tk2.MustGetErrCode("select * from t where id = 2 for update nowait", errno.ErrLockAcquireFailAndNoWaitSet)
+	tk2.MustExec("rollback")
+	txnCtx := tk.Session().GetSessionVars().TxnCtx
+	cacheHit := txnCtx.PessimisticCacheHit
+	tk.MustQuery("select * from t where id = 1 for update").Check(testkit.Rows("1 1"))
+	require.Equal(t, cacheHit+1, txnCtx.PessimisticCacheHit)
+	tk.MustQuery("select * from t where id = 2 for update").Check(testkit.Rows("2 2"))
+	require.Equal(t, cacheHit+1, txnCtx.PessimisticCacheHit)
+	tk.MustExec("commit")
+}
+
+func TestSavepointRowCount(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key)")
+
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (2), (3)")
+	require.Equal(t, uint64(2), tk.Session().AffectedRows())
+	tk.MustExec("rollback to s1")
+	require.Equal(t, uint64(0), tk.Session().AffectedRows())
+	tk.MustQuery("select row_count()").Check(testkit.Rows("0"))
+	tk.MustExec("delete from t")
+	tk.MustQuery("select row_count()").Check(testkit.Rows("1"))
+	tk.MustExec("commit")
+}
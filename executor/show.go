@@ -986,16 +986,27 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
-	// Foreign Keys are supported by data dictionary even though
-	// they are not enforced by DDL. This is still helpful to applications.
+	// Foreign Keys are supported by data dictionary, and they are
+	// enforced by DML when foreign_key_checks is enabled.
+	var dbInfo *model.DBInfo
+	if is, ok := ctx.GetInfoSchema().(infoschema.InfoSchema); ok && len(tableInfo.ForeignKeys) > 0 {
+		dbInfo, _ = is.SchemaByTable(tableInfo)
+	}
 	for _, fk := range tableInfo.ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
 		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s FOREIGN KEY ", stringutil.Escape(fk.Name.O, sqlMode)))
 		colNames := make([]string, 0, len(fk.Cols))
 		for _, col := range fk.Cols {
 			colNames = append(colNames, stringutil.Escape(col.O, sqlMode))
 		}
 		buf.WriteString(fmt.Sprintf("(%s)", strings.Join(colNames, ",")))
-		buf.WriteString(fmt.Sprintf(" REFERENCES %s ", stringutil.Escape(fk.RefTable.O, sqlMode)))
+		refTable := stringutil.Escape(fk.RefTable.O, sqlMode)
+		if fk.RefSchema.L != "" && dbInfo != nil && dbInfo.Name.L != fk.RefSchema.L {
+			refTable = stringutil.Escape(fk.RefSchema.O, sqlMode) + "." + refTable
+		}
+		buf.WriteString(fmt.Sprintf(" REFERENCES %s ", refTable))
 		refColNames := make([]string, 0, len(fk.Cols))
 		for _, refCol := range fk.RefCols {
 			refColNames = append(refColNames, stringutil.Escape(refCol.O, sqlMode))
@@ -1009,6 +1020,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constr := range tableInfo.Constraints {
+		if constr.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constr.Name.O, sqlMode), constr.ExprString))
+		if !constr.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	buf.WriteString(") ENGINE=InnoDB")
@@ -1085,11 +1106,27 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 
 	// add direct placement info here
 	appendDirectPlacementInfo(tableInfo.DirectPlacementOpts, buf)
+	// add ttl info here.
+	appendTTLInfo(tableInfo.TTLInfo, buf, sqlMode)
 	// add partition info here.
 	appendPartitionInfo(tableInfo.Partition, buf, sqlMode)
 	return nil
 }
 
+func appendTTLInfo(ttlInfo *model.TTLInfo, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
+	if ttlInfo == nil {
+		return
+	}
+	fmt.Fprintf(buf, " /*T![ttl] TTL=%s + INTERVAL %s %s */", stringutil.Escape(ttlInfo.ColumnName.O, sqlMode),
+		ttlInfo.IntervalExprStr, ast.TimeUnitType(ttlInfo.IntervalTimeUnit).String())
+	if ttlInfo.Enable {
+		fmt.Fprintf(buf, " /*T![ttl] TTL_ENABLE='ON' */")
+	} else {
+		fmt.Fprintf(buf, " /*T![ttl] TTL_ENABLE='OFF' */")
+	}
+	fmt.Fprintf(buf, " /*T![ttl] TTL_JOB_INTERVAL='%s' */", format.OutputFormat(ttlInfo.JobInterval))
+}
+
 // ConstructResultOfShowCreateSequence constructs the result for show create sequence.
 func ConstructResultOfShowCreateSequence(ctx sessionctx.Context, tableInfo *model.TableInfo, buf *bytes.Buffer) {
 	sqlMode := ctx.GetSessionVars().SQLMode
@@ -1240,15 +1277,50 @@ func appendDirectPlacementInfo(directPlacementOpts *model.PlacementSettings, buf
 	fmt.Fprintf(buf, " */")
 }
 
+func keyPartitionColumns(partitionInfo *model.PartitionInfo, sqlMode mysql.SQLMode) string {
+	cols := make([]string, 0, len(partitionInfo.Columns))
+	for _, col := range partitionInfo.Columns {
+		cols = append(cols, stringutil.Escape(col.O, sqlMode))
+	}
+	return strings.Join(cols, ",")
+}
+
+// appendPartitionInterval appends the INTERVAL config of a RANGE partitioned table. The partitions
+// generated by FIRST and LAST PARTITION are shown as the partition definitions.
+func appendPartitionInterval(interval *model.PartitionIntervalInfo, buf *bytes.Buffer) {
+	if interval == nil {
+		return
+	}
+	fmt.Fprintf(buf, " /*T![interval_partition] INTERVAL (%s", interval.IntervalExprStr)
+	if unit := ast.TimeUnitType(interval.IntervalTimeUnit); unit != ast.TimeUnitInvalid {
+		fmt.Fprintf(buf, " %s", unit.String())
+	}
+	buf.WriteString(")")
+	if interval.NullPart {
+		buf.WriteString(" NULL PARTITION")
+	}
+	if interval.MaxValPart {
+		buf.WriteString(" MAXVALUE PARTITION")
+	}
+	if interval.Precreate > 0 {
+		fmt.Fprintf(buf, " PRECREATE %d", interval.Precreate)
+	}
+	if interval.Retention > 0 {
+		fmt.Fprintf(buf, " RETENTION %d", interval.Retention)
+	}
+	buf.WriteString(" */")
+}
+
 func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
-	if partitionInfo == nil {
+	// The table being converted from or to a non-partitioned table is shown as the non-partitioned one.
+	if partitionInfo == nil || partitionInfo.Type == model.PartitionTypeNone {
 		return
 	}
 	// Since MySQL 5.1/5.5 is very old and TiDB aims for 5.7/8.0 compatibility, we will not
 	// include the /*!50100 or /*!50500 comments for TiDB.
 	// This also solves the issue with comments within comments that would happen for
 	// PLACEMENT POLICY options.
-	if partitionInfo.Type == model.PartitionTypeHash {
+	if partitionInfo.Type == model.PartitionTypeHash || partitionInfo.Type == model.PartitionTypeKey {
 		defaultPartitionDefinitions := true
 		for i, def := range partitionInfo.Definitions {
 			if def.Name.O != fmt.Sprintf("p%d", i) {
@@ -1262,12 +1334,18 @@ func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer,
 		}
 
 		if defaultPartitionDefinitions {
-			fmt.Fprintf(buf, "\nPARTITION BY HASH (%s) PARTITIONS %d", partitionInfo.Expr, partitionInfo.Num)
+			if partitionInfo.Type == model.PartitionTypeKey {
+				fmt.Fprintf(buf, "\nPARTITION BY KEY (%s) PARTITIONS %d", keyPartitionColumns(partitionInfo, sqlMode), partitionInfo.Num)
+			} else {
+				fmt.Fprintf(buf, "\nPARTITION BY HASH (%s) PARTITIONS %d", partitionInfo.Expr, partitionInfo.Num)
+			}
 			return
 		}
 	}
-	// this if statement takes care of lists/range columns case
-	if partitionInfo.Columns != nil {
+	if partitionInfo.Type == model.PartitionTypeKey {
+		fmt.Fprintf(buf, "\nPARTITION BY KEY (%s)\n(", keyPartitionColumns(partitionInfo, sqlMode))
+	} else if partitionInfo.Columns != nil {
+		// this if statement takes care of lists/range columns case
 		// partitionInfo.Type == model.PartitionTypeRange || partitionInfo.Type == model.PartitionTypeList
 		// Notice that MySQL uses two spaces between LIST and COLUMNS...
 		fmt.Fprintf(buf, "\nPARTITION BY %s COLUMNS(", partitionInfo.Type.String())
@@ -1277,9 +1355,13 @@ func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer,
 				buf.WriteString(",")
 			}
 		}
-		buf.WriteString(")\n(")
+		buf.WriteString(")")
+		appendPartitionInterval(partitionInfo.Interval, buf)
+		buf.WriteString("\n(")
 	} else {
-		fmt.Fprintf(buf, "\nPARTITION BY %s (%s)\n(", partitionInfo.Type.String(), partitionInfo.Expr)
+		fmt.Fprintf(buf, "\nPARTITION BY %s (%s)", partitionInfo.Type.String(), partitionInfo.Expr)
+		appendPartitionInterval(partitionInfo.Interval, buf)
+		buf.WriteString("\n(")
 	}
 
 	for i, def := range partitionInfo.Definitions {
@@ -134,7 +134,11 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	case *ast.CommitStmt:
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
-		err = e.executeRollback(x)
+		err = e.executeRollback(ctx, x)
+	case *ast.SavepointStmt:
+		err = e.executeSavepoint(x)
+	case *ast.ReleaseSavepointStmt:
+		err = e.executeReleaseSavepoint(x)
 	case *ast.CreateUserStmt:
 		err = e.executeCreateUser(ctx, x)
 	case *ast.AlterUserStmt:
@@ -152,6 +156,10 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		return nil
 	case *ast.DropStatsStmt:
 		err = e.executeDropStats(x)
+	case *ast.LockStatsStmt:
+		err = e.executeLockStats(x)
+	case *ast.UnlockStatsStmt:
+		err = e.executeUnlockStats(x)
 	case *ast.SetRoleStmt:
 		err = e.executeSetRole(x)
 	case *ast.RevokeRoleStmt:
@@ -730,7 +738,10 @@ func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
 	e.ctx.GetSessionVars().SetInTxn(false)
 }
 
-func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
+func (e *SimpleExec) executeRollback(ctx context.Context, s *ast.RollbackStmt) error {
+	if s.SavepointName != "" {
+		return e.executeRollbackToSavepoint(ctx, s)
+	}
 	sessVars := e.ctx.GetSessionVars()
 	logutil.BgLogger().Debug("execute rollback statement", zap.Uint64("conn", sessVars.ConnectionID))
 	sessVars.SetInTxn(false)
@@ -751,6 +762,88 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// savepointTxn is the transaction which supports savepoints.
+type savepointTxn interface {
+	AddSavepoint() (kv.SavepointHandle, error)
+	RollbackToSavepoint(ctx context.Context, h kv.SavepointHandle) error
+	ReleaseSavepoint(h kv.SavepointHandle) error
+	DeleteSavepoint(h kv.SavepointHandle) error
+}
+
+func (e *SimpleExec) getSavepointTxn() (savepointTxn, error) {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	spTxn, ok := txn.(savepointTxn)
+	if !ok {
+		return nil, errors.Trace(kv.ErrSavepointNotSupported)
+	}
+	return spTxn, nil
+}
+
+func (e *SimpleExec) executeSavepoint(s *ast.SavepointStmt) error {
+	sessVars := e.ctx.GetSessionVars()
+	// Like MySQL, the savepoint outside a transaction is ignored.
+	if !sessVars.InTxn() && sessVars.IsAutocommit() {
+		return nil
+	}
+	if sessVars.BinlogClient != nil {
+		return ErrSavepointNotSupportedWithBinlog
+	}
+	spTxn, err := e.getSavepointTxn()
+	if err != nil {
+		return err
+	}
+	// The old savepoint with the same name is replaced, its states in the transaction are dropped first,
+	// otherwise they keep recording the modified keys until the transaction ends.
+	if _, old := sessVars.TxnCtx.GetSavepoint(s.Name); old != nil {
+		if err := spTxn.DeleteSavepoint(old.Handle); err != nil {
+			return err
+		}
+	}
+	h, err := spTxn.AddSavepoint()
+	if err != nil {
+		return err
+	}
+	sessVars.TxnCtx.AddSavepoint(s.Name, h)
+	return nil
+}
+
+func (e *SimpleExec) executeRollbackToSavepoint(ctx context.Context, s *ast.RollbackStmt) error {
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	idx, sp := txnCtx.GetSavepoint(s.SavepointName)
+	if sp == nil {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", s.SavepointName)
+	}
+	spTxn, err := e.getSavepointTxn()
+	if err != nil {
+		return err
+	}
+	if err := spTxn.RollbackToSavepoint(ctx, sp.Handle); err != nil {
+		return err
+	}
+	txnCtx.RollbackToSavepoint(idx)
+	return nil
+}
+
+func (e *SimpleExec) executeReleaseSavepoint(s *ast.ReleaseSavepointStmt) error {
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	idx, sp := txnCtx.GetSavepoint(s.Name)
+	if sp == nil {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", s.Name)
+	}
+	spTxn, err := e.getSavepointTxn()
+	if err != nil {
+		return err
+	}
+	if err := spTxn.ReleaseSavepoint(sp.Handle); err != nil {
+		return err
+	}
+	txnCtx.ReleaseSavepoint(idx)
+	return nil
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -1611,6 +1704,24 @@ func (e *SimpleExec) executeDropStats(s *ast.DropStatsStmt) (err error) {
 	return h.Update(e.ctx.GetInfoSchema().(infoschema.InfoSchema))
 }
 
+func (e *SimpleExec) executeLockStats(s *ast.LockStatsStmt) error {
+	h := domain.GetDomain(e.ctx).StatsHandle()
+	tids := make([]int64, 0, len(s.Tables))
+	for _, tbl := range s.Tables {
+		tids = append(tids, tbl.TableInfo.ID)
+	}
+	return h.AddLockedTables(tids)
+}
+
+func (e *SimpleExec) executeUnlockStats(s *ast.UnlockStatsStmt) error {
+	h := domain.GetDomain(e.ctx).StatsHandle()
+	tids := make([]int64, 0, len(s.Tables))
+	for _, tbl := range s.Tables {
+		tids = append(tids, tbl.TableInfo.ID)
+	}
+	return h.RemoveLockedTables(tids)
+}
+
 func (e *SimpleExec) autoNewTxn() bool {
 	switch e.Statement.(type) {
 	case *ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt:
@@ -1697,6 +1808,7 @@ func (e *SimpleExec) executeAdminFlushPlanCache(s *ast.AdminStmt) error {
 		// Record the timestamp. When other sessions want to use the plan cache,
 		// it will check the timestamp first to decide whether the plan cache should be flushed.
 		domain.GetDomain(e.ctx).SetExpiredTimeStamp4PC(now)
+		domain.GetDomain(e.ctx).InstancePlanCache().DeleteAll()
 	}
 	return nil
 }
@@ -62,6 +62,8 @@ type UpdateExec struct {
 	memTracker                *memory.Tracker
 
 	stats *updateRuntimeStats
+	// fkTriggers checks the foreign key constraints on the updated rows of each table.
+	fkTriggers map[int64]*fkTriggerExecs
 
 	handles        []kv.Handle
 	tableUpdatable []bool
@@ -192,11 +194,16 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
 		if err1 == nil {
 			e.updatedRowKeys[content.Start].Set(handle, changed)
+			if triggers := e.fkTriggers[content.TblID]; triggers != nil && changed {
+				if err := triggers.onUpdateRow(ctx, oldData, newTableData); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
 		sc := e.ctx.GetSessionVars().StmtCtx
-		if kv.ErrKeyExists.Equal(err1) && sc.DupKeyAsWarning {
+		if (kv.ErrKeyExists.Equal(err1) || table.ErrCheckConstraintViolated.Equal(err1)) && sc.DupKeyAsWarning {
 			sc.AppendWarning(err1)
 			continue
 		}
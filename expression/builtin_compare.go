@@ -1575,7 +1575,12 @@ func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Express
 	if MaybeOverOptimized4PlanCache(ctx, args) {
 		// To keep the result be compatible with MySQL, refine `int non-constant <cmp> str constant`
 		// here and skip this refine operation in all other cases for safety.
-		if (arg0IsInt && !arg0IsCon && arg1IsString && arg1IsCon) || (arg1IsInt && !arg1IsCon && arg0IsString && arg0IsCon) {
+		// The parameters of the non-prepared plan cache are the literals of the query, so the plans comparing int
+		// columns with non-int literals aren't cached, and the literals are refined as the queries without cache.
+		// Otherwise the literals, such as 2.5, would be rounded when the ranges are rebuilt.
+		nonPrepared := ctx.GetSessionVars().StmtCtx.InNonPreparedPlanBuilding
+		if (arg0IsInt && !arg0IsCon && (arg1IsString || (nonPrepared && !arg1IsInt)) && arg1IsCon) ||
+			(arg1IsInt && !arg1IsCon && (arg0IsString || (nonPrepared && !arg0IsInt)) && arg0IsCon) {
 			ctx.GetSessionVars().StmtCtx.SkipPlanCache = true
 			RemoveMutableConst(ctx, args)
 		} else {
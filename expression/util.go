@@ -114,6 +114,31 @@ func extractDependentColumns(result []*Column, expr Expression) []*Column {
 	return result
 }
 
+// CloneWithNewCtx clones the expression and binds the cloned functions and parameter markers to the new context,
+// so the expression built in one session can be evaluated in another session.
+func CloneWithNewCtx(ctx sessionctx.Context, expr Expression) Expression {
+	cloned := expr.Clone()
+	bindCtx(ctx, cloned)
+	return cloned
+}
+
+func bindCtx(ctx sessionctx.Context, expr Expression) {
+	switch x := expr.(type) {
+	case *ScalarFunction:
+		x.Function.setCtx(ctx)
+		for _, arg := range x.GetArgs() {
+			bindCtx(ctx, arg)
+		}
+	case *Constant:
+		if x.ParamMarker != nil {
+			x.ParamMarker = &ParamMarker{ctx: ctx, order: x.ParamMarker.order}
+		}
+		if x.DeferredExpr != nil {
+			x.DeferredExpr = CloneWithNewCtx(ctx, x.DeferredExpr)
+		}
+	}
+}
+
 // ExtractColumns extracts all columns from an expression.
 func ExtractColumns(expr Expression) []*Column {
 	// Pre-allocate a slice to reduce allocation, 8 doesn't have special meaning.
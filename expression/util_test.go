@@ -158,6 +158,26 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneWithNewCtx(t *testing.T) {
+	ctx1, ctx2 := mock.NewContext(), mock.NewContext()
+	ctx1.GetSessionVars().PreparedParams = []types.Datum{types.NewIntDatum(1)}
+	ctx2.GetSessionVars().PreparedParams = []types.Datum{types.NewIntDatum(2)}
+	param := &Constant{ParamMarker: &ParamMarker{ctx: ctx1, order: 0}, RetType: newIntFieldType()}
+	expr, err := NewFunctionBase(ctx1, ast.Plus, newIntFieldType(), param, NewOne())
+	require.NoError(t, err)
+
+	cloned := CloneWithNewCtx(ctx2, expr)
+	require.Same(t, ctx2, cloned.(*ScalarFunction).Function.getCtx())
+	require.Same(t, ctx1, expr.(*ScalarFunction).Function.getCtx())
+	val, isNull, err := cloned.EvalInt(ctx2, chunk.Row{})
+	require.NoError(t, err)
+	require.False(t, isNull)
+	require.Equal(t, int64(3), val)
+	val, _, err = expr.EvalInt(ctx1, chunk.Row{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), val)
+}
+
 func TestGetUint64FromConstant(t *testing.T) {
 	con := &Constant{
 		Value: types.NewDatum(nil),
@@ -17,6 +17,7 @@ package infoschema
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/pingcap/tidb/ddl/placement"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/util"
 )
 
@@ -59,6 +61,8 @@ type InfoSchema interface {
 	RuleBundles() []*placement.Bundle
 	// AllPlacementPolicies returns all placement policies
 	AllPlacementPolicies() []*model.PolicyInfo
+	// GetTableReferredForeignKeys gets the public and write only foreign keys which refer to the table.
+	GetTableReferredForeignKeys(schema, table string) []*model.ReferredFKInfo
 }
 
 type sortedTables []table.Table
@@ -108,6 +112,16 @@ type infoSchema struct {
 
 	// schemaMetaVersion is the version of schema, and we should check version when change schema.
 	schemaMetaVersion int64
+
+	// referredFKMap maps the parent table to the foreign keys which refer to it.
+	// It's built lazily since it's only used when the foreign key checks are enabled.
+	referredFKOnce sync.Once
+	referredFKMap  map[referredTableKey][]*model.ReferredFKInfo
+}
+
+type referredTableKey struct {
+	schema string
+	table  string
 }
 
 // MockInfoSchema only serves for test.
@@ -291,6 +305,48 @@ func (is *infoSchema) SchemaTables(schema model.CIStr) (tables []table.Table) {
 	return
 }
 
+// GetTableReferredForeignKeys implements the InfoSchema interface.
+func (is *infoSchema) GetTableReferredForeignKeys(schema, table string) []*model.ReferredFKInfo {
+	is.referredFKOnce.Do(is.buildReferredFKMap)
+	return is.referredFKMap[referredTableKey{schema: strings.ToLower(schema), table: strings.ToLower(table)}]
+}
+
+func (is *infoSchema) buildReferredFKMap() {
+	is.referredFKMap = make(map[referredTableKey][]*model.ReferredFKInfo)
+	for _, v := range is.schemaMap {
+		for _, tbl := range v.tables {
+			for _, fk := range tbl.Meta().ForeignKeys {
+				if fk.State != model.StatePublic && fk.State != model.StateWriteOnly {
+					continue
+				}
+				refSchema := fk.RefSchema.L
+				if refSchema == "" {
+					refSchema = v.dbInfo.Name.L
+				}
+				key := referredTableKey{schema: refSchema, table: fk.RefTable.L}
+				is.referredFKMap[key] = append(is.referredFKMap[key], &model.ReferredFKInfo{
+					Cols:        fk.RefCols,
+					ChildSchema: v.dbInfo.Name,
+					ChildTable:  tbl.Meta().Name,
+					ChildFKName: fk.Name,
+				})
+			}
+		}
+	}
+	// Sort the foreign keys to make the order of the cascade actions stable.
+	for _, fks := range is.referredFKMap {
+		sort.Slice(fks, func(i, j int) bool {
+			if fks[i].ChildSchema.L != fks[j].ChildSchema.L {
+				return fks[i].ChildSchema.L < fks[j].ChildSchema.L
+			}
+			if fks[i].ChildTable.L != fks[j].ChildTable.L {
+				return fks[i].ChildTable.L < fks[j].ChildTable.L
+			}
+			return fks[i].ChildFKName.L < fks[j].ChildFKName.L
+		})
+	}
+}
+
 // FindTableByPartitionID finds the partition-table info by the partitionID.
 // FindTableByPartitionID will traverse all the tables to find the partitionID partition in which partition-table.
 func (is *infoSchema) FindTableByPartitionID(partitionID int64) (table.Table, *model.DBInfo, *model.PartitionDefinition) {
@@ -612,3 +668,83 @@ func (ts *TemporaryTableAttachedInfoSchema) SchemaByTable(tableInfo *model.Table
 
 	return ts.InfoSchema.SchemaByTable(tableInfo)
 }
+
+// HypoIndexAttachedInfoSchema implements InfoSchema.
+// It attaches the hypothetical indexes to the tables, so the optimizer can take them into account as if they were
+// real indexes. The hypothetical indexes never exist in the storage, and the wrapped InfoSchema is not modified.
+type HypoIndexAttachedInfoSchema struct {
+	InfoSchema
+	// HypoIndexes maps the table ID to its hypothetical indexes.
+	HypoIndexes map[int64][]*model.IndexInfo
+}
+
+// AttachHypoIndexes attaches the hypothetical indexes to is. The local temporary tables are kept as the outermost
+// layer, so they can still be detached from the returned InfoSchema.
+func AttachHypoIndexes(is InfoSchema, hypoIndexes map[int64][]*model.IndexInfo) InfoSchema {
+	if len(hypoIndexes) == 0 {
+		return is
+	}
+	if tempIS, ok := is.(*TemporaryTableAttachedInfoSchema); ok {
+		return &TemporaryTableAttachedInfoSchema{
+			InfoSchema:           AttachHypoIndexes(tempIS.InfoSchema, hypoIndexes),
+			LocalTemporaryTables: tempIS.LocalTemporaryTables,
+		}
+	}
+	return &HypoIndexAttachedInfoSchema{InfoSchema: is, HypoIndexes: hypoIndexes}
+}
+
+// TableByName implements InfoSchema.TableByName
+func (is *HypoIndexAttachedInfoSchema) TableByName(schema, table model.CIStr) (table.Table, error) {
+	tbl, err := is.InfoSchema.TableByName(schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return is.attachHypoIndexes(tbl)
+}
+
+// TableByID implements InfoSchema.TableByID
+func (is *HypoIndexAttachedInfoSchema) TableByID(id int64) (table.Table, bool) {
+	tbl, ok := is.InfoSchema.TableByID(id)
+	if !ok {
+		return nil, false
+	}
+	tbl, err := is.attachHypoIndexes(tbl)
+	if err != nil {
+		return nil, false
+	}
+	return tbl, true
+}
+
+func (is *HypoIndexAttachedInfoSchema) attachHypoIndexes(tbl table.Table) (table.Table, error) {
+	hypoIndexes := is.HypoIndexes[tbl.Meta().ID]
+	if len(hypoIndexes) == 0 || tbl.Type().IsVirtualTable() {
+		return tbl, nil
+	}
+	tblInfo := tbl.Meta().Clone()
+	nextID := tblInfo.MaxIndexID
+	for _, hypoIdx := range hypoIndexes {
+		// The table may have been changed after the hypothetical index is created, so the columns are resolved
+		// by their names again, and the index which refers to a dropped column is ignored.
+		if tblInfo.FindIndexByName(hypoIdx.Name.L) != nil {
+			continue
+		}
+		idx := hypoIdx.Clone()
+		valid := true
+		for _, idxCol := range idx.Columns {
+			col := model.FindColumnInfo(tblInfo.Columns, idxCol.Name.L)
+			if col == nil {
+				valid = false
+				break
+			}
+			idxCol.Offset = col.Offset
+		}
+		if !valid {
+			continue
+		}
+		nextID++
+		idx.ID = nextID
+		idx.State = model.StatePublic
+		tblInfo.Indices = append(tblInfo.Indices, idx)
+	}
+	return tables.TableFromMeta(tbl.Allocators(nil), tblInfo)
+}
@@ -178,6 +178,10 @@ const (
 	TableAttributes = "ATTRIBUTES"
 	// TablePlacementRules is the string constant of placement rules table.
 	TablePlacementRules = "PLACEMENT_RULES"
+	// TableCheckConstraints is the string constant of CHECK_CONSTRAINTS.
+	TableCheckConstraints = "CHECK_CONSTRAINTS"
+	// TableInstancePlanCache is the string constant of the instance plan cache table.
+	TableInstancePlanCache = "INSTANCE_PLAN_CACHE"
 )
 
 const (
@@ -276,6 +280,8 @@ var tableIDMap = map[string]int64{
 	TableAttributes:                      autoid.InformationSchemaDBID + 77,
 	TableTiDBHotRegionsHistory:           autoid.InformationSchemaDBID + 78,
 	TablePlacementRules:                  autoid.InformationSchemaDBID + 79,
+	TableCheckConstraints:                autoid.InformationSchemaDBID + 80,
+	TableInstancePlanCache:               autoid.InformationSchemaDBID + 81,
 }
 
 type columnInfo struct {
@@ -574,6 +580,13 @@ var tableConstraintsCols = []columnInfo{
 	{name: "CONSTRAINT_TYPE", tp: mysql.TypeVarchar, size: 64},
 }
 
+var tableCheckConstraintsCols = []columnInfo{
+	{name: "CONSTRAINT_CATALOG", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CHECK_CLAUSE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag},
+}
+
 var tableTriggersCols = []columnInfo{
 	{name: "TRIGGER_CATALOG", tp: mysql.TypeVarchar, size: 512},
 	{name: "TRIGGER_SCHEMA", tp: mysql.TypeVarchar, size: 64},
@@ -1451,6 +1464,21 @@ var tableAttributesCols = []columnInfo{
 	{name: "RANGES", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
 }
 
+var tableInstancePlanCacheCols = []columnInfo{
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the prepared statement"},
+	{name: "SQL_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Text of the prepared statement"},
+	{name: "SCHEMA_NAME", tp: mysql.TypeVarchar, size: 64, comment: "Current schema when the plan is built"},
+	{name: "SCHEMA_VERSION", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag, comment: "Schema version when the plan is built"},
+	{name: "PARAM_TYPES", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Types of the parameters"},
+	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the cached plan"},
+	{name: "MEM_BYTES", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag, comment: "Estimated memory usage of the cached plan"},
+	{name: "HIT_COUNT", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "How many times the cached plan is used"},
+	{name: "MISS_COUNT", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "How many times the plan is built because it isn't cached or the cached one is invalid"},
+	{name: "HIT_RATE", tp: mysql.TypeDouble, size: 22, flag: mysql.NotNullFlag, comment: "HIT_COUNT / (HIT_COUNT + MISS_COUNT)"},
+	{name: "CREATE_TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "When the plan is cached"},
+	{name: "LAST_HIT_TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "When the cached plan is used last time"},
+}
+
 var tablePlacementRulesCols = []columnInfo{
 	{name: "POLICY_ID", tp: mysql.TypeLonglong, size: 64, flag: mysql.NotNullFlag},
 	{name: "CATALOG_NAME", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
@@ -1501,6 +1529,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// CheckConstraintType is the string constant of CHECK.
+	CheckConstraintType = "CHECK"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1839,6 +1869,8 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableAttributes:                         tableAttributesCols,
 	TablePlacementRules:                     tablePlacementRulesCols,
+	TableCheckConstraints:                   tableCheckConstraintsCols,
+	TableInstancePlanCache:                  tableInstancePlanCacheCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
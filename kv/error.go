@@ -54,6 +54,9 @@ var (
 		pmysql.Message(mysql.MySQLErrName[mysql.ErrWriteConflictInTiDB].Raw+" "+TxnRetryableMark, nil))
 	// ErrLockExpire is the error when the lock is expired.
 	ErrLockExpire = dbterror.ClassTiKV.NewStd(mysql.ErrLockExpire)
+	// ErrSavepointNotSupported is the error when the transaction doesn't support savepoints.
+	ErrSavepointNotSupported = dbterror.ClassKV.NewStdErr(mysql.ErrNotSupportedYet,
+		pmysql.Message("This version of TiDB doesn't yet support savepoints in the transaction", nil))
 )
 
 // IsTxnRetryableError checks if the error could safely retry the transaction.
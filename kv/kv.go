@@ -126,6 +126,12 @@ var (
 	LastActiveStagingHandle StagingHandle = -1
 )
 
+// SavepointHandle is the reference of a savepoint in a transaction.
+type SavepointHandle int
+
+// InvalidSavepointHandle is an invalid savepoint handle.
+const InvalidSavepointHandle SavepointHandle = 0
+
 // RetrieverMutator is the interface that groups Retriever and Mutator interfaces.
 type RetrieverMutator interface {
 	Retriever
@@ -235,6 +241,19 @@ type Transaction interface {
 	ClearDiskFullOpt()
 }
 
+// SavepointTransaction is a Transaction whose changes can be rolled back to a savepoint.
+type SavepointTransaction interface {
+	// AddSavepoint creates a savepoint, and returns its handle.
+	AddSavepoint() SavepointHandle
+	// RollbackToSavepoint discards the changes after the savepoint, and removes the later savepoints.
+	// The savepoint itself is kept. The keys locked after the savepoint are still locked.
+	RollbackToSavepoint(context.Context, SavepointHandle) error
+	// ReleaseSavepoint removes the savepoint and the later savepoints, and keeps the changes after them.
+	ReleaseSavepoint(SavepointHandle)
+	// DeleteSavepoint removes only the savepoint, and keeps the later savepoints and the changes.
+	DeleteSavepoint(SavepointHandle)
+}
+
 // Client is used to send request to KV layer.
 type Client interface {
 	// Send sends request to KV layer, returns a Response.
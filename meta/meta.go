@@ -689,6 +689,31 @@ var (
 	AddIndexJobListKey JobListKeyType = mDDLJobAddIdxList
 )
 
+// GetJobListKey returns the key of the idx-th job queue. The 0th queue is
+// DefaultJobListKey or AddIndexJobListKey, the others are suffixed with their indexes.
+func GetJobListKey(idx int, isAddIdx bool) JobListKeyType {
+	key := DefaultJobListKey
+	if isAddIdx {
+		key = AddIndexJobListKey
+	}
+	if idx == 0 {
+		return key
+	}
+	return JobListKeyType(fmt.Sprintf("%s_%d", key, idx))
+}
+
+// AllJobListKeys returns the keys of all the DDL job queues, where queueCount is the count of the job queues
+// for each kind of DDL jobs.
+func AllJobListKeys(queueCount int) []JobListKeyType {
+	keys := make([]JobListKeyType, 0, 2*queueCount)
+	for _, isAddIdx := range []bool{false, true} {
+		for i := 0; i < queueCount; i++ {
+			keys = append(keys, GetJobListKey(i, isAddIdx))
+		}
+	}
+	return keys
+}
+
 func (m *Meta) enQueueDDLJob(key []byte, job *model.Job) error {
 	b, err := job.Encode(true)
 	if err == nil {
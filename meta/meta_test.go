@@ -15,6 +15,7 @@
 package meta_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util"
@@ -636,6 +638,44 @@ func TestAddIndexJob(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobQueues(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer func() {
+		err := store.Close()
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, meta.DefaultJobListKey, meta.GetJobListKey(0, false))
+	require.Equal(t, meta.AddIndexJobListKey, meta.GetJobListKey(0, true))
+	require.Equal(t, meta.JobListKeyType("DDLJobList_3"), meta.GetJobListKey(3, false))
+	require.Equal(t, meta.JobListKeyType("DDLJobAddIdxList_3"), meta.GetJobListKey(3, true))
+	keys := meta.AllJobListKeys(variable.MaxDDLJobQueueCount)
+	require.Len(t, keys, 2*variable.MaxDDLJobQueueCount)
+	require.Contains(t, keys, meta.DefaultJobListKey)
+	require.Contains(t, keys, meta.AddIndexJobListKey)
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn, meta.GetJobListKey(3, false))
+	require.NoError(t, m.EnQueueDDLJob(&model.Job{ID: 1}))
+	require.NoError(t, m.EnQueueDDLJob(&model.Job{ID: 2}, meta.GetJobListKey(3, true)))
+	for _, key := range keys {
+		l, err := m.DDLJobQueueLen(key)
+		require.NoError(t, err)
+		switch {
+		case bytes.Equal(key, meta.GetJobListKey(3, false)), bytes.Equal(key, meta.GetJobListKey(3, true)):
+			require.Equal(t, int64(1), l)
+		default:
+			require.Equal(t, int64(0), l)
+		}
+	}
+	job, err := m.DeQueueDDLJob()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), job.ID)
+	require.NoError(t, txn.Rollback())
+}
+
 func BenchmarkGenGlobalIDs(b *testing.B) {
 	store, err := mockstore.NewMockStore()
 	require.NoError(b, err)
@@ -1684,11 +1684,17 @@ type DropIndexStmt struct {
 	IndexName string
 	Table     *TableName
 	LockAlg   *IndexLockAndAlgorithm
+	// IsHypo indicates the index to drop is a hypothetical index.
+	IsHypo bool
 }
 
 // Restore implements Node interface.
 func (n *DropIndexStmt) Restore(ctx *format.RestoreCtx) error {
-	ctx.WriteKeyWord("DROP INDEX ")
+	ctx.WriteKeyWord("DROP ")
+	if n.IsHypo {
+		ctx.WriteKeyWord("HYPO ")
+	}
+	ctx.WriteKeyWord("INDEX ")
 	if n.IfExists {
 		ctx.WriteWithSpecialComments("", func() {
 			ctx.WriteKeyWord("IF EXISTS ")
@@ -2014,6 +2020,9 @@ const (
 	TableOptionTableCheckSum
 	TableOptionUnion
 	TableOptionEncryption
+	TableOptionTTL
+	TableOptionTTLEnable
+	TableOptionTTLJobInterval
 	TableOptionPlacementPrimaryRegion       = TableOptionType(PlacementOptionPrimaryRegion)
 	TableOptionPlacementRegions             = TableOptionType(PlacementOptionRegions)
 	TableOptionPlacementFollowerCount       = TableOptionType(PlacementOptionFollowerCount)
@@ -2077,6 +2086,11 @@ type TableOption struct {
 	BoolValue  bool
 	Value      ValueExpr
 	TableNames []*TableName
+	// ColumnName, TimeUnitValue and Expr are used by the TTL option,
+	// which is written as `TTL = ColumnName + INTERVAL Expr TimeUnit`.
+	ColumnName    *ColumnName
+	TimeUnitValue *TimeUnitExpr
+	Expr          ExprNode
 }
 
 func (n *TableOption) Restore(ctx *format.RestoreCtx) error {
@@ -2124,6 +2138,39 @@ func (n *TableOption) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain("= ")
 			ctx.WritePlainf("%d", n.UintValue)
 		})
+	case TableOptionTTL:
+		var err error
+		ctx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() {
+			ctx.WriteKeyWord("TTL ")
+			ctx.WritePlain("= ")
+			ctx.WriteName(n.ColumnName.Name.String())
+			ctx.WritePlain(" + ")
+			ctx.WriteKeyWord("INTERVAL ")
+			if err = n.Expr.Restore(ctx); err != nil {
+				return
+			}
+			ctx.WritePlain(" ")
+			err = n.TimeUnitValue.Restore(ctx)
+		})
+		if err != nil {
+			return errors.Annotate(err, "An error occurred while restore TableOption.TTL")
+		}
+	case TableOptionTTLEnable:
+		ctx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() {
+			ctx.WriteKeyWord("TTL_ENABLE ")
+			ctx.WritePlain("= ")
+			if n.BoolValue {
+				ctx.WriteString("ON")
+			} else {
+				ctx.WriteString("OFF")
+			}
+		})
+	case TableOptionTTLJobInterval:
+		ctx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() {
+			ctx.WriteKeyWord("TTL_JOB_INTERVAL ")
+			ctx.WritePlain("= ")
+			ctx.WriteString(n.StrValue)
+		})
 	case TableOptionAutoRandomBase:
 		if n.BoolValue {
 			ctx.WriteWithSpecialComments(tidb.FeatureIDForceAutoInc, func() {
@@ -2534,6 +2581,11 @@ const (
 	AlterTableCache
 	AlterTableNoCache
 	AlterTableStatsOptions
+	AlterTableRemoveTTL
+	// AlterTableDropFirstPartition drops the first partitions of an INTERVAL partitioned table.
+	AlterTableDropFirstPartition
+	// AlterTableAddLastPartition adds the partitions to the end of an INTERVAL partitioned table.
+	AlterTableAddLastPartition
 )
 
 // LockType is the type for AlterTableSpec.
@@ -3051,6 +3103,24 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("DISABLE KEYS")
 	case AlterTableRemovePartitioning:
 		ctx.WriteKeyWord("REMOVE PARTITIONING")
+	case AlterTableRemoveTTL:
+		ctx.WriteWithSpecialComments(tidb.FeatureIDTTL, func() {
+			ctx.WriteKeyWord("REMOVE TTL")
+		})
+	case AlterTableDropFirstPartition:
+		ctx.WriteKeyWord("FIRST PARTITION LESS THAN ")
+		ctx.WritePlain("(")
+		if err := n.Partition.Interval.FirstRangeEnd.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterTableSpec.Partition.Interval.FirstRangeEnd")
+		}
+		ctx.WritePlain(")")
+	case AlterTableAddLastPartition:
+		ctx.WriteKeyWord("LAST PARTITION LESS THAN ")
+		ctx.WritePlain("(")
+		if err := n.Partition.Interval.LastRangeEnd.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterTableSpec.Partition.Interval.LastRangeEnd")
+		}
+		ctx.WritePlain(")")
 	case AlterTableWithValidation:
 		ctx.WriteKeyWord("WITH VALIDATION")
 	case AlterTableWithoutValidation:
@@ -3236,7 +3306,7 @@ func (n *AlterTableStmt) Restore(ctx *format.RestoreCtx) error {
 		return errors.Annotate(err, "An error occurred while restore AlterTableStmt.Table")
 	}
 	for i, spec := range n.Specs {
-		if i == 0 || spec.Tp == AlterTablePartition || spec.Tp == AlterTableRemovePartitioning || spec.Tp == AlterTableImportTablespace || spec.Tp == AlterTableDiscardTablespace {
+		if i == 0 || spec.Tp == AlterTablePartition || spec.Tp == AlterTableRemovePartitioning || spec.Tp == AlterTableRemoveTTL || spec.Tp == AlterTableDropFirstPartition || spec.Tp == AlterTableAddLastPartition || spec.Tp == AlterTableImportTablespace || spec.Tp == AlterTableDiscardTablespace {
 			ctx.WritePlain(" ")
 		} else {
 			ctx.WritePlain(", ")
@@ -3596,12 +3666,109 @@ type PartitionMethod struct {
 
 	// KeyAlgorithm is the optional hash algorithm type for `PARTITION BY [LINEAR] KEY` syntax.
 	KeyAlgorithm *PartitionKeyAlgorithm
+
+	// Interval is the optional INTERVAL clause of the RANGE type.
+	Interval *PartitionInterval
 }
 
 type PartitionKeyAlgorithm struct {
 	Type uint64
 }
 
+// PartitionIntervalExpr is the interval between the bounds of two adjacent partitions,
+// TimeUnit is TimeUnitInvalid if the interval is not a time interval.
+type PartitionIntervalExpr struct {
+	Expr     ExprNode
+	TimeUnit TimeUnitType
+}
+
+// PartitionInterval is the INTERVAL clause of a RANGE partitioned table, which is written as
+//
+//	INTERVAL (expr [unit]) [FIRST PARTITION LESS THAN (expr) LAST PARTITION LESS THAN (expr)]
+//	[NULL PARTITION] [MAXVALUE PARTITION] [PRECREATE n] [RETENTION n]
+//
+// The partitions from FIRST to LAST are generated by the interval. It is also used by
+// `ALTER TABLE ... FIRST|LAST PARTITION LESS THAN (expr)`, where only FirstRangeEnd or LastRangeEnd is set.
+type PartitionInterval struct {
+	IntervalExpr  PartitionIntervalExpr
+	FirstRangeEnd ExprNode
+	LastRangeEnd  ExprNode
+	NullPart      bool
+	MaxValPart    bool
+	// Precreate is the number of the partitions kept ahead of the current time in background.
+	Precreate uint64
+	// Retention is the number of the intervals of data kept, the older partitions are dropped in background.
+	Retention uint64
+}
+
+// Restore implements the Node interface.
+func (n *PartitionInterval) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("INTERVAL ")
+	ctx.WritePlain("(")
+	if err := n.IntervalExpr.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore PartitionInterval.IntervalExpr.Expr")
+	}
+	if n.IntervalExpr.TimeUnit != TimeUnitInvalid {
+		ctx.WritePlain(" ")
+		ctx.WriteKeyWord(n.IntervalExpr.TimeUnit.String())
+	}
+	ctx.WritePlain(")")
+	if n.FirstRangeEnd != nil && n.LastRangeEnd != nil {
+		ctx.WriteKeyWord(" FIRST PARTITION LESS THAN ")
+		ctx.WritePlain("(")
+		if err := n.FirstRangeEnd.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore PartitionInterval.FirstRangeEnd")
+		}
+		ctx.WritePlain(")")
+		ctx.WriteKeyWord(" LAST PARTITION LESS THAN ")
+		ctx.WritePlain("(")
+		if err := n.LastRangeEnd.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore PartitionInterval.LastRangeEnd")
+		}
+		ctx.WritePlain(")")
+	}
+	if n.NullPart {
+		ctx.WriteKeyWord(" NULL PARTITION")
+	}
+	if n.MaxValPart {
+		ctx.WriteKeyWord(" MAXVALUE PARTITION")
+	}
+	if n.Precreate > 0 {
+		ctx.WriteKeyWord(" PRECREATE ")
+		ctx.WritePlainf("%d", n.Precreate)
+	}
+	if n.Retention > 0 {
+		ctx.WriteKeyWord(" RETENTION ")
+		ctx.WritePlainf("%d", n.Retention)
+	}
+	return nil
+}
+
+func (n *PartitionInterval) acceptInPlace(v Visitor) bool {
+	if n.IntervalExpr.Expr != nil {
+		expr, ok := n.IntervalExpr.Expr.Accept(v)
+		if !ok {
+			return false
+		}
+		n.IntervalExpr.Expr = expr.(ExprNode)
+	}
+	if n.FirstRangeEnd != nil {
+		expr, ok := n.FirstRangeEnd.Accept(v)
+		if !ok {
+			return false
+		}
+		n.FirstRangeEnd = expr.(ExprNode)
+	}
+	if n.LastRangeEnd != nil {
+		expr, ok := n.LastRangeEnd.Accept(v)
+		if !ok {
+			return false
+		}
+		n.LastRangeEnd = expr.(ExprNode)
+	}
+	return true
+}
+
 // Restore implements the Node interface
 func (n *PartitionMethod) Restore(ctx *format.RestoreCtx) error {
 	if n.Linear {
@@ -3653,6 +3820,17 @@ func (n *PartitionMethod) Restore(ctx *format.RestoreCtx) error {
 		ctx.WritePlainf("%d", n.Limit)
 	}
 
+	if n.Interval != nil {
+		var err error
+		ctx.WritePlain(" ")
+		ctx.WriteWithSpecialComments(tidb.FeatureIDIntervalPartition, func() {
+			err = n.Interval.Restore(ctx)
+		})
+		if err != nil {
+			return errors.Annotate(err, "An error occurred while restore PartitionMethod.Interval")
+		}
+	}
+
 	return nil
 }
 
@@ -3672,6 +3850,9 @@ func (n *PartitionMethod) acceptInPlace(v Visitor) bool {
 		}
 		n.ColumnNames[i] = newColName.(*ColumnName)
 	}
+	if n.Interval != nil && !n.Interval.acceptInPlace(v) {
+		return false
+	}
 	return true
 }
 
@@ -3719,7 +3900,8 @@ func (n *PartitionOptions) Validate() error {
 			n.Num = 1
 		}
 	case model.PartitionTypeRange, model.PartitionTypeList:
-		if len(n.Definitions) == 0 {
+		// The partitions of INTERVAL partitioning can be generated from FIRST to LAST.
+		if len(n.Definitions) == 0 && (n.Interval == nil || n.Interval.FirstRangeEnd == nil) {
 			return ErrPartitionsMustBeDefined.GenWithStackByArgs(n.Tp)
 		}
 	case model.PartitionTypeSystemTime:
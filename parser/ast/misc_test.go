@@ -58,6 +58,8 @@ func TestMiscVisitorCover(t *testing.T) {
 		&ast.GrantStmt{},
 		&ast.PrepareStmt{SQLVar: &ast.VariableExpr{Value: valueExpr}},
 		&ast.RollbackStmt{},
+		&ast.SavepointStmt{},
+		&ast.ReleaseSavepointStmt{},
 		&ast.SetPwdStmt{},
 		&ast.SetStmt{Variables: []*ast.VariableAssignment{
 			{
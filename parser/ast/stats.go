@@ -23,6 +23,9 @@ var (
 	_ StmtNode = &AnalyzeTableStmt{}
 	_ StmtNode = &DropStatsStmt{}
 	_ StmtNode = &LoadStatsStmt{}
+	_ StmtNode = &LockStatsStmt{}
+	_ StmtNode = &UnlockStatsStmt{}
+	_ StmtNode = &RestoreStatsStmt{}
 )
 
 // AnalyzeTableStmt is used to create table statistics.
@@ -259,3 +262,118 @@ func (n *LoadStatsStmt) Accept(v Visitor) (Node, bool) {
 	n = newNode.(*LoadStatsStmt)
 	return v.Leave(n)
 }
+
+// LockStatsStmt is the statement node for locking the statistics of the tables,
+// auto analyze skips the locked tables.
+type LockStatsStmt struct {
+	stmtNode
+
+	Tables []*TableName
+}
+
+// Restore implements Node interface.
+func (n *LockStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("LOCK STATS ")
+	return restoreStatsTables(ctx, n.Tables)
+}
+
+// Accept implements Node Accept interface.
+func (n *LockStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*LockStatsStmt)
+	for i, val := range n.Tables {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Tables[i] = node.(*TableName)
+	}
+	return v.Leave(n)
+}
+
+// UnlockStatsStmt is the statement node for unlocking the statistics of the tables.
+type UnlockStatsStmt struct {
+	stmtNode
+
+	Tables []*TableName
+}
+
+// Restore implements Node interface.
+func (n *UnlockStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("UNLOCK STATS ")
+	return restoreStatsTables(ctx, n.Tables)
+}
+
+// Accept implements Node Accept interface.
+func (n *UnlockStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*UnlockStatsStmt)
+	for i, val := range n.Tables {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Tables[i] = node.(*TableName)
+	}
+	return v.Leave(n)
+}
+
+func restoreStatsTables(ctx *format.RestoreCtx, tables []*TableName) error {
+	for i, table := range tables {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := table.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore Tables[%d]", i)
+		}
+	}
+	return nil
+}
+
+// RestoreStatsStmt is the statement node for restoring the statistics of the table from the historical statistics.
+// The latest historical statistics recorded before the timestamp of the AS OF clause are restored.
+type RestoreStatsStmt struct {
+	stmtNode
+
+	Table *TableName
+	AsOf  *AsOfClause
+}
+
+// Restore implements Node interface.
+func (n *RestoreStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RESTORE STATS ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore RestoreStatsStmt.Table")
+	}
+	ctx.WritePlain(" ")
+	if err := n.AsOf.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore RestoreStatsStmt.AsOf")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *RestoreStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*RestoreStatsStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	node, ok = n.AsOf.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.AsOf = node.(*AsOfClause)
+	return v.Leave(n)
+}
@@ -285,6 +285,7 @@ var tokenMap = map[string]int{
 	"DEPTH":                    depth,
 	"DESC":                     desc,
 	"DESCRIBE":                 describe,
+	"DIGEST":                   digest,
 	"DIRECTORY":                directory,
 	"DISABLE":                  disable,
 	"DISCARD":                  discard,
@@ -370,6 +371,7 @@ var tokenMap = map[string]int{
 	"HOUR_MINUTE":              hourMinute,
 	"HOUR_SECOND":              hourSecond,
 	"HOUR":                     hour,
+	"HYPO":                     hypo,
 	"IDENTIFIED":               identified,
 	"IF":                       ifKwd,
 	"IGNORE":                   ignore,
@@ -527,6 +529,7 @@ var tokenMap = map[string]int{
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
 	"PERCENT":                  percent,
+	"PAUSE":                    pause,
 	"PER_DB":                   per_db,
 	"PER_TABLE":                per_table,
 	"PESSIMISTIC":              pessimistic,
@@ -538,6 +541,7 @@ var tokenMap = map[string]int{
 	"POSITION":                 position,
 	"PRE_SPLIT_REGIONS":        preSplitRegions,
 	"PRECEDING":                preceding,
+	"PRECREATE":                precreate,
 	"PREDICATE":                predicate,
 	"PRECISION":                precisionType,
 	"PREPARE":                  prepare,
@@ -591,6 +595,7 @@ var tokenMap = map[string]int{
 	"RESTORE":                  restore,
 	"RESTORES":                 restores,
 	"RESTRICT":                 restrict,
+	"RETENTION":                retention,
 	"REVERSE":                  reverse,
 	"REVOKE":                   revoke,
 	"RIGHT":                    right,
@@ -609,6 +614,7 @@ var tokenMap = map[string]int{
 	"SAMPLES":                  samples,
 	"SAMPLERATE":               sampleRate,
 	"SAN":                      san,
+	"SAVEPOINT":                savepoint,
 	"SCHEDULE":                 schedule,
 	"SCHEMA":                   database,
 	"SCHEMAS":                  databases,
@@ -744,6 +750,9 @@ var tokenMap = map[string]int{
 	"TRIM":                     trim,
 	"TRUE":                     trueKwd,
 	"TRUNCATE":                 truncate,
+	"TTL":                      ttl,
+	"TTL_ENABLE":               ttlEnable,
+	"TTL_JOB_INTERVAL":         ttlJobInterval,
 	"TYPE":                     tp,
 	"UNBOUNDED":                unbounded,
 	"UNCOMMITTED":              uncommitted,
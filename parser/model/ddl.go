@@ -95,6 +95,12 @@ const (
 	ActionAlterTableStatsOptions        ActionType = 58
 	ActionAlterNoCacheTable             ActionType = 59
 	ActionCreateTables                  ActionType = 60
+	ActionMultiSchemaChange             ActionType = 61
+	ActionAlterTTLInfo                  ActionType = 62
+	ActionAlterTTLRemove                ActionType = 63
+	ActionReorganizePartition           ActionType = 64
+	ActionAlterTablePartitioning        ActionType = 65
+	ActionRemovePartitioning            ActionType = 66
 )
 
 var actionMap = map[ActionType]string{
@@ -157,6 +163,12 @@ var actionMap = map[ActionType]string{
 	ActionAlterCacheTable:               "alter table cache",
 	ActionAlterNoCacheTable:             "alter table nocache",
 	ActionAlterTableStatsOptions:        "alter table statistics options",
+	ActionMultiSchemaChange:             "alter table multi-schema change",
+	ActionAlterTTLInfo:                  "alter table ttl",
+	ActionAlterTTLRemove:                "alter table no_ttl",
+	ActionReorganizePartition:           "reorganize partition",
+	ActionAlterTablePartitioning:        "alter table partition by",
+	ActionRemovePartitioning:            "remove partitioning",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -223,6 +235,31 @@ type DDLReorgMeta struct {
 	Warnings      map[errors.ErrorID]*terror.Error `json:"warnings"`
 	WarningsCount map[errors.ErrorID]int64         `json:"warnings_count"`
 	Location      *TimeZone                        `json:"time_zone"`
+	ReorgTp       ReorgType                        `json:"reorg_tp"`
+}
+
+// ReorgType indicates how the reorganization backfills the data.
+type ReorgType int8
+
+const (
+	// ReorgTypeNone means the backfilling way isn't decided, the jobs of the older versions are in this type,
+	// and they're backfilled in transactions.
+	ReorgTypeNone ReorgType = iota
+	// ReorgTypeTxn means the data is backfilled in transactions.
+	ReorgTypeTxn
+	// ReorgTypeIngest means the data is sorted on the local disk and ingested into the storage.
+	ReorgTypeIngest
+)
+
+// String implements fmt.Stringer interface.
+func (tp ReorgType) String() string {
+	switch tp {
+	case ReorgTypeTxn:
+		return "txn"
+	case ReorgTypeIngest:
+		return "ingest"
+	}
+	return ""
 }
 
 // TimeZone represents a single time zone.
@@ -241,6 +278,95 @@ func NewDDLReorgMeta() *DDLReorgMeta {
 // MultiSchemaInfo keeps some information for multi schema change.
 type MultiSchemaInfo struct {
 	Warnings []*errors.Error
+
+	// SubJobs are the schema changes of a multi-schema change job, they are
+	// executed one by one in the order of the ALTER TABLE specifications.
+	SubJobs []*SubJob `json:"sub_jobs"`
+	// Revertible is true when none of the sub-jobs has entered the state that
+	// can not be rolled back.
+	Revertible bool `json:"revertible"`
+}
+
+// NewMultiSchemaInfo new a MultiSchemaInfo.
+func NewMultiSchemaInfo() *MultiSchemaInfo {
+	return &MultiSchemaInfo{
+		SubJobs:    nil,
+		Revertible: true,
+	}
+}
+
+// SubJob is a representation of one DDL schema change. A Job may contain zero
+// (when multi-schema change is not applicable) or more SubJobs.
+type SubJob struct {
+	Type        ActionType      `json:"type"`
+	Args        []interface{}   `json:"-"`
+	RawArgs     json.RawMessage `json:"raw_args"`
+	SchemaState SchemaState     `json:"schema_state"`
+	SnapshotVer uint64          `json:"snapshot_ver"`
+	Revertible  bool            `json:"revertible"`
+	State       JobState        `json:"state"`
+	RowCount    int64           `json:"row_count"`
+	CtxVars     []interface{}   `json:"-"`
+}
+
+// IsNormal returns true if the sub-job is normally running.
+func (sub *SubJob) IsNormal() bool {
+	switch sub.State {
+	case JobStateCancelling, JobStateCancelled,
+		JobStateRollingback, JobStateRollbackDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsFinished returns true if the sub-job is done, rolled back or cancelled.
+func (sub *SubJob) IsFinished() bool {
+	return sub.State == JobStateDone ||
+		sub.State == JobStateRollbackDone ||
+		sub.State == JobStateCancelled
+}
+
+// ToProxyJob converts a sub-job to a proxy job, the proxy job is a normal job
+// that can be handled by the existing DDL handlers.
+func (sub *SubJob) ToProxyJob(parentJob *Job) *Job {
+	return &Job{
+		ID:              parentJob.ID,
+		Type:            sub.Type,
+		SchemaID:        parentJob.SchemaID,
+		TableID:         parentJob.TableID,
+		SchemaName:      parentJob.SchemaName,
+		State:           sub.State,
+		Error:           nil,
+		ErrorCount:      0,
+		RowCount:        sub.RowCount,
+		CtxVars:         sub.CtxVars,
+		Args:            sub.Args,
+		RawArgs:         sub.RawArgs,
+		SchemaState:     sub.SchemaState,
+		SnapshotVer:     sub.SnapshotVer,
+		RealStartTS:     parentJob.RealStartTS,
+		StartTS:         parentJob.StartTS,
+		DependencyID:    parentJob.DependencyID,
+		Query:           parentJob.Query,
+		BinlogInfo:      parentJob.BinlogInfo,
+		Version:         parentJob.Version,
+		ReorgMeta:       parentJob.ReorgMeta,
+		MultiSchemaInfo: &MultiSchemaInfo{Revertible: sub.Revertible},
+		Priority:        parentJob.Priority,
+	}
+}
+
+// FromProxyJob updates the sub-job with the state of the proxy job.
+func (sub *SubJob) FromProxyJob(proxyJob *Job) {
+	sub.Revertible = proxyJob.MultiSchemaInfo.Revertible
+	sub.SchemaState = proxyJob.SchemaState
+	sub.SnapshotVer = proxyJob.SnapshotVer
+	sub.Args = proxyJob.Args
+	sub.RawArgs = proxyJob.RawArgs
+	sub.State = proxyJob.State
+	sub.RowCount = proxyJob.RowCount
+	sub.CtxVars = proxyJob.CtxVars
 }
 
 // Job is for a DDL operation.
@@ -351,6 +477,14 @@ func (job *Job) GetWarnings() (map[errors.ErrorID]*terror.Error, map[errors.Erro
 	return job.ReorgMeta.Warnings, job.ReorgMeta.WarningsCount
 }
 
+// MarkNonRevertible marks the current job as non-revertible.
+// It means the job will keep running even if it is cancelled.
+func (job *Job) MarkNonRevertible() {
+	if job.MultiSchemaInfo != nil {
+		job.MultiSchemaInfo.Revertible = false
+	}
+}
+
 // Encode encodes job with json format.
 // updateRawArgs is used to determine whether to update the raw args.
 func (job *Job) Encode(updateRawArgs bool) ([]byte, error) {
@@ -360,6 +494,18 @@ func (job *Job) Encode(updateRawArgs bool) ([]byte, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if job.MultiSchemaInfo != nil {
+			for _, sub := range job.MultiSchemaInfo.SubJobs {
+				// Only update the args of executing sub-jobs.
+				if sub.Args == nil {
+					continue
+				}
+				sub.RawArgs, err = json.Marshal(sub.Args)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+		}
 	}
 
 	var b []byte
@@ -405,42 +551,119 @@ func (job *Job) String() string {
 		job.ID, job.Type, job.State, job.SchemaState, job.SchemaID, job.TableID, rowCount, len(job.Args), TSConvert2Time(job.StartTS), job.Error, job.ErrorCount, job.SnapshotVer)
 }
 
-func (job *Job) hasDependentSchema(other *Job) (bool, error) {
-	if other.Type == ActionDropSchema || other.Type == ActionCreateSchema {
-		if other.SchemaID == job.SchemaID {
-			return true, nil
+// decodeArgsWithoutChange decodes the job args like DecodeArgs, but it keeps job.Args unchanged,
+// so it can be used on the job that isn't encoded yet.
+func (job *Job) decodeArgsWithoutChange(args ...interface{}) error {
+	rawArgs := job.RawArgs
+	if rawArgs == nil {
+		var err error
+		if rawArgs, err = json.Marshal(job.Args); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	tmp := &Job{RawArgs: rawArgs}
+	return errors.Trace(tmp.DecodeArgs(args...))
+}
+
+// involvingSchemaAndTableIDs returns the IDs of all the schemas and tables that the job handles,
+// including the ones in the job args besides job.SchemaID and job.TableID.
+func (job *Job) involvingSchemaAndTableIDs() (schemaIDs []int64, tableIDs []int64, _ error) {
+	schemaIDs, tableIDs = []int64{job.SchemaID}, []int64{job.TableID}
+	switch job.Type {
+	case ActionRenameTable:
+		var oldSchemaID int64
+		if err := job.decodeArgsWithoutChange(&oldSchemaID); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		schemaIDs = append(schemaIDs, oldSchemaID)
+	case ActionRenameTables:
+		var (
+			oldSchemaIDs, newSchemaIDs, tblIDs []int64
+			tableNames                         []*CIStr
+		)
+		if err := job.decodeArgsWithoutChange(&oldSchemaIDs, &newSchemaIDs, &tableNames, &tblIDs); err != nil {
+			return nil, nil, errors.Trace(err)
 		}
-		if job.Type == ActionRenameTable {
-			var oldSchemaID int64
-			if err := job.DecodeArgs(&oldSchemaID); err != nil {
-				return false, errors.Trace(err)
+		schemaIDs = append(append(schemaIDs, oldSchemaIDs...), newSchemaIDs...)
+		tableIDs = append(tableIDs, tblIDs...)
+	case ActionExchangeTablePartition:
+		// The job handles the non-partitioned table, and the args contain the partitioned table.
+		var defID, ptSchemaID, ptID int64
+		if err := job.decodeArgsWithoutChange(&defID, &ptSchemaID, &ptID); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		schemaIDs = append(schemaIDs, ptSchemaID)
+		tableIDs = append(tableIDs, ptID)
+	case ActionAddForeignKey:
+		var (
+			fkInfo                  FKInfo
+			refSchemaID, refTableID int64
+		)
+		if err := job.decodeArgsWithoutChange(&fkInfo, &refSchemaID, &refTableID); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		// The jobs of the old versions don't have the referenced table in the args.
+		if refTableID != 0 {
+			schemaIDs = append(schemaIDs, refSchemaID)
+			tableIDs = append(tableIDs, refTableID)
+		}
+	case ActionMultiSchemaChange:
+		if job.MultiSchemaInfo == nil {
+			break
+		}
+		// The sub-jobs adding the foreign keys handle the referenced tables as well.
+		for _, sub := range job.MultiSchemaInfo.SubJobs {
+			if sub.Type != ActionAddForeignKey {
+				continue
 			}
-			if other.SchemaID == oldSchemaID {
-				return true, nil
+			subJob := &Job{Type: sub.Type, Args: sub.Args, RawArgs: sub.RawArgs}
+			subSchemaIDs, subTableIDs, err := subJob.involvingSchemaAndTableIDs()
+			if err != nil {
+				return nil, nil, errors.Trace(err)
 			}
+			schemaIDs = append(schemaIDs, subSchemaIDs[1:]...)
+			tableIDs = append(tableIDs, subTableIDs[1:]...)
 		}
 	}
-	return false, nil
+	return schemaIDs, tableIDs, nil
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
 }
 
 // IsDependentOn returns whether the job depends on "other".
 // How to check the job depends on "other"?
 // 1. The two jobs handle the same database when one of the two jobs is an ActionDropSchema or ActionCreateSchema type.
 // 2. Or the two jobs handle the same table.
+// The databases and tables that a job handles include the ones in its args, e.g. the partitioned table of
+// ActionExchangeTablePartition, the tables of ActionRenameTables and the referenced table of ActionAddForeignKey.
 func (job *Job) IsDependentOn(other *Job) (bool, error) {
-	isDependent, err := job.hasDependentSchema(other)
-	if err != nil || isDependent {
-		return isDependent, errors.Trace(err)
+	schemaIDs, tableIDs, err := job.involvingSchemaAndTableIDs()
+	if err != nil {
+		return false, errors.Trace(err)
 	}
-	isDependent, err = other.hasDependentSchema(job)
-	if err != nil || isDependent {
-		return isDependent, errors.Trace(err)
+	otherSchemaIDs, otherTableIDs, err := other.involvingSchemaAndTableIDs()
+	if err != nil {
+		return false, errors.Trace(err)
 	}
 
-	// TODO: If a job is ActionRenameTable, we need to check table name.
-	if other.TableID == job.TableID {
+	if (other.Type == ActionDropSchema || other.Type == ActionCreateSchema) && containsID(schemaIDs, other.SchemaID) {
 		return true, nil
 	}
+	if (job.Type == ActionDropSchema || job.Type == ActionCreateSchema) && containsID(otherSchemaIDs, job.SchemaID) {
+		return true, nil
+	}
+	for _, id := range tableIDs {
+		if containsID(otherTableIDs, id) {
+			return true, nil
+		}
+	}
 	return false, nil
 }
 
@@ -470,6 +693,16 @@ func (job *Job) IsCancelling() bool {
 	return job.State == JobStateCancelling
 }
 
+// IsPausing returns whether the job is pausing or not.
+func (job *Job) IsPausing() bool {
+	return job.State == JobStatePausing
+}
+
+// IsPaused returns whether the job is paused or not.
+func (job *Job) IsPaused() bool {
+	return job.State == JobStatePaused
+}
+
 // IsSynced returns whether the DDL modification is synced among all TiDB servers.
 func (job *Job) IsSynced() bool {
 	return job.State == JobStateSynced
@@ -504,6 +737,11 @@ const (
 	JobStateSynced JobState = 6
 	// JobStateCancelling is used to mark the DDL job is cancelled by the client, but the DDL work hasn't handle it.
 	JobStateCancelling JobState = 7
+	// JobStatePausing is used to mark the DDL job is paused by the client, but the DDL work hasn't handle it.
+	JobStatePausing JobState = 8
+	// JobStatePaused is used to mark the DDL job is paused. A paused job keeps its reorganization
+	// progress and isn't run until it's resumed.
+	JobStatePaused JobState = 9
 )
 
 // String implements fmt.Stringer interface.
@@ -521,6 +759,10 @@ func (s JobState) String() string {
 		return "cancelled"
 	case JobStateCancelling:
 		return "cancelling"
+	case JobStatePausing:
+		return "pausing"
+	case JobStatePaused:
+		return "paused"
 	case JobStateSynced:
 		return "synced"
 	default:
@@ -354,6 +354,13 @@ type TableInfo struct {
 
 	// StatsOptions is used when do analyze/auto-analyze for each table
 	StatsOptions *StatsOptions `json:"stats_options"`
+
+	// TTLInfo is the TTL config of the table, the expired rows are deleted in background.
+	TTLInfo *TTLInfo `json:"ttl_info"`
+
+	// ExchangePartitionInfo is set on both tables of an EXCHANGE PARTITION which rebuilds global indexes,
+	// the records of the tables can't be inserted or updated until the exchange finishes.
+	ExchangePartitionInfo *ExchangePartitionInfo `json:"exchange_partition_info,omitempty"`
 }
 type TableCacheStatusType int
 
@@ -544,6 +551,17 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	if t.Constraints != nil {
+		nt.Constraints = make([]*ConstraintInfo, len(t.Constraints))
+		for i := range t.Constraints {
+			nt.Constraints[i] = t.Constraints[i].Clone()
+		}
+	}
+
+	if t.TTLInfo != nil {
+		nt.TTLInfo = t.TTLInfo.Clone()
+	}
+
 	return &nt
 }
 
@@ -789,6 +807,9 @@ type PartitionType int
 
 // Partition types.
 const (
+	// PartitionTypeNone is the partition type of a non-partitioned table which is treated as a table
+	// with only one partition, when it is being converted from or to a partitioned table.
+	PartitionTypeNone       PartitionType = 0
 	PartitionTypeRange      PartitionType = 1
 	PartitionTypeHash       PartitionType = 2
 	PartitionTypeList       PartitionType = 3
@@ -808,6 +829,8 @@ func (p PartitionType) String() string {
 		return "KEY"
 	case PartitionTypeSystemTime:
 		return "SYSTEM_TIME"
+	case PartitionTypeNone:
+		return "NONE"
 	default:
 		return ""
 	}
@@ -832,6 +855,39 @@ type PartitionInfo struct {
 	DroppingDefinitions []PartitionDefinition `json:"dropping_definitions"`
 	States              []PartitionState      `json:"states"`
 	Num                 uint64                `json:"num"`
+	// DDLState is the schema state of the partitions in the mid state of a DDL job which rewrites
+	// the partitions, such as REORGANIZE PARTITION.
+	DDLState SchemaState `json:"ddl_state"`
+	// DDLAction is the type of the DDL job which is rewriting the partitions.
+	DDLAction ActionType `json:"ddl_action"`
+	// DDLType, DDLExpr and DDLColumns are the partitioning method on the other side of the DDL job
+	// which rewrites the partitions, they are different from the current ones when the job changes
+	// the partitioning method, such as ALTER TABLE ... PARTITION BY and REMOVE PARTITIONING.
+	DDLType    PartitionType `json:"ddl_type"`
+	DDLExpr    string        `json:"ddl_expr"`
+	DDLColumns []CIStr       `json:"ddl_columns"`
+
+	// Interval is the INTERVAL config of a RANGE partitioned table, it is nil for other tables.
+	Interval *PartitionIntervalInfo `json:"interval"`
+}
+
+// PartitionIntervalInfo records the INTERVAL config of a RANGE partitioned table. The bounds
+// of two adjacent partitions differ by the interval, except the NULL and MAXVALUE partitions.
+type PartitionIntervalInfo struct {
+	IntervalExprStr string `json:"interval_expr"`
+	// IntervalTimeUnit is the value of ast.TimeUnitType, it is 0 if the interval is not a time interval.
+	IntervalTimeUnit int  `json:"interval_time_unit"`
+	NullPart         bool `json:"null_part"`
+	MaxValPart       bool `json:"max_val_part"`
+	// Precreate is the number of the partitions kept ahead of the current time by the DDL owner.
+	Precreate uint64 `json:"precreate"`
+	// Retention is the number of the intervals of data kept, the older partitions are dropped by the DDL owner.
+	Retention uint64 `json:"retention"`
+}
+
+// IsAutoManaged returns whether the partitions are added or dropped in background.
+func (p *PartitionIntervalInfo) IsAutoManaged() bool {
+	return p.Precreate > 0 || p.Retention > 0
 }
 
 // GetNameByID gets the partition name by ID.
@@ -986,6 +1042,8 @@ func (t IndexType) String() string {
 		return "HASH"
 	case IndexTypeRtree:
 		return "RTREE"
+	case IndexTypeHypo:
+		return "HYPO"
 	default:
 		return ""
 	}
@@ -997,6 +1055,9 @@ const (
 	IndexTypeBtree
 	IndexTypeHash
 	IndexTypeRtree
+	// IndexTypeHypo is the type of the hypothetical index, which only exists in the view of the optimizer and
+	// is never written to the storage.
+	IndexTypeHypo
 )
 
 // IndexInfo provides meta data describing a DB index.
@@ -1014,6 +1075,42 @@ type IndexInfo struct {
 	Primary   bool           `json:"is_primary"`   // Whether the index is primary key.
 	Invisible bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global    bool           `json:"is_global"`    // Whether the index is global.
+	// BackfillState is the state of the backfilling when the index is added by ingesting.
+	BackfillState BackfillState `json:"backfill_state"`
+}
+
+// BackfillState is the state of the backfilling when an index is added by ingesting.
+//
+// The index entries of the rows are ingested into the index, and the index entries written by the
+// concurrent DML are kept in a temporary index. The temporary index is merged into the index before
+// the index becomes public.
+type BackfillState byte
+
+const (
+	// BackfillStateInapplicable means the index isn't added by ingesting.
+	BackfillStateInapplicable BackfillState = iota
+	// BackfillStateRunning means the rows are being backfilled, the DML only writes the temporary index.
+	BackfillStateRunning
+	// BackfillStateReadyToMerge means the backfilling is done, the DML writes both the temporary index and the index.
+	BackfillStateReadyToMerge
+	// BackfillStateMerging means the temporary index is being merged, the DML writes both the temporary index and the index.
+	BackfillStateMerging
+)
+
+// String implements fmt.Stringer interface.
+func (s BackfillState) String() string {
+	switch s {
+	case BackfillStateRunning:
+		return "backfill state running"
+	case BackfillStateReadyToMerge:
+		return "backfill state ready to merge"
+	case BackfillStateMerging:
+		return "backfill state merging"
+	case BackfillStateInapplicable:
+		return "backfill state inapplicable"
+	default:
+		return "backfill state unknown"
+	}
 }
 
 // Clone clones IndexInfo.
@@ -1070,14 +1167,18 @@ func (t *TableInfo) FindConstraintInfoByName(constrName string) *ConstraintInfo
 
 // FKInfo provides meta data describing a foreign key constraint.
 type FKInfo struct {
-	ID       int64       `json:"id"`
-	Name     CIStr       `json:"fk_name"`
-	RefTable CIStr       `json:"ref_table"`
-	RefCols  []CIStr     `json:"ref_cols"`
-	Cols     []CIStr     `json:"cols"`
-	OnDelete int         `json:"on_delete"`
-	OnUpdate int         `json:"on_update"`
-	State    SchemaState `json:"state"`
+	ID       int64 `json:"id"`
+	Name     CIStr `json:"fk_name"`
+	RefTable CIStr `json:"ref_table"`
+	// RefSchema is the schema of the referenced table, it's empty for the
+	// foreign keys created by the old versions, which means the same schema
+	// as the child table.
+	RefSchema CIStr       `json:"ref_schema"`
+	RefCols   []CIStr     `json:"ref_cols"`
+	Cols      []CIStr     `json:"cols"`
+	OnDelete  int         `json:"on_delete"`
+	OnUpdate  int         `json:"on_update"`
+	State     SchemaState `json:"state"`
 }
 
 // Clone clones FKInfo.
@@ -1092,6 +1193,14 @@ func (fk *FKInfo) Clone() *FKInfo {
 	return &nfk
 }
 
+// ReferredFKInfo provides the foreign key in the child table which refers to the parent table.
+type ReferredFKInfo struct {
+	Cols        []CIStr `json:"cols"`
+	ChildSchema CIStr   `json:"child_schema"`
+	ChildTable  CIStr   `json:"child_table"`
+	ChildFKName CIStr   `json:"child_fk_name"`
+}
+
 // DBInfo provides meta data describing a DB.
 type DBInfo struct {
 	ID                  int64              `json:"id"`      // Database ID
@@ -1250,6 +1359,43 @@ func (p *PlacementSettings) String() string {
 	return sb.String()
 }
 
+// DefaultTTLJobInterval is the default interval between two TTL jobs of a table.
+const DefaultTTLJobInterval = "1h"
+
+// ExchangePartitionInfo records the EXCHANGE PARTITION in progress on a table.
+type ExchangePartitionInfo struct {
+	// PartitionTableID is the ID of the partitioned table of the exchange.
+	PartitionTableID int64 `json:"partition_table_id"`
+	// PartitionName is the name of the exchanged partition.
+	PartitionName CIStr `json:"partition_name"`
+}
+
+// TTLInfo records the TTL config of a table. A row is expired when
+// `ColumnName + INTERVAL IntervalExprStr IntervalTimeUnit` is before the current time.
+type TTLInfo struct {
+	ColumnName      CIStr  `json:"column"`
+	IntervalExprStr string `json:"interval_expr"`
+	// IntervalTimeUnit is the value of ast.TimeUnitType, the ast package can't be imported here.
+	IntervalTimeUnit int  `json:"interval_time_unit"`
+	Enable           bool `json:"enable"`
+	// JobInterval is the interval between two TTL jobs of the table, e.g. "1h".
+	JobInterval string `json:"job_interval"`
+}
+
+// Clone clones TTLInfo.
+func (t *TTLInfo) Clone() *TTLInfo {
+	cloned := *t
+	return &cloned
+}
+
+// GetJobInterval parses the job interval, the default interval is returned if it is not set.
+func (t *TTLInfo) GetJobInterval() (time.Duration, error) {
+	if len(t.JobInterval) == 0 {
+		return time.ParseDuration(DefaultTTLJobInterval)
+	}
+	return time.ParseDuration(t.JobInterval)
+}
+
 type StatsOptions struct {
 	*StatsWindowSettings
 	AutoRecalc   bool         `json:"auto_recalc"`
@@ -193,6 +193,65 @@ func TestJobCodec(t *testing.T) {
 	isDependent, err = job2.IsDependentOn(job1)
 	require.NoError(t, err)
 	require.True(t, isDependent)
+	// job3: exchange partition of table 4 with table 5, the job isn't encoded yet
+	// job4: add index on table 4
+	job3 := &Job{
+		ID:         4,
+		TableID:    5,
+		SchemaID:   1,
+		Type:       ActionExchangeTablePartition,
+		BinlogInfo: &HistoryInfo{},
+		Args:       []interface{}{int64(6), int64(1), int64(4), "p0", true},
+	}
+	job4 := &Job{
+		ID:         5,
+		TableID:    4,
+		SchemaID:   1,
+		Type:       ActionAddIndex,
+		BinlogInfo: &HistoryInfo{},
+	}
+	isDependent, err = job3.IsDependentOn(job4)
+	require.NoError(t, err)
+	require.True(t, isDependent)
+	require.Len(t, job3.Args, 5)
+	isDependent, err = job3.IsDependentOn(job)
+	require.NoError(t, err)
+	require.False(t, isDependent)
+	// job5: rename tables 7 and 4
+	t7, t4, test := NewCIStr("t7"), NewCIStr("t4"), NewCIStr("test")
+	job5 := &Job{
+		ID:         6,
+		TableID:    7,
+		SchemaID:   1,
+		Type:       ActionRenameTables,
+		BinlogInfo: &HistoryInfo{},
+		Args:       []interface{}{[]int64{1, 1}, []int64{1, 1}, []*CIStr{&t7, &t4}, []int64{7, 4}, []*CIStr{&test, &test}},
+	}
+	isDependent, err = job4.IsDependentOn(job5)
+	require.NoError(t, err)
+	require.True(t, isDependent)
+	// job6: add foreign key on table 8 referring to table 4 in schema 9
+	// job7: drop schema 9
+	job6 := &Job{
+		ID:         7,
+		TableID:    8,
+		SchemaID:   1,
+		Type:       ActionAddForeignKey,
+		BinlogInfo: &HistoryInfo{},
+		Args:       []interface{}{&FKInfo{Name: NewCIStr("fk")}, int64(9), int64(4)},
+	}
+	isDependent, err = job6.IsDependentOn(job4)
+	require.NoError(t, err)
+	require.True(t, isDependent)
+	job7 := &Job{
+		ID:         8,
+		SchemaID:   9,
+		Type:       ActionDropSchema,
+		BinlogInfo: &HistoryInfo{},
+	}
+	isDependent, err = job7.IsDependentOn(job6)
+	require.NoError(t, err)
+	require.True(t, isDependent)
 
 	require.Equal(t, false, job.IsCancelled())
 	b, err := job.Encode(false)
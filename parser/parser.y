@@ -368,6 +368,7 @@ import (
 	deallocate            "DEALLOCATE"
 	definer               "DEFINER"
 	delayKeyWrite         "DELAY_KEY_WRITE"
+	digest                "DIGEST"
 	directory             "DIRECTORY"
 	disable               "DISABLE"
 	discard               "DISCARD"
@@ -412,6 +413,7 @@ import (
 	history               "HISTORY"
 	hosts                 "HOSTS"
 	hour                  "HOUR"
+	hypo                  "HYPO"
 	identified            "IDENTIFIED"
 	identSQLErrors        "ERRORS"
 	importKwd             "IMPORT"
@@ -491,12 +493,14 @@ import (
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
 	percent               "PERCENT"
+	pause                 "PAUSE"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
 	pipesAsOr
 	plugins               "PLUGINS"
 	policy                "POLICY"
 	preSplitRegions       "PRE_SPLIT_REGIONS"
+	precreate             "PRECREATE"
 	preceding             "PRECEDING"
 	prepare               "PREPARE"
 	preserve              "PRESERVE"
@@ -529,6 +533,7 @@ import (
 	restore               "RESTORE"
 	restores              "RESTORES"
 	resume                "RESUME"
+	retention             "RETENTION"
 	reverse               "REVERSE"
 	role                  "ROLE"
 	rollback              "ROLLBACK"
@@ -537,6 +542,7 @@ import (
 	rowFormat             "ROW_FORMAT"
 	rtree                 "RTREE"
 	san                   "SAN"
+	savepoint             "SAVEPOINT"
 	second                "SECOND"
 	secondaryEngine       "SECONDARY_ENGINE"
 	secondaryLoad         "SECONDARY_LOAD"
@@ -604,6 +610,9 @@ import (
 	transaction           "TRANSACTION"
 	triggers              "TRIGGERS"
 	truncate              "TRUNCATE"
+	ttl                   "TTL"
+	ttlEnable             "TTL_ENABLE"
+	ttlJobInterval        "TTL_JOB_INTERVAL"
 	unbounded             "UNBOUNDED"
 	uncommitted           "UNCOMMITTED"
 	undefined             "UNDEFINED"
@@ -897,10 +906,12 @@ import (
 	KillStmt                   "Kill statement"
 	LoadDataStmt               "Load data statement"
 	LoadStatsStmt              "Load statistic statement"
+	LockStatsStmt              "Lock statistic statement"
 	LockTablesStmt             "Lock tables statement"
 	PlanReplayerStmt           "Plan replayer statement"
 	PreparedStmt               "PreparedStmt"
 	PurgeImportStmt            "PURGE IMPORT statement that removes a IMPORT task record"
+	RestoreStatsStmt           "Restore statistic statement"
 	SelectStmt                 "SELECT statement"
 	SelectStmtWithClause       "common table expression SELECT statement"
 	RenameTableStmt            "rename table statement"
@@ -911,6 +922,8 @@ import (
 	RevokeStmt                 "Revoke statement"
 	RevokeRoleStmt             "Revoke role statement"
 	RollbackStmt               "ROLLBACK statement"
+	SavepointStmt              "SAVEPOINT statement"
+	ReleaseSavepointStmt       "RELEASE SAVEPOINT statement"
 	SplitRegionStmt            "Split index region statement"
 	SetStmt                    "Set variable statement"
 	ChangeStmt                 "Change statement"
@@ -923,6 +936,7 @@ import (
 	TraceStmt                  "TRACE statement"
 	TraceableStmt              "traceable statement"
 	TruncateTableStmt          "TRUNCATE TABLE statement"
+	UnlockStatsStmt            "Unlock statistic statement"
 	UnlockTablesStmt           "Unlock tables statement"
 	UpdateStmt                 "UPDATE statement"
 	SetOprStmt                 "Union/Except/Intersect select statement"
@@ -1098,6 +1112,13 @@ import (
 	PartitionNameList                      "Partition name list"
 	PartitionNameListOpt                   "table partition names list optional"
 	PartitionNumOpt                        "PARTITION NUM option"
+	PartitionIntervalOpt                   "INTERVAL partitioning option"
+	IntervalExpr                           "Interval between the bounds of two adjacent partitions"
+	FirstAndLastPartOpt                    "FIRST and LAST PARTITION LESS THAN option"
+	NullPartOpt                            "NULL PARTITION option"
+	MaxValPartOpt                          "MAXVALUE PARTITION option"
+	PrecreateOpt                           "PRECREATE n option"
+	RetentionOpt                           "RETENTION n option"
 	PartDefValuesOpt                       "VALUES {LESS THAN {(expr | value_list) | MAXVALUE} | IN {value_list}"
 	PartDefOptionList                      "PartDefOption list"
 	PartDefOption                          "COMMENT [=] xxx | TABLESPACE [=] tablespace_name | ENGINE [=] xxx"
@@ -1424,6 +1445,8 @@ import (
 %precedence local
 %precedence lowerThanRemove
 %precedence remove
+%precedence lowerThanFirst
+%precedence first
 %precedence lowerThenOrder
 %precedence order
 %precedence lowerThanFunction
@@ -1626,6 +1649,38 @@ AlterTablePartitionOpt:
 			Tp: ast.AlterTableRemovePartitioning,
 		}
 	}
+|	"REMOVE" "TTL"
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp: ast.AlterTableRemoveTTL,
+		}
+	}
+|	"FIRST" "PARTITION" "LESS" "THAN" '(' BitExpr ')'
+	{
+		partInterval := &ast.PartitionInterval{FirstRangeEnd: $6}
+		$$ = &ast.AlterTableSpec{
+			Tp: ast.AlterTableDropFirstPartition,
+			Partition: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{
+					Tp:       model.PartitionTypeRange,
+					Interval: partInterval,
+				},
+			},
+		}
+	}
+|	"LAST" "PARTITION" "LESS" "THAN" '(' BitExpr ')'
+	{
+		partInterval := &ast.PartitionInterval{LastRangeEnd: $6}
+		$$ = &ast.AlterTableSpec{
+			Tp: ast.AlterTableAddLastPartition,
+			Partition: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{
+					Tp:       model.PartitionTypeRange,
+					Interval: partInterval,
+				},
+			},
+		}
+	}
 |	"REORGANIZE" "PARTITION" NoWriteToBinLogAliasOpt ReorganizePartitionRuleOpt
 	{
 		ret := $4.(*ast.AlterTableSpec)
@@ -2164,7 +2219,6 @@ AlterTableSpec:
 	}
 |	"DROP" CheckConstraintKeyword Identifier
 	{
-		// Parse it and ignore it. Just for compatibility.
 		c := &ast.Constraint{
 			Name: $3,
 		}
@@ -2315,6 +2369,7 @@ ColumnKeywordOpt:
 |	"COLUMN"
 
 ColumnPosition:
+	/* empty */ %prec lowerThanFirst
 	{
 		$$ = &ast.ColumnPosition{Tp: ast.ColumnPositionNone}
 	}
@@ -3825,18 +3880,22 @@ PartitionKeyAlgorithmOpt:
 
 PartitionMethod:
 	SubPartitionMethod
-|	"RANGE" '(' BitExpr ')'
+|	"RANGE" '(' BitExpr ')' PartitionIntervalOpt
 	{
+		partitionInterval, _ := $5.(*ast.PartitionInterval)
 		$$ = &ast.PartitionMethod{
-			Tp:   model.PartitionTypeRange,
-			Expr: $3.(ast.ExprNode),
+			Tp:       model.PartitionTypeRange,
+			Expr:     $3.(ast.ExprNode),
+			Interval: partitionInterval,
 		}
 	}
-|	"RANGE" FieldsOrColumns '(' ColumnNameList ')'
+|	"RANGE" FieldsOrColumns '(' ColumnNameList ')' PartitionIntervalOpt
 	{
+		partitionInterval, _ := $6.(*ast.PartitionInterval)
 		$$ = &ast.PartitionMethod{
 			Tp:          model.PartitionTypeRange,
 			ColumnNames: $4.([]*ast.ColumnName),
+			Interval:    partitionInterval,
 		}
 	}
 |	"LIST" '(' BitExpr ')'
@@ -3875,6 +3934,81 @@ PartitionMethod:
 		}
 	}
 
+PartitionIntervalOpt:
+	{
+		$$ = nil
+	}
+|	"INTERVAL" '(' IntervalExpr ')' FirstAndLastPartOpt NullPartOpt MaxValPartOpt PrecreateOpt RetentionOpt
+	{
+		partitionInterval := &ast.PartitionInterval{
+			IntervalExpr: $3.(ast.PartitionIntervalExpr),
+			NullPart:     $6.(bool),
+			MaxValPart:   $7.(bool),
+			Precreate:    $8.(uint64),
+			Retention:    $9.(uint64),
+		}
+		if firstAndLast, ok := $5.([]ast.ExprNode); ok {
+			partitionInterval.FirstRangeEnd = firstAndLast[0]
+			partitionInterval.LastRangeEnd = firstAndLast[1]
+		}
+		$$ = partitionInterval
+	}
+
+IntervalExpr:
+	BitExpr
+	{
+		$$ = ast.PartitionIntervalExpr{Expr: $1, TimeUnit: ast.TimeUnitInvalid}
+	}
+|	BitExpr TimeUnit
+	{
+		$$ = ast.PartitionIntervalExpr{Expr: $1, TimeUnit: $2.(ast.TimeUnitType)}
+	}
+
+FirstAndLastPartOpt:
+	{
+		$$ = nil
+	}
+|	"FIRST" "PARTITION" "LESS" "THAN" '(' BitExpr ')' "LAST" "PARTITION" "LESS" "THAN" '(' BitExpr ')'
+	{
+		$$ = []ast.ExprNode{$6, $13}
+	}
+
+NullPartOpt:
+	{
+		$$ = false
+	}
+|	"NULL" "PARTITION"
+	{
+		$$ = true
+	}
+
+MaxValPartOpt:
+	{
+		$$ = false
+	}
+|	"MAXVALUE" "PARTITION"
+	{
+		$$ = true
+	}
+
+PrecreateOpt:
+	{
+		$$ = uint64(0)
+	}
+|	"PRECREATE" LengthNum
+	{
+		$$ = $2.(uint64)
+	}
+
+RetentionOpt:
+	{
+		$$ = uint64(0)
+	}
+|	"RETENTION" LengthNum
+	{
+		$$ = $2.(uint64)
+	}
+
 LinearOpt:
 	{
 		$$ = ""
@@ -4425,6 +4559,10 @@ DropIndexStmt:
 		}
 		$$ = &ast.DropIndexStmt{IfExists: $3.(bool), IndexName: $4, Table: $6.(*ast.TableName), LockAlg: indexLockAndAlgorithm}
 	}
+|	"DROP" "HYPO" "INDEX" IfExists Identifier "ON" TableName
+	{
+		$$ = &ast.DropIndexStmt{IfExists: $4.(bool), IndexName: $5, Table: $7.(*ast.TableName), IsHypo: true}
+	}
 
 DropTableStmt:
 	"DROP" OptTemporary TableOrTables IfExists TableNameList RestrictOrCascadeOpt
@@ -5706,6 +5844,10 @@ IndexTypeName:
 	{
 		$$ = model.IndexTypeRtree
 	}
+|	"HYPO"
+	{
+		$$ = model.IndexTypeHypo
+	}
 
 IndexInvisible:
 	"VISIBLE"
@@ -5752,6 +5894,7 @@ UnReservedKeyword:
 |	"COLUMNS"
 |	"CONFIG"
 |	"SAN"
+|	"SAVEPOINT"
 |	"COMMIT"
 |	"COMPACT"
 |	"COMPRESSED"
@@ -5791,6 +5934,7 @@ UnReservedKeyword:
 |	"HASH"
 |	"HELP"
 |	"HOUR"
+|	"HYPO"
 |	"INSERT_METHOD"
 |	"LESS"
 |	"LOCAL"
@@ -5830,6 +5974,12 @@ UnReservedKeyword:
 |	"TRACE"
 |	"TRANSACTION"
 |	"TRUNCATE"
+|	"TTL"
+|	"TTL_ENABLE"
+|	"TTL_JOB_INTERVAL"
+|	"PRECREATE"
+|	"RETENTION"
+|	"PAUSE"
 |	"UNBOUNDED"
 |	"UNKNOWN"
 |	"VALUE" %prec lowerThanValueKeyword
@@ -5952,6 +6102,7 @@ UnReservedKeyword:
 |	"TRADITIONAL"
 |	"SQL_BUFFER_RESULT"
 |	"DIRECTORY"
+|	"DIGEST"
 |	"HISTOGRAM"
 |	"HISTORY"
 |	"LIST"
@@ -8006,6 +8157,26 @@ RollbackStmt:
 	{
 		$$ = &ast.RollbackStmt{CompletionType: $2.(ast.CompletionType)}
 	}
+|	"ROLLBACK" "TO" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $3}
+	}
+|	"ROLLBACK" "TO" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.RollbackStmt{SavepointName: $4}
+	}
+
+SavepointStmt:
+	"SAVEPOINT" Identifier
+	{
+		$$ = &ast.SavepointStmt{Name: $2}
+	}
+
+ReleaseSavepointStmt:
+	"RELEASE" "SAVEPOINT" Identifier
+	{
+		$$ = &ast.ReleaseSavepointStmt{Name: $3}
+	}
 
 CompletionTypeWithinTransaction:
 	"AND" "CHAIN" "NO" "RELEASE"
@@ -10055,6 +10226,20 @@ AdminStmt:
 			JobIDs: $5.([]int64),
 		}
 	}
+|	"ADMIN" "PAUSE" "DDL" "JOBS" NumList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminPauseDDLJobs,
+			JobIDs: $5.([]int64),
+		}
+	}
+|	"ADMIN" "RESUME" "DDL" "JOBS" NumList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminResumeDDLJobs,
+			JobIDs: $5.([]int64),
+		}
+	}
 |	"ADMIN" "SHOW" "DDL" "JOB" "QUERIES" NumList
 	{
 		$$ = &ast.AdminStmt{
@@ -10976,6 +11161,8 @@ Statement:
 |	PreparedStmt
 |	PurgeImportStmt
 |	RollbackStmt
+|	SavepointStmt
+|	ReleaseSavepointStmt
 |	RenameTableStmt
 |	RenameUserStmt
 |	ReplaceIntoStmt
@@ -11012,6 +11199,9 @@ Statement:
 |	UseStmt
 |	UnlockTablesStmt
 |	LockTablesStmt
+|	LockStatsStmt
+|	UnlockStatsStmt
+|	RestoreStatsStmt
 |	ShutdownStmt
 |	RestartStmt
 |	HelpStmt
@@ -11173,6 +11363,28 @@ TableOption:
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionAutoIdCache, UintValue: $3.(uint64)}
 	}
+|	"TTL" EqOpt ColumnName '+' "INTERVAL" Expression TimeUnit
+	{
+		$$ = &ast.TableOption{
+			Tp:            ast.TableOptionTTL,
+			ColumnName:    $3.(*ast.ColumnName),
+			Expr:          $6,
+			TimeUnitValue: &ast.TimeUnitExpr{Unit: $7.(ast.TimeUnitType)},
+		}
+	}
+|	"TTL_ENABLE" EqOpt stringLit
+	{
+		onOrOff := strings.ToLower($3)
+		if onOrOff != "on" && onOrOff != "off" {
+			yylex.AppendError(yylex.Errorf("The value of TTL_ENABLE must be one of [ON|OFF]."))
+			return 1
+		}
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLEnable, BoolValue: onOrOff == "on"}
+	}
+|	"TTL_JOB_INTERVAL" EqOpt stringLit
+	{
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLJobInterval, StrValue: $3}
+	}
 |	ForceOpt "AUTO_RANDOM_BASE" EqOpt LengthNum
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionAutoRandomBase, UintValue: $4.(uint64), BoolValue: $1.(bool)}
@@ -12587,6 +12799,7 @@ BindableStmt:
  *
  *  Example:
  *      CREATE GLOBAL BINDING FOR select Col1,Col2 from table USING select Col1,Col2 from table use index(Col1)
+ *      CREATE GLOBAL BINDING FROM HISTORY USING PLAN DIGEST 'plan_digest'
  *******************************************************************/
 CreateBindingStmt:
 	"CREATE" GlobalScope "BINDING" "FOR" BindableStmt "USING" BindableStmt
@@ -12606,6 +12819,15 @@ CreateBindingStmt:
 			GlobalScope: $2.(bool),
 		}
 
+		$$ = x
+	}
+|	"CREATE" GlobalScope "BINDING" "FROM" "HISTORY" "USING" "PLAN" "DIGEST" stringLit
+	{
+		x := &ast.CreateBindingStmt{
+			GlobalScope: $2.(bool),
+			PlanDigest:  $9,
+		}
+
 		$$ = x
 	}
 
@@ -13377,6 +13599,31 @@ LoadStatsStmt:
 		}
 	}
 
+LockStatsStmt:
+	"LOCK" "STATS" TableNameList
+	{
+		$$ = &ast.LockStatsStmt{
+			Tables: $3.([]*ast.TableName),
+		}
+	}
+
+UnlockStatsStmt:
+	"UNLOCK" "STATS" TableNameList
+	{
+		$$ = &ast.UnlockStatsStmt{
+			Tables: $3.([]*ast.TableName),
+		}
+	}
+
+RestoreStatsStmt:
+	"RESTORE" "STATS" TableName AsOfClause
+	{
+		$$ = &ast.RestoreStatsStmt{
+			Table: $3.(*ast.TableName),
+			AsOf:  $4.(*ast.AsOfClause),
+		}
+	}
+
 DropPolicyStmt:
 	"DROP" "PLACEMENT" "POLICY" IfExists PolicyName
 	{
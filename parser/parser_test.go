@@ -83,7 +83,7 @@ func TestSimple(t *testing.T) {
 	unreservedKws := []string{
 		"auto_increment", "after", "begin", "bit", "bool", "boolean", "charset", "columns", "commit",
 		"date", "datediff", "datetime", "deallocate", "do", "from_days", "end", "engine", "engines", "execute", "extended", "first", "file", "full",
-		"local", "names", "offset", "password", "prepare", "quick", "rollback", "session", "signed",
+		"local", "names", "offset", "password", "prepare", "quick", "rollback", "savepoint", "session", "signed",
 		"start", "global", "tables", "tablespace", "target", "text", "time", "timestamp", "tidb", "transaction", "truncate", "unknown",
 		"value", "warnings", "year", "now", "substr", "subpartition", "subpartitions", "substring", "mode", "any", "some", "user", "identified",
 		"collation", "comment", "avg_row_length", "checksum", "compression", "connection", "key_block_size",
@@ -536,6 +536,15 @@ func TestDMLStmt(t *testing.T) {
 		{"ROLLBACK AND NO CHAIN RELEASE", true, "ROLLBACK RELEASE"},
 		{"ROLLBACK AND CHAIN NO RELEASE", true, "ROLLBACK AND CHAIN"},
 		{"ROLLBACK AND CHAIN RELEASE", false, ""},
+		{"ROLLBACK TO s1", true, "ROLLBACK TO `s1`"},
+		{"ROLLBACK TO SAVEPOINT s1", true, "ROLLBACK TO `s1`"},
+		{"ROLLBACK TO SAVEPOINT", true, "ROLLBACK TO `SAVEPOINT`"},
+		{"ROLLBACK TO", false, ""},
+		{"SAVEPOINT s1", true, "SAVEPOINT `s1`"},
+		{"SAVEPOINT `savepoint`", true, "SAVEPOINT `savepoint`"},
+		{"SAVEPOINT", false, ""},
+		{"RELEASE SAVEPOINT s1", true, "RELEASE SAVEPOINT `s1`"},
+		{"RELEASE s1", false, ""},
 		{`BEGIN;
 			INSERT INTO foo VALUES (42, 3.14);
 			INSERT INTO foo VALUES (-1, 2.78);
@@ -792,6 +801,11 @@ func TestDMLStmt(t *testing.T) {
 		{"admin checksum table t1, t2;", true, "ADMIN CHECKSUM TABLE `t1`, `t2`"},
 		{"admin cancel ddl jobs 1", true, "ADMIN CANCEL DDL JOBS 1"},
 		{"admin cancel ddl jobs 1, 2", true, "ADMIN CANCEL DDL JOBS 1, 2"},
+		{"admin pause ddl jobs 1", true, "ADMIN PAUSE DDL JOBS 1"},
+		{"admin pause ddl jobs 1, 2", true, "ADMIN PAUSE DDL JOBS 1, 2"},
+		{"admin resume ddl jobs 1", true, "ADMIN RESUME DDL JOBS 1"},
+		{"admin resume ddl jobs 1, 2", true, "ADMIN RESUME DDL JOBS 1, 2"},
+		{"admin pause ddl jobs", false, ""},
 		{"admin recover index t1 idx_a", true, "ADMIN RECOVER INDEX `t1` idx_a"},
 		{"admin cleanup index t1 idx_a", true, "ADMIN CLEANUP INDEX `t1` idx_a"},
 		{"admin show slow top 3", true, "ADMIN SHOW SLOW TOP 3"},
@@ -1151,6 +1165,18 @@ func TestDBAStmt(t *testing.T) {
 
 		// for load stats
 		{"load stats '/tmp/stats.json'", true, "LOAD STATS '/tmp/stats.json'"},
+		// for lock and unlock stats
+		{"lock stats t", true, "LOCK STATS `t`"},
+		{"lock stats t1, test.t2", true, "LOCK STATS `t1`, `test`.`t2`"},
+		{"unlock stats t", true, "UNLOCK STATS `t`"},
+		{"unlock stats t1, test.t2", true, "UNLOCK STATS `t1`, `test`.`t2`"},
+		{"lock stats", false, ""},
+		{"unlock stats", false, ""},
+		// for restore stats
+		{"restore stats t as of timestamp '2022-03-01 16:30:00'", true, "RESTORE STATS `t` AS OF TIMESTAMP _UTF8MB4'2022-03-01 16:30:00'"},
+		{"restore stats test.t as of timestamp now() - interval 1 day", true, "RESTORE STATS `test`.`t` AS OF TIMESTAMP DATE_SUB(NOW(), INTERVAL 1 DAY)"},
+		{"restore stats t", false, ""},
+		{"restore stats t1, t2 as of timestamp now()", false, ""},
 		// set
 		// user defined
 		{"SET @ = 1", true, "SET @``=1"},
@@ -2945,6 +2971,8 @@ func TestDDL(t *testing.T) {
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE BTREE", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE HASH", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING HASH"},
 		{"CREATE UNIQUE INDEX ident ON d_n.t_n ( ident , ident ASC ) TYPE RTREE", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING RTREE"},
+		{"CREATE INDEX idx ON t (a, b) TYPE HYPO", true, "CREATE INDEX `idx` ON `t` (`a`, `b`) USING HYPO"},
+		{"CREATE UNIQUE INDEX idx ON db.t (a) USING HYPO", true, "CREATE UNIQUE INDEX `idx` ON `db`.`t` (`a`) USING HYPO"},
 		{"CREATE UNIQUE INDEX ident TYPE BTREE ON d_n.t_n ( ident , ident ASC )", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE UNIQUE INDEX ident USING BTREE ON d_n.t_n ( ident , ident ASC )", true, "CREATE UNIQUE INDEX `ident` ON `d_n`.`t_n` (`ident`, `ident`) USING BTREE"},
 		{"CREATE SPATIAL INDEX idx ON t (a)", true, "CREATE SPATIAL INDEX `idx` ON `t` (`a`)"},
@@ -2988,6 +3016,10 @@ func TestDDL(t *testing.T) {
 		{"drop index a on db.`tb-ttb`", true, "DROP INDEX `a` ON `db`.`tb-ttb`"},
 		{"drop index if exists a on t", true, "DROP INDEX IF EXISTS `a` ON `t`"},
 		{"drop index if exists a on db.t", true, "DROP INDEX IF EXISTS `a` ON `db`.`t`"},
+		{"drop hypo index a on t", true, "DROP HYPO INDEX `a` ON `t`"},
+		{"drop hypo index if exists a on db.t", true, "DROP HYPO INDEX IF EXISTS `a` ON `db`.`t`"},
+		{"drop hypo index a on t algorithm = inplace", false, ""},
+		{"create table hypo (hypo int)", true, "CREATE TABLE `hypo` (`hypo` INT)"},
 		{"drop index if exists a on db.`tb-ttb`", true, "DROP INDEX IF EXISTS `a` ON `db`.`tb-ttb`"},
 		{"drop index idx on t algorithm = default", true, "DROP INDEX `idx` ON `t`"},
 		{"drop index idx on t algorithm default", true, "DROP INDEX `idx` ON `t`"},
@@ -3335,6 +3367,15 @@ func TestDDL(t *testing.T) {
 		{"create table t (a int auto_increment key) auto_id_cache 10", true, "CREATE TABLE `t` (`a` INT AUTO_INCREMENT PRIMARY KEY) AUTO_ID_CACHE = 10"},
 		{"create table t (a bigint, b varchar(255)) auto_id_cache 50", true, "CREATE TABLE `t` (`a` BIGINT,`b` VARCHAR(255)) AUTO_ID_CACHE = 50"},
 
+		// for ttl
+		{"create table t (created_at datetime) ttl = created_at + interval 30 day", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 30 DAY"},
+		{"create table t (created_at datetime) ttl created_at + interval 2 hour ttl_enable = 'off' ttl_job_interval = '1h'", true, "CREATE TABLE `t` (`created_at` DATETIME) TTL = `created_at` + INTERVAL 2 HOUR TTL_ENABLE = 'OFF' TTL_JOB_INTERVAL = '1h'"},
+		{"create table t (created_at datetime) ttl_enable = 'no'", false, ""},
+		{"alter table t ttl = created_at + interval 1 month", true, "ALTER TABLE `t` TTL = `created_at` + INTERVAL 1 MONTH"},
+		{"alter table t ttl_enable = 'on'", true, "ALTER TABLE `t` TTL_ENABLE = 'ON'"},
+		{"alter table t remove ttl", true, "ALTER TABLE `t` REMOVE TTL"},
+		{"alter table t comment 'x' remove ttl", true, "ALTER TABLE `t` COMMENT = 'x' REMOVE TTL"},
+
 		// for auto_random_id
 		{"create table t (a bigint auto_random(3) primary key) auto_random_base = 10", true, "CREATE TABLE `t` (`a` BIGINT AUTO_RANDOM(3) PRIMARY KEY) AUTO_RANDOM_BASE = 10"},
 		{"create table t (a bigint primary key auto_random(4), b varchar(100)) auto_random_base 200", true, "CREATE TABLE `t` (`a` BIGINT PRIMARY KEY AUTO_RANDOM(4),`b` VARCHAR(100)) AUTO_RANDOM_BASE = 200"},
@@ -4753,6 +4794,12 @@ func TestBinding(t *testing.T) {
 		{"create session binding for select 1 union select 2 intersect select 3 using select 1 union select 2 intersect select 3", true, "CREATE SESSION BINDING FOR SELECT 1 UNION SELECT 2 INTERSECT SELECT 3 USING SELECT 1 UNION SELECT 2 INTERSECT SELECT 3"},
 		{"drop session binding for select 1 union select 2 intersect select 3 using select 1 union select 2 intersect select 3", true, "DROP SESSION BINDING FOR SELECT 1 UNION SELECT 2 INTERSECT SELECT 3 USING SELECT 1 UNION SELECT 2 INTERSECT SELECT 3"},
 		{"drop session binding for select 1 union select 2 intersect select 3", true, "DROP SESSION BINDING FOR SELECT 1 UNION SELECT 2 INTERSECT SELECT 3"},
+		{"create global binding from history using plan digest 'ab3d5a0ff4c8'", true, "CREATE GLOBAL BINDING FROM HISTORY USING PLAN DIGEST 'ab3d5a0ff4c8'"},
+		{"create session binding from history using plan digest 'ab3d5a0ff4c8'", true, "CREATE SESSION BINDING FROM HISTORY USING PLAN DIGEST 'ab3d5a0ff4c8'"},
+		{"create binding from history using plan digest 'ab3d5a0ff4c8'", true, "CREATE SESSION BINDING FROM HISTORY USING PLAN DIGEST 'ab3d5a0ff4c8'"},
+		{"create global binding from history using plan digest ab3d5a0ff4c8", false, ""},
+		{"create global binding from history using plan 'ab3d5a0ff4c8'", false, ""},
+		{"select digest from t", true, "SELECT `digest` FROM `t`"},
 		// Update cases.
 		{"CREATE GLOBAL BINDING FOR UPDATE `t` SET `a`=1 WHERE `b`=1 USING UPDATE /*+ USE_INDEX(`t` `b`)*/ `t` SET `a`=1 WHERE `b`=1", true, "CREATE GLOBAL BINDING FOR UPDATE `t` SET `a`=1 WHERE `b`=1 USING UPDATE /*+ USE_INDEX(`t` `b`)*/ `t` SET `a`=1 WHERE `b`=1"},
 		{"CREATE SESSION BINDING FOR UPDATE `t` SET `a`=1 WHERE `b`=1 USING UPDATE /*+ USE_INDEX(`t` `b`)*/ `t` SET `a`=1 WHERE `b`=1", true, "CREATE SESSION BINDING FOR UPDATE `t` SET `a`=1 WHERE `b`=1 USING UPDATE /*+ USE_INDEX(`t` `b`)*/ `t` SET `a`=1 WHERE `b`=1"},
@@ -5374,6 +5421,20 @@ func TestTablePartition(t *testing.T) {
 		{`CREATE TABLE t1 (a INT, b TIMESTAMP DEFAULT '0000-00-00 00:00:00')
 ENGINE=INNODB PARTITION BY LINEAR HASH (a) PARTITIONS 1;`, true, "CREATE TABLE `t1` (`a` INT,`b` TIMESTAMP DEFAULT _UTF8MB4'0000-00-00 00:00:00') ENGINE = INNODB PARTITION BY LINEAR HASH (`a`) PARTITIONS 1"},
 
+		// INTERVAL partitioning
+		{"create table t (a int) partition by range (a) interval (100) first partition less than (100) last partition less than (1000)", true, "CREATE TABLE `t` (`a` INT) PARTITION BY RANGE (`a`) INTERVAL (100) FIRST PARTITION LESS THAN (100) LAST PARTITION LESS THAN (1000)"},
+		{"create table t (a int) partition by range (a) interval (100) first partition less than (100) last partition less than (1000) null partition maxvalue partition", true, "CREATE TABLE `t` (`a` INT) PARTITION BY RANGE (`a`) INTERVAL (100) FIRST PARTITION LESS THAN (100) LAST PARTITION LESS THAN (1000) NULL PARTITION MAXVALUE PARTITION"},
+		{"create table t (a date) partition by range columns (a) interval (1 month) first partition less than ('2022-01-01') last partition less than ('2022-06-01') precreate 3 retention 12", true, "CREATE TABLE `t` (`a` DATE) PARTITION BY RANGE COLUMNS (`a`) INTERVAL (1 MONTH) FIRST PARTITION LESS THAN (_UTF8MB4'2022-01-01') LAST PARTITION LESS THAN (_UTF8MB4'2022-06-01') PRECREATE 3 RETENTION 12"},
+		{"create table t (a date) partition by range columns (a) interval (1 month) retention 12 (partition p0 values less than ('2022-01-01'))", true, "CREATE TABLE `t` (`a` DATE) PARTITION BY RANGE COLUMNS (`a`) INTERVAL (1 MONTH) RETENTION 12 (PARTITION `p0` VALUES LESS THAN (_UTF8MB4'2022-01-01'))"},
+		{"create table t (a int) partition by range (a) interval (100)", false, ""},
+		{"create table t (a int) partition by range (a) interval (100) first partition less than (100)", false, ""},
+		{"create table t (a int) partition by range (a) interval (100) retention 2 precreate 3 first partition less than (100) last partition less than (1000)", false, ""},
+		{"create table t (a int) partition by hash (a) interval (100) first partition less than (100) last partition less than (1000)", false, ""},
+		{"alter table t first partition less than (200)", true, "ALTER TABLE `t` FIRST PARTITION LESS THAN (200)"},
+		{"alter table t last partition less than ('2023-01-01')", true, "ALTER TABLE `t` LAST PARTITION LESS THAN (_UTF8MB4'2023-01-01')"},
+		{"alter table t add column b int first partition less than (200)", false, ""},
+		{"alter table t add column b int first, last partition less than (200)", false, ""},
+
 		// empty clause is valid only for HASH/KEY partitions
 		{"create table t1 (a int) partition by hash (a) (partition x, partition y)", true, "CREATE TABLE `t1` (`a` INT) PARTITION BY HASH (`a`) (PARTITION `x`,PARTITION `y`)"},
 		{"create table t1 (a int) partition by key (a) (partition x, partition y)", true, "CREATE TABLE `t1` (`a` INT) PARTITION BY KEY (`a`) (PARTITION `x`,PARTITION `y`)"},
@@ -5892,6 +5953,8 @@ func (checker *nodeTextCleaner) Enter(in ast.Node) (out ast.Node, skipChildren b
 				opt.StrValue = strings.ToUpper(opt.StrValue)
 			case ast.TableOptionCollate:
 				opt.StrValue = strings.ToUpper(opt.StrValue)
+			case ast.TableOptionTTL:
+				opt.Expr.Accept(checker)
 			}
 		}
 		for _, col := range node.Cols {
@@ -5947,6 +6010,11 @@ func (checker *nodeTextCleaner) Enter(in ast.Node) (out ast.Node, skipChildren b
 			if v.Tp != 0 && !(v.Tp == ast.AlterTableOption && len(v.Options) == 0) {
 				specs = append(specs, v)
 			}
+			for _, opt := range v.Options {
+				if opt.Tp == ast.TableOptionTTL {
+					opt.Expr.Accept(checker)
+				}
+			}
 		}
 		node.Specs = specs
 	case *ast.Join:
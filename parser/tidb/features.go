@@ -28,15 +28,21 @@ const (
 	FeatureIDForceAutoInc = "force_inc"
 	// FeatureIDPlacement is the `placement rule` feature.
 	FeatureIDPlacement = "placement"
+	// FeatureIDTTL is the `ttl` feature.
+	FeatureIDTTL = "ttl"
+	// FeatureIDIntervalPartition is the `interval partition` feature.
+	FeatureIDIntervalPartition = "interval_partition"
 )
 
 var featureIDs = map[string]struct{}{
-	FeatureIDAutoRandom:     {},
-	FeatureIDAutoIDCache:    {},
-	FeatureIDAutoRandomBase: {},
-	FeatureIDClusteredIndex: {},
-	FeatureIDForceAutoInc:   {},
-	FeatureIDPlacement:      {},
+	FeatureIDAutoRandom:        {},
+	FeatureIDAutoIDCache:       {},
+	FeatureIDAutoRandomBase:    {},
+	FeatureIDClusteredIndex:    {},
+	FeatureIDForceAutoInc:      {},
+	FeatureIDPlacement:         {},
+	FeatureIDTTL:               {},
+	FeatureIDIntervalPartition: {},
 }
 
 func CanParseFeature(fs ...string) bool {
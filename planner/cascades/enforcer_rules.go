@@ -22,6 +22,9 @@ import (
 	"github.com/pingcap/tidb/planner/memo"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
 )
 
 // Enforcer defines the interface for enforcer rules.
@@ -38,6 +41,12 @@ type Enforcer interface {
 // GetEnforcerRules gets all candidate enforcer rules based
 // on required physical property.
 func GetEnforcerRules(g *memo.Group, prop *property.PhysicalProperty) (enforcers []Enforcer) {
+	if g.EngineType == memo.EngineTiFlash && prop.TaskTp == property.MppTaskType && prop.MPPPartitionTp != property.AnyType {
+		if canEnforceExchange(g, prop) {
+			enforcers = append(enforcers, mppExchangeEnforcer)
+		}
+		return
+	}
 	if g.EngineType != memo.EngineTiDB {
 		return
 	}
@@ -85,3 +94,52 @@ func (e *OrderEnforcer) GetEnforceCost(g *memo.Group) float64 {
 	cost := sort.GetCost(g.Prop.Stats.RowCount, g.Prop.Schema)
 	return cost
 }
+
+// canEnforceExchange checks whether the required partitioning can be enforced by
+// an exchange. The hash exchange on the string columns is not supported when
+// the new collation is enabled, unless it is allowed by the session variable.
+func canEnforceExchange(g *memo.Group, prop *property.PhysicalProperty) bool {
+	if prop.MPPPartitionTp != property.HashType || !collate.NewCollationEnabled() {
+		return true
+	}
+	sctx := g.Equivalents.Front().Value.(*memo.GroupExpr).ExprNode.SCtx()
+	if sctx.GetSessionVars().HashExchangeWithNewCollation {
+		return true
+	}
+	for _, col := range prop.MPPPartitionCols {
+		if types.IsString(col.Col.RetType.Tp) {
+			return false
+		}
+	}
+	return true
+}
+
+// MPPExchangeEnforcer enforces the partitioning property of the MPP tasks
+// by exchanging the data between them.
+type MPPExchangeEnforcer struct {
+}
+
+var mppExchangeEnforcer = &MPPExchangeEnforcer{}
+
+// NewProperty removes the partitioning property from required physical property.
+func (e *MPPExchangeEnforcer) NewProperty(prop *property.PhysicalProperty) (newProp *property.PhysicalProperty) {
+	return &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64}
+}
+
+// OnEnforce adds the ExchangeSender and ExchangeReceiver to satisfy required partitioning property.
+func (e *MPPExchangeEnforcer) OnEnforce(reqProp *property.PhysicalProperty, child memo.Implementation) (impl memo.Implementation) {
+	childPlan := child.GetPlan()
+	sender := plannercore.PhysicalExchangeSender{
+		ExchangeType: tipb.ExchangeType(reqProp.MPPPartitionTp),
+		HashCols:     reqProp.MPPPartitionCols,
+	}.Init(childPlan.SCtx(), childPlan.Stats())
+	receiver := plannercore.PhysicalExchangeReceiver{}.Init(childPlan.SCtx(), childPlan.Stats())
+	impl = implementation.NewExchangeImpl(receiver, sender).AttachChildren(child)
+	return
+}
+
+// GetEnforceCost calculates cost of transferring the data through the network.
+func (e *MPPExchangeEnforcer) GetEnforceCost(g *memo.Group) float64 {
+	sctx := g.Equivalents.Front().Value.(*memo.GroupExpr).ExprNode.SCtx()
+	return g.Prop.Stats.RowCount * sctx.GetSessionVars().GetNetworkFactor(nil)
+}
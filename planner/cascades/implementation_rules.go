@@ -18,6 +18,8 @@ import (
 	"math"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/kv"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	impl "github.com/pingcap/tidb/planner/implementation"
 	"github.com/pingcap/tidb/planner/memo"
@@ -52,6 +54,18 @@ var defaultImplementationMap = map[memo.Operand][]ImplementationRule{
 	memo.OperandTiKVSingleGather: {
 		&ImplTiKVSingleReadGather{},
 	},
+	memo.OperandTiKVDoubleGather: {
+		&ImplTiKVDoubleReadGather{},
+	},
+	memo.OperandTiKVPointGet: {
+		&ImplTiKVPointGet{},
+	},
+	memo.OperandTiKVIndexMergeGather: {
+		&ImplTiKVIndexMergeGather{},
+	},
+	memo.OperandTiFlashMPPGather: {
+		&ImplTiFlashMPPGather{},
+	},
 	memo.OperandShow: {
 		&ImplShow{},
 	},
@@ -75,6 +89,7 @@ var defaultImplementationMap = map[memo.Operand][]ImplementationRule{
 		&ImplHashJoinBuildLeft{},
 		&ImplHashJoinBuildRight{},
 		&ImplMergeJoin{},
+		&ImplTiFlashMPPHashJoin{},
 	},
 	memo.OperandUnionAll: {
 		&ImplUnionAll{},
@@ -140,6 +155,11 @@ type ImplProjection struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplProjection) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	if expr.Group.EngineType == memo.EngineTiFlash {
+		// The Projection in TiFlash can not keep the partitioning of its child,
+		// since the partition columns may be calculated by the Projection.
+		return prop.IsEmpty() && prop.MPPPartitionTp == property.AnyType
+	}
 	return true
 }
 
@@ -182,6 +202,94 @@ func (r *ImplTiKVSingleReadGather) OnImplement(expr *memo.GroupExpr, reqProp *pr
 	return []memo.Implementation{impl.NewTableReaderImpl(reader, sg.Source)}, nil
 }
 
+// ImplTiKVDoubleReadGather implements TiKVDoubleGather as PhysicalIndexLookUpReader.
+type ImplTiKVDoubleReadGather struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplTiKVDoubleReadGather) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	dg := expr.ExprNode.(*plannercore.TiKVDoubleGather)
+	// IndexLookUpReader on partition table can't keep order.
+	return prop.IsEmpty() || dg.Source.TableInfo().GetPartitionInfo() == nil
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplTiKVDoubleReadGather) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	logicProp := expr.Group.Prop
+	dg := expr.ExprNode.(*plannercore.TiKVDoubleGather)
+	// The filters on the table side would filter out some rows read from the
+	// index side, so the index side is expected to read more rows.
+	indexProp := &property.PhysicalProperty{SortItems: reqProp.SortItems, ExpectedCnt: math.MaxFloat64}
+	if reqProp.ExpectedCnt < logicProp.Stats.RowCount {
+		indexProp.ExpectedCnt = reqProp.ExpectedCnt * expr.Children[0].Prop.Stats.RowCount / logicProp.Stats.RowCount
+	}
+	tableProp := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	reader := dg.GetPhysicalIndexLookUpReader(logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), indexProp, tableProp)
+	return []memo.Implementation{impl.NewIndexLookUpReaderImpl(reader, dg.Source, logicProp.Schema)}, nil
+}
+
+// ImplTiKVIndexMergeGather implements TiKVIndexMergeGather as PhysicalIndexMergeReader.
+type ImplTiKVIndexMergeGather struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplTiKVIndexMergeGather) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	// IndexMergeReader can't keep order.
+	return prop.IsEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplTiKVIndexMergeGather) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	logicProp := expr.Group.Prop
+	g := expr.ExprNode.(*plannercore.TiKVIndexMergeGather)
+	childProps := make([]*property.PhysicalProperty, 0, len(expr.Children))
+	for range expr.Children {
+		childProps = append(childProps, &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	}
+	reader, err := g.GetPhysicalIndexMergeReader(logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), childProps...)
+	if err != nil {
+		return nil, err
+	}
+	return []memo.Implementation{impl.NewIndexMergeReaderImpl(reader, g.Source)}, nil
+}
+
+// ImplTiFlashMPPGather implements TiFlashMPPGather as PhysicalTableReader
+// which reads the data from TiFlash in the MPP mode.
+type ImplTiFlashMPPGather struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplTiFlashMPPGather) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	// The results of the MPP tasks are not ordered.
+	return prop.IsEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplTiFlashMPPGather) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	logicProp := expr.Group.Prop
+	mg := expr.ExprNode.(*plannercore.TiFlashMPPGather)
+	childProp := &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64}
+	reader := mg.GetPhysicalTableReader(logicProp.Schema, logicProp.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), childProp)
+	return []memo.Implementation{impl.NewMPPTableReaderImpl(reader)}, nil
+}
+
+// ImplTiKVPointGet implements TiKVPointGet as PointGetPlan or BatchPointGetPlan.
+type ImplTiKVPointGet struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplTiKVPointGet) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return prop.IsEmpty()
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplTiKVPointGet) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	logicProp := expr.Group.Prop
+	pg := expr.ExprNode.(*plannercore.TiKVPointGet)
+	plan := pg.GetPhysicalPointGet(logicProp.Stats)
+	return []memo.Implementation{impl.NewPointGetImpl(plan, pg.ReadColumns())}, nil
+}
+
 // ImplTableScan implements TableScan as PhysicalTableScan.
 type ImplTableScan struct {
 }
@@ -189,6 +297,9 @@ type ImplTableScan struct {
 // Match implements ImplementationRule Match interface.
 func (r *ImplTableScan) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
 	ts := expr.ExprNode.(*plannercore.LogicalTableScan)
+	if expr.Group.EngineType == memo.EngineTiFlash {
+		return prop.IsEmpty() && prop.MPPPartitionTp == property.AnyType
+	}
 	return prop.IsEmpty() || (len(prop.SortItems) == 1 && ts.HandleCols != nil && prop.SortItems[0].Col.Equal(nil, ts.HandleCols.GetCol(0)))
 }
 
@@ -201,6 +312,9 @@ func (r *ImplTableScan) OnImplement(expr *memo.GroupExpr, reqProp *property.Phys
 		ts.KeepOrder = true
 		ts.Desc = reqProp.SortItems[0].Desc
 	}
+	if expr.Group.EngineType == memo.EngineTiFlash {
+		ts.StoreType = kv.TiFlash
+	}
 	tblCols, tblColHists := logicalScan.Source.TblCols, logicalScan.Source.TblColHists
 	return []memo.Implementation{impl.NewTableScanImpl(ts, tblCols, tblColHists)}, nil
 }
@@ -259,6 +373,9 @@ type ImplSelection struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplSelection) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	if expr.Group.EngineType == memo.EngineTiFlash {
+		return prop.IsEmpty()
+	}
 	return true
 }
 
@@ -273,6 +390,8 @@ func (r *ImplSelection) OnImplement(expr *memo.GroupExpr, reqProp *property.Phys
 		return []memo.Implementation{impl.NewTiDBSelectionImpl(physicalSel)}, nil
 	case memo.EngineTiKV:
 		return []memo.Implementation{impl.NewTiKVSelectionImpl(physicalSel)}, nil
+	case memo.EngineTiFlash:
+		return []memo.Implementation{impl.NewTiFlashSelectionImpl(physicalSel)}, nil
 	default:
 		return nil, plannercore.ErrInternal.GenWithStack("Unsupported EngineType '%s' for Selection.", expr.Group.EngineType.String())
 	}
@@ -323,6 +442,9 @@ func (r *ImplHashAgg) Match(expr *memo.GroupExpr, prop *property.PhysicalPropert
 // OnImplement implements ImplementationRule OnImplement interface.
 func (r *ImplHashAgg) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
 	la := expr.ExprNode.(*plannercore.LogicalAggregation)
+	if expr.Group.EngineType == memo.EngineTiFlash {
+		return r.implTiFlashHashAgg(expr, la, reqProp), nil
+	}
 	hashAgg := plannercore.NewPhysicalHashAgg(
 		la,
 		expr.Group.Prop.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt),
@@ -339,6 +461,47 @@ func (r *ImplHashAgg) OnImplement(expr *memo.GroupExpr, reqProp *property.Physic
 	}
 }
 
+// implTiFlashHashAgg implements the LogicalAggregation in TiFlash. The partial
+// Aggregation can be executed on any partition of the data, while the other
+// Aggregations require the data to be partitioned by the group by columns.
+func (r *ImplHashAgg) implTiFlashHashAgg(expr *memo.GroupExpr, la *plannercore.LogicalAggregation, reqProp *property.PhysicalProperty) []memo.Implementation {
+	childProp := &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64}
+	runMode := plannercore.Mpp2Phase
+	if la.IsPartial {
+		if reqProp.MPPPartitionTp != property.AnyType {
+			return nil
+		}
+	} else {
+		if reqProp.MPPPartitionTp == property.BroadcastType || len(la.GroupByItems) == 0 {
+			return nil
+		}
+		partitionCols := la.GetPotentialPartitionKeys()
+		if len(partitionCols) != len(la.GroupByItems) {
+			return nil
+		}
+		if reqProp.MPPPartitionTp == property.HashType {
+			matches := reqProp.IsSubsetOf(partitionCols)
+			if len(matches) == 0 {
+				return nil
+			}
+			chosenCols := make([]*property.MPPPartitionColumn, 0, len(matches))
+			for _, idx := range matches {
+				chosenCols = append(chosenCols, partitionCols[idx])
+			}
+			partitionCols = chosenCols
+		}
+		childProp.MPPPartitionTp = property.HashType
+		childProp.MPPPartitionCols = partitionCols
+		if len(la.AggFuncs) == 0 || la.AggFuncs[0].Mode != aggregation.FinalMode {
+			runMode = plannercore.Mpp1Phase
+		}
+	}
+	hashAgg := plannercore.NewPhysicalHashAgg(la, expr.Group.Prop.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt), childProp)
+	hashAgg.SetSchema(expr.Group.Prop.Schema.Clone())
+	hashAgg.MppRunMode = runMode
+	return []memo.Implementation{impl.NewTiFlashHashAggImpl(hashAgg)}
+}
+
 // ImplLimit is the implementation rule which implements LogicalLimit
 // to PhysicalLimit.
 type ImplLimit struct {
@@ -442,6 +605,9 @@ type ImplHashJoinBuildLeft struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplHashJoinBuildLeft) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	if expr.Group.EngineType != memo.EngineTiDB {
+		return false
+	}
 	switch expr.ExprNode.(*plannercore.LogicalJoin).JoinType {
 	case plannercore.InnerJoin, plannercore.LeftOuterJoin, plannercore.RightOuterJoin:
 		return prop.IsEmpty()
@@ -471,7 +637,7 @@ type ImplHashJoinBuildRight struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplHashJoinBuildRight) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	return prop.IsEmpty()
+	return expr.Group.EngineType == memo.EngineTiDB && prop.IsEmpty()
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -497,7 +663,7 @@ type ImplMergeJoin struct {
 
 // Match implements ImplementationRule Match interface.
 func (r *ImplMergeJoin) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
-	return true
+	return expr.Group.EngineType == memo.EngineTiDB
 }
 
 // OnImplement implements ImplementationRule OnImplement interface.
@@ -512,6 +678,77 @@ func (r *ImplMergeJoin) OnImplement(expr *memo.GroupExpr, reqProp *property.Phys
 	return mergeJoinImpls, nil
 }
 
+// ImplTiFlashMPPHashJoin implements LogicalJoin in TiFlash to PhysicalHashJoin
+// which is executed in the MPP mode. It generates the broadcast join if the
+// build side is small enough, and the shuffle join if there are join keys.
+type ImplTiFlashMPPHashJoin struct {
+}
+
+// Match implements ImplementationRule Match interface.
+func (r *ImplTiFlashMPPHashJoin) Match(expr *memo.GroupExpr, prop *property.PhysicalProperty) (matched bool) {
+	return expr.Group.EngineType == memo.EngineTiFlash && prop.IsEmpty() && prop.MPPPartitionTp == property.AnyType
+}
+
+// OnImplement implements ImplementationRule OnImplement interface.
+func (r *ImplTiFlashMPPHashJoin) OnImplement(expr *memo.GroupExpr, reqProp *property.PhysicalProperty) ([]memo.Implementation, error) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	sctx := join.SCtx()
+	stats := expr.Group.Prop.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt)
+	leftProp, rightProp := expr.Children[0].Prop, expr.Children[1].Prop
+	var impls []memo.Implementation
+	// The broadcast join.
+	canBroadcast := [2]bool{
+		plannercore.CheckFitBroadcast(sctx, leftProp.Stats, leftProp.Schema),
+		plannercore.CheckFitBroadcast(sctx, rightProp.Stats, rightProp.Schema),
+	}
+	if len(join.EqualConditions) == 0 && sctx.GetSessionVars().AllowCartesianBCJ == 2 {
+		canBroadcast = [2]bool{true, true}
+	}
+	if buildIdx := getMPPJoinBuildIdx(join, true, leftProp.Stats, rightProp.Stats); canBroadcast[buildIdx] {
+		childProps := make([]*property.PhysicalProperty, 2)
+		childProps[buildIdx] = &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64, MPPPartitionTp: property.BroadcastType}
+		childProps[1-buildIdx] = &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64}
+		hashJoin := plannercore.NewPhysicalMPPHashJoin(join, buildIdx, true, stats, childProps...)
+		hashJoin.SetSchema(expr.Group.Prop.Schema)
+		impls = append(impls, impl.NewHashJoinImpl(hashJoin))
+	}
+	// The shuffle join.
+	if len(join.EqualConditions) > 0 {
+		leftKeys, rightKeys := join.GetPotentialPartitionKeys()
+		childProps := []*property.PhysicalProperty{
+			{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64, MPPPartitionTp: property.HashType, MPPPartitionCols: leftKeys},
+			{TaskTp: property.MppTaskType, ExpectedCnt: math.MaxFloat64, MPPPartitionTp: property.HashType, MPPPartitionCols: rightKeys},
+		}
+		buildIdx := getMPPJoinBuildIdx(join, false, leftProp.Stats, rightProp.Stats)
+		hashJoin := plannercore.NewPhysicalMPPHashJoin(join, buildIdx, false, stats, childProps...)
+		hashJoin.SetSchema(expr.Group.Prop.Schema)
+		impls = append(impls, impl.NewHashJoinImpl(hashJoin))
+	}
+	return impls, nil
+}
+
+// getMPPJoinBuildIdx returns the index of the build side of the MPP hash join.
+// TiFlash does not require the build side of an outer join to be the inner side,
+// so the smaller side is chosen unless it is a broadcast join, the build side is
+// fixed by the session variable or there are other conditions.
+func getMPPJoinBuildIdx(join *plannercore.LogicalJoin, useBCJ bool, lStats, rStats *property.StatsInfo) int {
+	switch join.JoinType {
+	case plannercore.SemiJoin, plannercore.AntiSemiJoin:
+		return 1
+	case plannercore.LeftOuterJoin, plannercore.RightOuterJoin:
+		if useBCJ || join.SCtx().GetSessionVars().MPPOuterJoinFixedBuildSide || len(join.OtherConditions) > 0 {
+			if join.JoinType == plannercore.LeftOuterJoin {
+				return 1
+			}
+			return 0
+		}
+	}
+	if lStats.Count() > rStats.Count() {
+		return 1
+	}
+	return 0
+}
+
 // ImplUnionAll implements LogicalUnionAll to PhysicalUnionAll.
 type ImplUnionAll struct {
 }
@@ -120,13 +120,29 @@ var TiKVLayerOptimizationBatch = TransformationRuleBatch{
 		NewRuleEnumeratePaths(),
 	},
 	memo.OperandSelection: {
+		NewRuleTransformTiKVSingleGatherToIndexMerge(),
 		NewRulePushSelDownTiKVSingleGather(),
+		NewRulePushSelDownTiKVDoubleGather(),
+		NewRulePushSelDownTiFlashMPPGather(),
 		NewRulePushSelDownTableScan(),
 		NewRulePushSelDownIndexScan(),
 		NewRuleMergeAdjacentSelection(),
 	},
+	memo.OperandTiKVSingleGather: {
+		NewRuleTransformTiKVSingleGatherToPointGet(),
+	},
+	memo.OperandTiKVDoubleGather: {
+		NewRuleTransformTiKVDoubleGatherToPointGet(),
+	},
 	memo.OperandAggregation: {
 		NewRulePushAggDownGather(),
+		NewRulePushAggDownTiFlashMPPGather(),
+	},
+	memo.OperandProjection: {
+		NewRulePushProjDownTiFlashMPPGather(),
+	},
+	memo.OperandJoin: {
+		NewRulePushJoinDownTiFlashMPPGather(),
 	},
 	memo.OperandLimit: {
 		NewRulePushLimitDownTiKVSingleGather(),
@@ -338,12 +354,16 @@ func (r *PushSelDownTiKVSingleGather) OnTransform(old *memo.ExprIter) (newExprs
 	pushedSelExpr := memo.NewGroupExpr(pushedSel)
 	pushedSelExpr.Children = append(pushedSelExpr.Children, childGroup)
 	pushedSelGroup := memo.NewGroupWithSchema(pushedSelExpr, childGroup.Prop.Schema).SetEngineType(childGroup.EngineType)
-	// The field content of TiKVSingleGather would not be modified currently, so we
-	// just reference the same tg instead of making a copy of it.
-	//
-	// TODO: if we save pushed filters later in TiKVSingleGather, in order to do partition
-	//       pruning or skyline pruning, we need to make a copy of the TiKVSingleGather here.
-	tblGatherExpr := memo.NewGroupExpr(sg)
+	// We save the pushed filters in the new TiKVSingleGather to do partition pruning,
+	// so we need to make a copy of the TiKVSingleGather here.
+	newSg := plannercore.TiKVSingleGather{
+		Source:        sg.Source,
+		IsIndexGather: sg.IsIndexGather,
+		Index:         sg.Index,
+		PruningConds:  appendPruningConds(sg.PruningConds, pushed),
+	}.Init(sctx, sg.SelectBlockOffset())
+	newSg.SetSchema(sg.Schema())
+	tblGatherExpr := memo.NewGroupExpr(newSg)
 	tblGatherExpr.Children = append(tblGatherExpr.Children, pushedSelGroup)
 	if len(remained) == 0 {
 		// `oldSel -> oldTg -> any` is transformed to `newTg -> pushedSel -> any`.
@@ -357,6 +377,285 @@ func (r *PushSelDownTiKVSingleGather) OnTransform(old *memo.ExprIter) (newExprs
 	return []*memo.GroupExpr{remainedSelExpr}, true, false, nil
 }
 
+// appendPruningConds returns a new slice of the pruning conditions which
+// contains both the old ones and the newly pushed ones.
+func appendPruningConds(oldConds, pushed []expression.Expression) []expression.Expression {
+	conds := make([]expression.Expression, 0, len(oldConds)+len(pushed))
+	conds = append(conds, oldConds...)
+	return append(conds, pushed...)
+}
+
+// PushSelDownTiKVDoubleGather pushes the selection down to the children of TiKVDoubleGather.
+type PushSelDownTiKVDoubleGather struct {
+	baseRule
+}
+
+// NewRulePushSelDownTiKVDoubleGather creates a new Transformation PushSelDownTiKVDoubleGather.
+// The pattern of this rule is `Selection -> TiKVDoubleGather`.
+func NewRulePushSelDownTiKVDoubleGather() Transformation {
+	rule := &PushSelDownTiKVDoubleGather{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandSelection,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandTiKVDoubleGather, memo.EngineTiDBOnly),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `oldSel -> oldDg -> (indexSide, tableSide)` to one of the following new exprs:
+// 1. `newDg -> (indexSel -> indexSide, tableSel -> tableSide)`
+// 2. `remainedSel -> newDg -> (indexSel -> indexSide, tableSel -> tableSide)`
+// The filters which only use the columns of the index are pushed to the index side,
+// the other pushable filters are pushed to the table side. The indexSel or the tableSel
+// is omitted if there is no filter for it.
+func (r *PushSelDownTiKVDoubleGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
+	dgExpr := old.Children[0].GetExpr()
+	dg := dgExpr.ExprNode.(*plannercore.TiKVDoubleGather)
+	sctx := dg.SCtx()
+	pushed, remained := expression.PushDownExprs(sctx.GetSessionVars().StmtCtx, sel.Conditions, sctx.GetClient(), kv.TiKV)
+	if len(pushed) == 0 {
+		return nil, false, false, nil
+	}
+	var indexConds, tableConds []expression.Expression
+	for _, cond := range pushed {
+		if dg.IsCoveredByIndex(expression.ExtractColumns(cond)) {
+			indexConds = append(indexConds, cond)
+		} else {
+			tableConds = append(tableConds, cond)
+		}
+	}
+	children := []*memo.Group{dgExpr.Children[0], dgExpr.Children[1]}
+	for i, conds := range [][]expression.Expression{indexConds, tableConds} {
+		if len(conds) == 0 {
+			continue
+		}
+		childGroup := children[i]
+		pushedSel := plannercore.LogicalSelection{Conditions: conds}.Init(sctx, sel.SelectBlockOffset())
+		pushedSelExpr := memo.NewGroupExpr(pushedSel)
+		pushedSelExpr.SetChildren(childGroup)
+		children[i] = memo.NewGroupWithSchema(pushedSelExpr, childGroup.Prop.Schema).SetEngineType(childGroup.EngineType)
+	}
+	newDg := plannercore.TiKVDoubleGather{
+		Source:         dg.Source,
+		Index:          dg.Index,
+		FullIdxCols:    dg.FullIdxCols,
+		FullIdxColLens: dg.FullIdxColLens,
+		HandleCols:     dg.HandleCols,
+		PruningConds:   appendPruningConds(dg.PruningConds, pushed),
+	}.Init(sctx, dg.SelectBlockOffset())
+	newDg.SetSchema(dg.Schema())
+	newDgExpr := memo.NewGroupExpr(newDg)
+	newDgExpr.SetChildren(children...)
+	if len(remained) == 0 {
+		return []*memo.GroupExpr{newDgExpr}, true, false, nil
+	}
+	newDgGroup := memo.NewGroupWithSchema(newDgExpr, old.Children[0].Group.Prop.Schema)
+	remainedSel := plannercore.LogicalSelection{Conditions: remained}.Init(sctx, sel.SelectBlockOffset())
+	remainedSelExpr := memo.NewGroupExpr(remainedSel)
+	remainedSelExpr.SetChildren(newDgGroup)
+	return []*memo.GroupExpr{remainedSelExpr}, true, false, nil
+}
+
+// TransformTiKVSingleGatherToIndexMerge uses the DNF filters of the Selection to build
+// TiKVIndexMergeGathers, which read the table rows by the union of several index or table ranges.
+type TransformTiKVSingleGatherToIndexMerge struct {
+	baseRule
+}
+
+// NewRuleTransformTiKVSingleGatherToIndexMerge creates a new Transformation TransformTiKVSingleGatherToIndexMerge.
+// The pattern of this rule is `Selection -> TiKVSingleGather`.
+func NewRuleTransformTiKVSingleGatherToIndexMerge() Transformation {
+	rule := &TransformTiKVSingleGatherToIndexMerge{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandSelection,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandTiKVSingleGather, memo.EngineTiDBOnly),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *TransformTiKVSingleGatherToIndexMerge) Match(expr *memo.ExprIter) bool {
+	sg := expr.Children[0].GetExpr().ExprNode.(*plannercore.TiKVSingleGather)
+	return !sg.IsIndexGather
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `sel -> tableGather` to `indexMergeGather -> (partialScans..., tableSide)`
+// or `remainedSel -> indexMergeGather -> (partialScans..., tableSide)`. The old expression
+// is kept, since the TiKVIndexMergeGather is not always better than the other gathers.
+func (r *TransformTiKVSingleGatherToIndexMerge) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
+	sg := old.Children[0].GetExpr().ExprNode.(*plannercore.TiKVSingleGather)
+	gathers, remainedConds, err := sg.Source.Convert2IndexMergeGathers(sel.Conditions)
+	if err != nil {
+		return nil, false, false, err
+	}
+	for i, gather := range gathers {
+		gatherExpr := memo.Convert2GroupExpr(gather)
+		for _, child := range gatherExpr.Children {
+			setEngineTypeRecursively(child, memo.EngineTiKV)
+		}
+		if len(remainedConds[i]) == 0 {
+			newExprs = append(newExprs, gatherExpr)
+			continue
+		}
+		gatherGroup := memo.NewGroupWithSchema(gatherExpr, old.Children[0].Group.Prop.Schema)
+		remainedSel := plannercore.LogicalSelection{Conditions: remainedConds[i]}.Init(sel.SCtx(), sel.SelectBlockOffset())
+		remainedSelExpr := memo.NewGroupExpr(remainedSel)
+		remainedSelExpr.SetChildren(gatherGroup)
+		newExprs = append(newExprs, remainedSelExpr)
+	}
+	return newExprs, false, false, nil
+}
+
+// setEngineTypeRecursively sets the engine type of the group and all of its descendants.
+func setEngineTypeRecursively(g *memo.Group, engineType memo.EngineType) {
+	g.SetEngineType(engineType)
+	for elem := g.Equivalents.Front(); elem != nil; elem = elem.Next() {
+		for _, child := range elem.Value.(*memo.GroupExpr).Children {
+			setEngineTypeRecursively(child, engineType)
+		}
+	}
+}
+
+// TransformTiKVSingleGatherToPointGet converts the TiKVSingleGather to TiKVPointGet
+// if the child of the gather only reads some points of the handle or a unique index.
+type TransformTiKVSingleGatherToPointGet struct {
+	baseRule
+}
+
+// NewRuleTransformTiKVSingleGatherToPointGet creates a new Transformation TransformTiKVSingleGatherToPointGet.
+// The pattern of this rule is `TiKVSingleGather -> Any`.
+func NewRuleTransformTiKVSingleGatherToPointGet() Transformation {
+	rule := &TransformTiKVSingleGatherToPointGet{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandTiKVSingleGather,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandAny, memo.EngineTiKVOnly),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `sg -> scan` to `pointGet`, and transforms `sg -> sel -> scan`
+// to `sel -> pointGet`. The old TiKVSingleGather is kept, since the implementations
+// of the TiKVPointGet can not keep order.
+func (r *TransformTiKVSingleGatherToPointGet) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	pg, filters, err := convert2PointGet(old.Children[0].Group)
+	if pg == nil || err != nil {
+		return nil, false, false, err
+	}
+	return buildPointGetExprs(pg, filters, old.GetExpr().Group.Prop.Schema), false, false, nil
+}
+
+// TransformTiKVDoubleGatherToPointGet converts the TiKVDoubleGather to TiKVPointGet
+// if the index side of the gather only reads some points of a unique index.
+type TransformTiKVDoubleGatherToPointGet struct {
+	baseRule
+}
+
+// NewRuleTransformTiKVDoubleGatherToPointGet creates a new Transformation TransformTiKVDoubleGatherToPointGet.
+// The pattern of this rule is `TiKVDoubleGather -> (Any, Any)`.
+func NewRuleTransformTiKVDoubleGatherToPointGet() Transformation {
+	rule := &TransformTiKVDoubleGatherToPointGet{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandTiKVDoubleGather,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandAny, memo.EngineTiKVOnly),
+		memo.NewPattern(memo.OperandAny, memo.EngineTiKVOnly),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `dg -> (indexSide, tableSide)` to `pointGet` or `sel -> pointGet`,
+// the filters of both sides are evaluated by the `sel` above the TiKVPointGet.
+func (r *TransformTiKVDoubleGatherToPointGet) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	tableFilters, ok := getTableSideFilters(old.Children[1].Group)
+	if !ok {
+		return nil, false, false, nil
+	}
+	pg, indexFilters, err := convert2PointGet(old.Children[0].Group)
+	if pg == nil || err != nil {
+		return nil, false, false, err
+	}
+	filters := make([]expression.Expression, 0, len(indexFilters)+len(tableFilters))
+	filters = append(filters, indexFilters...)
+	filters = append(filters, tableFilters...)
+	return buildPointGetExprs(pg, filters, old.GetExpr().Group.Prop.Schema), false, false, nil
+}
+
+// convert2PointGet tries to convert one of the `scan` or `sel -> scan` exprs in
+// the group to a TiKVPointGet, the conditions of the `sel` are returned as the
+// filters of the TiKVPointGet.
+func convert2PointGet(g *memo.Group) (pg *plannercore.TiKVPointGet, filters []expression.Expression, err error) {
+	for elem := g.Equivalents.Front(); elem != nil; elem = elem.Next() {
+		expr := elem.Value.(*memo.GroupExpr)
+		sel, ok := expr.ExprNode.(*plannercore.LogicalSelection)
+		if !ok {
+			pg, err = convertScan2PointGet(expr)
+			if pg != nil || err != nil {
+				return pg, nil, err
+			}
+			continue
+		}
+		for childElem := expr.Children[0].Equivalents.Front(); childElem != nil; childElem = childElem.Next() {
+			pg, err = convertScan2PointGet(childElem.Value.(*memo.GroupExpr))
+			if pg != nil || err != nil {
+				return pg, sel.Conditions, err
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+func convertScan2PointGet(expr *memo.GroupExpr) (*plannercore.TiKVPointGet, error) {
+	switch scan := expr.ExprNode.(type) {
+	case *plannercore.LogicalTableScan:
+		return scan.Convert2PointGet()
+	case *plannercore.LogicalIndexScan:
+		return scan.Convert2PointGet(), nil
+	}
+	return nil, nil
+}
+
+// getTableSideFilters finds the `ts` or `sel -> ts` expr in the table side group
+// of a TiKVDoubleGather and returns the conditions of the `sel`.
+func getTableSideFilters(g *memo.Group) (filters []expression.Expression, ok bool) {
+	for elem := g.Equivalents.Front(); elem != nil; elem = elem.Next() {
+		expr := elem.Value.(*memo.GroupExpr)
+		switch node := expr.ExprNode.(type) {
+		case *plannercore.LogicalTableScan:
+			return nil, true
+		case *plannercore.LogicalSelection:
+			if expr.Children[0].GetFirstElem(memo.OperandTableScan) != nil {
+				return node.Conditions, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// buildPointGetExprs builds `pointGet` or `sel -> pointGet` if there are some filters.
+func buildPointGetExprs(pg *plannercore.TiKVPointGet, filters []expression.Expression, schema *expression.Schema) []*memo.GroupExpr {
+	pgExpr := memo.NewGroupExpr(pg)
+	if len(filters) == 0 {
+		return []*memo.GroupExpr{pgExpr}
+	}
+	pgGroup := memo.NewGroupWithSchema(pgExpr, schema)
+	sel := plannercore.LogicalSelection{Conditions: filters}.Init(pg.SCtx(), pg.SelectBlockOffset())
+	selExpr := memo.NewGroupExpr(sel)
+	selExpr.SetChildren(pgGroup)
+	return []*memo.GroupExpr{selExpr}
+}
+
 // EnumeratePaths converts DataSource to table scan and index scans.
 type EnumeratePaths struct {
 	baseRule
@@ -376,7 +675,13 @@ func (r *EnumeratePaths) OnTransform(old *memo.ExprIter) (newExprs []*memo.Group
 	gathers := ds.Convert2Gathers()
 	for _, gather := range gathers {
 		expr := memo.Convert2GroupExpr(gather)
-		expr.Children[0].SetEngineType(memo.EngineTiKV)
+		engineType := memo.EngineTiKV
+		if _, isMPP := gather.(*plannercore.TiFlashMPPGather); isMPP {
+			engineType = memo.EngineTiFlash
+		}
+		for _, child := range expr.Children {
+			child.SetEngineType(engineType)
+		}
 		newExprs = append(newExprs, expr)
 	}
 	return newExprs, true, false, nil
@@ -433,6 +738,28 @@ func (r *PushAggDownGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 	aggSchema := old.GetExpr().Group.Prop.Schema
 	gather := old.Children[0].GetExpr().ExprNode.(*plannercore.TiKVSingleGather)
 	childGroup := old.Children[0].GetExpr().Children[0]
+	partialAgg, finalAgg, partialSchema := splitAggregation(agg, aggSchema, false)
+	if partialAgg == nil {
+		return nil, false, false, nil
+	}
+	partialAggExpr := memo.NewGroupExpr(partialAgg)
+	partialAggExpr.SetChildren(childGroup)
+	partialAggGroup := memo.NewGroupWithSchema(partialAggExpr, partialSchema).SetEngineType(childGroup.EngineType)
+	gatherExpr := memo.NewGroupExpr(gather)
+	gatherExpr.SetChildren(partialAggGroup)
+	gatherGroup := memo.NewGroupWithSchema(gatherExpr, partialSchema)
+	finalAggExpr := memo.NewGroupExpr(finalAgg)
+	finalAggExpr.SetChildren(gatherGroup)
+	finalAggExpr.AddAppliedRule(r)
+	// We don't erase the old complete mode Aggregation because
+	// this transformation would not always be better.
+	return []*memo.GroupExpr{finalAggExpr}, false, false, nil
+}
+
+// splitAggregation splits the complete mode Aggregation to the partial one and the final one.
+// It returns nil if the Aggregation can not be split.
+func splitAggregation(agg *plannercore.LogicalAggregation, aggSchema *expression.Schema, isMPP bool) (
+	partialAgg, finalAgg *plannercore.LogicalAggregation, partialSchema *expression.Schema) {
 	// The old Aggregation should stay unchanged for other transformation.
 	// So we build a new LogicalAggregation for the partialAgg.
 	aggFuncs := make([]*aggregation.AggFuncDesc, len(agg.AggFuncs))
@@ -447,38 +774,270 @@ func (r *PushAggDownGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.Gr
 			AggFuncs:     aggFuncs,
 			GroupByItems: gbyItems,
 			Schema:       aggSchema,
-		}, true, false)
+		}, true, isMPP)
 	if partialPref == nil {
-		return nil, false, false, nil
+		return nil, nil, nil
 	}
 	// Remove unnecessary FirstRow.
 	partialPref.AggFuncs =
 		plannercore.RemoveUnnecessaryFirstRow(agg.SCtx(), finalPref.AggFuncs, finalPref.GroupByItems, partialPref.AggFuncs, partialPref.GroupByItems, partialPref.Schema, funcMap)
 
-	partialAgg := plannercore.LogicalAggregation{
+	partialAgg = plannercore.LogicalAggregation{
 		AggFuncs:     partialPref.AggFuncs,
 		GroupByItems: partialPref.GroupByItems,
+		IsPartial:    true,
 	}.Init(agg.SCtx(), agg.SelectBlockOffset())
 	partialAgg.CopyAggHints(agg)
 
-	finalAgg := plannercore.LogicalAggregation{
+	finalAgg = plannercore.LogicalAggregation{
 		AggFuncs:     finalPref.AggFuncs,
 		GroupByItems: finalPref.GroupByItems,
 	}.Init(agg.SCtx(), agg.SelectBlockOffset())
 	finalAgg.CopyAggHints(agg)
+	return partialAgg, finalAgg, partialPref.Schema
+}
 
-	partialAggExpr := memo.NewGroupExpr(partialAgg)
-	partialAggExpr.SetChildren(childGroup)
-	partialAggGroup := memo.NewGroupWithSchema(partialAggExpr, partialPref.Schema).SetEngineType(childGroup.EngineType)
-	gatherExpr := memo.NewGroupExpr(gather)
-	gatherExpr.SetChildren(partialAggGroup)
-	gatherGroup := memo.NewGroupWithSchema(gatherExpr, partialPref.Schema)
-	finalAggExpr := memo.NewGroupExpr(finalAgg)
-	finalAggExpr.SetChildren(gatherGroup)
-	finalAggExpr.AddAppliedRule(r)
+// PushSelDownTiFlashMPPGather pushes the Selection down to the child of TiFlashMPPGather.
+type PushSelDownTiFlashMPPGather struct {
+	baseRule
+}
+
+// NewRulePushSelDownTiFlashMPPGather creates a new Transformation PushSelDownTiFlashMPPGather.
+// The pattern of this rule is `Selection -> TiFlashMPPGather -> Any`.
+func NewRulePushSelDownTiFlashMPPGather() Transformation {
+	rule := &PushSelDownTiFlashMPPGather{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandSelection,
+		memo.EngineTiDBOnly,
+		memo.BuildPattern(
+			memo.OperandTiFlashMPPGather,
+			memo.EngineTiDBOnly,
+			memo.NewPattern(memo.OperandAny, memo.EngineTiFlashOnly),
+		),
+	)
+	return rule
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `oldSel -> oldMg -> any` to one of the following new exprs:
+// 1. `newMg -> pushedSel -> any`
+// 2. `remainedSel -> newMg -> pushedSel -> any`
+func (r *PushSelDownTiFlashMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
+	mg := old.Children[0].GetExpr().ExprNode.(*plannercore.TiFlashMPPGather)
+	childGroup := old.Children[0].Children[0].Group
+	sctx := sel.SCtx()
+	pushed, remained := expression.PushDownExprs(sctx.GetSessionVars().StmtCtx, sel.Conditions, sctx.GetClient(), kv.TiFlash)
+	if len(pushed) == 0 {
+		return nil, false, false, nil
+	}
+	pushedSel := plannercore.LogicalSelection{Conditions: pushed}.Init(sctx, sel.SelectBlockOffset())
+	pushedSelExpr := memo.NewGroupExpr(pushedSel)
+	pushedSelExpr.SetChildren(childGroup)
+	pushedSelGroup := memo.NewGroupWithSchema(pushedSelExpr, childGroup.Prop.Schema).SetEngineType(childGroup.EngineType)
+	mgExpr := memo.NewGroupExpr(mg)
+	mgExpr.SetChildren(pushedSelGroup)
+	if len(remained) == 0 {
+		return []*memo.GroupExpr{mgExpr}, true, false, nil
+	}
+	remainedSel := plannercore.LogicalSelection{Conditions: remained}.Init(sctx, sel.SelectBlockOffset())
+	remainedSelExpr := memo.NewGroupExpr(remainedSel)
+	remainedSelExpr.SetChildren(memo.NewGroupWithSchema(mgExpr, old.Children[0].Group.Prop.Schema))
+	return []*memo.GroupExpr{remainedSelExpr}, true, false, nil
+}
+
+// PushProjDownTiFlashMPPGather pushes the Projection down to the child of TiFlashMPPGather.
+type PushProjDownTiFlashMPPGather struct {
+	baseRule
+}
+
+// NewRulePushProjDownTiFlashMPPGather creates a new Transformation PushProjDownTiFlashMPPGather.
+// The pattern of this rule is `Projection -> TiFlashMPPGather -> Any`.
+func NewRulePushProjDownTiFlashMPPGather() Transformation {
+	rule := &PushProjDownTiFlashMPPGather{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandProjection,
+		memo.EngineTiDBOnly,
+		memo.BuildPattern(
+			memo.OperandTiFlashMPPGather,
+			memo.EngineTiDBOnly,
+			memo.NewPattern(memo.OperandAny, memo.EngineTiFlashOnly),
+		),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *PushProjDownTiFlashMPPGather) Match(expr *memo.ExprIter) bool {
+	proj := expr.GetExpr().ExprNode.(*plannercore.LogicalProjection)
+	sctx := proj.SCtx()
+	return expression.CanExprsPushDown(sctx.GetSessionVars().StmtCtx, proj.Exprs, sctx.GetClient(), kv.TiFlash)
+}
+
+// OnTransform implements Transformation interface.
+// It transforms `proj -> mg -> any` to `newMg -> proj -> any`.
+func (r *PushProjDownTiFlashMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	proj := old.GetExpr().ExprNode.(*plannercore.LogicalProjection)
+	projSchema := old.GetExpr().Group.Prop.Schema
+	childGroup := old.Children[0].Children[0].Group
+	projExpr := memo.NewGroupExpr(proj)
+	projExpr.SetChildren(childGroup)
+	projGroup := memo.NewGroupWithSchema(projExpr, projSchema).SetEngineType(childGroup.EngineType)
+	newMg := plannercore.TiFlashMPPGather{}.Init(proj.SCtx(), proj.SelectBlockOffset())
+	newMg.SetSchema(projSchema)
+	mgExpr := memo.NewGroupExpr(newMg)
+	mgExpr.SetChildren(projGroup)
+	// The old Projection is kept, since it may be merged with the other
+	// operators in TiDB.
+	return []*memo.GroupExpr{mgExpr}, false, false, nil
+}
+
+// PushAggDownTiFlashMPPGather pushes the Aggregation down to the child of TiFlashMPPGather.
+type PushAggDownTiFlashMPPGather struct {
+	baseRule
+}
+
+// NewRulePushAggDownTiFlashMPPGather creates a new Transformation PushAggDownTiFlashMPPGather.
+// The pattern of this rule is `Aggregation -> TiFlashMPPGather -> Any`.
+func NewRulePushAggDownTiFlashMPPGather() Transformation {
+	rule := &PushAggDownTiFlashMPPGather{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandAggregation,
+		memo.EngineTiDBOnly,
+		memo.BuildPattern(
+			memo.OperandTiFlashMPPGather,
+			memo.EngineTiDBOnly,
+			memo.NewPattern(memo.OperandAny, memo.EngineTiFlashOnly),
+		),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *PushAggDownTiFlashMPPGather) Match(expr *memo.ExprIter) bool {
+	if expr.GetExpr().HasAppliedRule(r) {
+		return false
+	}
+	agg := expr.GetExpr().ExprNode.(*plannercore.LogicalAggregation)
+	if agg.HasDistinct() {
+		return false
+	}
+	for _, aggFunc := range agg.AggFuncs {
+		// The avg() is rewritten to sum() and count() by a Projection in the
+		// MPP mode, which is not supported by this rule now.
+		if aggFunc.Mode != aggregation.CompleteMode || aggFunc.Name == ast.AggFuncAvg {
+			return false
+		}
+	}
+	return plannercore.CheckAggCanPushCop(agg.SCtx(), agg.AggFuncs, agg.GroupByItems, kv.TiFlash)
+}
+
+// OnTransform implements Transformation interface.
+//
+// It transforms `agg -> mg -> any` to one of the following new exprs:
+// 1. `finalAgg -> mg -> partialAgg -> any` if there is no group by item.
+// 2. `newMg -> finalAgg -> partialAgg -> any` if there are some aggregate functions.
+// 3. `newMg -> agg -> any` if there is no aggregate function.
+// The finalAgg or the agg in TiFlash requires its child to be partitioned by the group by items,
+// which is enforced by an exchange.
+func (r *PushAggDownTiFlashMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	agg := old.GetExpr().ExprNode.(*plannercore.LogicalAggregation)
+	aggSchema := old.GetExpr().Group.Prop.Schema
+	mg := old.Children[0].GetExpr().ExprNode.(*plannercore.TiFlashMPPGather)
+	childGroup := old.Children[0].Children[0].Group
+	var topGroup *memo.Group
+	switch {
+	case len(agg.GroupByItems) == 0:
+		partialAgg, finalAgg, partialSchema := splitAggregation(agg, aggSchema, false)
+		if partialAgg == nil {
+			return nil, false, false, nil
+		}
+		partialAggExpr := memo.NewGroupExpr(partialAgg)
+		partialAggExpr.SetChildren(childGroup)
+		partialAggGroup := memo.NewGroupWithSchema(partialAggExpr, partialSchema).SetEngineType(childGroup.EngineType)
+		newMg := plannercore.TiFlashMPPGather{}.Init(mg.SCtx(), mg.SelectBlockOffset())
+		newMg.SetSchema(partialSchema)
+		mgExpr := memo.NewGroupExpr(newMg)
+		mgExpr.SetChildren(partialAggGroup)
+		finalAggExpr := memo.NewGroupExpr(finalAgg)
+		finalAggExpr.SetChildren(memo.NewGroupWithSchema(mgExpr, partialSchema))
+		finalAggExpr.AddAppliedRule(r)
+		return []*memo.GroupExpr{finalAggExpr}, false, false, nil
+	case len(agg.AggFuncs) > 0:
+		partialAgg, finalAgg, partialSchema := splitAggregation(agg, aggSchema, true)
+		if partialAgg == nil {
+			return nil, false, false, nil
+		}
+		partialAggExpr := memo.NewGroupExpr(partialAgg)
+		partialAggExpr.SetChildren(childGroup)
+		partialAggGroup := memo.NewGroupWithSchema(partialAggExpr, partialSchema).SetEngineType(childGroup.EngineType)
+		finalAggExpr := memo.NewGroupExpr(finalAgg)
+		finalAggExpr.SetChildren(partialAggGroup)
+		topGroup = memo.NewGroupWithSchema(finalAggExpr, aggSchema).SetEngineType(childGroup.EngineType)
+	default:
+		aggExpr := memo.NewGroupExpr(agg)
+		aggExpr.SetChildren(childGroup)
+		topGroup = memo.NewGroupWithSchema(aggExpr, aggSchema).SetEngineType(childGroup.EngineType)
+	}
+	newMg := plannercore.TiFlashMPPGather{}.Init(mg.SCtx(), mg.SelectBlockOffset())
+	newMg.SetSchema(aggSchema)
+	mgExpr := memo.NewGroupExpr(newMg)
+	mgExpr.SetChildren(topGroup)
 	// We don't erase the old complete mode Aggregation because
 	// this transformation would not always be better.
-	return []*memo.GroupExpr{finalAggExpr}, false, false, nil
+	return []*memo.GroupExpr{mgExpr}, false, false, nil
+}
+
+// PushJoinDownTiFlashMPPGather pushes the Join down to TiFlash if both of its
+// children are TiFlashMPPGathers.
+type PushJoinDownTiFlashMPPGather struct {
+	baseRule
+}
+
+// NewRulePushJoinDownTiFlashMPPGather creates a new Transformation PushJoinDownTiFlashMPPGather.
+// The pattern of this rule is `Join -> (TiFlashMPPGather -> Any, TiFlashMPPGather -> Any)`.
+func NewRulePushJoinDownTiFlashMPPGather() Transformation {
+	rule := &PushJoinDownTiFlashMPPGather{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandJoin,
+		memo.EngineTiDBOnly,
+		memo.BuildPattern(
+			memo.OperandTiFlashMPPGather,
+			memo.EngineTiDBOnly,
+			memo.NewPattern(memo.OperandAny, memo.EngineTiFlashOnly),
+		),
+		memo.BuildPattern(
+			memo.OperandTiFlashMPPGather,
+			memo.EngineTiDBOnly,
+			memo.NewPattern(memo.OperandAny, memo.EngineTiFlashOnly),
+		),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *PushJoinDownTiFlashMPPGather) Match(expr *memo.ExprIter) bool {
+	join := expr.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	return join.CanPushToMPP()
+}
+
+// OnTransform implements Transformation interface.
+// It transforms `join -> (mg -> left, mg -> right)` to `newMg -> join -> (left, right)`.
+func (r *PushJoinDownTiFlashMPPGather) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	join := old.GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	joinSchema := old.GetExpr().Group.Prop.Schema
+	leftGroup := old.Children[0].Children[0].Group
+	rightGroup := old.Children[1].Children[0].Group
+	joinExpr := memo.NewGroupExpr(join)
+	joinExpr.SetChildren(leftGroup, rightGroup)
+	joinGroup := memo.NewGroupWithSchema(joinExpr, joinSchema).SetEngineType(memo.EngineTiFlash)
+	newMg := plannercore.TiFlashMPPGather{}.Init(join.SCtx(), join.SelectBlockOffset())
+	newMg.SetSchema(joinSchema)
+	mgExpr := memo.NewGroupExpr(newMg)
+	mgExpr.SetChildren(joinGroup)
+	// The old Join is kept, since the MPP join is not always better.
+	return []*memo.GroupExpr{mgExpr}, false, false, nil
 }
 
 // PushSelDownSort pushes the Selection down to the child of Sort.
@@ -79,6 +79,9 @@ type planCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	// stmtText is the parameterized SQL of the statement generated by the non-prepared plan cache, which doesn't
+	// have a statement ID.
+	stmtText string
 
 	hash []byte
 }
@@ -88,7 +91,7 @@ func (key *planCacheKey) Hash() []byte {
 	if len(key.hash) == 0 {
 		var (
 			dbBytes    = hack.Slice(key.database)
-			bufferSize = len(dbBytes) + 8*6 + 3*8
+			bufferSize = len(dbBytes) + 8*6 + 3*8 + len(key.stmtText)
 		)
 		if key.hash == nil {
 			key.hash = make([]byte, 0, bufferSize)
@@ -109,6 +112,7 @@ func (key *planCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		key.hash = append(key.hash, hack.Slice(key.stmtText)...)
 	}
 	return key.hash
 }
@@ -151,6 +155,18 @@ func NewPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, schemaVe
 	return key
 }
 
+// newPlanCacheKeyForStmt creates a planCacheKey object for the prepared statement. The statements generated by the
+// non-prepared plan cache are identified by their parameterized SQL instead of the statement IDs.
+func newPlanCacheKeyForStmt(sessionVars *variable.SessionVars, pstmtID uint32, preparedStmt *CachedPrepareStmt) kvcache.Key {
+	prepared := preparedStmt.PreparedAst
+	if !preparedStmt.NonPrepared {
+		return NewPlanCacheKey(sessionVars, pstmtID, prepared.SchemaVersion)
+	}
+	key := NewPlanCacheKey(sessionVars, 0, prepared.SchemaVersion).(*planCacheKey)
+	key.stmtText = prepared.Stmt.Text()
+	return key
+}
+
 // FieldSlice is the slice of the types.FieldType
 type FieldSlice []types.FieldType
 
@@ -218,6 +234,9 @@ type CachedPrepareStmt struct {
 	SnapshotTSEvaluator  func(sessionctx.Context) (uint64, error)
 	NormalizedSQL4PC     string
 	NormalizedSQL4PCHash string
+	// NonPrepared indicates whether the statement is generated by the non-prepared plan cache from a text query,
+	// whose literals are replaced by parameters.
+	NonPrepared bool
 }
 
 // GetPreparedStmt extract the prepared statement from the execute statement.
@@ -15,14 +15,18 @@
 package core_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/stretchr/testify/require"
 )
@@ -246,3 +250,42 @@ func TestCacheable(t *testing.T) {
 	require.True(t, core.Cacheable(stmt, is))
 
 }
+
+func TestParameterizeAST(t *testing.T) {
+	p := parser.New()
+	for _, ca := range []struct {
+		sql      string
+		paramSQL string
+		params   []interface{}
+	}{
+		{"select * from t where a = 1", "SELECT * FROM `t` WHERE `a`=?", []interface{}{int64(1)}},
+		{"select * from t where a > 1.5 and b = 'x' and c < 1e3", "SELECT * FROM `t` WHERE `a`>? AND `b`=? AND `c`<?", []interface{}{"1.5", "x", float64(1000)}},
+		{"select a, 1 from t where a in (1, 2) group by 1 order by 1 limit 10", "SELECT `a`,1 FROM `t` WHERE `a` IN (?,?) GROUP BY 1 ORDER BY 1 LIMIT 10", []interface{}{int64(1), int64(2)}},
+		{"select * from t where a = true and b = date_format(c, '%Y')", "SELECT * FROM `t` WHERE `a`=TRUE AND `b`=DATE_FORMAT(`c`, _UTF8MB4'%Y')", []interface{}{}},
+		{"select * from t where a = 0x01 and b is null", "SELECT * FROM `t` WHERE `a`=x'01' AND `b` IS NULL", []interface{}{}},
+	} {
+		stmt, err := p.ParseOneStmt(ca.sql, "", "")
+		require.NoError(t, err)
+		paramSQL, params, err := core.ParameterizeAST(stmt)
+		require.NoError(t, err, ca.sql)
+		require.Equal(t, ca.paramSQL, paramSQL, ca.sql)
+		require.Len(t, params, len(ca.params), ca.sql)
+		for i, param := range params {
+			str, err := param.ToString()
+			require.NoError(t, err)
+			expected := types.NewDatum(ca.params[i])
+			expectedStr, err := expected.ToString()
+			require.NoError(t, err)
+			require.Equal(t, expectedStr, str, ca.sql)
+		}
+		// The statement is left unchanged.
+		var sb strings.Builder
+		require.NoError(t, stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)))
+		require.NotContains(t, sb.String(), "?")
+	}
+
+	stmt, err := p.ParseOneStmt("select * from t where a = ?", "", "")
+	require.NoError(t, err)
+	_, _, err = core.ParameterizeAST(stmt)
+	require.Error(t, err)
+}
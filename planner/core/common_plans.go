@@ -129,6 +129,20 @@ type CancelDDLJobs struct {
 	JobIDs []int64
 }
 
+// PauseDDLJobs represents a pause DDL jobs plan.
+type PauseDDLJobs struct {
+	baseSchemaProducer
+
+	JobIDs []int64
+}
+
+// ResumeDDLJobs represents a resume DDL jobs plan.
+type ResumeDDLJobs struct {
+	baseSchemaProducer
+
+	JobIDs []int64
+}
+
 // ReloadExprPushdownBlacklist reloads the data from expr_pushdown_blacklist table.
 type ReloadExprPushdownBlacklist struct {
 	baseSchemaProducer
@@ -225,8 +239,23 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 	if !ok {
 		return errors.Errorf("invalid CachedPrepareStmt type")
 	}
+	return e.optimizePreparedStmt(ctx, sctx, is, preparedObj)
+}
+
+// optimizePreparedStmt optimizes the prepared statement with the parameters of the Execute.
+func (e *Execute) optimizePreparedStmt(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, preparedObj *CachedPrepareStmt) error {
+	vars := sctx.GetSessionVars()
 	prepared := preparedObj.PreparedAst
-	vars.StmtCtx.StmtType = prepared.StmtType
+	if !preparedObj.NonPrepared {
+		vars.StmtCtx.StmtType = prepared.StmtType
+	}
+	inPreparedPlanBuilding, inNonPreparedPlanBuilding := vars.StmtCtx.InPreparedPlanBuilding, vars.StmtCtx.InNonPreparedPlanBuilding
+	vars.StmtCtx.InPreparedPlanBuilding = true
+	vars.StmtCtx.InNonPreparedPlanBuilding = preparedObj.NonPrepared
+	defer func() {
+		vars.StmtCtx.InPreparedPlanBuilding = inPreparedPlanBuilding
+		vars.StmtCtx.InNonPreparedPlanBuilding = inNonPreparedPlanBuilding
+	}()
 
 	paramLen := len(e.PrepareParams)
 	if paramLen > 0 {
@@ -446,7 +475,7 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	var bindSQL string
 	if prepared.UseCache {
 		bindSQL = GetBindSQL4PlanCache(sctx, preparedStmt)
-		cacheKey = NewPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+		cacheKey = newPlanCacheKeyForStmt(sctx.GetSessionVars(), e.ExecID, preparedStmt)
 	}
 	tps := make([]*types.FieldType, len(e.UsingVars))
 	varsNum := len(e.UsingVars)
@@ -457,6 +486,21 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 			tps[i] = types.NewFieldType(mysql.TypeNull)
 		}
 	}
+	if preparedStmt.NonPrepared {
+		// The parameters of the non-prepared plan cache are the literals of the query, whose types may affect the plan.
+		tps = make([]*types.FieldType, len(e.PrepareParams))
+		varsNum = len(e.PrepareParams)
+		for i, param := range e.PrepareParams {
+			tps[i] = types.NewFieldType(mysql.TypeUnspecified)
+			types.DefaultParamTypeForValue(param.GetValue(), tps[i])
+		}
+	}
+	// The plans which can be shared by the sessions are cached in the instance plan cache instead of the
+	// plan cache of the session.
+	var instanceCacheKey kvcache.Key
+	if prepared.UseCache && !stmtCtx.SkipPlanCache && variable.EnableInstancePlanCache.Load() && isInstancePlanCacheable(prepared.Stmt) {
+		instanceCacheKey = newInstancePlanCacheKey(sessVars, preparedStmt, bindSQL, tps)
+	}
 	if prepared.CachedPlan != nil {
 		// Rewriting the expression in the select.where condition  will convert its
 		// type from "paramMarker" to "Constant".When Point Select queries are executed,
@@ -483,7 +527,30 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 		stmtCtx.PointExec = true
 		return nil
 	}
-	if prepared.UseCache {
+	if instanceCacheKey != nil {
+		if e.getInstanceCachedPlan(sctx, preparedStmt, instanceCacheKey) {
+			if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
+				return err
+			}
+			err := e.setFoundInPlanCache(sctx, true)
+			if err != nil {
+				return err
+			}
+			if len(bindSQL) > 0 {
+				err = sessVars.SetSystemVar(variable.TiDBFoundInBinding, variable.BoolToOnOff(true))
+				if err != nil {
+					return err
+				}
+			}
+			if metrics.ResettablePlanCacheCounterFortTest {
+				metrics.PlanCacheCounter.WithLabelValues("prepare").Inc()
+			} else {
+				planCacheCounter.Inc()
+			}
+			return nil
+		}
+	}
+	if prepared.UseCache && !stmtCtx.SkipPlanCache {
 		if cacheValue, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
 				return err
@@ -563,12 +630,19 @@ REBUILD:
 		// rebuild key to exclude kv.TiFlash when stmt is not read only
 		if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(stmt, sessVars) {
 			delete(sessVars.IsolationReadEngines, kv.TiFlash)
-			cacheKey = NewPlanCacheKey(sessVars, e.ExecID, prepared.SchemaVersion)
+			cacheKey = newPlanCacheKeyForStmt(sessVars, e.ExecID, preparedStmt)
+			if instanceCacheKey != nil {
+				instanceCacheKey = newInstancePlanCacheKey(sessVars, preparedStmt, bindSQL, tps)
+			}
 			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
 		}
 		cached := NewPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps, sessVars.StmtCtx.BindSQL)
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
+		if instanceCacheKey != nil && putInstanceCachedPlan(sctx, preparedStmt, instanceCacheKey, p, names, stmtCtx.TblInfo2UnionScan, tps) {
+			// The plan is shared by the sessions, so it's not cached by the session.
+			return e.setFoundInPlanCache(sctx, false)
+		}
 		if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			hitVal := false
 			for i, cacheVal := range cacheVals.([]*PlanCacheValue) {
@@ -946,6 +1020,8 @@ type SQLBindPlan struct {
 	Db           string
 	Charset      string
 	Collation    string
+	// Source is where the binding comes from, it's bindinfo.Manual if it's empty.
+	Source string
 }
 
 // Simple represents a simple statement plan which doesn't need any optimization.
@@ -1005,6 +1081,11 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 
 	RowLen int
+
+	// FKChecks and FKCascades are the foreign key checkers and cascades of the inserted,
+	// the replaced and the on duplicate updated rows.
+	FKChecks   []*FKCheck
+	FKCascades []*FKCascade
 }
 
 // Update represents Update plan.
@@ -1025,6 +1106,10 @@ type Update struct {
 	// e.g. update t partition(p0) set a = 1;
 	PartitionedTable []table.PartitionedTable
 
+	// FKChecks and FKCascades are the foreign key checkers and cascades of the updated tables, indexed by the table ID.
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
+
 	tblID2Table map[int64]table.Table
 }
 
@@ -1037,6 +1122,10 @@ type Delete struct {
 	SelectPlan PhysicalPlan
 
 	TblColPosInfos TblColPosInfoSlice
+
+	// FKChecks and FKCascades are the foreign key checkers and cascades of the deleted tables, indexed by the table ID.
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
 }
 
 // AnalyzeInfo is used to store the database name, table name and partition name of analyze task.
@@ -1112,6 +1201,15 @@ type LoadStats struct {
 	Path string
 }
 
+// RestoreStats represents a restore stats plan, it restores the latest historical stats of the table
+// whose version is not greater than Version.
+type RestoreStats struct {
+	baseSchemaProducer
+
+	Table   *ast.TableName
+	Version uint64
+}
+
 // PlanReplayer represents a plan replayer plan.
 type PlanReplayer struct {
 	baseSchemaProducer
@@ -1383,17 +1481,18 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, driverSide, indent st
 		}
 		err = e.explainPlanInRowFormat(x.tablePlan, "cop[tikv]", "(Probe)", childIndent, true)
 	case *Insert:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+		fkPlans := make([]Plan, 0, len(x.FKChecks)+len(x.FKCascades))
+		for _, check := range x.FKChecks {
+			fkPlans = append(fkPlans, check)
 		}
-	case *Update:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+		for _, cascade := range x.FKCascades {
+			fkPlans = append(fkPlans, cascade)
 		}
+		err = e.explainDMLChildrenInRowFormat(x.SelectPlan, fkPlans, childIndent)
+	case *Update:
+		err = e.explainDMLChildrenInRowFormat(x.SelectPlan, fkTriggerPlans(x.FKChecks, x.FKCascades), childIndent)
 	case *Delete:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
-		}
+		err = e.explainDMLChildrenInRowFormat(x.SelectPlan, fkTriggerPlans(x.FKChecks, x.FKCascades), childIndent)
 	case *Execute:
 		if x.Plan != nil {
 			err = e.explainPlanInRowFormat(x.Plan, "root", "", indent, true)
@@ -1406,6 +1505,27 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, driverSide, indent st
 	return
 }
 
+// explainDMLChildrenInRowFormat generates explain information for the select plan and the
+// foreign key checkers and cascades of the DML plans.
+func (e *Explain) explainDMLChildrenInRowFormat(selectPlan PhysicalPlan, fkPlans []Plan, childIndent string) (err error) {
+	if e.ctx == nil || !e.ctx.GetSessionVars().ForeignKeyChecks {
+		fkPlans = nil
+	}
+	if selectPlan != nil {
+		err = e.explainPlanInRowFormat(selectPlan, "root", "", childIndent, len(fkPlans) == 0)
+		if err != nil {
+			return
+		}
+	}
+	for i, p := range fkPlans {
+		err = e.explainPlanInRowFormat(p, "root", "", childIndent, i == len(fkPlans)-1)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
 func getRuntimeInfo(ctx sessionctx.Context, p Plan, runtimeStatsColl *execdetails.RuntimeStatsColl) (actRows, analyzeInfo, memoryInfo, diskInfo string) {
 	if runtimeStatsColl == nil {
 		runtimeStatsColl = ctx.GetSessionVars().StmtCtx.RuntimeStatsColl
@@ -108,4 +108,6 @@ var (
 	ErrPartitionNoTemporary     = dbterror.ClassOptimizer.NewStd(mysql.ErrPartitionNoTemporary)
 	ErrViewSelectTemporaryTable = dbterror.ClassOptimizer.NewStd(mysql.ErrViewSelectTmptable)
 	ErrSubqueryMoreThan1Row     = dbterror.ClassOptimizer.NewStd(mysql.ErrSubqueryNo1Row)
+	ErrRowIsReferenced2         = dbterror.ClassOptimizer.NewStd(mysql.ErrRowIsReferenced2)
+	ErrNoReferencedRow2         = dbterror.ClassOptimizer.NewStd(mysql.ErrNoReferencedRow2)
 )
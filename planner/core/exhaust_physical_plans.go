@@ -1746,12 +1746,19 @@ func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJ
 }
 
 func checkChildFitBC(p Plan) bool {
-	if p.statsInfo().HistColl == nil {
-		return p.SCtx().GetSessionVars().BroadcastJoinThresholdCount == -1 || p.statsInfo().Count() < p.SCtx().GetSessionVars().BroadcastJoinThresholdCount
+	return CheckFitBroadcast(p.SCtx(), p.statsInfo(), p.Schema())
+}
+
+// CheckFitBroadcast checks whether the data with the stats and schema is small
+// enough to be broadcast in an MPP join.
+func CheckFitBroadcast(sctx sessionctx.Context, stats *property.StatsInfo, schema *expression.Schema) bool {
+	sessVars := sctx.GetSessionVars()
+	if stats.HistColl == nil {
+		return sessVars.BroadcastJoinThresholdCount == -1 || stats.Count() < sessVars.BroadcastJoinThresholdCount
 	}
-	avg := p.statsInfo().HistColl.GetAvgRowSize(p.SCtx(), p.Schema().Columns, false, false)
-	sz := avg * float64(p.statsInfo().Count())
-	return p.SCtx().GetSessionVars().BroadcastJoinThresholdSize == -1 || sz < float64(p.SCtx().GetSessionVars().BroadcastJoinThresholdSize)
+	avg := stats.HistColl.GetAvgRowSize(sctx, schema.Columns, false, false)
+	sz := avg * float64(stats.Count())
+	return sessVars.BroadcastJoinThresholdSize == -1 || sz < float64(sessVars.BroadcastJoinThresholdSize)
 }
 
 // If we can use mpp broadcast join, that's our first choice.
@@ -1999,6 +2006,49 @@ func (p *LogicalJoin) tryToGetMppHashJoin(prop *property.PhysicalProperty, useBC
 	return []PhysicalPlan{join}
 }
 
+// CanPushToMPP checks whether the join can be executed by TiFlash in the MPP mode.
+// The cartesian product is only supported by the broadcast join.
+func (p *LogicalJoin) CanPushToMPP() bool {
+	switch p.JoinType {
+	case InnerJoin, LeftOuterJoin, RightOuterJoin, SemiJoin, AntiSemiJoin:
+	default:
+		return false
+	}
+	if len(p.EqualConditions) == 0 && p.ctx.GetSessionVars().AllowCartesianBCJ == 0 {
+		return false
+	}
+	if len(p.LeftConditions) != 0 && p.JoinType != LeftOuterJoin {
+		return false
+	}
+	if len(p.RightConditions) != 0 && p.JoinType != RightOuterJoin {
+		return false
+	}
+	return canExprsInJoinPushdown(p, kv.TiFlash)
+}
+
+// NewPhysicalMPPHashJoin creates a PhysicalHashJoin which is executed by TiFlash in the MPP mode.
+// The buildIdx is the index of the child which is used to build the hash table.
+func NewPhysicalMPPHashJoin(p *LogicalJoin, buildIdx int, useBCJ bool, newStats *property.StatsInfo, prop ...*property.PhysicalProperty) *PhysicalHashJoin {
+	lkeys, rkeys, _, _ := p.GetJoinKeys()
+	baseJoin := basePhysicalJoin{
+		JoinType:        p.JoinType,
+		LeftConditions:  p.LeftConditions,
+		RightConditions: p.RightConditions,
+		OtherConditions: p.OtherConditions,
+		DefaultValues:   p.DefaultValues,
+		LeftJoinKeys:    lkeys,
+		RightJoinKeys:   rkeys,
+		InnerChildIdx:   buildIdx,
+	}
+	return PhysicalHashJoin{
+		basePhysicalJoin: baseJoin,
+		Concurrency:      uint(p.ctx.GetSessionVars().CopTiFlashConcurrencyFactor),
+		EqualConditions:  p.EqualConditions,
+		storeTp:          kv.TiFlash,
+		mppShuffleJoin:   !useBCJ,
+	}.Init(p.ctx, newStats, p.blockOffset, prop...)
+}
+
 func choosePartitionKeys(keys []*property.MPPPartitionColumn, matches []int) []*property.MPPPartitionColumn {
 	newKeys := make([]*property.MPPPartitionColumn, 0, len(matches))
 	for _, id := range matches {
@@ -1110,6 +1110,28 @@ func (p *TiKVSingleGather) ExplainInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *TiKVDoubleGather) ExplainInfo() string {
+	return p.Source.ExplainInfo() + ", index:" + p.Index.Name.String()
+}
+
+// ExplainInfo implements Plan interface.
+func (p *TiKVIndexMergeGather) ExplainInfo() string {
+	return p.Source.ExplainInfo()
+}
+
+// ExplainInfo implements Plan interface.
+func (p *TiKVPointGet) ExplainInfo() string {
+	buffer := bytes.NewBufferString(p.Source.ExplainInfo())
+	if p.Index != nil {
+		buffer.WriteString(", index:" + p.Index.Name.String())
+	} else if p.Source.handleCols != nil {
+		fmt.Fprintf(buffer, ", pk col:%s", p.Source.handleCols)
+	}
+	fmt.Fprintf(buffer, ", cond:%v", p.AccessConds)
+	return buffer.String()
+}
+
 // MetricTableTimeFormat is the time format for metric table explain and format.
 const MetricTableTimeFormat = "2006-01-02 15:04:05.999"
 
@@ -1462,9 +1462,12 @@ func (er *expressionRewriter) inToExpression(lLen int, not bool, tp *types.Field
 			if c, ok := args[i].(*expression.Constant); ok {
 				var isExceptional bool
 				if expression.MaybeOverOptimized4PlanCache(er.sctx, []expression.Expression{c}) {
-					if c.GetType().EvalType() == types.ETString {
+					cEt := c.GetType().EvalType()
+					if cEt == types.ETString || (er.sctx.GetSessionVars().StmtCtx.InNonPreparedPlanBuilding && cEt != types.ETInt) {
 						// To keep the result be compatible with MySQL, refine `int non-constant <cmp> str constant`
-						// here and skip this refine operation in all other cases for safety.
+						// here and skip this refine operation in all other cases for safety. The plans of the
+						// non-prepared plan cache comparing int columns with non-int literals aren't cached either,
+						// see compareFunctionClass.refineArgs.
 						er.sctx.GetSessionVars().StmtCtx.SkipPlanCache = true
 						expression.RemoveMutableConst(er.sctx, []expression.Expression{c})
 					} else {
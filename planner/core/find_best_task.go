@@ -1020,6 +1020,30 @@ func (ds *DataSource) canConvertToPointGetForPlanCache(path *util.AccessPath) bo
 	return false
 }
 
+// canConvertToTiKVPointGet checks whether the ranges built from the access conditions
+// can be read by [batch] point get, it is used by the cascades planner.
+func (ds *DataSource) canConvertToTiKVPointGet(ranges []*ranger.Range, accessConds []expression.Expression) bool {
+	if len(ranges) == 0 || !ds.isPointGetConvertableSchema() || tidbutil.IsMemDB(ds.DBName.L) {
+		return false
+	}
+	// The partitions are pruned by the gathers in the cascades planner, so we
+	// do not build [batch] point get for partitioned tables now.
+	if ds.tableInfo.GetPartitionInfo() != nil {
+		return false
+	}
+	if expression.MaybeOverOptimized4PlanCache(ds.ctx, accessConds) &&
+		!ds.canConvertToPointGetForPlanCache(&util.AccessPath{Ranges: ranges, AccessConds: accessConds}) {
+		return false
+	}
+	for _, ran := range ranges {
+		// unique indexes can have duplicated NULL rows so we cannot use PointGet if there is NULL
+		if !ran.IsPointNonNullable(ds.ctx) {
+			return false
+		}
+	}
+	return true
+}
+
 func (ds *DataSource) convertToIndexMergeScan(prop *property.PhysicalProperty, candidate *candidatePath) (task task, err error) {
 	if prop.TaskTp != property.RootTaskType || !prop.IsEmpty() {
 		return invalidTask, nil
@@ -1298,10 +1322,16 @@ func (ts *PhysicalTableScan) appendExtraHandleCol(ds *DataSource) (*expression.C
 // When we add the extra selection, it should meet two conditions:
 // 1. The length of 'ds.pushedDownConds` should not be zero.
 // 2. The result of function `MaybeOverOptimized4PlanCache(ds.pushedDownConds)` call needs to return true.
+// 3. The plan isn't built for the non-prepared plan cache. Its parameters are the literals of the query, whose types
+// are a part of the cache key, and the plans comparing int columns with non-int literals, whose values would be
+// rounded when the ranges are rebuilt, aren't cached, so the rebuilt ranges are exact.
 func (ds *DataSource) addSelection4PlanCache(task *rootTask, stats *property.StatsInfo, prop *property.PhysicalProperty) {
 	if !expression.MaybeOverOptimized4PlanCache(ds.ctx, ds.pushedDownConds) || len(ds.pushedDownConds) == 0 {
 		return
 	}
+	if ds.ctx.GetSessionVars().StmtCtx.InNonPreparedPlanBuilding {
+		return
+	}
 	sel := PhysicalSelection{Conditions: ds.pushedDownConds}.Init(ds.ctx, stats, ds.blockOffset, prop)
 	sel.SetChildren(task.p)
 	task.p = sel
@@ -1749,6 +1779,9 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 		colSet.Insert(col.UniqueID)
 		curCorr := float64(0)
 		for _, item := range histColl.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCorrelation {
+				continue
+			}
 			if (col.ID == item.ColIDs[0] && path.FullIdxCols[0].ID == item.ColIDs[1]) ||
 				(col.ID == item.ColIDs[1] && path.FullIdxCols[0].ID == item.ColIDs[0]) {
 				curCorr = item.ScalarVals
@@ -1769,9 +1802,24 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 // GetPhysicalScan returns PhysicalTableScan for the LogicalTableScan.
 func (s *LogicalTableScan) GetPhysicalScan(schema *expression.Schema, stats *property.StatsInfo) *PhysicalTableScan {
 	ds := s.Source
+	columns := ds.Columns
+	if schema.Len() != len(columns) {
+		// The table side of a TiKVDoubleGather may output extra handle columns, and
+		// the partial table scans of a TiKVIndexMergeGather only output the handle.
+		columns = make([]*model.ColumnInfo, 0, schema.Len())
+		for _, col := range schema.Columns {
+			if idx := ds.schema.ColumnIndex(col); idx != -1 {
+				columns = append(columns, ds.Columns[idx])
+			} else if col.ID == model.ExtraHandleID {
+				columns = append(columns, model.NewExtraHandleColInfo())
+			} else {
+				columns = append(columns, col.ToInfo())
+			}
+		}
+	}
 	ts := PhysicalTableScan{
 		Table:           ds.tableInfo,
-		Columns:         ds.Columns,
+		Columns:         columns,
 		TableAsName:     ds.TableAsName,
 		DBName:          ds.DBName,
 		isPartition:     ds.isPartition,
@@ -1809,7 +1857,8 @@ func (s *LogicalIndexScan) GetPhysicalIndexScan(schema *expression.Schema, stats
 		physicalTableID:  ds.physicalTableID,
 	}.Init(ds.ctx, ds.blockOffset)
 	is.stats = stats
-	is.initSchema(s.FullIdxCols, s.IsDoubleRead)
+	idxExprCols := s.FullIdxCols[:len(s.FullIdxCols):len(s.FullIdxCols)]
+	is.initSchema(append(idxExprCols, ds.commonHandleCols...), s.IsDoubleRead)
 	return is
 }
 
@@ -0,0 +1,374 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+)
+
+// FKLookup describes how to look up the rows of a table by the values of some columns.
+type FKLookup struct {
+	// Tbl is the looked up table, it's nil if the table doesn't exist.
+	Tbl table.Table
+	// Cols are the matched columns of Tbl.
+	Cols []*model.ColumnInfo
+	// Idx is the index whose leading columns are Cols.
+	Idx *model.IndexInfo
+	// IsHandle indicates the leading columns of the handle are Cols.
+	// The rows are scanned from the whole table if Idx is nil and IsHandle is false, it only happens to the
+	// foreign keys created before the DDL requires the indexes on the foreign key columns.
+	IsHandle bool
+	// ColOffsets are the offsets of the values to be matched in the modified row.
+	ColOffsets []int
+
+	tblName model.CIStr
+}
+
+// AccessObject implements dataAccesser interface.
+func (l *FKLookup) AccessObject(_ bool) string {
+	buffer := bytes.NewBufferString("table:")
+	buffer.WriteString(l.tblName.O)
+	switch {
+	case l.Idx != nil:
+		buffer.WriteString(", index:" + l.Idx.Name.O + "(")
+		for i, col := range l.Idx.Columns {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(col.Name.O)
+		}
+		buffer.WriteString(")")
+	case l.IsHandle:
+		buffer.WriteString(", handle")
+	}
+	return buffer.String()
+}
+
+// FKCheck indicates the foreign key constraint checker.
+type FKCheck struct {
+	baseSchemaProducer
+	FKLookup
+
+	FK         *model.FKInfo
+	ReferredFK *model.ReferredFKInfo
+	// CheckExist is true if the rows looked up must exist, it's used to check the
+	// parent rows of the modified child rows. Otherwise it's used to check the
+	// child rows of the modified parent rows, and the looked up rows must not exist.
+	CheckExist bool
+	FailedErr  error
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (p *FKCheck) OperatorInfo(_ bool) string {
+	if p.CheckExist {
+		return fmt.Sprintf("foreign_key:%s, check_exist", p.FK.Name.O)
+	}
+	return fmt.Sprintf("foreign_key:%s, check_not_exist", p.FK.Name.O)
+}
+
+// FKCascadeType indicates in which (delete/update) statements the cascade action happens.
+type FKCascadeType int8
+
+const (
+	// FKCascadeOnDelete indicates the cascade action happens when the parent rows are deleted.
+	FKCascadeOnDelete FKCascadeType = 1
+	// FKCascadeOnUpdate indicates the cascade action happens when the parent rows are updated.
+	FKCascadeOnUpdate FKCascadeType = 2
+)
+
+// String implements the fmt.Stringer interface.
+func (tp FKCascadeType) String() string {
+	if tp == FKCascadeOnDelete {
+		return "on_delete"
+	}
+	return "on_update"
+}
+
+// FKCascade indicates the CASCADE and SET NULL actions on the child rows of the modified parent rows.
+type FKCascade struct {
+	baseSchemaProducer
+	// FKLookup looks up the child rows.
+	FKLookup
+
+	Tp         FKCascadeType
+	Action     ast.ReferOptionType
+	FK         *model.FKInfo
+	ReferredFK *model.ReferredFKInfo
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (p *FKCascade) OperatorInfo(_ bool) string {
+	return fmt.Sprintf("foreign_key:%s, %s:%s", p.FK.Name.O, p.Tp, p.Action)
+}
+
+// BuildOnInsertFKChecks builds the checkers which check that the parent rows of the inserted rows exist.
+func BuildOnInsertFKChecks(ctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table) []*FKCheck {
+	return buildChildFKChecks(ctx, is, tbl, nil)
+}
+
+// BuildOnDeleteFKTriggers builds the checkers and the cascades for the child rows of the deleted rows.
+func BuildOnDeleteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table) ([]*FKCheck, []*FKCascade) {
+	return buildParentFKTriggers(ctx, is, tbl, FKCascadeOnDelete, nil)
+}
+
+// BuildOnUpdateFKTriggers builds the checkers and the cascades for updating the columns in updatedCols.
+// The parent rows of the updated rows are checked, and the child rows of the updated rows are checked or cascaded.
+func BuildOnUpdateFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table, updatedCols map[string]struct{}) ([]*FKCheck, []*FKCascade) {
+	checks := buildChildFKChecks(ctx, is, tbl, updatedCols)
+	parentChecks, cascades := buildParentFKTriggers(ctx, is, tbl, FKCascadeOnUpdate, updatedCols)
+	return append(checks, parentChecks...), cascades
+}
+
+func buildChildFKChecks(ctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table, updatedCols map[string]struct{}) []*FKCheck {
+	tblInfo := tbl.Meta()
+	if len(tblInfo.ForeignKeys) == 0 {
+		return nil
+	}
+	dbInfo, ok := is.SchemaByTable(tblInfo)
+	if !ok {
+		return nil
+	}
+	var checks []*FKCheck
+	for _, fk := range tblInfo.ForeignKeys {
+		// The foreign key in write only state is checked, so the rows written while it's being added are valid.
+		if (fk.State != model.StatePublic && fk.State != model.StateWriteOnly) || !anyColUpdated(fk.Cols, updatedCols) {
+			continue
+		}
+		colOffsets, ok := columnOffsets(tblInfo, fk.Cols)
+		if !ok {
+			continue
+		}
+		refSchema := fk.RefSchema
+		if refSchema.L == "" {
+			refSchema = dbInfo.Name
+		}
+		lookup := FKLookup{tblName: fk.RefTable, ColOffsets: colOffsets}
+		if refTbl, err := is.TableByName(refSchema, fk.RefTable); err == nil {
+			buildFKLookup(&lookup, refTbl, fk.RefCols)
+		}
+		checks = append(checks, FKCheck{
+			FKLookup:   lookup,
+			FK:         fk,
+			CheckExist: true,
+			FailedErr:  ErrNoReferencedRow2.FastGenByArgs(ddl.FKDescription(dbInfo.Name, tblInfo.Name, fk)),
+		}.Init(ctx))
+	}
+	return checks
+}
+
+func buildParentFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table, tp FKCascadeType, updatedCols map[string]struct{}) ([]*FKCheck, []*FKCascade) {
+	tblInfo := tbl.Meta()
+	dbInfo, ok := is.SchemaByTable(tblInfo)
+	if !ok {
+		return nil, nil
+	}
+	var (
+		checks   []*FKCheck
+		cascades []*FKCascade
+	)
+	for _, referredFK := range is.GetTableReferredForeignKeys(dbInfo.Name.L, tblInfo.Name.L) {
+		if !anyColUpdated(referredFK.Cols, updatedCols) {
+			continue
+		}
+		colOffsets, ok := columnOffsets(tblInfo, referredFK.Cols)
+		if !ok {
+			continue
+		}
+		childTbl, err := is.TableByName(referredFK.ChildSchema, referredFK.ChildTable)
+		if err != nil {
+			continue
+		}
+		var fk *model.FKInfo
+		for _, childFK := range childTbl.Meta().ForeignKeys {
+			if childFK.Name.L == referredFK.ChildFKName.L {
+				fk = childFK
+				break
+			}
+		}
+		if fk == nil {
+			continue
+		}
+		lookup := FKLookup{tblName: referredFK.ChildTable, ColOffsets: colOffsets}
+		buildFKLookup(&lookup, childTbl, fk.Cols)
+		action := ast.ReferOptionType(fk.OnDelete)
+		if tp == FKCascadeOnUpdate {
+			action = ast.ReferOptionType(fk.OnUpdate)
+		}
+		switch action {
+		case ast.ReferOptionCascade, ast.ReferOptionSetNull:
+			cascades = append(cascades, FKCascade{
+				FKLookup:   lookup,
+				Tp:         tp,
+				Action:     action,
+				FK:         fk,
+				ReferredFK: referredFK,
+			}.Init(ctx))
+		default:
+			// RESTRICT, NO ACTION and the default behaviour reject the modification if any child row exists.
+			// SET DEFAULT is not supported by InnoDB, so it's regarded as RESTRICT.
+			checks = append(checks, FKCheck{
+				FKLookup:   lookup,
+				FK:         fk,
+				ReferredFK: referredFK,
+				FailedErr:  ErrRowIsReferenced2.FastGenByArgs(ddl.FKDescription(referredFK.ChildSchema, referredFK.ChildTable, fk)),
+			}.Init(ctx))
+		}
+	}
+	return checks, cascades
+}
+
+// buildFKLookup chooses how to look up the rows of tbl by cols.
+func buildFKLookup(lookup *FKLookup, tbl table.Table, cols []model.CIStr) {
+	tblInfo := tbl.Meta()
+	lookup.Cols = make([]*model.ColumnInfo, 0, len(cols))
+	for _, name := range cols {
+		col := model.FindColumnInfo(tblInfo.Columns, name.L)
+		if col == nil || col.State != model.StatePublic {
+			// Regard the table as not existing if the columns don't exist.
+			lookup.Cols = nil
+			return
+		}
+		lookup.Cols = append(lookup.Cols, col)
+	}
+	lookup.Tbl = tbl
+	lookup.IsHandle, lookup.Idx = ddl.FindFKLookupIndex(tblInfo, cols)
+}
+
+func anyColUpdated(cols []model.CIStr, updatedCols map[string]struct{}) bool {
+	if updatedCols == nil {
+		return true
+	}
+	for _, col := range cols {
+		if _, ok := updatedCols[col.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func columnOffsets(tblInfo *model.TableInfo, cols []model.CIStr) ([]int, bool) {
+	offsets := make([]int, 0, len(cols))
+	for _, name := range cols {
+		col := model.FindColumnInfo(tblInfo.Columns, name.L)
+		if col == nil {
+			return nil, false
+		}
+		offsets = append(offsets, col.Offset)
+	}
+	return offsets, true
+}
+
+// buildOnInsertFKTriggers builds the foreign key checkers of the insert plan.
+// For `REPLACE`, the replaced rows are deleted. For `INSERT ... ON DUPLICATE KEY UPDATE`,
+// the duplicated rows are updated.
+func (p *Insert) buildOnInsertFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, onDupColSet map[string]struct{}) {
+	p.FKChecks = BuildOnInsertFKChecks(ctx, is, p.Table)
+	var checks []*FKCheck
+	switch {
+	case p.IsReplace:
+		checks, p.FKCascades = BuildOnDeleteFKTriggers(ctx, is, p.Table)
+	case len(onDupColSet) > 0:
+		// The child side checks are already built for the inserted rows, so only build the parent side triggers here.
+		checks, p.FKCascades = buildParentFKTriggers(ctx, is, p.Table, FKCascadeOnUpdate, onDupColSet)
+	}
+	p.FKChecks = append(p.FKChecks, checks...)
+}
+
+// buildOnUpdateFKTriggers builds the foreign key checkers and cascades of the update plan.
+func (updt *Update) buildOnUpdateFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) {
+	updatedColsByTbl := make(map[int64]map[string]struct{}, len(updt.TblColPosInfos))
+	for _, assign := range updt.OrderedList {
+		for _, content := range updt.TblColPosInfos {
+			if len(updt.TblColPosInfos) > 1 && (assign.Col.Index < content.Start || assign.Col.Index >= content.End) {
+				continue
+			}
+			if updatedColsByTbl[content.TblID] == nil {
+				updatedColsByTbl[content.TblID] = make(map[string]struct{})
+			}
+			updatedColsByTbl[content.TblID][assign.ColName.L] = struct{}{}
+		}
+	}
+	for tblID, updatedCols := range updatedColsByTbl {
+		tbl, ok := tblID2table[tblID]
+		if !ok {
+			continue
+		}
+		checks, cascades := BuildOnUpdateFKTriggers(ctx, is, tbl, updatedCols)
+		if len(checks) > 0 {
+			if updt.FKChecks == nil {
+				updt.FKChecks = make(map[int64][]*FKCheck)
+			}
+			updt.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if updt.FKCascades == nil {
+				updt.FKCascades = make(map[int64][]*FKCascade)
+			}
+			updt.FKCascades[tblID] = cascades
+		}
+	}
+}
+
+// buildOnDeleteFKTriggers builds the foreign key checkers and cascades of the delete plan.
+func (del *Delete) buildOnDeleteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) {
+	for tblID, tbl := range tblID2table {
+		checks, cascades := BuildOnDeleteFKTriggers(ctx, is, tbl)
+		if len(checks) > 0 {
+			if del.FKChecks == nil {
+				del.FKChecks = make(map[int64][]*FKCheck)
+			}
+			del.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if del.FKCascades == nil {
+				del.FKCascades = make(map[int64][]*FKCascade)
+			}
+			del.FKCascades[tblID] = cascades
+		}
+	}
+}
+
+// fkTriggerPlans returns the foreign key checkers and cascades ordered by the table ID, it's used by explain.
+func fkTriggerPlans(checks map[int64][]*FKCheck, cascades map[int64][]*FKCascade) []Plan {
+	tblIDs := make([]int64, 0, len(checks)+len(cascades))
+	for tblID := range checks {
+		tblIDs = append(tblIDs, tblID)
+	}
+	for tblID := range cascades {
+		if _, ok := checks[tblID]; !ok {
+			tblIDs = append(tblIDs, tblID)
+		}
+	}
+	sort.Slice(tblIDs, func(i, j int) bool { return tblIDs[i] < tblIDs[j] })
+	var plans []Plan
+	for _, tblID := range tblIDs {
+		for _, check := range checks[tblID] {
+			plans = append(plans, check)
+		}
+		for _, cascade := range cascades[tblID] {
+			plans = append(plans, cascade)
+		}
+	}
+	return plans
+}
@@ -38,6 +38,9 @@ type HandleCols interface {
 	// BuildHandleFromIndexRow builds a Handle from index row data.
 	// The last column(s) of `row` must be the handle column(s).
 	BuildHandleFromIndexRow(row chunk.Row) (kv.Handle, error)
+	// BuildPartitionHandleFromIndexRow builds a PartitionHandle from the row data of a global index.
+	// The last column of `row` must be the partition ID, and the handle column(s) must be before it.
+	BuildPartitionHandleFromIndexRow(row chunk.Row) (kv.PartitionHandle, error)
 	// ResolveIndices resolves handle column indices.
 	ResolveIndices(schema *expression.Schema) (HandleCols, error)
 	// IsInt returns if the HandleCols is a single tnt column.
@@ -89,6 +92,19 @@ func (cb *CommonHandleCols) BuildHandleFromIndexRow(row chunk.Row) (kv.Handle, e
 	return cb.buildHandleByDatumsBuffer(datumBuf)
 }
 
+// BuildPartitionHandleFromIndexRow implements the kv.HandleCols interface.
+func (cb *CommonHandleCols) BuildPartitionHandleFromIndexRow(row chunk.Row) (kv.PartitionHandle, error) {
+	datumBuf := make([]types.Datum, 0, 4)
+	for i := 0; i < cb.NumCols(); i++ {
+		datumBuf = append(datumBuf, row.GetDatum(row.Len()-1-cb.NumCols()+i, cb.columns[i].RetType))
+	}
+	handle, err := cb.buildHandleByDatumsBuffer(datumBuf)
+	if err != nil {
+		return kv.PartitionHandle{}, err
+	}
+	return kv.NewPartitionHandle(row.GetInt64(row.Len()-1), handle), nil
+}
+
 // BuildHandleByDatums implements the kv.HandleCols interface.
 func (cb *CommonHandleCols) BuildHandleByDatums(row []types.Datum) (kv.Handle, error) {
 	datumBuf := make([]types.Datum, 0, 4)
@@ -200,6 +216,11 @@ func (ib *IntHandleCols) BuildHandleFromIndexRow(row chunk.Row) (kv.Handle, erro
 	return kv.IntHandle(row.GetInt64(row.Len() - 1)), nil
 }
 
+// BuildPartitionHandleFromIndexRow implements the kv.HandleCols interface.
+func (ib *IntHandleCols) BuildPartitionHandleFromIndexRow(row chunk.Row) (kv.PartitionHandle, error) {
+	return kv.NewPartitionHandle(row.GetInt64(row.Len()-1), kv.IntHandle(row.GetInt64(row.Len()-2))), nil
+}
+
 // BuildHandleByDatums implements the kv.HandleCols interface.
 func (ib *IntHandleCols) BuildHandleByDatums(row []types.Datum) (kv.Handle, error) {
 	return kv.IntHandle(row[ib.col.Index].GetInt64()), nil
@@ -47,6 +47,30 @@ func (sg TiKVSingleGather) Init(ctx sessionctx.Context, offset int) *TiKVSingleG
 	return &sg
 }
 
+// Init initializes TiKVDoubleGather.
+func (dg TiKVDoubleGather) Init(ctx sessionctx.Context, offset int) *TiKVDoubleGather {
+	dg.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTiKVDoubleGather, &dg, offset)
+	return &dg
+}
+
+// Init initializes TiKVPointGet.
+func (pg TiKVPointGet) Init(ctx sessionctx.Context, offset int) *TiKVPointGet {
+	pg.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTiKVPointGet, &pg, offset)
+	return &pg
+}
+
+// Init initializes TiFlashMPPGather.
+func (mg TiFlashMPPGather) Init(ctx sessionctx.Context, offset int) *TiFlashMPPGather {
+	mg.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTiFlashMPPGather, &mg, offset)
+	return &mg
+}
+
+// Init initializes TiKVIndexMergeGather.
+func (g TiKVIndexMergeGather) Init(ctx sessionctx.Context, offset int) *TiKVIndexMergeGather {
+	g.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTiKVIndexMergeGather, &g, offset)
+	return &g
+}
+
 // Init initializes LogicalTableScan.
 func (ts LogicalTableScan) Init(ctx sessionctx.Context, offset int) *LogicalTableScan {
 	ts.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTableScan, &ts, offset)
@@ -238,6 +262,18 @@ func (p Delete) Init(ctx sessionctx.Context) *Delete {
 	return &p
 }
 
+// Init initializes FKCheck.
+func (p FKCheck) Init(ctx sessionctx.Context) *FKCheck {
+	p.basePlan = newBasePlan(ctx, plancodec.TypeForeignKeyCheck, 0)
+	return &p
+}
+
+// Init initializes FKCascade.
+func (p FKCascade) Init(ctx sessionctx.Context) *FKCascade {
+	p.basePlan = newBasePlan(ctx, plancodec.TypeForeignKeyCascade, 0)
+	return &p
+}
+
 // Init initializes Insert.
 func (p Insert) Init(ctx sessionctx.Context) *Insert {
 	p.basePlan = newBasePlan(ctx, plancodec.TypeInsert, 0)
@@ -375,15 +411,21 @@ func (p PhysicalUnionScan) Init(ctx sessionctx.Context, stats *property.StatsInf
 // Init initializes PhysicalIndexLookUpReader.
 func (p PhysicalIndexLookUpReader) Init(ctx sessionctx.Context, offset int) *PhysicalIndexLookUpReader {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeIndexLookUp, &p, offset)
-	p.TablePlans = flattenPushDownPlan(p.tablePlan)
-	p.IndexPlans = flattenPushDownPlan(p.indexPlan)
-	p.schema = p.tablePlan.Schema()
+	if p.tablePlan != nil && p.indexPlan != nil {
+		p.TablePlans = flattenPushDownPlan(p.tablePlan)
+		p.IndexPlans = flattenPushDownPlan(p.indexPlan)
+		p.schema = p.tablePlan.Schema()
+	}
 	return &p
 }
 
 // Init initializes PhysicalIndexMergeReader.
 func (p PhysicalIndexMergeReader) Init(ctx sessionctx.Context, offset int) *PhysicalIndexMergeReader {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeIndexMerge, &p, offset)
+	if p.tablePlan == nil && len(p.partialPlans) == 0 {
+		// The children are attached later by SetChildren.
+		return &p
+	}
 	if p.tablePlan != nil {
 		p.stats = p.tablePlan.statsInfo()
 	} else {
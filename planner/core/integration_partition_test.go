@@ -1149,3 +1149,41 @@ func TestIssue27532(t *testing.T) {
 	tk.MustQuery(`select * from t2`).Sort().Check(testkit.Rows("1 1 1 1", "2 2 2 2", "3 3 3 3", "4 4 4 4"))
 	tk.MustExec(`drop table t2`)
 }
+
+func TestKeyPartitionPruning(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database key_partition_pruning")
+	defer tk.MustExec(`drop database key_partition_pruning`)
+	tk.MustExec("use key_partition_pruning")
+	tk.MustExec(`set @@tidb_partition_prune_mode="dynamic"`)
+	tk.MustExec(`create table t (a int, b varchar(10), c int, key(c)) partition by key(a, b) partitions 5`)
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf(`insert into t values (%d, 'b%d', %d)`, i, i%3, i))
+	}
+	partitionOf := func(a int) string {
+		for p := 0; p < 5; p++ {
+			if len(tk.MustQuery(fmt.Sprintf("select 1 from t partition (p%d) where c = %d", p, a)).Rows()) > 0 {
+				return fmt.Sprintf("p%d", p)
+			}
+		}
+		return ""
+	}
+	for i := 0; i < 20; i++ {
+		part := partitionOf(i)
+		require.NotEqual(t, "", part)
+		sql := fmt.Sprintf("select c from t where a = %d and b = 'b%d'", i, i%3)
+		rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		require.Contains(t, fmt.Sprintf("%v", rows), "partition:"+part+" ")
+		tk.MustQuery(sql).Check(testkit.Rows(fmt.Sprintf("%d", i)))
+	}
+	// All the partitions are accessed without the values of all the partitioning columns.
+	rows := tk.MustQuery("explain format = 'brief' select c from t where a = 1").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "partition:all")
+	rows = tk.MustQuery("explain format = 'brief' select c from t where (a, b) in ((1, 'b1'), (2, 'b2'))").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "partition:")
+	tk.MustQuery("select c from t where (a, b) in ((1, 'b1'), (2, 'b2'))").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select c from t where a is null and b is null").Check(testkit.Rows())
+}
@@ -3481,6 +3481,46 @@ func (s *testIntegrationSuite) TestReorderSimplifiedOuterJoins(c *C) {
 	}
 }
 
+func (s *testIntegrationSuite) TestOuterJoinReorder(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1,t2,t3,t4")
+	tk.MustExec("create table t1 (a int, b int, key(a))")
+	tk.MustExec("create table t2 (a int, b int, key(a))")
+	tk.MustExec("create table t3 (a int, b int, key(a))")
+	tk.MustExec("create table t4 (a int, b int, key(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (null, null)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, null), (4, 4), (9, 9)")
+	tk.MustExec("insert into t3 values (1, 1), (2, null), (4, 4)")
+	tk.MustExec("insert into t4 values (1, 1), (3, 3), (5, 5), (7, 7), (9, 9), (null, 1)")
+	tk.MustExec("analyze table t1, t2, t3, t4")
+
+	var input []string
+	var output []struct {
+		SQL    string
+		Plan   []string
+		Result []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + tt).Rows())
+			output[i].Result = s.testData.ConvertRowsToStrings(tk.MustQuery(tt).Sort().Rows())
+		})
+		tk.MustQuery("explain format = 'brief' " + tt).Check(testkit.Rows(output[i].Plan...))
+		tk.MustQuery(tt).Sort().Check(testkit.Rows(output[i].Result...))
+		// The results must be the same as the ones of the original join order and the DP solver.
+		tk.MustExec("set @@tidb_enable_outer_join_reorder = 0")
+		tk.MustQuery(tt).Sort().Check(testkit.Rows(output[i].Result...))
+		tk.MustExec("set @@tidb_enable_outer_join_reorder = 1")
+		tk.MustExec("set @@tidb_opt_join_reorder_threshold = 10")
+		tk.MustQuery(tt).Sort().Check(testkit.Rows(output[i].Result...))
+		tk.MustExec("set @@tidb_opt_join_reorder_threshold = default")
+	}
+}
+
 // Apply operator may got panic because empty Projection is eliminated.
 func (s *testIntegrationSerialSuite) TestIssue23887(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
@@ -46,6 +46,10 @@ var (
 	_ LogicalPlan = &LogicalTableDual{}
 	_ LogicalPlan = &DataSource{}
 	_ LogicalPlan = &TiKVSingleGather{}
+	_ LogicalPlan = &TiKVDoubleGather{}
+	_ LogicalPlan = &TiKVPointGet{}
+	_ LogicalPlan = &TiKVIndexMergeGather{}
+	_ LogicalPlan = &TiFlashMPPGather{}
 	_ LogicalPlan = &LogicalTableScan{}
 	_ LogicalPlan = &LogicalIndexScan{}
 	_ LogicalPlan = &LogicalUnionAll{}
@@ -341,6 +345,10 @@ type LogicalAggregation struct {
 	// noCopPushDown indicates if planner must not push this agg down to coprocessor.
 	// It is true when the agg is in the outer child tree of apply.
 	noCopPushDown bool
+
+	// IsPartial indicates whether it is the partial aggregation split from a
+	// complete one, which can be executed on any partition of the data.
+	IsPartial bool
 }
 
 // HasDistinct shows whether LogicalAggregation has functions with distinct.
@@ -607,6 +615,68 @@ type TiKVSingleGather struct {
 	// PhysicalTableReader or PhysicalIndexReader.
 	IsIndexGather bool
 	Index         *model.IndexInfo
+	// PruningConds are the filters pushed down below this gather, they are
+	// used to prune the partitions of a partitioned table.
+	PruningConds []expression.Expression
+}
+
+// TiKVDoubleGather is a logical operator of TiDB layer to gather tuples
+// from TiKV regions by reading an index first and then looking up the
+// table rows by the handles read from the index. Its first child is the
+// index side and its second child is the table side.
+type TiKVDoubleGather struct {
+	logicalSchemaProducer
+	Source *DataSource
+	Index  *model.IndexInfo
+	// FullIdxCols and FullIdxColLens are used to decide whether a filter
+	// can be evaluated on the index side.
+	FullIdxCols    []*expression.Column
+	FullIdxColLens []int
+	// HandleCols are the handle columns in the schema of the table side.
+	HandleCols HandleCols
+	// PruningConds are the filters pushed down below this gather, they are
+	// used to prune the partitions of a partitioned table.
+	PruningConds []expression.Expression
+}
+
+// IsCoveredByIndex checks whether the columns can be read from the index side.
+func (p *TiKVDoubleGather) IsCoveredByIndex(cols []*expression.Column) bool {
+	return p.Source.isCoveringIndex(cols, p.FullIdxCols, p.FullIdxColLens, p.Source.tableInfo)
+}
+
+// TiKVIndexMergeGather is a logical operator of TiDB layer to gather tuples
+// from TiKV regions by reading the handles from several partial scans first
+// and then looking up the table rows by the union of the handles. Its last
+// child is the table side and the other children are the partial scans.
+type TiKVIndexMergeGather struct {
+	logicalSchemaProducer
+	Source *DataSource
+	// HandleCols are the handle columns read by the partial scans.
+	HandleCols HandleCols
+	// PruningConds are the filters used to prune the partitions of a partitioned table.
+	PruningConds []expression.Expression
+}
+
+// TiFlashMPPGather is a logical operator of TiDB layer to gather the results of
+// an MPP query which is executed by TiFlash. The operators below it are
+// executed in TiFlash, and their data can be exchanged between TiFlash nodes.
+type TiFlashMPPGather struct {
+	logicalSchemaProducer
+}
+
+// TiKVPointGet is a leaf logical operator of TiDB layer to read the rows
+// from TiKV by the point keys of the handle or a unique index.
+type TiKVPointGet struct {
+	logicalSchemaProducer
+	Source *DataSource
+	// Index is nil if the rows are read by the int handles.
+	Index      *model.IndexInfo
+	IdxCols    []*expression.Column
+	IdxColLens []int
+	// IsDoubleRead marks if the needed columns can not be covered by the Index.
+	IsDoubleRead bool
+	AccessConds  expression.CNFExprs
+	Ranges       []*ranger.Range
 }
 
 // LogicalTableScan is the logical table scan operator for TiKV.
@@ -700,24 +770,280 @@ func (ds *DataSource) buildIndexGather(path *util.AccessPath) LogicalPlan {
 	return sg
 }
 
-// Convert2Gathers builds logical TiKVSingleGathers from DataSource.
-func (ds *DataSource) Convert2Gathers() (gathers []LogicalPlan) {
-	tg := ds.buildTableGather()
-	gathers = append(gathers, tg)
+func (ds *DataSource) buildIndexLookUpGather(path *util.AccessPath) LogicalPlan {
+	is := LogicalIndexScan{
+		Source:         ds,
+		IsDoubleRead:   true,
+		Index:          path.Index,
+		FullIdxCols:    path.FullIdxCols,
+		FullIdxColLens: path.FullIdxColLens,
+	}.Init(ds.ctx, ds.blockOffset)
+	is.Columns = make([]*model.ColumnInfo, len(ds.Columns))
+	copy(is.Columns, ds.Columns)
+	is.SetSchema(ds.Schema())
+	is.IdxCols, is.IdxColLens = expression.IndexInfo2PrefixCols(is.Columns, is.schema.Columns, is.Index)
+
+	// The table side must output the handle columns, because the reader
+	// needs them to keep order and to check the looked up rows.
+	tblSchema := ds.Schema().Clone()
+	handleCols := ds.handleCols
+	if ds.tableInfo.IsCommonHandle {
+		for _, col := range ds.commonHandleCols {
+			if tblSchema.ColumnIndex(col) == -1 {
+				tblSchema.Append(col)
+			}
+		}
+	} else if handleCols == nil {
+		handleCol := ds.newExtraHandleSchemaCol()
+		tblSchema.Append(handleCol)
+		handleCols = NewIntHandleCols(handleCol)
+	}
+	// The HandleCols of the table side is nil, since it is a row id scan
+	// whose ranges are built from the handles read from the index side.
+	ts := LogicalTableScan{Source: ds}.Init(ds.ctx, ds.blockOffset)
+	ts.SetSchema(tblSchema)
+
+	dg := TiKVDoubleGather{
+		Source:         ds,
+		Index:          path.Index,
+		FullIdxCols:    path.FullIdxCols,
+		FullIdxColLens: path.FullIdxColLens,
+		HandleCols:     handleCols,
+	}.Init(ds.ctx, ds.blockOffset)
+	dg.SetSchema(ds.Schema())
+	dg.SetChildren(is, ts)
+	return dg
+}
+
+func (ds *DataSource) buildMPPGather() LogicalPlan {
+	ts := LogicalTableScan{Source: ds, HandleCols: ds.handleCols}.Init(ds.ctx, ds.blockOffset)
+	ts.SetSchema(ds.Schema())
+	mg := TiFlashMPPGather{}.Init(ds.ctx, ds.blockOffset)
+	mg.SetSchema(ds.Schema())
+	mg.SetChildren(ts)
+	return mg
+}
+
+// canUseMPP checks whether the DataSource can be read by a TiFlashMPPGather.
+// The partitioned tables and the virtual columns are not supported now.
+func (ds *DataSource) canUseMPP() bool {
+	if !ds.ctx.GetSessionVars().IsMPPAllowed() || ds.tableInfo.GetPartitionInfo() != nil {
+		return false
+	}
+	for _, col := range ds.schema.Columns {
+		if col.VirtualExpr != nil {
+			return false
+		}
+	}
 	for _, path := range ds.possibleAccessPaths {
-		if !path.IsIntHandlePath {
-			path.FullIdxCols, path.FullIdxColLens = expression.IndexInfo2Cols(ds.Columns, ds.schema.Columns, path.Index)
-			path.IdxCols, path.IdxColLens = expression.IndexInfo2PrefixCols(ds.Columns, ds.schema.Columns, path.Index)
-			// If index columns can cover all of the needed columns, we can use a IndexGather + IndexScan.
-			if ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo) {
-				gathers = append(gathers, ds.buildIndexGather(path))
+		if path.StoreType == kv.TiFlash {
+			return true
+		}
+	}
+	return false
+}
+
+// Convert2Gathers builds logical TiKVSingleGathers, TiKVDoubleGathers and TiFlashMPPGather from DataSource.
+func (ds *DataSource) Convert2Gathers() (gathers []LogicalPlan) {
+	canUseTiKVTable := true
+	if ds.canUseMPP() {
+		gathers = append(gathers, ds.buildMPPGather())
+		canUseTiKVTable = false
+		for _, path := range ds.possibleAccessPaths {
+			if path.IsTablePath() && path.StoreType == kv.TiKV {
+				canUseTiKVTable = true
 			}
-			// TODO: If index columns can not cover the schema, use IndexLookUpGather.
+		}
+	}
+	if canUseTiKVTable {
+		gathers = append(gathers, ds.buildTableGather())
+	}
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath() || path.PartialIndexPaths != nil || path.Index.Global {
+			continue
+		}
+		path.FullIdxCols, path.FullIdxColLens = expression.IndexInfo2Cols(ds.Columns, ds.schema.Columns, path.Index)
+		path.IdxCols, path.IdxColLens = expression.IndexInfo2PrefixCols(ds.Columns, ds.schema.Columns, path.Index)
+		// If index columns can cover all of the needed columns, we can use a IndexGather + IndexScan.
+		// Otherwise, we use a TiKVDoubleGather to look up the table rows.
+		if ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo) {
+			gathers = append(gathers, ds.buildIndexGather(path))
+		} else {
+			gathers = append(gathers, ds.buildIndexLookUpGather(path))
 		}
 	}
 	return gathers
 }
 
+// Convert2IndexMergeGathers builds TiKVIndexMergeGathers for the DNF conditions which can be read
+// by the union of several index or table ranges. The filters which can not be evaluated by each
+// gather are returned too, they need to be evaluated above the gather.
+func (ds *DataSource) Convert2IndexMergeGathers(conds []expression.Expression) (gathers []LogicalPlan, remainedConds [][]expression.Expression, err error) {
+	sessVars := ds.ctx.GetSessionVars()
+	if !(sessVars.GetEnableIndexMerge() || len(ds.indexMergeHints) > 0) || sessVars.StmtCtx.NoIndexMergeHint ||
+		ds.tableInfo.TempTableType == model.TempTableLocal || sessVars.StmtCtx.ReadFromTableCache {
+		return nil, nil, nil
+	}
+	// Only the regular paths are used to build the partial paths, they are
+	// always in front of the IndexMerge paths.
+	usedIndexCount := 0
+	for usedIndexCount < len(ds.possibleAccessPaths) && ds.possibleAccessPaths[usedIndexCount].PartialIndexPaths == nil {
+		usedIndexCount++
+	}
+	if usedIndexCount <= 1 {
+		return nil, nil, nil
+	}
+	ds.initStats(nil)
+	for i, cond := range conds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.LogicOr {
+			continue
+		}
+		partialPaths, err := ds.buildIndexMergePartialPaths(sf, usedIndexCount)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(partialPaths) == 0 || !ds.canBuildIndexMergeGather(partialPaths) {
+			continue
+		}
+		gather, remained := ds.buildIndexMergeGather(ds.buildIndexMergeOrPath(conds, partialPaths, i), conds)
+		gathers = append(gathers, gather)
+		remainedConds = append(remainedConds, remained)
+	}
+	return gathers, remainedConds, nil
+}
+
+// canBuildIndexMergeGather checks whether the partial paths can be converted to partial scans,
+// the partial table scans of the clustered index are not supported now.
+func (ds *DataSource) canBuildIndexMergeGather(partialPaths []*util.AccessPath) bool {
+	for _, path := range partialPaths {
+		if path.IsCommonHandlePath || (path.IsIntHandlePath && ds.handleCols == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ds *DataSource) buildIndexMergeGather(path *util.AccessPath, conds []expression.Expression) (LogicalPlan, []expression.Expression) {
+	handleCols := ds.handleCols
+	if handleCols == nil {
+		handleCols = NewIntHandleCols(ds.newExtraHandleSchemaCol())
+	}
+	children := make([]LogicalPlan, 0, len(path.PartialIndexPaths)+1)
+	for _, partialPath := range path.PartialIndexPaths {
+		if partialPath.IsTablePath() {
+			// The partial table scan only outputs the handle column. Its table filters
+			// are not pushed down, since the whole DNF filter is kept in this case.
+			ts := LogicalTableScan{
+				Source:      ds,
+				HandleCols:  handleCols,
+				AccessConds: partialPath.AccessConds,
+				Ranges:      partialPath.Ranges,
+			}.Init(ds.ctx, ds.blockOffset)
+			ts.SetSchema(expression.NewSchema(handleCols.GetCol(0)))
+			children = append(children, ts)
+			continue
+		}
+		is := LogicalIndexScan{
+			Source:         ds,
+			IsDoubleRead:   true,
+			EqCondCount:    partialPath.EqCondCount,
+			AccessConds:    partialPath.AccessConds,
+			Ranges:         partialPath.Ranges,
+			Index:          partialPath.Index,
+			FullIdxCols:    partialPath.FullIdxCols,
+			FullIdxColLens: partialPath.FullIdxColLens,
+			IdxCols:        partialPath.IdxCols,
+			IdxColLens:     partialPath.IdxColLens,
+		}.Init(ds.ctx, ds.blockOffset)
+		is.Columns = make([]*model.ColumnInfo, len(ds.Columns))
+		copy(is.Columns, ds.Columns)
+		is.SetSchema(ds.Schema())
+		if len(partialPath.IndexFilters) == 0 {
+			children = append(children, is)
+			continue
+		}
+		sel := LogicalSelection{Conditions: partialPath.IndexFilters}.Init(ds.ctx, ds.blockOffset)
+		sel.SetChildren(is)
+		children = append(children, sel)
+	}
+
+	// The HandleCols of the table side is nil, since it is a row id scan.
+	ts := LogicalTableScan{Source: ds}.Init(ds.ctx, ds.blockOffset)
+	ts.SetSchema(ds.Schema())
+	var tableSide LogicalPlan = ts
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	pushed, remained := expression.PushDownExprs(sc, path.TableFilters, ds.ctx.GetClient(), kv.TiKV)
+	pushed, remainedVirtualConds := SplitSelCondsWithVirtualColumn(pushed)
+	remained = append(remained, remainedVirtualConds...)
+	if len(pushed) > 0 {
+		sel := LogicalSelection{Conditions: pushed}.Init(ds.ctx, ds.blockOffset)
+		sel.SetChildren(ts)
+		tableSide = sel
+	}
+	children = append(children, tableSide)
+
+	gather := TiKVIndexMergeGather{
+		Source:       ds,
+		HandleCols:   handleCols,
+		PruningConds: conds,
+	}.Init(ds.ctx, ds.blockOffset)
+	gather.SetSchema(ds.Schema())
+	gather.SetChildren(children...)
+	return gather, remained
+}
+
+// Convert2PointGet converts the LogicalTableScan to a TiKVPointGet if all of its
+// ranges are points of the int handle. It returns nil if it can not be converted.
+func (ts *LogicalTableScan) Convert2PointGet() (*TiKVPointGet, error) {
+	if len(ts.AccessConds) == 0 || ts.HandleCols == nil || !ts.HandleCols.IsInt() {
+		return nil, nil
+	}
+	ranges, err := ranger.BuildTableRange(ts.AccessConds, ts.ctx, ts.HandleCols.GetCol(0).RetType)
+	if err != nil {
+		return nil, err
+	}
+	if !ts.Source.canConvertToTiKVPointGet(ranges, ts.AccessConds) {
+		return nil, nil
+	}
+	pg := TiKVPointGet{
+		Source:      ts.Source,
+		AccessConds: ts.AccessConds,
+		Ranges:      ranges,
+	}.Init(ts.ctx, ts.blockOffset)
+	pg.SetSchema(ts.Source.Schema())
+	return pg, nil
+}
+
+// Convert2PointGet converts the LogicalIndexScan to a TiKVPointGet if all of its
+// ranges are points of the unique index. It returns nil if it can not be converted.
+func (is *LogicalIndexScan) Convert2PointGet() *TiKVPointGet {
+	// We simply do not build [batch] point get for prefix indexes. This can be optimized.
+	if len(is.AccessConds) == 0 || !is.Index.Unique || is.Index.HasPrefixIndex() {
+		return nil
+	}
+	// If any range cannot cover all columns of the index, we cannot build [batch] point get.
+	for _, ran := range is.Ranges {
+		if len(ran.LowVal) != len(is.Index.Columns) {
+			return nil
+		}
+	}
+	if !is.Source.canConvertToTiKVPointGet(is.Ranges, is.AccessConds) {
+		return nil
+	}
+	pg := TiKVPointGet{
+		Source:       is.Source,
+		Index:        is.Index,
+		IdxCols:      is.IdxCols,
+		IdxColLens:   is.IdxColLens,
+		IsDoubleRead: is.IsDoubleRead,
+		AccessConds:  is.AccessConds,
+		Ranges:       is.Ranges,
+	}.Init(is.ctx, is.blockOffset)
+	pg.SetSchema(is.Source.Schema())
+	return pg
+}
+
 func (ds *DataSource) deriveCommonHandleTablePathStats(path *util.AccessPath, conds []expression.Expression, isIm bool) error {
 	path.CountAfterAccess = float64(ds.statisticTable.Count)
 	path.Ranges = ranger.FullNotNullRange()
@@ -743,7 +1069,7 @@ func (ds *DataSource) deriveCommonHandleTablePathStats(path *util.AccessPath, co
 				path.ConstCols[i] = res.ColumnValues[i] != nil
 			}
 		}
-		path.CountAfterAccess, err = ds.tableStats.HistColl.GetRowCountByIndexRanges(ds.ctx, path.Index.ID, path.Ranges)
+		path.CountAfterAccess, err = ds.getRowCountByIndexRanges(path)
 		if err != nil {
 			return err
 		}
@@ -771,7 +1097,7 @@ func (ds *DataSource) deriveCommonHandleTablePathStats(path *util.AccessPath, co
 	}
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
-	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
+	if !isIm && path.CountAfterAccess < ds.stats.RowCount {
 		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
 	}
 	return nil
@@ -849,7 +1175,7 @@ func (ds *DataSource) deriveTablePathStats(path *util.AccessPath, conds []expres
 	path.CountAfterAccess, err = ds.statisticTable.GetRowCountByIntColumnRanges(ds.ctx, pkCol.ID, path.Ranges)
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
-	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
+	if !isIm && path.CountAfterAccess < ds.stats.RowCount {
 		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
 	}
 	return err
@@ -897,7 +1223,7 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 				path.ConstCols[i] = res.ColumnValues[i] != nil
 			}
 		}
-		path.CountAfterAccess, err = ds.tableStats.HistColl.GetRowCountByIndexRanges(ds.ctx, path.Index.ID, path.Ranges)
+		path.CountAfterAccess, err = ds.getRowCountByIndexRanges(path)
 		if err != nil {
 			return err
 		}
@@ -907,6 +1233,19 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 	return nil
 }
 
+// getRowCountByIndexRanges estimates the row count of the ranges of the index path. The hypothetical indexes
+// don't have statistics of their own, so their row count is estimated by the statistics of the index columns.
+func (ds *DataSource) getRowCountByIndexRanges(path *util.AccessPath) (float64, error) {
+	if path.Index.Tp != model.IndexTypeHypo {
+		return ds.tableStats.HistColl.GetRowCountByIndexRanges(ds.ctx, path.Index.ID, path.Ranges)
+	}
+	colIDs := make([]int64, 0, len(path.IdxCols))
+	for _, col := range path.IdxCols {
+		colIDs = append(colIDs, col.UniqueID)
+	}
+	return ds.tableStats.HistColl.GetRowCountByIndexColumnsRanges(ds.ctx, colIDs, path.Ranges)
+}
+
 // deriveIndexPathStats will fulfill the information that the AccessPath need.
 // conds is the conditions used to generate the DetachRangeResult for path.
 // isIm indicates whether this function is called to generate the partial path for IndexMerge.
@@ -935,7 +1274,7 @@ func (ds *DataSource) deriveIndexPathStats(path *util.AccessPath, conds []expres
 	path.IndexFilters = append(path.IndexFilters, indexFilters...)
 	// If the `CountAfterAccess` is less than `stats.RowCount`, there must be some inconsistent stats info.
 	// We prefer the `stats.RowCount` because it could use more stats info to calculate the selectivity.
-	if path.CountAfterAccess < ds.stats.RowCount && !isIm {
+	if !isIm && path.CountAfterAccess < ds.stats.RowCount {
 		path.CountAfterAccess = math.Min(ds.stats.RowCount/SelectionFactor, float64(ds.statisticTable.Count))
 	}
 	if path.IndexFilters != nil {
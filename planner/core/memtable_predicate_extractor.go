@@ -1153,6 +1153,11 @@ type TimeRange struct {
 	EndTime   time.Time
 }
 
+// isEmpty returns whether no time is in the range, the zero times mean unbounded.
+func (r TimeRange) isEmpty() bool {
+	return !r.StartTime.IsZero() && !r.EndTime.IsZero() && r.StartTime.After(r.EndTime)
+}
+
 // Extract implements the MemTablePredicateExtractor Extract interface
 func (e *SlowQueryExtractor) Extract(
 	ctx sessionctx.Context,
@@ -1381,39 +1386,99 @@ type StatementsSummaryExtractor struct {
 	// Digests represents digest applied to, and we should apply all digest if there is no digest specified.
 	// e.g: SELECT * FROM STATEMENTS_SUMMARY WHERE digest='8019af26debae8aa7642c501dbc43212417b3fb14e6aec779f709976b7e521be'
 	Digests set.StringSet
+	// SchemaNames represents schema names applied to, and we should apply all schemas if there is no schema specified.
+	// e.g: SELECT * FROM STATEMENTS_SUMMARY WHERE schema_name='test'
+	SchemaNames set.StringSet
 	// Enable is true means the executor should use digest to locate statement summary.
 	// Enable is false, means the executor should keep the behavior compatible with before.
 	Enable bool
+	// BeginTimeRange and EndTimeRange are the ranges of the SUMMARY_BEGIN_TIME and SUMMARY_END_TIME, which are
+	// used to skip the history summaries. A zero time means the range is unbounded on that side.
+	// e.g: SELECT * FROM STATEMENTS_SUMMARY_HISTORY WHERE summary_begin_time >= '2022-01-01 00:00:00'
+	BeginTimeRange TimeRange
+	EndTimeRange   TimeRange
 }
 
 // Extract implements the MemTablePredicateExtractor Extract interface
 func (e *StatementsSummaryExtractor) Extract(
-	_ sessionctx.Context,
+	ctx sessionctx.Context,
 	schema *expression.Schema,
 	names []*types.FieldName,
 	predicates []expression.Expression,
 ) (remained []expression.Expression) {
 	// Extract the `digest` column
 	remained, skip, digests := e.extractCol(schema, names, predicates, "digest", false)
-	e.SkipRequest = skip
-	if e.SkipRequest {
+	if skip {
+		e.SkipRequest = true
+		return nil
+	}
+	// Extract the `schema_name` column
+	remained, skip, schemaNames := e.extractCol(schema, names, remained, "schema_name", false)
+	if skip {
+		e.SkipRequest = true
 		return nil
 	}
 	if digests.Count() > 0 {
 		e.Enable = true
 		e.Digests = digests
 	}
+	if schemaNames.Count() > 0 {
+		e.Enable = true
+		e.SchemaNames = schemaNames
+	}
+
+	// The summary times are shown in the local time zone of the TiDB server. The time predicates are kept
+	// because the ranges are only used to skip the summaries roughly.
+	var startTime, endTime int64
+	_, startTime, endTime = e.extractTimeRange(ctx, schema, names, remained, "summary_begin_time", time.Local)
+	e.BeginTimeRange = e.convertToTimeRange(startTime, endTime)
+	_, startTime, endTime = e.extractTimeRange(ctx, schema, names, remained, "summary_end_time", time.Local)
+	e.EndTimeRange = e.convertToTimeRange(startTime, endTime)
+	if e.BeginTimeRange.isEmpty() || e.EndTimeRange.isEmpty() {
+		e.SkipRequest = true
+		return nil
+	}
 	return remained
 }
 
+func (e *StatementsSummaryExtractor) convertToTimeRange(start, end int64) TimeRange {
+	var timeRange TimeRange
+	if start != 0 {
+		timeRange.StartTime = e.convertToTime(start)
+	}
+	if end != 0 {
+		timeRange.EndTime = e.convertToTime(end)
+	}
+	return timeRange
+}
+
 func (e *StatementsSummaryExtractor) explainInfo(p *PhysicalMemTable) string {
 	if e.SkipRequest {
 		return "skip_request: true"
 	}
-	if !e.Enable {
-		return ""
+	r := new(bytes.Buffer)
+	if len(e.Digests) > 0 {
+		r.WriteString(fmt.Sprintf("digests: [%s], ", extractStringFromStringSet(e.Digests)))
+	}
+	if len(e.SchemaNames) > 0 {
+		r.WriteString(fmt.Sprintf("schema_names: [%s], ", extractStringFromStringSet(e.SchemaNames)))
+	}
+	writeTimeRange := func(name string, timeRange TimeRange) {
+		if !timeRange.StartTime.IsZero() {
+			r.WriteString(fmt.Sprintf("%s_start: %s, ", name, types.NewTime(types.FromGoTime(timeRange.StartTime), mysql.TypeDatetime, types.MaxFsp)))
+		}
+		if !timeRange.EndTime.IsZero() {
+			r.WriteString(fmt.Sprintf("%s_end: %s, ", name, types.NewTime(types.FromGoTime(timeRange.EndTime), mysql.TypeDatetime, types.MaxFsp)))
+		}
 	}
-	return fmt.Sprintf("digests: [%s]", extractStringFromStringSet(e.Digests))
+	writeTimeRange("summary_begin_time", e.BeginTimeRange)
+	writeTimeRange("summary_end_time", e.EndTimeRange)
+	// remove the last ", " in the message info
+	s := r.String()
+	if len(s) > 2 {
+		return s[:len(s)-2]
+	}
+	return s
 }
 
 // TikvRegionPeersExtractor is used to extract some predicates of cluster table.
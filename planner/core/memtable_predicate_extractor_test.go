@@ -1552,3 +1552,76 @@ func TestTikvRegionPeersExtractor(t *testing.T) {
 		}
 	}
 }
+
+func TestStatementsSummaryExtractor(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	se, err := session.CreateSession4Test(store)
+	require.NoError(t, err)
+
+	localTime := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+		require.NoError(t, err)
+		return tm
+	}
+	var cases = []struct {
+		sql            string
+		digests        set.StringSet
+		schemaNames    set.StringSet
+		beginTimeRange plannercore.TimeRange
+		endTimeRange   plannercore.TimeRange
+		skipRequest    bool
+	}{
+		{
+			sql:     "select * from information_schema.statements_summary_history where digest='abc'",
+			digests: set.NewStringSet("abc"),
+		},
+		{
+			sql:         "select * from information_schema.statements_summary_history where digest in ('abc', 'def') and schema_name='test'",
+			digests:     set.NewStringSet("abc", "def"),
+			schemaNames: set.NewStringSet("test"),
+		},
+		{
+			sql:         "select * from information_schema.statements_summary_history where schema_name='test' and schema_name='test2'",
+			skipRequest: true,
+		},
+		{
+			sql:            "select * from information_schema.statements_summary_history where summary_begin_time>='2022-01-01 10:00:00'",
+			beginTimeRange: plannercore.TimeRange{StartTime: localTime("2022-01-01 10:00:00")},
+		},
+		{
+			sql: `select * from information_schema.statements_summary_history
+					where summary_begin_time>='2022-01-01 10:00:00' and summary_end_time<='2022-01-02 10:00:00'`,
+			beginTimeRange: plannercore.TimeRange{StartTime: localTime("2022-01-01 10:00:00")},
+			endTimeRange:   plannercore.TimeRange{EndTime: localTime("2022-01-02 10:00:00")},
+		},
+		{
+			sql: `select * from information_schema.statements_summary_history
+					where summary_begin_time>='2022-01-02 10:00:00' and summary_begin_time<='2022-01-01 10:00:00'`,
+			skipRequest: true,
+		},
+	}
+	parser := parser.New()
+	for _, ca := range cases {
+		logicalMemTable := getLogicalMemTable(t, dom, se, parser, ca.sql)
+		require.NotNil(t, logicalMemTable.Extractor)
+
+		extractor := logicalMemTable.Extractor.(*plannercore.StatementsSummaryExtractor)
+		require.Equal(t, ca.skipRequest, extractor.SkipRequest, "SQL: %v", ca.sql)
+		if ca.skipRequest {
+			continue
+		}
+		require.Equal(t, len(ca.digests) > 0 || len(ca.schemaNames) > 0, extractor.Enable, "SQL: %v", ca.sql)
+		if len(ca.digests) > 0 {
+			require.EqualValues(t, ca.digests, extractor.Digests, "SQL: %v", ca.sql)
+		}
+		if len(ca.schemaNames) > 0 {
+			require.EqualValues(t, ca.schemaNames, extractor.SchemaNames, "SQL: %v", ca.sql)
+		}
+		require.True(t, ca.beginTimeRange.StartTime.Equal(extractor.BeginTimeRange.StartTime), "SQL: %v", ca.sql)
+		require.True(t, ca.beginTimeRange.EndTime.Equal(extractor.BeginTimeRange.EndTime), "SQL: %v", ca.sql)
+		require.True(t, ca.endTimeRange.StartTime.Equal(extractor.EndTimeRange.StartTime), "SQL: %v", ca.sql)
+		require.True(t, ca.endTimeRange.EndTime.Equal(extractor.EndTimeRange.EndTime), "SQL: %v", ca.sql)
+	}
+}
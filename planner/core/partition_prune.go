@@ -47,6 +47,8 @@ func PartitionPruning(ctx sessionctx.Context, tbl table.PartitionedTable, conds
 		return ret, nil
 	case model.PartitionTypeList:
 		return s.pruneListPartition(ctx, tbl, partitionNames, conds)
+	case model.PartitionTypeKey:
+		return s.findUsedKeyPartitions(ctx, tbl, partitionNames, conds, columns, names)
 	}
 	return []int{FullRange}, nil
 }
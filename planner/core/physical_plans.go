@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
@@ -131,7 +132,7 @@ func (p *PhysicalTableReader) GetTableScan() *PhysicalTableScan {
 func (sg *TiKVSingleGather) GetPhysicalTableReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalTableReader {
 	reader := PhysicalTableReader{}.Init(sg.ctx, sg.blockOffset)
 	reader.PartitionInfo = PartitionInfo{
-		PruningConds:   sg.Source.allConds,
+		PruningConds:   sg.PruningConds,
 		PartitionNames: sg.Source.partitionNames,
 		Columns:        sg.Source.TblCols,
 		ColumnNames:    sg.Source.names,
@@ -145,12 +146,128 @@ func (sg *TiKVSingleGather) GetPhysicalTableReader(schema *expression.Schema, st
 // GetPhysicalIndexReader returns PhysicalIndexReader for logical TiKVSingleGather.
 func (sg *TiKVSingleGather) GetPhysicalIndexReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexReader {
 	reader := PhysicalIndexReader{}.Init(sg.ctx, sg.blockOffset)
+	reader.PartitionInfo = PartitionInfo{
+		PruningConds:   sg.PruningConds,
+		PartitionNames: sg.Source.partitionNames,
+		Columns:        sg.Source.TblCols,
+		ColumnNames:    sg.Source.names,
+	}
+	reader.stats = stats
+	reader.SetSchema(schema)
+	reader.childrenReqProps = props
+	return reader
+}
+
+// GetPhysicalIndexLookUpReader returns PhysicalIndexLookUpReader for logical TiKVDoubleGather.
+func (dg *TiKVDoubleGather) GetPhysicalIndexLookUpReader(stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexLookUpReader {
+	reader := PhysicalIndexLookUpReader{}.Init(dg.ctx, dg.blockOffset)
+	reader.PartitionInfo = PartitionInfo{
+		PruningConds:   dg.PruningConds,
+		PartitionNames: dg.Source.partitionNames,
+		Columns:        dg.Source.TblCols,
+		ColumnNames:    dg.Source.names,
+	}
+	if dg.HandleCols.IsInt() {
+		reader.ExtraHandleCol = dg.HandleCols.GetCol(0)
+	} else {
+		reader.CommonHandleCols = make([]*expression.Column, 0, dg.HandleCols.NumCols())
+		for i := 0; i < dg.HandleCols.NumCols(); i++ {
+			reader.CommonHandleCols = append(reader.CommonHandleCols, dg.HandleCols.GetCol(i))
+		}
+	}
+	reader.stats = stats
+	reader.childrenReqProps = props
+	return reader
+}
+
+// GetPhysicalTableReader returns PhysicalTableReader for logical TiFlashMPPGather.
+func (mg *TiFlashMPPGather) GetPhysicalTableReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalTableReader {
+	reader := PhysicalTableReader{StoreType: kv.TiFlash}.Init(mg.ctx, mg.blockOffset)
 	reader.stats = stats
 	reader.SetSchema(schema)
 	reader.childrenReqProps = props
 	return reader
 }
 
+// GetPhysicalIndexMergeReader returns PhysicalIndexMergeReader for logical TiKVIndexMergeGather.
+func (g *TiKVIndexMergeGather) GetPhysicalIndexMergeReader(stats *property.StatsInfo, props ...*property.PhysicalProperty) (*PhysicalIndexMergeReader, error) {
+	reader := PhysicalIndexMergeReader{}.Init(g.ctx, g.blockOffset)
+	reader.PartitionInfo = PartitionInfo{
+		PruningConds:   g.PruningConds,
+		PartitionNames: g.Source.partitionNames,
+		Columns:        g.Source.TblCols,
+		ColumnNames:    g.Source.names,
+	}
+	handleExprCols := make([]*expression.Column, 0, g.HandleCols.NumCols())
+	for i := 0; i < g.HandleCols.NumCols(); i++ {
+		handleExprCols = append(handleExprCols, g.HandleCols.GetCol(i))
+	}
+	// The handle columns are resolved by the rows read by the partial scans.
+	handleCols, err := g.HandleCols.ResolveIndices(expression.NewSchema(handleExprCols...))
+	if err != nil {
+		return nil, err
+	}
+	reader.tblScanHandleCols = handleCols
+	reader.stats = stats
+	reader.childrenReqProps = props
+	return reader, nil
+}
+
+// GetPhysicalPointGet returns PointGetPlan or BatchPointGetPlan for logical TiKVPointGet.
+func (pg *TiKVPointGet) GetPhysicalPointGet(stats *property.StatsInfo) PhysicalPlan {
+	ds := pg.Source
+	if len(pg.Ranges) == 1 {
+		p := PointGetPlan{
+			ctx:              ds.ctx,
+			AccessConditions: pg.AccessConds,
+			schema:           ds.schema.Clone(),
+			dbName:           ds.DBName.L,
+			TblInfo:          ds.TableInfo(),
+			outputNames:      ds.OutputNames(),
+			LockWaitTime:     ds.ctx.GetSessionVars().LockWaitTimeout,
+			Columns:          ds.Columns,
+		}.Init(ds.ctx, stats, pg.blockOffset)
+		if pg.Index == nil {
+			p.Handle = kv.IntHandle(pg.Ranges[0].LowVal[0].GetInt64())
+			p.UnsignedHandle = mysql.HasUnsignedFlag(ds.handleCols.GetCol(0).RetType.Flag)
+		} else {
+			p.IndexInfo = pg.Index
+			p.IdxCols = pg.IdxCols
+			p.IdxColLens = pg.IdxColLens
+			p.IndexValues = pg.Ranges[0].LowVal
+		}
+		return p
+	}
+	p := BatchPointGetPlan{
+		ctx:              ds.ctx,
+		AccessConditions: pg.AccessConds,
+		TblInfo:          ds.TableInfo(),
+		Columns:          ds.Columns,
+	}.Init(ds.ctx, stats, ds.schema.Clone(), ds.names, pg.blockOffset)
+	if pg.Index == nil {
+		for _, ran := range pg.Ranges {
+			p.Handles = append(p.Handles, kv.IntHandle(ran.LowVal[0].GetInt64()))
+		}
+	} else {
+		p.IndexInfo = pg.Index
+		p.IdxCols = pg.IdxCols
+		p.IdxColLens = pg.IdxColLens
+		for _, ran := range pg.Ranges {
+			p.IndexValues = append(p.IndexValues, ran.LowVal)
+		}
+	}
+	return p
+}
+
+// ReadColumns returns the columns read by the TiKVPointGet, they are used to
+// calculate the cost of the point get.
+func (pg *TiKVPointGet) ReadColumns() []*expression.Column {
+	if pg.Index != nil && !pg.IsDoubleRead {
+		return pg.IdxCols
+	}
+	return pg.Source.TblCols
+}
+
 // Clone implements PhysicalPlan interface.
 func (p *PhysicalTableReader) Clone() (PhysicalPlan, error) {
 	cloned := new(PhysicalTableReader)
@@ -175,6 +292,10 @@ func (p *PhysicalTableReader) Clone() (PhysicalPlan, error) {
 func (p *PhysicalTableReader) SetChildren(children ...PhysicalPlan) {
 	p.tablePlan = children[0]
 	p.TablePlans = flattenPushDownPlan(p.tablePlan)
+	if _, isMPP := p.tablePlan.(*PhysicalExchangeSender); isMPP {
+		p.PartitionInfos = p.PartitionInfos[:0]
+		collectPartitionInfosFromMPPPlan(p, p.tablePlan)
+	}
 }
 
 // ExtractCorrelatedCols implements PhysicalPlan interface.
@@ -311,6 +432,15 @@ func (p *PhysicalIndexLookUpReader) Clone() (PhysicalPlan, error) {
 	return cloned, nil
 }
 
+// SetChildren overrides PhysicalPlan SetChildren interface.
+func (p *PhysicalIndexLookUpReader) SetChildren(children ...PhysicalPlan) {
+	p.indexPlan, p.tablePlan = children[0], children[1]
+	setTableScanToTableRowIDScan(p.tablePlan)
+	p.IndexPlans = flattenPushDownPlan(p.indexPlan)
+	p.TablePlans = flattenPushDownPlan(p.tablePlan)
+	p.schema = p.tablePlan.Schema()
+}
+
 // ExtractCorrelatedCols implements PhysicalPlan interface.
 func (p *PhysicalIndexLookUpReader) ExtractCorrelatedCols() (corCols []*expression.CorrelatedColumn) {
 	for _, child := range p.TablePlans {
@@ -337,6 +467,26 @@ type PhysicalIndexMergeReader struct {
 
 	// Used by partition table.
 	PartitionInfo PartitionInfo
+
+	// tblScanHandleCols is the HandleCols of the table scan, it is set to the
+	// table scan when the children are attached by SetChildren.
+	tblScanHandleCols HandleCols
+}
+
+// SetChildren overrides PhysicalPlan SetChildren interface.
+// The last child is the table plan, and the others are the partial plans.
+func (p *PhysicalIndexMergeReader) SetChildren(children ...PhysicalPlan) {
+	p.partialPlans, p.tablePlan = children[:len(children)-1], children[len(children)-1]
+	setTableScanToTableRowIDScan(p.tablePlan)
+	p.PartialPlans = make([][]PhysicalPlan, 0, len(p.partialPlans))
+	for _, partialPlan := range p.partialPlans {
+		p.PartialPlans = append(p.PartialPlans, flattenPushDownPlan(partialPlan))
+	}
+	p.TablePlans = flattenPushDownPlan(p.tablePlan)
+	if ts, ok := p.TablePlans[0].(*PhysicalTableScan); ok && p.tblScanHandleCols != nil {
+		ts.HandleCols = p.tblScanHandleCols
+	}
+	p.schema = p.tablePlan.Schema()
 }
 
 // ExtractCorrelatedCols implements PhysicalPlan interface.
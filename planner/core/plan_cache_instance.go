@@ -0,0 +1,645 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+	atomic2 "go.uber.org/atomic"
+)
+
+// instancePlanCacheSysVars are the system variables which affect the plans, besides the ones already in
+// instancePlanCacheKey. The plans can't be shared by the sessions with different values of them.
+var instancePlanCacheSysVars = []string{
+	variable.TiDBOptAggPushDown,
+	variable.TiDBOptDistinctAggPushDown,
+	variable.TiDBOptPreferRangeScan,
+	variable.TiDBOptLimitPushDownThreshold,
+	variable.TiDBOptEnableCorrelationAdjustment,
+	variable.TiDBOptCorrelationThreshold,
+	variable.TiDBOptCorrelationExpFactor,
+	variable.TiDBOptCPUFactor,
+	variable.TiDBOptCopCPUFactor,
+	variable.TiDBOptTiFlashConcurrencyFactor,
+	variable.TiDBOptNetworkFactor,
+	variable.TiDBOptScanFactor,
+	variable.TiDBOptDescScanFactor,
+	variable.TiDBOptSeekFactor,
+	variable.TiDBOptMemoryFactor,
+	variable.TiDBOptDiskFactor,
+	variable.TiDBOptConcurrencyFactor,
+	variable.TiDBOptJoinReorderThreshold,
+	variable.TiDBOptInSubqToJoinAndAgg,
+	variable.TiDBOptBCJ,
+	variable.TiDBOptimizerSelectivityLevel,
+	variable.TiDBEnableIndexMerge,
+	variable.TiDBEnableIndexMergeJoin,
+	variable.TiDBEnableCascadesPlanner,
+	variable.TiDBEnableOrderedResultMode,
+	variable.TiDBEnablePaging,
+	variable.TiDBAllowBatchCop,
+	variable.TiDBAllowMPPExecution,
+	variable.TiDBEnforceMPPExecution,
+	variable.TiDBPartitionPruneMode,
+}
+
+// instancePlanCacheKey is used to access the instance plan cache, which is shared by all the sessions of the
+// TiDB instance. Unlike planCacheKey, it doesn't depend on the connection and the prepared statement, so the
+// sessions preparing the same statement share the cached plan. The statement is identified by its restored
+// text, so the texts differing only in the spaces or the comments share the plan, while the ones with different
+// hints, literals or output names don't. The schema version and the statistics which the plan depends on are
+// checked by the cached value.
+type instancePlanCacheKey struct {
+	database             string
+	stmtText             string
+	sqlMode              mysql.SQLMode
+	timezoneOffset       int
+	isolationReadEngines map[kv.StoreType]struct{}
+	selectLimit          uint64
+	bindSQL              string
+	paramTypes           []*types.FieldType
+	sysVars              []string
+
+	hash []byte
+}
+
+// Hash implements Key interface.
+func (key *instancePlanCacheKey) Hash() []byte {
+	if len(key.hash) == 0 {
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.database))
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.stmtText))
+		key.hash = codec.EncodeInt(key.hash, int64(key.sqlMode))
+		key.hash = codec.EncodeInt(key.hash, int64(key.timezoneOffset))
+		for _, storeType := range []kv.StoreType{kv.TiDB, kv.TiKV, kv.TiFlash} {
+			if _, ok := key.isolationReadEngines[storeType]; ok {
+				key.hash = append(key.hash, storeType.Name()...)
+			}
+		}
+		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.bindSQL))
+		key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(encodeParamTypes(key.paramTypes)))
+		for _, val := range key.sysVars {
+			key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(val))
+		}
+	}
+	return key.hash
+}
+
+// encodeParamTypes encodes the parameter types to a string. The types which are considered the same by
+// FieldSlice.Equal are encoded to the same string, except for the NULL type.
+func encodeParamTypes(tps []*types.FieldType) string {
+	var sb strings.Builder
+	for i, tp := range tps {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		t := tp.Tp
+		if t == mysql.TypeVarString {
+			t = mysql.TypeVarchar
+		}
+		sb.WriteString(types.TypeToStr(t, tp.Charset))
+		if tp.EvalType() == types.ETInt && mysql.HasUnsignedFlag(tp.Flag) {
+			sb.WriteString(" unsigned")
+		}
+		if tp.EvalType() == types.ETString && tp.Collate != "" {
+			sb.WriteString(" collate ")
+			sb.WriteString(tp.Collate)
+		}
+	}
+	return sb.String()
+}
+
+// newInstancePlanCacheKey creates a new instancePlanCacheKey object.
+func newInstancePlanCacheKey(sessionVars *variable.SessionVars, preparedStmt *CachedPrepareStmt, bindSQL string, paramTypes []*types.FieldType) *instancePlanCacheKey {
+	timezoneOffset := 0
+	if sessionVars.TimeZone != nil {
+		_, timezoneOffset = time.Now().In(sessionVars.TimeZone).Zone()
+	}
+	key := &instancePlanCacheKey{
+		database:             sessionVars.CurrentDB,
+		stmtText:             restoreStmtText(preparedStmt.PreparedAst.Stmt),
+		sqlMode:              sessionVars.SQLMode,
+		timezoneOffset:       timezoneOffset,
+		isolationReadEngines: make(map[kv.StoreType]struct{}),
+		selectLimit:          sessionVars.SelectLimit,
+		bindSQL:              bindSQL,
+		paramTypes:           paramTypes,
+		sysVars:              make([]string, 0, len(instancePlanCacheSysVars)),
+	}
+	for k, v := range sessionVars.IsolationReadEngines {
+		key.isolationReadEngines[k] = v
+	}
+	for _, name := range instancePlanCacheSysVars {
+		val, _ := sessionVars.GetSystemVar(name)
+		key.sysVars = append(key.sysVars, val)
+	}
+	return key
+}
+
+// restoreStmtText returns the text restored from the statement. Unlike the SQL digest, it keeps the hints, the
+// literals which aren't parameters and the letter case of the names, because they change the plan or the names
+// of the result columns.
+func restoreStmtText(stmt ast.StmtNode) string {
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		// The statement can't be restored, fall back to its original text.
+		return stmt.Text()
+	}
+	return sb.String()
+}
+
+// isInstancePlanCacheable checks whether the plan of the prepared statement can be shared by the sessions.
+// The plans of the locking reads depend on the transaction mode of the session, so they aren't shared.
+func isInstancePlanCacheable(stmt ast.StmtNode) bool {
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok {
+		return false
+	}
+	return sel.LockInfo == nil || sel.LockInfo.LockType == ast.SelectLockNone
+}
+
+// instancePlanCacheValue stores a plan in the instance plan cache. The plan isn't bound to any session, it's
+// cloned for the session which hits the cache.
+type instancePlanCacheValue struct {
+	plan              Plan
+	names             types.NameSlice
+	tblInfo2UnionScan map[*model.TableInfo]bool
+	schemaVersion     int64
+	// tables and statsVersions are the statistics versions of the tables when the plan is built.
+	tables         []*model.TableInfo
+	statsVersions  []uint64
+	normalizedPlan string
+	planDigest     *parser.Digest
+
+	sqlDigest   string
+	sqlText     string
+	schemaName  string
+	paramTypes  string
+	memUsage    int64
+	createTime  time.Time
+	missCount   int64
+	hitCount    atomic2.Int64
+	lastHitTime atomic2.Int64
+}
+
+// isStale checks whether the plan is built on an old schema or old statistics.
+func (v *instancePlanCacheValue) isStale(sctx sessionctx.Context, schemaVersion int64) bool {
+	if v.schemaVersion != schemaVersion {
+		return true
+	}
+	statsVersions := getStatsVersions(sctx, v.tables)
+	for i, version := range statsVersions {
+		if version != v.statsVersions[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *instancePlanCacheValue) hit() {
+	v.hitCount.Inc()
+	v.lastHitTime.Store(time.Now().UnixNano())
+}
+
+func getStatsVersions(sctx sessionctx.Context, tables []*model.TableInfo) []uint64 {
+	versions := make([]uint64, len(tables))
+	statsHandle := domain.GetDomain(sctx).StatsHandle()
+	if statsHandle == nil {
+		return versions
+	}
+	for i, tbl := range tables {
+		versions[i] = statsHandle.GetTableStats(tbl).Version
+	}
+	return versions
+}
+
+// newInstancePlanCacheValue creates an instancePlanCacheValue from the plan built by the session. It returns
+// false if the plan can't be shared by the sessions.
+func newInstancePlanCacheValue(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt, p Plan, names types.NameSlice,
+	tblInfo2UnionScan map[*model.TableInfo]bool, paramTypes []*types.FieldType) (*instancePlanCacheValue, bool) {
+	// The plan in the cache is bound to no session, so it doesn't keep the session alive.
+	cloner := &planSessionCloner{}
+	plan, ok := cloner.clone(p)
+	if !ok {
+		return nil, false
+	}
+	value := &instancePlanCacheValue{
+		plan:              plan,
+		names:             names,
+		tblInfo2UnionScan: make(map[*model.TableInfo]bool, len(tblInfo2UnionScan)),
+		schemaVersion:     preparedStmt.PreparedAst.SchemaVersion,
+		tables:            cloner.tables,
+		statsVersions:     getStatsVersions(sctx, cloner.tables),
+		normalizedPlan:    preparedStmt.NormalizedPlan,
+		planDigest:        preparedStmt.PlanDigest,
+		sqlText:           preparedStmt.PreparedAst.Stmt.Text(),
+		schemaName:        sctx.GetSessionVars().CurrentDB,
+		paramTypes:        encodeParamTypes(paramTypes),
+		createTime:        time.Now(),
+		missCount:         1,
+	}
+	for k, v := range tblInfo2UnionScan {
+		value.tblInfo2UnionScan[k] = v
+	}
+	if preparedStmt.SQLDigest != nil {
+		value.sqlDigest = preparedStmt.SQLDigest.String()
+	}
+	value.memUsage = int64(unsafe.Sizeof(*value)) + cloner.memUsage + int64(len(value.sqlText)+len(value.normalizedPlan)+
+		len(value.schemaName)+len(value.paramTypes)+len(value.sqlDigest)) +
+		int64(len(names))*int64(unsafe.Sizeof(types.FieldName{})) + int64(len(value.tables))*16
+	return value, true
+}
+
+// InstancePlanCacheEntry is the information of a plan in the instance plan cache.
+type InstancePlanCacheEntry struct {
+	SQLDigest     string
+	SQLText       string
+	SchemaName    string
+	SchemaVersion int64
+	ParamTypes    string
+	PlanDigest    string
+	MemBytes      int64
+	HitCount      int64
+	MissCount     int64
+	CreateTime    time.Time
+	LastHitTime   time.Time
+}
+
+// GetInstancePlanCacheEntries returns the information of the plans in the instance plan cache.
+func GetInstancePlanCacheEntries(sctx sessionctx.Context) []*InstancePlanCacheEntry {
+	values := domain.GetDomain(sctx).InstancePlanCache().Values()
+	entries := make([]*InstancePlanCacheEntry, 0, len(values))
+	for _, v := range values {
+		value := v.(*instancePlanCacheValue)
+		entry := &InstancePlanCacheEntry{
+			SQLDigest:     value.sqlDigest,
+			SQLText:       value.sqlText,
+			SchemaName:    value.schemaName,
+			SchemaVersion: value.schemaVersion,
+			ParamTypes:    value.paramTypes,
+			MemBytes:      value.memUsage,
+			HitCount:      value.hitCount.Load(),
+			MissCount:     value.missCount,
+			CreateTime:    value.createTime,
+		}
+		if value.planDigest != nil {
+			entry.PlanDigest = value.planDigest.String()
+		}
+		if lastHitTime := value.lastHitTime.Load(); lastHitTime > 0 {
+			entry.LastHitTime = time.Unix(0, lastHitTime)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getInstanceCachedPlan tries to get the plan from the instance plan cache and clones it for the session.
+func (e *Execute) getInstanceCachedPlan(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt, key kvcache.Key) bool {
+	cache := domain.GetDomain(sctx).InstancePlanCache()
+	v, ok := cache.Get(key)
+	if !ok {
+		return false
+	}
+	value := v.(*instancePlanCacheValue)
+	if value.isStale(sctx, preparedStmt.PreparedAst.SchemaVersion) {
+		return false
+	}
+	for tblInfo, unionScan := range value.tblInfo2UnionScan {
+		if !unionScan && tableHasDirtyContent(sctx, tblInfo) {
+			return false
+		}
+	}
+	cloner := &planSessionCloner{sctx: sctx, params: preparedStmt.PreparedAst.Params}
+	plan, ok := cloner.clone(value.plan)
+	if !ok {
+		return false
+	}
+	if err := e.rebuildRange(plan); err != nil {
+		return false
+	}
+	value.hit()
+	e.names = value.names
+	e.Plan = plan
+	sctx.GetSessionVars().StmtCtx.SetPlanDigest(value.normalizedPlan, value.planDigest)
+	return true
+}
+
+// putInstanceCachedPlan puts the plan built by the session into the instance plan cache. It returns false if
+// the plan can't be shared by the sessions or it exceeds the memory quota of the cache.
+func putInstanceCachedPlan(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt, key kvcache.Key, p Plan,
+	names types.NameSlice, tblInfo2UnionScan map[*model.TableInfo]bool, paramTypes []*types.FieldType) bool {
+	value, ok := newInstancePlanCacheValue(sctx, preparedStmt, p, names, tblInfo2UnionScan, paramTypes)
+	if !ok {
+		return false
+	}
+	cache := domain.GetDomain(sctx).InstancePlanCache()
+	if v, exists := cache.Get(key); exists {
+		oldValue := v.(*instancePlanCacheValue)
+		// Don't replace the plan built on a newer schema by the session which still uses an old schema.
+		if oldValue.schemaVersion > value.schemaVersion {
+			return true
+		}
+		value.missCount += oldValue.missCount
+		value.hitCount.Store(oldValue.hitCount.Load())
+		value.lastHitTime.Store(oldValue.lastHitTime.Load())
+	}
+	cache.SetQuota(variable.InstancePlanCacheMaxMemSize.Load())
+	return cache.Put(key, value, value.memUsage)
+}
+
+// planSessionCloner clones the physical plans for a session. The cloned plans share the immutable parts, such as
+// the schemas and the table infos, with the original plans, while the expressions and the parameters, which are
+// bound to the session, are cloned and bound to the new session.
+type planSessionCloner struct {
+	sctx sessionctx.Context
+	// params are the parameter markers of the prepared statement in the new session.
+	// The parameter markers in the plans are kept if it's nil.
+	params []ast.ParamMarkerExpr
+
+	// tables are the tables read by the plan.
+	tables []*model.TableInfo
+	// memUsage is the estimated memory usage of the plan.
+	memUsage int64
+}
+
+func (c *planSessionCloner) clone(p Plan) (Plan, bool) {
+	switch x := p.(type) {
+	case *PointGetPlan:
+		if x.Lock || x.PartitionInfo != nil || x.TblInfo.GetPartitionInfo() != nil {
+			return nil, false
+		}
+		cloned := *x
+		cloned.basePlan.ctx = c.sctx
+		cloned.ctx = c.sctx
+		cloned.HandleParam = c.cloneParam(x.HandleParam)
+		cloned.IndexValues = append([]types.Datum(nil), x.IndexValues...)
+		cloned.IndexValueParams = c.cloneParams(x.IndexValueParams)
+		cloned.AccessConditions = c.cloneExprs(x.AccessConditions)
+		c.tables = append(c.tables, x.TblInfo)
+		c.memUsage += int64(unsafe.Sizeof(cloned)) + int64(len(x.IndexValues))*int64(unsafe.Sizeof(types.Datum{}))
+		return &cloned, true
+	case *BatchPointGetPlan:
+		if x.Lock || x.PartitionExpr != nil || x.TblInfo.GetPartitionInfo() != nil {
+			return nil, false
+		}
+		cloned := *x
+		cloned.basePlan.ctx = c.sctx
+		cloned.ctx = c.sctx
+		cloned.Handles = append([]kv.Handle(nil), x.Handles...)
+		cloned.HandleParams = c.cloneParams(x.HandleParams)
+		if x.IndexValues != nil {
+			cloned.IndexValues = make([][]types.Datum, len(x.IndexValues))
+			for i, values := range x.IndexValues {
+				cloned.IndexValues[i] = append([]types.Datum(nil), values...)
+				c.memUsage += int64(len(values)) * int64(unsafe.Sizeof(types.Datum{}))
+			}
+		}
+		if x.IndexValueParams != nil {
+			cloned.IndexValueParams = make([][]*driver.ParamMarkerExpr, len(x.IndexValueParams))
+			for i, params := range x.IndexValueParams {
+				cloned.IndexValueParams[i] = c.cloneParams(params)
+			}
+		}
+		cloned.AccessConditions = c.cloneExprs(x.AccessConditions)
+		c.tables = append(c.tables, x.TblInfo)
+		c.memUsage += int64(unsafe.Sizeof(cloned)) + int64(len(x.Handles))*16
+		return &cloned, true
+	case PhysicalPlan:
+		return c.clonePhysicalPlan(x)
+	}
+	return nil, false
+}
+
+func (c *planSessionCloner) clonePhysicalPlan(p PhysicalPlan) (PhysicalPlan, bool) {
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		if x.StoreType != kv.TiKV || len(x.PartitionInfos) > 0 {
+			return nil, false
+		}
+		cloned := *x
+		tablePlan, ok := c.clonePhysicalPlan(x.tablePlan)
+		if !ok || !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.tablePlan = tablePlan
+		cloned.TablePlans = flattenPushDownPlan(tablePlan)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalIndexReader:
+		cloned := *x
+		indexPlan, ok := c.clonePhysicalPlan(x.indexPlan)
+		if !ok || !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.indexPlan = indexPlan
+		cloned.IndexPlans = flattenPushDownPlan(indexPlan)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalIndexLookUpReader:
+		cloned := *x
+		indexPlan, ok := c.clonePhysicalPlan(x.indexPlan)
+		if !ok {
+			return nil, false
+		}
+		tablePlan, ok := c.clonePhysicalPlan(x.tablePlan)
+		if !ok || !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.indexPlan = indexPlan
+		cloned.IndexPlans = flattenPushDownPlan(indexPlan)
+		cloned.tablePlan = tablePlan
+		cloned.TablePlans = flattenPushDownPlan(tablePlan)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalTableScan:
+		if x.StoreType != kv.TiKV || x.isPartition || x.Table.GetPartitionInfo() != nil || x.SampleInfo != nil {
+			return nil, false
+		}
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.AccessCondition = c.cloneExprs(x.AccessCondition)
+		cloned.filterCondition = c.cloneExprs(x.filterCondition)
+		if handleCols, ok := x.HandleCols.(*CommonHandleCols); ok {
+			clonedHandleCols := *handleCols
+			if c.sctx != nil {
+				clonedHandleCols.sc = c.sctx.GetSessionVars().StmtCtx
+			} else {
+				clonedHandleCols.sc = nil
+			}
+			cloned.HandleCols = &clonedHandleCols
+		}
+		c.tables = append(c.tables, x.Table)
+		c.memUsage += int64(unsafe.Sizeof(cloned)) + int64(len(x.Ranges))*int64(unsafe.Sizeof(types.Datum{}))*2
+		return &cloned, true
+	case *PhysicalIndexScan:
+		if x.isPartition || x.Table.GetPartitionInfo() != nil {
+			return nil, false
+		}
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.AccessCondition = c.cloneExprs(x.AccessCondition)
+		if x.GenExprs != nil {
+			cloned.GenExprs = make(map[model.TableColumnID]expression.Expression, len(x.GenExprs))
+			for id, expr := range x.GenExprs {
+				cloned.GenExprs[id] = c.cloneExpr(expr)
+			}
+		}
+		c.tables = append(c.tables, x.Table)
+		c.memUsage += int64(unsafe.Sizeof(cloned)) + int64(len(x.Ranges))*int64(unsafe.Sizeof(types.Datum{}))*2
+		return &cloned, true
+	case *PhysicalSelection:
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.Conditions = c.cloneExprs(x.Conditions)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalProjection:
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.Exprs = c.cloneExprs(x.Exprs)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalLimit:
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalTopN:
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.ByItems = c.cloneByItems(x.ByItems)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	case *PhysicalSort:
+		cloned := *x
+		if !c.cloneBase(&cloned.basePhysicalPlan, &cloned) {
+			return nil, false
+		}
+		cloned.ByItems = c.cloneByItems(x.ByItems)
+		c.memUsage += int64(unsafe.Sizeof(cloned))
+		return &cloned, true
+	}
+	return nil, false
+}
+
+// cloneBase binds the base of the cloned plan to the session and clones the children.
+func (c *planSessionCloner) cloneBase(base *basePhysicalPlan, self PhysicalPlan) bool {
+	base.ctx = c.sctx
+	base.self = self
+	if len(base.children) == 0 {
+		return true
+	}
+	children := make([]PhysicalPlan, 0, len(base.children))
+	for _, child := range base.children {
+		cloned, ok := c.clonePhysicalPlan(child)
+		if !ok {
+			return false
+		}
+		children = append(children, cloned)
+	}
+	base.children = children
+	return true
+}
+
+func (c *planSessionCloner) cloneExpr(expr expression.Expression) expression.Expression {
+	c.memUsage += exprMemUsage(expr)
+	return expression.CloneWithNewCtx(c.sctx, expr)
+}
+
+func (c *planSessionCloner) cloneExprs(exprs []expression.Expression) []expression.Expression {
+	if exprs == nil {
+		return nil
+	}
+	cloned := make([]expression.Expression, 0, len(exprs))
+	for _, expr := range exprs {
+		cloned = append(cloned, c.cloneExpr(expr))
+	}
+	return cloned
+}
+
+func (c *planSessionCloner) cloneByItems(items []*util.ByItems) []*util.ByItems {
+	cloned := make([]*util.ByItems, 0, len(items))
+	for _, item := range items {
+		cloned = append(cloned, &util.ByItems{Expr: c.cloneExpr(item.Expr), Desc: item.Desc})
+	}
+	return cloned
+}
+
+// cloneParam returns the parameter marker of the new session which has the same order.
+func (c *planSessionCloner) cloneParam(param *driver.ParamMarkerExpr) *driver.ParamMarkerExpr {
+	if param == nil || c.params == nil {
+		return param
+	}
+	return c.params[param.Order].(*driver.ParamMarkerExpr)
+}
+
+func (c *planSessionCloner) cloneParams(params []*driver.ParamMarkerExpr) []*driver.ParamMarkerExpr {
+	if params == nil {
+		return nil
+	}
+	cloned := make([]*driver.ParamMarkerExpr, 0, len(params))
+	for _, param := range params {
+		cloned = append(cloned, c.cloneParam(param))
+	}
+	return cloned
+}
+
+// exprMemUsage estimates the memory usage of the expression.
+func exprMemUsage(expr expression.Expression) int64 {
+	switch x := expr.(type) {
+	case *expression.ScalarFunction:
+		// The built-in function signatures are larger than the scalar function itself.
+		usage := int64(unsafe.Sizeof(*x)) * 4
+		for _, arg := range x.GetArgs() {
+			usage += exprMemUsage(arg)
+		}
+		return usage
+	case *expression.Constant:
+		return int64(unsafe.Sizeof(*x))
+	case *expression.Column:
+		return int64(unsafe.Sizeof(*x))
+	}
+	return 64
+}
@@ -0,0 +1,253 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/hint"
+)
+
+// NonPreparedPlanCacheable checks whether the plan of the text query can be cached by the non-prepared plan cache.
+// Only the simple select statements which read a single table are supported currently, and they must pass the
+// same checks as the prepared statements.
+func NonPreparedPlanCacheable(sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) bool {
+	sel, ok := node.(*ast.SelectStmt)
+	if !ok || sel.Kind != ast.SelectStmtKindSelect || sel.With != nil || sel.SelectIntoOpt != nil ||
+		len(sel.WindowSpecs) > 0 || sel.From == nil || sel.From.TableRefs == nil {
+		return false
+	}
+	if sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone {
+		return false
+	}
+	join := sel.From.TableRefs
+	if join.Right != nil {
+		return false
+	}
+	tblSrc, ok := join.Left.(*ast.TableSource)
+	if !ok {
+		return false
+	}
+	tblName, ok := tblSrc.Source.(*ast.TableName)
+	if !ok || tblName.AsOf != nil || tblName.TableSample != nil || util.IsMemOrSysDB(tblName.Schema.L) {
+		return false
+	}
+	return CacheableWithCtx(sctx, node, is)
+}
+
+// paramReplacer replaces the literals of the query with parameter markers.
+type paramReplacer struct {
+	params  []*driver.ValueExpr
+	markers []ast.ParamMarkerExpr
+	// hasParamMarker indicates whether the query already has parameter markers, which can't be parameterized.
+	hasParamMarker bool
+}
+
+// Enter implements Visitor interface.
+func (pr *paramReplacer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch n := in.(type) {
+	case *ast.SelectField, *ast.GroupByClause, *ast.OrderByClause, *ast.Limit, *ast.TableOptimizerHint:
+		// The literals in these clauses are kept:
+		// 1. SelectField: the literals are the output names of the fields.
+		// 2. GroupByClause, OrderByClause: the literals may be the positions of the fields.
+		// 3. Limit: the plans depend on the values of the limit.
+		return in, true
+	case *ast.FuncCallExpr:
+		switch n.FnName.L {
+		case ast.DateFormat, ast.StrToDate, ast.TimeFormat, ast.FromUnixTime:
+			// The format arguments are kept, only the first arguments are parameterized.
+			if len(n.Args) > 0 {
+				ret, _ := n.Args[0].Accept(pr)
+				n.Args[0] = ret.(ast.ExprNode)
+			}
+			return in, true
+		}
+	case *driver.ParamMarkerExpr:
+		pr.hasParamMarker = true
+		return in, true
+	case *driver.ValueExpr:
+		if !parameterizable(n) {
+			return in, true
+		}
+		param := ast.NewParamMarkerExpr(0)
+		param.SetOrder(len(pr.params))
+		pr.params = append(pr.params, n)
+		pr.markers = append(pr.markers, param)
+		return param, true
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (pr *paramReplacer) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+// parameterizable checks whether the literal can be replaced with a parameter marker without changing the meaning
+// of the query. The type of a parameter is decided by its value, so the literals whose types can't be inferred from
+// the values, such as the boolean literals and the strings with non-default collations, are kept.
+func parameterizable(v *driver.ValueExpr) bool {
+	switch v.Kind() {
+	case types.KindInt64:
+		return !mysql.HasIsBooleanFlag(v.Type.Flag)
+	case types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+		return true
+	case types.KindString:
+		return v.Type.Charset == mysql.DefaultCharset && v.Type.Collate == mysql.DefaultCollationName
+	}
+	return false
+}
+
+// paramRestorer puts the literals replaced by paramReplacer back.
+type paramRestorer struct {
+	*paramReplacer
+}
+
+// Enter implements Visitor interface.
+func (pr *paramRestorer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	if n, ok := in.(*driver.ParamMarkerExpr); ok && n.Order < len(pr.markers) && pr.markers[n.Order] == n {
+		return pr.params[n.Order], true
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (pr *paramRestorer) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+// ParameterizeAST replaces the literals of the statement with parameter markers, and returns the SQL of the
+// parameterized statement and the values of the parameters. The statement is left unchanged.
+func ParameterizeAST(stmt ast.StmtNode) (paramSQL string, params []types.Datum, err error) {
+	replacer := &paramReplacer{}
+	stmt.Accept(replacer)
+	defer stmt.Accept(&paramRestorer{replacer})
+	if replacer.hasParamMarker {
+		return "", nil, errors.New("the statement already has parameter markers")
+	}
+	var sb strings.Builder
+	if err = stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", nil, err
+	}
+	params = make([]types.Datum, 0, len(replacer.params))
+	for _, param := range replacer.params {
+		params = append(params, param.Datum)
+	}
+	return sb.String(), params, nil
+}
+
+// paramMarkerCollector collects the parameter markers of the statement.
+type paramMarkerCollector struct {
+	markers []ast.ParamMarkerExpr
+}
+
+// Enter implements Visitor interface.
+func (pc *paramMarkerCollector) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	if n, ok := in.(*driver.ParamMarkerExpr); ok {
+		pc.markers = append(pc.markers, n)
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (pc *paramMarkerCollector) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+// NewNonPreparedPlanCacheStmt parses the parameterized SQL generated by ParameterizeAST, and prepares it as the
+// statement of the non-prepared plan cache.
+func NewNonPreparedPlanCacheStmt(ctx context.Context, sctx sessionctx.Context, paramSQL string, paramCount int,
+	is infoschema.InfoSchema) (*CachedPrepareStmt, error) {
+	vars := sctx.GetSessionVars()
+	p := parser.New()
+	p.SetParserConfig(vars.BuildParserConfig())
+	p.SetSQLMode(vars.SQLMode)
+	charset, collation := vars.GetCharsetInfo()
+	stmt, err := p.ParseOneStmt(paramSQL, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	var collector paramMarkerCollector
+	stmt.Accept(&collector)
+	if len(collector.markers) != paramCount {
+		return nil, errors.Errorf("the parameterized SQL has %d parameters, but %d are expected", len(collector.markers), paramCount)
+	}
+	if err = Preprocess(sctx, stmt, InPrepare, WithPreprocessorReturn(&PreprocessorReturn{InfoSchema: is})); err != nil {
+		return nil, err
+	}
+	if !PreparedPlanCacheEnabled() || !CacheableWithCtx(sctx, stmt, is) {
+		return nil, errors.New("the parameterized statement is not cacheable")
+	}
+
+	// The parameter markers are sorted by their positions in the SQL, the same as the prepared statements.
+	markers := collector.markers
+	sort.Slice(markers, func(i, j int) bool {
+		return markers[i].(*driver.ParamMarkerExpr).Offset < markers[j].(*driver.ParamMarkerExpr).Offset
+	})
+	for i, marker := range markers {
+		marker.SetOrder(i)
+		param := marker.(*driver.ParamMarkerExpr)
+		param.Datum.SetNull()
+		param.InExecute = false
+	}
+	// Build the statement to collect the visit infos for the privilege check, the warnings of the building are
+	// discarded since it's not the statement executed by the users.
+	sc := vars.StmtCtx
+	warnings := sc.GetWarnings()
+	builder, _ := NewPlanBuilder().Init(sctx, is, &hint.BlockHintProcessor{})
+	_, err = builder.Build(ctx, stmt)
+	sc.SetWarnings(warnings)
+	if err != nil {
+		return nil, err
+	}
+	normalizedSQL, digest := parser.NormalizeDigest(paramSQL)
+	return &CachedPrepareStmt{
+		PreparedAst: &ast.Prepared{
+			Stmt:          stmt,
+			StmtType:      "Select",
+			Params:        markers,
+			SchemaVersion: is.SchemaMetaVersion(),
+			UseCache:      true,
+		},
+		VisitInfos:    builder.GetVisitInfo(),
+		NormalizedSQL: normalizedSQL,
+		SQLDigest:     digest,
+		ForUpdateRead: builder.GetIsForUpdateRead(),
+		NonPrepared:   true,
+	}, nil
+}
+
+// GetPlanFromNonPreparedPlanCache gets the plan of the statement generated by the non-prepared plan cache with the
+// parameters, the plan is built and cached if it's not found in the plan cache.
+func GetPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema,
+	preparedStmt *CachedPrepareStmt, params []types.Datum) (Plan, types.NameSlice, error) {
+	e := &Execute{PrepareParams: params}
+	if err := e.optimizePreparedStmt(ctx, sctx, is, preparedStmt); err != nil {
+		return nil, nil, err
+	}
+	return e.Plan, e.names, nil
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain"
@@ -60,6 +61,7 @@ import (
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/set"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 
@@ -703,6 +705,8 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		return b.buildLoadData(ctx, x)
 	case *ast.LoadStatsStmt:
 		return b.buildLoadStats(x), nil
+	case *ast.RestoreStatsStmt:
+		return b.buildRestoreStats(x)
 	case *ast.IndexAdviseStmt:
 		return b.buildIndexAdvise(x), nil
 	case *ast.PlanReplayerStmt:
@@ -729,15 +733,15 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
 	case *ast.BinlogStmt, *ast.FlushStmt, *ast.UseStmt, *ast.BRIEStmt,
-		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
+		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
-		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
+		*ast.LockStatsStmt, *ast.UnlockStatsStmt, *ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
 		*ast.RenameUserStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
 	case *ast.CreateBindingStmt:
-		return b.buildCreateBindPlan(x)
+		return b.buildCreateBindPlan(ctx, x)
 	case *ast.DropBindingStmt:
 		return b.buildDropBindPlan(x)
 	case *ast.ChangeStmt:
@@ -881,7 +885,10 @@ func checkHintedSQL(sql, charset, collation, db string) error {
 	return nil
 }
 
-func (b *PlanBuilder) buildCreateBindPlan(v *ast.CreateBindingStmt) (Plan, error) {
+func (b *PlanBuilder) buildCreateBindPlan(ctx context.Context, v *ast.CreateBindingStmt) (Plan, error) {
+	if v.OriginNode == nil {
+		return b.buildCreateBindPlanFromPlanDigest(ctx, v)
+	}
 	charSet, collation := b.ctx.GetSessionVars().GetCharsetInfo()
 
 	// Because we use HintedNode.Restore instead of HintedNode.Text, so we need do some check here
@@ -907,6 +914,72 @@ func (b *PlanBuilder) buildCreateBindPlan(v *ast.CreateBindingStmt) (Plan, error
 	return p, nil
 }
 
+// buildCreateBindPlanFromPlanDigest builds the plan to create the binding for the statement whose plan digest
+// is specified. The statement and the hints of its plan are looked up in the statement summary, including the
+// history intervals and the persistent files. The slow log isn't searched because it doesn't record the plan hints.
+func (b *PlanBuilder) buildCreateBindPlanFromPlanDigest(ctx context.Context, v *ast.CreateBindingStmt) (Plan, error) {
+	if v.PlanDigest == "" {
+		return nil, errors.New("plan digest is empty")
+	}
+	bindableStmt, err := stmtsummary.StmtSummaryByDigestMap.GetBindableStmtByPlanDigest(ctx, v.PlanDigest)
+	if err != nil {
+		return nil, err
+	}
+	if bindableStmt == nil {
+		return nil, errors.Errorf("can't find any plans for '%s' in the statement summary", v.PlanDigest)
+	}
+
+	p := parser.New()
+	originNode, err := p.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
+	if err != nil {
+		return nil, errors.Errorf("binding failed: %v", err)
+	}
+	if insertStmt, ok := originNode.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+		return nil, errors.Errorf("can't create binding for the plan '%s' of an INSERT statement without SELECT", v.PlanDigest)
+	}
+	db := utilparser.GetDefaultDB(originNode, bindableStmt.Schema)
+	for _, tn := range extractTableList(originNode, nil, false) {
+		dbName := tn.Schema
+		if dbName.L == "" {
+			dbName = model.NewCIStr(db)
+		}
+		if tbl, err := b.is.TableByName(dbName, tn.Name); err == nil && tbl.Meta().TempTableType != model.TempTableNone {
+			return nil, ddl.ErrOptOnTemporaryTable.GenWithStackByArgs("create binding")
+		}
+	}
+	normdOrigSQL := parser.Normalize(utilparser.RestoreWithDefaultDB(originNode, db, bindableStmt.Query))
+	// The hints are restored into the statement, so the original statement has to be parsed again.
+	hintedNode, err := p.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
+	if err != nil {
+		return nil, errors.Errorf("binding failed: %v", err)
+	}
+	bindSQL := bindinfo.GenerateBindSQL(ctx, hintedNode, bindableStmt.PlanHint, true, db)
+	if bindSQL == "" {
+		return nil, errors.Errorf("can't create binding for the plan '%s' without hints", v.PlanDigest)
+	}
+	if err = checkHintedSQL(bindSQL, bindableStmt.Charset, bindableStmt.Collation, db); err != nil {
+		return nil, err
+	}
+	bindStmt, err := p.ParseOneStmt(bindSQL, bindableStmt.Charset, bindableStmt.Collation)
+	if err != nil {
+		return nil, errors.Errorf("binding failed: %v", err)
+	}
+
+	plan := &SQLBindPlan{
+		SQLBindOp:    OpSQLBindCreate,
+		NormdOrigSQL: normdOrigSQL,
+		BindSQL:      bindSQL,
+		IsGlobal:     v.GlobalScope,
+		BindStmt:     bindStmt,
+		Db:           db,
+		Charset:      bindableStmt.Charset,
+		Collation:    bindableStmt.Collation,
+		Source:       bindinfo.History,
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
+	return plan, nil
+}
+
 // detectSelectAgg detects an aggregate function or GROUP BY clause.
 func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	if sel.GroupBy != nil {
@@ -1060,13 +1133,14 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
-			if check && latestIndexes == nil {
+			// The hypothetical index never exists in the latest schema.
+			if check && latestIndexes == nil && index.Tp != model.IndexTypeHypo {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
 					return nil, err
 				}
 			}
-			if check {
+			if check && index.Tp != model.IndexTypeHypo {
 				if latestIndex, ok := latestIndexes[index.ID]; !ok || latestIndex.State != model.StatePublic {
 					continue
 				}
@@ -1334,7 +1408,15 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		}
 	case ast.AdminCancelDDLJobs:
 		p := &CancelDDLJobs{JobIDs: as.JobIDs}
-		p.setSchemaAndNames(buildCancelDDLJobsFields())
+		p.setSchemaAndNames(buildCommandDDLJobsFields())
+		ret = p
+	case ast.AdminPauseDDLJobs:
+		p := &PauseDDLJobs{JobIDs: as.JobIDs}
+		p.setSchemaAndNames(buildCommandDDLJobsFields())
+		ret = p
+	case ast.AdminResumeDDLJobs:
+		p := &ResumeDDLJobs{JobIDs: as.JobIDs}
+		p.setSchemaAndNames(buildCommandDDLJobsFields())
 		ret = p
 	case ast.AdminCheckIndexRange:
 		schema, names, err := b.buildCheckIndexSchema(as.Tables[0], as.Index)
@@ -2757,7 +2839,7 @@ func buildShowSlowSchema() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
-func buildCancelDDLJobsFields() (*expression.Schema, types.NameSlice) {
+func buildCommandDDLJobsFields() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(2)
 	schema.Append(buildColumnWithName("", "JOB_ID", mysql.TypeVarchar, 64))
 	schema.Append(buildColumnWithName("", "RESULT", mysql.TypeVarchar, 128))
@@ -3059,6 +3141,14 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 		}
 	case *ast.ShutdownStmt:
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ShutdownPriv, "", "", "", nil)
+	case *ast.LockStatsStmt:
+		if err := b.checkStatsTables(raw.Tables, "lock stats"); err != nil {
+			return nil, err
+		}
+	case *ast.UnlockStatsStmt:
+		if err := b.checkStatsTables(raw.Tables, "unlock stats"); err != nil {
+			return nil, err
+		}
 	case *ast.BeginStmt:
 		readTS := b.ctx.GetSessionVars().TxnReadTS.PeakTxnReadTS()
 		if raw.AsOf != nil {
@@ -3428,6 +3518,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if err != nil {
 		return nil, err
 	}
+	insertPlan.buildOnInsertFKTriggers(b.ctx, b.is, onDupColSet)
 
 	err = insertPlan.ResolveIndices()
 	return insertPlan, err
@@ -3867,6 +3958,36 @@ func (b *PlanBuilder) buildLoadStats(ld *ast.LoadStatsStmt) Plan {
 	return p
 }
 
+func (b *PlanBuilder) buildRestoreStats(rs *ast.RestoreStatsStmt) (Plan, error) {
+	if err := b.checkStatsTables([]*ast.TableName{rs.Table}, "restore stats"); err != nil {
+		return nil, err
+	}
+	version, err := calculateTsExpr(b.ctx, rs.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreStats{Table: rs.Table, Version: version}, nil
+}
+
+// checkStatsTables checks the tables of the statements which change the stats directly,
+// they need the same privileges as ANALYZE.
+func (b *PlanBuilder) checkStatsTables(tables []*ast.TableName, op string) error {
+	for _, tbl := range tables {
+		if tbl.TableInfo != nil && tbl.TableInfo.TempTableType != model.TempTableNone {
+			return ErrOptOnTemporaryTable.GenWithStackByArgs(op)
+		}
+		user := b.ctx.GetSessionVars().User
+		var insertErr, selectErr error
+		if user != nil {
+			insertErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+			selectErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.AuthUsername, user.AuthHostname, tbl.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tbl.Schema.O, tbl.Name.O, "", insertErr)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, tbl.Schema.O, tbl.Name.O, "", selectErr)
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) Plan {
 	p := &IndexAdvise{
 		IsLocal:     node.IsLocal,
@@ -4491,10 +4612,67 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	if err != nil {
 		return nil, err
 	}
+	if !explain.Analyze && len(b.ctx.GetSessionVars().HypoIndexes) > 0 {
+		appendHypoIndexNotes(b.ctx, targetPlan)
+	}
 
 	return b.buildExplainPlan(targetPlan, explain.Format, nil, explain.Analyze, explain.Stmt, nil)
 }
 
+// appendHypoIndexNotes appends a note for every hypothetical index used by the plan, since the plan can't be
+// executed until the indexes are really created.
+func appendHypoIndexNotes(sctx sessionctx.Context, p Plan) {
+	sc := sctx.GetSessionVars().StmtCtx
+	noted := make(map[string]struct{})
+	checkIndex := func(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) {
+		if idxInfo == nil || idxInfo.Tp != model.IndexTypeHypo {
+			return
+		}
+		key := tblInfo.Name.L + "." + idxInfo.Name.L
+		if _, ok := noted[key]; !ok {
+			noted[key] = struct{}{}
+			sc.AppendNote(errors.Errorf("the plan uses the hypothetical index '%s' of table '%s'", idxInfo.Name.O, tblInfo.Name.O))
+		}
+	}
+	var visit func(p Plan)
+	visit = func(p Plan) {
+		switch x := p.(type) {
+		case *PhysicalIndexScan:
+			checkIndex(x.Table, x.Index)
+		case *PointGetPlan:
+			checkIndex(x.TblInfo, x.IndexInfo)
+		case *BatchPointGetPlan:
+			checkIndex(x.TblInfo, x.IndexInfo)
+		case *PhysicalIndexReader:
+			visit(x.indexPlan)
+		case *PhysicalIndexLookUpReader:
+			visit(x.indexPlan)
+		case *PhysicalIndexMergeReader:
+			for _, partialPlan := range x.partialPlans {
+				visit(partialPlan)
+			}
+		case *Insert:
+			if x.SelectPlan != nil {
+				visit(x.SelectPlan)
+			}
+		case *Update:
+			if x.SelectPlan != nil {
+				visit(x.SelectPlan)
+			}
+		case *Delete:
+			if x.SelectPlan != nil {
+				visit(x.SelectPlan)
+			}
+		}
+		if physicalPlan, ok := p.(PhysicalPlan); ok {
+			for _, child := range physicalPlan.Children() {
+				visit(child)
+			}
+		}
+	}
+	visit(p)
+}
+
 func (b *PlanBuilder) buildSelectInto(ctx context.Context, sel *ast.SelectStmt) (Plan, error) {
 	if sem.IsEnabled() {
 		return nil, ErrNotSupportedWithSem.GenWithStackByArgs("SELECT INTO")
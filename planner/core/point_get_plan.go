@@ -599,6 +599,9 @@ func newBatchPointGetPlan(
 			if intDatum == nil {
 				return nil
 			}
+			if param != nil {
+				skipPlanCacheForConvertedParam(stmtCtx, &d, intDatum)
+			}
 			handles[i] = kv.IntHandle(intDatum.GetInt64())
 			handleParams[i] = param
 		}
@@ -690,6 +693,7 @@ func newBatchPointGetPlan(
 					if dval == nil {
 						return nil
 					}
+					skipPlanCacheForConvertedParam(stmtCtx, &innerX.Datum, dval)
 					values[permIndex] = innerX.Datum
 					valuesParams[permIndex] = innerX
 				default:
@@ -715,6 +719,7 @@ func newBatchPointGetPlan(
 			if dval == nil {
 				return nil
 			}
+			skipPlanCacheForConvertedParam(stmtCtx, &x.Datum, dval)
 			values = []types.Datum{*dval}
 			valuesParams = []*driver.ParamMarkerExpr{x}
 		default:
@@ -1218,6 +1223,9 @@ func getNameValuePairs(stmtCtx *stmtctx.StatementContext, tbl *model.TableInfo,
 		if err != nil || cmp != 0 {
 			return nil, false
 		}
+		if param != nil {
+			skipPlanCacheForConvertedParam(stmtCtx, &d, &dVal)
+		}
 		return append(nvPairs, nameValuePair{colName: colName.Name.Name.L, value: dVal, param: param}), false
 	}
 	return nil, false
@@ -1239,6 +1247,15 @@ func getPointGetValue(stmtCtx *stmtctx.StatementContext, col *model.ColumnInfo,
 	return &dVal
 }
 
+// skipPlanCacheForConvertedParam skips the non-prepared plan cache if the parameter is converted to another kind
+// for the column. The parameters are the literals of the query, and the ones of the next queries, such as 2.5 for
+// an int column, may not be converted exactly when the cached plan is reused.
+func skipPlanCacheForConvertedParam(stmtCtx *stmtctx.StatementContext, param, converted *types.Datum) {
+	if stmtCtx.InNonPreparedPlanBuilding && param.Kind() != converted.Kind() {
+		stmtCtx.SkipPlanCache = true
+	}
+}
+
 func checkCanConvertInPointGet(col *model.ColumnInfo, d types.Datum) bool {
 	kind := d.Kind()
 	switch col.FieldType.EvalType() {
@@ -1378,6 +1395,7 @@ func buildPointUpdatePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 	updatePlan.tblID2Table = map[int64]table.Table{
 		tbl.ID: t,
 	}
+	updatePlan.buildOnUpdateFKTriggers(ctx, is, updatePlan.tblID2Table)
 	if tbl.GetPartitionInfo() != nil {
 		pt := t.(table.PartitionedTable)
 		var updateTableList []*ast.TableName
@@ -1481,6 +1499,10 @@ func buildPointDeletePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 			},
 		},
 	}.Init(ctx)
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	if t, ok := is.TableByID(tbl.ID); ok {
+		delPlan.buildOnDeleteFKTriggers(ctx, is, map[int64]table.Table{tbl.ID: t})
+	}
 	return delPlan
 }
 
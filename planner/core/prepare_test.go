@@ -28,11 +28,14 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/kvcache"
@@ -2695,3 +2698,341 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPrepareSerialSuite) TestInstancePlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	tk2 := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = 0")
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk2.MustExec("use test")
+	for _, tk := range []*testkit.TestKit{tk, tk2} {
+		tk.MustExec("prepare stmt from 'select * from t where b = ?'")
+		tk.MustExec("prepare stmt_pg from 'select * from t where a = ?'")
+		tk.MustExec("prepare stmt_range from 'select a from t where b > ? order by c limit 2'")
+	}
+
+	// The plans built by a session are used by the other sessions.
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("set @a = 2")
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("2 2 2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @a = 3")
+	tk.MustQuery("execute stmt_pg using @a").Check(testkit.Rows("3 3 3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustQuery("execute stmt_pg using @a").Check(testkit.Rows("2 2 2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @a = 0")
+	tk.MustQuery("execute stmt_range using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("set @a = 1")
+	tk2.MustQuery("execute stmt_range using @a").Check(testkit.Rows("2", "3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The plans of the sessions with different variables aren't shared.
+	tk2.MustExec("set @@tidb_opt_prefer_range_scan = 1")
+	tk2.MustQuery("execute stmt_range using @a").Check(testkit.Rows("2", "3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("set @@tidb_opt_prefer_range_scan = default")
+
+	tk.MustQuery("select sql_text, param_types, hit_count, miss_count, hit_rate from information_schema.instance_plan_cache order by sql_text, hit_count").Check(testkit.Rows(
+		"select * from t where a = ? bigint 1 1 0.5",
+		"select * from t where b = ? bigint 2 1 0.6666666666666666",
+		"select a from t where b > ? order by c limit 2 bigint 0 1 0",
+		"select a from t where b > ? order by c limit 2 bigint 1 1 0.5",
+	))
+
+	// The plans are invalidated by DDL.
+	tk.MustExec("alter table t add column d int")
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The plans are invalidated when the statistics change.
+	tk.MustExec("analyze table t")
+	c.Assert(dom.StatsHandle().Update(dom.InfoSchema()), IsNil)
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select hit_count, miss_count from information_schema.instance_plan_cache where sql_text = 'select * from t where b = ?'").Check(testkit.Rows("4 3"))
+
+	// The plans exceeding the memory quota aren't cached.
+	tk.MustExec("set global tidb_instance_plan_cache_max_mem_size = 1")
+	defer tk.MustExec("set global tidb_instance_plan_cache_max_mem_size = default")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt_pg using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+	tk.MustExec("set global tidb_instance_plan_cache_max_mem_size = default")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("1"))
+	tk.MustExec("admin flush instance plan_cache")
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+
+	// The statements with the same SQL digest share the plans, unless their literals are different.
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk2.MustExec("prepare stmt_upper from 'SELECT * FROM t  WHERE b = ? /* comment */'")
+	tk2.MustQuery("execute stmt_upper using @a").Check(testkit.Rows("1 1 1 <nil>"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("execute stmt_range using @a").Check(testkit.Rows("2", "3"))
+	tk2.MustExec("prepare stmt_limit from 'select a from t where b > ? order by c limit 1'")
+	tk2.MustQuery("execute stmt_limit using @a").Check(testkit.Rows("2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
+func (s *testPrepareSerialSuite) TestInstancePlanCacheWithHintsAndNames(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	tk2 := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = 0")
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk2.MustExec("use test")
+	tk.MustExec("set @a = 1")
+	tk2.MustExec("set @a = 1")
+
+	// The statements with different hints don't share the plans.
+	tk.MustExec("prepare stmt from 'select /*+ use_index(t, b) */ a from t where b = ?'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("prepare stmt from 'select /*+ ignore_index(t, b) */ a from t where b = ?'")
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("prepare stmt from 'select a from t where b = ?'")
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("prepare stmt from 'select /*+ USE_INDEX(t, b) */ a from t where b = ?'")
+	tk2.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("3"))
+
+	// The statements with the aliases differing in the letter case don't share the plans, because the names
+	// of the result columns are different.
+	tk.MustExec("prepare stmt from 'select a as X from t where b = ?'")
+	rs, err := tk.Exec("execute stmt using @a")
+	c.Assert(err, IsNil)
+	c.Assert(rs.Fields()[0].ColumnAsName.O, Equals, "X")
+	c.Assert(rs.Close(), IsNil)
+	tk2.MustExec("prepare stmt from 'select a as x from t where b = ?'")
+	rs, err = tk2.Exec("execute stmt using @a")
+	c.Assert(err, IsNil)
+	c.Assert(rs.Fields()[0].ColumnAsName.O, Equals, "x")
+	c.Assert(rs.Close(), IsNil)
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("prepare stmt from 'select a  as  X from t where b = ?'")
+	rs, err = tk2.Exec("execute stmt using @a")
+	c.Assert(err, IsNil)
+	c.Assert(rs.Fields()[0].ColumnAsName.O, Equals, "X")
+	c.Assert(rs.Close(), IsNil)
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+}
+
+func (s *testPrepareSerialSuite) TestNonPreparedPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), key(b))")
+	tk.MustExec("insert into t values (1, 1, 'a'), (2, 2, 'b'), (3, 3, 'c')")
+
+	// The plan cache is disabled by default.
+	tk.MustQuery("select * from t where b = 1").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select * from t where b = 2").Check(testkit.Rows("2 2 b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustQuery("select * from t where b = 1").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 2").Check(testkit.Rows("2 2 b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t where b = 3 and c = 'c'").Check(testkit.Rows("3 3 c"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 2 and c = 'c'").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The point queries.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 3").Check(testkit.Rows("3 3 c"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The plans comparing int columns with non-int literals aren't cached, because the literals would be rounded
+	// when the ranges and the handles are rebuilt.
+	tk.MustQuery("select * from t where b = 1.0").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 2.0").Check(testkit.Rows("2 2 b"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 2.5").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t where a = 1.0").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where a = 2.5").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t where a in (1.0, 2.0)").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where a in (1.5, 3.0)").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The literals in the fields, order by and limit clauses are kept.
+	tk.MustQuery("select b, 1 from t where b < 3 order by 1 limit 1").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select b, 2 from t where b < 3 order by 1 limit 1").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select b, 2 from t where b < 2 order by 1 limit 1").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The explain statements use the plan cache too, and the cached plans are the same as the ones built without it.
+	tk.MustQuery("explain format = 'brief' select * from t where b = 3").Check(testkit.Rows(
+		"IndexLookUp 10.00 root  ",
+		"├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:b(b) range:[3,3], keep order:false, stats:pseudo",
+		"└─TableRowIDScan(Probe) 10.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	for _, sql := range []string{
+		"select * from t where b = 1 and c = 'a'",
+		"select * from t use index(b) where b >= 2",
+		"select * from t where a > 1 and c = 'c'",
+	} {
+		tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 0")
+		expected := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+		tk.MustQuery("explain format = 'brief' " + sql).Check(expected)
+		tk.MustQuery("explain format = 'brief' " + sql).Check(expected)
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	}
+	tk.MustQuery("select * from t use index(b) where b >= 3").Check(testkit.Rows("3 3 c"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The uncacheable queries.
+	tk.MustQuery("select * from t where b = 1 for update").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select * from t where b = 1 for update").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select t1.a from t t1, t t2 where t1.a = t2.a and t1.b = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select t1.a from t t1, t t2 where t1.a = t2.a and t1.b = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select /*+ ignore_plan_cache() */ * from t where b = 1").Check(testkit.Rows("1 1 a"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 1 and c = database()").Check(testkit.Rows())
+	tk.MustQuery("select * from t where b = 1 and c = database()").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The plans are invalidated by DDL.
+	tk.MustExec("alter table t add column d int")
+	tk.MustQuery("select * from t where b = 1").Check(testkit.Rows("1 1 a <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where b = 2").Check(testkit.Rows("2 2 b <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The prepared statements don't use the non-prepared plan cache.
+	tk.MustExec("prepare stmt from 'select * from t where b = 3'")
+	tk.MustQuery("execute stmt").Check(testkit.Rows("3 3 c <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt").Check(testkit.Rows("3 3 c <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+}
+
+func (s *testPrepareSerialSuite) TestExplainExecuteWithHypoIndex(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("create index idx_a on t(a) type hypo")
+	// The parser doesn't support EXPLAIN EXECUTE, so the statement is built from the AST.
+	explainExecuteUsesHypoIndex := func() bool {
+		execStmt, err := parser.New().ParseOneStmt("execute stmt using @a", "", "")
+		c.Assert(err, IsNil)
+		rs, err := tk.Se.ExecuteStmt(context.Background(), &ast.ExplainStmt{Stmt: execStmt, Format: types.ExplainFormatROW})
+		c.Assert(err, IsNil)
+		for _, row := range tk.ResultSetToResult(rs, Commentf("explain execute")).Rows() {
+			if strings.Contains(fmt.Sprintf("%v", row), "idx_a") {
+				return true
+			}
+		}
+		return false
+	}
+
+	check := func(instanceCache bool) {
+		tk.MustExec(fmt.Sprintf("set global tidb_enable_instance_plan_cache = %v", instanceCache))
+		tk.MustExec("prepare stmt from 'select b from t where a = ?'")
+		tk.MustExec("set @a = 1")
+		// The plan with the hypothetical index isn't cached, so it's never used for execution.
+		c.Assert(explainExecuteUsesHypoIndex(), IsTrue)
+		tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+		tk.MustExec("set @a = 2")
+		tk.MustQuery("execute stmt using @a").Check(testkit.Rows("2"))
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+		// The cached plan isn't taken when explaining with the hypothetical index.
+		c.Assert(explainExecuteUsesHypoIndex(), IsTrue)
+		tk.MustExec("set @a = 3")
+		tk.MustQuery("execute stmt using @a").Check(testkit.Rows("3"))
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+		tk.MustExec("deallocate prepare stmt")
+	}
+	check(false)
+	tk.MustExec("drop hypo index idx_a on t")
+	tk.MustExec("create index idx_a on t(a) type hypo")
+	check(true)
+	tk.MustExec("set global tidb_enable_instance_plan_cache = default")
+}
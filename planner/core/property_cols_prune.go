@@ -85,6 +85,12 @@ func (p *TiKVSingleGather) PreparePossibleProperties(schema *expression.Schema,
 	return childrenProperties[0]
 }
 
+// PreparePossibleProperties implements LogicalPlan PreparePossibleProperties interface.
+func (p *TiKVDoubleGather) PreparePossibleProperties(schema *expression.Schema, childrenProperties ...[][]*expression.Column) [][]*expression.Column {
+	// Only the order of the index side can be kept.
+	return childrenProperties[0]
+}
+
 // PreparePossibleProperties implements LogicalPlan PreparePossibleProperties interface.
 func (p *LogicalSelection) PreparePossibleProperties(schema *expression.Schema, childrenProperties ...[][]*expression.Column) [][]*expression.Column {
 	return childrenProperties[0]
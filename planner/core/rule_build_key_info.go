@@ -328,6 +328,21 @@ func (tg *TiKVSingleGather) BuildKeyInfo(selfSchema *expression.Schema, childSch
 	selfSchema.Keys = childSchema[0].Keys
 }
 
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (dg *TiKVDoubleGather) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	selfSchema.Keys = childSchema[1].Keys
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (g *TiKVIndexMergeGather) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	selfSchema.Keys = childSchema[len(childSchema)-1].Keys
+}
+
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (pg *TiKVPointGet) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	pg.Source.BuildKeyInfo(selfSchema, childSchema)
+}
+
 func (*buildKeySolver) name() string {
 	return "build_keys"
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -132,6 +133,48 @@ func substituteExpression(cond expression.Expression, sctx *stmtctx.StatementCon
 	}
 }
 
+// substituteVirtualColumnsForStats substitutes the expressions of the virtual generated columns in the conditions with
+// the columns which have statistics, so that the selectivity can be estimated by their histograms instead of the pseudo
+// estimation even if the columns are not indexed. The conditions are cloned so the plan itself is not changed.
+// It returns the substituted conditions and the histogram collection which contains the statistics of these columns.
+func (ds *DataSource) substituteVirtualColumnsForStats(conds expression.CNFExprs, coll *statistics.HistColl) (expression.CNFExprs, *statistics.HistColl) {
+	if ds.TblColHists == nil || ds.TblColHists.Pseudo || len(conds) == 0 {
+		return conds, coll
+	}
+	exprToColumn := make(ExprColumnMap)
+	for _, col := range ds.TblCols {
+		// Unlike collectGenerateColumn, the conditions are only used for estimation, so the same evaluation type is enough.
+		if col.VirtualExpr == nil || col.GetType().EvalType() != col.VirtualExpr.GetType().EvalType() {
+			continue
+		}
+		if _, ok := ds.TblColHists.Columns[col.UniqueID]; ok {
+			exprToColumn[col.VirtualExpr] = col
+		}
+	}
+	if len(exprToColumn) == 0 {
+		return conds, coll
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	schema := expression.NewSchema(ds.TblCols...)
+	newConds := make(expression.CNFExprs, 0, len(conds))
+	for _, cond := range conds {
+		newCond := cond.Clone()
+		substituteExpression(newCond, sc, ds.ctx, exprToColumn, schema)
+		newConds = append(newConds, newCond)
+	}
+	newColl := *coll
+	newColl.Columns = make(map[int64]*statistics.Column, len(coll.Columns)+len(exprToColumn))
+	for id, c := range coll.Columns {
+		newColl.Columns[id] = c
+	}
+	for _, col := range exprToColumn {
+		if _, ok := newColl.Columns[col.UniqueID]; !ok {
+			newColl.Columns[col.UniqueID] = ds.TblColHists.Columns[col.UniqueID]
+		}
+	}
+	return newConds, &newColl
+}
+
 func (gc *gcSubstituter) substitute(ctx context.Context, lp LogicalPlan, exprToColumn ExprColumnMap) LogicalPlan {
 	sctx := lp.SCtx().GetSessionVars().StmtCtx
 	var tp types.EvalType
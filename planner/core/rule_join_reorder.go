@@ -21,6 +21,7 @@ import (
 	"sort"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/tracing"
@@ -32,14 +33,27 @@ import (
 //
 // For example: "InnerJoin(InnerJoin(a, b), LeftJoin(c, d))"
 // results in a join group {a, b, LeftJoin(c, d)}.
-func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) {
+//
+// The outer side of an outer join is also expanded if the outer join is
+// allowed to be reordered, its inner side is kept as a single node and the
+// outer join is returned as an outerJoinEdge. For example:
+// "InnerJoin(LeftJoin(InnerJoin(a, b), c), d)" results in a join group
+// {a, b, c, d} and an outer join edge from a or b to c.
+func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression, outerJoins []*outerJoinEdge) {
 	join, isJoin := p.(*LogicalJoin)
-	if !isJoin || join.preferJoinType > uint(0) || join.JoinType != InnerJoin || join.StraightJoin {
-		return []LogicalPlan{p}, nil, nil
+	if !isJoin || join.preferJoinType > uint(0) || join.StraightJoin {
+		return []LogicalPlan{p}, nil, nil, nil
+	}
+	switch join.JoinType {
+	case InnerJoin:
+	case LeftOuterJoin, RightOuterJoin:
+		return extractOuterJoinGroup(join)
+	default:
+		return []LogicalPlan{p}, nil, nil, nil
 	}
 
-	lhsGroup, lhsEqualConds, lhsOtherConds := extractJoinGroup(join.children[0])
-	rhsGroup, rhsEqualConds, rhsOtherConds := extractJoinGroup(join.children[1])
+	lhsGroup, lhsEqualConds, lhsOtherConds, lhsOuterJoins := extractJoinGroup(join.children[0])
+	rhsGroup, rhsEqualConds, rhsOtherConds, rhsOuterJoins := extractJoinGroup(join.children[1])
 
 	group = append(group, lhsGroup...)
 	group = append(group, rhsGroup...)
@@ -49,7 +63,92 @@ func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression
 	otherConds = append(otherConds, join.OtherConditions...)
 	otherConds = append(otherConds, lhsOtherConds...)
 	otherConds = append(otherConds, rhsOtherConds...)
-	return group, eqEdges, otherConds
+	outerJoins = append(outerJoins, lhsOuterJoins...)
+	outerJoins = append(outerJoins, rhsOuterJoins...)
+	return group, eqEdges, otherConds, outerJoins
+}
+
+// extractOuterJoinGroup expands the outer side of the outer join into the join group.
+// The outer join is kept as a single node if it can't be reordered, that is, it has
+// no equal condition or its conditions refer to more than one node of the outer side.
+func extractOuterJoinGroup(join *LogicalJoin) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression, outerJoins []*outerJoinEdge) {
+	if !join.ctx.GetSessionVars().EnableOuterJoinReorder || len(join.EqualConditions) == 0 {
+		return []LogicalPlan{join}, nil, nil, nil
+	}
+	outerIdx := 0
+	if join.JoinType == RightOuterJoin {
+		outerIdx = 1
+	}
+	group, eqEdges, otherConds, outerJoins = extractJoinGroup(join.children[outerIdx])
+	edge := newOuterJoinEdge(join, outerIdx)
+	if len(group) > 1 {
+		refNodes := 0
+		for _, node := range group {
+			if edge.refersTo(node.Schema()) {
+				refNodes++
+			}
+		}
+		if refNodes > 1 {
+			return []LogicalPlan{join}, nil, nil, nil
+		}
+	}
+	group = append(group, edge.innerNode)
+	outerJoins = append(outerJoins, edge)
+	return group, eqEdges, otherConds, outerJoins
+}
+
+// outerJoinEdge is an outer join in the join group. The inner side of the outer join
+// is a single node of the group, and the conditions of the outer join only refer to it
+// and one node of the outer side, so the outer join can be done once that node is joined.
+type outerJoinEdge struct {
+	innerNode LogicalPlan
+	// eqConds are the equal conditions whose first argument is from the outer side.
+	eqConds []*expression.ScalarFunction
+	// outerConds and innerConds are the conditions only referring to the outer side
+	// and the inner side, otherConds are the other conditions of the outer join.
+	outerConds []expression.Expression
+	innerConds []expression.Expression
+	otherConds []expression.Expression
+}
+
+func newOuterJoinEdge(join *LogicalJoin, outerIdx int) *outerJoinEdge {
+	edge := &outerJoinEdge{
+		innerNode:  join.children[1-outerIdx],
+		outerConds: join.LeftConditions,
+		innerConds: join.RightConditions,
+		otherConds: join.OtherConditions,
+	}
+	if outerIdx == 0 {
+		edge.eqConds = join.EqualConditions
+		return edge
+	}
+	edge.outerConds, edge.innerConds = join.RightConditions, join.LeftConditions
+	edge.eqConds = make([]*expression.ScalarFunction, 0, len(join.EqualConditions))
+	for _, cond := range join.EqualConditions {
+		args := cond.GetArgs()
+		newCond := expression.NewFunctionInternal(join.ctx, ast.EQ, cond.GetType(), args[1], args[0]).(*expression.ScalarFunction)
+		edge.eqConds = append(edge.eqConds, newCond)
+	}
+	return edge
+}
+
+// refersTo checks whether the conditions of the outer join refer to the columns of the schema.
+func (e *outerJoinEdge) refersTo(schema *expression.Schema) bool {
+	for _, cond := range e.eqConds {
+		if schema.Contains(cond.GetArgs()[0].(*expression.Column)) {
+			return true
+		}
+	}
+	for _, conds := range [][]expression.Expression{e.outerConds, e.otherConds} {
+		for _, cond := range conds {
+			for _, col := range expression.ExtractColumns(cond) {
+				if schema.Contains(col) {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 type joinReOrderSolver struct {
@@ -72,31 +171,44 @@ func (s *joinReOrderSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 // optimizeRecursive recursively collects join groups and applies join reorder algorithm for each group.
 func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalPlan, tracer *joinReorderTrace) (LogicalPlan, error) {
 	var err error
-	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
+	curJoinGroup, eqEdges, otherConds, outerJoins := extractJoinGroup(p)
+	// A single outer join has no other join order.
+	if len(curJoinGroup) == 2 && len(outerJoins) == 1 {
+		curJoinGroup = []LogicalPlan{p}
+	}
 	if len(curJoinGroup) > 1 {
 		for i := range curJoinGroup {
-			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i], tracer)
+			node := curJoinGroup[i]
+			curJoinGroup[i], err = s.optimizeRecursive(ctx, node, tracer)
 			if err != nil {
 				return nil, err
 			}
-		}
-		baseGroupSolver := &baseSingleGroupJoinOrderSolver{
-			ctx:        ctx,
-			otherConds: otherConds,
+			for _, outerJoin := range outerJoins {
+				if outerJoin.innerNode == node {
+					outerJoin.innerNode = curJoinGroup[i]
+				}
+			}
 		}
 		originalSchema := p.Schema()
 		if len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
-			groupSolver := &joinReorderGreedySolver{
-				baseSingleGroupJoinOrderSolver: baseGroupSolver,
-				eqEdges:                        eqEdges,
-			}
-			p, err = groupSolver.solve(curJoinGroup, tracer)
+			p, err = s.solveByGreedy(ctx, curJoinGroup, eqEdges, otherConds, outerJoins, tracer)
 		} else {
 			dpSolver := &joinReorderDPSolver{
-				baseSingleGroupJoinOrderSolver: baseGroupSolver,
+				baseSingleGroupJoinOrderSolver: &baseSingleGroupJoinOrderSolver{
+					ctx:        ctx,
+					otherConds: otherConds,
+					outerJoins: outerJoins,
+				},
 			}
 			dpSolver.newJoin = dpSolver.newJoinWithEdges
-			p, err = dpSolver.solve(curJoinGroup, expression.ScalarFuncs2Exprs(eqEdges), tracer)
+			var newPlan LogicalPlan
+			newPlan, err = dpSolver.solve(curJoinGroup, expression.ScalarFuncs2Exprs(eqEdges), tracer)
+			// The DP solver may fail to find a join order satisfying the outer joins, then
+			// the greedy solver is used, it can always find one.
+			if err == nil && newPlan == nil {
+				newPlan, err = s.solveByGreedy(ctx, curJoinGroup, eqEdges, otherConds, outerJoins, tracer)
+			}
+			p = newPlan
 		}
 		if err != nil {
 			return nil, err
@@ -134,11 +246,73 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	return p, nil
 }
 
+func (s *joinReOrderSolver) solveByGreedy(ctx sessionctx.Context, curJoinGroup []LogicalPlan, eqEdges []*expression.ScalarFunction,
+	otherConds []expression.Expression, outerJoins []*outerJoinEdge, tracer *joinReorderTrace) (LogicalPlan, error) {
+	groupSolver := &joinReorderGreedySolver{
+		baseSingleGroupJoinOrderSolver: &baseSingleGroupJoinOrderSolver{
+			ctx:        ctx,
+			otherConds: append([]expression.Expression(nil), otherConds...),
+			outerJoins: outerJoins,
+		},
+		eqEdges: eqEdges,
+	}
+	return groupSolver.solve(curJoinGroup, tracer)
+}
+
 // nolint:structcheck
 type baseSingleGroupJoinOrderSolver struct {
 	ctx          sessionctx.Context
 	curJoinGroup []*jrNode
 	otherConds   []expression.Expression
+	outerJoins   []*outerJoinEdge
+}
+
+// checkOuterJoin checks whether the two nodes can be joined with the outer joins in the group.
+// The inner node of an outer join can only be joined with the node containing its outer node
+// by the outer join, the outer join is returned in this case and outerIsLeft tells which side
+// its outer node is in.
+func (s *baseSingleGroupJoinOrderSolver) checkOuterJoin(lNode, rNode LogicalPlan) (outerJoin *outerJoinEdge, outerIsLeft bool, ok bool) {
+	for _, edge := range s.outerJoins {
+		switch edge.innerNode {
+		case lNode:
+			if !edge.refersTo(rNode.Schema()) {
+				return nil, false, false
+			}
+			outerJoin, outerIsLeft = edge, false
+		case rNode:
+			if !edge.refersTo(lNode.Schema()) {
+				return nil, false, false
+			}
+			outerJoin, outerIsLeft = edge, true
+		}
+	}
+	return outerJoin, outerIsLeft, true
+}
+
+// newOuterJoin builds the outer join of the edge, the outer child contains the outer node of it.
+// The conditions of the join group connecting the two children are evaluated after the outer join,
+// so they're put in a selection above it.
+func (s *baseSingleGroupJoinOrderSolver) newOuterJoin(outer LogicalPlan, outerJoin *outerJoinEdge, filters []expression.Expression) LogicalPlan {
+	offset := outer.SelectBlockOffset()
+	if offset != outerJoin.innerNode.SelectBlockOffset() {
+		offset = -1
+	}
+	join := LogicalJoin{
+		JoinType:        LeftOuterJoin,
+		reordered:       true,
+		EqualConditions: outerJoin.eqConds,
+		LeftConditions:  outerJoin.outerConds,
+		RightConditions: outerJoin.innerConds,
+		OtherConditions: outerJoin.otherConds,
+	}.Init(s.ctx, offset)
+	join.SetChildren(outer, outerJoin.innerNode)
+	join.SetSchema(buildLogicalJoinSchema(LeftOuterJoin, join))
+	if len(filters) == 0 {
+		return join
+	}
+	sel := LogicalSelection{Conditions: filters}.Init(s.ctx, offset)
+	sel.SetChildren(join)
+	return sel
 }
 
 // baseNodeCumCost calculate the cumulative cost of the node in the join group.
@@ -75,6 +75,19 @@ func (s *joinReorderDPSolver) solve(joinGroup []LogicalPlan, eqConds []expressio
 		}
 		addEqEdge(lIdx, rIdx, sf)
 	}
+	// The outer joins only connect the nodes, their conditions are added when the outer joins are built.
+	for _, outerJoin := range s.outerJoins {
+		outerIdx, err := findNodeIndexInGroup(joinGroup, outerJoin.eqConds[0].GetArgs()[0].(*expression.Column))
+		if err != nil {
+			return nil, err
+		}
+		for innerIdx, node := range joinGroup {
+			if node == outerJoin.innerNode {
+				adjacents[outerIdx] = append(adjacents[outerIdx], innerIdx)
+				adjacents[innerIdx] = append(adjacents[innerIdx], outerIdx)
+			}
+		}
+	}
 	totalNonEqEdges := make([]joinGroupNonEqEdge, 0, len(s.otherConds))
 	for _, cond := range s.otherConds {
 		cols := expression.ExtractColumns(cond)
@@ -126,6 +139,10 @@ func (s *joinReorderDPSolver) solve(joinGroup []LogicalPlan, eqConds []expressio
 		if err != nil {
 			return nil, err
 		}
+		// No join order of the sub graph satisfies the outer joins.
+		if join == nil {
+			return nil, nil
+		}
 		joins = append(joins, join)
 	}
 	remainedOtherConds := make([]expression.Expression, 0, len(totalNonEqEdges))
@@ -183,13 +200,25 @@ func (s *joinReorderDPSolver) dpGraph(visitID2NodeID, nodeID2VisitID []int, join
 			if bestPlan[sub] == nil || bestPlan[remain] == nil {
 				continue
 			}
+			outerJoin, outerIsLeft, ok := s.checkOuterJoin(bestPlan[sub].p, bestPlan[remain].p)
+			if !ok {
+				continue
+			}
 			// Get the edge connecting the two parts.
 			usedEdges, otherConds := s.nodesAreConnected(sub, remain, nodeID2VisitID, totalEqEdges, totalNonEqEdges)
 			// Here we only check equal condition currently.
-			if len(usedEdges) == 0 {
+			if len(usedEdges) == 0 && outerJoin == nil {
 				continue
 			}
-			join, err := s.newJoinWithEdge(bestPlan[sub].p, bestPlan[remain].p, usedEdges, otherConds)
+			var (
+				join LogicalPlan
+				err  error
+			)
+			if outerJoin != nil {
+				join, err = s.newOuterJoinWithEdge(bestPlan[sub].p, bestPlan[remain].p, outerJoin, outerIsLeft, usedEdges, otherConds)
+			} else {
+				join, err = s.newJoinWithEdge(bestPlan[sub].p, bestPlan[remain].p, usedEdges, otherConds)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -206,6 +235,9 @@ func (s *joinReorderDPSolver) dpGraph(visitID2NodeID, nodeID2VisitID []int, join
 			}
 		}
 	}
+	if bestPlan[(1<<nodeCnt)-1] == nil {
+		return nil, nil
+	}
 	return bestPlan[(1<<nodeCnt)-1].p, nil
 }
 
@@ -253,6 +285,24 @@ func (s *joinReorderDPSolver) newJoinWithEdge(leftPlan, rightPlan LogicalPlan, e
 	return join, err
 }
 
+// newOuterJoinWithEdge builds the outer join of the two plans, the equal edges and other conditions
+// connecting them are evaluated after the outer join.
+func (s *joinReorderDPSolver) newOuterJoinWithEdge(leftPlan, rightPlan LogicalPlan, outerJoin *outerJoinEdge, outerIsLeft bool,
+	edges []joinGroupEqEdge, otherConds []expression.Expression) (LogicalPlan, error) {
+	filters := make([]expression.Expression, 0, len(edges)+len(otherConds))
+	for _, edge := range edges {
+		filters = append(filters, edge.edge)
+	}
+	filters = append(filters, otherConds...)
+	outer := rightPlan
+	if outerIsLeft {
+		outer = leftPlan
+	}
+	join := s.newOuterJoin(outer, outerJoin, filters)
+	_, err := join.recursiveDeriveStats(nil)
+	return join, err
+}
+
 // Make cartesian join as bushy tree.
 func (s *joinReorderDPSolver) makeBushyJoin(cartesianJoinGroup []LogicalPlan, otherConds []expression.Expression) LogicalPlan {
 	for len(cartesianJoinGroup) > 1 {
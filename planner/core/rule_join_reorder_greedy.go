@@ -39,6 +39,9 @@ type joinReorderGreedySolver struct {
 //   See baseNodeCumCost for more details.
 // TODO: this formula can be changed to real physical cost in future.
 //
+// The inner node of an outer join can only be joined with the join tree
+// containing its outer node, by the outer join.
+//
 // For the nodes and join trees which don't have a join equal condition to
 // connect them, we make a bushy join tree to do the cartesian joins finally.
 func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan, tracer *joinReorderTrace) (LogicalPlan, error) {
@@ -66,10 +69,44 @@ func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan, tracer *joi
 		}
 		cartesianGroup = append(cartesianGroup, newNode.p)
 	}
+	if len(s.outerJoins) > 0 {
+		var err error
+		cartesianGroup, err = s.joinConnectedTrees(cartesianGroup)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return s.makeBushyJoin(cartesianGroup), nil
 }
 
+// joinConnectedTrees joins the join trees which are still connected with each other. It happens when
+// the inner node of an outer join can't be joined with the join tree being built because its outer node
+// isn't in it, the inner node is then left alone and connects the join trees built later.
+func (s *joinReorderGreedySolver) joinConnectedTrees(joinTrees []LogicalPlan) ([]LogicalPlan, error) {
+	for joined := true; joined; {
+		joined = false
+		for i := 0; i < len(joinTrees) && !joined; i++ {
+			for j := i + 1; j < len(joinTrees); j++ {
+				newJoin, remainOthers := s.checkConnectionAndMakeJoin(joinTrees[i], joinTrees[j])
+				if newJoin == nil {
+					continue
+				}
+				_, err := newJoin.recursiveDeriveStats(nil)
+				if err != nil {
+					return nil, err
+				}
+				joinTrees[i] = newJoin
+				joinTrees = append(joinTrees[:j], joinTrees[j+1:]...)
+				s.otherConds = remainOthers
+				joined = true
+				break
+			}
+		}
+	}
+	return joinTrees, nil
+}
+
 func (s *joinReorderGreedySolver) constructConnectedJoinTree(tracer *joinReorderTrace) (*jrNode, error) {
 	curJoinTree := s.curJoinGroup[0]
 	s.curJoinGroup = s.curJoinGroup[1:]
@@ -111,6 +148,10 @@ func (s *joinReorderGreedySolver) constructConnectedJoinTree(tracer *joinReorder
 }
 
 func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftNode, rightNode LogicalPlan) (LogicalPlan, []expression.Expression) {
+	outerJoin, outerIsLeft, ok := s.checkOuterJoin(leftNode, rightNode)
+	if !ok {
+		return nil, nil
+	}
 	var usedEdges []*expression.ScalarFunction
 	remainOtherConds := make([]expression.Expression, len(s.otherConds))
 	copy(remainOtherConds, s.otherConds)
@@ -124,7 +165,7 @@ func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftNode, rightNode
 			usedEdges = append(usedEdges, newSf)
 		}
 	}
-	if len(usedEdges) == 0 {
+	if len(usedEdges) == 0 && outerJoin == nil {
 		return nil, nil
 	}
 	var otherConds []expression.Expression
@@ -132,5 +173,13 @@ func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftNode, rightNode
 	remainOtherConds, otherConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
 		return expression.ExprFromSchema(expr, mergedSchema)
 	})
+	if outerJoin != nil {
+		outer := rightNode
+		if outerIsLeft {
+			outer = leftNode
+		}
+		filters := append(expression.ScalarFuncs2Exprs(usedEdges), otherConds...)
+		return s.newOuterJoin(outer, outerJoin, filters), remainOtherConds
+	}
 	return s.newJoinWithEdges(leftNode, rightNode, usedEdges, otherConds), remainOtherConds
 }
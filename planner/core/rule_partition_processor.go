@@ -25,9 +25,11 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
@@ -343,6 +345,79 @@ func (s *partitionProcessor) processHashPartition(ds *DataSource, pi *model.Part
 	return tableDual, nil
 }
 
+func (s *partitionProcessor) findUsedKeyPartitions(ctx sessionctx.Context, tbl table.Table, partitionNames []model.CIStr,
+	conds []expression.Expression, columns []*expression.Column, names types.NameSlice) ([]int, error) {
+	pi := tbl.Meta().Partition
+	partExpr, err := tbl.(partitionTable).PartitionExpr()
+	if err != nil {
+		return nil, err
+	}
+	keyCols := make([]*expression.Column, 0, len(pi.Columns))
+	colLen := make([]int, 0, len(pi.Columns))
+	for i, col := range pi.Columns {
+		idx := expression.FindFieldNameIdxByColName(names, col.L)
+		if idx < 0 {
+			return []int{FullRange}, nil
+		}
+		keyCol := columns[idx].Clone().(*expression.Column)
+		keyCol.Index = i
+		keyCols = append(keyCols, keyCol)
+		colLen = append(colLen, types.UnspecifiedLength)
+	}
+	detachedResult, err := ranger.DetachCondAndBuildRangeForPartition(ctx, conds, keyCols, colLen)
+	if err != nil {
+		return nil, err
+	}
+	ranges := detachedResult.Ranges
+	used := make([]int, 0, len(ranges))
+	for _, r := range ranges {
+		// Only the point ranges on all the partitioning columns can be located, since the key
+		// partitioning hashes the values.
+		if !r.IsPointNullable(ctx) || len(r.HighVal) != len(keyCols) {
+			used = []int{FullRange}
+			break
+		}
+		idx, err := partExpr.LocateKeyPartition(pi.Num, r.HighVal)
+		if err != nil {
+			return nil, err
+		}
+		if len(partitionNames) > 0 && !s.findByName(partitionNames, pi.Definitions[idx].Name.L) {
+			continue
+		}
+		used = append(used, idx)
+	}
+	if len(used) == 1 && used[0] == FullRange {
+		or := partitionRangeOR{partitionRange{0, len(pi.Definitions)}}
+		return s.convertToIntSlice(or, pi, partitionNames), nil
+	}
+	sort.Ints(used)
+	ret := used[:0]
+	for i := 0; i < len(used); i++ {
+		if i == 0 || used[i] != used[i-1] {
+			ret = append(ret, used[i])
+		}
+	}
+	return ret, nil
+}
+
+func (s *partitionProcessor) processKeyPartition(ds *DataSource, pi *model.PartitionInfo, opt *logicalOptimizeOp) (LogicalPlan, error) {
+	names, err := s.reconstructTableColNames(ds)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.findUsedKeyPartitions(ds.SCtx(), ds.table, ds.partitionNames, ds.allConds, ds.TblCols, names)
+	if err != nil {
+		return nil, err
+	}
+	if used != nil {
+		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi), opt)
+	}
+	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
+	tableDual.schema = ds.Schema()
+	appendNoPartitionChildTraceStep(ds, tableDual, opt)
+	return tableDual, nil
+}
+
 // listPartitionPruner uses to prune partition for list partition.
 type listPartitionPruner struct {
 	*partitionProcessor
@@ -638,6 +713,8 @@ func (s *partitionProcessor) prune(ds *DataSource, opt *logicalOptimizeOp) (Logi
 		return s.processHashPartition(ds, pi, opt)
 	case model.PartitionTypeList:
 		return s.processListPartition(ds, pi, opt)
+	case model.PartitionTypeKey:
+		return s.processKeyPartition(ds, pi, opt)
 	}
 
 	// We haven't implement partition by list and so on.
@@ -1286,7 +1363,19 @@ func (s *partitionProcessor) resolveAccessPaths(ds *DataSource) error {
 	if err != nil {
 		return err
 	}
-	possiblePaths, err = filterPathByIsolationRead(ds.ctx, possiblePaths, ds.tableInfo.Name, ds.DBName)
+	// The global indexes contain the entries of all the partitions, they can't be used to read a single partition.
+	localPaths := possiblePaths[:0]
+	for _, path := range possiblePaths {
+		if path.Index == nil || !path.Index.Global {
+			localPaths = append(localPaths, path)
+		}
+	}
+	if len(localPaths) == 0 {
+		tablePath := &util.AccessPath{StoreType: kv.TiKV}
+		fillContentForTablePath(tablePath, ds.tableInfo)
+		localPaths = append(localPaths, tablePath)
+	}
+	possiblePaths, err = filterPathByIsolationRead(ds.ctx, localPaths, ds.tableInfo.Name, ds.DBName)
 	if err != nil {
 		return err
 	}
@@ -217,6 +217,51 @@ func (ds *DataSource) getGroupNDVs(colGroups [][]*expression.Column) []property.
 			}
 		}
 	}
+	if ds.ctx.GetSessionVars().EnableExtendedStats && tbl.ExtendedStats != nil {
+		ndvs = appendGroupNDVsByExtendedStats(ndvs, colGroups, tbl.ExtendedStats)
+	}
+	return ndvs
+}
+
+// appendGroupNDVsByExtendedStats appends the NDVs of the column groups which have cardinality extended statistics
+// and whose NDVs aren't provided by the indexes.
+func appendGroupNDVsByExtendedStats(ndvs []property.GroupNDV, colGroups [][]*expression.Column, extStats *statistics.ExtendedStatsColl) []property.GroupNDV {
+	for _, g := range colGroups {
+		if len(g) < 2 || getGroupNDV4Cols(g, &property.StatsInfo{GroupNDVs: ndvs}) != nil {
+			continue
+		}
+		colIDs := make([]int64, 0, len(g))
+		for _, col := range g {
+			colIDs = append(colIDs, col.ID)
+		}
+		// The column IDs of the cardinality extended statistics are sorted.
+		sort.Slice(colIDs, func(i, j int) bool {
+			return colIDs[i] < colIDs[j]
+		})
+		for _, item := range extStats.Stats {
+			if item.Tp != ast.StatsTypeCardinality || item.ScalarVals <= 0 || len(item.ColIDs) != len(colIDs) {
+				continue
+			}
+			match := true
+			for i, id := range item.ColIDs {
+				if id != colIDs[i] {
+					match = false
+					break
+				}
+			}
+			if match {
+				cols := make([]int64, 0, len(g))
+				for _, col := range g {
+					cols = append(cols, col.UniqueID)
+				}
+				ndvs = append(ndvs, property.GroupNDV{
+					Cols: cols,
+					NDV:  item.ScalarVals,
+				})
+				break
+			}
+		}
+	}
 	return ndvs
 }
 
@@ -247,7 +292,8 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 }
 
 func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths []*util.AccessPath) *property.StatsInfo {
-	selectivity, nodes, err := ds.tableStats.HistColl.Selectivity(ds.ctx, conds, filledPaths)
+	selConds, coll := ds.substituteVirtualColumnsForStats(conds, ds.tableStats.HistColl)
+	selectivity, nodes, err := coll.Selectivity(ds.ctx, selConds, filledPaths)
 	if err != nil {
 		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
 		selectivity = SelectionFactor
@@ -277,7 +323,9 @@ func (ds *DataSource) derivePathStatsAndTryHeuristics() error {
 			path.IsSingleScan = true
 		} else {
 			ds.deriveIndexPathStats(path, ds.pushedDownConds, false)
-			path.IsSingleScan = ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+			// The entries of a global index may be stale, which are filtered by the partition IDs after the index scan,
+			// so the global index is never a single scan.
+			path.IsSingleScan = !path.Index.Global && ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
 		}
 		// Try some heuristic rules to select access path.
 		if len(path.Ranges) == 0 {
@@ -552,6 +600,40 @@ func (is *LogicalIndexScan) DeriveStats(childStats []*property.StatsInfo, selfSc
 	return is.stats, nil
 }
 
+// DeriveStats implements LogicalPlan DeriveStats interface.
+func (dg *TiKVDoubleGather) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	// The filters on the index side and the table side are assumed to be independent.
+	dg.stats = childStats[1]
+	if tableRowCount := dg.Source.tableStats.RowCount; tableRowCount > 0 {
+		dg.stats = childStats[1].Scale(childStats[0].RowCount / tableRowCount)
+	}
+	return dg.stats, nil
+}
+
+// DeriveStats implements LogicalPlan DeriveStats interface.
+func (g *TiKVIndexMergeGather) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	// The handles read by the partial scans may overlap, so the sum of their
+	// row counts is an upper bound of the rows to look up.
+	partialRowCount := 0.0
+	for _, stats := range childStats[:len(childStats)-1] {
+		partialRowCount += stats.RowCount
+	}
+	tableStats := childStats[len(childStats)-1]
+	g.stats = tableStats
+	if tableRowCount := g.Source.tableStats.RowCount; tableRowCount > 0 {
+		g.stats = tableStats.Scale(math.Min(partialRowCount/tableRowCount, 1))
+	}
+	return g.stats, nil
+}
+
+// DeriveStats implements LogicalPlan DeriveStats interface.
+func (pg *TiKVPointGet) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	pg.Source.initStats(nil)
+	// Each point range reads at most one row.
+	pg.stats = pg.Source.deriveStatsByFilter(pg.AccessConds, nil).ScaleByExpectCnt(float64(len(pg.Ranges)))
+	return pg.stats, nil
+}
+
 // getIndexMergeOrPath generates all possible IndexMergeOrPaths.
 func (ds *DataSource) generateIndexMergeOrPaths(filters []expression.Expression) error {
 	usedIndexCount := len(ds.possibleAccessPaths)
@@ -560,35 +642,9 @@ func (ds *DataSource) generateIndexMergeOrPaths(filters []expression.Expression)
 		if !ok || sf.FuncName.L != ast.LogicOr {
 			continue
 		}
-		var partialPaths = make([]*util.AccessPath, 0, usedIndexCount)
-		dnfItems := expression.FlattenDNFConditions(sf)
-		for _, item := range dnfItems {
-			cnfItems := expression.SplitCNFItems(item)
-			itemPaths := ds.accessPathsForConds(cnfItems, usedIndexCount)
-			if len(itemPaths) == 0 {
-				partialPaths = nil
-				break
-			}
-			partialPath, err := ds.buildIndexMergePartialPath(itemPaths)
-			if err != nil {
-				return err
-			}
-			if partialPath == nil {
-				partialPaths = nil
-				break
-			}
-			partialPaths = append(partialPaths, partialPath)
-		}
-		// If all of the partialPaths use the same index, we will not use the indexMerge.
-		singlePath := true
-		for i := len(partialPaths) - 1; i >= 1; i-- {
-			if partialPaths[i].Index != partialPaths[i-1].Index {
-				singlePath = false
-				break
-			}
-		}
-		if singlePath {
-			continue
+		partialPaths, err := ds.buildIndexMergePartialPaths(sf, usedIndexCount)
+		if err != nil {
+			return err
 		}
 		if len(partialPaths) > 1 {
 			possiblePath := ds.buildIndexMergeOrPath(filters, partialPaths, i)
@@ -617,6 +673,35 @@ func (ds *DataSource) generateIndexMergeOrPaths(filters []expression.Expression)
 	return nil
 }
 
+// buildIndexMergePartialPaths builds a partial path for each item of the DNF condition. It returns nil
+// if any of the items can not be read by a partial path, or all of the partial paths use the same index.
+func (ds *DataSource) buildIndexMergePartialPaths(sf *expression.ScalarFunction, usedIndexCount int) ([]*util.AccessPath, error) {
+	var partialPaths = make([]*util.AccessPath, 0, usedIndexCount)
+	dnfItems := expression.FlattenDNFConditions(sf)
+	for _, item := range dnfItems {
+		cnfItems := expression.SplitCNFItems(item)
+		itemPaths := ds.accessPathsForConds(cnfItems, usedIndexCount)
+		if len(itemPaths) == 0 {
+			return nil, nil
+		}
+		partialPath, err := ds.buildIndexMergePartialPath(itemPaths)
+		if err != nil {
+			return nil, err
+		}
+		if partialPath == nil {
+			return nil, nil
+		}
+		partialPaths = append(partialPaths, partialPath)
+	}
+	// If all of the partialPaths use the same index, we will not use the indexMerge.
+	for i := len(partialPaths) - 1; i >= 1; i-- {
+		if partialPaths[i].Index != partialPaths[i-1].Index {
+			return partialPaths, nil
+		}
+	}
+	return nil, nil
+}
+
 // isInIndexMergeHints checks whether current index or primary key is in IndexMerge hints.
 func (ds *DataSource) isInIndexMergeHints(name string) bool {
 	if len(ds.indexMergeHints) == 0 {
@@ -23,6 +23,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/memo"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tipb/go-tipb"
 )
 
 // TableDualImpl implementation of PhysicalTableDual.
@@ -123,6 +124,10 @@ func NewTableScanImpl(ts *plannercore.PhysicalTableScan, cols []*expression.Colu
 func (impl *TableScanImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
 	ts := impl.plan.(*plannercore.PhysicalTableScan)
 	width := impl.tblColHists.GetTableAvgRowSize(impl.plan.SCtx(), impl.tblCols, kv.TiKV, true)
+	if ts.StoreType == kv.TiFlash {
+		// TiFlash is a columnar storage, only the needed columns are scanned.
+		width = impl.tblColHists.GetTableAvgRowSize(impl.plan.SCtx(), ts.Schema().Columns, kv.TiFlash, true)
+	}
 	sessVars := ts.SCtx().GetSessionVars()
 	impl.cost = outCount * sessVars.GetScanFactor(ts.Table) * width
 	if ts.Desc {
@@ -131,6 +136,55 @@ func (impl *TableScanImpl) CalcCost(outCount float64, children ...memo.Implement
 	return impl.cost
 }
 
+// MPPTableReaderImpl is the implementation of PhysicalTableReader which reads
+// the data from TiFlash in the MPP mode.
+type MPPTableReaderImpl struct {
+	baseImpl
+}
+
+// NewMPPTableReaderImpl creates a new MPP table reader Implementation.
+func NewMPPTableReaderImpl(reader *plannercore.PhysicalTableReader) *MPPTableReaderImpl {
+	return &MPPTableReaderImpl{baseImpl{plan: reader}}
+}
+
+// GetCostLimit implements Implementation interface.
+func (impl *MPPTableReaderImpl) GetCostLimit(costLimit float64, children ...memo.Implementation) float64 {
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	if sessVars.IsMPPEnforced() {
+		return math.MaxFloat64
+	}
+	concurrency := sessVars.CopTiFlashConcurrencyFactor
+	if math.MaxFloat64/concurrency < costLimit {
+		return math.MaxFloat64
+	}
+	return costLimit * concurrency
+}
+
+// CalcCost calculates the cost of the MPP table reader Implementation.
+func (impl *MPPTableReaderImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	cost := children[0].GetCost() + children[0].GetPlan().Stats().RowCount*sessVars.GetNetworkFactor(nil)
+	// The MPP tasks are run in parallel by the TiFlash nodes.
+	impl.cost = cost / sessVars.CopTiFlashConcurrencyFactor
+	if sessVars.IsMPPEnforced() {
+		impl.cost = cost / 1000000000
+	}
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *MPPTableReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalTableReader)
+	childPlan := children[0].GetPlan()
+	// The results of the MPP tasks are passed through to TiDB.
+	sender := plannercore.PhysicalExchangeSender{
+		ExchangeType: tipb.ExchangeType_PassThrough,
+	}.Init(reader.SCtx(), childPlan.Stats())
+	sender.SetChildren(childPlan)
+	reader.SetChildren(sender)
+	return impl
+}
+
 // IndexReaderImpl is the implementation of PhysicalIndexReader.
 type IndexReaderImpl struct {
 	baseImpl
@@ -168,6 +222,186 @@ func NewIndexReaderImpl(reader *plannercore.PhysicalIndexReader, source *planner
 	}
 }
 
+// IndexLookUpReaderImpl is the implementation of PhysicalIndexLookUpReader.
+type IndexLookUpReaderImpl struct {
+	baseImpl
+	tblInfo     *model.TableInfo
+	tblColHists *statistics.HistColl
+	// schema is the schema of the TiKVDoubleGather, the schema of the reader
+	// may contain extra handle columns which should be pruned by a projection.
+	schema *expression.Schema
+}
+
+// NewIndexLookUpReaderImpl creates a new IndexLookUpReader Implementation.
+func NewIndexLookUpReaderImpl(reader *plannercore.PhysicalIndexLookUpReader, source *plannercore.DataSource, schema *expression.Schema) *IndexLookUpReaderImpl {
+	return &IndexLookUpReaderImpl{
+		baseImpl:    baseImpl{plan: reader},
+		tblInfo:     source.TableInfo(),
+		tblColHists: source.TblColHists,
+		schema:      schema,
+	}
+}
+
+// GetCostLimit implements Implementation interface.
+func (impl *IndexLookUpReaderImpl) GetCostLimit(costLimit float64, children ...memo.Implementation) float64 {
+	// The cost of the table side is scaled down according to the row count
+	// of the index side, so we can not limit the cost of the children here.
+	return math.MaxFloat64
+}
+
+// CalcCost implements Implementation interface.
+func (impl *IndexLookUpReaderImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	indexPlan, tablePlan := children[0].GetPlan(), children[1].GetPlan()
+	indexRows := indexPlan.Stats().RowCount
+	// The table side only reads the rows whose handles are read from the index side,
+	// so its cost is scaled by the ratio of the index rows to the table rows.
+	tableScan := tablePlan
+	for len(tableScan.Children()) > 0 {
+		tableScan = tableScan.Children()[0]
+	}
+	tableRows, tableCost := indexRows, children[1].GetCost()
+	if tableScanRows := tableScan.Stats().RowCount; tableScanRows > 0 {
+		ratio := math.Min(indexRows/tableScanRows, 1)
+		tableRows = tablePlan.Stats().RowCount * ratio
+		tableCost *= ratio
+	}
+	cost := children[0].GetCost() + tableCost
+	// Network cost of transferring rows of the index side and the table side to TiDB.
+	cost += indexRows * sessVars.GetNetworkFactor(impl.tblInfo) * impl.tblColHists.GetAvgRowSize(impl.plan.SCtx(), indexPlan.Schema().Columns, true, false)
+	cost += tableRows * sessVars.GetNetworkFactor(impl.tblInfo) * impl.tblColHists.GetAvgRowSize(impl.plan.SCtx(), tablePlan.Schema().Columns, false, false)
+	copIterWorkers := float64(sessVars.DistSQLScanConcurrency())
+	cost /= copIterWorkers
+	// Add cost of building table reader executors.
+	cost += indexRows * sessVars.CPUFactor
+	// Add cost of worker goroutines in index lookup.
+	numTblWorkers := float64(sessVars.IndexLookupConcurrency())
+	cost += (numTblWorkers + 1) * sessVars.ConcurrencyFactor
+	// Add cost of sorting the handles of each batch.
+	indexLookupSize := float64(sessVars.IndexLookupSize)
+	if batchSize := math.Min(indexLookupSize, indexRows); batchSize > 2 {
+		cost += (indexRows * math.Log2(batchSize) * sessVars.CPUFactor) / numTblWorkers
+	}
+	// Add cost of sorting the retrieved rows if the reader keeps order.
+	if !impl.plan.GetChildReqProps(0).IsEmpty() && indexRows > 0 {
+		if batchSize := math.Min(indexLookupSize*tableRows/indexRows, tableRows); batchSize > 2 {
+			cost += (tableRows * math.Log2(batchSize) * sessVars.CPUFactor) / numTblWorkers
+		}
+	}
+	impl.cost = cost
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *IndexLookUpReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalIndexLookUpReader)
+	reader.SetChildren(children[0].GetPlan(), children[1].GetPlan())
+	if reader.Schema().Len() > impl.schema.Len() {
+		// Add a projection to make sure not to output the extra handle columns.
+		proj := plannercore.PhysicalProjection{
+			Exprs: expression.Column2Exprs(impl.schema.Columns),
+		}.Init(reader.SCtx(), reader.Stats(), reader.SelectBlockOffset(), nil)
+		proj.SetSchema(impl.schema)
+		proj.SetChildren(reader)
+		impl.plan = proj
+	}
+	return impl
+}
+
+// IndexMergeReaderImpl is the implementation of PhysicalIndexMergeReader.
+type IndexMergeReaderImpl struct {
+	baseImpl
+	tblInfo     *model.TableInfo
+	tblColHists *statistics.HistColl
+}
+
+// NewIndexMergeReaderImpl creates a new IndexMergeReader Implementation.
+func NewIndexMergeReaderImpl(reader *plannercore.PhysicalIndexMergeReader, source *plannercore.DataSource) *IndexMergeReaderImpl {
+	return &IndexMergeReaderImpl{
+		baseImpl:    baseImpl{plan: reader},
+		tblInfo:     source.TableInfo(),
+		tblColHists: source.TblColHists,
+	}
+}
+
+// GetCostLimit implements Implementation interface.
+func (impl *IndexMergeReaderImpl) GetCostLimit(costLimit float64, children ...memo.Implementation) float64 {
+	// The cost of the table side is scaled down according to the row count
+	// of the partial sides, so we can not limit the cost of the children here.
+	return math.MaxFloat64
+}
+
+// CalcCost implements Implementation interface.
+func (impl *IndexMergeReaderImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	sessVars := impl.plan.SCtx().GetSessionVars()
+	netFactor := sessVars.GetNetworkFactor(impl.tblInfo)
+	partialChildren, tableChild := children[:len(children)-1], children[len(children)-1]
+	var cost, partialRows float64
+	for _, child := range partialChildren {
+		partialPlan := child.GetPlan()
+		rows := partialPlan.Stats().RowCount
+		partialRows += rows
+		// Network cost of transferring the handles read by the partial side to TiDB.
+		cost += child.GetCost() + rows*netFactor*impl.tblColHists.GetAvgRowSize(impl.plan.SCtx(), partialPlan.Schema().Columns, true, false)
+	}
+	// The table side only reads the rows whose handles are read from the partial sides,
+	// so its cost is scaled by the ratio of the partial rows to the table rows.
+	tablePlan := tableChild.GetPlan()
+	tableScan := tablePlan
+	for len(tableScan.Children()) > 0 {
+		tableScan = tableScan.Children()[0]
+	}
+	tableRows, tableCost := partialRows, tableChild.GetCost()
+	if tableScanRows := tableScan.Stats().RowCount; tableScanRows > 0 {
+		ratio := math.Min(partialRows/tableScanRows, 1)
+		tableRows = tablePlan.Stats().RowCount * ratio
+		tableCost *= ratio
+	}
+	cost += tableCost + tableRows*netFactor*impl.tblColHists.GetAvgRowSize(impl.plan.SCtx(), tablePlan.Schema().Columns, false, false)
+	cost /= float64(sessVars.DistSQLScanConcurrency())
+	// Add cost of deduplicating the handles read by the partial sides.
+	cost += partialRows * sessVars.CPUFactor
+	impl.cost = cost
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *IndexMergeReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalIndexMergeReader)
+	childPlans := make([]plannercore.PhysicalPlan, 0, len(children))
+	for _, child := range children {
+		childPlans = append(childPlans, child.GetPlan())
+	}
+	reader.SetChildren(childPlans...)
+	return impl
+}
+
+// PointGetImpl is the implementation of PointGetPlan and BatchPointGetPlan.
+type PointGetImpl struct {
+	baseImpl
+	cols []*expression.Column
+}
+
+// NewPointGetImpl creates a new PointGet Implementation.
+func NewPointGetImpl(plan plannercore.PhysicalPlan, cols []*expression.Column) *PointGetImpl {
+	return &PointGetImpl{
+		baseImpl: baseImpl{plan: plan},
+		cols:     cols,
+	}
+}
+
+// CalcCost implements Implementation interface.
+func (impl *PointGetImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	switch p := impl.plan.(type) {
+	case *plannercore.PointGetPlan:
+		impl.cost = p.GetCost(impl.cols)
+	case *plannercore.BatchPointGetPlan:
+		impl.cost = p.GetCost(impl.cols)
+	}
+	impl.plan.SetCost(impl.cost)
+	return impl.cost
+}
+
 // IndexScanImpl is the Implementation of PhysicalIndexScan.
 type IndexScanImpl struct {
 	baseImpl
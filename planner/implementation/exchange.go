@@ -0,0 +1,46 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package implementation
+
+import (
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/planner/memo"
+)
+
+// ExchangeImpl is the implementation of a PhysicalExchangeReceiver and the
+// PhysicalExchangeSender below it, which exchange the data between the MPP tasks.
+type ExchangeImpl struct {
+	baseImpl
+	sender *plannercore.PhysicalExchangeSender
+}
+
+// NewExchangeImpl creates a new ExchangeImpl.
+func NewExchangeImpl(receiver *plannercore.PhysicalExchangeReceiver, sender *plannercore.PhysicalExchangeSender) *ExchangeImpl {
+	receiver.SetChildren(sender)
+	return &ExchangeImpl{baseImpl: baseImpl{plan: receiver}, sender: sender}
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (impl *ExchangeImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	childPlan := children[0].GetPlan()
+	impl.cost = childPlan.Stats().RowCount*impl.plan.SCtx().GetSessionVars().GetNetworkFactor(nil) + children[0].GetCost()
+	return impl.cost
+}
+
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *ExchangeImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	impl.sender.SetChildren(children[0].GetPlan())
+	return impl
+}
@@ -78,6 +78,22 @@ func NewTiKVSelectionImpl(sel *plannercore.PhysicalSelection) *TiKVSelectionImpl
 	return &TiKVSelectionImpl{baseImpl{plan: sel}}
 }
 
+// TiFlashSelectionImpl is the implementation of PhysicalSelection in TiFlash layer.
+type TiFlashSelectionImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (sel *TiFlashSelectionImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	sel.cost = children[0].GetPlan().Stats().RowCount*sel.plan.SCtx().GetSessionVars().CopCPUFactor + children[0].GetCost()
+	return sel.cost
+}
+
+// NewTiFlashSelectionImpl creates a new TiFlashSelectionImpl.
+func NewTiFlashSelectionImpl(sel *plannercore.PhysicalSelection) *TiFlashSelectionImpl {
+	return &TiFlashSelectionImpl{baseImpl{plan: sel}}
+}
+
 // TiDBHashAggImpl is the implementation of PhysicalHashAgg in TiDB layer.
 type TiDBHashAggImpl struct {
 	baseImpl
@@ -121,6 +137,24 @@ func NewTiKVHashAggImpl(agg *plannercore.PhysicalHashAgg) *TiKVHashAggImpl {
 	return &TiKVHashAggImpl{baseImpl{plan: agg}}
 }
 
+// TiFlashHashAggImpl is the implementation of PhysicalHashAgg in TiFlash layer.
+type TiFlashHashAggImpl struct {
+	baseImpl
+}
+
+// CalcCost implements Implementation CalcCost interface.
+func (agg *TiFlashHashAggImpl) CalcCost(outCount float64, children ...memo.Implementation) float64 {
+	hashAgg := agg.plan.(*plannercore.PhysicalHashAgg)
+	selfCost := hashAgg.GetCost(children[0].GetPlan().Stats().RowCount, false, true)
+	agg.cost = selfCost + children[0].GetCost()
+	return agg.cost
+}
+
+// NewTiFlashHashAggImpl creates a new TiFlashHashAggImpl.
+func NewTiFlashHashAggImpl(agg *plannercore.PhysicalHashAgg) *TiFlashHashAggImpl {
+	return &TiFlashHashAggImpl{baseImpl{plan: agg}}
+}
+
 // LimitImpl is the implementation of PhysicalLimit. Since PhysicalLimit on different
 // engines have the same behavior, and we don't calculate the cost of `Limit`, we only
 // have one Implementation for it.
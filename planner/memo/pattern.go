@@ -68,6 +68,14 @@ const (
 	OperandShow
 	// OperandWindow is the operand for window function.
 	OperandWindow
+	// OperandTiKVDoubleGather is the operand for TiKVDoubleGather.
+	OperandTiKVDoubleGather
+	// OperandTiKVPointGet is the operand for TiKVPointGet.
+	OperandTiKVPointGet
+	// OperandTiKVIndexMergeGather is the operand for TiKVIndexMergeGather.
+	OperandTiKVIndexMergeGather
+	// OperandTiFlashMPPGather is the operand for TiFlashMPPGather.
+	OperandTiFlashMPPGather
 	// OperandUnsupported is the operand for unsupported operators.
 	OperandUnsupported
 )
@@ -105,6 +113,14 @@ func GetOperand(p plannercore.LogicalPlan) Operand {
 		return OperandLimit
 	case *plannercore.TiKVSingleGather:
 		return OperandTiKVSingleGather
+	case *plannercore.TiKVDoubleGather:
+		return OperandTiKVDoubleGather
+	case *plannercore.TiKVPointGet:
+		return OperandTiKVPointGet
+	case *plannercore.TiKVIndexMergeGather:
+		return OperandTiKVIndexMergeGather
+	case *plannercore.TiFlashMPPGather:
+		return OperandTiFlashMPPGather
 	case *plannercore.LogicalTableScan:
 		return OperandTableScan
 	case *plannercore.LogicalMemTable:
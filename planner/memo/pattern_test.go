@@ -36,6 +36,10 @@ func TestGetOperand(t *testing.T) {
 	require.Equal(t, OperandTopN, GetOperand(&plannercore.LogicalTopN{}))
 	require.Equal(t, OperandLock, GetOperand(&plannercore.LogicalLock{}))
 	require.Equal(t, OperandLimit, GetOperand(&plannercore.LogicalLimit{}))
+	require.Equal(t, OperandTiKVDoubleGather, GetOperand(&plannercore.TiKVDoubleGather{}))
+	require.Equal(t, OperandTiKVPointGet, GetOperand(&plannercore.TiKVPointGet{}))
+	require.Equal(t, OperandTiKVIndexMergeGather, GetOperand(&plannercore.TiKVIndexMergeGather{}))
+	require.Equal(t, OperandTiFlashMPPGather, GetOperand(&plannercore.TiFlashMPPGather{}))
 }
 
 func TestOperandMatch(t *testing.T) {
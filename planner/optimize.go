@@ -107,6 +107,14 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}()
 	}
 
+	// The hypothetical indexes are only visible when explaining a statement, so they are never used for execution.
+	if explain, ok := node.(*ast.ExplainStmt); ok && !explain.Analyze && len(sessVars.HypoIndexes) > 0 {
+		is = infoschema.AttachHypoIndexes(is, sessVars.HypoIndexes)
+		// The schema version is kept with the hypothetical indexes, the plan of EXPLAIN EXECUTE mustn't be
+		// taken from or put into the plan cache.
+		sessVars.StmtCtx.SkipPlanCache = true
+	}
+
 	tableHints := hint.ExtractTableHintsFromStmtNode(node, sctx)
 	originStmtHints, originStmtHintsOffs, warns := handleStmtHints(tableHints)
 	sessVars.StmtCtx.StmtHints = originStmtHints
@@ -121,6 +129,19 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}
 	}
 
+	if sessVars.EnableNonPreparedPlanCache {
+		p, names, ok, err := getPlanFromNonPreparedPlanCache(ctx, sctx, node, is)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			if !useMaxTS(sctx, p) {
+				sctx.PrepareTSFuture(ctx)
+			}
+			return p, names, nil
+		}
+	}
+
 	if _, isolationReadContainTiKV := sessVars.IsolationReadEngines[kv.TiKV]; isolationReadContainTiKV {
 		var fp plannercore.Plan
 		if fpv, ok := sctx.Value(plannercore.PointPlanKey).(plannercore.PointPlanVal); ok {
@@ -254,6 +275,63 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	return bestPlan, names, nil
 }
 
+// getPlanFromNonPreparedPlanCache tries to get the plan of the text query from the plan cache. The literals of the
+// query are replaced by parameters, and the parameterized query is planned as a prepared statement, so the queries
+// which only differ in the literals can share the cached plans.
+func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, bool, error) {
+	sessVars := sctx.GetSessionVars()
+	stmtCtx := sessVars.StmtCtx
+	if stmtCtx.InPreparedPlanBuilding || sessVars.InRestrictedSQL || !plannercore.PreparedPlanCacheEnabled() ||
+		sctx.Value(plannercore.PointPlanKey) != nil || len(sessVars.HypoIndexes) > 0 {
+		return nil, nil, false, nil
+	}
+	// The stale reads are not supported.
+	if sessVars.SnapshotTS != 0 || stmtCtx.IsStaleness || sessVars.TxnReadTS.PeakTxnReadTS() > 0 ||
+		(sessVars.InTxn() && sessVars.TxnCtx.IsStaleness) {
+		return nil, nil, false, nil
+	}
+	stmtNode, ok := node.(ast.StmtNode)
+	if !ok || !plannercore.NonPreparedPlanCacheable(sctx, stmtNode, is) {
+		return nil, nil, false, nil
+	}
+	paramSQL, params, err := plannercore.ParameterizeAST(stmtNode)
+	if err != nil {
+		logutil.BgLogger().Debug("parameterize the statement failed", zap.Error(err))
+		return nil, nil, false, nil
+	}
+	// The unqualified names in the statement are resolved in the current database.
+	stmtKey := sessVars.CurrentDB + "." + paramSQL
+	preparedStmt, ok := sessVars.GetNonPreparedPlanCacheStmt(stmtKey).(*plannercore.CachedPrepareStmt)
+	if !ok {
+		preparedStmt, err = plannercore.NewNonPreparedPlanCacheStmt(ctx, sctx, paramSQL, len(params), is)
+		if err != nil {
+			logutil.BgLogger().Debug("prepare the parameterized statement failed", zap.String("sql", paramSQL), zap.Error(err))
+			return nil, nil, false, nil
+		}
+		_, preparedStmt.NormalizedSQL4PC, preparedStmt.NormalizedSQL4PCHash, err = ExtractSelectAndNormalizeDigest(preparedStmt.PreparedAst.Stmt, sessVars.CurrentDB)
+		if err != nil {
+			preparedStmt.NormalizedSQL4PC, preparedStmt.NormalizedSQL4PCHash = "", ""
+		}
+		sessVars.AddNonPreparedPlanCacheStmt(stmtKey, preparedStmt)
+	}
+	p, names, err := plannercore.GetPlanFromNonPreparedPlanCache(ctx, sctx, is, preparedStmt, params)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return p, names, true, nil
+}
+
+// OptimizeForCost does optimization without the fast plans and the SQL bindings, and returns the best plan with
+// its estimated cost, so the costs of the same node under different InfoSchemas can be compared with each other.
+// It's used by the what-if analysis, such as the index advisor. The node must be prepared first.
+func OptimizeForCost(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, float64, error) {
+	p, _, cost, err := optimize(ctx, sctx, node, is)
+	if err != nil {
+		return nil, 0, err
+	}
+	return p, cost, nil
+}
+
 func allowInReadOnlyMode(sctx sessionctx.Context, node ast.Node) (bool, error) {
 	pm := privilege.GetPrivilegeManager(sctx)
 	if pm == nil {
@@ -277,7 +355,9 @@ func allowInReadOnlyMode(sctx sessionctx.Context, node ast.Node) (bool, error) {
 		*ast.DropBindingStmt,
 		*ast.PrepareStmt,
 		*ast.BeginStmt,
-		*ast.RollbackStmt:
+		*ast.RollbackStmt,
+		*ast.SavepointStmt,
+		*ast.ReleaseSavepointStmt:
 		return true, nil
 	case *ast.CommitStmt:
 		txn, err := sctx.Txn(true)
@@ -1723,7 +1723,7 @@ func (cc *clientConn) handleLoadStats(ctx context.Context, loadStatsInfo *execut
 }
 
 // handleIndexAdvise does the index advise work and returns the advise result for index.
-func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo) error {
+func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo, status uint16) error {
 	if cc.capability&mysql.ClientLocalFiles == 0 {
 		return errNotAllowedCommand
 	}
@@ -1742,9 +1742,8 @@ func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *ex
 	if err := indexAdviseInfo.GetIndexAdvice(ctx, data); err != nil {
 		return err
 	}
-
-	// TODO: Write the rss []ResultSet. It will be done in another PR.
-	return nil
+	_, err = cc.writeResultset(ctx, &tidbResultSet{recordSet: indexAdviseInfo.Result}, false, status, 0)
+	return err
 }
 
 func (cc *clientConn) handlePlanReplayerLoad(ctx context.Context, planReplayerLoadInfo *executor.PlanReplayerLoadInfo) error {
@@ -2032,11 +2031,9 @@ func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bo
 
 	indexAdvise := cc.ctx.Value(executor.IndexAdviseVarKey)
 	if indexAdvise != nil {
-		handled = true
 		defer cc.ctx.SetValue(executor.IndexAdviseVarKey, nil)
-		if err := cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo)); err != nil {
-			return handled, err
-		}
+		// The index advice is returned as a result set instead of an OK packet.
+		return true, cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo), status)
 	}
 
 	planReplayerLoad := cc.ctx.Value(executor.PlanReplayerLoadVarKey)
@@ -376,6 +376,44 @@ const (
 		column_ids TEXT(19372),
 		PRIMARY KEY (table_id) CLUSTERED
 	);`
+	// CreateTTLJobHistoryTable stores the progress and the result of the jobs which delete the expired rows of the TTL tables.
+	CreateTTLJobHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_ttl_job_history (
+		job_id VARCHAR(64) NOT NULL,
+		table_id BIGINT(64) NOT NULL,
+		parent_table_id BIGINT(64) NOT NULL,
+		table_schema VARCHAR(64) NOT NULL,
+		table_name VARCHAR(64) NOT NULL,
+		partition_name VARCHAR(64) DEFAULT NULL,
+		create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		finish_time TIMESTAMP NULL DEFAULT NULL,
+		ttl_expire TIMESTAMP NULL DEFAULT NULL,
+		range_count BIGINT(64) NOT NULL DEFAULT 0,
+		finished_range_count BIGINT(64) NOT NULL DEFAULT 0,
+		scanned_rows BIGINT(64) NOT NULL DEFAULT 0,
+		deleted_rows BIGINT(64) NOT NULL DEFAULT 0,
+		error_delete_rows BIGINT(64) NOT NULL DEFAULT 0,
+		status ENUM('running','finished','error','cancelled') NOT NULL DEFAULT 'running',
+		error TEXT DEFAULT NULL,
+		PRIMARY KEY (job_id),
+		KEY idx_table_create_time (table_id, create_time)
+	);`
+	// CreateStatsHistoryTable stores the historical stats of the tables. The stats are dumped to json, compressed
+	// and split into several blocks, seq_no is the sequence number of the block.
+	CreateStatsHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.stats_history (
+		table_id BIGINT(64) NOT NULL,
+		stats_data LONGBLOB NOT NULL,
+		seq_no BIGINT(64) NOT NULL,
+		version BIGINT(64) NOT NULL,
+		create_time DATETIME(6) NOT NULL,
+		UNIQUE KEY table_version_seq (table_id, version, seq_no),
+		KEY table_create_time (table_id, create_time, seq_no)
+	);`
+	// CreateStatsTableLockedTable stores the tables whose stats are locked, auto analyze skips these tables.
+	CreateStatsTableLockedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_table_locked (
+		table_id BIGINT(64) NOT NULL,
+		version BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY (table_id)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -557,11 +595,15 @@ const (
 	version81 = 81
 	// version82 adds the mysql.analyze_options table
 	version82 = 82
+	// version83 adds the mysql.tidb_ttl_job_history table
+	version83 = 83
+	// version84 adds the mysql.stats_history and mysql.stats_table_locked tables
+	version84 = 84
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version82
+var currentBootstrapVersion int64 = version84
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -647,6 +689,8 @@ var (
 		upgradeToVer80,
 		upgradeToVer81,
 		upgradeToVer82,
+		upgradeToVer83,
+		upgradeToVer84,
 	}
 )
 
@@ -1703,6 +1747,21 @@ func upgradeToVer82(s Session, ver int64) {
 	doReentrantDDL(s, CreateAnalyzeOptionsTable)
 }
 
+func upgradeToVer83(s Session, ver int64) {
+	if ver >= version83 {
+		return
+	}
+	doReentrantDDL(s, CreateTTLJobHistoryTable)
+}
+
+func upgradeToVer84(s Session, ver int64) {
+	if ver >= version84 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsHistoryTable)
+	doReentrantDDL(s, CreateStatsTableLockedTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1789,6 +1848,12 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateTableCacheMetaTable)
 	// Create analyze_options table.
 	mustExecute(s, CreateAnalyzeOptionsTable)
+	// Create tidb_ttl_job_history table.
+	mustExecute(s, CreateTTLJobHistoryTable)
+	// Create stats_history table.
+	mustExecute(s, CreateStatsHistoryTable)
+	// Create stats_table_locked table.
+	mustExecute(s, CreateStatsTableLockedTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1830,13 +1895,6 @@ func doDMLWorks(s Session) {
 					vVal = string(variable.Dynamic)
 				}
 			}
-			if v.Name == variable.TiDBEnableChangeMultiSchema {
-				vVal = variable.Off
-				if flag.Lookup("test.v") != nil || flag.Lookup("check.v") != nil {
-					// enable change multi schema in test case for compatibility with old cases.
-					vVal = variable.On
-				}
-			}
 			if v.Name == variable.TiDBEnableAsyncCommit && config.GetGlobalConfig().Store == "tikv" {
 				vVal = variable.On
 			}
@@ -982,7 +982,7 @@ func (s *session) checkTxnAborted(stmt sqlexec.Statement) error {
 	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.CommitStmt); ok {
 		return nil
 	}
-	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok {
+	if x, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok && x.SavepointName == "" {
 		return nil
 	}
 	return err
@@ -2710,6 +2710,8 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 
 	dom.PlanReplayerLoop()
 
+	dom.StartTTLJobManager()
+
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
@@ -127,6 +127,54 @@ func (txn *LazyTxn) cleanupStmtBuf() {
 	txn.mu.TxnInfo.EntriesSize = uint64(txn.Transaction.Size())
 }
 
+// AddSavepoint creates a savepoint in the transaction, and returns its handle.
+func (txn *LazyTxn) AddSavepoint() (kv.SavepointHandle, error) {
+	spTxn, ok := txn.Transaction.(kv.SavepointTransaction)
+	if !ok {
+		return kv.InvalidSavepointHandle, errors.Trace(kv.ErrSavepointNotSupported)
+	}
+	return spTxn.AddSavepoint(), nil
+}
+
+// RollbackToSavepoint discards the changes after the savepoint.
+// The pessimistic locks acquired after the savepoint are kept until the transaction ends, the same as InnoDB.
+func (txn *LazyTxn) RollbackToSavepoint(ctx context.Context, h kv.SavepointHandle) error {
+	spTxn, ok := txn.Transaction.(kv.SavepointTransaction)
+	if !ok {
+		return errors.Trace(kv.ErrSavepointNotSupported)
+	}
+	// The reverted changes are published to the transaction directly, they can't be discarded with the statement.
+	txn.flushStmtBuf()
+	err := spTxn.RollbackToSavepoint(ctx, h)
+	txn.initStmtBuf()
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.TxnInfo.EntriesCount = uint64(txn.Transaction.Len())
+	txn.mu.TxnInfo.EntriesSize = uint64(txn.Transaction.Size())
+	return err
+}
+
+// ReleaseSavepoint removes the savepoint and the later ones, and keeps the changes after them.
+func (txn *LazyTxn) ReleaseSavepoint(h kv.SavepointHandle) error {
+	spTxn, ok := txn.Transaction.(kv.SavepointTransaction)
+	if !ok {
+		return errors.Trace(kv.ErrSavepointNotSupported)
+	}
+	spTxn.ReleaseSavepoint(h)
+	return nil
+}
+
+// DeleteSavepoint removes only the savepoint, the later ones are kept.
+func (txn *LazyTxn) DeleteSavepoint(h kv.SavepointHandle) error {
+	spTxn, ok := txn.Transaction.(kv.SavepointTransaction)
+	if !ok {
+		return errors.Trace(kv.ErrSavepointNotSupported)
+	}
+	spTxn.DeleteSavepoint(h)
+	return nil
+}
+
 // resetTxnInfo resets the transaction info.
 // Note: call it under lock!
 func (txn *LazyTxn) resetTxnInfo(
@@ -65,6 +65,10 @@ type StatementContext struct {
 	// Set the following variables before execution
 	StmtHints
 
+	// MultiSchemaInfo collects the sub-jobs of an ALTER TABLE statement with multiple specifications,
+	// they are submitted as one multi-schema change job after all the specifications are checked.
+	MultiSchemaInfo *model.MultiSchemaInfo
+
 	// IsDDLJobInQueue is used to mark whether the DDL job is put into the queue.
 	// If IsDDLJobInQueue is true, it means the DDL job is in the queue of storage, and it can be handled by the DDL worker.
 	IsDDLJobInQueue        bool
@@ -90,6 +94,7 @@ type StatementContext struct {
 	AllowInvalidDate       bool
 	IgnoreNoPartition      bool
 	SkipPlanCache          bool
+	InPreparedPlanBuilding bool
 	IgnoreExplainIDSuffix  bool
 	SkipUTF8Check          bool
 	SkipASCIICheck         bool
@@ -98,6 +103,8 @@ type StatementContext struct {
 	// or is affected by the tidb_read_staleness session variable, then the statement will be makred as isStaleness
 	// in stmtCtx
 	IsStaleness bool
+	// InNonPreparedPlanBuilding indicates the plan is built for the non-prepared plan cache.
+	InNonPreparedPlanBuilding bool
 	// mu struct holds variables that change during execution.
 	mu struct {
 		sync.Mutex
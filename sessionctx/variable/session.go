@@ -46,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/kvcache"
 	utilMath "github.com/pingcap/tidb/util/math"
 	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/stringutil"
@@ -183,6 +184,98 @@ type TransactionContext struct {
 
 	// CachedTables is not nil if the transaction write on cached table.
 	CachedTables map[int64]interface{}
+
+	// Savepoints contains the savepoints of the transaction in the order they are created.
+	Savepoints []SavepointRecord
+}
+
+// SavepointRecord is a savepoint of the transaction.
+type SavepointRecord struct {
+	// Name is the lower case name of the savepoint.
+	Name string
+	// Handle is the handle of the savepoint in the transaction.
+	Handle kv.SavepointHandle
+	// TableDeltaMap is the copy of TransactionContext.TableDeltaMap when the savepoint is created.
+	TableDeltaMap map[int64]TableDelta
+	// pessimisticLockCache is the copy of TransactionContext.pessimisticLockCache when the savepoint is created.
+	pessimisticLockCache map[string][]byte
+}
+
+// AddSavepoint adds a savepoint to the transaction. The old savepoint with the same name is removed.
+func (tc *TransactionContext) AddSavepoint(name string, h kv.SavepointHandle) {
+	name = strings.ToLower(name)
+	for i, sp := range tc.Savepoints {
+		if sp.Name == name {
+			tc.Savepoints = append(tc.Savepoints[:i], tc.Savepoints[i+1:]...)
+			break
+		}
+	}
+	tc.tdmLock.Lock()
+	deltaMap := cloneTableDeltaMap(tc.TableDeltaMap)
+	tc.tdmLock.Unlock()
+	tc.Savepoints = append(tc.Savepoints, SavepointRecord{
+		Name:                 name,
+		Handle:               h,
+		TableDeltaMap:        deltaMap,
+		pessimisticLockCache: clonePessimisticLockCache(tc.pessimisticLockCache),
+	})
+}
+
+// GetSavepoint returns the index and the record of the savepoint, or -1 and nil if it doesn't exist.
+func (tc *TransactionContext) GetSavepoint(name string) (int, *SavepointRecord) {
+	name = strings.ToLower(name)
+	for i := range tc.Savepoints {
+		if tc.Savepoints[i].Name == name {
+			return i, &tc.Savepoints[i]
+		}
+	}
+	return -1, nil
+}
+
+// RollbackToSavepoint restores the transaction context to the savepoint at idx, and removes the later savepoints.
+// The savepoint itself is kept. The keys locked after the savepoint are still locked, but their cached values are
+// dropped, then they are read from the store again.
+func (tc *TransactionContext) RollbackToSavepoint(idx int) {
+	sp := &tc.Savepoints[idx]
+	tc.tdmLock.Lock()
+	tc.TableDeltaMap = cloneTableDeltaMap(sp.TableDeltaMap)
+	tc.tdmLock.Unlock()
+	tc.pessimisticLockCache = clonePessimisticLockCache(sp.pessimisticLockCache)
+	tc.Savepoints = tc.Savepoints[:idx+1]
+}
+
+// ReleaseSavepoint removes the savepoint at idx and the later savepoints.
+func (tc *TransactionContext) ReleaseSavepoint(idx int) {
+	tc.Savepoints = tc.Savepoints[:idx]
+}
+
+func clonePessimisticLockCache(m map[string][]byte) map[string][]byte {
+	if m == nil {
+		return nil
+	}
+	cloned := make(map[string][]byte, len(m))
+	for key, val := range m {
+		cloned[key] = val
+	}
+	return cloned
+}
+
+func cloneTableDeltaMap(m map[int64]TableDelta) map[int64]TableDelta {
+	if m == nil {
+		return nil
+	}
+	cloned := make(map[int64]TableDelta, len(m))
+	for id, delta := range m {
+		if delta.ColSize != nil {
+			colSize := make(map[int64]int64, len(delta.ColSize))
+			for colID, size := range delta.ColSize {
+				colSize[colID] = size
+			}
+			delta.ColSize = colSize
+		}
+		cloned[id] = delta
+	}
+	return cloned
 }
 
 // GetShard returns the shard prefix for the next `count` rowids.
@@ -491,6 +584,9 @@ type SessionVars struct {
 	// PreparedParams params for prepared statements
 	PreparedParams    PreparedParams
 	LastUpdateTime4PC types.Time
+	// nonPreparedPlanCacheStmts stores the statements generated by the non-prepared plan cache, whose literals are
+	// replaced by parameters. They're keyed by the parameterized SQL.
+	nonPreparedPlanCacheStmts *kvcache.SimpleLRUCache
 
 	// ActiveRoles stores active roles for current user
 	ActiveRoles []*auth.RoleIdentity
@@ -746,6 +842,9 @@ type SessionVars struct {
 	// EnablePlacementChecks indicates whether a user can check validation of placement.
 	EnablePlacementChecks bool
 
+	// ForeignKeyChecks indicates whether the foreign key constraints are enforced on DML.
+	ForeignKeyChecks bool
+
 	// WaitSplitRegionFinish defines the split region behaviour is sync or async.
 	WaitSplitRegionFinish bool
 
@@ -771,6 +870,9 @@ type SessionVars struct {
 	// to use the greedy join reorder algorithm.
 	TiDBOptJoinReorderThreshold int
 
+	// EnableOuterJoinReorder indicates whether the join reorder can reorder the joins across outer joins.
+	EnableOuterJoinReorder bool
+
 	// SlowQueryFile indicates which slow query log file for SLOW_QUERY table to parse.
 	SlowQueryFile string
 
@@ -941,12 +1043,18 @@ type SessionVars struct {
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
+	// EnableNonPreparedPlanCache indicates whether to cache the plans of the text queries.
+	EnableNonPreparedPlanCache bool
+
 	// TrackAggregateMemoryUsage indicates whether to track the memory usage of aggregate function.
 	TrackAggregateMemoryUsage bool
 
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition
 	TiDBEnableExchangePartition bool
 
+	// EnableGlobalIndex indicates whether to create global indexes on partitioned tables.
+	EnableGlobalIndex bool
+
 	// AllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	AllowFallbackToTiKV map[kv.StoreType]struct{}
@@ -974,6 +1082,10 @@ type SessionVars struct {
 	// TemporaryTableData stores committed kv values for temporary table for current session.
 	TemporaryTableData TemporaryTableData
 
+	// HypoIndexes stores the hypothetical indexes created in the session, indexed by the table ID.
+	// They are only visible to the optimizer when explaining statements.
+	HypoIndexes map[int64][]*model.IndexInfo
+
 	// MPPStoreLastFailTime records the lastest fail time that a TiFlash store failed.
 	MPPStoreLastFailTime map[string]time.Time
 
@@ -1197,6 +1309,7 @@ func NewSessionVars() *SessionVars {
 		EnableVectorizedExpression:  DefEnableVectorizedExpression,
 		CommandValue:                uint32(mysql.ComSleep),
 		TiDBOptJoinReorderThreshold: DefTiDBOptJoinReorderThreshold,
+		EnableOuterJoinReorder:      DefTiDBEnableOuterJoinReorder,
 		SlowQueryFile:               config.GetGlobalConfig().Log.SlowQueryFile,
 		WaitSplitRegionFinish:       DefTiDBWaitSplitRegionFinish,
 		WaitSplitRegionTimeout:      DefWaitSplitRegionTimeout,
@@ -1233,6 +1346,7 @@ func NewSessionVars() *SessionVars {
 		GuaranteeLinearizability:    DefTiDBGuaranteeLinearizability,
 		AnalyzeVersion:              DefTiDBAnalyzeVersion,
 		EnableIndexMergeJoin:        DefTiDBEnableIndexMergeJoin,
+		EnableNonPreparedPlanCache:  DefTiDBEnableNonPreparedPlanCache,
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 		TMPTableSize:                DefTiDBTmpTableMaxSize,
@@ -1610,6 +1724,36 @@ func (s *SessionVars) WithdrawAllPreparedStmt() {
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
 
+// nonPreparedPlanCacheStmtCapacity is the max number of the statements generated by the non-prepared plan cache
+// in a session.
+const nonPreparedPlanCacheStmtCapacity = 100
+
+// nonPreparedPlanCacheStmtKey is the key of SessionVars.nonPreparedPlanCacheStmts.
+type nonPreparedPlanCacheStmtKey string
+
+// Hash implements kvcache.Key interface.
+func (k nonPreparedPlanCacheStmtKey) Hash() []byte {
+	return []byte(k)
+}
+
+// GetNonPreparedPlanCacheStmt gets the statement generated by the non-prepared plan cache from the parameterized SQL.
+func (s *SessionVars) GetNonPreparedPlanCacheStmt(sql string) interface{} {
+	if s.nonPreparedPlanCacheStmts == nil {
+		return nil
+	}
+	stmt, _ := s.nonPreparedPlanCacheStmts.Get(nonPreparedPlanCacheStmtKey(sql))
+	return stmt
+}
+
+// AddNonPreparedPlanCacheStmt adds the statement generated by the non-prepared plan cache to current session.
+// The least recently used statements are removed if there are too many statements.
+func (s *SessionVars) AddNonPreparedPlanCacheStmt(sql string, stmt interface{}) {
+	if s.nonPreparedPlanCacheStmts == nil {
+		s.nonPreparedPlanCacheStmts = kvcache.NewSimpleLRUCache(nonPreparedPlanCacheStmtCapacity, 0, 0)
+	}
+	s.nonPreparedPlanCacheStmts.Put(nonPreparedPlanCacheStmtKey(sql), stmt)
+}
+
 // SetStmtVar sets the value of a system variable temporarily
 func (s *SessionVars) SetStmtVar(name string, val string) error {
 	s.stmtVars[name] = val
@@ -405,12 +405,18 @@ func TestForeignKeyChecks(t *testing.T) {
 	sv := GetSysVar(ForeignKeyChecks)
 	vars := NewSessionVars()
 
+	require.Equal(t, Off, sv.Value)
 	val, err := sv.Validate(vars, "on", ScopeSession)
 	require.NoError(t, err)
-	require.Equal(t, "OFF", val) // warns and refuses to set ON.
+	require.Equal(t, On, val)
+	require.NoError(t, sv.SetSessionFromHook(vars, val))
+	require.True(t, vars.ForeignKeyChecks)
 
-	warn := vars.StmtCtx.GetWarnings()[0].Err
-	require.Equal(t, "[variable:8047]variable 'foreign_key_checks' does not yet support value: on", warn.Error())
+	val, err = sv.Validate(vars, "0", ScopeSession)
+	require.NoError(t, err)
+	require.Equal(t, Off, val)
+	require.NoError(t, sv.SetSessionFromHook(vars, val))
+	require.False(t, vars.ForeignKeyChecks)
 
 }
 
@@ -399,6 +399,10 @@ const (
 	// tidb_ddl_error_count_limit defines the count of ddl error limit.
 	TiDBDDLErrorCountLimit = "tidb_ddl_error_count_limit"
 
+	// tidb_ddl_job_worker_cnt defines the count of ddl job queues for each kind of jobs.
+	// Jobs of independent tables are put into different queues and run concurrently.
+	TiDBDDLJobWorkerCount = "tidb_ddl_job_worker_cnt"
+
 	// tidb_ddl_reorg_priority defines the operations priority of adding indices.
 	// It can be: PRIORITY_LOW, PRIORITY_NORMAL, PRIORITY_HIGH
 	TiDBDDLReorgPriority = "tidb_ddl_reorg_priority"
@@ -424,6 +428,9 @@ const (
 	// tidb_scatter_region will scatter the regions for DDLs when it is ON.
 	TiDBScatterRegion = "tidb_scatter_region"
 
+	// TiDBDDLEnableFastReorg indicates whether to add indexes by ingesting the sorted index data when it is ON.
+	TiDBDDLEnableFastReorg = "tidb_ddl_enable_fast_reorg"
+
 	// TiDBWaitSplitRegionFinish defines the split region behaviour is sync or async.
 	TiDBWaitSplitRegionFinish = "tidb_wait_split_region_finish"
 
@@ -454,6 +461,9 @@ const (
 	// we'll choose a rather time consuming algorithm to calculate the join order.
 	TiDBOptJoinReorderThreshold = "tidb_opt_join_reorder_threshold"
 
+	// TiDBEnableOuterJoinReorder indicates whether the join reorder can reorder the joins across outer joins.
+	TiDBEnableOuterJoinReorder = "tidb_enable_outer_join_reorder"
+
 	// SlowQueryFile indicates which slow query log file for SLOW_QUERY table to parse.
 	TiDBSlowQueryFile = "tidb_slow_query_file"
 
@@ -569,6 +579,10 @@ const (
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition.
 	TiDBEnableExchangePartition = "tidb_enable_exchange_partition"
 
+	// TiDBEnableGlobalIndex indicates whether to create global indexes on partitioned tables
+	// when the unique keys do not include all the partitioning columns.
+	TiDBEnableGlobalIndex = "tidb_enable_global_index"
+
 	// TiDBAllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	TiDBAllowFallbackToTiKV = "tidb_allow_fallback_to_tikv"
@@ -642,6 +656,25 @@ const (
 	TiDBDisableColumnTrackingTime = "tidb_disable_column_tracking_time"
 	// TiDBStatsLoadPseudoTimeout indicates whether to fallback to pseudo stats after load timeout.
 	TiDBStatsLoadPseudoTimeout = "tidb_stats_load_pseudo_timeout"
+	// TiDBTTLJobEnable indicates whether to run the background jobs which delete the expired rows of the TTL tables.
+	TiDBTTLJobEnable = "tidb_ttl_job_enable"
+	// TiDBTTLScanBatchSize is the number of the rows read by each scan query of the TTL jobs.
+	TiDBTTLScanBatchSize = "tidb_ttl_scan_batch_size"
+	// TiDBTTLDeleteBatchSize is the number of the rows deleted by each delete statement of the TTL jobs.
+	TiDBTTLDeleteBatchSize = "tidb_ttl_delete_batch_size"
+	// TiDBTTLDeleteRateLimit is the max number of the delete statements executed by the TTL jobs per second
+	// on each TiDB node. 0 means no limit.
+	TiDBTTLDeleteRateLimit = "tidb_ttl_delete_rate_limit"
+	// TiDBTTLScanWorkerCount is the number of the workers which scan and delete the region ranges of a TTL job concurrently.
+	TiDBTTLScanWorkerCount = "tidb_ttl_scan_worker_count"
+	// TiDBEnableInstancePlanCache indicates whether the plans of the prepared statements are cached in the
+	// instance-level plan cache, which is shared by all the sessions of the TiDB instance.
+	TiDBEnableInstancePlanCache = "tidb_enable_instance_plan_cache"
+	// TiDBInstancePlanCacheMaxMemSize is the memory quota of the instance-level plan cache.
+	TiDBInstancePlanCacheMaxMemSize = "tidb_instance_plan_cache_max_mem_size"
+	// TiDBEnableNonPreparedPlanCache indicates whether the plans of the text queries are cached. The literals of
+	// the queries are replaced by parameters so that the queries only differ in the literals can share the plans.
+	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
 )
 
 // TiDB intentional limits
@@ -730,8 +763,10 @@ const (
 	DefTiDBDDLReorgWorkerCount            = 4
 	DefTiDBDDLReorgBatchSize              = 256
 	DefTiDBDDLErrorCountLimit             = 512
+	DefTiDBDDLJobWorkerCount              = 1
+	MaxDDLJobQueueCount                   = 16 // The max value of tidb_ddl_job_worker_cnt, also the max count of the job queues for each kind of DDL jobs.
 	DefTiDBMaxDeltaSchemaCount            = 1024
-	DefTiDBChangeMultiSchema              = false
+	DefTiDBChangeMultiSchema              = true
 	DefTiDBPointGetCache                  = false
 	DefTiDBEnableAutoIncrementInGenerated = false
 	DefTiDBHashAggPartialConcurrency      = ConcurrencyUnset
@@ -745,11 +780,13 @@ const (
 	DefEnableStrictDoubleTypeCheck        = true
 	DefEnableVectorizedExpression         = true
 	DefTiDBOptJoinReorderThreshold        = 0
+	DefTiDBEnableOuterJoinReorder         = true
 	DefTiDBDDLSlowOprThreshold            = 300
 	DefTiDBUseFastAnalyze                 = false
 	DefTiDBSkipIsolationLevelCheck        = false
 	DefTiDBExpensiveQueryTimeThreshold    = 60 // 60s
 	DefTiDBScatterRegion                  = false
+	DefTiDBDDLEnableFastReorg             = false
 	DefTiDBWaitSplitRegionFinish          = true
 	DefWaitSplitRegionTimeout             = 300 // 300s
 	DefTiDBEnableNoopFuncs                = Off
@@ -783,6 +820,7 @@ const (
 	DefTiDBEnableIndexMergeJoin           = false
 	DefTiDBTrackAggregateMemoryUsage      = true
 	DefTiDBEnableExchangePartition        = false
+	DefTiDBEnableGlobalIndex              = false
 	DefCTEMaxRecursionDepth               = 1000
 	DefTiDBTmpTableMaxSize                = 64 << 20 // 64MB.
 	DefTiDBEnableLocalTxn                 = false
@@ -799,6 +837,14 @@ const (
 	DefTiDBEnableColumnTracking           = false
 	DefTiDBStatsLoadSyncWait              = 0
 	DefTiDBStatsLoadPseudoTimeout         = false
+	DefTiDBTTLJobEnable                   = true
+	DefTiDBTTLScanBatchSize               = 500
+	DefTiDBTTLDeleteBatchSize             = 100
+	DefTiDBTTLDeleteRateLimit             = 0
+	DefTiDBTTLScanWorkerCount             = 4
+	DefTiDBEnableInstancePlanCache        = false
+	DefTiDBInstancePlanCacheMaxMemSize    = 100 << 20 // 100MB
+	DefTiDBEnableNonPreparedPlanCache     = false
 )
 
 // Process global variables.
@@ -832,4 +878,11 @@ var (
 	EnableColumnTracking                  = atomic.NewBool(DefTiDBEnableColumnTracking)
 	StatsLoadSyncWait                     = atomic.NewInt64(DefTiDBStatsLoadSyncWait)
 	StatsLoadPseudoTimeout                = atomic.NewBool(DefTiDBStatsLoadPseudoTimeout)
+	EnableTTLJob                          = atomic.NewBool(DefTiDBTTLJobEnable)
+	TTLScanBatchSize                      = atomic.NewInt64(DefTiDBTTLScanBatchSize)
+	TTLDeleteBatchSize                    = atomic.NewInt64(DefTiDBTTLDeleteBatchSize)
+	TTLDeleteRateLimit                    = atomic.NewInt64(DefTiDBTTLDeleteRateLimit)
+	TTLScanWorkerCount                    = atomic.NewInt32(DefTiDBTTLScanWorkerCount)
+	EnableInstancePlanCache               = atomic.NewBool(DefTiDBEnableInstancePlanCache)
+	InstancePlanCacheMaxMemSize           = atomic.NewInt64(DefTiDBInstancePlanCacheMaxMemSize)
 )
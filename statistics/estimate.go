@@ -24,14 +24,23 @@ import (
 func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRatio uint64) {
 	sampleSize, sampleNDV, onlyOnceItems := h.sampleSize, uint64(len(h.sorted)), h.onlyOnceItems
 	scaleRatio = rowCount / sampleSize
+	if onlyOnceItems == sampleSize {
+		// The count of the elements of a unique column isn't scaled up.
+		scaleRatio = 1
+	}
+	return EstimateNDV(sampleSize, sampleNDV, onlyOnceItems, rowCount), scaleRatio
+}
 
+// EstimateNDV estimates the NDV of rowCount rows by a sample of them, sampleNDV is the NDV of the sample and
+// onlyOnceItems is the number of the values which occur only once in the sample.
+func EstimateNDV(sampleSize, sampleNDV, onlyOnceItems, rowCount uint64) uint64 {
 	if onlyOnceItems == sampleSize {
 		// Assume this is a unique column, so do not scale up the count of elements
-		return rowCount, 1
+		return rowCount
 	} else if onlyOnceItems == 0 {
 		// Assume data only consists of sampled data
 		// Nothing to do, no change with scale ratio
-		return sampleNDV, scaleRatio
+		return sampleNDV
 	}
 	// Charikar, Moses, et al. "Towards estimation error guarantees for distinct values."
 	// Proceedings of the nineteenth ACM SIGMOD-SIGACT-SIGART symposium on Principles of database systems. ACM, 2000.
@@ -44,8 +53,8 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 	N := float64(rowCount)
 	d := float64(sampleNDV)
 
-	ndv = uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
+	ndv := uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
 	ndv = mathutil.MaxUint64(ndv, sampleNDV)
 	ndv = mathutil.MinUint64(ndv, rowCount)
-	return ndv, scaleRatio
+	return ndv
 }
@@ -381,3 +381,68 @@ func TestDumpVer2Stats(t *testing.T) {
 	// the statistics.Table in the stats cache is the same as the unmarshalled statistics.Table
 	requireTableEqual(t, statsCacheTbl, loadTbl)
 }
+
+func TestJSONTableToBlocks(t *testing.T) {
+	tk, dom, clean := createTestKitAndDom(t)
+	defer clean()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10), index idx(a, b))")
+	tk.MustExec("insert into t value(1, 'aaa'), (3, 'aab'), (5, 'bba'), (2, 'bbb'), (4, 'cca'), (6, 'ccc')")
+	tk.MustExec("analyze table t")
+	dumpJSONTable := getStatsJSON(t, dom, "test", "t")
+	jsonBytes, err := json.Marshal(dumpJSONTable)
+	require.NoError(t, err)
+
+	blocks, err := handle.JSONTableToBlocks(dumpJSONTable, 16)
+	require.NoError(t, err)
+	require.Greater(t, len(blocks), 1)
+	for _, block := range blocks {
+		require.LessOrEqual(t, len(block), 16)
+	}
+	loadJSONTable, err := handle.BlocksToJSONTable(blocks)
+	require.NoError(t, err)
+	loadBytes, err := json.Marshal(loadJSONTable)
+	require.NoError(t, err)
+	require.Equal(t, jsonBytes, loadBytes)
+}
+
+func TestRestoreHistoricalStats(t *testing.T) {
+	tk, dom, clean := createTestKitAndDom(t)
+	defer clean()
+	tk.MustExec("set global tidb_enable_historical_stats = 1")
+	defer tk.MustExec("set global tidb_enable_historical_stats = 0")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, index idx(a))")
+	tk.MustGetErrMsg("restore stats t as of timestamp now()", "[executor:8258]There are no historical stats of table 'test.t' before the given time")
+
+	tk.MustExec("insert into t values (1), (2), (3)")
+	tk.MustExec("analyze table t")
+	rows := tk.MustQuery("select version from mysql.stats_history where table_id = (select table_id from mysql.stats_meta)").Rows()
+	require.Len(t, rows, 1)
+	tk.MustExec("insert into t values (4), (5), (6), (7), (8)")
+	tk.MustExec("analyze table t")
+	rows = tk.MustQuery("select version from mysql.stats_history order by version").Rows()
+	require.Len(t, rows, 2)
+	tk.MustQuery("select count from mysql.stats_meta").Check(testkit.Rows("8"))
+
+	// Restore the stats recorded by the first analyze.
+	restoreTo := func(version string) {
+		ts := tk.MustQuery(fmt.Sprintf("select from_unixtime((%s >> 18) / 1000 + 0.001)", version)).Rows()[0][0].(string)
+		tk.MustExec(fmt.Sprintf("restore stats t as of timestamp '%s'", ts))
+	}
+	version := rows[0][0].(string)
+	restoreTo(version)
+	tk.MustQuery("show warnings").Check(testkit.Rows(fmt.Sprintf("Note 1105 Restore Stats: the stats of version %s are restored", version)))
+	tk.MustQuery("select count from mysql.stats_meta").Check(testkit.Rows("3"))
+	h := dom.StatsHandle()
+	require.NoError(t, h.Update(dom.InfoSchema()))
+	tk.MustQuery("explain format = 'brief' select * from t where a > 0").Check(testkit.Rows(
+		"IndexReader 3.00 root  index:IndexRangeScan",
+		"└─IndexRangeScan 3.00 cop[tikv] table:t, index:idx(a) range:(0,+inf], keep order:false"))
+
+	// Restore the latest stats.
+	restoreTo(rows[1][0].(string))
+	tk.MustQuery("select count from mysql.stats_meta").Check(testkit.Rows("8"))
+}
@@ -72,6 +72,13 @@ func (h *Handle) gcTableStats(is infoschema.InfoSchema, physicalID int64) error
 	h.mu.Unlock()
 	if !ok {
 		logutil.BgLogger().Info("remove stats in GC due to dropped table", zap.Int64("table_id", physicalID))
+		// The historical stats and the stats lock are kept after DROP STATS, so only remove them for the dropped table.
+		if _, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_history where table_id = %?", physicalID); err != nil {
+			return errors.Trace(err)
+		}
+		if _, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_table_locked where table_id = %?", physicalID); err != nil {
+			return errors.Trace(err)
+		}
 		return errors.Trace(h.DeleteTableStatsFromKV([]int64{physicalID}))
 	}
 	tblInfo := tbl.Meta()
@@ -15,9 +15,11 @@
 package handle
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"sync"
@@ -41,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -976,6 +979,14 @@ func (h *Handle) extendedStatsFromStorage(reader *statsReader, table *statistics
 				}
 			} else {
 				item.StringVals = statsStr
+				// The degree of the dependency is kept as the scalar value to be used in the estimation.
+				if item.Tp == ast.StatsTypeDependency && statsStr != "" {
+					item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
+					if err != nil {
+						logutil.BgLogger().Error("[stats] parse dependency stats failed", zap.String("stats", statsStr), zap.Error(err))
+						return nil, err
+					}
+				}
 			}
 			table.ExtendedStats.Stats[name] = item
 		}
@@ -1476,7 +1487,10 @@ const (
 
 // InsertExtendedStats inserts a record into mysql.stats_extended and update version in mysql.stats_meta.
 func (h *Handle) InsertExtendedStats(statsName string, colIDs []int64, tp int, tableID int64, ifNotExists bool) (err error) {
-	sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	// The dependency is from the first column to the second one, so the order of its columns is kept.
+	if tp != int(ast.StatsTypeDependency) {
+		sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+	}
 	bytes, err := json.Marshal(colIDs)
 	if err != nil {
 		return errors.Trace(err)
@@ -1668,14 +1682,134 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 
 func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeCardinality:
+		return h.fillExtStatsCardVals(item, cols, collectors)
+	case ast.StatsTypeDependency:
+		return h.fillExtStatsDepVals(item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
 	}
 	return nil
 }
 
+// sampleRowsOfExtStats returns the encoded values of the columns of the extended stats for every sampled row, the rows
+// which contain NULL values are skipped. rowCount is the estimated count of the rows whose values are all not NULL.
+func (h *Handle) sampleRowsOfExtStats(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) (rows [][][]byte, rowCount int64, ok bool) {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != len(item.ColIDs) {
+		return nil, 0, false
+	}
+	rowCount = math.MaxInt64
+	for _, offset := range colOffsets {
+		// The virtual generated columns aren't sampled.
+		if collectors[offset] == nil {
+			return nil, 0, false
+		}
+		rowCount = mathutil.MinInt64(rowCount, collectors[offset].Count)
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	// The samples of the columns are matched by their ordinals, i.e, the positions of the sampled rows.
+	ordinal2Row := make(map[int][][]byte, len(collectors[colOffsets[0]].Samples))
+	for i, offset := range colOffsets {
+		for _, sample := range collectors[offset].Samples {
+			row, exists := ordinal2Row[sample.Ordinal]
+			if !exists {
+				if i > 0 {
+					continue
+				}
+				row = make([][]byte, 0, len(colOffsets))
+			}
+			if len(row) != i {
+				continue
+			}
+			val, err := codec.EncodeKey(sc, nil, sample.Value)
+			if err != nil {
+				return nil, 0, false
+			}
+			ordinal2Row[sample.Ordinal] = append(row, val)
+		}
+	}
+	rows = make([][][]byte, 0, len(ordinal2Row))
+	for _, row := range ordinal2Row {
+		if len(row) == len(colOffsets) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, rowCount, true
+}
+
+// fillExtStatsCardVals estimates the NDV of the column group by the sampled rows.
+func (h *Handle) fillExtStatsCardVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	rows, rowCount, ok := h.sampleRowsOfExtStats(item, cols, collectors)
+	if !ok {
+		return nil
+	}
+	if len(rows) == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	counts := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		counts[string(bytes.Join(row, nil))]++
+	}
+	var onlyOnceItems uint64
+	for _, cnt := range counts {
+		if cnt == 1 {
+			onlyOnceItems++
+		}
+	}
+	sampleSize := uint64(len(rows))
+	item.ScalarVals = float64(statistics.EstimateNDV(sampleSize, uint64(len(counts)), onlyOnceItems, mathutil.MaxUint64(uint64(rowCount), sampleSize)))
+	return item
+}
+
+// fillExtStatsDepVals computes the degree of the functional dependency from the first column to the second column,
+// i.e, the fraction of the rows whose value of the second column is the most common one among the rows which have
+// the same value of the first column. 1 means the first column determines the second column.
+func (h *Handle) fillExtStatsDepVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	if len(item.ColIDs) != 2 {
+		return nil
+	}
+	rows, _, ok := h.sampleRowsOfExtStats(item, cols, collectors)
+	if !ok {
+		return nil
+	}
+	degree := float64(1)
+	if len(rows) > 0 {
+		groups := make(map[string]map[string]int, len(rows))
+		for _, row := range rows {
+			group, exists := groups[string(row[0])]
+			if !exists {
+				group = make(map[string]int)
+				groups[string(row[0])] = group
+			}
+			group[string(row[1])]++
+		}
+		var determinedRows int
+		for _, group := range groups {
+			maxCnt := 0
+			for _, cnt := range group {
+				maxCnt = mathutil.Max(maxCnt, cnt)
+			}
+			determinedRows += maxCnt
+		}
+		degree = float64(determinedRows) / float64(len(rows))
+	}
+	item.ScalarVals = degree
+	item.StringVals = strconv.FormatFloat(degree, 'f', 6, 64)
+	return item
+}
+
 func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
@@ -791,7 +791,7 @@ func (s *testStatsSuite) TestAnalyzeVirtualCol(c *C) {
 	tk.MustExec("insert into t(a) values(2),(1),(1),(3),(NULL)")
 	tk.MustExec("set @@tidb_analyze_version = 2")
 	tk.MustExec("analyze table t")
-	c.Assert(len(tk.MustQuery("show stats_histograms where table_name ='t'").Rows()), Equals, 3)
+	c.Assert(len(tk.MustQuery("show stats_histograms where table_name ='t'").Rows()), Equals, 4)
 }
 
 func (s *testStatsSuite) TestShowGlobalStats(c *C) {
@@ -2039,6 +2039,65 @@ func (s *testStatsSuite) TestCorrelationStatsCompute(c *C) {
 	))
 }
 
+func (s *testStatsSuite) TestCardinalityAndDependencyStatsCompute(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	c.Assert(err.Error(), Equals, "Only support Cardinality statistics type on at least 2 columns")
+	err = tk.ExecToErr("alter table t add stats_extended s1 dependency(a,b,c)")
+	c.Assert(err.Error(), Equals, "Only support Correlation and Dependency statistics types on 2 columns")
+	// a determines b, and every combination of b and c has 2 rows.
+	tk.MustExec("insert into t values(1,1,1),(2,2,1),(3,1,1),(4,2,1),(1,1,2),(2,2,2),(3,1,2),(4,2,2)")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(b,c)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(a,b,c)")
+	tk.MustExec("alter table t add stats_extended s3 dependency(a,b)")
+	tk.MustExec("alter table t add stats_extended s4 dependency(b,a)")
+	tk.MustQuery("select name, type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"s1 0 [2,3] <nil> 0",
+		"s2 0 [1,2,3] <nil> 0",
+		"s3 1 [1,2] <nil> 0",
+		"s4 1 [2,1] <nil> 0",
+	))
+	for _, ver := range []int{1, 2} {
+		tk.MustExec(fmt.Sprintf("set @@session.tidb_analyze_version=%d", ver))
+		tk.MustExec("analyze table t")
+		tk.MustQuery("select name, type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+			"s1 0 [2,3] 4.000000 1",
+			"s2 0 [1,2,3] 8.000000 1",
+			"s3 1 [1,2] 1.000000 1",
+			"s4 1 [2,1] 0.500000 1",
+		))
+	}
+
+	do := s.do
+	is := do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(do.StatsHandle().Update(is), IsNil)
+	statsTbl := do.StatsHandle().GetTableStats(tbl.Meta())
+	c.Assert(statsTbl.ExtendedStats, NotNil)
+	c.Assert(len(statsTbl.ExtendedStats.Stats), Equals, 4)
+	c.Assert(statsTbl.ExtendedStats.Stats["s1"].ScalarVals, Equals, float64(4))
+	c.Assert(statsTbl.ExtendedStats.Stats["s4"].ScalarVals, Equals, 0.5)
+	tk.MustQuery("show stats_extended where stats_name in ('s1', 's4')").Sort().Check(testkit.Rows(
+		fmt.Sprintf("test t s1 [b,c] cardinality 4.000000 %d", statsTbl.ExtendedStats.LastUpdateVersion),
+		fmt.Sprintf("test t s4 [b,a] dependency 0.500000 %d", statsTbl.ExtendedStats.LastUpdateVersion),
+	))
+
+	// The rows with NULL values are skipped.
+	tk.MustExec("insert into t values(5,null,null),(null,null,1)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select name, stats from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"s1 4.000000",
+		"s2 8.000000",
+		"s3 1.000000",
+		"s4 0.500000",
+	))
+}
+
 func (s *testStatsSuite) TestSyncStatsExtendedRemoval(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
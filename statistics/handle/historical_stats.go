@@ -0,0 +1,181 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pingcap/errors"
+	mysql "github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// maxHistoricalStatsBlockSize is the max size of each block of the compressed json in mysql.stats_history,
+// it keeps the size of each row less than the entry size limit.
+const maxHistoricalStatsBlockSize = 1 << 20
+
+// ErrNoHistoricalStats is returned when there are no historical stats of the table before the given version.
+var ErrNoHistoricalStats = dbterror.ClassExecutor.NewStd(mysql.ErrNoHistoricalStats)
+
+// JSONTableToBlocks compresses the json of the table stats and splits it into blocks no larger than blockSize.
+func JSONTableToBlocks(jsonTbl *JSONTable, blockSize int) ([][]byte, error) {
+	data, err := json.Marshal(jsonTbl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	if _, err = gzw.Write(data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = gzw.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	compressed := buf.Bytes()
+	blocks := make([][]byte, 0, len(compressed)/blockSize+1)
+	for len(compressed) > blockSize {
+		blocks = append(blocks, compressed[:blockSize])
+		compressed = compressed[blockSize:]
+	}
+	return append(blocks, compressed), nil
+}
+
+// BlocksToJSONTable merges the blocks made by JSONTableToBlocks and decompresses them to the json of the table stats.
+func BlocksToJSONTable(blocks [][]byte) (*JSONTable, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(bytes.Join(blocks, nil)))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := io.ReadAll(gzr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = gzr.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonTbl := &JSONTable{}
+	if err = json.Unmarshal(data, jsonTbl); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return jsonTbl, nil
+}
+
+// RecordHistoricalStatsToStorage dumps the current stats of the table and keeps them in mysql.stats_history.
+// The stats are recorded with the latest stats version of the table and its partitions, and nothing is written
+// if this version has been recorded. It returns the recorded version.
+func (h *Handle) RecordHistoricalStatsToStorage(dbName string, tableInfo *model.TableInfo) (uint64, error) {
+	version, err := h.latestStatsVersion(tableInfo)
+	if err != nil || version == 0 {
+		return 0, err
+	}
+	ctx := context.Background()
+	rows, _, err := h.execRestrictedSQL(ctx, "select 1 from mysql.stats_history where table_id = %? and version = %? limit 1", tableInfo.ID, version)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return version, nil
+	}
+	jsonTbl, err := h.DumpStatsToJSON(dbName, tableInfo, nil)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	blocks, err := JSONTableToBlocks(jsonTbl, maxHistoricalStatsBlockSize)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return version, h.saveHistoricalStatsBlocks(tableInfo.ID, version, blocks)
+}
+
+func (h *Handle) saveHistoricalStatsBlocks(tableID int64, version uint64, blocks [][]byte) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin pessimistic")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	now := time.Now()
+	for i, block := range blocks {
+		const sql = "insert into mysql.stats_history(table_id, stats_data, seq_no, version, create_time) values (%?, %?, %?, %?, %?)"
+		if _, err = exec.ExecuteInternal(ctx, sql, tableID, block, i, version, now); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// latestStatsVersion returns the max version in mysql.stats_meta of the table and its partitions.
+func (h *Handle) latestStatsVersion(tableInfo *model.TableInfo) (uint64, error) {
+	physicalIDs := []int64{tableInfo.ID}
+	if pi := tableInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+		}
+	}
+	var maxVersion uint64
+	for _, id := range physicalIDs {
+		version, _, _, err := h.statsMetaByTableIDFromStorage(id, 0)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if version > maxVersion {
+			maxVersion = version
+		}
+	}
+	return maxVersion, nil
+}
+
+// RestoreHistoricalStats loads the latest historical stats of the table whose version is not greater than the
+// given version from mysql.stats_history, and saves them as the current stats. It returns the version of the
+// restored stats.
+func (h *Handle) RestoreHistoricalStats(is infoschema.InfoSchema, dbName string, tableInfo *model.TableInfo, version uint64) (uint64, error) {
+	ctx := context.Background()
+	rows, _, err := h.execRestrictedSQL(ctx, "select max(version) from mysql.stats_history where table_id = %? and version <= %?", tableInfo.ID, version)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return 0, ErrNoHistoricalStats.GenWithStackByArgs(dbName, tableInfo.Name.O)
+	}
+	version = rows[0].GetUint64(0)
+	rows, _, err = h.execRestrictedSQL(ctx, "select stats_data from mysql.stats_history where table_id = %? and version = %? order by seq_no", tableInfo.ID, version)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	blocks := make([][]byte, 0, len(rows))
+	for _, row := range rows {
+		blocks = append(blocks, row.GetBytes(0))
+	}
+	jsonTbl, err := BlocksToJSONTable(blocks)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	// The table may have been renamed after the stats were recorded.
+	jsonTbl.DatabaseName, jsonTbl.TableName = dbName, tableInfo.Name.L
+	return version, errors.Trace(h.LoadStatsFromJSON(is, jsonTbl))
+}
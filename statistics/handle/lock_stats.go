@@ -0,0 +1,81 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// AddLockedTables locks the stats of the tables, auto analyze skips the locked tables.
+func (h *Handle) AddLockedTables(tids []int64) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin pessimistic")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	txn, err := h.mu.ctx.Txn(true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, tid := range tids {
+		if _, err = exec.ExecuteInternal(ctx, "insert ignore into mysql.stats_table_locked(table_id, version) values (%?, %?)", tid, txn.StartTS()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// RemoveLockedTables unlocks the stats of the tables.
+func (h *Handle) RemoveLockedTables(tids []int64) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin pessimistic")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	for _, tid := range tids {
+		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_table_locked where table_id = %?", tid); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// GetLockedTables returns the IDs of the tables whose stats are locked.
+func (h *Handle) GetLockedTables() (map[int64]struct{}, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select table_id from mysql.stats_table_locked")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tids := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		tids[row.GetInt64(0)] = struct{}{}
+	}
+	return tids, nil
+}
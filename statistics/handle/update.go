@@ -1037,6 +1037,12 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	if !timeutil.WithinDayTimePeriod(start, end, time.Now()) {
 		return false
 	}
+	lockedTables, err := h.GetLockedTables()
+	if err != nil {
+		// Skip the whole round since the locked tables mustn't be analyzed.
+		logutil.BgLogger().Error("[stats] get the locked tables for auto analyze failed", zap.Error(err))
+		return false
+	}
 	pruneMode := h.CurrentPruneMode()
 	for _, db := range dbs {
 		if util.IsMemOrSysDB(strings.ToLower(db)) {
@@ -1048,6 +1054,10 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 			if tblInfo.IsView() {
 				continue
 			}
+			// The stats of the table are locked by LOCK STATS.
+			if _, ok := lockedTables[tblInfo.ID]; ok {
+				continue
+			}
 			pi := tblInfo.GetPartitionInfo()
 			if pi == nil {
 				statsTbl := h.GetTableStats(tblInfo)
@@ -586,6 +586,43 @@ func (s *testSerialStatsSuite) TestAutoAnalyzeOnEmptyTable(c *C) {
 	c.Assert(s.do.StatsHandle().HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
 }
 
+func (s *testSerialStatsSuite) TestAutoAnalyzeSkipLockedTable(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+
+	oriStart := tk.MustQuery("select @@tidb_auto_analyze_start_time").Rows()[0][0].(string)
+	oriEnd := tk.MustQuery("select @@tidb_auto_analyze_end_time").Rows()[0][0].(string)
+	defer func() {
+		tk.MustExec(fmt.Sprintf("set global tidb_auto_analyze_start_time='%v'", oriStart))
+		tk.MustExec(fmt.Sprintf("set global tidb_auto_analyze_end_time='%v'", oriEnd))
+	}()
+	tk.MustExec("set global tidb_auto_analyze_start_time='00:00 +0000'")
+	tk.MustExec("set global tidb_auto_analyze_end_time='23:59 +0000'")
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, index idx(a))")
+	tk.MustExec("analyze table t")
+	tk.MustExec("insert into t values (1)" + strings.Repeat(", (1)", int(handle.AutoAnalyzeMinCnt)))
+	h := s.do.StatsHandle()
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(s.do.InfoSchema()), IsNil)
+
+	tk.MustExec("lock stats t")
+	tbl, err := s.do.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tk.MustQuery("select table_id from mysql.stats_table_locked").Check(testkit.Rows(fmt.Sprintf("%d", tbl.Meta().ID)))
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsFalse)
+
+	tk.MustExec("unlock stats t")
+	tk.MustQuery("select table_id from mysql.stats_table_locked").Check(testkit.Rows())
+
+	// The round is skipped when the locked tables can't be read.
+	tk.MustExec("rename table mysql.stats_table_locked to mysql.stats_table_locked_bak")
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsFalse)
+	tk.MustExec("rename table mysql.stats_table_locked_bak to mysql.stats_table_locked")
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
+}
+
 func (s *testSerialStatsSuite) TestAutoAnalyzeOutOfSpecifiedTime(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
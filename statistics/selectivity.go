@@ -316,6 +316,9 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 			CETraceExpr(ctx, tableID, "Table Stats-Expression-CNF", expr, ret*float64(coll.Count))
 		}
 	}
+	if ctx.GetSessionVars().EnableExtendedStats {
+		ret *= coll.adjustSelectivityByExtendedStats(ctx, usedSets)
+	}
 
 	// Now we try to cover those still not covered DNF conditions using independence assumption,
 	// i.e., sel(condA or condB) = sel(condA) + sel(condB) - sel(condA) * sel(condB)
@@ -418,6 +421,78 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	return ret, nodes, nil
 }
 
+// adjustSelectivityByExtendedStats adjusts the selectivity of the equal conditions on the correlated columns, which
+// are estimated independently by the column statistics, by the dependency and cardinality extended statistics.
+// It returns the factor to be multiplied to the selectivity.
+func (coll *HistColl) adjustSelectivityByExtendedStats(sctx sessionctx.Context, usedSets []*StatsNode) float64 {
+	if coll.ExtendedStats == nil || len(coll.ExtendedStats.Stats) == 0 {
+		return 1
+	}
+	// pointSels maps the IDs of the column infos to the selectivities of the equal conditions on the columns.
+	pointSels := make(map[int64]float64)
+	for _, set := range usedSets {
+		if set.Tp != ColType || len(set.Ranges) != 1 || !set.Ranges[0].IsPointNonNullable(sctx) || set.Selectivity <= 0 {
+			continue
+		}
+		if colHist := coll.Columns[set.ID]; colHist != nil && colHist.Info != nil {
+			pointSels[colHist.Info.ID] = set.Selectivity
+		}
+	}
+	if len(pointSels) < 2 {
+		return 1
+	}
+	names := make([]string, 0, len(coll.ExtendedStats.Stats))
+	for name := range coll.ExtendedStats.Stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	factor := 1.0
+	// The dependencies are applied first, the dependent columns aren't adjusted again.
+	for _, name := range names {
+		item := coll.ExtendedStats.Stats[name]
+		if item.Tp != ast.StatsTypeDependency || len(item.ColIDs) != 2 {
+			continue
+		}
+		_, ok := pointSels[item.ColIDs[0]]
+		selB, ok1 := pointSels[item.ColIDs[1]]
+		if !ok || !ok1 {
+			continue
+		}
+		// sel(a = x and b = y) = sel(a = x) * (degree + (1 - degree) * sel(b = y)), the degree is the fraction
+		// of the rows whose b is determined by a.
+		degree := math.Max(0, math.Min(1, item.ScalarVals))
+		factor *= (degree + (1-degree)*selB) / selB
+		delete(pointSels, item.ColIDs[1])
+	}
+	for _, name := range names {
+		item := coll.ExtendedStats.Stats[name]
+		if item.Tp != ast.StatsTypeCardinality || item.ScalarVals <= 0 {
+			continue
+		}
+		product, minSel := 1.0, 1.0
+		covered := true
+		for _, colID := range item.ColIDs {
+			sel, ok := pointSels[colID]
+			if !ok {
+				covered = false
+				break
+			}
+			product *= sel
+			minSel = math.Min(minSel, sel)
+		}
+		if !covered {
+			continue
+		}
+		// The rows of the column group are assumed to be distributed uniformly on its distinct values, so the
+		// selectivity isn't less than 1/NDV, and it can't be larger than the selectivity of any single column.
+		factor *= math.Min(minSel, math.Max(product, 1/item.ScalarVals)) / product
+		for _, colID := range item.ColIDs {
+			delete(pointSels, colID)
+		}
+	}
+	return factor
+}
+
 func getMaskAndRanges(ctx sessionctx.Context, exprs []expression.Expression, rangeType ranger.RangeType, lengths []int, cachedPath *planutil.AccessPath, cols ...*expression.Column) (mask int64, ranges []*ranger.Range, partCover bool, err error) {
 	isDNF := false
 	var accessConds, remainedConds []expression.Expression
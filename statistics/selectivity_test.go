@@ -20,6 +20,7 @@ import (
 	"math"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"testing"
 	"time"
 
@@ -908,3 +909,40 @@ func TestSelectivityGreedyAlgo(t *testing.T) {
 	require.Equal(t, 1, len(usedSets))
 	require.Equal(t, int64(1), usedSets[0].ID)
 }
+
+func TestSelectivityWithExtendedStats(t *testing.T) {
+	domain.RunAutoAnalyze = false
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("set @@session.tidb_enable_extended_stats = on")
+	testKit.MustExec("drop table if exists t")
+	testKit.MustExec("create table t(a int, b int, c int)")
+	// a determines b, and every combination of b and c has 10 rows.
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, %d)", i%100, i%10, i/100))
+	}
+	testKit.MustExec("insert into t values " + strings.Join(values, ","))
+	testKit.MustExec("alter table t add stats_extended s1 dependency(a, b)")
+	testKit.MustExec("alter table t add stats_extended s2 cardinality(b, c)")
+	h := dom.StatsHandle()
+	require.Nil(t, h.DumpStatsDeltaToKV(handle.DumpAll))
+	testKit.MustExec("analyze table t")
+	testKit.MustExec("explain select * from t where a = 5 and b = 5 and c = 1")
+	require.Nil(t, h.LoadNeededHistograms())
+
+	estRows := func(sql string) string {
+		rows := testKit.MustQuery("explain format = 'brief' " + sql).Rows()
+		return rows[0][1].(string)
+	}
+	// The selectivity of b = 5 is 1 since it's determined by a = 5.
+	require.Equal(t, "10.00", estRows("select * from t where a = 5 and b = 5"))
+	// The NDV of (b, c) is 100 rather than the max NDV of b and c.
+	require.Equal(t, "100.00", estRows("select count(*) from t group by b, c"))
+
+	testKit.MustExec("set @@session.tidb_enable_extended_stats = off")
+	require.Equal(t, "1.00", estRows("select * from t where a = 5 and b = 5"))
+	require.Equal(t, "10.00", estRows("select count(*) from t group by b, c"))
+}
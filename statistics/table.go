@@ -64,9 +64,8 @@ const (
 // Table represents statistics for a table.
 type Table struct {
 	HistColl
-	Version       uint64
-	Name          string
-	ExtendedStats *ExtendedStatsColl
+	Version uint64
+	Name    string
 	// TblInfoUpdateTS is the UpdateTS of the TableInfo used when filling this struct.
 	// It is the schema version of the corresponding table. It is used to skip redundant
 	// loading of stats, i.e, if the cached stats is already update-to-date with mysql.stats_xxx tables,
@@ -110,6 +109,9 @@ type HistColl struct {
 	// The physical id is used when try to load column stats from storage.
 	HavePhysicalID bool
 	Pseudo         bool
+
+	// ExtendedStats are the extended statistics of the table, their column IDs are the IDs of the column infos.
+	ExtendedStats *ExtendedStatsColl
 }
 
 // MemoryUsage returns the total memory usage of this Table.
@@ -418,6 +420,53 @@ func (coll *HistColl) GetRowCountByIndexRanges(sctx sessionctx.Context, idxID in
 	return result, errors.Trace(err)
 }
 
+// GetRowCountByIndexColumnsRanges estimates the row count of the index ranges by the statistics of the index columns.
+// It's used for the indexes that don't have statistics of their own, such as the hypothetical indexes. The columns
+// are assumed to be independent of each other.
+func (coll *HistColl) GetRowCountByIndexColumnsRanges(sctx sessionctx.Context, colIDs []int64, indexRanges []*ranger.Range) (float64, error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	if coll.Count == 0 {
+		return 0, nil
+	}
+	tableRowCount := float64(coll.Count)
+	var totalCount float64
+	for _, indexRange := range indexRanges {
+		eqLen, err := indexRange.PrefixEqualLen(sc)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		selectivity := 1.0
+		for i := 0; i < len(indexRange.LowVal) && i < len(colIDs); i++ {
+			colRange := &ranger.Range{
+				LowVal:    []types.Datum{indexRange.LowVal[i]},
+				HighVal:   []types.Datum{indexRange.HighVal[i]},
+				Collators: []collate.Collator{collate.GetBinaryCollator()},
+			}
+			if i < len(indexRange.Collators) {
+				colRange.Collators[0] = indexRange.Collators[i]
+			}
+			// Only the last column of the range can be an open interval.
+			if i >= eqLen || i == len(indexRange.LowVal)-1 {
+				colRange.LowExclude = indexRange.LowExclude
+				colRange.HighExclude = indexRange.HighExclude
+			}
+			count, err := coll.GetRowCountByColumnRanges(sctx, colIDs[i], []*ranger.Range{colRange})
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			selectivity *= count / tableRowCount
+			if i >= eqLen {
+				break
+			}
+		}
+		totalCount += selectivity * tableRowCount
+	}
+	if totalCount > tableRowCount {
+		totalCount = tableRowCount
+	}
+	return totalCount, nil
+}
+
 // CETraceRange appends a list of ranges and related information into CE trace
 func CETraceRange(sctx sessionctx.Context, tableID int64, colNames []string, ranges []*ranger.Range, tp string, rowCount uint64) {
 	sc := sctx.GetSessionVars().StmtCtx
@@ -486,6 +535,7 @@ func (coll *HistColl) ID2UniqueID(columns []*expression.Column) *HistColl {
 		Pseudo:         coll.Pseudo,
 		Count:          coll.Count,
 		ModifyCount:    coll.ModifyCount,
+		ExtendedStats:  coll.ExtendedStats,
 		Columns:        cols,
 	}
 	return newColl
@@ -538,6 +588,7 @@ func (coll *HistColl) GenerateHistCollFromColumnInfo(infos []*model.ColumnInfo,
 		Pseudo:         coll.Pseudo,
 		Count:          coll.Count,
 		ModifyCount:    coll.ModifyCount,
+		ExtendedStats:  coll.ExtendedStats,
 		Columns:        newColHistMap,
 		Indices:        newIdxHistMap,
 		ColID2IdxID:    colID2IdxID,
@@ -0,0 +1,233 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	tikverr "github.com/tikv/client-go/v2/error"
+	tikvstore "github.com/tikv/client-go/v2/kv"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+// savepointKeyState is the state of a key in the MemDB when the savepoint is created.
+type savepointKeyState struct {
+	// exists means the key is in the MemDB, maybe with only the flags.
+	exists   bool
+	hasValue bool
+	value    []byte
+	flags    tikvstore.KeyFlags
+}
+
+// savepoints records the original states of the keys which are modified after the savepoints.
+// The savepoints can't be the staging buffers of the MemDB, because the statements read the snapshot
+// of the MemDB at the bottom staging buffer, and the changes above it are invisible.
+type savepoints struct {
+	// stack[i] holds the original states of the keys modified after the (i+1)-th savepoint.
+	// It's nil if the savepoint is deleted while the later ones are kept, so that their handles don't change.
+	stack []map[string]savepointKeyState
+}
+
+func (s *savepoints) add() kv.SavepointHandle {
+	s.stack = append(s.stack, make(map[string]savepointKeyState))
+	return kv.SavepointHandle(len(s.stack))
+}
+
+// recordKey records the state of the key before it is modified.
+// A key recorded in a savepoint is always recorded in the earlier ones, so it stops at the first
+// savepoint which has the key.
+func (s *savepoints) recordKey(db *tikv.MemDB, key []byte) {
+	if s == nil || len(s.stack) == 0 {
+		return
+	}
+	var (
+		state    savepointKeyState
+		captured bool
+	)
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i] == nil {
+			continue
+		}
+		if _, ok := s.stack[i][string(key)]; ok {
+			return
+		}
+		if !captured {
+			state = getSavepointKeyState(db, key)
+			captured = true
+		}
+		s.stack[i][string(key)] = state
+	}
+}
+
+func getSavepointKeyState(db *tikv.MemDB, key []byte) savepointKeyState {
+	flags, err := db.GetFlags(key)
+	if err != nil {
+		return savepointKeyState{}
+	}
+	state := savepointKeyState{exists: true, flags: flags}
+	if value, err := db.Get(key); err == nil {
+		state.hasValue = true
+		// The value points to the memory arena of the MemDB, which may be reused after cleaning up a staging buffer.
+		state.value = append([]byte{}, value...)
+	}
+	return state
+}
+
+func (s *savepoints) rollbackTo(ctx context.Context, db *tikv.MemDB, snapshot kv.Snapshot, h kv.SavepointHandle) error {
+	if h <= 0 || int(h) > len(s.stack) || s.stack[h-1] == nil {
+		return errors.Errorf("invalid savepoint handle %d", h)
+	}
+	// The locks acquired after the savepoint are kept, and whether the locked keys exist is read from the
+	// snapshot again, since the values in the MemDB are discarded.
+	var lockedKeys []kv.Key
+	for key, state := range s.stack[h-1] {
+		if state.hasValue || state.flags.HasLocked() {
+			continue
+		}
+		if flags, err := db.GetFlags([]byte(key)); err == nil && flags.HasLocked() {
+			lockedKeys = append(lockedKeys, kv.Key(key))
+		}
+	}
+	var lockedValues map[string][]byte
+	if len(lockedKeys) > 0 {
+		var err error
+		lockedValues, err = snapshot.BatchGet(ctx, lockedKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for key, state := range s.stack[h-1] {
+		_, exists := lockedValues[key]
+		if err := restoreKeyState(db, []byte(key), state, exists); err != nil {
+			return err
+		}
+	}
+	s.stack = s.stack[:h]
+	s.stack[h-1] = make(map[string]savepointKeyState)
+	return nil
+}
+
+func (s *savepoints) release(h kv.SavepointHandle) {
+	if h <= 0 || int(h) > len(s.stack) {
+		return
+	}
+	s.stack = s.stack[:h-1]
+	s.trim()
+}
+
+// delete removes only the savepoint, the later savepoints are kept. The keys modified after it are still
+// recorded in the earlier savepoints, so its states can be dropped directly.
+func (s *savepoints) delete(h kv.SavepointHandle) {
+	if h <= 0 || int(h) > len(s.stack) {
+		return
+	}
+	s.stack[h-1] = nil
+	s.trim()
+}
+
+// trim removes the deleted savepoints at the top of the stack.
+func (s *savepoints) trim() {
+	for len(s.stack) > 0 && s.stack[len(s.stack)-1] == nil {
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+}
+
+// restoreKeyState restores the value and the flags of the key to the state. The persistent flags,
+// which mean the key is locked in the pessimistic transaction, are kept.
+func restoreKeyState(db *tikv.MemDB, key []byte, state savepointKeyState, lockedValueExists bool) error {
+	curFlags, err := db.GetFlags(key)
+	if tikverr.IsErrNotFound(err) {
+		if !state.exists {
+			return nil
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	target := state.flags | curFlags.AndPersistent()
+	if state.hasValue {
+		ops := getRestoreFlagsOps(curFlags, target)
+		if len(state.value) == 0 {
+			return db.DeleteWithFlags(key, ops...)
+		}
+		return db.SetWithFlags(key, state.value, ops...)
+	}
+	if !state.flags.HasLocked() {
+		if lockedValueExists {
+			target = tikvstore.ApplyFlagsOps(target, tikvstore.SetKeyLockedValueExists)
+		} else {
+			target = tikvstore.ApplyFlagsOps(target, tikvstore.SetKeyLockedValueNotExists)
+		}
+	}
+	if _, err := db.Get(key); err == nil {
+		// The MemDB can't drop the value of a key, so the key is removed and the flags are added back.
+		db.Lock()
+		db.RemoveFromBuffer(key)
+		db.Unlock()
+		curFlags = 0
+	}
+	if ops := getRestoreFlagsOps(curFlags, target); len(ops) > 0 {
+		db.UpdateFlags(key, ops...)
+	}
+	return nil
+}
+
+// getRestoreFlagsOps returns the operations which change the flags from cur to target as much as possible.
+func getRestoreFlagsOps(cur, target tikvstore.KeyFlags) []tikvstore.FlagsOp {
+	var ops []tikvstore.FlagsOp
+	if target.HasPresumeKeyNotExists() != cur.HasPresumeKeyNotExists() {
+		if target.HasPresumeKeyNotExists() {
+			ops = append(ops, tikvstore.SetPresumeKeyNotExists)
+			cur = tikvstore.ApplyFlagsOps(cur, tikvstore.SetPresumeKeyNotExists)
+		} else {
+			ops = append(ops, tikvstore.DelPresumeKeyNotExists)
+			cur = tikvstore.ApplyFlagsOps(cur, tikvstore.DelPresumeKeyNotExists)
+		}
+	}
+	if cur.HasNeedCheckExists() && !target.HasNeedCheckExists() {
+		ops = append(ops, tikvstore.DelNeedCheckExists)
+	}
+	if target.HasLocked() && !cur.HasLocked() {
+		ops = append(ops, tikvstore.SetKeyLocked)
+	}
+	if target.HasLockedValueExists() != cur.HasLockedValueExists() {
+		if target.HasLockedValueExists() {
+			ops = append(ops, tikvstore.SetKeyLockedValueExists)
+		} else {
+			ops = append(ops, tikvstore.SetKeyLockedValueNotExists)
+		}
+	}
+	if target.HasNeedLocked() != cur.HasNeedLocked() {
+		if target.HasNeedLocked() {
+			ops = append(ops, tikvstore.SetNeedLocked)
+		} else {
+			ops = append(ops, tikvstore.DelNeedLocked)
+		}
+	}
+	if target.HasPrewriteOnly() && !cur.HasPrewriteOnly() {
+		ops = append(ops, tikvstore.SetPrewriteOnly)
+	}
+	if target.HasIgnoredIn2PC() && !cur.HasIgnoredIn2PC() {
+		ops = append(ops, tikvstore.SetIgnoredIn2PC)
+	}
+	if target.HasReadable() && !cur.HasReadable() {
+		ops = append(ops, tikvstore.SetReadable)
+	}
+	if target.HasNewlyInserted() && !cur.HasNewlyInserted() {
+		ops = append(ops, tikvstore.SetNewlyInserted)
+	}
+	return ops
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/kv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteSavepoint(t *testing.T) {
+	var s savepoints
+	h1 := s.add()
+	h2 := s.add()
+	h3 := s.add()
+	require.Equal(t, kv.SavepointHandle(3), h3)
+
+	// Deleting a savepoint in the middle keeps the handles of the later ones.
+	s.delete(h2)
+	require.Len(t, s.stack, 3)
+	require.Nil(t, s.stack[h2-1])
+	require.Error(t, s.rollbackTo(context.Background(), nil, nil, h2))
+
+	// Deleting the latest savepoint removes the deleted ones below it too.
+	s.delete(h3)
+	require.Len(t, s.stack, 1)
+	require.Equal(t, kv.SavepointHandle(2), s.add())
+
+	s.delete(h1)
+	s.release(2)
+	require.Empty(t, s.stack)
+}
@@ -42,6 +42,7 @@ type tikvTxn struct {
 	*tikv.KVTxn
 	idxNameCache        map[int64]*model.TableInfo
 	snapshotInterceptor kv.SnapshotInterceptor
+	savepoints          savepoints
 }
 
 // NewTiKVTxn returns a new Transaction.
@@ -52,7 +53,7 @@ func NewTiKVTxn(txn *tikv.KVTxn) kv.Transaction {
 	totalLimit := atomic.LoadUint64(&kv.TxnTotalSizeLimit)
 	txn.GetUnionStore().SetEntrySizeLimit(entryLimit, totalLimit)
 
-	return &tikvTxn{txn, make(map[int64]*model.TableInfo), nil}
+	return &tikvTxn{txn, make(map[int64]*model.TableInfo), nil, savepoints{}}
 }
 
 func (txn *tikvTxn) GetTableInfo(id int64) *model.TableInfo {
@@ -146,6 +147,7 @@ func (txn *tikvTxn) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]b
 }
 
 func (txn *tikvTxn) Delete(k kv.Key) error {
+	txn.savepoints.recordKey(txn.KVTxn.GetMemBuffer(), k)
 	err := txn.KVTxn.Delete(k)
 	return derr.ToTiDBErr(err)
 }
@@ -164,12 +166,33 @@ func (txn *tikvTxn) Get(ctx context.Context, k kv.Key) ([]byte, error) {
 }
 
 func (txn *tikvTxn) Set(k kv.Key, v []byte) error {
+	txn.savepoints.recordKey(txn.KVTxn.GetMemBuffer(), k)
 	err := txn.KVTxn.Set(k, v)
 	return derr.ToTiDBErr(err)
 }
 
 func (txn *tikvTxn) GetMemBuffer() kv.MemBuffer {
-	return newMemBuffer(txn.KVTxn.GetMemBuffer())
+	return newMemBuffer(txn.KVTxn.GetMemBuffer(), &txn.savepoints)
+}
+
+// AddSavepoint implements the kv.SavepointTransaction interface.
+func (txn *tikvTxn) AddSavepoint() kv.SavepointHandle {
+	return txn.savepoints.add()
+}
+
+// RollbackToSavepoint implements the kv.SavepointTransaction interface.
+func (txn *tikvTxn) RollbackToSavepoint(ctx context.Context, h kv.SavepointHandle) error {
+	return txn.savepoints.rollbackTo(ctx, txn.KVTxn.GetMemBuffer(), txn.GetSnapshot(), h)
+}
+
+// ReleaseSavepoint implements the kv.SavepointTransaction interface.
+func (txn *tikvTxn) ReleaseSavepoint(h kv.SavepointHandle) {
+	txn.savepoints.release(h)
+}
+
+// DeleteSavepoint implements the kv.SavepointTransaction interface.
+func (txn *tikvTxn) DeleteSavepoint(h kv.SavepointHandle) {
+	txn.savepoints.delete(h)
 }
 
 func (txn *tikvTxn) SetOption(opt int, val interface{}) {
@@ -26,13 +26,14 @@ import (
 // memBuffer wraps tikv.MemDB as kv.MemBuffer.
 type memBuffer struct {
 	*tikv.MemDB
+	savepoints *savepoints
 }
 
-func newMemBuffer(m *tikv.MemDB) kv.MemBuffer {
+func newMemBuffer(m *tikv.MemDB, savepoints *savepoints) kv.MemBuffer {
 	if m == nil {
 		return nil
 	}
-	return &memBuffer{MemDB: m}
+	return &memBuffer{MemDB: m, savepoints: savepoints}
 }
 
 func (m *memBuffer) Size() int {
@@ -40,10 +41,12 @@ func (m *memBuffer) Size() int {
 }
 
 func (m *memBuffer) Delete(k kv.Key) error {
+	m.savepoints.recordKey(m.MemDB, k)
 	return m.MemDB.Delete(k)
 }
 
 func (m *memBuffer) DeleteWithFlags(k kv.Key, ops ...kv.FlagsOp) error {
+	m.savepoints.recordKey(m.MemDB, k)
 	err := m.MemDB.DeleteWithFlags(k, getTiKVFlagsOps(ops)...)
 	return derr.ToTiDBErr(err)
 }
@@ -78,11 +81,13 @@ func (m *memBuffer) InspectStage(handle kv.StagingHandle, f func(kv.Key, kv.KeyF
 }
 
 func (m *memBuffer) Set(key kv.Key, value []byte) error {
+	m.savepoints.recordKey(m.MemDB, key)
 	err := m.MemDB.Set(key, value)
 	return derr.ToTiDBErr(err)
 }
 
 func (m *memBuffer) SetWithFlags(key kv.Key, value []byte, ops ...kv.FlagsOp) error {
+	m.savepoints.recordKey(m.MemDB, key)
 	err := m.MemDB.SetWithFlags(key, value, getTiKVFlagsOps(ops)...)
 	return derr.ToTiDBErr(err)
 }
@@ -1900,10 +1900,22 @@ func (w *GCWorker) doGCPlacementRules(dr util.DelRangeTask) (err error) {
 			return
 		}
 		physicalTableIDs = append(physicalTableIDs, historyJob.TableID)
-	case model.ActionDropSchema, model.ActionDropTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionDropSchema, model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition:
 		if err = historyJob.DecodeArgs(&physicalTableIDs); err != nil {
 			return
 		}
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		var ids []int64
+		if err = historyJob.DecodeArgs(&ids); err != nil {
+			return
+		}
+		// The table ID is in the IDs when the table is converted from or to a non-partitioned table,
+		// the placement rules of the table are still used.
+		for _, id := range ids {
+			if id != historyJob.TableID {
+				physicalTableIDs = append(physicalTableIDs, id)
+			}
+		}
 	}
 
 	if len(physicalTableIDs) == 0 {
@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta and map dependency describing a table check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	ConstraintExpr expression.Expression
+}
+
+// ToConstraint converts model.ConstraintInfo to Constraint. The expression is
+// resolved against the public columns of tblInfo, so it can be evaluated on a
+// row whose datums are ordered by the column offsets.
+func ToConstraint(sctx sessionctx.Context, constraintInfo *model.ConstraintInfo, tblInfo *model.TableInfo) (*Constraint, error) {
+	expr, err := expression.ParseSimpleExprWithTableInfo(sctx, constraintInfo.ExprString, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Constraint{
+		ConstraintInfo: constraintInfo,
+		ConstraintExpr: expr,
+	}, nil
+}
+
+// IsWritableConstraint returns whether the constraint should be checked by the write path.
+func IsWritableConstraint(constraintInfo *model.ConstraintInfo) bool {
+	if !constraintInfo.Enforced {
+		return false
+	}
+	switch constraintInfo.State {
+	case model.StateWriteOnly, model.StateWriteReorganization, model.StatePublic:
+		return true
+	}
+	return false
+}
+
+// CheckRowConstraint verifies that row satisfies all the given check constraints.
+// A constraint is only violated when it evaluates to FALSE, NULL counts as satisfied.
+func CheckRowConstraint(sctx sessionctx.Context, constraints []*Constraint, row []types.Datum) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	chkRow := chunk.MutRowFromDatums(row).ToRow()
+	for _, constraint := range constraints {
+		val, isNull, err := constraint.ConstraintExpr.EvalInt(sctx, chkRow)
+		if err != nil {
+			return err
+		}
+		if !isNull && val == 0 {
+			return ErrCheckConstraintViolated.FastGenByArgs(constraint.Name.O)
+		}
+	}
+	return nil
+}
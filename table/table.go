@@ -104,6 +104,10 @@ var (
 	ErrTempTableFull = dbterror.ClassTable.NewStd(mysql.ErrRecordFileFull)
 	// ErrOptOnCacheTable returns when exec unsupported opt at cache mode
 	ErrOptOnCacheTable = dbterror.ClassDDL.NewStd(mysql.ErrOptOnCacheTable)
+	// ErrCheckConstraintViolated returns when the written row violates a check constraint.
+	ErrCheckConstraintViolated = dbterror.ClassTable.NewStd(mysql.ErrCheckConstraintViolated)
+	// ErrExchangePartitionInProgress returns when the written table is being exchanged with a partition.
+	ErrExchangePartitionInProgress = dbterror.ClassTable.NewStd(mysql.ErrExchangePartitionInProgress)
 )
 
 // RecordIterFunc is used for low-level record iteration.
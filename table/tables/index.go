@@ -145,7 +145,11 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
-		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
+		if c.idxInfo.Global {
+			txn.CacheTableInfo(c.tblInfo.ID, c.tblInfo)
+		} else {
+			txn.CacheTableInfo(c.phyTblID, c.tblInfo)
+		}
 	}
 	var opt table.CreateIdxOpt
 	for _, fn := range opts {
@@ -198,6 +202,14 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		return nil, err
 	}
 
+	if c.idxInfo.BackfillState != model.BackfillStateInapplicable {
+		// The index is being added by ingesting, the untouched entries are already handled by the backfill.
+		if opt.Untouched {
+			return nil, nil
+		}
+		return nil, c.writeTempIndex(txn, key, idxVal, false)
+	}
+
 	if !distinct || skipCheck || opt.Untouched {
 		err = txn.GetMemBuffer().Set(key, idxVal)
 		return nil, err
@@ -213,11 +225,13 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		ctx = context.TODO()
 	}
 
+	// The existing entry of a global index may be stale, so it can't be checked lazily.
+	lazyCheck := sctx.GetSessionVars().LazyCheckKeyNotExists() && !c.idxInfo.Global
 	var value []byte
 	if c.tblInfo.TempTableType != model.TempTableNone {
 		// Always check key for temporary table because it does not write to TiKV
 		value, err = txn.Get(ctx, key)
-	} else if sctx.GetSessionVars().LazyCheckKeyNotExists() {
+	} else if lazyCheck {
 		value, err = txn.GetMemBuffer().Get(ctx, key)
 	} else {
 		value, err = txn.Get(ctx, key)
@@ -226,13 +240,23 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		return nil, err
 	}
 	if err != nil || len(value) == 0 {
-		if sctx.GetSessionVars().LazyCheckKeyNotExists() && err != nil {
+		if lazyCheck && err != nil {
 			err = txn.GetMemBuffer().SetWithFlags(key, idxVal, kv.SetPresumeKeyNotExists)
 		} else {
 			err = txn.GetMemBuffer().Set(key, idxVal)
 		}
 		return nil, err
 	}
+	if c.idxInfo.Global {
+		stale, err := IsStaleGlobalIndexValue(c.tblInfo, value)
+		if err != nil {
+			return nil, err
+		}
+		// The entry of a dropped or truncated partition is waiting for being cleaned up, it can be overwritten.
+		if stale {
+			return nil, txn.GetMemBuffer().Set(key, idxVal)
+		}
+	}
 
 	handle, err := tablecodec.DecodeHandleInUniqueIndexValue(value, c.tblInfo.IsCommonHandle)
 	if err != nil {
@@ -241,12 +265,46 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 	return handle, kv.ErrKeyExists
 }
 
+// IsStaleGlobalIndexValue checks whether the value of a global index entry points to a partition
+// which is not in the table any more, e.g. the partition has been dropped, truncated or exchanged.
+// The partitions in DroppingDefinitions are regarded as removed, even if they're still in the
+// Definitions while they're reorganized.
+func IsStaleGlobalIndexValue(tblInfo *model.TableInfo, value []byte) (bool, error) {
+	pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(value)
+	if err != nil || !ok {
+		return false, err
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return false, nil
+	}
+	for _, def := range pi.DroppingDefinitions {
+		if def.ID == pid {
+			return true, nil
+		}
+	}
+	for _, def := range pi.Definitions {
+		if def.ID == pid {
+			return false, nil
+		}
+	}
+	for _, def := range pi.AddingDefinitions {
+		if def.ID == pid {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
 	}
+	if c.idxInfo.BackfillState != model.BackfillStateInapplicable {
+		return c.writeTempIndex(txn, key, nil, true)
+	}
 	if distinct {
 		err = txn.GetMemBuffer().DeleteWithFlags(key, kv.SetNeedLocked)
 	} else {
@@ -255,6 +313,26 @@ func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexed
 	return err
 }
 
+// writeTempIndex writes the change of an index which is being added by ingesting to its temporary index,
+// the changes are merged into the index after the backfill is done. Once the backfill is done, the change
+// is written to both the temporary index and the index.
+func (c *index) writeTempIndex(txn kv.Transaction, key []byte, idxVal []byte, isDelete bool) error {
+	tempKey := make([]byte, len(key))
+	copy(tempKey, key)
+	tablecodec.IndexKey2TempIndexKey(c.idxInfo.ID, tempKey)
+	err := txn.GetMemBuffer().Set(tempKey, tablecodec.EncodeTempIndexValue(idxVal, isDelete))
+	if err != nil {
+		return err
+	}
+	if c.idxInfo.BackfillState == model.BackfillStateRunning {
+		return nil
+	}
+	if isDelete {
+		return txn.GetMemBuffer().Delete(key)
+	}
+	return txn.GetMemBuffer().Set(key, idxVal)
+}
+
 // Drop removes the KV index from store.
 func (c *index) Drop(txn kv.Transaction) error {
 	it, err := txn.Iter(c.prefix, c.prefix.PrefixNext())
@@ -389,3 +389,33 @@ func buildTableInfo(t *testing.T, sql string) *model.TableInfo {
 	require.NoError(t, err)
 	return tblInfo
 }
+
+func TestIsStaleGlobalIndexValue(t *testing.T) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	idxInfo := &model.IndexInfo{ID: 2, Unique: true, Global: true}
+	tblInfo := &model.TableInfo{
+		ID:      1,
+		Indices: []*model.IndexInfo{idxInfo},
+		Partition: &model.PartitionInfo{
+			Enable:              true,
+			Definitions:         []model.PartitionDefinition{{ID: 10}, {ID: 11}},
+			AddingDefinitions:   []model.PartitionDefinition{{ID: 12}},
+			DroppingDefinitions: []model.PartitionDefinition{{ID: 11}},
+		},
+	}
+	for _, c := range []struct {
+		pid   int64
+		stale bool
+	}{
+		{10, false},
+		{11, true},
+		{12, false},
+		{13, true},
+	} {
+		val, err := tablecodec.GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, false, []types.Datum{types.NewIntDatum(1)}, kv.IntHandle(1), c.pid, nil)
+		require.NoError(t, err)
+		stale, err := tables.IsStaleGlobalIndexValue(tblInfo, val)
+		require.NoError(t, err)
+		require.Equal(t, c.stale, stale, "partition %d", c.pid)
+	}
+}
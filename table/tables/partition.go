@@ -81,6 +81,12 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+
+	// reorganizePartitions is the set of the partitions being reorganized by REORGANIZE PARTITION,
+	// ALTER TABLE ... PARTITION BY or REMOVE PARTITIONING, the records written to them are also written to reorgTable, which has the partition layout
+	// on the other side of the reorganization, so that the sessions on both schema versions see them.
+	reorganizePartitions map[int64]struct{}
+	reorgTable           *partitionedTable
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -107,12 +113,75 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		t.Constraints = tbl.Constraints
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if pi.DDLState != model.StateNone {
+		from, _ := reorganizedDefinitions(pi)
+		ret.reorganizePartitions = make(map[int64]struct{}, len(from))
+		for _, def := range from {
+			ret.reorganizePartitions[def.ID] = struct{}{}
+		}
+		reorgInfo := ReorganizedTableInfo(tblInfo)
+		reorgTbl := *tbl
+		reorgTbl.meta = reorgInfo
+		t, err := newPartitionedTable(&reorgTbl, reorgInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret.reorgTable = t.(*partitionedTable)
+	}
 	return ret, nil
 }
 
+// reorganizedDefinitions returns the partitions being reorganized in the current partition layout,
+// and the ones which replace them in the other layout. The new partitions replace the old ones in
+// the Definitions when the reorganization reaches the DeleteReorganization state.
+func reorganizedDefinitions(pi *model.PartitionInfo) (from, to []model.PartitionDefinition) {
+	if pi.DDLState == model.StateDeleteReorganization {
+		return pi.AddingDefinitions, pi.DroppingDefinitions
+	}
+	return pi.DroppingDefinitions, pi.AddingDefinitions
+}
+
+// ReorganizedTableInfo returns a copy of the table info with the partition layout on the other
+// side of the reorganization in progress, that is, the new layout before the partitions are
+// swapped, and the old layout after that.
+func ReorganizedTableInfo(tblInfo *model.TableInfo) *model.TableInfo {
+	pi := *tblInfo.Partition
+	from, to := reorganizedDefinitions(&pi)
+	pi.Type, pi.Expr, pi.Columns = pi.DDLType, pi.DDLExpr, pi.DDLColumns
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)-len(from)+len(to))
+	for _, def := range pi.Definitions {
+		if len(from) > 0 && def.ID == from[0].ID {
+			defs = append(defs, to...)
+		}
+		if !hasPartitionDefinition(from, def.ID) {
+			defs = append(defs, def)
+		}
+	}
+	pi.Definitions = defs
+	pi.Num = uint64(len(defs))
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.DDLState = model.StateNone
+	pi.DDLAction = model.ActionNone
+	pi.DDLType, pi.DDLExpr, pi.DDLColumns = model.PartitionTypeNone, "", nil
+	info := tblInfo.Clone()
+	info.Partition = &pi
+	return info
+}
+
+func hasPartitionDefinition(defs []model.PartitionDefinition, id int64) bool {
+	for i := range defs {
+		if defs[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -128,6 +197,11 @@ func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 		return generateHashPartitionExpr(ctx, pi, columns, names)
 	case model.PartitionTypeList:
 		return generateListPartitionExpr(ctx, tblInfo, columns, names)
+	case model.PartitionTypeKey:
+		return generateKeyPartitionExpr(pi, columns, names)
+	case model.PartitionTypeNone:
+		// The records are all in the only partition.
+		return &PartitionExpr{}, nil
 	}
 	panic("cannot reach here")
 }
@@ -149,6 +223,8 @@ type PartitionExpr struct {
 	// InValues: x in (1,2); x in (3,4); x in (5,6), used for list partition.
 	InValues []expression.Expression
 	*ForListPruning
+	// Used in the key partition pruning process.
+	*ForKeyPruning
 }
 
 func initEvalBufferType(t *partitionedTable) {
@@ -898,6 +974,23 @@ func generateHashPartitionExpr(ctx sessionctx.Context, pi *model.PartitionInfo,
 	}, nil
 }
 
+func generateKeyPartitionExpr(pi *model.PartitionInfo, columns []*expression.Column, names types.NameSlice) (*PartitionExpr, error) {
+	keyPartCols := make([]*expression.Column, 0, len(pi.Columns))
+	offset := make([]int, 0, len(pi.Columns))
+	for _, col := range pi.Columns {
+		idx := expression.FindFieldNameIdxByColName(names, col.L)
+		if idx < 0 {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(col.L)
+		}
+		keyPartCols = append(keyPartCols, columns[idx])
+		offset = append(offset, idx)
+	}
+	return &PartitionExpr{
+		ForKeyPruning: &ForKeyPruning{KeyPartCols: keyPartCols},
+		ColumnOffset:  offset,
+	}, nil
+}
+
 // PartitionExpr returns the partition expression.
 func (t *partitionedTable) PartitionExpr() (*PartitionExpr, error) {
 	return t.partitionExpr, nil
@@ -924,6 +1017,10 @@ func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.Par
 		idx, err = t.locateHashPartition(ctx, pi, r)
 	case model.PartitionTypeList:
 		idx, err = t.locateListPartition(ctx, pi, r)
+	case model.PartitionTypeKey:
+		idx, err = t.locateKeyPartition(pi, r)
+	case model.PartitionTypeNone:
+		idx = 0
 	}
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -1076,6 +1173,15 @@ func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model
 	return int(ret), nil
 }
 
+func (t *partitionedTable) locateKeyPartition(pi *model.PartitionInfo, r []types.Datum) (int, error) {
+	kp := t.partitionExpr.ForKeyPruning
+	keyVals := make([]types.Datum, len(kp.KeyPartCols))
+	for i, col := range kp.KeyPartCols {
+		keyVals[i] = r[col.Index]
+	}
+	return kp.LocateKeyPartition(pi.Num, keyVals)
+}
+
 // GetPartition returns a Table, which is actually a partition.
 func (t *partitionedTable) GetPartition(pid int64) table.PhysicalTable {
 	// Attention, can't simply use `return t.partitions[pid]` here.
@@ -1126,7 +1232,53 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := t.reorganizePartitions[pid]; ok && t.meta.Partition.DDLState != model.StateDeleteOnly {
+		err = t.reorgTable.addReorganizedRecord(ctx, recordID, r, opts)
+	}
+	return recordID, errors.Trace(err)
+}
+
+// addReorganizedRecord adds the record written to a partition being reorganized to the partition
+// of the reorganized layout, with the same handle.
+func (t *partitionedTable) addReorganizedRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum, opts []table.AddRecordOption) error {
+	pid, err := t.locatePartition(ctx, t.meta.Partition, r)
+	if err != nil {
+		// The old layout may not cover the records written by the new layout, such as the range of the
+		// last partition is extended, the sessions with the old layout can't see them anyway.
+		if table.ErrNoPartitionForGivenValue.Equal(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	cols := t.Cols()
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
+		// Append the handle as _tidb_rowid, so the record has the same handle in both layouts.
+		r = append(r[:len(cols):len(cols)], types.NewIntDatum(h.IntValue()))
+	}
+	addOpts := make([]table.AddRecordOption, 0, len(opts))
+	for _, opt := range opts {
+		if opt != table.IsUpdate {
+			addOpts = append(addOpts, opt)
+		}
+	}
+	_, err = t.GetPartition(pid).AddRecord(ctx, r, addOpts...)
+	return errors.Trace(err)
+}
+
+// removeReorganizedRecord removes the record from the partition of the reorganized layout.
+func (t *partitionedTable) removeReorganizedRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	pid, err := t.locatePartition(ctx, t.meta.Partition, r)
+	if err != nil {
+		if table.ErrNoPartitionForGivenValue.Equal(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return errors.Trace(t.GetPartition(pid).RemoveRecord(ctx, h, r))
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1170,7 +1322,13 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	if err = tbl.RemoveRecord(ctx, h, r); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := t.reorganizePartitions[pid]; ok {
+		err = t.reorgTable.removeReorganizedRecord(ctx, h, r)
+	}
+	return errors.Trace(err)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1192,6 +1350,16 @@ func (t *partitionTableWithGivenSets) UpdateRecord(ctx context.Context, sctx ses
 	return partitionedTableUpdateRecord(ctx, sctx, t.partitionedTable, h, currData, newData, touched, t.givenSetPartitions)
 }
 
+// hasGlobalIndex checks whether the table has any global index.
+func hasGlobalIndex(tblInfo *model.TableInfo) bool {
+	for _, idx := range tblInfo.Indices {
+		if idx.Global {
+			return true
+		}
+	}
+	return false
+}
+
 func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context, t *partitionedTable, h kv.Handle, currData, newData []types.Datum, touched []bool, partitionSelection map[int64]struct{}) error {
 	partitionInfo := t.meta.GetPartitionInfo()
 	from, err := t.locatePartition(ctx, partitionInfo, currData)
@@ -1215,26 +1383,59 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
-		if err != nil {
-			return errors.Trace(err)
+		var newHandle kv.Handle
+		if hasGlobalIndex(t.meta) {
+			// The entries of the global indexes are shared by the partitions, the old ones must be
+			// removed before adding the new ones, otherwise the record conflicts with itself.
+			if err = t.GetPartition(from).RemoveRecord(ctx, h, currData); err != nil {
+				return errors.Trace(err)
+			}
+			if newHandle, err = t.GetPartition(to).AddRecord(ctx, newData); err != nil {
+				return errors.Trace(err)
+			}
+		} else {
+			newHandle, err = t.GetPartition(to).AddRecord(ctx, newData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// UpdateRecord should be side effect free, but there're two steps here.
+			// What would happen if step1 succeed but step2 meets error? It's hard
+			// to rollback.
+			// So this special order is chosen: add record first, errors such as
+			// 'Key Already Exists' will generally happen during step1, errors are
+			// unlikely to happen in step2.
+			err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
+			if err != nil {
+				logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
+				return errors.Trace(err)
+			}
 		}
-		// UpdateRecord should be side effect free, but there're two steps here.
-		// What would happen if step1 succeed but step2 meets error? It's hard
-		// to rollback.
-		// So this special order is chosen: add record first, errors such as
-		// 'Key Already Exists' will generally happen during step1, errors are
-		// unlikely to happen in step2.
-		err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
-		if err != nil {
-			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
-			return errors.Trace(err)
+		if _, ok := t.reorganizePartitions[from]; ok {
+			if err = t.reorgTable.removeReorganizedRecord(ctx, h, currData); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if _, ok := t.reorganizePartitions[to]; ok && t.meta.Partition.DDLState != model.StateDeleteOnly {
+			return errors.Trace(t.reorgTable.addReorganizedRecord(ctx, newHandle, newData, nil))
 		}
 		return nil
 	}
 
 	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := t.reorganizePartitions[to]; ok {
+		// The record may move between the partitions of the reorganized layout, so it is
+		// removed and added again instead of being updated in place.
+		if err = t.reorgTable.removeReorganizedRecord(ctx, h, currData); err != nil {
+			return errors.Trace(err)
+		}
+		if t.meta.Partition.DDLState != model.StateDeleteOnly {
+			return errors.Trace(t.reorgTable.addReorganizedRecord(ctx, h, newData, nil))
+		}
+	}
+	return nil
 }
 
 // FindPartitionByName finds partition in table meta by name.
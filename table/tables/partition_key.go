@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+// ForKeyPruning is used for key partition pruning.
+type ForKeyPruning struct {
+	// KeyPartCols is the partitioning columns, the Index of each column is its offset in the row.
+	KeyPartCols []*expression.Column
+}
+
+// LocateKeyPartition returns the index of the partition the values of the partitioning columns belong to.
+func (kp *ForKeyPruning) LocateKeyPartition(numParts uint64, keyVals []types.Datum) (int, error) {
+	fts := make([]*types.FieldType, len(kp.KeyPartCols))
+	for i, col := range kp.KeyPartCols {
+		fts[i] = col.RetType
+	}
+	hash, err := KeyPartitionHash(fts, keyVals)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return int(uint64(hash) % numParts), nil
+}
+
+// IsKeyPartitionColumnType returns whether a column of the type can be used as a KEY partitioning column.
+func IsKeyPartitionColumnType(ft *types.FieldType) bool {
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear,
+		mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal,
+		mysql.TypeDate, mysql.TypeDatetime, mysql.TypeDuration,
+		mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeEnum, mysql.TypeSet:
+		return true
+	}
+	return false
+}
+
+// KeyPartitionHash calculates the hash value of the partitioning columns of a KEY partitioned table.
+// It is compatible with MySQL: each value is hashed in the storage format of MySQL by the hash function
+// of its collation, so the rows are distributed to the same partitions as MySQL does.
+func KeyPartitionHash(fts []*types.FieldType, vals []types.Datum) (uint32, error) {
+	nr1, nr2 := uint64(1), uint64(4)
+	for i, ft := range fts {
+		if vals[i].IsNull() {
+			nr1 ^= (nr1 << 1) | 1
+			continue
+		}
+		b, err := keyPartitionValueBytes(ft, vals[i])
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		for _, c := range b {
+			nr1 ^= (((nr1 & 63) + nr2) * uint64(c)) + (nr1 << 8)
+			nr2 += 3
+		}
+	}
+	return uint32(nr1), nil
+}
+
+// keyPartitionValueBytes returns the bytes of the value to hash, which is the value in the storage format
+// of MySQL, or the sort key of the value for the strings of a case insensitive collation.
+func keyPartitionValueBytes(ft *types.FieldType, d types.Datum) ([]byte, error) {
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		return littleEndianBytes(uint64(d.GetInt64()), intPackLength(ft.Tp)), nil
+	case mysql.TypeYear:
+		year := d.GetInt64()
+		if year != 0 {
+			year -= 1900
+		}
+		return []byte{byte(year)}, nil
+	case mysql.TypeFloat:
+		return littleEndianBytes(uint64(math.Float32bits(float32(d.GetFloat64()))), 4), nil
+	case mysql.TypeDouble:
+		return littleEndianBytes(math.Float64bits(d.GetFloat64()), 8), nil
+	case mysql.TypeNewDecimal:
+		return d.GetMysqlDecimal().ToBin(ft.Flen, ft.Decimal)
+	case mysql.TypeDate:
+		t := d.GetMysqlTime()
+		return littleEndianBytes(uint64(t.Day()+t.Month()*32+t.Year()*16*32), 3), nil
+	case mysql.TypeDatetime:
+		return datetimeBinary(d.GetMysqlTime(), ft.Decimal), nil
+	case mysql.TypeDuration:
+		return durationBinary(d.GetMysqlDuration(), ft.Decimal), nil
+	case mysql.TypeEnum:
+		packLen := 1
+		if len(ft.Elems) >= 256 {
+			packLen = 2
+		}
+		return littleEndianBytes(d.GetMysqlEnum().Value, packLen), nil
+	case mysql.TypeSet:
+		packLen := (len(ft.Elems) + 7) / 8
+		if packLen > 4 {
+			packLen = 8
+		}
+		return littleEndianBytes(d.GetMysqlSet().Value, packLen), nil
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString:
+		return stringKeyPartitionBytes(ft, d.GetBytes()), nil
+	}
+	return nil, errors.Errorf("unsupported type %s for key partitioning", types.TypeStr(ft.Tp))
+}
+
+func stringKeyPartitionBytes(ft *types.FieldType, b []byte) []byte {
+	if ft.Charset == charset.CharsetBin {
+		// BINARY(n) is padded with zeros to the full length like the stored value of MySQL.
+		if ft.Tp == mysql.TypeString && len(b) < ft.Flen {
+			padded := make([]byte, ft.Flen)
+			copy(padded, b)
+			return padded
+		}
+		return b
+	}
+	str := strings.TrimRight(string(b), " ")
+	if !collate.NewCollationEnabled() || collate.IsBinCollation(ft.Collate) {
+		return []byte(str)
+	}
+	key := collate.GetCollator(ft.Collate).Key(str)
+	if ft.Collate == "utf8mb4_general_ci" || ft.Collate == "utf8_general_ci" {
+		// The weights of general_ci are hashed as the low byte first by MySQL.
+		for i := 0; i+1 < len(key); i += 2 {
+			key[i], key[i+1] = key[i+1], key[i]
+		}
+	}
+	return key
+}
+
+func intPackLength(tp byte) int {
+	switch tp {
+	case mysql.TypeTiny:
+		return 1
+	case mysql.TypeShort:
+		return 2
+	case mysql.TypeInt24:
+		return 3
+	case mysql.TypeLong:
+		return 4
+	}
+	return 8
+}
+
+func littleEndianBytes(v uint64, n int) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b[:n]
+}
+
+// bigEndianBytes returns the lowest n bytes of v in big endian.
+func bigEndianBytes(v uint64, n int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b[8-n:]
+}
+
+// appendFracBytes appends the fractional part of a datetime or time value as MySQL stores it.
+func appendFracBytes(b []byte, frac int64, fsp int) []byte {
+	switch fsp {
+	case 1, 2:
+		return append(b, byte(frac/10000))
+	case 3, 4:
+		return append(b, bigEndianBytes(uint64(frac/100), 2)...)
+	case 5, 6:
+		return append(b, bigEndianBytes(uint64(frac), 3)...)
+	}
+	return b
+}
+
+// datetimeBinary returns the DATETIME value in the binary format of MySQL 5.6.4 and later.
+func datetimeBinary(t types.Time, fsp int) []byte {
+	ymd := uint64((t.Year()*13+t.Month())<<5 | t.Day())
+	hms := uint64(t.Hour()<<12 | t.Minute()<<6 | t.Second())
+	b := bigEndianBytes((ymd<<17|hms)+0x8000000000, 5)
+	return appendFracBytes(b, int64(t.Microsecond()), fsp)
+}
+
+// durationBinary returns the TIME value in the binary format of MySQL 5.6.4 and later.
+func durationBinary(d types.Duration, fsp int) []byte {
+	hms := int64(d.Hour()<<12 | d.Minute()<<6 | d.Second())
+	packed := hms<<24 + int64(d.MicroSecond())
+	if d.Duration < 0 {
+		packed = -packed
+	}
+	if fsp >= 5 {
+		return bigEndianBytes(uint64(packed+0x800000000000), 6)
+	}
+	b := bigEndianBytes(uint64((packed>>24)+0x800000), 3)
+	return appendFracBytes(b, packed%(1<<24), fsp)
+}
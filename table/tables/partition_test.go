@@ -16,17 +16,20 @@ package tables_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/pingcap/tidb/ddl"
 	mysql "github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -608,3 +611,79 @@ func TestIssue24746(t *testing.T) {
 	err = tk.ExecToErr("insert into t_24746 partition (p1) values(4,'ERROR, not allowed to read from partition p0',4) on duplicate key update a = a + 1, b = 'ERROR, not allowed to read from p0!'")
 	require.True(t, table.ErrRowDoesNotMatchGivenPartitionSet.Equal(err))
 }
+
+func TestKeyPartitionHash(t *testing.T) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	hash := func(ft *types.FieldType, d types.Datum) uint32 {
+		h, err := tables.KeyPartitionHash([]*types.FieldType{ft}, []types.Datum{d})
+		require.NoError(t, err)
+		return h
+	}
+	intType := types.NewFieldType(tmysql.TypeLong)
+	// The hash of NULL is the initial value of the hash mixed once.
+	require.Equal(t, uint32(2), hash(intType, types.NewDatum(nil)))
+	require.NotEqual(t, hash(intType, types.NewIntDatum(1)), hash(intType, types.NewIntDatum(2)))
+	// The value is hashed in its storage format, so the hash depends on the column type.
+	require.NotEqual(t, hash(intType, types.NewIntDatum(1)), hash(types.NewFieldType(tmysql.TypeTiny), types.NewIntDatum(1)))
+
+	// The strings equal in the collation have the same hash.
+	ciType := types.NewFieldType(tmysql.TypeVarchar)
+	ciType.Charset, ciType.Collate = "utf8mb4", "utf8mb4_general_ci"
+	require.Equal(t, hash(ciType, types.NewStringDatum("abc")), hash(ciType, types.NewStringDatum("ABC  ")))
+	binType := types.NewFieldType(tmysql.TypeVarchar)
+	binType.Charset, binType.Collate = "utf8mb4", "utf8mb4_bin"
+	require.Equal(t, hash(binType, types.NewStringDatum("abc")), hash(binType, types.NewStringDatum("abc  ")))
+	require.NotEqual(t, hash(binType, types.NewStringDatum("abc")), hash(binType, types.NewStringDatum("ABC")))
+	// BINARY(n) is padded with zeros.
+	binaryType := types.NewFieldType(tmysql.TypeString)
+	binaryType.Charset, binaryType.Collate, binaryType.Flen = "binary", "binary", 4
+	require.Equal(t, hash(binaryType, types.NewBytesDatum([]byte("ab"))), hash(binaryType, types.NewBytesDatum([]byte("ab\x00\x00"))))
+
+	// All the columns are hashed.
+	h1, err := tables.KeyPartitionHash([]*types.FieldType{intType, binType}, []types.Datum{types.NewIntDatum(1), types.NewStringDatum("a")})
+	require.NoError(t, err)
+	h2, err := tables.KeyPartitionHash([]*types.FieldType{intType, binType}, []types.Datum{types.NewIntDatum(1), types.NewStringDatum("b")})
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h2)
+}
+
+func TestKeyPartitionAddRecord(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(10), c datetime(3), d decimal(10, 2), key(a)) partition by key(a, b, c, d) partitions 5")
+	tb, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	pi := tb.Meta().Partition
+	require.Equal(t, model.PartitionTypeKey, pi.Type)
+	require.Equal(t, []model.CIStr{model.NewCIStr("a"), model.NewCIStr("b"), model.NewCIStr("c"), model.NewCIStr("d")}, pi.Columns)
+
+	for i := 0; i < 20; i++ {
+		tk.MustExec("insert into t values (?, ?, ?, ?)", i, fmt.Sprintf("b%d", i), fmt.Sprintf("2021-01-%02d 10:00:00.123", i+1), float64(i)/4)
+	}
+	tk.MustExec("insert into t values (null, null, null, null)")
+	tk.MustExec("admin check table t")
+	// The rows are located by the same hash when they are read back.
+	total := 0
+	for _, def := range pi.Definitions {
+		rows := tk.MustQuery(fmt.Sprintf("select a, b, c, d from t partition (%s)", def.Name.O)).Rows()
+		for _, row := range rows {
+			datums := make([]types.Datum, len(row))
+			for i, v := range types.MakeDatums(row...) {
+				if v.GetString() == "<nil>" {
+					continue
+				}
+				datums[i], err = v.ConvertTo(tk.Session().GetSessionVars().StmtCtx, &tb.Meta().Columns[i].FieldType)
+				require.NoError(t, err)
+			}
+			pt, err := tb.(table.PartitionedTable).GetPartitionByRow(tk.Session(), datums)
+			require.NoError(t, err)
+			require.Equal(t, def.ID, pt.GetPhysicalID())
+		}
+		total += len(rows)
+	}
+	require.Equal(t, 21, total)
+}
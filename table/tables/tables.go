@@ -46,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/generatedexpr"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tipb/go-binlog"
@@ -64,6 +65,7 @@ type TableCommon struct {
 	HiddenColumns                   []*table.Column
 	WritableColumns                 []*table.Column
 	FullHiddenColsAndVisibleColumns []*table.Column
+	Constraints                     []*table.Constraint
 	indices                         []table.Index
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
@@ -148,6 +150,7 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs)
+	initTableConstraints(&t)
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
@@ -194,6 +197,28 @@ func initTableIndices(t *TableCommon) error {
 	return nil
 }
 
+// initTableConstraints initializes the check constraints which need to be checked by the write path.
+// A constraint which can't be built is skipped, so that it doesn't fail the loading of the whole schema.
+func initTableConstraints(t *TableCommon) {
+	tblInfo := t.meta
+	if len(tblInfo.Constraints) == 0 {
+		return
+	}
+	ctx := mock.NewContext()
+	for _, constraintInfo := range tblInfo.Constraints {
+		if !table.IsWritableConstraint(constraintInfo) {
+			continue
+		}
+		constraint, err := table.ToConstraint(ctx, constraintInfo, tblInfo)
+		if err != nil {
+			logutil.BgLogger().Warn("skip the invalid check constraint", zap.String("table", tblInfo.Name.O),
+				zap.String("constraint", constraintInfo.Name.O), zap.String("expr", constraintInfo.ExprString), zap.Error(err))
+			continue
+		}
+		t.Constraints = append(t.Constraints, constraint)
+	}
+}
+
 func initTableCommonWithIndices(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators) error {
 	initTableCommon(t, tblInfo, physicalTableID, cols, allocs)
 	return initTableIndices(t)
@@ -334,6 +359,13 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 		return err
 	}
 
+	if err = checkExchangePartitionInProgress(t.meta); err != nil {
+		return err
+	}
+	if err = table.CheckRowConstraint(sctx, t.Constraints, newData); err != nil {
+		return err
+	}
+
 	memBuffer := txn.GetMemBuffer()
 	sh := memBuffer.Staging()
 	defer memBuffer.Cleanup(sh)
@@ -660,6 +692,13 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 		}
 	}
 
+	if err = checkExchangePartitionInProgress(t.meta); err != nil {
+		return nil, err
+	}
+	if err = table.CheckRowConstraint(sctx, t.Constraints, r); err != nil {
+		return nil, err
+	}
+
 	var ctx context.Context
 	if opt.Ctx != nil {
 		ctx = opt.Ctx
@@ -871,6 +910,15 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 }
 
 // genIndexKeyStr generates index content string representation.
+// checkExchangePartitionInProgress rejects the records written to the tables of an EXCHANGE PARTITION
+// which rebuilds global indexes, otherwise they may be duplicated with the records exchanged in.
+func checkExchangePartitionInProgress(tblInfo *model.TableInfo) error {
+	if tblInfo.ExchangePartitionInfo != nil {
+		return table.ErrExchangePartitionInProgress.GenWithStackByArgs(tblInfo.Name.O)
+	}
+	return nil
+}
+
 func genIndexKeyStr(colVals []types.Datum) (string, error) {
 	// Pass pre-composed error to txn.
 	strVals := make([]string, 0, len(colVals))
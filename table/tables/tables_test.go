@@ -417,6 +417,17 @@ func TestTableFromMeta(t *testing.T) {
 
 	_, err = tables.AllocHandle(context.Background(), tk.Session(), tb)
 	require.Error(t, err)
+
+	// The invalid check constraint is skipped instead of failing the loading of the table.
+	tk.MustExec("create table t_check (a int check (a > 0), b int check (b > 0))")
+	tb, err = dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t_check"))
+	require.NoError(t, err)
+	tbInfo = tb.Meta().Clone()
+	require.Len(t, tbInfo.Constraints, 2)
+	tbInfo.Constraints[0].ExprString = "c > 0"
+	tb, err = tables.TableFromMeta(nil, tbInfo)
+	require.NoError(t, err)
+	require.Len(t, tb.(*tables.TableCommon).Constraints, 1)
 }
 
 func TestShardRowIDBitsStep(t *testing.T) {
@@ -1035,6 +1035,61 @@ func GetTableIndexKeyRange(tableID, indexID int64) (startKey, endKey []byte) {
 	return
 }
 
+// TempIndexPrefix is used to generate the ID of the temporary index, which keeps the index entries written by
+// the DML when the index is added by ingesting.
+const TempIndexPrefix = 0x7fff000000000000
+
+const (
+	tempIndexValueFlagNormal  byte = 'n'
+	tempIndexValueFlagDeleted byte = 'd'
+)
+
+// IndexKey2TempIndexKey converts the index key to the key of its temporary index in place.
+func IndexKey2TempIndexKey(indexID int64, key []byte) {
+	eid := codec.EncodeIntToCmpUint(TempIndexPrefix | indexID)
+	binary.BigEndian.PutUint64(key[prefixLen:], eid)
+}
+
+// TempIndexKey2IndexKey converts the key of the temporary index to the index key in place.
+func TempIndexKey2IndexKey(indexID int64, tempKey []byte) {
+	eid := codec.EncodeIntToCmpUint(indexID)
+	binary.BigEndian.PutUint64(tempKey[prefixLen:], eid)
+}
+
+// IsTempIndexKey checks whether the index key belongs to a temporary index.
+func IsTempIndexKey(indexKey []byte) bool {
+	if !IsIndexKey(indexKey) || len(indexKey) < prefixLen+idLen {
+		return false
+	}
+	indexID := codec.DecodeCmpUintToInt(binary.BigEndian.Uint64(indexKey[prefixLen:]))
+	return indexID&TempIndexPrefix == TempIndexPrefix
+}
+
+// EncodeTempIndexValue encodes the value of the temporary index, isDelete means the index entry is deleted.
+func EncodeTempIndexValue(value []byte, isDelete bool) []byte {
+	if isDelete {
+		return []byte{tempIndexValueFlagDeleted}
+	}
+	tempVal := make([]byte, 0, len(value)+1)
+	tempVal = append(tempVal, tempIndexValueFlagNormal)
+	return append(tempVal, value...)
+}
+
+// DecodeTempIndexValue decodes the value of the temporary index to the index value.
+func DecodeTempIndexValue(tempVal []byte) (value []byte, isDelete bool, err error) {
+	if len(tempVal) == 0 {
+		return nil, false, errors.New("invalid temporary index value")
+	}
+	switch tempVal[0] {
+	case tempIndexValueFlagDeleted:
+		return nil, true, nil
+	case tempIndexValueFlagNormal:
+		return tempVal[1:], false, nil
+	default:
+		return nil, false, errors.Errorf("invalid temporary index value flag %d", tempVal[0])
+	}
+}
+
 // GetIndexKeyBuf reuse or allocate buffer
 func GetIndexKeyBuf(buf []byte, defaultCap int) []byte {
 	if buf != nil {
@@ -1371,6 +1426,28 @@ func DecodeHandleInUniqueIndexValue(data []byte, isCommonHandle bool) (kv.Handle
 	return h, nil
 }
 
+// DecodePartitionIDInIndexValue decodes the partition ID in the value of a global index.
+// It returns false if the value does not contain a partition ID.
+func DecodePartitionIDInIndexValue(data []byte) (int64, bool, error) {
+	if len(data) <= MaxOldEncodeValueLen {
+		return 0, false, nil
+	}
+	var segs IndexValueSegments
+	if getIndexVersion(data) == 1 {
+		segs = SplitIndexValueForClusteredIndexVersion1(data)
+	} else {
+		segs = SplitIndexValue(data)
+	}
+	if segs.PartitionID == nil {
+		return 0, false, nil
+	}
+	_, pid, err := codec.DecodeInt(segs.PartitionID)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	return pid, true, nil
+}
+
 func encodePartitionID(idxVal []byte, partitionID int64) []byte {
 	idxVal = append(idxVal, PartitionIDFlag)
 	idxVal = codec.EncodeInt(idxVal, partitionID)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -580,3 +581,55 @@ func TestUntouchedIndexKValue(t *testing.T) {
 	untouchedIndexValue := []byte{0, 0, 0, 0, 0, 0, 0, 1, 49}
 	require.True(t, IsUntouchedIndexKValue(untouchedIndexKey, untouchedIndexValue))
 }
+
+func TestDecodePartitionIDInIndexValue(t *testing.T) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	tblInfo := &model.TableInfo{ID: 1}
+	idxInfo := &model.IndexInfo{ID: 2, Unique: true, Global: true}
+	val, err := GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, false, []types.Datum{types.NewIntDatum(10)}, kv.IntHandle(100), 42, nil)
+	require.NoError(t, err)
+	pid, ok, err := DecodePartitionIDInIndexValue(val)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(42), pid)
+	h, err := DecodeHandleInUniqueIndexValue(val, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), h.IntValue())
+
+	idxInfo.Global = false
+	val, err = GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, false, []types.Datum{types.NewIntDatum(10)}, kv.IntHandle(100), 42, nil)
+	require.NoError(t, err)
+	_, ok, err = DecodePartitionIDInIndexValue(val)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestTempIndexKeyValue(t *testing.T) {
+	tableID := int64(4)
+	indexID := int64(5)
+	key := EncodeIndexSeekKey(tableID, indexID, []byte("abc"))
+	require.False(t, IsTempIndexKey(key))
+
+	tempKey := make([]byte, len(key))
+	copy(tempKey, key)
+	IndexKey2TempIndexKey(indexID, tempKey)
+	require.True(t, IsTempIndexKey(tempKey))
+	tTableID, tIndexID, isRecordKey, err := DecodeKeyHead(tempKey)
+	require.NoError(t, err)
+	require.Equal(t, tableID, tTableID)
+	require.Equal(t, TempIndexPrefix|indexID, tIndexID)
+	require.False(t, isRecordKey)
+	TempIndexKey2IndexKey(indexID, tempKey)
+	require.Equal(t, []byte(key), tempKey)
+
+	val := []byte{0, 1, 2}
+	origin, isDelete, err := DecodeTempIndexValue(EncodeTempIndexValue(val, false))
+	require.NoError(t, err)
+	require.False(t, isDelete)
+	require.Equal(t, val, origin)
+	_, isDelete, err = DecodeTempIndexValue(EncodeTempIndexValue(nil, true))
+	require.NoError(t, err)
+	require.True(t, isDelete)
+	_, _, err = DecodeTempIndexValue(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,452 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/copr"
+	"github.com/pingcap/tidb/store/driver/backoff"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// job deletes the expired rows of a physical table. The record key range of the table
+// is split by the regions, and the ranges are scanned and deleted by the workers concurrently.
+type job struct {
+	m          *JobManager
+	id         string
+	dbName     model.CIStr
+	tblInfo    *model.TableInfo
+	partition  *model.PartitionDefinition
+	physicalID int64
+	// handleCols are the columns of the handle, which are used to locate the expired rows.
+	handleCols []*model.ColumnInfo
+	expire     string
+
+	rangeCount         int
+	finishedRangeCount atomic.Int64
+	scannedRows        atomic.Int64
+	deletedRows        atomic.Int64
+	errorDeleteRows    atomic.Int64
+	progressMu         sync.Mutex
+}
+
+// handleRange is the range [start, end) of the handles. A nil bound means unbounded.
+type handleRange struct {
+	start []types.Datum
+	end   []types.Datum
+	// startExclusive indicates the start bound is excluded.
+	startExclusive bool
+}
+
+func newJob(m *JobManager, dbName model.CIStr, tblInfo *model.TableInfo, partition *model.PartitionDefinition) *job {
+	j := &job{
+		m:          m,
+		id:         uuid.New().String(),
+		dbName:     dbName,
+		tblInfo:    tblInfo,
+		partition:  partition,
+		physicalID: tblInfo.ID,
+	}
+	if partition != nil {
+		j.physicalID = partition.ID
+	}
+	switch {
+	case tblInfo.PKIsHandle:
+		j.handleCols = []*model.ColumnInfo{tblInfo.GetPkColInfo()}
+	case tblInfo.IsCommonHandle:
+		pk := tables.FindPrimaryIndex(tblInfo)
+		for _, idxCol := range pk.Columns {
+			j.handleCols = append(j.handleCols, tblInfo.Columns[idxCol.Offset])
+		}
+	default:
+		j.handleCols = []*model.ColumnInfo{model.NewExtraHandleColInfo()}
+	}
+	return j
+}
+
+func (j *job) run(ctx context.Context) (err error) {
+	if err = j.m.querySQLInto(ctx, &j.expire, fmt.Sprintf("SELECT CAST(DATE_SUB(NOW(), INTERVAL %s %s) AS CHAR)",
+		strings.ReplaceAll(j.tblInfo.TTLInfo.IntervalExprStr, "%", "%%"), ast.TimeUnitType(j.tblInfo.TTLInfo.IntervalTimeUnit).String())); err != nil {
+		return errors.Trace(err)
+	}
+	ranges, err := j.splitRanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	j.rangeCount = len(ranges)
+
+	var partitionName interface{}
+	if j.partition != nil {
+		partitionName = j.partition.Name.O
+	}
+	if err = j.m.execSQL(ctx, "INSERT INTO mysql.tidb_ttl_job_history (job_id, table_id, parent_table_id, table_schema, table_name, partition_name, ttl_expire, range_count) VALUES (%?, %?, %?, %?, %?, %?, %?, %?)",
+		j.id, j.physicalID, j.tblInfo.ID, j.dbName.O, j.tblInfo.Name.O, partitionName, j.expire, j.rangeCount); err != nil {
+		return errors.Trace(err)
+	}
+	logutil.BgLogger().Info("[ttl] start job", zap.String("jobID", j.id), zap.String("table", j.tblInfo.Name.O),
+		zap.Int64("physicalTableID", j.physicalID), zap.String("expire", j.expire), zap.Int("rangeCount", j.rangeCount))
+
+	err = j.runWorkers(ctx, ranges)
+	status := "finished"
+	var errMsg interface{}
+	if err != nil {
+		status, errMsg = "error", err.Error()
+		if ctx.Err() != nil || !j.m.shouldRun() {
+			status = "cancelled"
+		}
+	}
+	// The job must be finished even if the context is cancelled.
+	if finishErr := j.m.execSQL(context.Background(), "UPDATE mysql.tidb_ttl_job_history SET finish_time = NOW(), status = %?, error = %?, finished_range_count = %?, scanned_rows = %?, deleted_rows = %?, error_delete_rows = %? WHERE job_id = %?",
+		status, errMsg, j.finishedRangeCount.Load(), j.scannedRows.Load(), j.deletedRows.Load(), j.errorDeleteRows.Load(), j.id); finishErr != nil && err == nil {
+		err = finishErr
+	}
+	logutil.BgLogger().Info("[ttl] finish job", zap.String("jobID", j.id), zap.String("status", status),
+		zap.Int64("scannedRows", j.scannedRows.Load()), zap.Int64("deletedRows", j.deletedRows.Load()), zap.Error(err))
+	return errors.Trace(err)
+}
+
+func (j *job) runWorkers(ctx context.Context, ranges []handleRange) error {
+	workerCnt := int(variable.TTLScanWorkerCount.Load())
+	if workerCnt > len(ranges) {
+		workerCnt = len(ranges)
+	}
+	rangeCh := make(chan handleRange, len(ranges))
+	for _, r := range ranges {
+		rangeCh <- r
+	}
+	close(rangeCh)
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < workerCnt; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rangeCh {
+				if err := j.scanRange(ctx, r); err != nil {
+					errMu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMu.Unlock()
+					return
+				}
+				j.finishedRangeCount.Inc()
+				j.reportProgress(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// reportProgress updates the progress of the job in the history table.
+func (j *job) reportProgress(ctx context.Context) {
+	j.progressMu.Lock()
+	defer j.progressMu.Unlock()
+	err := j.m.execSQL(ctx, "UPDATE mysql.tidb_ttl_job_history SET finished_range_count = %?, scanned_rows = %?, deleted_rows = %?, error_delete_rows = %? WHERE job_id = %?",
+		j.finishedRangeCount.Load(), j.scannedRows.Load(), j.deletedRows.Load(), j.errorDeleteRows.Load(), j.id)
+	if err != nil {
+		logutil.BgLogger().Warn("[ttl] report job progress failed", zap.String("jobID", j.id), zap.Error(err))
+	}
+}
+
+// scanRange scans the expired rows in the range in batches, and deletes them.
+func (j *job) scanRange(ctx context.Context, r handleRange) error {
+	res, err := j.m.pool.Get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer j.m.pool.Put(res)
+	exec := res.(sqlexec.SQLExecutor)
+	sctx := res.(sessionctx.Context)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		if !j.m.shouldRun() {
+			return errors.New("the TTL job is stopped")
+		}
+		scanBatchSize := int(variable.TTLScanBatchSize.Load())
+		sql, args := j.buildScanSQL(r, scanBatchSize)
+		rows, fields, err := execInSession(ctx, exec, sql, args...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		j.scannedRows.Add(int64(len(rows)))
+		handles := make([][]types.Datum, 0, len(rows))
+		for _, row := range rows {
+			handle := make([]types.Datum, len(fields))
+			for i, field := range fields {
+				handle[i] = row.GetDatum(i, &field.Column.FieldType)
+			}
+			handles = append(handles, handle)
+		}
+		deleteBatchSize := int(variable.TTLDeleteBatchSize.Load())
+		for len(handles) > 0 {
+			batch := handles
+			if len(batch) > deleteBatchSize {
+				batch = batch[:deleteBatchSize]
+			}
+			handles = handles[len(batch):]
+			if err := j.m.waitDelete(ctx); err != nil {
+				return errors.Trace(err)
+			}
+			sql, args := j.buildDeleteSQL(batch)
+			if _, _, err := execInSession(ctx, exec, sql, args...); err != nil {
+				// The failed rows are left to the next job.
+				logutil.BgLogger().Warn("[ttl] delete expired rows failed", zap.String("jobID", j.id), zap.Error(err))
+				j.errorDeleteRows.Add(int64(len(batch)))
+				continue
+			}
+			j.deletedRows.Add(int64(sctx.GetSessionVars().StmtCtx.AffectedRows()))
+		}
+		if len(rows) < scanBatchSize {
+			return nil
+		}
+		// The next batch starts after the last scanned handle.
+		lastRow := rows[len(rows)-1]
+		r.start = make([]types.Datum, len(fields))
+		for i, field := range fields {
+			r.start[i] = lastRow.GetDatum(i, &field.Column.FieldType)
+		}
+		r.startExclusive = true
+	}
+}
+
+// buildScanSQL builds the sql to read the handles of the expired rows in the range.
+func (j *job) buildScanSQL(r handleRange, limit int) (string, []interface{}) {
+	var sb strings.Builder
+	args := make([]interface{}, 0, 8)
+	sb.WriteString("SELECT ")
+	args = j.writeHandleColList(&sb, args)
+	sb.WriteString(" FROM %n.%n")
+	args = append(args, j.dbName.O, j.tblInfo.Name.O)
+	if j.partition != nil {
+		sb.WriteString(" PARTITION (%n)")
+		args = append(args, j.partition.Name.O)
+	}
+	sb.WriteString(" WHERE %n < %?")
+	args = append(args, j.tblInfo.TTLInfo.ColumnName.O, j.expire)
+	if r.start != nil {
+		op := " >= "
+		if r.startExclusive {
+			op = " > "
+		}
+		sb.WriteString(" AND ")
+		args = j.writeHandleCols(&sb, args)
+		sb.WriteString(op)
+		args = writeHandle(&sb, args, r.start)
+	}
+	if r.end != nil {
+		sb.WriteString(" AND ")
+		args = j.writeHandleCols(&sb, args)
+		sb.WriteString(" < ")
+		args = writeHandle(&sb, args, r.end)
+	}
+	sb.WriteString(" ORDER BY ")
+	args = j.writeHandleColList(&sb, args)
+	sb.WriteString(" LIMIT %?")
+	args = append(args, limit)
+	return sb.String(), args
+}
+
+// buildDeleteSQL builds the sql to delete the rows of the handles. The rows are checked
+// again in case they are updated after being scanned.
+func (j *job) buildDeleteSQL(handles [][]types.Datum) (string, []interface{}) {
+	var sb strings.Builder
+	args := make([]interface{}, 0, len(handles)*len(j.handleCols)+8)
+	sb.WriteString("DELETE FROM %n.%n")
+	args = append(args, j.dbName.O, j.tblInfo.Name.O)
+	if j.partition != nil {
+		sb.WriteString(" PARTITION (%n)")
+		args = append(args, j.partition.Name.O)
+	}
+	sb.WriteString(" WHERE ")
+	args = j.writeHandleCols(&sb, args)
+	sb.WriteString(" IN (")
+	for i, handle := range handles {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		args = writeHandle(&sb, args, handle)
+	}
+	sb.WriteString(") AND %n < %?")
+	args = append(args, j.tblInfo.TTLInfo.ColumnName.O, j.expire)
+	return sb.String(), args
+}
+
+// writeHandleCols writes the handle columns as an operand, which is a row if there are multiple columns.
+func (j *job) writeHandleCols(sb *strings.Builder, args []interface{}) []interface{} {
+	if len(j.handleCols) == 1 {
+		return j.writeHandleColList(sb, args)
+	}
+	sb.WriteString("(")
+	args = j.writeHandleColList(sb, args)
+	sb.WriteString(")")
+	return args
+}
+
+func (j *job) writeHandleColList(sb *strings.Builder, args []interface{}) []interface{} {
+	for i, col := range j.handleCols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("%n")
+		args = append(args, col.Name.O)
+	}
+	return args
+}
+
+func writeHandle(sb *strings.Builder, args []interface{}, handle []types.Datum) []interface{} {
+	if len(handle) > 1 {
+		sb.WriteString("(")
+	}
+	for i := range handle {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("%?")
+		args = append(args, datumToArg(&handle[i]))
+	}
+	if len(handle) > 1 {
+		sb.WriteString(")")
+	}
+	return args
+}
+
+// datumToArg converts the datum to the argument of the sql.
+func datumToArg(d *types.Datum) interface{} {
+	switch d.Kind() {
+	case types.KindNull:
+		return nil
+	case types.KindInt64:
+		return d.GetInt64()
+	case types.KindUint64:
+		return d.GetUint64()
+	case types.KindFloat32, types.KindFloat64:
+		return d.GetFloat64()
+	case types.KindString, types.KindBytes:
+		return d.GetString()
+	default:
+		s, err := d.ToString()
+		if err != nil {
+			return nil
+		}
+		return s
+	}
+}
+
+// splitRanges splits the handle space of the table by the regions. The table is scanned
+// as a whole if the region boundaries can't be converted to the handles exactly.
+func (j *job) splitRanges() ([]handleRange, error) {
+	fullRange := []handleRange{{}}
+	if !j.canSplitByHandle() {
+		return fullRange, nil
+	}
+	s, ok := j.m.store.(tikv.Storage)
+	if !ok {
+		// Only support split ranges in tikv.Storage now.
+		return fullRange, nil
+	}
+	startKey := tablecodec.GenTableRecordPrefix(j.physicalID)
+	kvRange := kv.KeyRange{StartKey: startKey, EndKey: startKey.PrefixNext()}
+	maxSleep := 10000 // ms
+	bo := backoff.NewBackofferWithVars(context.Background(), maxSleep, nil)
+	rc := copr.NewRegionCache(s.GetRegionCache())
+	keyRanges, err := rc.SplitRegionRanges(bo, []kv.KeyRange{kvRange})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ranges := make([]handleRange, 0, len(keyRanges))
+	var start []types.Datum
+	for i := 0; i < len(keyRanges)-1; i++ {
+		bound, ok := j.decodeHandle(keyRanges[i].EndKey)
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, handleRange{start: start, end: bound})
+		start = bound
+	}
+	return append(ranges, handleRange{start: start}), nil
+}
+
+// canSplitByHandle returns whether the handles decoded from the record keys can be compared with
+// the handle columns in sql. The string columns with a non-binary collation are encoded to the
+// sort keys, and the time columns are encoded to integers, so they are not supported. The unsigned
+// int handles are encoded as the signed ones, so the order of the keys isn't the order of the
+// handles, and they are not supported either.
+func (j *job) canSplitByHandle() bool {
+	if !j.tblInfo.IsCommonHandle {
+		return !j.tblInfo.PKIsHandle || !mysql.HasUnsignedFlag(j.handleCols[0].Flag)
+	}
+	pk := tables.FindPrimaryIndex(j.tblInfo)
+	for _, idxCol := range pk.Columns {
+		col := j.tblInfo.Columns[idxCol.Offset]
+		if idxCol.Length != types.UnspecifiedLength {
+			return false
+		}
+		if types.IsTypeInteger(col.Tp) {
+			continue
+		}
+		if types.IsString(col.Tp) && (col.Collate == charset.CollationBin || collate.IsBinCollation(col.Collate)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// decodeHandle decodes the handle of the record key.
+func (j *job) decodeHandle(key kv.Key) ([]types.Datum, bool) {
+	handle, err := tablecodec.DecodeRowKey(key)
+	if err != nil || handle.IsInt() == j.tblInfo.IsCommonHandle {
+		return nil, false
+	}
+	if handle.IsInt() {
+		return []types.Datum{types.NewIntDatum(handle.IntValue())}, true
+	}
+	data, err := handle.Data()
+	if err != nil || len(data) != len(j.handleCols) {
+		return nil, false
+	}
+	return data, true
+}
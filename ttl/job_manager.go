@@ -0,0 +1,205 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"context"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/owner"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// Prompt is the prompt for the TTL job owner manager.
+	Prompt = "ttl"
+	// OwnerKey is the TTL job owner path that is saved to etcd.
+	OwnerKey = "/tidb/ttl/owner"
+	// CheckInterval is the interval to check whether there are TTL tables which need to run a job.
+	CheckInterval = time.Minute
+)
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// JobManager runs the jobs which delete the expired rows of the TTL tables.
+// The jobs are only run by the TTL job owner, and every physical table runs a job
+// at most once in its TTL_JOB_INTERVAL. The progress of the jobs is recorded in
+// mysql.tidb_ttl_job_history.
+type JobManager struct {
+	store   kv.Storage
+	pool    sessionPool
+	owner   owner.Manager
+	limiter *rate.Limiter
+}
+
+// NewJobManager creates a new JobManager.
+func NewJobManager(store kv.Storage, pool sessionPool, owner owner.Manager) *JobManager {
+	return &JobManager{
+		store:   store,
+		pool:    pool,
+		owner:   owner,
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+// RunJobsOnce runs a job for every TTL table whose last job is older than its TTL_JOB_INTERVAL,
+// and returns after all the jobs are finished.
+func (m *JobManager) RunJobsOnce(ctx context.Context, is infoschema.InfoSchema) error {
+	if !m.shouldRun() {
+		return nil
+	}
+	// The jobs are run synchronously, so the running jobs left in the history are the
+	// ones interrupted by the restart of the former owner.
+	if err := m.execSQL(ctx, "UPDATE mysql.tidb_ttl_job_history SET status = 'cancelled', finish_time = NOW() WHERE status = 'running'"); err != nil {
+		return errors.Trace(err)
+	}
+	for _, db := range is.AllSchemas() {
+		if util.IsMemOrSysDB(db.Name.L) {
+			continue
+		}
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tblInfo := tbl.Meta()
+			if tblInfo.TTLInfo == nil || !tblInfo.TTLInfo.Enable {
+				continue
+			}
+			interval, err := tblInfo.TTLInfo.GetJobInterval()
+			if err != nil {
+				logutil.BgLogger().Warn("[ttl] invalid job interval", zap.String("table", tblInfo.Name.O), zap.Error(err))
+				continue
+			}
+			if pi := tblInfo.GetPartitionInfo(); pi != nil {
+				for i := range pi.Definitions {
+					m.runJobIfNeeded(ctx, db.Name, tblInfo, &pi.Definitions[i], interval)
+				}
+			} else {
+				m.runJobIfNeeded(ctx, db.Name, tblInfo, nil, interval)
+			}
+			if err := ctx.Err(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *JobManager) runJobIfNeeded(ctx context.Context, dbName model.CIStr, tblInfo *model.TableInfo, partition *model.PartitionDefinition, interval time.Duration) {
+	if !m.shouldRun() || ctx.Err() != nil {
+		return
+	}
+	job := newJob(m, dbName, tblInfo, partition)
+	logger := logutil.BgLogger().With(zap.String("table", tblInfo.Name.O), zap.Int64("physicalTableID", job.physicalID))
+	elapsed, err := m.sinceLastJob(ctx, job.physicalID)
+	if err != nil {
+		logger.Warn("[ttl] get the last job failed", zap.Error(err))
+		return
+	}
+	if elapsed >= 0 && elapsed < interval {
+		return
+	}
+	if err := job.run(ctx); err != nil {
+		logger.Warn("[ttl] job failed", zap.String("jobID", job.id), zap.Error(err))
+	}
+}
+
+// sinceLastJob returns the time elapsed since the last job of the physical table was created,
+// or -1 if the table never runs a job.
+func (m *JobManager) sinceLastJob(ctx context.Context, physicalID int64) (time.Duration, error) {
+	rows, err := m.querySQL(ctx, "SELECT TIMESTAMPDIFF(SECOND, MAX(create_time), NOW()) FROM mysql.tidb_ttl_job_history WHERE table_id = %?", physicalID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return -1, nil
+	}
+	return time.Duration(rows[0].GetInt64(0)) * time.Second, nil
+}
+
+// shouldRun returns whether the jobs should keep running on this TiDB.
+func (m *JobManager) shouldRun() bool {
+	return variable.EnableTTLJob.Load() && m.owner.IsOwner()
+}
+
+// waitDelete throttles the delete statements by tidb_ttl_delete_rate_limit.
+func (m *JobManager) waitDelete(ctx context.Context) error {
+	limit := rate.Inf
+	if l := variable.TTLDeleteRateLimit.Load(); l > 0 {
+		limit = rate.Limit(l)
+	}
+	if m.limiter.Limit() != limit {
+		m.limiter.SetLimit(limit)
+	}
+	return m.limiter.Wait(ctx)
+}
+
+func (m *JobManager) execSQL(ctx context.Context, sql string, args ...interface{}) error {
+	_, err := m.querySQL(ctx, sql, args...)
+	return err
+}
+
+// querySQLInto runs the query, and reads the first column of the first row as a string.
+func (m *JobManager) querySQLInto(ctx context.Context, result *string, sql string, args ...interface{}) error {
+	rows, err := m.querySQL(ctx, sql, args...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return errors.Errorf("unexpected empty result of %s", sql)
+	}
+	*result = rows[0].GetString(0)
+	return nil
+}
+
+func (m *JobManager) querySQL(ctx context.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	se, err := m.pool.Get()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer m.pool.Put(se)
+	rows, _, err := execInSession(ctx, se.(sqlexec.SQLExecutor), sql, args...)
+	return rows, err
+}
+
+// execInSession executes the sql in the session, and returns the result rows and the fields.
+func execInSession(ctx context.Context, exec sqlexec.SQLExecutor, sql string, args ...interface{}) ([]chunk.Row, []*ast.ResultField, error) {
+	rs, err := exec.ExecuteInternal(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if rs == nil {
+		return nil, nil, nil
+	}
+	defer terror.Call(rs.Close)
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 1024)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return rows, rs.Fields(), nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func insertRows(tk *testkit.TestKit, table string, n int) {
+	for i := 1; i <= n; i++ {
+		// The rows with an odd id are expired.
+		if i%2 == 1 {
+			tk.MustExec(fmt.Sprintf("insert into %s (id, created_at) values (%d, date_sub(now(), interval 2 day))", table, i))
+		} else {
+			tk.MustExec(fmt.Sprintf("insert into %s (id, created_at) values (%d, now())", table, i))
+		}
+	}
+}
+
+func TestRunTTLJobs(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ttl_scan_batch_size = 3")
+	tk.MustExec("set @@global.tidb_ttl_delete_batch_size = 2")
+	defer func() {
+		tk.MustExec("set @@global.tidb_ttl_scan_batch_size = default")
+		tk.MustExec("set @@global.tidb_ttl_delete_batch_size = default")
+	}()
+
+	tk.MustExec("create table t_int (id int primary key, created_at datetime) ttl = created_at + interval 1 day")
+	tk.MustExec("create table t_rowid (id int, created_at timestamp) ttl = created_at + interval 1 day")
+	tk.MustExec("create table t_common (id varchar(10), v int, created_at date, primary key (id, v) clustered) ttl = created_at + interval 1 day")
+	tk.MustExec("create table t_part (id int primary key, created_at datetime) ttl = created_at + interval 1 day partition by hash (id) partitions 2")
+	tk.MustExec("create table t_disabled (id int primary key, created_at datetime) ttl = created_at + interval 1 day ttl_enable = 'off'")
+	for _, tbl := range []string{"t_int", "t_rowid", "t_part", "t_disabled"} {
+		insertRows(tk, tbl, 20)
+	}
+	for i := 1; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t_common values ('%d', %d, date_sub(now(), interval %d day))", i, i, (i%2)*2))
+	}
+	tk.MustQuery("split table t_int by (5), (10), (15)").Check(testkit.Rows("3 1"))
+
+	require.NoError(t, dom.TTLJobManager().RunJobsOnce(context.Background(), dom.InfoSchema()))
+	for _, tbl := range []string{"t_int", "t_rowid", "t_part"} {
+		tk.MustQuery(fmt.Sprintf("select count(*), sum(id %% 2) from %s", tbl)).Check(testkit.Rows("10 0"))
+	}
+	tk.MustQuery("select count(*), sum(v % 2) from t_common").Check(testkit.Rows("10 0"))
+	tk.MustQuery("select count(*) from t_disabled").Check(testkit.Rows("20"))
+	tk.MustQuery("select table_name, partition_name, range_count, finished_range_count, scanned_rows, deleted_rows, error_delete_rows, status from mysql.tidb_ttl_job_history order by table_name, partition_name").Check(testkit.Rows(
+		"t_common <nil> 1 1 10 10 0 finished",
+		"t_int <nil> 4 4 10 10 0 finished",
+		"t_part p0 1 1 0 0 0 finished",
+		"t_part p1 1 1 10 10 0 finished",
+		"t_rowid <nil> 1 1 10 10 0 finished",
+	))
+
+	// The jobs are not run again until the job interval is elapsed.
+	insertRows(tk, "t_int", 1)
+	require.NoError(t, dom.TTLJobManager().RunJobsOnce(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("select count(*) from t_int").Check(testkit.Rows("11"))
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_job_history").Check(testkit.Rows("5"))
+
+	tk.MustExec("update mysql.tidb_ttl_job_history set create_time = date_sub(create_time, interval 1 hour) where table_name = 't_int'")
+	tk.MustExec("set @@global.tidb_ttl_job_enable = 'OFF'")
+	require.NoError(t, dom.TTLJobManager().RunJobsOnce(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("select count(*) from t_int").Check(testkit.Rows("11"))
+	tk.MustExec("set @@global.tidb_ttl_job_enable = 'ON'")
+	require.NoError(t, dom.TTLJobManager().RunJobsOnce(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("select count(*) from t_int").Check(testkit.Rows("10"))
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_job_history where table_name = 't_int'").Check(testkit.Rows("2"))
+}
+
+func TestRunTTLJobWithUnsignedHandle(t *testing.T) {
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	// The unsigned handles not less than 1 << 63 are encoded as the negative ones, so the region
+	// which starts from 9223372036854775810 ends at 3, and the table is scanned as a whole.
+	tk.MustExec("create table t_uint (id bigint unsigned primary key, created_at datetime) ttl = created_at + interval 1 day")
+	insertRows(tk, "t_uint", 6)
+	for i := 1; i <= 6; i++ {
+		createdAt := "now()"
+		if i%2 == 1 {
+			createdAt = "date_sub(now(), interval 2 day)"
+		}
+		tk.MustExec(fmt.Sprintf("insert into t_uint values (%d, %s)", uint64(math.MaxInt64)+uint64(i), createdAt))
+	}
+	tk.MustQuery("split table t_uint by (9223372036854775810), (3)").Check(testkit.Rows("2 1"))
+
+	require.NoError(t, dom.TTLJobManager().RunJobsOnce(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("select id from t_uint order by id").Check(testkit.Rows(
+		"2", "4", "6", "9223372036854775809", "9223372036854775811", "9223372036854775813",
+	))
+	tk.MustQuery("select range_count, finished_range_count, scanned_rows, deleted_rows, status from mysql.tidb_ttl_job_history where table_name = 't_uint'").Check(testkit.Rows(
+		"1 1 6 6 finished",
+	))
+}
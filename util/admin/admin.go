@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
@@ -55,19 +56,19 @@ func GetDDLInfo(txn kv.Transaction) (*DDLInfo, error) {
 	t := meta.NewMeta(txn)
 
 	info.Jobs = make([]*model.Job, 0, 2)
-	job, err := t.GetDDLJobByIdx(0)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	if job != nil {
+	var addIdxJob *model.Job
+	for _, jobListKey := range meta.AllJobListKeys(variable.MaxDDLJobQueueCount) {
+		job, err := t.GetDDLJobByIdx(0, jobListKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if job == nil {
+			continue
+		}
 		info.Jobs = append(info.Jobs, job)
-	}
-	addIdxJob, err := t.GetDDLJobByIdx(0, meta.AddIndexJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	if addIdxJob != nil {
-		info.Jobs = append(info.Jobs, addIdxJob)
+		if addIdxJob == nil && MayNeedBackfill(job.Type) {
+			addIdxJob = job
+		}
 	}
 
 	info.SchemaVer, err = t.GetSchemaVersion()
@@ -111,21 +112,29 @@ func IsJobRollbackable(job *model.Job) bool {
 		}
 	case model.ActionAddTablePartition:
 		return job.SchemaState == model.StateNone || job.SchemaState == model.StateReplicaOnly
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		return job.SchemaState != model.StateDeleteReorganization
+	case model.ActionExchangeTablePartition:
+		return job.SchemaState == model.StateNone || job.SchemaState == model.StateWriteOnly
 	case model.ActionDropColumn, model.ActionDropColumns, model.ActionDropTablePartition,
 		model.ActionRebaseAutoID, model.ActionShardRowID,
-		model.ActionTruncateTable, model.ActionAddForeignKey,
-		model.ActionDropForeignKey, model.ActionRenameTable,
+		model.ActionTruncateTable, model.ActionDropForeignKey, model.ActionRenameTable,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
-		model.ActionModifyTableAutoIdCache, model.ActionModifySchemaDefaultPlacement:
+		model.ActionModifyTableAutoIdCache, model.ActionModifySchemaDefaultPlacement,
+		model.ActionAlterTTLInfo, model.ActionAlterTTLRemove:
 		return job.SchemaState == model.StateNone
+	case model.ActionMultiSchemaChange:
+		return job.MultiSchemaInfo.Revertible
 	}
 	return true
 }
 
 // MayNeedBackfill returns whether the action type may need to backfill the data.
 func MayNeedBackfill(tp model.ActionType) bool {
-	return tp == model.ActionAddIndex || tp == model.ActionAddPrimaryKey || tp == model.ActionModifyColumn
+	return tp == model.ActionAddIndex || tp == model.ActionAddPrimaryKey || tp == model.ActionModifyColumn ||
+		tp == model.ActionMultiSchemaChange || tp == model.ActionReorganizePartition ||
+		tp == model.ActionAlterTablePartitioning || tp == model.ActionRemovePartitioning
 }
 
 // CancelJobs cancels the DDL jobs.
@@ -136,19 +145,15 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 
 	errs := make([]error, len(ids))
 	t := meta.NewMeta(txn)
-	generalJobs, err := getDDLJobsInQueue(t, meta.DefaultJobListKey)
+	jobs, err := getAllQueuedJobs(t)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	addIdxJobs, err := getDDLJobsInQueue(t, meta.AddIndexJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	jobs := append(generalJobs, addIdxJobs...)
 
 	for i, id := range ids {
 		found := false
-		for j, job := range jobs {
+		for _, qJob := range jobs {
+			job := qJob.job
 			if id != job.ID {
 				logutil.BgLogger().Debug("the job that needs to be canceled isn't equal to current job",
 					zap.Int64("need to canceled job ID", id),
@@ -177,12 +182,83 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 				errs[i] = errors.Trace(err)
 				continue
 			}
-			if MayNeedBackfill(job.Type) {
-				offset := int64(j - len(generalJobs))
-				err = t.UpdateDDLJob(offset, job, true, meta.AddIndexJobListKey)
-			} else {
-				err = t.UpdateDDLJob(int64(j), job, true)
+			err = t.UpdateDDLJob(qJob.offset, job, true, qJob.jobListKey)
+			if err != nil {
+				errs[i] = errors.Trace(err)
+			}
+		}
+		if !found {
+			errs[i] = ErrDDLJobNotFound.GenWithStackByArgs(id)
+		}
+	}
+	return errs, nil
+}
+
+// PauseJobs pauses the DDL jobs. A paused job keeps its place in the DDL job queue,
+// and its reorganization progress is kept until it's resumed.
+func PauseJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateJobsState(txn, ids, func(job *model.Job) (bool, error) {
+		if job.IsDone() || job.IsSynced() {
+			return false, ErrCannotPauseDDLJob.GenWithStackByArgs(job.ID)
+		}
+		// The job is already paused or the DDL worker hasn't handled the pausing request.
+		if job.IsPaused() || job.IsPausing() {
+			return false, nil
+		}
+		// The job is cleaning up after being cancelled, pausing it may leave the data half cleaned.
+		if job.IsCancelling() || job.IsCancelled() || job.IsRollingback() || job.IsRollbackDone() {
+			return false, ErrCannotPauseDDLJob.GenWithStackByArgs(job.ID)
+		}
+		job.State = model.JobStatePausing
+		return true, nil
+	})
+}
+
+// ResumeJobs resumes the paused DDL jobs.
+func ResumeJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateJobsState(txn, ids, func(job *model.Job) (bool, error) {
+		if !job.IsPaused() {
+			return false, ErrCannotResumeDDLJob.GenWithStackByArgs(job.ID)
+		}
+		job.State = model.JobStateRunning
+		return true, nil
+	})
+}
+
+// updateJobsState finds the DDL jobs in the queues and updates them by the
+// function f. f returns whether the job needs to be written back.
+func updateJobsState(txn kv.Transaction, ids []int64, f func(job *model.Job) (bool, error)) ([]error, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	errs := make([]error, len(ids))
+	t := meta.NewMeta(txn)
+	jobs, err := getAllQueuedJobs(t)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for i, id := range ids {
+		found := false
+		for _, qJob := range jobs {
+			job := qJob.job
+			if id != job.ID {
+				continue
+			}
+			found = true
+			needUpdate, err := f(job)
+			if err != nil || !needUpdate {
+				errs[i] = err
+				continue
+			}
+			// Make sure RawArgs isn't overwritten.
+			err = json.Unmarshal(job.RawArgs, &job.Args)
+			if err != nil {
+				errs[i] = errors.Trace(err)
+				continue
 			}
+			err = t.UpdateDDLJob(qJob.offset, job, true, qJob.jobListKey)
 			if err != nil {
 				errs[i] = errors.Trace(err)
 			}
@@ -209,18 +285,39 @@ func getDDLJobsInQueue(t *meta.Meta, jobListKey meta.JobListKeyType) ([]*model.J
 	return jobs, nil
 }
 
+// queuedJob is a DDL job with the key of its queue and its offset in the queue.
+type queuedJob struct {
+	job        *model.Job
+	jobListKey meta.JobListKeyType
+	offset     int64
+}
+
+// getAllQueuedJobs gets the DDL jobs in all the DDL job queues.
+func getAllQueuedJobs(t *meta.Meta) ([]queuedJob, error) {
+	var queuedJobs []queuedJob
+	for _, jobListKey := range meta.AllJobListKeys(variable.MaxDDLJobQueueCount) {
+		jobs, err := getDDLJobsInQueue(t, jobListKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i, job := range jobs {
+			queuedJobs = append(queuedJobs, queuedJob{job: job, jobListKey: jobListKey, offset: int64(i)})
+		}
+	}
+	return queuedJobs, nil
+}
+
 // GetDDLJobs get all DDL jobs and sorts jobs by job.ID.
 func GetDDLJobs(txn kv.Transaction) ([]*model.Job, error) {
 	t := meta.NewMeta(txn)
-	generalJobs, err := getDDLJobsInQueue(t, meta.DefaultJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	addIdxJobs, err := getDDLJobsInQueue(t, meta.AddIndexJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
+	var jobs []*model.Job
+	for _, jobListKey := range meta.AllJobListKeys(variable.MaxDDLJobQueueCount) {
+		queueJobs, err := getDDLJobsInQueue(t, jobListKey)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		jobs = append(jobs, queueJobs...)
 	}
-	jobs := append(generalJobs, addIdxJobs...)
 	sort.Sort(jobArray(jobs))
 	return jobs, nil
 }
@@ -498,6 +595,10 @@ var (
 	ErrCancelFinishedDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCancelFinishedDDLJob)
 	// ErrCannotCancelDDLJob returns when cancel a almost finished ddl job, because cancel in now may cause data inconsistency.
 	ErrCannotCancelDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotCancelDDLJob)
+	// ErrCannotPauseDDLJob returns when pause a finished ddl job or a job which is rolling back.
+	ErrCannotPauseDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotPauseDDLJob)
+	// ErrCannotResumeDDLJob returns when resume a ddl job which isn't paused.
+	ErrCannotResumeDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotResumeDDLJob)
 	// ErrAdminCheckTable returns when the table records is inconsistent with the index values.
 	ErrAdminCheckTable = dbterror.ClassAdmin.NewStd(errno.ErrAdminCheckTable)
 )
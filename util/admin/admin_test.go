@@ -271,6 +271,91 @@ func TestCancelJobs(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPauseAndResumeJobs(t *testing.T) {
+	store, clean := newMockStore(t)
+	defer clean()
+
+	txn, err := store.Begin()
+	require.NoError(t, err)
+
+	m := meta.NewMeta(txn)
+	job := &model.Job{
+		ID:       1,
+		SchemaID: 1,
+		TableID:  2,
+		Type:     model.ActionAddColumn,
+		State:    model.JobStateRunning,
+	}
+	addIdxJob := &model.Job{
+		ID:       2,
+		SchemaID: 1,
+		TableID:  2,
+		Type:     model.ActionAddIndex,
+		State:    model.JobStateRunning,
+	}
+	doneJob := &model.Job{
+		ID:       3,
+		SchemaID: 1,
+		Type:     model.ActionCreateTable,
+		State:    model.JobStateDone,
+	}
+	rollingbackJob := &model.Job{
+		ID:       4,
+		SchemaID: 1,
+		TableID:  2,
+		Type:     model.ActionAddIndex,
+		State:    model.JobStateRollingback,
+	}
+	require.NoError(t, m.EnQueueDDLJob(job))
+	// The job in the extra job queue can be paused and resumed too.
+	addIdxJobListKey := meta.GetJobListKey(1, true)
+	require.NoError(t, m.EnQueueDDLJob(addIdxJob, addIdxJobListKey))
+	require.NoError(t, m.EnQueueDDLJob(doneJob))
+	require.NoError(t, m.EnQueueDDLJob(rollingbackJob, meta.AddIndexJobListKey))
+
+	// Only the paused jobs can be resumed.
+	errs, err := ResumeJobs(txn, []int64{job.ID})
+	require.NoError(t, err)
+	require.Regexp(t, "This job:1 isn't paused, so can't be resumed$", errs[0].Error())
+
+	errs, err = PauseJobs(txn, []int64{job.ID, addIdxJob.ID, doneJob.ID, rollingbackJob.ID, -1})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.Regexp(t, "This job:3 can't be paused now$", errs[2].Error())
+	require.Regexp(t, "This job:4 can't be paused now$", errs[3].Error())
+	require.Regexp(t, "DDL Job:-1 not found$", errs[4].Error())
+
+	gotJob, err := m.GetDDLJobByIdx(0)
+	require.NoError(t, err)
+	require.Equal(t, model.JobStatePausing, gotJob.State)
+	gotJob, err = m.GetDDLJobByIdx(0, addIdxJobListKey)
+	require.NoError(t, err)
+	require.Equal(t, model.JobStatePausing, gotJob.State)
+
+	// Pausing a pausing job is a no-op.
+	errs, err = PauseJobs(txn, []int64{job.ID})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+
+	// The pausing job isn't paused by the DDL worker yet.
+	errs, err = ResumeJobs(txn, []int64{job.ID})
+	require.NoError(t, err)
+	require.Error(t, errs[0])
+
+	gotJob.State = model.JobStatePaused
+	require.NoError(t, m.UpdateDDLJob(0, gotJob, false, addIdxJobListKey))
+	errs, err = ResumeJobs(txn, []int64{addIdxJob.ID})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	gotJob, err = m.GetDDLJobByIdx(0, addIdxJobListKey)
+	require.NoError(t, err)
+	require.Equal(t, model.JobStateRunning, gotJob.State)
+
+	err = txn.Rollback()
+	require.NoError(t, err)
+}
+
 func TestGetHistoryDDLJobs(t *testing.T) {
 	store, clean := newMockStore(t)
 	defer clean()
@@ -0,0 +1,144 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// syncCacheEntry wraps Key, Value and the memory usage of the pair. It's the value of list.Element.
+type syncCacheEntry struct {
+	key   Key
+	value Value
+	size  int64
+}
+
+// SyncLRUCache is a thread-safe least recently used cache. The memory usage of the cache is tracked by
+// a memory.Tracker, whose bytes limit is the quota of the cache: the least recently used elements are
+// evicted when putting a new element exceeds the quota.
+type SyncLRUCache struct {
+	mu         sync.Mutex
+	elements   map[string]*list.Element
+	cache      *list.List
+	memTracker *memory.Tracker
+}
+
+// NewSyncLRUCache creates a SyncLRUCache object whose memory quota is "quota" bytes.
+func NewSyncLRUCache(label int, quota int64) *SyncLRUCache {
+	return &SyncLRUCache{
+		elements:   make(map[string]*list.Element),
+		cache:      list.New(),
+		memTracker: memory.NewTracker(label, quota),
+	}
+}
+
+// MemTracker returns the memory tracker of the cache.
+func (l *SyncLRUCache) MemTracker() *memory.Tracker {
+	return l.memTracker
+}
+
+// Get tries to find the corresponding value according to the given key.
+func (l *SyncLRUCache) Get(key Key) (value Value, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, exists := l.elements[string(key.Hash())]
+	if !exists {
+		return nil, false
+	}
+	l.cache.MoveToFront(element)
+	return element.Value.(*syncCacheEntry).value, true
+}
+
+// Put puts the (key, value) pair, which uses "size" bytes of memory, into the LRU Cache.
+// It returns false if the pair is larger than the quota and isn't cached.
+func (l *SyncLRUCache) Put(key Key, value Value, size int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hash := string(key.Hash())
+	if element, exists := l.elements[hash]; exists {
+		l.removeElement(element)
+	}
+	quota := l.memTracker.GetBytesLimit()
+	if size > quota {
+		return false
+	}
+	for l.memTracker.BytesConsumed()+size > quota {
+		l.removeElement(l.cache.Back())
+	}
+	element := l.cache.PushFront(&syncCacheEntry{key: key, value: value, size: size})
+	l.elements[hash] = element
+	l.memTracker.Consume(size)
+	return true
+}
+
+// Delete deletes the key-value pair from the LRU Cache.
+func (l *SyncLRUCache) Delete(key Key) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, exists := l.elements[string(key.Hash())]; exists {
+		l.removeElement(element)
+	}
+}
+
+// DeleteAll deletes all elements from the LRU Cache.
+func (l *SyncLRUCache) DeleteAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for lru := l.cache.Back(); lru != nil; lru = l.cache.Back() {
+		l.removeElement(lru)
+	}
+}
+
+// SetQuota sets the memory quota of the cache, and evicts the least recently used elements until
+// the memory usage doesn't exceed the new quota.
+func (l *SyncLRUCache) SetQuota(quota int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.memTracker.GetBytesLimit() == quota {
+		return
+	}
+	l.memTracker.SetBytesLimit(quota)
+	for l.memTracker.BytesConsumed() > quota {
+		l.removeElement(l.cache.Back())
+	}
+}
+
+// Size gets the current cache size.
+func (l *SyncLRUCache) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cache.Len()
+}
+
+// Values return all values in cache, from the most recently used one to the least recently used one.
+func (l *SyncLRUCache) Values() []Value {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	values := make([]Value, 0, l.cache.Len())
+	for ele := l.cache.Front(); ele != nil; ele = ele.Next() {
+		values = append(values, ele.Value.(*syncCacheEntry).value)
+	}
+	return values
+}
+
+func (l *SyncLRUCache) removeElement(element *list.Element) {
+	entry := element.Value.(*syncCacheEntry)
+	l.cache.Remove(element)
+	delete(l.elements, string(entry.key.Hash()))
+	l.memTracker.Consume(-entry.size)
+}
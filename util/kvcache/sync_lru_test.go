@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncLRUCache(t *testing.T) {
+	lru := NewSyncLRUCache(memory.LabelForInstancePlanCache, 100)
+	for i := 0; i < 5; i++ {
+		require.True(t, lru.Put(newMockHashKey(int64(i)), i, 20))
+	}
+	require.Equal(t, 5, lru.Size())
+	require.Equal(t, int64(100), lru.MemTracker().BytesConsumed())
+
+	// The least recently used element is evicted when the quota is exceeded.
+	_, ok := lru.Get(newMockHashKey(0))
+	require.True(t, ok)
+	require.True(t, lru.Put(newMockHashKey(5), 5, 30))
+	require.Equal(t, 4, lru.Size())
+	require.Equal(t, int64(90), lru.MemTracker().BytesConsumed())
+	for _, i := range []int64{1, 2} {
+		_, ok = lru.Get(newMockHashKey(i))
+		require.False(t, ok)
+	}
+	require.Equal(t, []Value{5, 0, 4, 3}, lru.Values())
+
+	// Putting an existing key replaces the value.
+	require.True(t, lru.Put(newMockHashKey(3), 33, 10))
+	value, ok := lru.Get(newMockHashKey(3))
+	require.True(t, ok)
+	require.Equal(t, 33, value)
+	require.Equal(t, int64(80), lru.MemTracker().BytesConsumed())
+
+	// The element larger than the quota isn't cached.
+	require.False(t, lru.Put(newMockHashKey(6), 6, 101))
+	_, ok = lru.Get(newMockHashKey(6))
+	require.False(t, ok)
+
+	lru.SetQuota(40)
+	require.Equal(t, []Value{33, 5}, lru.Values())
+	require.Equal(t, int64(40), lru.MemTracker().BytesConsumed())
+
+	lru.Delete(newMockHashKey(5))
+	require.Equal(t, 1, lru.Size())
+	require.Equal(t, int64(10), lru.MemTracker().BytesConsumed())
+	lru.DeleteAll()
+	require.Equal(t, 0, lru.Size())
+	require.Equal(t, int64(0), lru.MemTracker().BytesConsumed())
+}
+
+func TestSyncLRUCacheConcurrently(t *testing.T) {
+	lru := NewSyncLRUCache(memory.LabelForInstancePlanCache, 1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := newMockHashKey(int64(i*100 + j))
+				lru.Put(key, j, 10)
+				lru.Get(key)
+				if j%10 == 0 {
+					lru.Delete(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.LessOrEqual(t, lru.MemTracker().BytesConsumed(), int64(1000))
+	require.Equal(t, int64(lru.Size()*10), lru.MemTracker().BytesConsumed())
+}
@@ -556,4 +556,6 @@ const (
 	LabelForIndexJoinInnerWorker int = -20
 	// LabelForIndexJoinOuterWorker represents the label of IndexJoin OuterWorker
 	LabelForIndexJoinOuterWorker int = -21
+	// LabelForInstancePlanCache represents the label of the instance plan cache
+	LabelForInstancePlanCache int = -22
 )
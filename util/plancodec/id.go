@@ -127,6 +127,18 @@ const (
 	TypeCTE = "CTEFullScan"
 	// TypeCTEDefinition is the type of CTE definition
 	TypeCTEDefinition = "CTE"
+	// TypeForeignKeyCheck is the type of FKCheck
+	TypeForeignKeyCheck = "Foreign_Key_Check"
+	// TypeForeignKeyCascade is the type of FKCascade
+	TypeForeignKeyCascade = "Foreign_Key_Cascade"
+	// TypeTiKVDoubleGather is the type of TiKVDoubleGather.
+	TypeTiKVDoubleGather = "TiKVDoubleGather"
+	// TypeTiKVIndexMergeGather is the type of TiKVIndexMergeGather.
+	TypeTiKVIndexMergeGather = "TiKVIndexMergeGather"
+	// TypeTiKVPointGet is the type of TiKVPointGet.
+	TypeTiKVPointGet = "TiKVPointGet"
+	// TypeTiFlashMPPGather is the type of TiFlashMPPGather.
+	TypeTiFlashMPPGather = "TiFlashMPPGather"
 )
 
 // plan id.
@@ -184,6 +196,12 @@ const (
 	typeCTE                   int = 50
 	typeCTEDefinition         int = 51
 	typeCTETable              int = 52
+	typeForeignKeyCheck       int = 53
+	typeForeignKeyCascade     int = 54
+	typeTiKVDoubleGather      int = 55
+	typeTiKVIndexMergeGather  int = 56
+	typeTiKVPointGet          int = 57
+	typeTiFlashMPPGather      int = 58
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -293,6 +311,18 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeCTEDefinition
 	case TypeCTETable:
 		return typeCTETable
+	case TypeForeignKeyCheck:
+		return typeForeignKeyCheck
+	case TypeForeignKeyCascade:
+		return typeForeignKeyCascade
+	case TypeTiKVDoubleGather:
+		return typeTiKVDoubleGather
+	case TypeTiKVIndexMergeGather:
+		return typeTiKVIndexMergeGather
+	case TypeTiKVPointGet:
+		return typeTiKVPointGet
+	case TypeTiFlashMPPGather:
+		return typeTiFlashMPPGather
 	}
 	// Should never reach here.
 	return 0
@@ -403,6 +433,18 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeCTEDefinition
 	case typeCTETable:
 		return TypeCTETable
+	case typeForeignKeyCheck:
+		return TypeForeignKeyCheck
+	case typeForeignKeyCascade:
+		return TypeForeignKeyCascade
+	case typeTiKVDoubleGather:
+		return TypeTiKVDoubleGather
+	case typeTiKVIndexMergeGather:
+		return TypeTiKVIndexMergeGather
+	case typeTiKVPointGet:
+		return TypeTiKVPointGet
+	case typeTiFlashMPPGather:
+		return TypeTiFlashMPPGather
 	}
 
 	// Should never reach here.
@@ -0,0 +1,595 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// persistFlushInterval is the interval to persist the summaries whose interval has ended but no statement
+	// of the same kind runs afterwards.
+	persistFlushInterval = time.Minute
+	// persistBackupTimeFormat is the time format in the names of the rotated files, which is decided by lumberjack.
+	persistBackupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// stmtSummaryRecord is a summary persisted in the file, each line in the file is a record in JSON.
+type stmtSummaryRecord struct {
+	SchemaName string   `json:"schema_name"`
+	Digest     string   `json:"digest"`
+	PrevDigest string   `json:"prev_digest"`
+	PlanDigest string   `json:"plan_digest"`
+	BeginTime  int64    `json:"begin_time"`
+	EndTime    int64    `json:"end_time"`
+	AuthUsers  []string `json:"auth_users"`
+	// PlanHint, Charset and Collation aren't shown in the tables, they are used to create bindings from the history.
+	PlanHint  string `json:"plan_hint,omitempty"`
+	Charset   string `json:"charset,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	// Columns holds the values of the columns formatted as strings, the null values are omitted.
+	Columns map[string]string `json:"columns"`
+}
+
+// stmtSummaryRecordKey identifies a summary in an interval.
+type stmtSummaryRecordKey struct {
+	schemaName string
+	digest     string
+	prevDigest string
+	planDigest string
+	beginTime  int64
+}
+
+// expiredSummaries are the expired elements of a summary which are waiting to be persisted.
+type expiredSummaries struct {
+	ssbd       *stmtSummaryByDigest
+	ssElements []*stmtSummaryByDigestElement
+}
+
+// stmtSummaryPersistence writes the expired summaries to a rotating file.
+// The summaries expired while adding statements or evicting are queued, and they are encoded and written by
+// the background goroutine, so that the file isn't written on the hot path or under the lock of the map.
+type stmtSummaryPersistence struct {
+	// The mutex protects the writer, it's held while encoding and writing the summaries.
+	sync.Mutex
+	filename string
+	writer   *lumberjack.Logger
+	closed   bool
+
+	pendingMu sync.Mutex
+	pending   []expiredSummaries
+	// notifyCh notifies the background goroutine that there are pending summaries.
+	notifyCh chan struct{}
+
+	exitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// SetupPersistence starts persisting the expired statement summaries to the file if it's enabled in the config.
+func SetupPersistence() error {
+	return StmtSummaryByDigestMap.setupPersistence(config.GetGlobalConfig().StmtSummary)
+}
+
+// ClosePersistence persists the expired statement summaries and closes the file.
+func ClosePersistence() {
+	StmtSummaryByDigestMap.closePersistence()
+}
+
+func (ssMap *stmtSummaryByDigestMap) setupPersistence(cfg config.StmtSummary) error {
+	if !cfg.EnablePersistent {
+		return nil
+	}
+	if len(cfg.Filename) == 0 {
+		return errors.New("the filename of the persistent statement summary is empty")
+	}
+	if st, err := os.Stat(cfg.Filename); err == nil && st.IsDir() {
+		return errors.Errorf("can't use directory %s as the persistent statement summary file", cfg.Filename)
+	}
+	p := &stmtSummaryPersistence{
+		filename: cfg.Filename,
+		writer: &lumberjack.Logger{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.FileMaxSize,
+			MaxAge:     cfg.FileMaxDays,
+			MaxBackups: cfg.FileMaxBackups,
+			LocalTime:  true,
+		},
+		notifyCh: make(chan struct{}, 1),
+		exitCh:   make(chan struct{}),
+	}
+	ssMap.persistence.Store(p)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(persistFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.notifyCh:
+				p.flushPending()
+			case <-ticker.C:
+				ssMap.persistExpiredSummaries(time.Now().Unix(), false)
+			case <-p.exitCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (ssMap *stmtSummaryByDigestMap) closePersistence() {
+	p := ssMap.getPersistence()
+	if p == nil {
+		return
+	}
+	close(p.exitCh)
+	p.wg.Wait()
+	ssMap.persistExpiredSummaries(time.Now().Unix(), true)
+	ssMap.persistence.Store((*stmtSummaryPersistence)(nil))
+	p.Lock()
+	defer p.Unlock()
+	p.closed = true
+	if err := p.writer.Close(); err != nil {
+		logutil.BgLogger().Warn("close persistent statement summary file failed", zap.Error(err))
+	}
+}
+
+func (ssMap *stmtSummaryByDigestMap) getPersistence() *stmtSummaryPersistence {
+	p, _ := ssMap.persistence.Load().(*stmtSummaryPersistence)
+	return p
+}
+
+// persistenceEnabled returns whether the expired summaries are persisted to the file.
+func (ssMap *stmtSummaryByDigestMap) persistenceEnabled() bool {
+	return ssMap.getPersistence() != nil
+}
+
+// persistExpiredSummaries persists the summaries whose interval has ended before now, and the pending ones.
+// The statements which read the begin time before the interval changes may still be added to the last element
+// of the previous interval, so only the elements of the earlier intervals are persisted, unless the persistence
+// is closing, when no statements are running any more.
+func (ssMap *stmtSummaryByDigestMap) persistExpiredSummaries(now int64, closing bool) {
+	p := ssMap.getPersistence()
+	if p == nil {
+		return
+	}
+	intervalSeconds := ssMap.refreshInterval()
+	ssMap.Lock()
+	if ssMap.beginTimeForCurInterval+intervalSeconds <= now {
+		ssMap.beginTimeForCurInterval = now / intervalSeconds * intervalSeconds
+	}
+	beginTime := ssMap.beginTimeForCurInterval
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+	if !closing {
+		beginTime -= intervalSeconds
+	}
+
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		p.enqueue(ssbd, ssbd.collectExpiredSummaries(beginTime, intervalSeconds))
+	}
+	p.flushPending()
+}
+
+// queueExpiredSummaries queues the expired elements to be persisted by the background goroutine.
+func (ssMap *stmtSummaryByDigestMap) queueExpiredSummaries(ssbd *stmtSummaryByDigest, ssElements []*stmtSummaryByDigestElement) {
+	p := ssMap.getPersistence()
+	if p == nil || len(ssElements) == 0 {
+		return
+	}
+	p.enqueue(ssbd, ssElements)
+	select {
+	case p.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (p *stmtSummaryPersistence) enqueue(ssbd *stmtSummaryByDigest, ssElements []*stmtSummaryByDigestElement) {
+	if len(ssElements) == 0 {
+		return
+	}
+	p.pendingMu.Lock()
+	p.pending = append(p.pending, expiredSummaries{ssbd: ssbd, ssElements: ssElements})
+	p.pendingMu.Unlock()
+}
+
+// flushPending writes the pending elements which are not persisted yet to the file. The readers of the files
+// call it too, so that the summaries which are evicted from the memory are always found in the files.
+func (p *stmtSummaryPersistence) flushPending() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.pendingMu.Lock()
+	pending := p.pending
+	p.pending = nil
+	p.pendingMu.Unlock()
+
+	var buf []byte
+	for _, summaries := range pending {
+		ssbd := summaries.ssbd
+		for _, ssElement := range summaries.ssElements {
+			record := newStmtSummaryRecord(ssElement, ssbd)
+			if record == nil {
+				continue
+			}
+			line, err := json.Marshal(record)
+			if err != nil {
+				logutil.BgLogger().Warn("encode persistent statement summary failed", zap.String("digest", ssbd.digest), zap.Error(err))
+				continue
+			}
+			buf = append(buf, line...)
+			buf = append(buf, '\n')
+		}
+	}
+	if len(buf) == 0 || p.closed {
+		return
+	}
+	if _, err := p.writer.Write(buf); err != nil {
+		logutil.BgLogger().Warn("write persistent statement summary failed", zap.Error(err))
+	}
+}
+
+// collectExpiredSummaries returns the elements whose interval begins before `beginTimeForCurInterval`.
+func (ssbd *stmtSummaryByDigest) collectExpiredSummaries(beginTimeForCurInterval int64, intervalSeconds int64) []*stmtSummaryByDigestElement {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+
+	if !ssbd.initialized {
+		return nil
+	}
+	var ssElements []*stmtSummaryByDigestElement
+	for listElement := ssbd.history.Front(); listElement != nil; listElement = listElement.Next() {
+		ssElement := listElement.Value.(*stmtSummaryByDigestElement)
+		if ssElement.beginTime >= beginTimeForCurInterval {
+			break
+		}
+		if listElement.Next() == nil {
+			// The last element isn't expired by adding statements, so expire it here.
+			ssElement.onExpire(intervalSeconds)
+		}
+		ssElements = append(ssElements, ssElement)
+	}
+	return ssElements
+}
+
+// newStmtSummaryRecord marks the element persisted and returns its record. It returns nil if the element
+// has been persisted.
+func newStmtSummaryRecord(ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) *stmtSummaryRecord {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+
+	if ssElement.persisted {
+		return nil
+	}
+	ssElement.persisted = true
+	record := &stmtSummaryRecord{
+		SchemaName: ssbd.schemaName,
+		Digest:     ssbd.digest,
+		PrevDigest: ssbd.prevDigest,
+		PlanDigest: ssbd.planDigest,
+		BeginTime:  ssElement.beginTime,
+		EndTime:    ssElement.endTime,
+		AuthUsers:  make([]string, 0, len(ssElement.authUsers)),
+		PlanHint:   ssElement.planHint,
+		Charset:    ssElement.charset,
+		Collation:  ssElement.collation,
+		Columns:    make(map[string]string, len(columnValueFactoryMap)),
+	}
+	for user := range ssElement.authUsers {
+		record.AuthUsers = append(record.AuthUsers, user)
+	}
+	sort.Strings(record.AuthUsers)
+	for name, factory := range columnValueFactoryMap {
+		d := types.NewDatum(factory(ssElement, ssbd))
+		if d.IsNull() {
+			continue
+		}
+		s, err := d.ToString()
+		if err != nil {
+			continue
+		}
+		record.Columns[name] = s
+	}
+	return record
+}
+
+// persistentFile is a persistent statement summary file.
+type persistentFile struct {
+	path string
+	// rotateTime is the time when the file is rotated, it's zero for the file being written.
+	rotateTime time.Time
+}
+
+// getPersistentFiles returns the persistent files ordered by the time they are written.
+func (p *stmtSummaryPersistence) getPersistentFiles() ([]persistentFile, error) {
+	dir := filepath.Dir(p.filename)
+	base := filepath.Base(p.filename)
+	ext := filepath.Ext(base)
+	prefix := base[:len(base)-len(ext)] + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var files []persistentFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		rotateTime, err := time.ParseInLocation(persistBackupTimeFormat, name[len(prefix):len(name)-len(ext)], time.Local)
+		if err != nil {
+			continue
+		}
+		files = append(files, persistentFile{path: filepath.Join(dir, name), rotateTime: rotateTime})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].rotateTime.Before(files[j].rotateTime)
+	})
+	if _, err := os.Stat(p.filename); err == nil {
+		files = append(files, persistentFile{path: p.filename})
+	}
+	return files, nil
+}
+
+// PersistenceEnabled returns whether the expired statement summaries are persisted to the file. If so, the
+// history should be read by GetPersistedStmtSummaryHistoryRows.
+func (ssr *stmtSummaryReader) PersistenceEnabled() bool {
+	return ssr.ssMap.persistenceEnabled()
+}
+
+// GetPersistedStmtSummaryHistoryRows gets the history statement summaries rows from both the memory and
+// the persistent files.
+func (ssr *stmtSummaryReader) GetPersistedStmtSummaryHistoryRows(ctx context.Context) ([][]types.Datum, error) {
+	ssMap := ssr.ssMap
+	p := ssMap.getPersistence()
+	if p == nil {
+		return ssr.GetStmtSummaryHistoryRows(), nil
+	}
+	p.flushPending()
+
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	other := ssMap.other
+	ssMap.Unlock()
+
+	// The summaries which aren't persisted are read from the memory. They may be persisted while
+	// reading the files, so the records of them in the files are skipped.
+	historySize := ssMap.historySize()
+	rows := make([][]types.Datum, 0, len(values))
+	inMemory := make(map[stmtSummaryRecordKey]struct{}, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		if ssr.checker != nil && !ssr.checker.isValid(ssbd.schemaName, ssbd.digest) {
+			continue
+		}
+		for _, ssElement := range ssbd.collectHistorySummaries(historySize) {
+			ssElement.Lock()
+			persisted := ssElement.persisted
+			ssElement.Unlock()
+			if persisted {
+				continue
+			}
+			inMemory[stmtSummaryRecordKey{
+				schemaName: ssbd.schemaName,
+				digest:     ssbd.digest,
+				prevDigest: ssbd.prevDigest,
+				planDigest: ssbd.planDigest,
+				beginTime:  ssElement.beginTime,
+			}] = struct{}{}
+			if ssr.isElementVisible(ssElement) {
+				rows = append(rows, ssr.getStmtByDigestElementRow(ssElement, ssbd))
+			}
+		}
+	}
+	if ssr.checker == nil {
+		rows = append(rows, ssr.getStmtEvictedOtherHistoryRow(other, historySize)...)
+	}
+
+	files, err := p.getPersistentFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		// The records in a rotated file begin before the file is rotated.
+		if !file.rotateTime.IsZero() && !ssr.beginTimeLower.IsZero() && file.rotateTime.Before(ssr.beginTimeLower) {
+			continue
+		}
+		if rows, err = ssr.readPersistentFile(ctx, file.path, inMemory, rows); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func (ssr *stmtSummaryReader) readPersistentFile(ctx context.Context, path string, inMemory map[stmtSummaryRecordKey]struct{}, rows [][]types.Datum) ([][]types.Datum, error) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	err := scanPersistentFile(ctx, path, func(record *stmtSummaryRecord) error {
+		if !ssr.isRecordVisible(record) {
+			return nil
+		}
+		if _, ok := inMemory[stmtSummaryRecordKey{
+			schemaName: record.SchemaName,
+			digest:     record.Digest,
+			prevDigest: record.PrevDigest,
+			planDigest: record.PlanDigest,
+			beginTime:  record.BeginTime,
+		}]; ok {
+			return nil
+		}
+		row, err := ssr.getStmtSummaryRecordRow(sc, record)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// scanPersistentFile calls fn on each record in the persistent file in the order they are written.
+func scanPersistentFile(ctx context.Context, path string, fn func(record *stmtSummaryRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The file is removed by rotating.
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			logutil.BgLogger().Warn("close persistent statement summary file failed", zap.String("file", path), zap.Error(err))
+		}
+	}()
+
+	reader := bufio.NewReader(file)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// The last line may be being written.
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		var record stmtSummaryRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			logutil.BgLogger().Warn("decode persistent statement summary failed", zap.String("file", path), zap.Error(err))
+			continue
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+}
+
+// getBindableStmtByPlanDigest gets the latest users' bindable statement whose plan digest is the specified one
+// from the persistent files.
+func (p *stmtSummaryPersistence) getBindableStmtByPlanDigest(ctx context.Context, planDigest string) (*BindableStmt, error) {
+	p.flushPending()
+	files, err := p.getPersistentFiles()
+	if err != nil {
+		return nil, err
+	}
+	// The newer files are read first, and the later records in a file are newer.
+	for i := len(files) - 1; i >= 0; i-- {
+		var latest *BindableStmt
+		err := scanPersistentFile(ctx, files[i].path, func(record *stmtSummaryRecord) error {
+			if record.PlanDigest != planDigest {
+				return nil
+			}
+			if stmt := record.toBindableStmt(); stmt != nil {
+				latest = stmt
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil {
+			return latest, nil
+		}
+	}
+	return nil, nil
+}
+
+// toBindableStmt returns the bindable statement of the record, or nil if it can't be bound.
+func (record *stmtSummaryRecord) toBindableStmt() *BindableStmt {
+	// Empty auth users means that it is an internal queries.
+	if len(record.AuthUsers) == 0 || len(record.PlanHint) == 0 || !isBindableStmtType(record.Columns[StmtTypeStr]) {
+		return nil
+	}
+	stmt := &BindableStmt{
+		Schema:    record.SchemaName,
+		Query:     record.Columns[QuerySampleTextStr],
+		PlanHint:  record.PlanHint,
+		Charset:   record.Charset,
+		Collation: record.Collation,
+	}
+	// The sample SQL of SQL command prepare / execute is `execute ...`, so the normalized SQL is used like the
+	// summaries in the memory.
+	if record.Columns[PreparedStr] == "1" {
+		stmt.Query = record.Columns[DigestTextStr]
+	}
+	return stmt
+}
+
+func (ssr *stmtSummaryReader) isRecordVisible(record *stmtSummaryRecord) bool {
+	if ssr.checker != nil && !ssr.checker.isValid(record.SchemaName, record.Digest) {
+		return false
+	}
+	if !ssr.isTimeValid(record.BeginTime, record.EndTime) {
+		return false
+	}
+	if ssr.user != nil && !ssr.hasProcessPriv {
+		for _, user := range record.AuthUsers {
+			if user == ssr.user.Username {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (ssr *stmtSummaryReader) getStmtSummaryRecordRow(sc *stmtctx.StatementContext, record *stmtSummaryRecord) ([]types.Datum, error) {
+	row := make([]types.Datum, len(ssr.columns))
+	for i, col := range ssr.columns {
+		if col.Name.O == util.ClusterTableInstanceColumnName {
+			row[i] = types.NewStringDatum(ssr.instanceAddr)
+			continue
+		}
+		value, ok := record.Columns[col.Name.O]
+		if !ok {
+			continue
+		}
+		if types.IsString(col.Tp) {
+			row[i] = types.NewStringDatum(value)
+			continue
+		}
+		d := types.NewStringDatum(value)
+		converted, err := d.ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		row[i] = converted
+	}
+	return row, nil
+}
@@ -0,0 +1,319 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/set"
+	"github.com/stretchr/testify/require"
+)
+
+func newPersistentReaderForTest(ssMap *stmtSummaryByDigestMap) *stmtSummaryReader {
+	columns := []struct {
+		name string
+		tp   byte
+	}{
+		{SummaryBeginTimeStr, mysql.TypeTimestamp},
+		{SummaryEndTimeStr, mysql.TypeTimestamp},
+		{SchemaNameStr, mysql.TypeVarchar},
+		{DigestStr, mysql.TypeVarchar},
+		{IndexNamesStr, mysql.TypeVarchar},
+		{SampleUserStr, mysql.TypeVarchar},
+		{ExecCountStr, mysql.TypeLonglong},
+		{AvgLatencyStr, mysql.TypeLonglong},
+		{AvgTotalKeysStr, mysql.TypeDouble},
+		{FirstSeenStr, mysql.TypeTimestamp},
+		{PlanInCacheStr, mysql.TypeTiny},
+		{QuerySampleTextStr, mysql.TypeBlob},
+		{PrevSampleTextStr, mysql.TypeBlob},
+	}
+	cols := make([]*model.ColumnInfo, len(columns))
+	for i, col := range columns {
+		cols[i] = &model.ColumnInfo{
+			ID:        int64(i),
+			Name:      model.NewCIStr(col.name),
+			Offset:    i,
+			FieldType: *types.NewFieldType(col.tp),
+		}
+	}
+	reader := NewStmtSummaryReader(nil, true, cols, "")
+	reader.ssMap = ssMap
+	return reader
+}
+
+func countLines(t *testing.T, filename string) int {
+	file, err := os.Open(filename)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, file.Close())
+	}()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func requireRowsEqual(t *testing.T, expected, actual [][]types.Datum) {
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		require.Equal(t, len(expected[i]), len(actual[i]))
+		for j := range expected[i] {
+			require.Equal(t, expected[i][j].IsNull(), actual[i][j].IsNull())
+			if expected[i][j].IsNull() {
+				continue
+			}
+			expectedStr, err := expected[i][j].ToString()
+			require.NoError(t, err)
+			actualStr, err := actual[i][j].ToString()
+			require.NoError(t, err)
+			require.Equal(t, expectedStr, actualStr)
+		}
+	}
+}
+
+func TestPersistStmtSummary(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	require.NoError(t, ssMap.SetRefreshInterval("10", false))
+	require.NoError(t, ssMap.SetHistorySize("2", false))
+	defer func() {
+		require.NoError(t, ssMap.SetRefreshInterval("1800", false))
+		require.NoError(t, ssMap.SetHistorySize("24", false))
+	}()
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{EnablePersistent: true, Filename: filename, FileMaxSize: 64}
+	require.NoError(t, ssMap.setupPersistence(cfg))
+	reader := newPersistentReaderForTest(ssMap)
+	require.True(t, reader.PersistenceEnabled())
+
+	now := time.Now().Unix()
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.SchemaName = "schema_name2"
+	stmtExecInfo2.Digest = "digest2"
+	for i := 0; i < 5; i++ {
+		ssMap.beginTimeForCurInterval = now + int64(i+1)*10
+		ssMap.AddStatement(stmtExecInfo1)
+		if i == 0 {
+			ssMap.AddStatement(stmtExecInfo2)
+		}
+	}
+	// The expired summaries of the first statement are queued when the statement runs again, then they are
+	// written by the background goroutine.
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filename)
+		return err == nil && countLines(t, filename) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	// Only the summaries in the memory are read without persistence.
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 3)
+	rows, err := reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+
+	// The summaries which don't expire by running the statements again are persisted in the background.
+	reader.SetChecker(NewStmtSummaryChecker(nil, set.NewStringSet(stmtExecInfo2.SchemaName)))
+	expectedRows := reader.GetStmtSummaryHistoryRows()
+	require.Len(t, expectedRows, 1)
+	ssMap.persistExpiredSummaries(now+100, false)
+	require.Equal(t, 6, countLines(t, filename))
+	ssMap.Clear()
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 0)
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	requireRowsEqual(t, expectedRows, rows)
+
+	reader.SetChecker(NewStmtSummaryChecker(set.NewStringSet(stmtExecInfo1.Digest), nil))
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	reader.SetTimeRange(time.Unix(now+30, 0), time.Time{}, time.Time{}, time.Unix(now+50, 0))
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	reader.SetChecker(nil)
+	reader.SetTimeRange(time.Time{}, time.Time{}, time.Time{}, time.Time{})
+
+	// The history is kept after restarting.
+	ssMap.closePersistence()
+	require.False(t, reader.PersistenceEnabled())
+	ssMap = newStmtSummaryByDigestMap()
+	require.NoError(t, ssMap.setupPersistence(cfg))
+	defer ssMap.closePersistence()
+	reader = newPersistentReaderForTest(ssMap)
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+
+	reader.user = &auth.UserIdentity{Username: "bad_user"}
+	reader.hasProcessPriv = false
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 0)
+	reader.user = &auth.UserIdentity{Username: stmtExecInfo1.User}
+	rows, err = reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+}
+
+func TestPersistEvictedStmtSummary(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	require.NoError(t, ssMap.SetRefreshInterval("10", false))
+	require.NoError(t, ssMap.SetMaxStmtCount("1", false))
+	defer func() {
+		require.NoError(t, ssMap.SetRefreshInterval("1800", false))
+		require.NoError(t, ssMap.SetMaxStmtCount("", false))
+	}()
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{EnablePersistent: true, Filename: filename, FileMaxSize: 64}
+	require.NoError(t, ssMap.setupPersistence(cfg))
+	defer ssMap.closePersistence()
+	reader := newPersistentReaderForTest(ssMap)
+	reader.SetChecker(NewStmtSummaryChecker(nil, set.NewStringSet("schema_name1", "schema_name2")))
+
+	now := time.Now().Unix()
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.SchemaName = "schema_name1"
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.SchemaName = "schema_name2"
+	stmtExecInfo2.Digest = "digest2"
+	ssMap.beginTimeForCurInterval = now + 10
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.beginTimeForCurInterval = now + 20
+	// The first statement is evicted, and its expired summary is queued.
+	ssMap.AddStatement(stmtExecInfo2)
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 1)
+	// The queued summaries are found by the readers of the files.
+	rows, err := reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, 1, countLines(t, filename))
+}
+
+func TestGetPersistedBindableStmtByPlanDigest(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	require.NoError(t, ssMap.SetRefreshInterval("10", false))
+	defer func() {
+		require.NoError(t, ssMap.SetRefreshInterval("1800", false))
+	}()
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{EnablePersistent: true, Filename: filename, FileMaxSize: 64}
+	require.NoError(t, ssMap.setupPersistence(cfg))
+	defer ssMap.closePersistence()
+
+	now := time.Now().Unix()
+	ctx := context.Background()
+	stmtExecInfo := generateAnyExecInfo()
+	stmtExecInfo.OriginalSQL = "execute stmt"
+	stmtExecInfo.NormalizedSQL = "select ? from t"
+	stmtExecInfo.Prepared = true
+	stmtExecInfo.StmtCtx.StmtType = "Select"
+	for i, hint := range []string{"use_index(@`sel_1` `t` )", "ignore_index(@`sel_1` `t` )"} {
+		hint := hint
+		stmtExecInfo.PlanGenerator = func() (string, string) {
+			return "", hint
+		}
+		ssMap.beginTimeForCurInterval = now + int64(i+1)*10
+		ssMap.AddStatement(stmtExecInfo)
+	}
+	ssMap.persistExpiredSummaries(now+100, false)
+	require.Equal(t, 2, countLines(t, filename))
+	ssMap.Clear()
+
+	// The latest persisted summary is used.
+	stmt, err := ssMap.GetBindableStmtByPlanDigest(ctx, stmtExecInfo.PlanDigest)
+	require.NoError(t, err)
+	require.NotNil(t, stmt)
+	require.Equal(t, "select ? from t", stmt.Query)
+	require.Equal(t, "ignore_index(@`sel_1` `t` )", stmt.PlanHint)
+	require.Equal(t, stmtExecInfo.SchemaName, stmt.Schema)
+	stmt, err = ssMap.GetBindableStmtByPlanDigest(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, stmt)
+}
+
+func TestPersistentFiles(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "tidb-statements.log")
+	for _, name := range []string{
+		"tidb-statements-2022-01-02T00-00-00.000.log",
+		"tidb-statements-2022-01-01T00-00-00.000.log",
+		"tidb-statements.log",
+		"tidb-statements-bad-time.log",
+		"tidb-slow.log",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	p := &stmtSummaryPersistence{filename: filename}
+	files, err := p.getPersistentFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, filepath.Join(dir, "tidb-statements-2022-01-01T00-00-00.000.log"), files[0].path)
+	require.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.Local), files[0].rotateTime)
+	require.Equal(t, filepath.Join(dir, "tidb-statements-2022-01-02T00-00-00.000.log"), files[1].path)
+	require.Equal(t, filename, files[2].path)
+	require.True(t, files[2].rotateTime.IsZero())
+}
+
+func TestPersistStmtSummaryOfPreviousInterval(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	require.NoError(t, ssMap.SetRefreshInterval("10", false))
+	defer func() {
+		require.NoError(t, ssMap.SetRefreshInterval("1800", false))
+	}()
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{EnablePersistent: true, Filename: filename, FileMaxSize: 64}
+	require.NoError(t, ssMap.setupPersistence(cfg))
+	defer ssMap.closePersistence()
+	reader := newPersistentReaderForTest(ssMap)
+
+	now := time.Now().Unix() / 10 * 10
+	stmtExecInfo := generateAnyExecInfo()
+	ssMap.beginTimeForCurInterval = now
+	ssMap.AddStatement(stmtExecInfo)
+	// The interval has just ended, the summary is still kept in the memory.
+	ssMap.persistExpiredSummaries(now+15, false)
+	ssbd := ssMap.summaryMap.Values()[0].(*stmtSummaryByDigest)
+	ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
+	require.False(t, ssElement.persisted)
+	// The statement which reads the begin time before the interval changes is added to the previous interval.
+	ssbd.add(stmtExecInfo, now, 10, ssMap.historySize())
+	require.Equal(t, int64(2), ssElement.execCount)
+	require.Len(t, reader.GetStmtSummaryHistoryRows(), 1)
+
+	ssMap.persistExpiredSummaries(now+25, false)
+	require.True(t, ssElement.persisted)
+	require.Equal(t, 1, countLines(t, filename))
+	rows, err := reader.GetPersistedStmtSummaryHistoryRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+}
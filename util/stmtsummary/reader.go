@@ -40,6 +40,11 @@ type stmtSummaryReader struct {
 	ssMap                *stmtSummaryByDigestMap
 	columnValueFactories []columnValueFactory
 	checker              *stmtSummaryChecker
+	// The bounds of the begin time and the end time of the history summaries, zero means unbounded.
+	beginTimeLower time.Time
+	beginTimeUpper time.Time
+	endTimeLower   time.Time
+	endTimeUpper   time.Time
 }
 
 // NewStmtSummaryReader return a new statement summaries reader.
@@ -81,7 +86,7 @@ func (ssr *stmtSummaryReader) GetStmtSummaryCurrentRows() [][]types.Datum {
 	rows := make([][]types.Datum, 0, len(values))
 	for _, value := range values {
 		ssbd := value.(*stmtSummaryByDigest)
-		if ssr.checker != nil && !ssr.checker.isDigestValid(ssbd.digest) {
+		if ssr.checker != nil && !ssr.checker.isValid(ssbd.schemaName, ssbd.digest) {
 			continue
 		}
 		record := ssr.getStmtByDigestRow(ssbd, beginTime)
@@ -109,7 +114,7 @@ func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 	rows := make([][]types.Datum, 0, len(values)*historySize)
 	for _, value := range values {
 		ssbd := value.(*stmtSummaryByDigest)
-		if ssr.checker != nil && !ssr.checker.isDigestValid(ssbd.digest) {
+		if ssr.checker != nil && !ssr.checker.isValid(ssbd.schemaName, ssbd.digest) {
 			continue
 		}
 		records := ssr.getStmtByDigestHistoryRow(ssbd, historySize)
@@ -127,6 +132,41 @@ func (ssr *stmtSummaryReader) SetChecker(checker *stmtSummaryChecker) {
 	ssr.checker = checker
 }
 
+// SetTimeRange sets the bounds of the begin time and the end time of the history summaries to read,
+// the zero times mean unbounded.
+func (ssr *stmtSummaryReader) SetTimeRange(beginTimeLower, beginTimeUpper, endTimeLower, endTimeUpper time.Time) {
+	ssr.beginTimeLower = beginTimeLower
+	ssr.beginTimeUpper = beginTimeUpper
+	ssr.endTimeLower = endTimeLower
+	ssr.endTimeUpper = endTimeUpper
+}
+
+// isTimeValid checks whether a summary between [beginTime, endTime) is in the time range.
+func (ssr *stmtSummaryReader) isTimeValid(beginTime, endTime int64) bool {
+	begin, end := time.Unix(beginTime, 0), time.Unix(endTime, 0)
+	if (!ssr.beginTimeLower.IsZero() && begin.Before(ssr.beginTimeLower)) ||
+		(!ssr.beginTimeUpper.IsZero() && begin.After(ssr.beginTimeUpper)) {
+		return false
+	}
+	if (!ssr.endTimeLower.IsZero() && end.Before(ssr.endTimeLower)) ||
+		(!ssr.endTimeUpper.IsZero() && end.After(ssr.endTimeUpper)) {
+		return false
+	}
+	return true
+}
+
+// isElementVisible checks whether the history summary element can be read by the user and is in the time range.
+func (ssr *stmtSummaryReader) isElementVisible(ssElement *stmtSummaryByDigestElement) bool {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+	if ssr.user != nil && !ssr.hasProcessPriv {
+		if _, ok := ssElement.authUsers[ssr.user.Username]; !ok {
+			return false
+		}
+	}
+	return ssr.isTimeValid(ssElement.beginTime, ssElement.endTime)
+}
+
 func (ssr *stmtSummaryReader) getStmtByDigestRow(ssbd *stmtSummaryByDigest, beginTimeForCurInterval int64) []types.Datum {
 	var ssElement *stmtSummaryByDigestElement
 
@@ -164,11 +204,7 @@ func (ssr *stmtSummaryReader) getStmtByDigestHistoryRow(ssbd *stmtSummaryByDiges
 
 	rows := make([][]types.Datum, 0, len(ssElements))
 	for _, ssElement := range ssElements {
-		isAuthed := true
-		if ssr.user != nil && !ssr.hasProcessPriv {
-			_, isAuthed = ssElement.authUsers[ssr.user.Username]
-		}
-		if isAuthed {
+		if ssr.isElementVisible(ssElement) {
 			rows = append(rows, ssr.getStmtByDigestElementRow(ssElement, ssbd))
 		}
 	}
@@ -206,18 +242,23 @@ func (ssr *stmtSummaryReader) getStmtEvictedOtherHistoryRow(ssbde *stmtSummaryBy
 }
 
 type stmtSummaryChecker struct {
-	digests set.StringSet
+	digests     set.StringSet
+	schemaNames set.StringSet
 }
 
-// NewStmtSummaryChecker return a new statement summaries checker.
-func NewStmtSummaryChecker(digests set.StringSet) *stmtSummaryChecker {
+// NewStmtSummaryChecker return a new statement summaries checker. An empty set means no restriction on the field.
+func NewStmtSummaryChecker(digests, schemaNames set.StringSet) *stmtSummaryChecker {
 	return &stmtSummaryChecker{
-		digests: digests,
+		digests:     digests,
+		schemaNames: schemaNames,
 	}
 }
 
-func (ssc *stmtSummaryChecker) isDigestValid(digest string) bool {
-	return ssc.digests.Exist(digest)
+func (ssc *stmtSummaryChecker) isValid(schemaName, digest string) bool {
+	if len(ssc.digests) > 0 && !ssc.digests.Exist(digest) {
+		return false
+	}
+	return len(ssc.schemaNames) == 0 || ssc.schemaNames.Exist(schemaName)
 }
 
 // Statements summary table column name.
@@ -17,6 +17,7 @@ package stmtsummary
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -75,6 +76,9 @@ type stmtSummaryByDigestMap struct {
 
 	// other stores summary of evicted data.
 	other *stmtSummaryByDigestEvicted
+
+	// persistence stores the *stmtSummaryPersistence which writes the expired summaries to the file.
+	persistence atomic.Value
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -92,6 +96,7 @@ type stmtSummaryByDigest struct {
 	// They won't change once this object is created, so locking is not needed.
 	schemaName    string
 	digest        string
+	prevDigest    string
 	planDigest    string
 	stmtType      string
 	normalizedSQL string
@@ -201,6 +206,8 @@ type stmtSummaryByDigestElement struct {
 	// pessimistic execution retry information.
 	execRetryCount uint
 	execRetryTime  time.Duration
+	// persisted means the element has been written to the persistent file.
+	persisted bool
 }
 
 // StmtExecInfo records execution information of each statement.
@@ -252,7 +259,9 @@ func newStmtSummaryByDigestMap() *stmtSummaryByDigestMap {
 	}
 	newSsMap.summaryMap.SetOnEvict(func(k kvcache.Key, v kvcache.Value) {
 		historySize := newSsMap.historySize()
-		newSsMap.other.AddEvicted(k.(*stmtSummaryByDigestKey), v.(*stmtSummaryByDigest), historySize)
+		ssbd := v.(*stmtSummaryByDigest)
+		newSsMap.other.AddEvicted(k.(*stmtSummaryByDigestKey), ssbd, historySize)
+		newSsMap.queueExpiredSummaries(ssbd, ssbd.collectExpiredSummaries(newSsMap.beginTimeForCurInterval, newSsMap.refreshInterval()))
 	})
 	return newSsMap
 }
@@ -320,7 +329,9 @@ func (ssMap *stmtSummaryByDigestMap) AddStatement(sei *StmtExecInfo) {
 	}()
 	// Lock a single entry, not the whole cache.
 	if summary != nil {
-		summary.add(sei, beginTime, intervalSeconds, historySize)
+		if expired := summary.add(sei, beginTime, intervalSeconds, historySize); expired != nil {
+			ssMap.queueExpiredSummaries(summary, []*stmtSummaryByDigestElement{expired})
+		}
 	}
 }
 
@@ -369,38 +380,112 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 	stmts := make([]*BindableStmt, 0, len(values))
 	for _, value := range values {
 		ssbd := value.(*stmtSummaryByDigest)
-		func() {
-			ssbd.Lock()
-			defer ssbd.Unlock()
-			if ssbd.initialized && (ssbd.stmtType == "Select" || ssbd.stmtType == "Delete" || ssbd.stmtType == "Update" || ssbd.stmtType == "Insert" || ssbd.stmtType == "Replace") {
-				if ssbd.history.Len() > 0 {
-					ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
-					ssElement.Lock()
-
-					// Empty auth users means that it is an internal queries.
-					if len(ssElement.authUsers) > 0 && (int64(ssbd.history.Len()) > cnt || ssElement.execCount > cnt) {
-						stmt := &BindableStmt{
-							Schema:    ssbd.schemaName,
-							Query:     ssElement.sampleSQL,
-							PlanHint:  ssElement.planHint,
-							Charset:   ssElement.charset,
-							Collation: ssElement.collation,
-						}
-						// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
-						// If it is binary protocol prepare / execute, ssbd.normalizedSQL should be same as ssElement.sampleSQL.
-						if ssElement.prepared {
-							stmt.Query = ssbd.normalizedSQL
-						}
-						stmts = append(stmts, stmt)
-					}
-					ssElement.Unlock()
-				}
-			}
-		}()
+		if stmt := ssbd.getBindableStmt(func(ssElement *stmtSummaryByDigestElement) bool {
+			return int64(ssbd.history.Len()) > cnt || ssElement.execCount > cnt
+		}); stmt != nil {
+			stmts = append(stmts, stmt)
+		}
 	}
 	return stmts
 }
 
+// GetBindableStmtByPlanDigest gets the users' bindable statement whose plan digest is the specified one.
+// The summaries of all the intervals in the memory are searched first, and then the persistent files.
+// The latest one is returned if the plan is used by several statements.
+func (ssMap *stmtSummaryByDigestMap) GetBindableStmtByPlanDigest(ctx context.Context, planDigest string) (*BindableStmt, error) {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	var (
+		latest         *BindableStmt
+		latestSeenTime time.Time
+	)
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		if ssbd.planDigest != planDigest {
+			continue
+		}
+		stmt, lastSeen := ssbd.getLatestBindableStmtWithHint()
+		if stmt != nil && (latest == nil || lastSeen.After(latestSeenTime)) {
+			latest, latestSeenTime = stmt, lastSeen
+		}
+	}
+	if latest != nil {
+		return latest, nil
+	}
+	// The summaries in the files are expired, so they are older than the ones in the memory.
+	if p := ssMap.getPersistence(); p != nil {
+		return p.getBindableStmtByPlanDigest(ctx, planDigest)
+	}
+	return nil, nil
+}
+
+// getBindableStmt gets the bindable statement from the latest element of the summary if the element satisfies the filter.
+func (ssbd *stmtSummaryByDigest) getBindableStmt(filter func(ssElement *stmtSummaryByDigestElement) bool) *BindableStmt {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+	if !ssbd.initialized || !isBindableStmtType(ssbd.stmtType) {
+		return nil
+	}
+	if ssbd.history.Len() == 0 {
+		return nil
+	}
+	ssElement := ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
+	ssElement.Lock()
+	defer ssElement.Unlock()
+
+	// Empty auth users means that it is an internal queries.
+	if len(ssElement.authUsers) == 0 || !filter(ssElement) {
+		return nil
+	}
+	return newBindableStmt(ssElement, ssbd)
+}
+
+// getLatestBindableStmtWithHint gets the bindable statement from the latest element of the summary which has
+// plan hints in any interval, and returns the last time it's seen.
+func (ssbd *stmtSummaryByDigest) getLatestBindableStmtWithHint() (*BindableStmt, time.Time) {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+	if !ssbd.initialized || !isBindableStmtType(ssbd.stmtType) {
+		return nil, time.Time{}
+	}
+	for listElement := ssbd.history.Back(); listElement != nil; listElement = listElement.Prev() {
+		ssElement := listElement.Value.(*stmtSummaryByDigestElement)
+		ssElement.Lock()
+		var stmt *BindableStmt
+		if len(ssElement.authUsers) > 0 && len(ssElement.planHint) > 0 {
+			stmt = newBindableStmt(ssElement, ssbd)
+		}
+		lastSeen := ssElement.lastSeen
+		ssElement.Unlock()
+		if stmt != nil {
+			return stmt, lastSeen
+		}
+	}
+	return nil, time.Time{}
+}
+
+func newBindableStmt(ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) *BindableStmt {
+	stmt := &BindableStmt{
+		Schema:    ssbd.schemaName,
+		Query:     ssElement.sampleSQL,
+		PlanHint:  ssElement.planHint,
+		Charset:   ssElement.charset,
+		Collation: ssElement.collation,
+	}
+	// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
+	// If it is binary protocol prepare / execute, ssbd.normalizedSQL should be same as ssElement.sampleSQL.
+	if ssElement.prepared {
+		stmt.Query = ssbd.normalizedSQL
+	}
+	return stmt
+}
+
+func isBindableStmtType(stmtType string) bool {
+	return stmtType == "Select" || stmtType == "Delete" || stmtType == "Update" || stmtType == "Insert" || stmtType == "Replace"
+}
+
 // SetEnabled enables or disables statement summary in global(cluster) or session(server) scope.
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value string, inSession bool) error {
 	if err := ssMap.sysVars.setVariable(typeEnable, value, inSession); err != nil {
@@ -507,6 +592,7 @@ func (ssbd *stmtSummaryByDigest) init(sei *StmtExecInfo, beginTime int64, interv
 	}
 	ssbd.schemaName = sei.SchemaName
 	ssbd.digest = sei.Digest
+	ssbd.prevDigest = sei.PrevSQLDigest
 	ssbd.planDigest = planDigest
 	ssbd.stmtType = sei.StmtCtx.StmtType
 	ssbd.normalizedSQL = formatSQL(sei.NormalizedSQL)
@@ -515,7 +601,8 @@ func (ssbd *stmtSummaryByDigest) init(sei *StmtExecInfo, beginTime int64, interv
 	ssbd.initialized = true
 }
 
-func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, intervalSeconds int64, historySize int) {
+// add adds a statement to the summary, and returns the element which expires to the history if any.
+func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, intervalSeconds int64, historySize int) (expired *stmtSummaryByDigestElement) {
 	// Enclose this block in a function to ensure the lock will always be released.
 	ssElement, isElementNew := func() (*stmtSummaryByDigestElement, bool) {
 		ssbd.Lock()
@@ -535,6 +622,7 @@ func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, interva
 			} else {
 				// The last elements expires to the history.
 				lastElement.onExpire(intervalSeconds)
+				expired = lastElement
 			}
 		}
 		if isElementNew {
@@ -556,6 +644,7 @@ func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, interva
 	if !isElementNew {
 		ssElement.add(sei, intervalSeconds)
 	}
+	return expired
 }
 
 // collectHistorySummaries puts at most `historySize` summaries to an array.
@@ -16,6 +16,7 @@ package stmtsummary
 
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -1149,6 +1150,50 @@ func TestGetMoreThanCntBindableStmt(t *testing.T) {
 	require.Equal(t, 1, len(stmts))
 }
 
+func TestGetBindableStmtByPlanDigest(t *testing.T) {
+	ssMap := newStmtSummaryByDigestMap()
+	ctx := context.Background()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.OriginalSQL = "select 1"
+	stmtExecInfo1.NormalizedSQL = "select ?"
+	stmtExecInfo1.StmtCtx.StmtType = "Select"
+	ssMap.AddStatement(stmtExecInfo1)
+	// The plan without hints can't be bound.
+	stmt, err := ssMap.GetBindableStmtByPlanDigest(ctx, "plan_digest")
+	require.NoError(t, err)
+	require.Nil(t, stmt)
+
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.OriginalSQL = "select 2"
+	stmtExecInfo2.NormalizedSQL = "select ? from t"
+	stmtExecInfo2.Digest = "digest2"
+	stmtExecInfo2.StmtCtx.StmtType = "Select"
+	stmtExecInfo2.PlanGenerator = func() (string, string) {
+		return "", "use_index(@`sel_1` `t` )"
+	}
+	ssMap.AddStatement(stmtExecInfo2)
+	stmt, err = ssMap.GetBindableStmtByPlanDigest(ctx, "plan_digest")
+	require.NoError(t, err)
+	require.NotNil(t, stmt)
+	require.Equal(t, "select 2", stmt.Query)
+	require.Equal(t, "use_index(@`sel_1` `t` )", stmt.PlanHint)
+	require.Equal(t, "schema_name", stmt.Schema)
+	stmt, err = ssMap.GetBindableStmtByPlanDigest(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, stmt)
+
+	// The summaries in the earlier intervals are searched too.
+	ssMap.beginTimeForCurInterval += 1800
+	stmtExecInfo2.PlanGenerator = emptyPlanGenerator
+	ssMap.AddStatement(stmtExecInfo2)
+	stmt, err = ssMap.GetBindableStmtByPlanDigest(ctx, "plan_digest")
+	require.NoError(t, err)
+	require.NotNil(t, stmt)
+	require.Equal(t, "select 2", stmt.Query)
+	require.Equal(t, "use_index(@`sel_1` `t` )", stmt.PlanHint)
+}
+
 // Test `formatBackoffTypes`.
 func TestFormatBackoffTypes(t *testing.T) {
 	backoffMap := make(map[string]int)